@@ -59,6 +59,13 @@ type ConnectionArgs struct {
 	// OpenID Connect tokens
 	OIDCTokens *oidc.Tokens[*oidc.IDTokenClaims]
 
+	// Username and password used with the external authentication method
+	ExternalAuthUsername string
+	ExternalAuthPassword string
+
+	// Secret of the instance access link used with the access link authentication method
+	AccessLinkSecret string
+
 	// Skip automatic GetServer request upon connection
 	SkipGetServer bool
 
@@ -377,10 +384,19 @@ func httpsIncus(ctx context.Context, requestURL string, args *ConnectionArgs) (I
 		eventListeners:     make(map[string][]*EventListener),
 	}
 
-	if slices.Contains([]string{api.AuthenticationMethodOIDC}, args.AuthType) {
+	if slices.Contains([]string{api.AuthenticationMethodOIDC, api.AuthenticationMethodExternal, api.AuthenticationMethodAccessLink}, args.AuthType) {
 		server.RequireAuthenticated(true)
 	}
 
+	if args.AuthType == api.AuthenticationMethodExternal {
+		server.externalAuthUsername = args.ExternalAuthUsername
+		server.externalAuthPassword = args.ExternalAuthPassword
+	}
+
+	if args.AuthType == api.AuthenticationMethodAccessLink {
+		server.accessLinkSecret = args.AccessLinkSecret
+	}
+
 	// Setup the HTTP client
 	httpClient, err := tlsHTTPClient(args.HTTPClient, args.TLSClientCert, args.TLSClientKey, args.TLSCA, args.TLSServerCert, args.InsecureSkipVerify, args.Proxy, args.TransportWrapper)
 	if err != nil {
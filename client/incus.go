@@ -51,6 +51,11 @@ type ProtocolIncus struct {
 	project       string
 
 	oidcClient *oidcClient
+
+	externalAuthUsername string
+	externalAuthPassword string
+
+	accessLinkSecret string
 }
 
 // Disconnect gets rid of any background goroutines.
@@ -184,6 +189,8 @@ func (r *ProtocolIncus) DoWebsocket(dialer websocket.Dialer, uri string, req *ht
 // User-Agent (if r.httpUserAgent is set).
 // X-Incus-authenticated (if r.requireAuthenticated is set).
 // OIDC Authorization header (if r.oidcClient is set).
+// Basic Authorization header (if r.externalAuthUsername is set).
+// Access link Authorization header (if r.accessLinkSecret is set).
 func (r *ProtocolIncus) addClientHeaders(req *http.Request) {
 	if r.httpUserAgent != "" {
 		req.Header.Set("User-Agent", r.httpUserAgent)
@@ -196,6 +203,14 @@ func (r *ProtocolIncus) addClientHeaders(req *http.Request) {
 	if r.oidcClient != nil {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.oidcClient.getAccessToken()))
 	}
+
+	if r.externalAuthUsername != "" {
+		req.SetBasicAuth(r.externalAuthUsername, r.externalAuthPassword)
+	}
+
+	if r.accessLinkSecret != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.accessLinkSecret))
+	}
 }
 
 // RequireAuthenticated sets whether we expect to be authenticated with the server.
@@ -532,9 +547,16 @@ func (r *ProtocolIncus) getSourceImageConnectionInfo(source ImageServer, image a
 
 	// Optimization for the local image case
 	if r.isSameServer(source) {
-		// Always use fingerprints for local case
-		instSrc.Fingerprint = image.Fingerprint
-		instSrc.Alias = ""
+		// Always use fingerprints for local case, unless the server is left to resolve the alias.
+		if image.Fingerprint != "" || instSrc.Alias == "" {
+			instSrc.Fingerprint = image.Fingerprint
+
+			// Keep the alias for the server to track it, the fingerprint takes precedence.
+			if !r.HasExtension("instance_rebuild_latest_image") {
+				instSrc.Alias = ""
+			}
+		}
+
 		return nil, nil
 	}
 
@@ -0,0 +1,80 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetBackupPoints returns the restorable points stored in the backup target, optionally limited to an instance.
+func (r *ProtocolIncus) GetBackupPoints(instanceName string) ([]api.BackupPoint, error) {
+	if !r.HasExtension("backup_target") {
+		return nil, errors.New("The server is missing the required \"backup_target\" API extension")
+	}
+
+	v := url.Values{}
+	v.Set("recursion", "1")
+
+	if instanceName != "" {
+		v.Set("instance", instanceName)
+	}
+
+	// Fetch the raw value
+	points := []api.BackupPoint{}
+
+	_, err := r.queryStruct("GET", fmt.Sprintf("/backup-points?%s", v.Encode()), nil, "", &points)
+	if err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// GetBackupPoint returns a restorable point stored in the backup target.
+func (r *ProtocolIncus) GetBackupPoint(id string) (*api.BackupPoint, error) {
+	if !r.HasExtension("backup_target") {
+		return nil, errors.New("The server is missing the required \"backup_target\" API extension")
+	}
+
+	// Fetch the raw value
+	point := api.BackupPoint{}
+
+	_, err := r.queryStruct("GET", fmt.Sprintf("/backup-points/%s", url.PathEscape(id)), nil, "", &point)
+	if err != nil {
+		return nil, err
+	}
+
+	return &point, nil
+}
+
+// RestoreBackupPoint creates a new instance from a restorable point stored in the backup target.
+func (r *ProtocolIncus) RestoreBackupPoint(id string, req api.BackupPointPost) (Operation, error) {
+	if !r.HasExtension("backup_target") {
+		return nil, errors.New("The server is missing the required \"backup_target\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("/backup-points/%s", url.PathEscape(id)), req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteBackupPoint deletes a restorable point from the backup target.
+func (r *ProtocolIncus) DeleteBackupPoint(id string) (Operation, error) {
+	if !r.HasExtension("backup_target") {
+		return nil, errors.New("The server is missing the required \"backup_target\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("DELETE", fmt.Sprintf("/backup-points/%s", url.PathEscape(id)), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -204,6 +204,34 @@ func (r *ProtocolIncus) UpdateClusterMemberState(name string, state api.ClusterM
 	return op, nil
 }
 
+// CreateClusterMemberSnapshots snapshots all instances of a cluster member.
+func (r *ProtocolIncus) CreateClusterMemberSnapshots(name string, snapshots api.ClusterMemberSnapshotsPost) (Operation, error) {
+	if !r.HasExtension("clustering_member_snapshots") {
+		return nil, errors.New("The server is missing the required \"clustering_member_snapshots\" API extension")
+	}
+
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("/cluster/members/%s/snapshots", name), snapshots, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteClusterMemberSnapshots deletes a snapshot from all instances of a cluster member.
+func (r *ProtocolIncus) DeleteClusterMemberSnapshots(name string, snapshotName string) (Operation, error) {
+	if !r.HasExtension("clustering_member_snapshots") {
+		return nil, errors.New("The server is missing the required \"clustering_member_snapshots\" API extension")
+	}
+
+	op, _, err := r.queryOperation("DELETE", fmt.Sprintf("/cluster/members/%s/snapshots/%s", name, snapshotName), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // GetClusterGroups returns the cluster groups.
 func (r *ProtocolIncus) GetClusterGroups() ([]api.ClusterGroup, error) {
 	if !r.HasExtension("clustering_groups") {
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	neturl "net/url"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -15,7 +18,9 @@ import (
 // Event handling functions
 
 // getEvents connects to the Incus monitoring interface.
-func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
+// When since isn't zero, a dedicated connection replaying the recorded events is used and the target is added
+// before any event is received.
+func (r *ProtocolIncus) getEvents(allProjects bool, since time.Time, target *EventTarget) (*EventListener, error) {
 	// Prevent anything else from interacting with the listeners
 	r.eventListenersLock.Lock()
 	defer r.eventListenersLock.Unlock()
@@ -38,21 +43,38 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 		listener.projectName = connInfo.Project
 	}
 
+	listener.connKey = listener.projectName
+	if !since.IsZero() {
+		listener.connKey = fmt.Sprintf("%s@%p", listener.projectName, &listener)
+		listener.targets = []*EventTarget{target}
+	}
+
 	// There is an existing Go routine for the required project filter, so just add another target.
-	if r.eventListeners[listener.projectName] != nil {
-		r.eventListeners[listener.projectName] = append(r.eventListeners[listener.projectName], &listener)
+	if r.eventListeners[listener.connKey] != nil {
+		r.eventListeners[listener.connKey] = append(r.eventListeners[listener.connKey], &listener)
 		return &listener, nil
 	}
 
 	// Setup a new connection with Incus
-	var url string
-	var err error
+	values := neturl.Values{}
 	if allProjects {
-		url, err = r.setQueryAttributes("/events?all-projects=true")
-	} else {
-		url, err = r.setQueryAttributes("/events")
+		values.Set("all-projects", "true")
+	}
+
+	if !since.IsZero() {
+		values.Set("since", since.Format(time.RFC3339))
+
+		if target.types != nil {
+			values.Set("type", strings.Join(target.types, ","))
+		}
 	}
 
+	path := "/events"
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+
+	url, err := r.setQueryAttributes(path)
 	if err != nil {
 		return nil, err
 	}
@@ -64,11 +86,11 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 	}
 
 	r.eventConnsLock.Lock()
-	r.eventConns[listener.projectName] = wsConn // Save for others to use.
+	r.eventConns[listener.connKey] = wsConn // Save for others to use.
 	r.eventConnsLock.Unlock()
 
 	// Initialize the event listener list if we were able to connect to the events websocket.
-	r.eventListeners[listener.projectName] = []*EventListener{&listener}
+	r.eventListeners[listener.connKey] = []*EventListener{&listener}
 
 	// Spawn a watcher that will close the websocket connection after all
 	// listeners are gone.
@@ -83,14 +105,14 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 
 			r.eventListenersLock.Lock()
 			r.eventConnsLock.Lock()
-			if len(r.eventListeners[listener.projectName]) == 0 {
+			if len(r.eventListeners[listener.connKey]) == 0 {
 				// We don't need the connection anymore, disconnect and clear.
-				if r.eventListeners[listener.projectName] != nil {
-					_ = r.eventConns[listener.projectName].Close()
-					delete(r.eventConns, listener.projectName)
+				if r.eventListeners[listener.connKey] != nil {
+					_ = r.eventConns[listener.connKey].Close()
+					delete(r.eventConns, listener.connKey)
 				}
 
-				r.eventListeners[listener.projectName] = nil
+				r.eventListeners[listener.connKey] = nil
 				r.eventListenersLock.Unlock()
 				r.eventConnsLock.Unlock()
 
@@ -112,14 +134,14 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 				defer r.eventListenersLock.Unlock()
 
 				// Tell all the current listeners about the failure
-				for _, listener := range r.eventListeners[listener.projectName] {
+				for _, listener := range r.eventListeners[listener.connKey] {
 					listener.err = err
 					listener.ctxCancel()
 				}
 
 				// And remove them all from the list so that when watcher routine runs it will
 				// close the websocket connection.
-				r.eventListeners[listener.projectName] = nil
+				r.eventListeners[listener.connKey] = nil
 
 				close(stopCh) // Instruct watcher go routine to cleanup.
 
@@ -138,15 +160,23 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 				continue
 			}
 
-			// Send the message to all handlers
+			// Send the message to all handlers. The handlers of the connections replaying recorded events
+			// are called in order once the locks are released.
+			var orderedFunctions []func(api.Event)
+
 			r.eventListenersLock.Lock()
-			for _, listener := range r.eventListeners[listener.projectName] {
+			for _, listener := range r.eventListeners[listener.connKey] {
 				listener.targetsLock.Lock()
 				for _, target := range listener.targets {
 					if target.types != nil && !slices.Contains(target.types, event.Type) {
 						continue
 					}
 
+					if listener.connKey != listener.projectName {
+						orderedFunctions = append(orderedFunctions, target.function)
+						continue
+					}
+
 					go target.function(event)
 				}
 
@@ -154,6 +184,10 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 			}
 
 			r.eventListenersLock.Unlock()
+
+			for _, function := range orderedFunctions {
+				function(event)
+			}
 		}
 	}()
 
@@ -162,12 +196,32 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 
 // GetEvents gets the events for the project defined on the client.
 func (r *ProtocolIncus) GetEvents() (*EventListener, error) {
-	return r.getEvents(false)
+	return r.getEvents(false, time.Time{}, nil)
 }
 
 // GetEventsAllProjects gets events for all projects.
 func (r *ProtocolIncus) GetEventsAllProjects() (*EventListener, error) {
-	return r.getEvents(true)
+	return r.getEvents(true, time.Time{}, nil)
+}
+
+// GetEventsSince gets the events for the project defined on the client (or all projects), starting with the
+// replay of the recorded events which happened since the given time.
+// The function is called for all the events of the given types, including the replayed ones.
+func (r *ProtocolIncus) GetEventsSince(since time.Time, allProjects bool, types []string, function func(api.Event)) (*EventListener, error) {
+	err := r.CheckExtension("event_history")
+	if err != nil {
+		return nil, err
+	}
+
+	if since.IsZero() {
+		return nil, errors.New("A valid since time must be provided")
+	}
+
+	if function == nil {
+		return nil, errors.New("A valid function must be provided")
+	}
+
+	return r.getEvents(allProjects, since, &EventTarget{function: function, types: types})
 }
 
 // SendEvent send an event to the server via the client's event listener connection.
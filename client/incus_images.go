@@ -2,6 +2,7 @@ package incus
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -376,6 +377,47 @@ func (r *ProtocolIncus) GetImageAliases() ([]api.ImageAliasesEntry, error) {
 	return aliases, nil
 }
 
+// GetImageAliasesByNames returns the aliases of the project with the given names, in a single request.
+// Names which don't match any alias are ignored.
+func (r *ProtocolIncus) GetImageAliasesByNames(names []string) ([]api.ImageAliasesEntry, error) {
+	if len(names) == 0 {
+		return []api.ImageAliasesEntry{}, nil
+	}
+
+	// Fall back to filtering the full list of aliases on older servers.
+	if !r.HasExtension("image_aliases_lookup") {
+		allAliases, err := r.GetImageAliases()
+		if err != nil {
+			return nil, err
+		}
+
+		aliases := []api.ImageAliasesEntry{}
+		for _, alias := range allAliases {
+			if slices.Contains(names, alias.Name) {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		return aliases, nil
+	}
+
+	v := url.Values{}
+	v.Set("recursion", "1")
+	for _, name := range names {
+		v.Add("name", name)
+	}
+
+	aliases := []api.ImageAliasesEntry{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/images/aliases?"+v.Encode(), nil, "", &aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
 // GetImageAliasNames returns the list of available alias names.
 func (r *ProtocolIncus) GetImageAliasNames() ([]string, error) {
 	// Fetch the raw URL values.
@@ -405,7 +447,22 @@ func (r *ProtocolIncus) GetImageAlias(name string) (*api.ImageAliasesEntry, stri
 
 // GetImageAliasType returns an existing alias as an ImageAliasesEntry struct.
 func (r *ProtocolIncus) GetImageAliasType(imageType string, name string) (*api.ImageAliasesEntry, string, error) {
-	alias, etag, err := r.GetImageAlias(name)
+	var alias *api.ImageAliasesEntry
+	var etag string
+	var err error
+
+	// Servers proxying upstream image servers may provide aliases of the same name for multiple types.
+	if imageType != "" && r.HasExtension("images_cache") {
+		alias = &api.ImageAliasesEntry{}
+
+		v := url.Values{}
+		v.Set("type", imageType)
+
+		etag, err = r.queryStruct("GET", fmt.Sprintf("/images/aliases/%s?%s", url.PathEscape(name), v.Encode()), nil, "", alias)
+	} else {
+		alias, etag, err = r.GetImageAlias(name)
+	}
+
 	if err != nil {
 		return nil, "", err
 	}
@@ -430,6 +487,18 @@ func (r *ProtocolIncus) GetImageAliasArchitectures(imageType string, name string
 		return nil, err
 	}
 
+	// Aliases with architecture variants list the target of each architecture.
+	if len(alias.Architectures) > 0 {
+		entries := make(map[string]*api.ImageAliasesEntry, len(alias.Architectures))
+		for architecture, fingerprint := range alias.Architectures {
+			entry := *alias
+			entry.Target = fingerprint
+			entries[architecture] = &entry
+		}
+
+		return entries, nil
+	}
+
 	img, _, err := r.GetImage(alias.Target)
 	if err != nil {
 		return nil, err
@@ -446,6 +515,12 @@ func (r *ProtocolIncus) CreateImage(image api.ImagesPost, args *ImageCreateArgs)
 		}
 	}
 
+	if image.Provenance != nil || image.SBOM != "" {
+		if !r.HasExtension("image_sbom") {
+			return nil, errors.New("The server is missing the required \"image_sbom\" API extension")
+		}
+	}
+
 	// Send the JSON based request
 	if args == nil {
 		op, _, err := r.queryOperation("POST", "/images", image, "")
@@ -1021,6 +1096,23 @@ func (r *ProtocolIncus) RefreshImage(fingerprint string) (Operation, error) {
 	return op, nil
 }
 
+// GetImageSBOM returns the SBOM document attached to an image.
+func (r *ProtocolIncus) GetImageSBOM(fingerprint string) (json.RawMessage, error) {
+	if !r.HasExtension("image_sbom") {
+		return nil, errors.New("The server is missing the required \"image_sbom\" API extension")
+	}
+
+	sbom := json.RawMessage{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/images/%s/sbom", url.PathEscape(fingerprint)), nil, "", &sbom)
+	if err != nil {
+		return nil, err
+	}
+
+	return sbom, nil
+}
+
 // CreateImageSecret requests that Incus issues a temporary image secret.
 func (r *ProtocolIncus) CreateImageSecret(fingerprint string) (Operation, error) {
 	// Send the request
@@ -1034,6 +1126,10 @@ func (r *ProtocolIncus) CreateImageSecret(fingerprint string) (Operation, error)
 
 // CreateImageAlias sets up a new image alias.
 func (r *ProtocolIncus) CreateImageAlias(alias api.ImageAliasesPost) error {
+	if len(alias.Architectures) > 0 && !r.HasExtension("image_alias_architectures") {
+		return errors.New("The server is missing the required \"image_alias_architectures\" API extension")
+	}
+
 	// Send the request
 	_, _, err := r.query("POST", "/images/aliases", alias, "")
 	if err != nil {
@@ -1045,6 +1141,10 @@ func (r *ProtocolIncus) CreateImageAlias(alias api.ImageAliasesPost) error {
 
 // UpdateImageAlias updates the image alias definition.
 func (r *ProtocolIncus) UpdateImageAlias(name string, alias api.ImageAliasesEntryPut, ETag string) error {
+	if len(alias.Architectures) > 0 && !r.HasExtension("image_alias_architectures") {
+		return errors.New("The server is missing the required \"image_alias_architectures\" API extension")
+	}
+
 	// Send the request
 	_, _, err := r.query("PUT", fmt.Sprintf("/images/aliases/%s", url.PathEscape(name)), alias, ETag)
 	if err != nil {
@@ -325,6 +325,13 @@ func (r *ProtocolIncus) RebuildInstance(instanceName string, instance api.Instan
 		return nil, err
 	}
 
+	if instance.LatestImage {
+		err = r.CheckExtension("instance_rebuild_latest_image")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return r.rebuildInstance(instanceName, instance)
 }
 
@@ -793,6 +800,26 @@ func (r *ProtocolIncus) CopyInstance(source InstanceServer, instance api.Instanc
 			}
 		}
 
+		if args.Verify {
+			if !r.HasExtension("migration_verify") {
+				return nil, errors.New("The target server is missing the required \"migration_verify\" API extension")
+			}
+
+			if !source.HasExtension("migration_verify") {
+				return nil, errors.New("The source server is missing the required \"migration_verify\" API extension")
+			}
+		}
+
+		if args.Instant {
+			if !r.HasExtension("instance_copy_instant") {
+				return nil, errors.New("The server is missing the required \"instance_copy_instant\" API extension")
+			}
+
+			if args.Verify {
+				return nil, errors.New("Instant copies can't be verified")
+			}
+		}
+
 		// Allow overriding the target name
 		if args.Name != "" {
 			req.Name = args.Name
@@ -803,6 +830,8 @@ func (r *ProtocolIncus) CopyInstance(source InstanceServer, instance api.Instanc
 		req.Source.Refresh = args.Refresh
 		req.Source.RefreshExcludeOlder = args.RefreshExcludeOlder
 		req.Source.AllowInconsistent = args.AllowInconsistent
+		req.Source.Verify = args.Verify
+		req.Source.Instant = args.Instant
 	}
 
 	if req.Source.Live {
@@ -819,8 +848,15 @@ func (r *ProtocolIncus) CopyInstance(source InstanceServer, instance api.Instanc
 		return nil, fmt.Errorf("Failed to get destination connection info: %w", err)
 	}
 
-	// Optimization for the local copy case
-	if destInfo.URL == sourceInfo.URL && destInfo.SocketPath == sourceInfo.SocketPath && (!r.IsClustered() || instance.Location == r.clusterTarget || r.HasExtension("cluster_internal_copy")) {
+	isLocalCopy := destInfo.URL == sourceInfo.URL && destInfo.SocketPath == sourceInfo.SocketPath && (!r.IsClustered() || instance.Location == r.clusterTarget || r.HasExtension("cluster_internal_copy"))
+
+	// Instant copies are clones on the storage pool of the source.
+	if req.Source.Instant && !isLocalCopy {
+		return nil, errors.New("Instant copies are only possible on the server of the source instance")
+	}
+
+	// Optimization for the local copy case, verification requiring a migration.
+	if !req.Source.Verify && isLocalCopy {
 		// Project handling
 		if destInfo.Project != sourceInfo.Project {
 			if !r.HasExtension("container_copy_project") {
@@ -860,6 +896,7 @@ func (r *ProtocolIncus) CopyInstance(source InstanceServer, instance api.Instanc
 		Live:              req.Source.Live,
 		InstanceOnly:      req.Source.InstanceOnly,
 		AllowInconsistent: req.Source.AllowInconsistent,
+		Verify:            req.Source.Verify,
 	}
 
 	// Push mode migration
@@ -945,8 +982,12 @@ func (r *ProtocolIncus) CopyInstance(source InstanceServer, instance api.Instanc
 			}
 		}
 
-		// Launch the relay
-		err = r.proxyMigration(targetOp.(*operation), targetSecrets, source, op.(*operation), sourceSecrets)
+		// Launch the relay, either on the relay server or from the client
+		if args.Relay != nil {
+			err = r.relayMigration(args.Relay, targetOp.(*operation), targetSecrets, source, op.(*operation), sourceSecrets)
+		} else {
+			err = r.proxyMigration(targetOp.(*operation), targetSecrets, source, op.(*operation), sourceSecrets)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -1123,6 +1164,10 @@ func (r *ProtocolIncus) MigrateInstance(name string, instance api.InstancePost)
 		return nil, errors.New("The server is missing the required \"cluster_migration_inconsistent_copy\" API extension")
 	}
 
+	if instance.Verify && !r.HasExtension("migration_verify") {
+		return nil, errors.New("The server is missing the required \"migration_verify\" API extension")
+	}
+
 	// Quick check.
 	if !instance.Migration {
 		return nil, errors.New("Can't ask for a rename through MigrateInstance")
@@ -1137,6 +1182,33 @@ func (r *ProtocolIncus) MigrateInstance(name string, instance api.InstancePost)
 	return op, nil
 }
 
+// CheckMigrateInstance requests that Incus checks whether the instance can be migrated, without moving it.
+func (r *ProtocolIncus) CheckMigrateInstance(name string, instance api.InstancePost) (*api.InstanceMigrationCheck, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("instance_migration_check") {
+		return nil, errors.New("The server is missing the required \"instance_migration_check\" API extension")
+	}
+
+	// Quick check.
+	if !instance.Migration {
+		return nil, errors.New("Can't check a rename through CheckMigrateInstance")
+	}
+
+	report := api.InstanceMigrationCheck{}
+
+	// Send the request
+	_, err = r.queryStruct("POST", fmt.Sprintf("%s/%s?dry-run=1", path, url.PathEscape(name)), instance, "", &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
 // DeleteInstance requests that Incus deletes the instance.
 func (r *ProtocolIncus) DeleteInstance(name string) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -1194,23 +1266,6 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 	opAPI := op.Get()
 
 	// Process additional arguments
-
-	// Parse the fds
-	fds := map[string]string{}
-
-	value, ok := opAPI.Metadata["fds"]
-	if ok {
-		values, ok := value.(map[string]any)
-		if ok {
-			for k, v := range values {
-				val, ok := v.(string)
-				if ok {
-					fds[k] = val
-				}
-			}
-		}
-	}
-
 	if exec.RecordOutput && (args.Stdout != nil || args.Stderr != nil) {
 		err = op.Wait()
 		if err != nil {
@@ -1271,10 +1326,36 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		}
 	}
 
+	err = r.execInstanceWebsockets(opAPI, exec.Interactive, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// execInstanceWebsockets connects the websockets of an exec operation to the provided arguments.
+func (r *ProtocolIncus) execInstanceWebsockets(opAPI api.Operation, interactive bool, args *InstanceExecArgs) error {
+	// Parse the fds
+	fds := map[string]string{}
+
+	value, ok := opAPI.Metadata["fds"]
+	if ok {
+		values, ok := value.(map[string]any)
+		if ok {
+			for k, v := range values {
+				val, ok := v.(string)
+				if ok {
+					fds[k] = val
+				}
+			}
+		}
+	}
+
 	if fds[api.SecretNameControl] != "" {
 		conn, err := r.GetOperationWebsocket(opAPI.ID, fds[api.SecretNameControl])
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		go func() {
@@ -1287,13 +1368,13 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		}
 	}
 
-	if exec.Interactive {
+	if interactive {
 		// Handle interactive sections
 		if args.Stdin != nil && args.Stdout != nil {
 			// Connect to the websocket
 			conn, err := r.GetOperationWebsocket(opAPI.ID, fds["0"])
 			if err != nil {
-				return nil, err
+				return err
 			}
 
 			// And attach stdin and stdout to it
@@ -1320,7 +1401,7 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		if fds["0"] != "" {
 			conn, err := r.GetOperationWebsocket(opAPI.ID, fds["0"])
 			if err != nil {
-				return nil, err
+				return err
 			}
 
 			go func() {
@@ -1337,7 +1418,7 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		if fds["1"] != "" {
 			conn, err := r.GetOperationWebsocket(opAPI.ID, fds["1"])
 			if err != nil {
-				return nil, err
+				return err
 			}
 
 			// Discard Stdout from remote command if output writer not supplied.
@@ -1354,7 +1435,7 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		if fds["2"] != "" {
 			conn, err := r.GetOperationWebsocket(opAPI.ID, fds["2"])
 			if err != nil {
-				return nil, err
+				return err
 			}
 
 			// Discard Stderr from remote command if output writer not supplied.
@@ -1402,7 +1483,35 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		}()
 	}
 
-	return op, nil
+	return nil
+}
+
+// ExecInstanceSync runs a command inside the instance and returns its exit status and output once it has exited.
+func (r *ProtocolIncus) ExecInstanceSync(instanceName string, exec api.InstanceExecPost) (*api.InstanceExecResult, error) {
+	if !r.HasExtension("instance_exec_sync") {
+		return nil, errors.New("The server is missing the required \"instance_exec_sync\" API extension")
+	}
+
+	if exec.User > 0 || exec.Group > 0 || exec.Cwd != "" {
+		if !r.HasExtension("container_exec_user_group_cwd") {
+			return nil, errors.New("The server is missing the required \"container_exec_user_group_cwd\" API extension")
+		}
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	result := api.InstanceExecResult{}
+
+	// Send the request
+	_, err = r.queryStruct("POST", fmt.Sprintf("%s/%s/exec?wait=true", path, url.PathEscape(instanceName)), exec, "", &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
 }
 
 // GetInstanceFile retrieves the provided path from the instance.
@@ -1447,7 +1556,36 @@ func (r *ProtocolIncus) GetInstanceFile(instanceName string, filePath string) (i
 		return nil, nil, err
 	}
 
-	requestURL, err = r.setQueryAttributes(requestURL)
+	return r.getInstanceFile(requestURL)
+}
+
+// GetInstanceSnapshotFile retrieves the provided path from the instance snapshot.
+func (r *ProtocolIncus) GetInstanceSnapshotFile(instanceName string, snapshotName string, filePath string) (io.ReadCloser, *InstanceFileResponse, error) {
+	if !r.HasExtension("instance_snapshot_files") {
+		return nil, nil, errors.New("The server is missing the required \"instance_snapshot_files\" API extension")
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Prepare the HTTP request
+	u, err := url.Parse(fmt.Sprintf("%s/1.0%s/%s/snapshots/%s/files", r.httpBaseURL.String(), path, url.PathEscape(instanceName), url.PathEscape(snapshotName)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := u.Query()
+	values.Set("path", filePath)
+	u.RawQuery = values.Encode()
+
+	return r.getInstanceFile(u.String())
+}
+
+// getInstanceFile retrieves a file or directory listing from the provided URL.
+func (r *ProtocolIncus) getInstanceFile(requestURL string) (io.ReadCloser, *InstanceFileResponse, error) {
+	requestURL, err := r.setQueryAttributes(requestURL)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -2009,8 +2147,12 @@ func (r *ProtocolIncus) CopyInstanceSnapshot(source InstanceServer, instanceName
 			}
 		}
 
-		// Launch the relay
-		err = r.proxyMigration(targetOp.(*operation), targetSecrets, source, op.(*operation), sourceSecrets)
+		// Launch the relay, either on the relay server or from the client
+		if args.Relay != nil {
+			err = r.relayMigration(args.Relay, targetOp.(*operation), targetSecrets, source, op.(*operation), sourceSecrets)
+		} else {
+			err = r.proxyMigration(targetOp.(*operation), targetSecrets, source, op.(*operation), sourceSecrets)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -2199,6 +2341,27 @@ func (r *ProtocolIncus) GetInstanceState(name string) (*api.InstanceState, strin
 	return &state, etag, nil
 }
 
+// GetInstanceDiskUsage returns the breakdown of the disk space used by the instance.
+func (r *ProtocolIncus) GetInstanceDiskUsage(name string) (*api.InstanceDiskUsage, error) {
+	if !r.HasExtension("instance_disk_usage") {
+		return nil, errors.New("The server is missing the required \"instance_disk_usage\" API extension")
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := api.InstanceDiskUsage{}
+
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s/%s/disk-usage", path, url.PathEscape(name)), nil, "", &usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
 // UpdateInstanceState updates the instance to match the requested state.
 func (r *ProtocolIncus) UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2536,6 +2699,87 @@ func (r *ProtocolIncus) DeleteInstanceTemplateFile(name string, templateName str
 	return err
 }
 
+// GetInstanceAccessLinks returns the access links of the instance.
+func (r *ProtocolIncus) GetInstanceAccessLinks(instanceName string) ([]api.InstanceAccessLink, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("instance_access_links") {
+		return nil, errors.New("The server is missing the required \"instance_access_links\" API extension")
+	}
+
+	// Fetch the raw value
+	links := []api.InstanceAccessLink{}
+
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s/%s/access-links?recursion=1", path, url.PathEscape(instanceName)), nil, "", &links)
+	if err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// GetInstanceAccessLink returns the access link of the instance with the given identifier.
+func (r *ProtocolIncus) GetInstanceAccessLink(instanceName string, id string) (*api.InstanceAccessLink, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("instance_access_links") {
+		return nil, errors.New("The server is missing the required \"instance_access_links\" API extension")
+	}
+
+	// Fetch the raw value
+	link := api.InstanceAccessLink{}
+
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s/%s/access-links/%s", path, url.PathEscape(instanceName), url.PathEscape(id)), nil, "", &link)
+	if err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// CreateInstanceAccessLink creates a new access link to the instance and returns its token.
+func (r *ProtocolIncus) CreateInstanceAccessLink(instanceName string, link api.InstanceAccessLinksPost) (*api.InstanceAccessLinkToken, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("instance_access_links") {
+		return nil, errors.New("The server is missing the required \"instance_access_links\" API extension")
+	}
+
+	// Send the request
+	token := api.InstanceAccessLinkToken{}
+
+	_, err = r.queryStruct("POST", fmt.Sprintf("%s/%s/access-links", path, url.PathEscape(instanceName)), link, "", &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// DeleteInstanceAccessLink revokes the access link of the instance.
+func (r *ProtocolIncus) DeleteInstanceAccessLink(instanceName string, id string) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return err
+	}
+
+	if !r.HasExtension("instance_access_links") {
+		return errors.New("The server is missing the required \"instance_access_links\" API extension")
+	}
+
+	_, _, err = r.query("DELETE", fmt.Sprintf("%s/%s/access-links/%s", path, url.PathEscape(instanceName), url.PathEscape(id)), nil, "")
+	return err
+}
+
 // ConsoleInstance requests that Incus attaches to the console device of a instance.
 func (r *ProtocolIncus) ConsoleInstance(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2559,6 +2803,10 @@ func (r *ProtocolIncus) ConsoleInstance(instanceName string, console api.Instanc
 		return nil, errors.New(`The server is missing the required "console_force" API extension`)
 	}
 
+	if console.Name != "" && !r.HasExtension("instance_console_serials") {
+		return nil, errors.New(`The server is missing the required "instance_console_serials" API extension`)
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/console", path, url.PathEscape(instanceName)), console, "")
 	if err != nil {
@@ -2656,6 +2904,10 @@ func (r *ProtocolIncus) ConsoleInstanceDynamic(instanceName string, console api.
 		return nil, nil, errors.New(`The server is missing the required "console_force" API extension`)
 	}
 
+	if console.Name != "" && !r.HasExtension("instance_console_serials") {
+		return nil, nil, errors.New(`The server is missing the required "instance_console_serials" API extension`)
+	}
+
 	// Send the request.
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/console", path, url.PathEscape(instanceName)), console, "")
 	if err != nil {
@@ -2730,7 +2982,7 @@ func (r *ProtocolIncus) ConsoleInstanceDynamic(instanceName string, console api.
 // GetInstanceConsoleLog requests that Incus attaches to the console device of a instance.
 //
 // Note that it's the caller's responsibility to close the returned ReadCloser.
-func (r *ProtocolIncus) GetInstanceConsoleLog(instanceName string, _ *InstanceConsoleLogArgs) (io.ReadCloser, error) {
+func (r *ProtocolIncus) GetInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) (io.ReadCloser, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
 	if err != nil {
 		return nil, err
@@ -2743,6 +2995,14 @@ func (r *ProtocolIncus) GetInstanceConsoleLog(instanceName string, _ *InstanceCo
 	// Prepare the HTTP request
 	uri := fmt.Sprintf("%s/1.0%s/%s/console", r.httpBaseURL.String(), path, url.PathEscape(instanceName))
 
+	if args != nil && args.Name != "" {
+		if !r.HasExtension("instance_console_serials") {
+			return nil, errors.New(`The server is missing the required "instance_console_serials" API extension`)
+		}
+
+		uri += "?name=" + url.QueryEscape(args.Name)
+	}
+
 	uri, err = r.setQueryAttributes(uri)
 	if err != nil {
 		return nil, err
@@ -2867,6 +3127,10 @@ func (r *ProtocolIncus) CreateInstanceBackup(instanceName string, backup api.Ins
 		return nil, errors.New("The server is missing the required \"container_backup\" API extension")
 	}
 
+	if backup.Target && !r.HasExtension("backup_target") {
+		return nil, errors.New("The server is missing the required \"backup_target\" API extension")
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/backups", path, url.PathEscape(instanceName)), backup, "")
 	if err != nil {
@@ -2987,6 +3251,11 @@ func (r *ProtocolIncus) GetInstanceBackupFile(instanceName string, name string,
 func (r *ProtocolIncus) proxyMigration(targetOp *operation, targetSecrets map[string]string, source InstanceServer, sourceOp *operation, sourceSecrets map[string]string) error {
 	// Quick checks.
 	for n := range targetSecrets {
+		// Additional filesystem connections are optional and only used if offered by both sides.
+		if n != api.SecretNameFilesystem && strings.HasPrefix(n, api.SecretNameFilesystem) {
+			continue
+		}
+
 		_, ok := sourceSecrets[n]
 		if !ok {
 			return fmt.Errorf("Migration target expects the \"%s\" socket but source isn't providing it", n)
@@ -3029,6 +3298,11 @@ func (r *ProtocolIncus) proxyMigration(targetOp *operation, targetSecrets map[st
 			continue
 		}
 
+		// Skip connections the target doesn't expect.
+		if targetSecrets[name] == "" {
+			continue
+		}
+
 		// Handle resets (used for multiple objects)
 		sourceConn, err := source.GetOperationWebsocket(sourceOp.ID, sourceSecrets[name])
 		if err != nil {
@@ -3069,6 +3343,42 @@ func (r *ProtocolIncus) proxyMigration(targetOp *operation, targetSecrets map[st
 	return nil
 }
 
+// relayMigration has the relay server connect to the migration websockets of both the source and the target
+// and proxy the data between them, for servers which can't reach each other.
+func (r *ProtocolIncus) relayMigration(relay InstanceServer, targetOp *operation, targetSecrets map[string]string, source InstanceServer, sourceOp *operation, sourceSecrets map[string]string) error {
+	sourceInfo, err := source.GetConnectionInfo()
+	if err != nil {
+		return err
+	}
+
+	targetInfo, err := r.GetConnectionInfo()
+	if err != nil {
+		return err
+	}
+
+	req := api.MigrationRelayPost{
+		Source: api.MigrationRelayEndpoint{
+			Addresses:   sourceInfo.Addresses,
+			Certificate: sourceInfo.Certificate,
+			Operation:   sourceOp.ID,
+			Websockets:  sourceSecrets,
+		},
+		Target: api.MigrationRelayEndpoint{
+			Addresses:   targetInfo.Addresses,
+			Certificate: targetInfo.Certificate,
+			Operation:   targetOp.ID,
+			Websockets:  targetSecrets,
+		},
+	}
+
+	_, err = relay.CreateMigrationRelay(req)
+	if err != nil {
+		return fmt.Errorf("Failed relaying the migration: %w", err)
+	}
+
+	return nil
+}
+
 // GetInstanceDebugMemory retrieves memory debug information for a given instance and saves it to the specified file path.
 func (r *ProtocolIncus) GetInstanceDebugMemory(name string, format string) (io.ReadCloser, error) {
 	path, v, err := r.instanceTypeToPath(api.InstanceTypeVM)
@@ -3107,3 +3417,138 @@ func (r *ProtocolIncus) GetInstanceDebugMemory(name string, format string) (io.R
 
 	return resp.Body, nil
 }
+
+// GetInstanceDebugLXCConfig returns the final liblxc configuration of a container.
+func (r *ProtocolIncus) GetInstanceDebugLXCConfig(name string) (*api.InstanceDebugLXCConfig, error) {
+	if !r.HasExtension("instance_debug_lxc_config") {
+		return nil, errors.New("The server is missing the required \"instance_debug_lxc_config\" API extension")
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeContainer)
+	if err != nil {
+		return nil, err
+	}
+
+	config := api.InstanceDebugLXCConfig{}
+
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s/%s/debug/lxc-config", path, url.PathEscape(name)), nil, "", &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// RunInstanceDebugQMP sends a QMP command to a running virtual machine and returns its result.
+func (r *ProtocolIncus) RunInstanceDebugQMP(name string, command api.InstanceDebugQMPPost) (*api.InstanceDebugQMP, error) {
+	if !r.HasExtension("instance_debug_qmp") {
+		return nil, errors.New("The server is missing the required \"instance_debug_qmp\" API extension")
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeVM)
+	if err != nil {
+		return nil, err
+	}
+
+	result := api.InstanceDebugQMP{}
+
+	_, err = r.queryStruct("POST", fmt.Sprintf("%s/%s/debug/qmp", path, url.PathEscape(name)), command, "", &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ExecInstanceDebugNetns runs a command on the host, joined only to the network namespace of a running container.
+func (r *ProtocolIncus) ExecInstanceDebugNetns(name string, command api.InstanceDebugNetnsPost, args *InstanceExecArgs) (Operation, error) {
+	if !r.HasExtension("instance_debug_netns") {
+		return nil, errors.New("The server is missing the required \"instance_debug_netns\" API extension")
+	}
+
+	// Ensure args are equivalent to empty InstanceExecArgs.
+	if args == nil {
+		args = &InstanceExecArgs{}
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeContainer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/debug/netns", path, url.PathEscape(name)), command, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.execInstanceWebsockets(op.Get(), command.Interactive, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// CreateInstanceDebugProfile captures a CPU profile of a running instance on the server.
+// The folded stacks are returned under the "profile" key of the operation metadata.
+func (r *ProtocolIncus) CreateInstanceDebugProfile(name string, profile api.InstanceDebugProfilePost) (Operation, error) {
+	if !r.HasExtension("instance_debug_profile") {
+		return nil, errors.New("The server is missing the required \"instance_debug_profile\" API extension")
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/debug/profile", path, url.PathEscape(name)), profile, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// GetInstanceDeviceMedia returns the state of the removable media of an instance device.
+func (r *ProtocolIncus) GetInstanceDeviceMedia(name string, device string) (*api.InstanceDeviceMedia, error) {
+	if !r.HasExtension("instance_device_media") {
+		return nil, errors.New("The server is missing the required \"instance_device_media\" API extension")
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeVM)
+	if err != nil {
+		return nil, err
+	}
+
+	media := api.InstanceDeviceMedia{}
+
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s/%s/devices/%s/media", path, url.PathEscape(name), url.PathEscape(device)), nil, "", &media)
+	if err != nil {
+		return nil, err
+	}
+
+	return &media, nil
+}
+
+// UpdateInstanceDeviceMedia ejects, inserts or toggles the removable media of an instance device.
+func (r *ProtocolIncus) UpdateInstanceDeviceMedia(name string, device string, req api.InstanceDeviceMediaPost) (*api.InstanceDeviceMedia, error) {
+	if !r.HasExtension("instance_device_media") {
+		return nil, errors.New("The server is missing the required \"instance_device_media\" API extension")
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeVM)
+	if err != nil {
+		return nil, err
+	}
+
+	media := api.InstanceDeviceMedia{}
+
+	_, err = r.queryStruct("POST", fmt.Sprintf("%s/%s/devices/%s/media", path, url.PathEscape(name), url.PathEscape(device)), req, "", &media)
+	if err != nil {
+		return nil, err
+	}
+
+	return &media, nil
+}
@@ -77,6 +77,40 @@ func (r *ProtocolIncus) GetNetworkACL(name string) (*api.NetworkACL, string, err
 	return &acl, etag, nil
 }
 
+// GetNetworkACLRevisions returns the recorded previous configurations of the network ACL, oldest first.
+func (r *ProtocolIncus) GetNetworkACLRevisions(name string) ([]api.ConfigRevision, error) {
+	if !r.HasExtension("config_revisions") {
+		return nil, errors.New(`The server is missing the required "config_revisions" API extension`)
+	}
+
+	revisions := []api.ConfigRevision{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/network-acls/%s/revisions?recursion=1", url.PathEscape(name)), nil, "", &revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetNetworkACLRevision returns a recorded previous configuration of the network ACL.
+func (r *ProtocolIncus) GetNetworkACLRevision(name string, revision int64) (*api.ConfigRevision, error) {
+	if !r.HasExtension("config_revisions") {
+		return nil, errors.New(`The server is missing the required "config_revisions" API extension`)
+	}
+
+	configRevision := api.ConfigRevision{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/network-acls/%s/revisions/%d", url.PathEscape(name), revision), nil, "", &configRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configRevision, nil
+}
+
 // GetNetworkACLLogfile returns a reader for the ACL log file.
 //
 // Note that it's the caller's responsibility to close the returned ReadCloser.
@@ -117,6 +117,40 @@ func (r *ProtocolIncus) GetNetwork(name string) (*api.Network, string, error) {
 	return &network, etag, nil
 }
 
+// GetNetworkRevisions returns the recorded previous configurations of the network, oldest first.
+func (r *ProtocolIncus) GetNetworkRevisions(name string) ([]api.ConfigRevision, error) {
+	if !r.HasExtension("config_revisions") {
+		return nil, errors.New(`The server is missing the required "config_revisions" API extension`)
+	}
+
+	revisions := []api.ConfigRevision{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/networks/%s/revisions?recursion=1", url.PathEscape(name)), nil, "", &revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetNetworkRevision returns a recorded previous configuration of the network.
+func (r *ProtocolIncus) GetNetworkRevision(name string, revision int64) (*api.ConfigRevision, error) {
+	if !r.HasExtension("config_revisions") {
+		return nil, errors.New(`The server is missing the required "config_revisions" API extension`)
+	}
+
+	configRevision := api.ConfigRevision{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/networks/%s/revisions/%d", url.PathEscape(name), revision), nil, "", &configRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configRevision, nil
+}
+
 // GetNetworkLeases returns a list of Network struct.
 func (r *ProtocolIncus) GetNetworkLeases(name string) ([]api.NetworkLease, error) {
 	if !r.HasExtension("network_leases") {
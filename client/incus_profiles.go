@@ -104,6 +104,40 @@ func (r *ProtocolIncus) GetProfile(name string) (*api.Profile, string, error) {
 	return &profile, etag, nil
 }
 
+// GetProfileRevisions returns the recorded previous configurations of the profile, oldest first.
+func (r *ProtocolIncus) GetProfileRevisions(name string) ([]api.ConfigRevision, error) {
+	if !r.HasExtension("config_revisions") {
+		return nil, errors.New(`The server is missing the required "config_revisions" API extension`)
+	}
+
+	revisions := []api.ConfigRevision{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/profiles/%s/revisions?recursion=1", url.PathEscape(name)), nil, "", &revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetProfileRevision returns a recorded previous configuration of the profile.
+func (r *ProtocolIncus) GetProfileRevision(name string, revision int64) (*api.ConfigRevision, error) {
+	if !r.HasExtension("config_revisions") {
+		return nil, errors.New(`The server is missing the required "config_revisions" API extension`)
+	}
+
+	configRevision := api.ConfigRevision{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/profiles/%s/revisions/%d", url.PathEscape(name), revision), nil, "", &configRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configRevision, nil
+}
+
 // CreateProfile defines a new instance profile.
 func (r *ProtocolIncus) CreateProfile(profile api.ProfilesPost) error {
 	// Send the request
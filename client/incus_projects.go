@@ -82,6 +82,40 @@ func (r *ProtocolIncus) GetProject(name string) (*api.Project, string, error) {
 	return &project, etag, nil
 }
 
+// GetProjectRevisions returns the recorded previous configurations of the project, oldest first.
+func (r *ProtocolIncus) GetProjectRevisions(name string) ([]api.ConfigRevision, error) {
+	if !r.HasExtension("config_revisions") {
+		return nil, errors.New(`The server is missing the required "config_revisions" API extension`)
+	}
+
+	revisions := []api.ConfigRevision{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/projects/%s/revisions?recursion=1", url.PathEscape(name)), nil, "", &revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetProjectRevision returns a recorded previous configuration of the project.
+func (r *ProtocolIncus) GetProjectRevision(name string, revision int64) (*api.ConfigRevision, error) {
+	if !r.HasExtension("config_revisions") {
+		return nil, errors.New(`The server is missing the required "config_revisions" API extension`)
+	}
+
+	configRevision := api.ConfigRevision{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/projects/%s/revisions/%d", url.PathEscape(name), revision), nil, "", &configRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configRevision, nil
+}
+
 // GetProjectState returns a Project state for the provided name.
 func (r *ProtocolIncus) GetProjectState(name string) (*api.ProjectState, error) {
 	if !r.HasExtension("project_usage") {
@@ -0,0 +1,135 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetReportNames returns the names of the generated reports.
+func (r *ProtocolIncus) GetReportNames() ([]string, error) {
+	if !r.HasExtension("reports") {
+		return nil, errors.New("The server is missing the required \"reports\" API extension")
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/reports"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetReports returns the generated reports.
+func (r *ProtocolIncus) GetReports() ([]api.Report, error) {
+	if !r.HasExtension("reports") {
+		return nil, errors.New("The server is missing the required \"reports\" API extension")
+	}
+
+	// Fetch the raw value
+	reports := []api.Report{}
+
+	_, err := r.queryStruct("GET", "/reports?recursion=1", nil, "", &reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// GetReport returns a generated report.
+func (r *ProtocolIncus) GetReport(name string) (*api.Report, error) {
+	if !r.HasExtension("reports") {
+		return nil, errors.New("The server is missing the required \"reports\" API extension")
+	}
+
+	// Fetch the raw value
+	report := api.Report{}
+
+	_, err := r.queryStruct("GET", fmt.Sprintf("/reports/%s", url.PathEscape(name)), nil, "", &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// GetReportSection returns a section of a generated report in the requested format (json or csv).
+//
+// Note that it's the caller's responsibility to close the returned ReadCloser.
+func (r *ProtocolIncus) GetReportSection(name string, section string, format string) (io.ReadCloser, error) {
+	if !r.HasExtension("reports") {
+		return nil, errors.New("The server is missing the required \"reports\" API extension")
+	}
+
+	v := url.Values{}
+	v.Set("section", section)
+	v.Set("format", format)
+
+	// Prepare the HTTP request
+	uri := fmt.Sprintf("%s/1.0/reports/%s?%s", r.httpBaseURL.String(), url.PathEscape(name), v.Encode())
+
+	uri, err := r.setQueryAttributes(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := incusParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, err
+}
+
+// CreateReport requests the generation of a new report.
+func (r *ProtocolIncus) CreateReport(req api.ReportsPost) (Operation, error) {
+	if !r.HasExtension("reports") {
+		return nil, errors.New("The server is missing the required \"reports\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", "/reports", req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteReport deletes a generated report.
+func (r *ProtocolIncus) DeleteReport(name string) error {
+	if !r.HasExtension("reports") {
+		return errors.New("The server is missing the required \"reports\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/reports/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
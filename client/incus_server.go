@@ -190,6 +190,36 @@ func (r *ProtocolIncus) GetMetrics() (string, error) {
 	return string(content), nil
 }
 
+// GetMigrationStatistics returns the statistics of the migrations recently sent by the server.
+func (r *ProtocolIncus) GetMigrationStatistics() ([]api.MigrationStatistics, error) {
+	if !r.HasExtension("migration_statistics") {
+		return nil, errors.New("The server is missing the required \"migration_statistics\" API extension")
+	}
+
+	stats := []api.MigrationStatistics{}
+
+	_, err := r.queryStruct("GET", "/metrics/migrations", nil, "", &stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// CreateMigrationRelay requests that the server relays the connections of a migration between two other servers.
+func (r *ProtocolIncus) CreateMigrationRelay(relay api.MigrationRelayPost) (Operation, error) {
+	if !r.HasExtension("migration_relay") {
+		return nil, errors.New("The server is missing the required \"migration_relay\" API extension")
+	}
+
+	op, _, err := r.queryOperation("POST", "/migration-relays", relay, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // ApplyServerPreseed configures a target Incus server with the provided server and cluster configuration.
 func (r *ProtocolIncus) ApplyServerPreseed(config api.InitPreseed) error {
 	// Apply server configuration.
@@ -62,6 +62,40 @@ func (r *ProtocolIncus) GetStoragePool(name string) (*api.StoragePool, string, e
 	return &pool, etag, nil
 }
 
+// GetStoragePoolMigration gets the migration features the server supports for a given storage pool.
+func (r *ProtocolIncus) GetStoragePoolMigration(name string) (*api.StoragePoolMigration, error) {
+	if !r.HasExtension("storage_pool_migration") {
+		return nil, errors.New("The server is missing the required \"storage_pool_migration\" API extension")
+	}
+
+	migration := api.StoragePoolMigration{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/storage-pools/%s/migration", url.PathEscape(name)), nil, "", &migration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &migration, nil
+}
+
+// GetStoragePoolStatistics gets the deduplication and compression statistics of a given storage pool.
+func (r *ProtocolIncus) GetStoragePoolStatistics(name string) (*api.StoragePoolStatistics, error) {
+	if !r.HasExtension("storage_pool_statistics") {
+		return nil, errors.New("The server is missing the required \"storage_pool_statistics\" API extension")
+	}
+
+	statistics := api.StoragePoolStatistics{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/storage-pools/%s/statistics", url.PathEscape(name)), nil, "", &statistics)
+	if err != nil {
+		return nil, err
+	}
+
+	return &statistics, nil
+}
+
 // CreateStoragePool defines a new storage pool using the provided StoragePool struct.
 func (r *ProtocolIncus) CreateStoragePool(pool api.StoragePoolsPost) error {
 	if !r.HasExtension("storage") {
@@ -208,12 +208,76 @@ func (r *ProtocolIncus) GetStoragePoolVolumeState(pool string, volType string, n
 	return &state, nil
 }
 
+// GetStoragePoolVolumeMirror returns the mirroring state of the volume.
+func (r *ProtocolIncus) GetStoragePoolVolumeMirror(pool string, volType string, name string) (*api.StorageVolumeMirror, error) {
+	if !r.HasExtension("storage_volume_mirror") {
+		return nil, errors.New("The server is missing the required \"storage_volume_mirror\" API extension")
+	}
+
+	// Fetch the raw value
+	mirror := api.StorageVolumeMirror{}
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s/%s/mirror", url.PathEscape(pool), url.PathEscape(volType), url.PathEscape(name))
+	_, err := r.queryStruct("GET", path, nil, "", &mirror)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mirror, nil
+}
+
+// UpdateStoragePoolVolumeMirror promotes or demotes the local copy of a mirrored volume.
+func (r *ProtocolIncus) UpdateStoragePoolVolumeMirror(pool string, volType string, name string, mirror api.StorageVolumeMirrorPost) error {
+	if !r.HasExtension("storage_volume_mirror") {
+		return errors.New("The server is missing the required \"storage_volume_mirror\" API extension")
+	}
+
+	// Send the request
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s/%s/mirror", url.PathEscape(pool), url.PathEscape(volType), url.PathEscape(name))
+	_, _, err := r.query("POST", path, mirror, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetStoragePoolVolumeDiff returns the changes of the volume between the from snapshot and the to snapshot, or
+// the volume itself if to is empty.
+func (r *ProtocolIncus) GetStoragePoolVolumeDiff(pool string, volType string, name string, from string, to string) ([]api.StorageVolumeDiffEntry, error) {
+	if !r.HasExtension("storage_volume_snapshot_diff") {
+		return nil, errors.New("The server is missing the required \"storage_volume_snapshot_diff\" API extension")
+	}
+
+	v := url.Values{}
+	v.Set("from", from)
+	if to != "" {
+		v.Set("to", to)
+	}
+
+	// Fetch the raw value
+	changes := []api.StorageVolumeDiffEntry{}
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s/%s/diff?%s", url.PathEscape(pool), url.PathEscape(volType), url.PathEscape(name), v.Encode())
+	_, err := r.queryStruct("GET", path, nil, "", &changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
 // CreateStoragePoolVolume defines a new storage volume.
 func (r *ProtocolIncus) CreateStoragePoolVolume(pool string, volume api.StorageVolumesPost) error {
 	if !r.HasExtension("storage") {
 		return errors.New("The server is missing the required \"storage\" API extension")
 	}
 
+	if volume.Source.Type == "existing" {
+		err := r.CheckExtension("storage_volume_import_existing")
+		if err != nil {
+			return err
+		}
+	}
+
 	// Send the request
 	path := fmt.Sprintf("/storage-pools/%s/volumes/%s", url.PathEscape(pool), url.PathEscape(volume.Type))
 	_, _, err := r.query("POST", path, volume, "")
@@ -1065,6 +1129,25 @@ func (r *ProtocolIncus) CreateStoragePoolVolumeFromISO(pool string, args Storage
 	return &op, nil
 }
 
+// CreateStoragePoolVolumeFromURL creates a custom volume from content downloaded by the server.
+func (r *ProtocolIncus) CreateStoragePoolVolumeFromURL(pool string, volume api.StorageVolumesPost) (Operation, error) {
+	err := r.CheckExtension("storage_volume_import_url")
+	if err != nil {
+		return nil, err
+	}
+
+	volume.Source.Type = "url"
+
+	// Send the request
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s", url.PathEscape(pool), url.PathEscape(volume.Type))
+	op, _, err := r.queryOperation("POST", path, volume, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // CreateStoragePoolVolumeFromBackup creates a custom volume from a backup file.
 func (r *ProtocolIncus) CreateStoragePoolVolumeFromBackup(pool string, args StorageVolumeBackupArgs) (Operation, error) {
 	if !r.HasExtension("custom_volume_backup") {
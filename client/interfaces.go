@@ -2,9 +2,11 @@ package incus
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -76,6 +78,8 @@ type InstanceServer interface {
 
 	// Server functions
 	GetMetrics() (metrics string, err error)
+	GetMigrationStatistics() (stats []api.MigrationStatistics, err error)
+	CreateMigrationRelay(relay api.MigrationRelayPost) (op Operation, err error)
 	GetServer() (server *api.Server, ETag string, err error)
 	GetServerResources() (resources *api.Resources, err error)
 	UpdateServer(server api.ServerPut, ETag string) (err error)
@@ -115,19 +119,27 @@ type InstanceServer interface {
 	UpdateInstance(name string, instance api.InstancePut, ETag string) (op Operation, err error)
 	RenameInstance(name string, instance api.InstancePost) (op Operation, err error)
 	MigrateInstance(name string, instance api.InstancePost) (op Operation, err error)
+	CheckMigrateInstance(name string, instance api.InstancePost) (report *api.InstanceMigrationCheck, err error)
 	DeleteInstance(name string) (op Operation, err error)
 	UpdateInstances(state api.InstancesPut, ETag string) (op Operation, err error)
 	RebuildInstance(instanceName string, req api.InstanceRebuildPost) (op Operation, err error)
 	RebuildInstanceFromImage(source ImageServer, image api.Image, instanceName string, req api.InstanceRebuildPost) (op RemoteOperation, err error)
 
 	ExecInstance(instanceName string, exec api.InstanceExecPost, args *InstanceExecArgs) (op Operation, err error)
+	ExecInstanceSync(instanceName string, exec api.InstanceExecPost) (result *api.InstanceExecResult, err error)
 	ConsoleInstance(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (op Operation, err error)
 	ConsoleInstanceDynamic(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (Operation, func(io.ReadWriteCloser) error, error)
 
 	GetInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) (content io.ReadCloser, err error)
 	DeleteInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) (err error)
 
+	GetInstanceAccessLinks(instanceName string) (links []api.InstanceAccessLink, err error)
+	GetInstanceAccessLink(instanceName string, id string) (link *api.InstanceAccessLink, err error)
+	CreateInstanceAccessLink(instanceName string, link api.InstanceAccessLinksPost) (token *api.InstanceAccessLinkToken, err error)
+	DeleteInstanceAccessLink(instanceName string, id string) (err error)
+
 	GetInstanceFile(instanceName string, path string) (content io.ReadCloser, resp *InstanceFileResponse, err error)
+	GetInstanceSnapshotFile(instanceName string, snapshotName string, path string) (content io.ReadCloser, resp *InstanceFileResponse, err error)
 	CreateInstanceFile(instanceName string, path string, args InstanceFileArgs) (err error)
 	DeleteInstanceFile(instanceName string, path string) (err error)
 
@@ -153,8 +165,15 @@ type InstanceServer interface {
 	GetInstanceBackupFile(instanceName string, name string, req *BackupFileRequest) (resp *BackupFileResponse, err error)
 	CreateInstanceFromBackup(args InstanceBackupArgs) (op Operation, err error)
 
+	// Backup target functions ("backup_target" API extension)
+	GetBackupPoints(instanceName string) (points []api.BackupPoint, err error)
+	GetBackupPoint(id string) (point *api.BackupPoint, err error)
+	RestoreBackupPoint(id string, req api.BackupPointPost) (op Operation, err error)
+	DeleteBackupPoint(id string) (op Operation, err error)
+
 	GetInstanceState(name string) (state *api.InstanceState, ETag string, err error)
 	UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (op Operation, err error)
+	GetInstanceDiskUsage(name string) (usage *api.InstanceDiskUsage, err error)
 
 	GetInstanceAccess(name string) (access api.Access, err error)
 
@@ -171,10 +190,18 @@ type InstanceServer interface {
 	DeleteInstanceTemplateFile(name string, templateName string) (err error)
 
 	GetInstanceDebugMemory(name string, format string) (rc io.ReadCloser, err error)
+	GetInstanceDebugLXCConfig(name string) (config *api.InstanceDebugLXCConfig, err error)
+	RunInstanceDebugQMP(name string, command api.InstanceDebugQMPPost) (result *api.InstanceDebugQMP, err error)
+	ExecInstanceDebugNetns(name string, command api.InstanceDebugNetnsPost, args *InstanceExecArgs) (op Operation, err error)
+	CreateInstanceDebugProfile(name string, profile api.InstanceDebugProfilePost) (op Operation, err error)
+
+	GetInstanceDeviceMedia(name string, device string) (media *api.InstanceDeviceMedia, err error)
+	UpdateInstanceDeviceMedia(name string, device string, req api.InstanceDeviceMediaPost) (media *api.InstanceDeviceMedia, err error)
 
 	// Event handling functions
 	GetEvents() (listener *EventListener, err error)
 	GetEventsAllProjects() (listener *EventListener, err error)
+	GetEventsSince(since time.Time, allProjects bool, types []string, function func(api.Event)) (listener *EventListener, err error)
 	SendEvent(event api.Event) error
 
 	// Image functions
@@ -183,7 +210,9 @@ type InstanceServer interface {
 	UpdateImage(fingerprint string, image api.ImagePut, ETag string) (err error)
 	DeleteImage(fingerprint string) (op Operation, err error)
 	RefreshImage(fingerprint string) (op Operation, err error)
+	GetImageSBOM(fingerprint string) (sbom json.RawMessage, err error)
 	CreateImageSecret(fingerprint string) (op Operation, err error)
+	GetImageAliasesByNames(names []string) (aliases []api.ImageAliasesEntry, err error)
 	CreateImageAlias(alias api.ImageAliasesPost) (err error)
 	UpdateImageAlias(name string, alias api.ImageAliasesEntryPut, ETag string) (err error)
 	RenameImageAlias(name string, alias api.ImageAliasesEntryPost) (err error)
@@ -199,6 +228,8 @@ type InstanceServer interface {
 	GetNetworksAllProjects() (networks []api.Network, err error)
 	GetNetworksAllProjectsWithFilter(filters []string) (networks []api.Network, err error)
 	GetNetwork(name string) (network *api.Network, ETag string, err error)
+	GetNetworkRevisions(name string) (revisions []api.ConfigRevision, err error)
+	GetNetworkRevision(name string, revision int64) (configRevision *api.ConfigRevision, err error)
 	GetNetworkLeases(name string) (leases []api.NetworkLease, err error)
 	GetNetworkState(name string) (state *api.NetworkState, err error)
 	CreateNetwork(network api.NetworksPost) (err error)
@@ -236,6 +267,8 @@ type InstanceServer interface {
 	GetNetworkACLs() (acls []api.NetworkACL, err error)
 	GetNetworkACLsAllProjects() (acls []api.NetworkACL, err error)
 	GetNetworkACL(name string) (acl *api.NetworkACL, ETag string, err error)
+	GetNetworkACLRevisions(name string) (revisions []api.ConfigRevision, err error)
+	GetNetworkACLRevision(name string, revision int64) (configRevision *api.ConfigRevision, err error)
 	GetNetworkACLLogfile(name string) (log io.ReadCloser, err error)
 	CreateNetworkACL(acl api.NetworkACLsPost) (err error)
 	UpdateNetworkACL(name string, acl api.NetworkACLPut, ETag string) (err error)
@@ -298,6 +331,8 @@ type InstanceServer interface {
 	GetProfiles() (profiles []api.Profile, err error)
 	GetProfilesWithFilter(filters []string) ([]api.Profile, error)
 	GetProfile(name string) (profile *api.Profile, ETag string, err error)
+	GetProfileRevisions(name string) (revisions []api.ConfigRevision, err error)
+	GetProfileRevision(name string, revision int64) (configRevision *api.ConfigRevision, err error)
 	CreateProfile(profile api.ProfilesPost) (err error)
 	UpdateProfile(name string, profile api.ProfilePut, ETag string) (err error)
 	RenameProfile(name string, profile api.ProfilePost) (err error)
@@ -308,6 +343,8 @@ type InstanceServer interface {
 	GetProjects() (projects []api.Project, err error)
 	GetProjectsWithFilter(filters []string) (projects []api.Project, err error)
 	GetProject(name string) (project *api.Project, ETag string, err error)
+	GetProjectRevisions(name string) (revisions []api.ConfigRevision, err error)
+	GetProjectRevision(name string, revision int64) (configRevision *api.ConfigRevision, err error)
 	GetProjectState(name string) (project *api.ProjectState, err error)
 	GetProjectAccess(name string) (access api.Access, err error)
 	CreateProject(project api.ProjectsPost) (err error)
@@ -316,11 +353,21 @@ type InstanceServer interface {
 	DeleteProject(name string) (err error)
 	DeleteProjectForce(name string) (err error)
 
+	// Report functions ("reports" API extension)
+	GetReportNames() (names []string, err error)
+	GetReports() (reports []api.Report, err error)
+	GetReport(name string) (report *api.Report, err error)
+	GetReportSection(name string, section string, format string) (content io.ReadCloser, err error)
+	CreateReport(req api.ReportsPost) (op Operation, err error)
+	DeleteReport(name string) (err error)
+
 	// Storage pool functions ("storage" API extension)
 	GetStoragePoolNames() (names []string, err error)
 	GetStoragePools() (pools []api.StoragePool, err error)
 	GetStoragePool(name string) (pool *api.StoragePool, ETag string, err error)
 	GetStoragePoolResources(name string) (resources *api.ResourcesStoragePool, err error)
+	GetStoragePoolMigration(name string) (migration *api.StoragePoolMigration, err error)
+	GetStoragePoolStatistics(name string) (statistics *api.StoragePoolStatistics, err error)
 	CreateStoragePool(pool api.StoragePoolsPost) (err error)
 	UpdateStoragePool(name string, pool api.StoragePoolPut, ETag string) (err error)
 	DeleteStoragePool(name string) (err error)
@@ -357,6 +404,9 @@ type InstanceServer interface {
 	GetStoragePoolVolumesWithFilterAllProjects(pool string, filters []string) (volumes []api.StorageVolume, err error)
 	GetStoragePoolVolume(pool string, volType string, name string) (volume *api.StorageVolume, ETag string, err error)
 	GetStoragePoolVolumeState(pool string, volType string, name string) (state *api.StorageVolumeState, err error)
+	GetStoragePoolVolumeMirror(pool string, volType string, name string) (mirror *api.StorageVolumeMirror, err error)
+	UpdateStoragePoolVolumeMirror(pool string, volType string, name string, mirror api.StorageVolumeMirrorPost) (err error)
+	GetStoragePoolVolumeDiff(pool string, volType string, name string, from string, to string) (changes []api.StorageVolumeDiffEntry, err error)
 	CreateStoragePoolVolume(pool string, volume api.StorageVolumesPost) (err error)
 	UpdateStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumePut, ETag string) (err error)
 	DeleteStoragePoolVolume(pool string, volType string, name string) (err error)
@@ -386,6 +436,7 @@ type InstanceServer interface {
 
 	// Storage volume ISO import function ("custom_volume_iso" API extension)
 	CreateStoragePoolVolumeFromISO(pool string, args StorageVolumeBackupArgs) (op Operation, err error)
+	CreateStoragePoolVolumeFromURL(pool string, volume api.StorageVolumesPost) (op Operation, err error)
 	CreateStoragePoolVolumeFromMigration(pool string, volume api.StorageVolumesPost) (op Operation, err error)
 
 	// Storage volume SFTP functions ("custom_volume_sftp" API extension)
@@ -405,6 +456,8 @@ type InstanceServer interface {
 	UpdateClusterCertificate(certs api.ClusterCertificatePut, ETag string) (err error)
 	GetClusterMemberState(name string) (*api.ClusterMemberState, string, error)
 	UpdateClusterMemberState(name string, state api.ClusterMemberStatePost) (op Operation, err error)
+	CreateClusterMemberSnapshots(name string, snapshots api.ClusterMemberSnapshotsPost) (op Operation, err error)
+	DeleteClusterMemberSnapshots(name string, snapshotName string) (op Operation, err error)
 	GetClusterGroups() ([]api.ClusterGroup, error)
 	GetClusterGroupNames() ([]string, error)
 	RenameClusterGroup(name string, group api.ClusterGroupPost) error
@@ -607,6 +660,18 @@ type InstanceCopyArgs struct {
 
 	// API extension: instance_allow_inconsistent_copy
 	AllowInconsistent bool
+
+	// API extension: migration_relay
+	// If set in "relay" mode, the server relaying the migration instead of the client
+	Relay InstanceServer
+
+	// API extension: migration_verify
+	// If set, the checksums of the volumes are compared once transferred
+	Verify bool
+
+	// API extension: instance_copy_instant
+	// If set, the instance is copied as an instant copy-on-write clone on the same storage pool
+	Instant bool
 }
 
 // The InstanceSnapshotCopyArgs struct is used to pass additional options during instance copy.
@@ -620,6 +685,10 @@ type InstanceSnapshotCopyArgs struct {
 	// API extension: container_snapshot_stateful_migration
 	// If set, the instance running state will be transferred (live migration)
 	Live bool
+
+	// API extension: migration_relay
+	// If set in "relay" mode, the server relaying the migration instead of the client
+	Relay InstanceServer
 }
 
 // The InstanceConsoleArgs struct is used to pass additional options during a
@@ -637,7 +706,12 @@ type InstanceConsoleArgs struct {
 
 // The InstanceConsoleLogArgs struct is used to pass additional options during a
 // instance console log request.
-type InstanceConsoleLogArgs struct{}
+type InstanceConsoleLogArgs struct {
+	// Name of the additional serial console
+	//
+	// API extension: instance_console_serials
+	Name string
+}
 
 // The InstanceExecArgs struct is used to pass additional options during instance exec.
 type InstanceExecArgs struct {
@@ -25,6 +25,8 @@ var api10Cmd = APIEndpoint{
 
 var api10 = []APIEndpoint{
 	api10Cmd,
+	clockCmd,
+	diskGrowCmd,
 	execCmd,
 	eventsCmd,
 	metricsCmd,
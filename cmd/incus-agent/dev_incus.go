@@ -217,6 +217,60 @@ var DevIncusDevicesGet = devIncusHandler{"/1.0/devices", func(d *Daemon, w http.
 	return okResponse(devices, "json")
 }}
 
+var DevIncusTokenPost = devIncusHandler{"/1.0/token", func(d *Daemon, w http.ResponseWriter, r *http.Request) *devIncusResponse {
+	if r.Method != "POST" {
+		return &devIncusResponse{fmt.Sprintf("method %q not allowed", r.Method), http.StatusBadRequest, "raw"}
+	}
+
+	client, err := getVsockClient(d)
+	if err != nil {
+		return smartResponse(fmt.Errorf("Failed connecting to host over vsock: %w", err))
+	}
+
+	defer client.Disconnect()
+
+	resp, _, err := client.RawQuery("POST", "/1.0/token", nil, "")
+	if err != nil {
+		return smartResponse(err)
+	}
+
+	var token api.DevIncusToken
+
+	err = resp.MetadataAsStruct(&token)
+	if err != nil {
+		return smartResponse(fmt.Errorf("Failed parsing response from host: %w", err))
+	}
+
+	return okResponse(token, "json")
+}}
+
+var DevIncusSnapshotsPost = devIncusHandler{"/1.0/snapshots", func(d *Daemon, w http.ResponseWriter, r *http.Request) *devIncusResponse {
+	if r.Method != "POST" {
+		return &devIncusResponse{fmt.Sprintf("method %q not allowed", r.Method), http.StatusBadRequest, "raw"}
+	}
+
+	client, err := getVsockClient(d)
+	if err != nil {
+		return smartResponse(fmt.Errorf("Failed connecting to host over vsock: %w", err))
+	}
+
+	defer client.Disconnect()
+
+	resp, _, err := client.RawQuery("POST", "/1.0/snapshots", r.Body, "")
+	if err != nil {
+		return smartResponse(err)
+	}
+
+	var snapshot api.DevIncusSnapshot
+
+	err = resp.MetadataAsStruct(&snapshot)
+	if err != nil {
+		return smartResponse(fmt.Errorf("Failed parsing response from host: %w", err))
+	}
+
+	return okResponse(snapshot, "json")
+}}
+
 var handlers = []devIncusHandler{
 	{"/", func(d *Daemon, w http.ResponseWriter, r *http.Request) *devIncusResponse {
 		return okResponse([]string{"/1.0"}, "json")
@@ -227,6 +281,8 @@ var handlers = []devIncusHandler{
 	DevIncusMetadataGet,
 	devIncusEventsGet,
 	DevIncusDevicesGet,
+	DevIncusTokenPost,
+	DevIncusSnapshotsPost,
 }
 
 func hoistReq(f func(*Daemon, http.ResponseWriter, *http.Request) *devIncusResponse, d *Daemon) func(http.ResponseWriter, *http.Request) {
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/response"
+	agentAPI "github.com/lxc/incus/v6/shared/api/agent"
+)
+
+var diskGrowCmd = APIEndpoint{
+	Name: "diskGrow",
+	Path: "disks/grow",
+
+	Post: APIEndpointAction{Handler: diskGrowPost},
+}
+
+func diskGrowPost(d *Daemon, r *http.Request) response.Response {
+	var req agentAPI.DiskGrowPost
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Serial == "" {
+		return response.BadRequest(errors.New("Disk serial is required"))
+	}
+
+	err = osGrowDisk(req.Serial)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"io/fs"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -925,3 +926,191 @@ func osSetEnv(post *api.InstanceExecPost, env map[string]string) {
 		}
 	}
 }
+
+// osClockMaxSlew is the largest clock offset which gets slewed rather than stepped.
+const osClockMaxSlew = 500 * time.Millisecond
+
+func osSetClock(target time.Time, mode string) error {
+	offset := time.Until(target)
+
+	if mode == "slew" && offset.Abs() <= osClockMaxSlew {
+		tx := unix.Timex{Modes: unix.ADJ_OFFSET_SINGLESHOT, Offset: offset.Microseconds()}
+
+		_, err := unix.Adjtimex(&tx)
+		if err != nil {
+			return fmt.Errorf("Failed slewing the clock: %w", err)
+		}
+
+		return nil
+	}
+
+	tv := unix.NsecToTimeval(time.Now().Add(offset).UnixNano())
+
+	err := unix.Settimeofday(&tv)
+	if err != nil {
+		return fmt.Errorf("Failed stepping the clock: %w", err)
+	}
+
+	logger.Info("Stepped the clock", logger.Ctx{"offset": offset})
+
+	return nil
+}
+
+// osDiskBySerial returns the name of the disk with the given serial, as found in /dev/disk/by-id.
+func osDiskBySerial(serial string) (string, error) {
+	entries, err := os.ReadDir("/dev/disk/by-id")
+	if err != nil {
+		return "", fmt.Errorf("Failed listing disks: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.Contains(name, "-part") || !(strings.HasSuffix(name, "-"+serial) || strings.HasSuffix(name, "_"+serial)) {
+			continue
+		}
+
+		devPath, err := filepath.EvalSymlinks(filepath.Join("/dev/disk/by-id", name))
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Base(devPath), nil
+	}
+
+	return "", api.StatusErrorf(http.StatusNotFound, "No disk found with serial %q", serial)
+}
+
+// osLastPartition returns the name and number of the partition of the disk which starts last, if any.
+func osLastPartition(disk string) (string, string, error) {
+	entries, err := os.ReadDir(filepath.Join("/sys/block", disk))
+	if err != nil {
+		return "", "", err
+	}
+
+	var lastName, lastNumber string
+	var lastStart int64 = -1
+
+	for _, entry := range entries {
+		partPath := filepath.Join("/sys/block", disk, entry.Name())
+
+		number, err := os.ReadFile(filepath.Join(partPath, "partition"))
+		if err != nil {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(partPath, "start"))
+		if err != nil {
+			continue
+		}
+
+		start, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+		if err != nil || start < lastStart {
+			continue
+		}
+
+		lastName = entry.Name()
+		lastNumber = strings.TrimSpace(string(number))
+		lastStart = start
+	}
+
+	return lastName, lastNumber, nil
+}
+
+// osBlockMount returns the mount point and filesystem type of the given block device, if mounted.
+func osBlockMount(name string) (string, string, error) {
+	content, err := os.ReadFile(filepath.Join("/sys/class/block", name, "dev"))
+	if err != nil {
+		return "", "", err
+	}
+
+	devID := strings.TrimSpace(string(content))
+
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", "", err
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Fields: ID, parent ID, major:minor, root, mount point, options..., "-", filesystem type, source, options.
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[2] != devID {
+			continue
+		}
+
+		sep := slices.Index(fields, "-")
+		if sep < 0 || sep+1 >= len(fields) {
+			continue
+		}
+
+		return fields[4], fields[sep+1], nil
+	}
+
+	return "", "", scanner.Err()
+}
+
+func osGrowDisk(serial string) error {
+	disk, err := osDiskBySerial(serial)
+	if err != nil {
+		return err
+	}
+
+	// SCSI disks need a rescan to pick up their new size.
+	rescanPath := filepath.Join("/sys/block", disk, "device", "rescan")
+	if util.PathExists(rescanPath) {
+		err = os.WriteFile(rescanPath, []byte("1"), 0o200)
+		if err != nil {
+			return fmt.Errorf("Failed rescanning disk %q: %w", disk, err)
+		}
+	}
+
+	// Grow the last partition of the disk to fill it.
+	target := disk
+	partName, partNumber, err := osLastPartition(disk)
+	if err != nil {
+		return err
+	}
+
+	if partName != "" {
+		_, err = exec.LookPath("growpart")
+		if err != nil {
+			return fmt.Errorf("Growing partitions requires the growpart tool: %w", err)
+		}
+
+		out, err := subprocess.RunCommand("growpart", filepath.Join("/dev", disk), partNumber)
+		if err != nil && !strings.Contains(out, "NOCHANGE") {
+			return fmt.Errorf("Failed growing partition %q: %w", partName, err)
+		}
+
+		target = partName
+	}
+
+	// Grow the filesystem of the partition if mounted.
+	mountPoint, fsType, err := osBlockMount(target)
+	if err != nil {
+		return err
+	}
+
+	switch fsType {
+	case "":
+		return nil
+	case "ext2", "ext3", "ext4":
+		_, err = subprocess.RunCommand("resize2fs", filepath.Join("/dev", target))
+	case "xfs":
+		_, err = subprocess.RunCommand("xfs_growfs", mountPoint)
+	case "btrfs":
+		_, err = subprocess.RunCommand("btrfs", "filesystem", "resize", "max", mountPoint)
+	default:
+		return fmt.Errorf("Growing %q filesystems isn't supported", fsType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed growing filesystem on %q: %w", target, err)
+	}
+
+	logger.Info("Grew disk", logger.Ctx{"disk": disk, "partition": partName, "filesystem": fsType})
+
+	return nil
+}
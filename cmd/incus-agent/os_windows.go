@@ -12,6 +12,7 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 
 	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/registry"
@@ -191,3 +192,11 @@ func osGetListener(port int64) (net.Listener, error) {
 func osSetEnv(post *api.InstanceExecPost, env map[string]string) {
 	env["PATH"] = "C:\\WINDOWS\\system32;C:\\WINDOWS"
 }
+
+func osSetClock(target time.Time, mode string) error {
+	return errors.New("Clock synchronization isn't supported on Windows")
+}
+
+func osGrowDisk(serial string) error {
+	return errors.New("Disk growth isn't supported on Windows")
+}
@@ -54,7 +54,10 @@ func (c *cmdPause) Command() *cobra.Command {
 	cmd.Use = usage("pause", i18n.G("[<remote>:]<instance> [[<remote>:]<instance>...]"))
 	cmd.Short = i18n.G("Pause instances")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Pause instances`))
+		`Pause instances
+
+With --to-disk, the instance state is stored on disk and its memory is released.
+The instance is then reported as suspended and "incus start" restores its state.`))
 	cmd.Aliases = []string{"freeze"}
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -146,6 +149,7 @@ type cmdAction struct {
 	flagStateful  bool
 	flagStateless bool
 	flagTimeout   int
+	flagToDisk    bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -160,6 +164,8 @@ func (c *cmdAction) Command(action string) *cobra.Command {
 		cmd.Flags().BoolVar(&c.flagStateful, "stateful", false, i18n.G("Store the instance state"))
 	case "start":
 		cmd.Flags().BoolVar(&c.flagStateless, "stateless", false, i18n.G("Ignore the instance state"))
+	case "pause":
+		cmd.Flags().BoolVar(&c.flagToDisk, "to-disk", false, i18n.G("Store the instance state on disk and release its memory"))
 	}
 
 	if slices.Contains([]string{"start", "restart", "stop"}, action) {
@@ -190,15 +196,20 @@ func (c *cmdAction) doActionAll(action string, resource remoteResource) error {
 	}
 
 	// Pause is called freeze, resume is called unfreeze.
+	// Pausing to disk is a stateful stop.
 	switch action {
 	case "pause":
 		action = "freeze"
+		if c.flagToDisk {
+			action = "stop"
+		}
+
 	case "resume":
 		action = "unfreeze"
 	}
 
 	// Only store state if asked to.
-	state := action == "stop" && c.flagStateful
+	state := action == "stop" && (c.flagStateful || c.flagToDisk)
 
 	req := api.InstancesPut{
 		State: &api.InstanceStatePut{
@@ -241,9 +252,13 @@ func (c *cmdAction) doActionAll(action string, resource remoteResource) error {
 func (c *cmdAction) doAction(action string, conf *config.Config, nameArg string) error {
 	state := false
 
-	// Pause is called freeze
+	// Pause is called freeze, unless pausing to disk which is a stateful stop
 	if action == "pause" {
 		action = "freeze"
+		if c.flagToDisk {
+			action = "stop"
+			state = true
+		}
 	}
 
 	// Resume is called unfreeze
@@ -408,7 +423,12 @@ func (c *cmdAction) Run(cmd *cobra.Command, args []string) error {
 					}
 
 				case "stop":
-					if ct.StatusCode == api.Stopped {
+					if ct.StatusCode == api.Stopped || ct.StatusCode == api.Suspended {
+						continue
+					}
+
+				case "pause":
+					if c.flagToDisk && (ct.StatusCode == api.Stopped || ct.StatusCode == api.Suspended) {
 						continue
 					}
 				}
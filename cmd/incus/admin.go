@@ -33,6 +33,10 @@ func (c *cmdAdmin) Command() *cobra.Command {
 	adminRecoverCmd := cmdAdminRecover{global: c.global}
 	cmd.AddCommand(adminRecoverCmd.Command())
 
+	// storage sub-command
+	adminStorageCmd := cmdAdminStorage{global: c.global}
+	cmd.AddCommand(adminStorageCmd.Command())
+
 	// shutdown sub-command
 	shutdownCmd := cmdAdminShutdown{global: c.global}
 	cmd.AddCommand(shutdownCmd.Command())
@@ -0,0 +1,195 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	incus "github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/internal/recover"
+)
+
+type cmdAdminStorage struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminStorage) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("storage")
+	cmd.Short = i18n.G("Manage the storage of the local server")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage the storage of the local server`))
+
+	// check sub-command
+	adminStorageCheckCmd := cmdAdminStorageCheck{global: c.global}
+	cmd.AddCommand(adminStorageCheckCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+
+	return cmd
+}
+
+type cmdAdminStorageCheck struct {
+	global *cmdGlobal
+
+	flagAdopt  bool
+	flagDelete bool
+	flagForce  bool
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminStorageCheck) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("check", i18n.G("[<pool>...]"))
+	cmd.Short = i18n.G("Check storage pools for orphaned volumes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Check storage pools for orphaned volumes
+
+  This compares the volumes found on the storage pools of the local server with the database records,
+  and reports the volumes which only exist on one side, usually left behind by interrupted operations.
+  All pools are checked if none is given.
+
+  Orphaned custom volumes which only exist on the storage can be adopted, getting new database records
+  with the default configuration. Orphaned instance volumes need to be recovered with "incus admin recover".
+
+  Orphaned volumes can also be deleted, either from the storage or from the database. The database records
+  of instance volumes are left alone, as those get removed by deleting their instance.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus admin storage check
+    Report the orphaned volumes of all storage pools
+
+incus admin storage check default --adopt
+    Adopt the orphaned custom volumes of pool "default"`))
+
+	cmd.Flags().BoolVar(&c.flagAdopt, "adopt", false, i18n.G("Adopt the orphaned custom volumes"))
+	cmd.Flags().BoolVar(&c.flagDelete, "delete", false, i18n.G("Delete the orphaned volumes"))
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, i18n.G("Don't ask for confirmation before adopting or deleting volumes"))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAdminStorageCheck) Run(_ *cobra.Command, args []string) error {
+	if c.flagAdopt && c.flagDelete {
+		return errors.New(i18n.G("Can't both adopt and delete the orphaned volumes"))
+	}
+
+	d, err := incus.ConnectIncusUnix("", nil)
+	if err != nil {
+		return err
+	}
+
+	req := recover.StorageCheckPost{
+		Pools: args,
+	}
+
+	res, err := c.check(d, req)
+	if err != nil {
+		return err
+	}
+
+	if c.flagAdopt || c.flagDelete {
+		if len(res.Volumes) == 0 {
+			return c.render(res)
+		}
+
+		req.Action = "delete"
+		if c.flagAdopt {
+			req.Action = "adopt"
+		}
+
+		if !c.flagForce {
+			err = c.render(res)
+			if err != nil {
+				return err
+			}
+
+			proceed, err := c.global.asker.AskBool(fmt.Sprintf(i18n.G("Are you sure you want to %s those volumes? (yes/no) [default=no]: "), req.Action), "no")
+			if err != nil {
+				return err
+			}
+
+			if !proceed {
+				return nil
+			}
+		}
+
+		res, err = c.check(d, req)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.render(res)
+}
+
+// check sends a storage check request to the daemon.
+func (c *cmdAdminStorageCheck) check(d incus.InstanceServer, req recover.StorageCheckPost) (*recover.StorageCheckResult, error) {
+	resp, _, err := d.RawQuery("POST", "/internal/storage/check", req, "")
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Failed storage check request: %w"), err)
+	}
+
+	res := recover.StorageCheckResult{}
+
+	err = resp.MetadataAsStruct(&res)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Failed parsing storage check response: %w"), err)
+	}
+
+	return &res, nil
+}
+
+// render shows the orphaned volumes found by the storage check, along with the errors.
+func (c *cmdAdminStorageCheck) render(res *recover.StorageCheckResult) error {
+	for _, checkErr := range res.Errors {
+		fmt.Fprintf(os.Stderr, i18n.G("Error: %s")+"\n", checkErr)
+	}
+
+	data := [][]string{}
+	for _, vol := range res.Volumes {
+		var missing string
+		if vol.Missing == "record" {
+			missing = i18n.G("DATABASE RECORD")
+		} else {
+			missing = i18n.G("STORAGE VOLUME")
+		}
+
+		status := vol.Action
+		if vol.Error != "" {
+			status = vol.Error
+		}
+
+		data = append(data, []string{vol.Pool, vol.Project, vol.Type, vol.Name, vol.ContentType, missing, status})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("POOL"),
+		i18n.G("PROJECT"),
+		i18n.G("TYPE"),
+		i18n.G("NAME"),
+		i18n.G("CONTENT-TYPE"),
+		i18n.G("MISSING"),
+		i18n.G("STATUS"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, res.Volumes)
+}
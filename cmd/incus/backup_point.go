@@ -0,0 +1,366 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type cmdBackupPoint struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBackupPoint) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("backup-point")
+	cmd.Short = i18n.G("Manage instance backups stored in the backup target")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage instance backups stored in the backup target
+
+The backup target is a restic or borg repository configured on the server
+with the backups.target.* server configuration keys.`))
+
+	// Create
+	backupPointCreateCmd := cmdBackupPointCreate{global: c.global, backupPoint: c}
+	cmd.AddCommand(backupPointCreateCmd.Command())
+
+	// Delete
+	backupPointDeleteCmd := cmdBackupPointDelete{global: c.global, backupPoint: c}
+	cmd.AddCommand(backupPointDeleteCmd.Command())
+
+	// List
+	backupPointListCmd := cmdBackupPointList{global: c.global, backupPoint: c}
+	cmd.AddCommand(backupPointListCmd.Command())
+
+	// Restore
+	backupPointRestoreCmd := cmdBackupPointRestore{global: c.global, backupPoint: c}
+	cmd.AddCommand(backupPointRestoreCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdBackupPointCreate struct {
+	global      *cmdGlobal
+	backupPoint *cmdBackupPoint
+
+	flagInstanceOnly bool
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBackupPointCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<instance> [<name>]"))
+	cmd.Short = i18n.G("Back up instances to the backup target")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Back up instances to the backup target
+
+The name of the backup defaults to its creation time.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus backup-point create u1
+    Back up the u1 instance to the backup target.`))
+
+	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false, i18n.G("Whether or not to only backup the instance (without snapshots)"))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdBackupPointCreate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance name"))
+	}
+
+	req := api.InstanceBackupsPost{
+		InstanceOnly: c.flagInstanceOnly,
+		Target:       true,
+	}
+
+	if len(args) > 1 {
+		req.Name = args[1]
+	}
+
+	op, err := resource.server.CreateInstanceBackup(resource.name, req)
+	if err != nil {
+		return err
+	}
+
+	// Watch the background operation
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Backing up instance: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done(i18n.G("Backup stored successfully!"))
+	return nil
+}
+
+// Delete.
+type cmdBackupPointDelete struct {
+	global      *cmdGlobal
+	backupPoint *cmdBackupPoint
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBackupPointDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<id>"))
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Delete backups from the backup target")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete backups from the backup target`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdBackupPointDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing backup point ID"))
+	}
+
+	op, err := resource.server.DeleteBackupPoint(resource.name)
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// List.
+type cmdBackupPointList struct {
+	global      *cmdGlobal
+	backupPoint *cmdBackupPoint
+
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBackupPointList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:][<instance>]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List the backups stored in the backup target")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List the backups stored in the backup target
+
+The backups of all instances of the project are listed, unless an instance is given.
+Backups of instances that no longer exist are listed too.`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdBackupPointList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	points, err := resource.server.GetBackupPoints(resource.name)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Instance != points[j].Instance {
+			return points[i].Instance < points[j].Instance
+		}
+
+		return points[i].CreatedAt.Before(points[j].CreatedAt)
+	})
+
+	data := [][]string{}
+	for _, point := range points {
+		data = append(data, []string{
+			point.Instance,
+			point.Name,
+			point.CreatedAt.Local().Format(dateLayout),
+			point.ID,
+		})
+	}
+
+	header := []string{
+		i18n.G("INSTANCE"),
+		i18n.G("NAME"),
+		i18n.G("CREATED AT"),
+		i18n.G("ID"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, points)
+}
+
+// Restore.
+type cmdBackupPointRestore struct {
+	global      *cmdGlobal
+	backupPoint *cmdBackupPoint
+
+	flagStorage string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBackupPointRestore) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("restore", i18n.G("[<remote>:]<id> [<instance name>]"))
+	cmd.Short = i18n.G("Restore instances from the backup target")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Restore instances from the backup target
+
+A new instance is created from the backup, named after the backed up instance unless a name is given.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus backup-point restore 4bba301e1b62 u1-restored
+    Create the u1-restored instance from the backup with ID 4bba301e1b62.`))
+
+	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", i18n.G("Storage pool name")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdBackupPointRestore) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing backup point ID"))
+	}
+
+	req := api.BackupPointPost{
+		Pool: c.flagStorage,
+	}
+
+	if len(args) > 1 {
+		req.Name = args[1]
+	}
+
+	op, err := resource.server.RestoreBackupPoint(resource.name, req)
+	if err != nil {
+		return err
+	}
+
+	// Watch the background operation
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Restoring instance: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done(i18n.G("Instance restored successfully!"))
+	return nil
+}
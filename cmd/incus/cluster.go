@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 	yaml "gopkg.in/yaml.v2"
 
+	incus "github.com/lxc/incus/v6/client"
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	"github.com/lxc/incus/v6/internal/i18n"
 	"github.com/lxc/incus/v6/shared/api"
@@ -102,6 +103,10 @@ func (c *cmdCluster) Command() *cobra.Command {
 	cmdClusterRestore := cmdClusterRestore{global: c.global, cluster: c}
 	cmd.AddCommand(cmdClusterRestore.Command())
 
+	// Snapshot cluster member instances
+	cmdClusterSnapshotInstances := cmdClusterSnapshotInstances{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterSnapshotInstances.Command())
+
 	clusterGroupCmd := cmdClusterGroup{global: c.global, cluster: c}
 	cmd.AddCommand(clusterGroupCmd.Command())
 
@@ -149,7 +154,12 @@ func (c *cmdClusterList) Command() *cobra.Command {
     f - Failure Domain
     d - Description
     s - Status
-    m - Message`))
+    m - Message
+    L - Labels
+
+	Custom columns are defined with "label:KEY[:NAME]", showing the value
+	of the label KEY of each member, with an optional column NAME
+	(defaults to the label key).`))
 
 	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultClusterColumns, i18n.G("Columns")+"``")
 	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
@@ -184,6 +194,7 @@ func (c *cmdClusterList) parseColumns() ([]clusterColumn, error) {
 		'd': {i18n.G("DESCRIPTION"), c.descriptionColumnData},
 		's': {i18n.G("STATUS"), c.statusColumnData},
 		'm': {i18n.G("MESSAGE"), c.messageColumnData},
+		'L': {i18n.G("LABELS"), c.labelsColumnData},
 	}
 
 	columnList := strings.Split(c.flagColumns, ",")
@@ -195,6 +206,25 @@ func (c *cmdClusterList) parseColumns() ([]clusterColumn, error) {
 			return nil, fmt.Errorf(i18n.G("Empty column entry (redundant, leading or trailing command) in '%s'"), c.flagColumns)
 		}
 
+		// Custom label column.
+		labelEntry, ok := strings.CutPrefix(columnEntry, "label:")
+		if ok {
+			key, name, _ := strings.Cut(labelEntry, ":")
+			if key == "" {
+				return nil, fmt.Errorf(i18n.G("Invalid label column entry '%s'"), columnEntry)
+			}
+
+			if name == "" {
+				name = strings.ToUpper(key)
+			}
+
+			columns = append(columns, clusterColumn{name, func(cluster api.ClusterMember) string {
+				return cluster.Labels[key]
+			}})
+
+			continue
+		}
+
 		for _, columnRune := range columnEntry {
 			column, ok := columnsShorthandMap[columnRune]
 			if !ok {
@@ -246,6 +276,22 @@ func (c *cmdClusterList) messageColumnData(cluster api.ClusterMember) string {
 	return cluster.Message
 }
 
+func (c *cmdClusterList) labelsColumnData(cluster api.ClusterMember) string {
+	labels := make([]string, 0, len(cluster.Labels))
+	for k, v := range cluster.Labels {
+		labels = append(labels, k+"="+v)
+	}
+
+	sort.Strings(labels)
+
+	labelsDelimiter := "\n"
+	if c.flagFormat == "csv" {
+		labelsDelimiter = ","
+	}
+
+	return strings.Join(labels, labelsDelimiter)
+}
+
 // Run runs the actual command logic.
 func (c *cmdClusterList) Run(cmd *cobra.Command, args []string) error {
 	// Quick checks.
@@ -438,6 +484,7 @@ type cmdClusterGet struct {
 	cluster *cmdCluster
 
 	flagIsProperty bool
+	flagIsLabel    bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -448,6 +495,7 @@ func (c *cmdClusterGet) Command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), cmd.Short)
 
 	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Get the key as a cluster property"))
+	cmd.Flags().BoolVarP(&c.flagIsLabel, "label", "l", false, i18n.G("Get the key as a cluster member label"))
 	cmd.RunE = c.Run
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -498,6 +546,16 @@ func (c *cmdClusterGet) Run(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if c.flagIsLabel {
+		value, ok := member.Labels[args[1]]
+		if !ok {
+			return fmt.Errorf(i18n.G("The label %q does not exist on cluster member %q"), args[1], resource.name)
+		}
+
+		fmt.Printf("%s\n", value)
+		return nil
+	}
+
 	value, ok := member.Config[args[1]]
 	if !ok {
 		return fmt.Errorf(i18n.G("The key %q does not exist on cluster member %q"), args[1], resource.name)
@@ -513,6 +571,7 @@ type cmdClusterSet struct {
 	cluster *cmdCluster
 
 	flagIsProperty bool
+	flagIsLabel    bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -523,6 +582,7 @@ func (c *cmdClusterSet) Command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), cmd.Short)
 
 	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Set the key as a cluster property"))
+	cmd.Flags().BoolVarP(&c.flagIsLabel, "label", "l", false, i18n.G("Set the key as a cluster member label"))
 	cmd.RunE = c.Run
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -579,6 +639,18 @@ func (c *cmdClusterSet) Run(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf(i18n.G("Error setting properties: %v"), err)
 			}
 		}
+	} else if c.flagIsLabel {
+		if writable.Labels == nil {
+			writable.Labels = map[string]string{}
+		}
+
+		for k, v := range keys {
+			if v == "" {
+				delete(writable.Labels, k)
+			} else {
+				writable.Labels[k] = v
+			}
+		}
 	} else {
 		maps.Copy(writable.Config, keys)
 	}
@@ -593,6 +665,7 @@ type cmdClusterUnset struct {
 	clusterSet *cmdClusterSet
 
 	flagIsProperty bool
+	flagIsLabel    bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -603,6 +676,7 @@ func (c *cmdClusterUnset) Command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), cmd.Short)
 
 	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Unset the key as a cluster property"))
+	cmd.Flags().BoolVarP(&c.flagIsLabel, "label", "l", false, i18n.G("Unset the key as a cluster member label"))
 	cmd.RunE = c.Run
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -629,6 +703,7 @@ func (c *cmdClusterUnset) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	c.clusterSet.flagIsProperty = c.flagIsProperty
+	c.clusterSet.flagIsLabel = c.flagIsLabel
 
 	args = append(args, "")
 	return c.clusterSet.Run(cmd, args)
@@ -1460,8 +1535,9 @@ func (c *cmdClusterUpdateCertificate) Run(cmd *cobra.Command, args []string) err
 type cmdClusterEvacuateAction struct {
 	global *cmdGlobal
 
-	flagAction string
-	flagForce  bool
+	flagAction   string
+	flagForce    bool
+	flagSnapshot string
 }
 
 // Cluster member evacuation.
@@ -1483,6 +1559,7 @@ func (c *cmdClusterEvacuate) Command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Evacuate cluster member`))
 
 	cmd.Flags().StringVar(&c.action.flagAction, "action", "", i18n.G(`Force a particular evacuation action`)+"``")
+	cmd.Flags().StringVar(&c.action.flagSnapshot, "snapshot", "", i18n.G(`Snapshot all instances with this name before evacuating them`)+"``")
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -1512,6 +1589,8 @@ func (c *cmdClusterRestore) Command() *cobra.Command {
 	cmd.Short = i18n.G("Restore cluster member")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Restore cluster member`))
 
+	cmd.Flags().StringVar(&c.action.flagSnapshot, "snapshot", "", i18n.G(`Delete the snapshot with this name from all instances once restored`)+"``")
+
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			return c.global.cmpClusterMembers(toComplete)
@@ -1564,8 +1643,9 @@ func (c *cmdClusterEvacuateAction) Run(cmd *cobra.Command, args []string) error
 	}
 
 	state := api.ClusterMemberStatePost{
-		Action: cmd.Name(),
-		Mode:   c.flagAction,
+		Action:   cmd.Name(),
+		Mode:     c.flagAction,
+		Snapshot: c.flagSnapshot,
 	}
 
 	op, err := resource.server.UpdateClusterMemberState(resource.name, state)
@@ -1601,3 +1681,119 @@ func (c *cmdClusterEvacuateAction) Run(cmd *cobra.Command, args []string) error
 	progress.Done("")
 	return nil
 }
+
+// Snapshot cluster member instances.
+type cmdClusterSnapshotInstances struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+
+	flagDelete bool
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdClusterSnapshotInstances) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("snapshot-instances", i18n.G("[<remote>:]<member> [<snapshot name>]"))
+	cmd.Short = i18n.G("Snapshot all instances of a cluster member")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Snapshot all instances of a cluster member
+
+All instances located on the member get a snapshot with the same name in a single operation,
+for example before a maintenance of the host. If no name is given, one is generated.
+
+The --delete flag deletes the snapshot with that name from all instances of the member instead.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus cluster snapshot-instances server1 before-upgrade
+    Snapshot all instances of server1 as "before-upgrade".
+
+incus cluster snapshot-instances server1 before-upgrade --delete
+    Delete the "before-upgrade" snapshot from all instances of server1.`))
+
+	cmd.Flags().BoolVar(&c.flagDelete, "delete", false, i18n.G("Delete the snapshots instead of creating them"))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpClusterMembers(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdClusterSnapshotInstances) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing cluster member name"))
+	}
+
+	snapshotName := ""
+	if len(args) > 1 {
+		snapshotName = args[1]
+	}
+
+	var op incus.Operation
+	var format string
+
+	if c.flagDelete {
+		if snapshotName == "" {
+			return errors.New(i18n.G("Missing snapshot name"))
+		}
+
+		op, err = resource.server.DeleteClusterMemberSnapshots(resource.name, snapshotName)
+		if err != nil {
+			return err
+		}
+
+		format = i18n.G("Deleting snapshots: %s")
+	} else {
+		op, err = resource.server.CreateClusterMemberSnapshots(resource.name, api.ClusterMemberSnapshotsPost{Name: snapshotName})
+		if err != nil {
+			return err
+		}
+
+		format = i18n.G("Snapshotting instances: %s")
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: format,
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	if !c.flagDelete && !c.global.flagQuiet {
+		snapshotName, _ = op.Get().Metadata["snapshot"].(string)
+		fmt.Printf(i18n.G("Instances of cluster member %s snapshotted as %s")+"\n", resource.name, snapshotName)
+	}
+
+	return nil
+}
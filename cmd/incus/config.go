@@ -26,7 +26,7 @@ type cmdConfig struct {
 }
 
 // Command creates a Cobra command for managing instance and server configurations,
-// including options for device, edit, get, metadata, profile, set, show, template, trust, and unset.
+// including options for access-link, device, edit, get, metadata, profile, set, show, template, trust, and unset.
 func (c *cmdConfig) Command() *cobra.Command {
 	cmd := &cobra.Command{}
 	cmd.Use = usage("config")
@@ -34,6 +34,10 @@ func (c *cmdConfig) Command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Manage instance and server configuration options`))
 
+	// Access link
+	configAccessLinkCmd := cmdConfigAccessLink{global: c.global, config: c}
+	cmd.AddCommand(configAccessLinkCmd.Command())
+
 	// Device
 	configDeviceCmd := cmdConfigDevice{global: c.global, config: c}
 	cmd.AddCommand(configDeviceCmd.Command())
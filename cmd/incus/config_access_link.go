@@ -0,0 +1,328 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type cmdConfigAccessLink struct {
+	global *cmdGlobal
+	config *cmdConfig
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigAccessLink) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("access-link")
+	cmd.Short = i18n.G("Manage instance access links")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage instance access links
+
+Access links give time-limited access to the console and/or exec of a single instance,
+without otherwise trusting whoever they are handed to.`))
+
+	// Create
+	configAccessLinkCreateCmd := cmdConfigAccessLinkCreate{global: c.global, config: c.config, configAccessLink: c}
+	cmd.AddCommand(configAccessLinkCreateCmd.Command())
+
+	// List
+	configAccessLinkListCmd := cmdConfigAccessLinkList{global: c.global, config: c.config, configAccessLink: c}
+	cmd.AddCommand(configAccessLinkListCmd.Command())
+
+	// Revoke
+	configAccessLinkRevokeCmd := cmdConfigAccessLinkRevoke{global: c.global, config: c.config, configAccessLink: c}
+	cmd.AddCommand(configAccessLinkRevokeCmd.Command())
+
+	// Show
+	configAccessLinkShowCmd := cmdConfigAccessLinkShow{global: c.global, config: c.config, configAccessLink: c}
+	cmd.AddCommand(configAccessLinkShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// complete returns the completion of the instance name as the first argument.
+func (c *cmdConfigAccessLink) complete(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return c.global.cmpInstances(toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// Create.
+type cmdConfigAccessLinkCreate struct {
+	global           *cmdGlobal
+	config           *cmdConfig
+	configAccessLink *cmdConfigAccessLink
+
+	flagAccess      []string
+	flagDescription string
+	flagExpiry      string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigAccessLinkCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<instance>"))
+	cmd.Aliases = []string{"add"}
+	cmd.Short = i18n.G("Create instance access links")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create instance access links
+
+The secret of the access link is only shown once, and is used as a bearer token
+with the "access-link" authentication method.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus config access-link create v1 --access=console,exec --expiry=2H --description="Boot issue"
+    Create an access link to the console and exec of instance v1, valid for two hours`))
+
+	cmd.Flags().StringSliceVar(&c.flagAccess, "access", []string{api.InstanceAccessLinkAccessConsole}, i18n.G("What the access link allows (console and/or exec)")+"``")
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Description of the access link")+"``")
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "", i18n.G("How long the access link is valid for (e.g. 30M or 1d), defaults to one hour")+"``")
+
+	cmd.RunE = c.Run
+	cmd.ValidArgsFunction = c.configAccessLink.complete
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdConfigAccessLinkCreate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance name"))
+	}
+
+	req := api.InstanceAccessLinksPost{
+		Description: c.flagDescription,
+		Access:      c.flagAccess,
+	}
+
+	req.ExpiresAt, err = internalInstance.GetExpiry(time.Now(), c.flagExpiry)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Invalid expiry: %w"), err)
+	}
+
+	token, err := resource.server.CreateInstanceAccessLink(resource.name, req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(i18n.G("Access link %s created for instance %s")+"\n", token.ID, resource.name)
+	fmt.Printf(i18n.G("URL: %s")+"\n", token.URL)
+	fmt.Printf(i18n.G("Fingerprint: %s")+"\n", token.Fingerprint)
+	fmt.Printf(i18n.G("Secret: %s")+"\n", token.Secret)
+	fmt.Printf(i18n.G("Expires at: %s")+"\n", token.ExpiresAt.Local().Format(dateLayout))
+
+	return nil
+}
+
+// List.
+type cmdConfigAccessLinkList struct {
+	global           *cmdGlobal
+	config           *cmdConfig
+	configAccessLink *cmdConfigAccessLink
+
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigAccessLinkList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]<instance>"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List instance access links")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List instance access links`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+	cmd.ValidArgsFunction = c.configAccessLink.complete
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdConfigAccessLinkList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance name"))
+	}
+
+	links, err := resource.server.GetInstanceAccessLinks(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, link := range links {
+		lastUsed := ""
+		if !link.LastUsedAt.IsZero() {
+			lastUsed = link.LastUsedAt.Local().Format(dateLayout)
+		}
+
+		data = append(data, []string{
+			link.ID,
+			link.Description,
+			strings.Join(link.Access, ","),
+			link.CreatedBy,
+			link.ExpiresAt.Local().Format(dateLayout),
+			lastUsed,
+		})
+	}
+
+	header := []string{
+		i18n.G("ID"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("ACCESS"),
+		i18n.G("CREATED BY"),
+		i18n.G("EXPIRES AT"),
+		i18n.G("LAST USED AT"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, links)
+}
+
+// Revoke.
+type cmdConfigAccessLinkRevoke struct {
+	global           *cmdGlobal
+	config           *cmdConfig
+	configAccessLink *cmdConfigAccessLink
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigAccessLinkRevoke) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("revoke", i18n.G("[<remote>:]<instance> <id>"))
+	cmd.Aliases = []string{"delete", "rm", "remove"}
+	cmd.Short = i18n.G("Revoke instance access links")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Revoke instance access links`))
+
+	cmd.RunE = c.Run
+	cmd.ValidArgsFunction = c.configAccessLink.complete
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdConfigAccessLinkRevoke) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance name"))
+	}
+
+	return resource.server.DeleteInstanceAccessLink(resource.name, args[1])
+}
+
+// Show.
+type cmdConfigAccessLinkShow struct {
+	global           *cmdGlobal
+	config           *cmdConfig
+	configAccessLink *cmdConfigAccessLink
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigAccessLinkShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<instance> <id>"))
+	cmd.Short = i18n.G("Show instance access links")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show instance access links`))
+
+	cmd.RunE = c.Run
+	cmd.ValidArgsFunction = c.configAccessLink.complete
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdConfigAccessLinkShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance name"))
+	}
+
+	link, err := resource.server.GetInstanceAccessLink(resource.name, args[1])
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&link)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
 )
 
 type cmdConfigDevice struct {
@@ -31,6 +32,12 @@ func (c *cmdConfigDevice) Command() *cobra.Command {
 	configDeviceAddCmd := cmdConfigDeviceAdd{global: c.global, config: c.config, profile: c.profile, configDevice: c}
 	cmd.AddCommand(configDeviceAddCmd.Command())
 
+	// Eject
+	if c.config != nil {
+		configDeviceEjectCmd := cmdConfigDeviceEject{global: c.global, config: c.config, configDevice: c}
+		cmd.AddCommand(configDeviceEjectCmd.Command())
+	}
+
 	// Get
 	configDeviceGetCmd := cmdConfigDeviceGet{global: c.global, config: c.config, profile: c.profile, configDevice: c}
 	cmd.AddCommand(configDeviceGetCmd.Command())
@@ -206,6 +213,82 @@ func (c *cmdConfigDeviceAdd) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Eject.
+type cmdConfigDeviceEject struct {
+	global       *cmdGlobal
+	config       *cmdConfig
+	configDevice *cmdConfigDevice
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigDeviceEject) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("eject", i18n.G("[<remote>:]<instance> <device>"))
+	cmd.Short = i18n.G("Eject or insert the media of a removable device")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Eject or insert the media of a removable device
+
+Toggles the media of a cdrom drive of a running virtual machine.
+Ejected media stays out across reboots of the guest and is inserted again once the instance is stopped.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus config device eject v1 iso
+    Ejects the ISO image of the "iso" device, or inserts it back if already ejected.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpInstanceDeviceNames(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdConfigDeviceEject) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing name"))
+	}
+
+	devname := args[1]
+
+	media, err := resource.server.UpdateInstanceDeviceMedia(resource.name, devname, api.InstanceDeviceMediaPost{Action: "toggle"})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		if media.Inserted {
+			fmt.Printf(i18n.G("Media of device %s inserted in %s")+"\n", devname, resource.name)
+		} else {
+			fmt.Printf(i18n.G("Media of device %s ejected from %s")+"\n", devname, resource.name)
+		}
+	}
+
+	return nil
+}
+
 // Get.
 type cmdConfigDeviceGet struct {
 	global       *cmdGlobal
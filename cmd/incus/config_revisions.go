@@ -0,0 +1,363 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	incus "github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// configRevisionsEntity describes an entity whose configuration revisions can be listed, shown and rolled back.
+type configRevisionsEntity struct {
+	// Name of the entity argument in the command usage, like "<profile>".
+	arg string
+
+	// Error returned when the entity name is missing.
+	missingName string
+
+	// Completion of the entity names.
+	complete func(toComplete string) ([]string, cobra.ShellCompDirective)
+
+	// Functions getting the revisions of the entity and re-applying a recorded configuration.
+	revisions func(server incus.InstanceServer, name string) ([]api.ConfigRevision, error)
+	revision  func(server incus.InstanceServer, name string, revision int64) (*api.ConfigRevision, error)
+	update    func(server incus.InstanceServer, name string, document []byte) error
+}
+
+// profileConfigRevisionsEntity returns the configuration revisions entity of profiles.
+func profileConfigRevisionsEntity(global *cmdGlobal) configRevisionsEntity {
+	return configRevisionsEntity{
+		arg:         i18n.G("<profile>"),
+		missingName: i18n.G("Missing profile name"),
+		complete: func(toComplete string) ([]string, cobra.ShellCompDirective) {
+			return global.cmpProfiles(toComplete, true)
+		},
+		revisions: func(server incus.InstanceServer, name string) ([]api.ConfigRevision, error) {
+			return server.GetProfileRevisions(name)
+		},
+		revision: func(server incus.InstanceServer, name string, revision int64) (*api.ConfigRevision, error) {
+			return server.GetProfileRevision(name, revision)
+		},
+		update: func(server incus.InstanceServer, name string, document []byte) error {
+			config := api.ProfilePut{}
+			err := json.Unmarshal(document, &config)
+			if err != nil {
+				return err
+			}
+
+			return server.UpdateProfile(name, config, "")
+		},
+	}
+}
+
+// projectConfigRevisionsEntity returns the configuration revisions entity of projects.
+func projectConfigRevisionsEntity(global *cmdGlobal) configRevisionsEntity {
+	return configRevisionsEntity{
+		arg:         i18n.G("<project>"),
+		missingName: i18n.G("Missing project name"),
+		complete:    global.cmpProjects,
+		revisions: func(server incus.InstanceServer, name string) ([]api.ConfigRevision, error) {
+			return server.GetProjectRevisions(name)
+		},
+		revision: func(server incus.InstanceServer, name string, revision int64) (*api.ConfigRevision, error) {
+			return server.GetProjectRevision(name, revision)
+		},
+		update: func(server incus.InstanceServer, name string, document []byte) error {
+			config := api.ProjectPut{}
+			err := json.Unmarshal(document, &config)
+			if err != nil {
+				return err
+			}
+
+			return server.UpdateProject(name, config, "")
+		},
+	}
+}
+
+// networkConfigRevisionsEntity returns the configuration revisions entity of networks.
+func networkConfigRevisionsEntity(global *cmdGlobal) configRevisionsEntity {
+	return configRevisionsEntity{
+		arg:         i18n.G("<network>"),
+		missingName: i18n.G("Missing network name"),
+		complete:    global.cmpNetworks,
+		revisions: func(server incus.InstanceServer, name string) ([]api.ConfigRevision, error) {
+			return server.GetNetworkRevisions(name)
+		},
+		revision: func(server incus.InstanceServer, name string, revision int64) (*api.ConfigRevision, error) {
+			return server.GetNetworkRevision(name, revision)
+		},
+		update: func(server incus.InstanceServer, name string, document []byte) error {
+			config := api.NetworkPut{}
+			err := json.Unmarshal(document, &config)
+			if err != nil {
+				return err
+			}
+
+			return server.UpdateNetwork(name, config, "")
+		},
+	}
+}
+
+// networkACLConfigRevisionsEntity returns the configuration revisions entity of network ACLs.
+func networkACLConfigRevisionsEntity(global *cmdGlobal) configRevisionsEntity {
+	return configRevisionsEntity{
+		arg:         i18n.G("<ACL>"),
+		missingName: i18n.G("Missing network ACL name"),
+		complete:    global.cmpNetworkACLs,
+		revisions: func(server incus.InstanceServer, name string) ([]api.ConfigRevision, error) {
+			return server.GetNetworkACLRevisions(name)
+		},
+		revision: func(server incus.InstanceServer, name string, revision int64) (*api.ConfigRevision, error) {
+			return server.GetNetworkACLRevision(name, revision)
+		},
+		update: func(server incus.InstanceServer, name string, document []byte) error {
+			config := api.NetworkACLPut{}
+			err := json.Unmarshal(document, &config)
+			if err != nil {
+				return err
+			}
+
+			return server.UpdateNetworkACL(name, config, "")
+		},
+	}
+}
+
+type cmdConfigRevisions struct {
+	global *cmdGlobal
+	entity configRevisionsEntity
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigRevisions) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("revisions")
+	cmd.Short = i18n.G("Manage configuration revisions")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage configuration revisions
+
+Each change of the configuration records a revision holding the previous configuration.
+Rolling back to a revision restores the configuration it recorded.`))
+
+	// List
+	configRevisionsListCmd := cmdConfigRevisionsList{global: c.global, revisions: c}
+	cmd.AddCommand(configRevisionsListCmd.Command())
+
+	// Rollback
+	configRevisionsRollbackCmd := cmdConfigRevisionsRollback{global: c.global, revisions: c}
+	cmd.AddCommand(configRevisionsRollbackCmd.Command())
+
+	// Show
+	configRevisionsShowCmd := cmdConfigRevisionsShow{global: c.global, revisions: c}
+	cmd.AddCommand(configRevisionsShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// parse returns the server and entity name of the arguments, along with the revision number if requested.
+func (c *cmdConfigRevisions) parse(args []string, withRevision bool) (incus.InstanceServer, string, int64, error) {
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return nil, "", -1, err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return nil, "", -1, errors.New(c.entity.missingName)
+	}
+
+	if !withRevision {
+		return resource.server, resource.name, -1, nil
+	}
+
+	revision, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return nil, "", -1, fmt.Errorf(i18n.G("Invalid revision %q"), args[1])
+	}
+
+	return resource.server, resource.name, revision, nil
+}
+
+// complete returns the completion of the entity name as the first argument.
+func (c *cmdConfigRevisions) complete(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return c.entity.complete(toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// List.
+type cmdConfigRevisionsList struct {
+	global    *cmdGlobal
+	revisions *cmdConfigRevisions
+
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigRevisionsList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]")+c.revisions.entity.arg)
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List configuration revisions")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List configuration revisions`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+	cmd.ValidArgsFunction = c.revisions.complete
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdConfigRevisionsList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	server, name, _, err := c.revisions.parse(args, false)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := c.revisions.entity.revisions(server, name)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, revision := range revisions {
+		data = append(data, []string{
+			strconv.FormatInt(revision.Revision, 10),
+			revision.CreatedAt.Local().Format(dateLayout),
+			revision.Requestor,
+			strings.Join(revision.Changes, "\n"),
+		})
+	}
+
+	header := []string{
+		i18n.G("REVISION"),
+		i18n.G("DATE"),
+		i18n.G("REQUESTOR"),
+		i18n.G("CHANGES"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, revisions)
+}
+
+// Rollback.
+type cmdConfigRevisionsRollback struct {
+	global    *cmdGlobal
+	revisions *cmdConfigRevisions
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigRevisionsRollback) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rollback", i18n.G("[<remote>:]")+c.revisions.entity.arg+" "+i18n.G("<revision>"))
+	cmd.Short = i18n.G("Restore the configuration recorded in a revision")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Restore the configuration recorded in a revision
+
+The configuration which is replaced is itself recorded as a new revision, so that the rollback can be reverted.`))
+
+	cmd.RunE = c.Run
+	cmd.ValidArgsFunction = c.revisions.complete
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdConfigRevisionsRollback) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	server, name, revisionNumber, err := c.revisions.parse(args, true)
+	if err != nil {
+		return err
+	}
+
+	revision, err := c.revisions.entity.revision(server, name, revisionNumber)
+	if err != nil {
+		return err
+	}
+
+	document, err := json.Marshal(revision.Document)
+	if err != nil {
+		return err
+	}
+
+	return c.revisions.entity.update(server, name, document)
+}
+
+// Show.
+type cmdConfigRevisionsShow struct {
+	global    *cmdGlobal
+	revisions *cmdConfigRevisions
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdConfigRevisionsShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]")+c.revisions.entity.arg+" "+i18n.G("<revision>"))
+	cmd.Short = i18n.G("Show configuration revisions")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show configuration revisions`))
+
+	cmd.RunE = c.Run
+	cmd.ValidArgsFunction = c.revisions.complete
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdConfigRevisionsShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	server, name, revisionNumber, err := c.revisions.parse(args, true)
+	if err != nil {
+		return err
+	}
+
+	revision, err := c.revisions.entity.revision(server, name, revisionNumber)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&revision)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -30,6 +30,7 @@ type cmdConsole struct {
 	flagForce   bool
 	flagShowLog bool
 	flagType    string
+	flagSerial  string
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -47,6 +48,7 @@ as well as retrieve past log entries from it.`))
 	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("Forces a connection to the console, even if there is already an active session"))
 	cmd.Flags().BoolVar(&c.flagShowLog, "show-log", false, i18n.G("Retrieve the instance's console log"))
 	cmd.Flags().StringVarP(&c.flagType, "type", "t", "console", i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output")+"``")
+	cmd.Flags().StringVar(&c.flagSerial, "serial", "", i18n.G("Name of an additional serial console to use instead of the main one (virtual machines only)")+"``")
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return c.global.cmpInstances(toComplete)
@@ -118,6 +120,10 @@ func (c *cmdConsole) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("Unknown output type %q"), c.flagType)
 	}
 
+	if c.flagSerial != "" && c.flagType != "console" {
+		return errors.New(i18n.G("The --serial flag is only supported by the 'console' output type"))
+	}
+
 	// Connect to the daemon.
 	remote, name, err := conf.ParseRemote(args[0])
 	if err != nil {
@@ -139,7 +145,7 @@ func (c *cmdConsole) console(d incus.InstanceServer, name string) error {
 			return errors.New(i18n.G("The --show-log flag is only supported for by 'console' output type"))
 		}
 
-		console := &incus.InstanceConsoleLogArgs{}
+		console := &incus.InstanceConsoleLogArgs{Name: c.flagSerial}
 		log, err := d.GetInstanceConsoleLog(name, console)
 		if err != nil {
 			return err
@@ -194,6 +200,7 @@ func (c *cmdConsole) text(d incus.InstanceServer, name string) error {
 		Height: height,
 		Type:   "console",
 		Force:  c.flagForce,
+		Name:   c.flagSerial,
 	}
 
 	consoleDisconnect := make(chan bool)
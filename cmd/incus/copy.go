@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,12 +16,16 @@ import (
 	"github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/shared/api"
 	config "github.com/lxc/incus/v6/shared/cliconfig"
+	"github.com/lxc/incus/v6/shared/termios"
+	"github.com/lxc/incus/v6/shared/validate"
 )
 
 type cmdCopy struct {
 	global *cmdGlobal
 
 	flagNoProfiles          bool
+	flagCopyProfiles        bool
+	flagRelay               string
 	flagProfile             []string
 	flagConfig              []string
 	flagDevice              []string
@@ -30,9 +36,14 @@ type cmdCopy struct {
 	flagStorage             string
 	flagTarget              string
 	flagTargetProject       string
+	flagTargetPool          []string
+	flagTargetNetwork       []string
+	flagMapping             string
 	flagRefresh             bool
 	flagRefreshExcludeOlder bool
 	flagAllowInconsistent   bool
+	flagVerify              bool
+	flagInstant             bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -48,8 +59,13 @@ Transfer modes (--mode):
  - pull: Target server pulls the data from the source server (source must listen on network)
  - push: Source server pushes the data to the target server (target must listen on network)
  - relay: The CLI connects to both source and server and proxies the data (both source and target must listen on network)
+ - relay with --relay: Another server connects to both source and target and proxies the data (both source and target must be reachable from it)
 
 The pull transfer mode is the default as it is compatible with all server versions.
+
+With --instant, the new instance is a copy-on-write clone of the source on the same storage pool,
+so no data is copied. This requires a storage pool that supports instant cloning (btrfs, zfs, ceph
+or lvm with a thin pool).
 `))
 
 	cmd.RunE = c.Run
@@ -58,15 +74,22 @@ The pull transfer mode is the default as it is compatible with all server versio
 	cmd.Flags().StringArrayVarP(&c.flagProfile, "profile", "p", nil, i18n.G("Profile to apply to the new instance")+"``")
 	cmd.Flags().BoolVarP(&c.flagEphemeral, "ephemeral", "e", false, i18n.G("Ephemeral instance"))
 	cmd.Flags().StringVar(&c.flagMode, "mode", "pull", i18n.G("Transfer mode. One of pull, push or relay")+"``")
+	cmd.Flags().StringVar(&c.flagRelay, "relay", "", i18n.G("Remote server relaying the transfer instead of the client (implies --mode=relay)")+"``")
 	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false, i18n.G("Copy the instance without its snapshots"))
 	cmd.Flags().BoolVar(&c.flagStateless, "stateless", false, i18n.G("Copy a stateful instance stateless"))
 	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", i18n.G("Storage pool name")+"``")
 	cmd.Flags().StringVar(&c.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.Flags().StringVar(&c.flagTargetProject, "target-project", "", i18n.G("Copy to a project different from the source")+"``")
+	cmd.Flags().StringArrayVar(&c.flagTargetPool, "target-pool", nil, i18n.G("Storage pool to use on the target in place of a source pool (<source>=<target>)")+"``")
+	cmd.Flags().StringArrayVar(&c.flagTargetNetwork, "target-network", nil, i18n.G("Network to use on the target in place of a source network (<source>=<target>)")+"``")
+	cmd.Flags().StringVar(&c.flagMapping, "mapping", "", i18n.G("YAML file mapping source pools, networks and profiles to those of the target")+"``")
 	cmd.Flags().BoolVar(&c.flagNoProfiles, "no-profiles", false, i18n.G("Create the instance with no profiles applied"))
+	cmd.Flags().BoolVar(&c.flagCopyProfiles, "copy-profiles", false, i18n.G("Copy the profiles missing on the target from the source"))
 	cmd.Flags().BoolVar(&c.flagRefresh, "refresh", false, i18n.G("Perform an incremental copy"))
 	cmd.Flags().BoolVar(&c.flagRefreshExcludeOlder, "refresh-exclude-older", false, i18n.G("During incremental copy, exclude source snapshots earlier than latest target snapshot"))
 	cmd.Flags().BoolVar(&c.flagAllowInconsistent, "allow-inconsistent", false, i18n.G("Ignore copy errors for volatile files"))
+	cmd.Flags().BoolVar(&c.flagVerify, "verify", false, i18n.G("Compare the checksums of the volumes once transferred"))
+	cmd.Flags().BoolVar(&c.flagInstant, "instant", false, i18n.G("Create an instant copy-on-write clone on the same storage pool (implies --instance-only)"))
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -163,6 +186,31 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 		return err
 	}
 
+	mapping, err := parseResourceMapping(c.flagMapping, c.flagTargetPool, c.flagTargetNetwork)
+	if err != nil {
+		return err
+	}
+
+	// Connect to the server relaying the transfer.
+	var relay incus.InstanceServer
+	if c.flagRelay != "" {
+		if mode == "push" {
+			return errors.New(i18n.G("--relay can't be used with --mode=push"))
+		}
+
+		mode = "relay"
+
+		relayRemote, _, err := conf.ParseRemote(c.flagRelay)
+		if err != nil {
+			return err
+		}
+
+		relay, err = conf.GetInstanceServer(relayRemote)
+		if err != nil {
+			return err
+		}
+	}
+
 	var op incus.RemoteOperation
 	var writable api.InstancePut
 	var start bool
@@ -172,11 +220,20 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 			return errors.New(i18n.G("--instance-only can't be passed when the source is a snapshot"))
 		}
 
+		if c.flagVerify {
+			return errors.New(i18n.G("--verify can't be passed when the source is a snapshot"))
+		}
+
+		if c.flagInstant {
+			return errors.New(i18n.G("--instant can't be passed when the source is a snapshot"))
+		}
+
 		// Prepare the instance creation request
 		args := incus.InstanceSnapshotCopyArgs{
-			Name: destName,
-			Mode: mode,
-			Live: stateful,
+			Name:  destName,
+			Mode:  mode,
+			Live:  stateful,
+			Relay: relay,
 		}
 
 		if c.flagRefresh {
@@ -190,6 +247,13 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 			return err
 		}
 
+		// Translate the pools, networks and profiles to those of the target.
+		if entry.Devices == nil {
+			entry.Devices = map[string]map[string]string{}
+		}
+
+		entry.Profiles = mapping.apply(entry.Devices, entry.ExpandedDevices, entry.Profiles)
+
 		// Overwrite profiles.
 		if c.flagProfile != nil {
 			entry.Profiles = c.flagProfile
@@ -249,6 +313,14 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 			}
 		}
 
+		// Make sure that the profiles, storage pools and networks used by the instance exist on the target.
+		if sourceRemote != destRemote || c.flagTargetProject != "" {
+			err = c.prepareTargetDependencies(source, dest, mapping, entry.Profiles, entry.Devices)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Do the actual copy
 		if c.flagTarget != "" {
 			dest = dest.UseTarget(c.flagTarget)
@@ -268,6 +340,9 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 			Refresh:             c.flagRefresh,
 			RefreshExcludeOlder: c.flagRefreshExcludeOlder,
 			AllowInconsistent:   c.flagAllowInconsistent,
+			Relay:               relay,
+			Verify:              c.flagVerify,
+			Instant:             c.flagInstant,
 		}
 
 		// Copy of an instance into a new instance
@@ -282,6 +357,13 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 			start = true
 		}
 
+		// Translate the pools, networks and profiles to those of the target.
+		if entry.Devices == nil {
+			entry.Devices = map[string]map[string]string{}
+		}
+
+		entry.Profiles = mapping.apply(entry.Devices, entry.ExpandedDevices, entry.Profiles)
+
 		// Overwrite profiles.
 		if c.flagProfile != nil {
 			entry.Profiles = c.flagProfile
@@ -341,6 +423,14 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 			delete(entry.Config, "volatile.last_state.power")
 		}
 
+		// Make sure that the profiles, storage pools and networks used by the instance exist on the target.
+		if sourceRemote != destRemote || c.flagTargetProject != "" {
+			err = c.prepareTargetDependencies(source, dest, mapping, entry.Profiles, entry.Devices)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Do the actual copy
 		if c.flagTarget != "" {
 			dest = dest.UseTarget(c.flagTarget)
@@ -360,7 +450,7 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 		Quiet:  c.global.flagQuiet,
 	}
 
-	_, err = op.AddHandler(progress.UpdateOp)
+	_, err = op.AddHandler(migrationProgressHandler(&progress))
 	if err != nil {
 		progress.Done("")
 		return err
@@ -440,6 +530,140 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 	return nil
 }
 
+// prepareTargetDependencies checks that the profiles, storage pools and networks used by the instance exist on the
+// target before starting the transfer.
+// Missing profiles are copied from the source when requested, either through --copy-profiles or interactively.
+// Missing storage pools and networks can be remapped interactively, in which case the devices using them get a local
+// override. Otherwise the missing dependencies are reported along with the flags to resolve them.
+func (c *cmdCopy) prepareTargetDependencies(source incus.InstanceServer, dest incus.InstanceServer, mapping *resourceMapping, profiles []string, devices map[string]map[string]string) error {
+	interactive := termios.IsTerminal(getStdinFd())
+
+	// Gather the profiles, the devices coming from those already on the target and the ones to copy.
+	targetDevices := map[string]map[string]string{}
+	copiedProfiles := []api.ProfilesPost{}
+
+	for _, name := range profiles {
+		profile, _, err := dest.GetProfile(name)
+		if err == nil {
+			maps.Copy(targetDevices, profile.Devices)
+			continue
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			// Let the server report any other problem.
+			continue
+		}
+
+		copyProfile := c.flagCopyProfiles
+		if !copyProfile && interactive {
+			copyProfile, err = c.global.asker.AskBool(fmt.Sprintf(i18n.G("Profile %q doesn't exist on the target, copy it from the source?"), name)+" (yes/no) [default=no]: ", "no")
+			if err != nil {
+				return err
+			}
+		}
+
+		if !copyProfile {
+			return fmt.Errorf(i18n.G("Profile %q doesn't exist on the target, use --copy-profiles to copy it from the source or --profile to pick other profiles"), name)
+		}
+
+		sourceProfile, _, err := source.GetProfile(name)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed getting profile %q from the source: %w"), name, err)
+		}
+
+		// Translate the pools and networks of the copied profile like those of the instance.
+		put := sourceProfile.Writable()
+		if put.Devices == nil {
+			put.Devices = map[string]map[string]string{}
+		}
+
+		mapping.apply(put.Devices, nil, nil)
+		copiedProfiles = append(copiedProfiles, api.ProfilesPost{Name: name, ProfilePut: put})
+	}
+
+	// Check the storage pools and networks used by the devices the instance will have on the target.
+	expandedDevices := maps.Clone(targetDevices)
+	for _, profile := range copiedProfiles {
+		maps.Copy(expandedDevices, profile.Devices)
+	}
+
+	maps.Copy(expandedDevices, devices)
+
+	remap := &resourceMapping{Pools: map[string]string{}, Networks: map[string]string{}}
+	checked := map[string]bool{}
+
+	for _, devName := range slices.Sorted(maps.Keys(expandedDevices)) {
+		dev := expandedDevices[devName]
+
+		var kind string
+		var err error
+
+		switch {
+		case dev["type"] == "disk" && dev["pool"] != "":
+			kind = "pool"
+			if checked[kind+"/"+dev["pool"]] {
+				continue
+			}
+
+			_, _, err = dest.GetStoragePool(dev["pool"])
+		case dev["type"] == "nic" && dev["network"] != "":
+			kind = "network"
+			if checked[kind+"/"+dev["network"]] {
+				continue
+			}
+
+			_, _, err = dest.GetNetwork(dev["network"])
+		default:
+			continue
+		}
+
+		name := dev[kind]
+		checked[kind+"/"+name] = true
+
+		if err == nil || !api.StatusErrorCheck(err, http.StatusNotFound) {
+			continue
+		}
+
+		if !interactive {
+			if kind == "pool" {
+				return fmt.Errorf(i18n.G("Storage pool %q doesn't exist on the target, use --target-pool %s=<pool> to use another one"), name, name)
+			}
+
+			return fmt.Errorf(i18n.G("Network %q doesn't exist on the target, use --target-network %s=<network> to use another one"), name, name)
+		}
+
+		var question string
+		if kind == "pool" {
+			question = fmt.Sprintf(i18n.G("Storage pool %q doesn't exist on the target, storage pool to use instead:"), name)
+		} else {
+			question = fmt.Sprintf(i18n.G("Network %q doesn't exist on the target, network to use instead:"), name)
+		}
+
+		answer, err := c.global.asker.AskString(question+" ", "", validate.IsNotEmpty)
+		if err != nil {
+			return err
+		}
+
+		if kind == "pool" {
+			remap.Pools[name] = answer
+		} else {
+			remap.Networks[name] = answer
+		}
+	}
+
+	// Apply the remapping, the devices of profiles already on the target getting a local override.
+	remap.apply(devices, targetDevices, nil)
+
+	for _, profile := range copiedProfiles {
+		remap.apply(profile.Devices, nil, nil)
+
+		err := dest.CreateProfile(profile)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed copying profile %q to the target: %w"), profile.Name, err)
+		}
+	}
+
+	return nil
+}
+
 // Run runs the actual command logic.
 func (c *cmdCopy) Run(cmd *cobra.Command, args []string) error {
 	conf := c.global.conf
@@ -466,6 +690,15 @@ func (c *cmdCopy) Run(cmd *cobra.Command, args []string) error {
 	keepVolatile := c.flagRefresh
 	instanceOnly := c.flagInstanceOnly
 
+	// Instant copies are clones of the current state of the instance.
+	if c.flagInstant {
+		if c.flagRefresh {
+			return errors.New(i18n.G("--instant can't be used with --refresh"))
+		}
+
+		instanceOnly = true
+	}
+
 	// If target name is not specified, one will be chosen by the server
 	if len(args) < 2 {
 		return c.copyInstance(conf, args[0], "", keepVolatile, ephem, stateful, instanceOnly, mode, c.flagStorage, false)
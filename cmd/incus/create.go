@@ -381,7 +381,7 @@ func (c *cmdCreate) create(conf *config.Config, args []string, launch bool) (inc
 			return nil, "", err
 		}
 
-		if conf.Remotes[iremote].Protocol == "incus" {
+		if conf.Remotes[iremote].Protocol == "incus" && imgInfo.Type != "" {
 			if imgInfo.Type != "virtual-machine" && c.flagVM {
 				return nil, "", errors.New(i18n.G("Asked for a VM but image is of type container"))
 			}
@@ -443,14 +443,17 @@ func (c *cmdCreate) create(conf *config.Config, args []string, launch bool) (inc
 		return nil, "", errors.New(i18n.G("Didn't get name of new instance from the server"))
 	}
 
-	if len(instances) == 1 && name == "" {
+	// The server may pick the name or qualify the requested one with the cluster member.
+	if len(instances) == 1 {
 		uri, err := url.Parse(instances[0])
 		if err != nil {
 			return nil, "", err
 		}
 
-		name = path.Base(uri.Path)
-		fmt.Printf(i18n.G("Instance name is: %s")+"\n", name)
+		if path.Base(uri.Path) != name {
+			name = path.Base(uri.Path)
+			fmt.Printf(i18n.G("Instance name is: %s")+"\n", name)
+		}
 	}
 
 	// Validate the network setup
@@ -1,14 +1,18 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
 )
 
 type cmdDebug struct {
@@ -27,6 +31,18 @@ func (c *cmdDebug) Command() *cobra.Command {
 	debugAttachCmd := cmdDebugMemory{global: c.global, debug: c}
 	cmd.AddCommand(debugAttachCmd.Command())
 
+	debugQMPCmd := cmdDebugQMP{global: c.global, debug: c}
+	cmd.AddCommand(debugQMPCmd.Command())
+
+	debugLXCConfigCmd := cmdDebugLXCConfig{global: c.global, debug: c}
+	cmd.AddCommand(debugLXCConfigCmd.Command())
+
+	debugNetnsCmd := cmdDebugNetns{global: c.global, debug: c}
+	cmd.AddCommand(debugNetnsCmd.Command())
+
+	debugProfileCmd := cmdDebugProfile{global: c.global, debug: c}
+	cmd.AddCommand(debugProfileCmd.Command())
+
 	return cmd
 }
 
@@ -95,3 +111,298 @@ func (c *cmdDebugMemory) Run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+type cmdDebugQMP struct {
+	global *cmdGlobal
+	debug  *cmdDebug
+}
+
+// Command returns command definition for the QMP debug command.
+func (c *cmdDebugQMP) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("qmp", i18n.G("[<remote>:]<instance> <command> [<arguments>]"))
+	cmd.Short = i18n.G("Send a QMP command to a virtual machine")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Send a QMP command to a running virtual machine and print its result.
+
+The arguments of the command are provided as a JSON object.
+Only a limited set of mostly read-only commands is allowed and this requires administrative access to the server.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus debug qmp vm1 query-status
+    Shows the QEMU status of the vm1 instance.
+
+incus debug qmp vm1 qom-list '{"path": "/machine"}'
+    Lists the properties of the machine object of the vm1 instance.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run executes the QMP debug command.
+func (c *cmdDebugQMP) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 3)
+	if exit {
+		return err
+	}
+
+	// Connect to the daemon
+	remote, name, err := conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	req := api.InstanceDebugQMPPost{Command: args[1]}
+	if len(args) > 2 {
+		err = json.Unmarshal([]byte(args[2]), &req.Arguments)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Invalid QMP arguments: %w"), err)
+		}
+	}
+
+	result, err := d.RunInstanceDebugQMP(name, req)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(result.Return, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(out))
+
+	return nil
+}
+
+type cmdDebugLXCConfig struct {
+	global *cmdGlobal
+	debug  *cmdDebug
+}
+
+// Command returns command definition for the liblxc configuration debug command.
+func (c *cmdDebugLXCConfig) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("lxc-config", i18n.G("[<remote>:]<instance>"))
+	cmd.Short = i18n.G("Show the liblxc configuration of a container")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the final liblxc configuration of a container, including raw.lxc.
+
+The keys generated by Incus which raw.lxc overrides are reported first.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus debug lxc-config c1
+    Shows the liblxc configuration of the c1 instance.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run executes the liblxc configuration debug command.
+func (c *cmdDebugLXCConfig) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Connect to the daemon
+	remote, name, err := conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	config, err := d.GetInstanceDebugLXCConfig(name)
+	if err != nil {
+		return err
+	}
+
+	for _, conflict := range config.Conflicts {
+		fmt.Fprintf(os.Stderr, i18n.G("raw.lxc overrides %s: %q replaced by %q")+"\n", conflict.Key, conflict.Value, conflict.RawValue)
+	}
+
+	fmt.Print(config.Config)
+
+	return nil
+}
+
+type cmdDebugNetns struct {
+	global *cmdGlobal
+	debug  *cmdDebug
+
+	exec cmdExec
+}
+
+// Command returns command definition for the network namespace debug command.
+func (c *cmdDebugNetns) Command() *cobra.Command {
+	c.exec = cmdExec{global: c.global, netns: true}
+
+	cmd := &cobra.Command{}
+	cmd.Use = usage("netns", i18n.G("[<remote>:]<instance> [flags] [--] [<command line>]"))
+	cmd.Short = i18n.G("Run commands in the network namespace of a container")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Run commands in the network namespace of a container
+
+The command runs on the host as root, joined only to the network namespace of
+the container. It doesn't enter the filesystem or PID namespace of the
+container, so host tools like tcpdump or ss can be used against its networking.
+
+The command defaults to a shell and requires administrative access to the server.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus debug netns c1
+    Start a host shell in the network namespace of the c1 instance.
+
+incus debug netns c1 -- tcpdump -ni eth0
+    Capture the traffic of the eth0 interface of the c1 instance.`))
+
+	cmd.RunE = c.exec.Run
+	cmd.Flags().StringArrayVar(&c.exec.flagEnvironment, "env", nil, i18n.G("Environment variable to set (e.g. HOME=/home/foo)")+"``")
+	cmd.Flags().StringVar(&c.exec.flagMode, "mode", "auto", i18n.G("Override the terminal mode (auto, interactive or non-interactive)")+"``")
+	cmd.Flags().BoolVarP(&c.exec.flagForceInteractive, "force-interactive", "t", false, i18n.G("Force pseudo-terminal allocation"))
+	cmd.Flags().BoolVarP(&c.exec.flagForceNonInteractive, "force-noninteractive", "T", false, i18n.G("Disable pseudo-terminal allocation"))
+	cmd.Flags().BoolVarP(&c.exec.flagDisableStdin, "disable-stdin", "n", false, i18n.G("Disable stdin (reads from /dev/null)"))
+	cmd.Flags().StringVar(&c.exec.flagCwd, "cwd", "", i18n.G("Directory to run the command in on the host (default /)")+"``")
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+type cmdDebugProfile struct {
+	global *cmdGlobal
+	debug  *cmdDebug
+
+	flagDuration  string
+	flagFrequency int
+}
+
+// Command returns command definition for the CPU profiling debug command.
+func (c *cmdDebugProfile) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("profile", i18n.G("[<remote>:]<instance> [<target>]"))
+	cmd.Short = i18n.G("Capture a CPU profile of an instance")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Capture a CPU profile of a running instance
+
+The call stacks are sampled on the server with perf, scoped to the cgroup of a
+container or to the vCPU threads of a virtual machine. For virtual machines,
+this shows the time spent by the host running the vCPUs, not the processes
+of the guest.
+
+The profile is written as folded stacks to the target file, or to the standard
+output if none is given, and can be rendered with flame graph tools.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus debug profile c1 c1.folded --duration=30s
+    Captures a CPU profile of the c1 instance during 30 seconds.
+
+incus debug profile c1 | flamegraph.pl > c1.svg
+    Renders a flame graph of the c1 instance.`))
+
+	cmd.RunE = c.Run
+	cmd.Flags().StringVar(&c.flagDuration, "duration", "30s", i18n.G("Duration of the profiling")+"``")
+	cmd.Flags().IntVar(&c.flagFrequency, "frequency", 99, i18n.G("Sampling frequency in Hz")+"``")
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	return cmd
+}
+
+// Run executes the CPU profiling debug command.
+func (c *cmdDebugProfile) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	duration, err := time.ParseDuration(c.flagDuration)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Invalid duration: %w"), err)
+	}
+
+	if duration < time.Second {
+		return errors.New(i18n.G("The profiling duration must be at least one second"))
+	}
+
+	// Connect to the daemon
+	remote, name, err := conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	req := api.InstanceDebugProfilePost{
+		Duration:  int(duration.Round(time.Second).Seconds()),
+		Frequency: c.flagFrequency,
+	}
+
+	op, err := d.CreateInstanceDebugProfile(name, req)
+	if err != nil {
+		return err
+	}
+
+	// Wait for the profiling to complete, keeping the standard output clean for the profile.
+	progress := cli.ProgressRenderer{
+		Quiet: c.global.flagQuiet || len(args) < 2,
+	}
+
+	progress.Update(fmt.Sprintf(i18n.G("Profiling instance for %s"), duration.Round(time.Second)))
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	profile, ok := op.Get().Metadata["profile"].(string)
+	if !ok {
+		return errors.New(i18n.G("The server didn't return a profile"))
+	}
+
+	if len(args) < 2 {
+		fmt.Print(profile)
+		return nil
+	}
+
+	err = os.WriteFile(args[1], []byte(profile), 0o644)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -107,7 +107,7 @@ func (c *cmdDelete) Run(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		if ct.StatusCode != 0 && ct.StatusCode != api.Stopped {
+		if ct.StatusCode != 0 && ct.StatusCode != api.Stopped && ct.StatusCode != api.Suspended {
 			if !c.flagForce {
 				return errors.New(i18n.G("The instance is currently running, stop it first or pass --force"))
 			}
@@ -3,10 +3,12 @@ package main
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/gorilla/websocket"
 	"github.com/spf13/cobra"
@@ -30,8 +32,13 @@ type cmdExec struct {
 	flagUser                uint32
 	flagGroup               uint32
 	flagCwd                 string
+	flagRemoteGroup         string
+	flagConcurrency         int
 
 	interactive bool
+
+	// Run the command on the host in the network namespace of the instance (incus debug netns).
+	netns bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -54,7 +61,10 @@ Mode defaults to non-interactive, interactive mode is selected if both stdin AND
 	Run the "bash" command in instance "c1"
 
 incus exec c1 -- ls -lh /
-	Run the "ls -lh /" command in instance "c1"`))
+	Run the "ls -lh /" command in instance "c1"
+
+incus exec --remote-group edge-sites c1 -- apt-get upgrade -y
+	Run the "apt-get upgrade -y" command in instance "c1" on all the remotes of the "edge-sites" group`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().StringArrayVar(&c.flagEnvironment, "env", nil, i18n.G("Environment variable to set (e.g. HOME=/home/foo)")+"``")
@@ -65,6 +75,8 @@ incus exec c1 -- ls -lh /
 	cmd.Flags().Uint32Var(&c.flagUser, "user", 0, i18n.G("User ID to run the command as (default 0)")+"``")
 	cmd.Flags().Uint32Var(&c.flagGroup, "group", 0, i18n.G("Group ID to run the command as (default 0)")+"``")
 	cmd.Flags().StringVar(&c.flagCwd, "cwd", "", i18n.G("Directory to run the command in (default /root)")+"``")
+	cmd.Flags().StringVar(&c.flagRemoteGroup, "remote-group", "", i18n.G("Run the command in the instance of the same name on all the remotes of the group")+"``")
+	cmd.Flags().IntVar(&c.flagConcurrency, "concurrency", 10, i18n.G("Maximum number of remotes to run the command on at once, with --remote-group")+"``")
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -99,7 +111,13 @@ func (c *cmdExec) Run(cmd *cobra.Command, args []string) error {
 	conf := c.global.conf
 
 	// Quick checks.
-	exit, err := c.global.checkArgs(cmd, args, 2, -1)
+	minArgs := 2
+	if c.netns {
+		// The command defaults to a shell.
+		minArgs = 1
+	}
+
+	exit, err := c.global.checkArgs(cmd, args, minArgs, -1)
 	if exit {
 		return err
 	}
@@ -112,6 +130,10 @@ func (c *cmdExec) Run(cmd *cobra.Command, args []string) error {
 		return errors.New(i18n.G("You can't pass -t or -T at the same time as --mode"))
 	}
 
+	if c.flagRemoteGroup != "" {
+		return c.runGroup(args[0], args[1:])
+	}
+
 	// Connect to the daemon
 	remote, name, err := conf.ParseRemote(args[0])
 	if err != nil {
@@ -130,15 +152,7 @@ func (c *cmdExec) Run(cmd *cobra.Command, args []string) error {
 		env["TERM"] = myTerm
 	}
 
-	for _, arg := range c.flagEnvironment {
-		pieces := strings.SplitN(arg, "=", 2)
-		value := ""
-		if len(pieces) > 1 {
-			value = pieces[1]
-		}
-
-		env[pieces[0]] = value
-	}
+	c.parseEnvironment(env)
 
 	// Configure the terminal
 	stdinFd := getStdinFd()
@@ -211,7 +225,20 @@ func (c *cmdExec) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Run the command in the instance
-	op, err := d.ExecInstance(name, req, &execArgs)
+	var op incus.Operation
+	if c.netns {
+		op, err = d.ExecInstanceDebugNetns(name, api.InstanceDebugNetnsPost{
+			Command:     req.Command,
+			Environment: req.Environment,
+			Interactive: req.Interactive,
+			Width:       req.Width,
+			Height:      req.Height,
+			Cwd:         req.Cwd,
+		}, &execArgs)
+	} else {
+		op, err = d.ExecInstance(name, req, &execArgs)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -235,3 +262,167 @@ func (c *cmdExec) Run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseEnvironment adds the environment variables passed with --env to env.
+func (c *cmdExec) parseEnvironment(env map[string]string) {
+	for _, arg := range c.flagEnvironment {
+		pieces := strings.SplitN(arg, "=", 2)
+		value := ""
+		if len(pieces) > 1 {
+			value = pieces[1]
+		}
+
+		env[pieces[0]] = value
+	}
+}
+
+// runGroup runs the command in the instance of the same name on all the remotes of a group.
+// The output of each remote is shown once its command completes, followed by a summary.
+func (c *cmdExec) runGroup(name string, command []string) error {
+	conf := c.global.conf
+
+	if strings.Contains(name, ":") {
+		return errors.New(i18n.G("The instance name can't include a remote when using --remote-group"))
+	}
+
+	if c.flagMode == "interactive" || c.flagForceInteractive {
+		return errors.New(i18n.G("Interactive mode can't be used with --remote-group"))
+	}
+
+	if c.flagConcurrency < 1 {
+		return errors.New(i18n.G("The concurrency must be at least 1"))
+	}
+
+	remotes, err := conf.GetRemoteGroup(c.flagRemoteGroup)
+	if err != nil {
+		return err
+	}
+
+	env := map[string]string{}
+	c.parseEnvironment(env)
+
+	req := api.InstanceExecPost{
+		Command:     command,
+		WaitForWS:   true,
+		Interactive: false,
+		Environment: env,
+		User:        c.flagUser,
+		Group:       c.flagGroup,
+		Cwd:         c.flagCwd,
+	}
+
+	type execResult struct {
+		exitCode int
+		err      error
+	}
+
+	results := make([]execResult, len(remotes))
+	slots := make(chan struct{}, c.flagConcurrency)
+	outputLock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+
+	for i, remote := range remotes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			var stdout, stderr bytes.Buffer
+			exitCode, err := c.execRemote(remote, name, req, &stdout, &stderr)
+			results[i] = execResult{exitCode: exitCode, err: err}
+
+			outputLock.Lock()
+			defer outputLock.Unlock()
+
+			if !c.global.flagQuiet {
+				fmt.Printf("=== %s ===\n", remote)
+			}
+
+			_, _ = os.Stdout.Write(stdout.Bytes())
+			_, _ = os.Stderr.Write(stderr.Bytes())
+
+			if err != nil {
+				fmt.Fprintf(os.Stderr, i18n.G("Error: %v")+"\n", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Summarize the results.
+	failed := 0
+	data := [][]string{}
+	for i, remote := range remotes {
+		status := i18n.G("OK")
+		exitCode := strconv.Itoa(results[i].exitCode)
+
+		if results[i].err != nil {
+			status = i18n.G("ERROR")
+			exitCode = ""
+			failed++
+		} else if results[i].exitCode != 0 {
+			status = i18n.G("FAILED")
+			failed++
+		}
+
+		data = append(data, []string{remote, status, exitCode})
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Println("")
+
+		header := []string{i18n.G("REMOTE"), i18n.G("STATUS"), i18n.G("EXIT CODE")}
+		err = cli.RenderTable(os.Stdout, cli.TableFormatTable, header, data, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		c.global.ret = 1
+		return fmt.Errorf(i18n.G("The command failed on %d out of %d remotes"), failed, len(remotes))
+	}
+
+	return nil
+}
+
+// execRemote runs the command non-interactively on a single remote and returns its exit code.
+func (c *cmdExec) execRemote(remote string, name string, req api.InstanceExecPost, stdout io.Writer, stderr io.Writer) (int, error) {
+	d, err := c.global.conf.GetInstanceServer(remote)
+	if err != nil {
+		return -1, err
+	}
+
+	execArgs := incus.InstanceExecArgs{
+		Stdin:    bytes.NewReader(nil),
+		Stdout:   stdout,
+		Stderr:   stderr,
+		DataDone: make(chan bool),
+	}
+
+	op, err := d.ExecInstance(name, req, &execArgs)
+	if err != nil {
+		return -1, err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return -1, err
+	}
+
+	// Wait for any remaining I/O to be flushed
+	<-execArgs.DataDone
+
+	exitCode := -1
+	opAPI := op.Get()
+	if opAPI.Metadata != nil {
+		exitStatusRaw, ok := opAPI.Metadata["return"].(float64)
+		if ok {
+			exitCode = int(exitStatusRaw)
+		}
+	}
+
+	return exitCode, nil
+}
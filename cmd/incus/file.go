@@ -459,7 +459,10 @@ func (c *cmdFilePull) Command() *cobra.Command {
 		`Pull files from instances`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`incus file pull foo/etc/hosts .
-   To pull /etc/hosts from the instance and write it to the current directory.`))
+   To pull /etc/hosts from the instance and write it to the current directory.
+
+incus file pull foo/snap0/etc/hosts .
+   To pull /etc/hosts from the snapshot "snap0" of the instance and write it to the current directory.`))
 
 	cmd.Flags().BoolVarP(&c.file.flagMkdir, "create-dirs", "p", false, i18n.G("Create any directories necessary"))
 	cmd.Flags().BoolVarP(&c.file.flagRecursive, "recursive", "r", false, i18n.G("Recursively transfer files"))
@@ -530,6 +533,7 @@ func (c *cmdFilePull) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	sftpClients := map[string]*sftp.Client{}
+	snapshotNames := map[string][]string{}
 
 	defer func() {
 		for _, sftpClient := range sftpClients {
@@ -548,6 +552,21 @@ func (c *cmdFilePull) Run(cmd *cobra.Command, args []string) error {
 			pathSpec[1] = "/" + pathSpec[1]
 		}
 
+		// Files are read directly from the snapshot when the path starts with the name of one.
+		snapName, snapPath, err := c.snapshotPath(resource, pathSpec[0], pathSpec[1], snapshotNames)
+		if err != nil {
+			return err
+		}
+
+		if snapName != "" {
+			err := c.pullSnapshotFile(resource.server, pathSpec[0], snapName, snapPath, target, targetIsDir)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		sftpConn, ok := sftpClients[pathSpec[0]]
 		if !ok {
 			sftpConn, err = resource.server.GetInstanceFileSFTP(pathSpec[0])
@@ -675,6 +694,69 @@ func (c *cmdFilePull) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// snapshotPath returns the snapshot and the path within it if the first component of the path is the name of
+// a snapshot of the instance. The snapshot name is empty for paths within the instance itself.
+func (c *cmdFilePull) snapshotPath(resource remoteResource, instName string, filePath string, snapshotNames map[string][]string) (string, string, error) {
+	// Edited files are pushed back, which can't be done to a snapshot.
+	if c.edit || !resource.server.HasExtension("instance_snapshot_files") {
+		return "", "", nil
+	}
+
+	snapName, snapPath, _ := strings.Cut(strings.TrimPrefix(filePath, "/"), "/")
+	if snapName == "" {
+		return "", "", nil
+	}
+
+	key := resource.remote + ":" + instName
+	names, ok := snapshotNames[key]
+	if !ok {
+		var err error
+
+		names, err = resource.server.GetInstanceSnapshotNames(instName)
+		if err != nil {
+			return "", "", err
+		}
+
+		snapshotNames[key] = names
+	}
+
+	if !slices.Contains(names, snapName) {
+		return "", "", nil
+	}
+
+	return snapName, "/" + snapPath, nil
+}
+
+// pullSnapshotFile pulls a file, or a directory when recursing, from an instance snapshot.
+func (c *cmdFilePull) pullSnapshotFile(server incus.InstanceServer, instName string, snapName string, p string, target string, targetIsDir bool) error {
+	content, resp, err := server.GetInstanceSnapshotFile(instName, snapName, p)
+	if err != nil {
+		return err
+	}
+
+	if resp.Type == "directory" {
+		if !c.file.flagRecursive {
+			return errors.New(i18n.G("Can't pull a directory without --recursive"))
+		}
+
+		err := os.MkdirAll(target, DirMode)
+		if err != nil {
+			return err
+		}
+
+		return c.file.recursivePullSnapshotFile(server, instName, snapName, p, target)
+	}
+
+	defer func() { _ = content.Close() }()
+
+	targetPath := target
+	if targetIsDir {
+		targetPath = filepath.Join(target, filepath.Base(p))
+	}
+
+	return c.file.writeSnapshotFile(content, resp, p, targetPath)
+}
+
 // Push.
 type cmdFilePush struct {
 	global *cmdGlobal
@@ -1174,6 +1256,98 @@ func (c *cmdFile) recursivePullFile(sftpConn *sftp.Client, p string, targetDir s
 	return nil
 }
 
+func (c *cmdFile) recursivePullSnapshotFile(server incus.InstanceServer, instName string, snapName string, p string, targetDir string) error {
+	content, resp, err := server.GetInstanceSnapshotFile(instName, snapName, p)
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(targetDir, filepath.Base(p))
+	logger.Infof("Pulling %s from %s (%s)", target, p, resp.Type)
+
+	if resp.Type == "directory" {
+		err := os.Mkdir(target, os.FileMode(resp.Mode))
+		if err != nil {
+			return err
+		}
+
+		for _, ent := range resp.Entries {
+			nextP := filepath.Join(p, ent)
+
+			err := c.recursivePullSnapshotFile(server, instName, snapName, nextP, target)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	defer func() { _ = content.Close() }()
+
+	return c.writeSnapshotFile(content, resp, p, target)
+}
+
+// writeSnapshotFile writes a file or symlink retrieved from an instance snapshot to the target path.
+func (c *cmdFile) writeSnapshotFile(content io.Reader, resp *incus.InstanceFileResponse, p string, targetPath string) error {
+	if resp.Type == "symlink" {
+		// The server returns the target of the symlink as its content.
+		linkTarget, err := io.ReadAll(content)
+		if err != nil {
+			return err
+		}
+
+		return os.Symlink(string(linkTarget), targetPath)
+	} else if resp.Type != "file" {
+		return fmt.Errorf(i18n.G("Unknown file type '%s'"), resp.Type)
+	}
+
+	if targetPath == "-" {
+		_, err := io.Copy(os.Stdout, content)
+		return err
+	}
+
+	f, err := os.Create(targetPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	err = os.Chmod(targetPath, os.FileMode(resp.Mode))
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: fmt.Sprintf(i18n.G("Pulling %s from %s: %%s"), targetPath, p),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	writer := &ioprogress.ProgressWriter{
+		WriteCloser: f,
+		Tracker: &ioprogress.ProgressTracker{
+			Handler: func(bytesReceived int64, speed int64) {
+				progress.UpdateProgress(ioprogress.ProgressData{
+					Text: fmt.Sprintf("%s (%s/s)",
+						units.GetByteSizeString(bytesReceived, 2),
+						units.GetByteSizeString(speed, 2)),
+				})
+			},
+		},
+	}
+
+	_, err = io.Copy(writer, content)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	return f.Close()
+}
+
 func (c *cmdFile) recursivePushFile(sftpConn *sftp.Client, source string, target string) error {
 	source = filepath.Clean(source)
 
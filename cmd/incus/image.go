@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -62,6 +63,10 @@ hash or alias name (if one is set).`))
 	imageAliasCmd := cmdImageAlias{global: c.global, image: c}
 	cmd.AddCommand(imageAliasCmd.Command())
 
+	// Bundle
+	imageBundleCmd := cmdImageBundle{global: c.global, image: c}
+	cmd.AddCommand(imageBundleCmd.Command())
+
 	// Copy
 	imageCopyCmd := cmdImageCopy{global: c.global, image: c}
 	cmd.AddCommand(imageCopyCmd.Command())
@@ -94,6 +99,10 @@ hash or alias name (if one is set).`))
 	imageRefreshCmd := cmdImageRefresh{global: c.global, image: c}
 	cmd.AddCommand(imageRefreshCmd.Command())
 
+	// SBOM
+	imageSBOMCmd := cmdImageSBOM{global: c.global, image: c}
+	cmd.AddCommand(imageSBOMCmd.Command())
+
 	// Show
 	imageShowCmd := cmdImageShow{global: c.global, image: c}
 	cmd.AddCommand(imageShowCmd.Command())
@@ -260,6 +269,26 @@ func (c *cmdImageCopy) Run(cmd *cobra.Command, args []string) error {
 		aliases[i].Name = entry
 	}
 
+	// Check for conflicting aliases in the target project before transferring the image.
+	newAliases := aliases
+	if c.flagCopyAliases {
+		newAliases = append(slices.Clone(imgInfo.Aliases), aliases...)
+	}
+
+	existingAliases, err := GetCommonAliases(destinationServer, newAliases...)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Error retrieving aliases: %w"), err)
+	}
+
+	if len(existingAliases) > 0 {
+		names := []string{}
+		for _, alias := range existingAliases {
+			names = append(names, alias.Name)
+		}
+
+		return fmt.Errorf(i18n.G("Aliases already exists: %s"), strings.Join(names, ", "))
+	}
+
 	copyArgs := incus.ImageCopyArgs{
 		Aliases:     aliases,
 		AutoUpdate:  c.flagAutoUpdate,
@@ -338,12 +367,40 @@ func (c *cmdImageDelete) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	aliases := map[string][]api.ImageAlias{}
 	for _, resource := range resources {
 		if resource.name == "" {
 			return errors.New(i18n.G("Image identifier missing"))
 		}
 
-		image := c.image.dereferenceAlias(resource.server, "", resource.name)
+		aliases[resource.remote] = append(aliases[resource.remote], api.ImageAlias{Name: resource.name})
+	}
+
+	// Resolve the aliases of each remote in a single request.
+	targets := map[string]map[string]string{}
+	for _, resource := range resources {
+		_, ok := targets[resource.remote]
+		if ok {
+			continue
+		}
+
+		existingAliases, err := GetCommonAliases(resource.server, aliases[resource.remote]...)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Error retrieving aliases: %w"), err)
+		}
+
+		targets[resource.remote] = map[string]string{}
+		for _, alias := range existingAliases {
+			targets[resource.remote][alias.Name] = alias.Target
+		}
+	}
+
+	for _, resource := range resources {
+		image, ok := targets[resource.remote][resource.name]
+		if !ok {
+			image = resource.name
+		}
+
 		op, err := resource.server.DeleteImage(image)
 		if err != nil {
 			return err
@@ -1040,10 +1097,15 @@ func (c *cmdImageInfo) Run(cmd *cobra.Command, args []string) error {
 
 	fmt.Println(i18n.G("Aliases:"))
 	for _, alias := range info.Aliases {
+		name := alias.Name
+		if alias.Variant {
+			name = fmt.Sprintf(i18n.G("%s (variant)"), alias.Name)
+		}
+
 		if alias.Description != "" {
-			fmt.Printf("    - %s (%s)\n", alias.Name, alias.Description)
+			fmt.Printf("    - %s (%s)\n", name, alias.Description)
 		} else {
-			fmt.Printf("    - %s\n", alias.Name)
+			fmt.Printf("    - %s\n", name)
 		}
 	}
 
@@ -1057,6 +1119,21 @@ func (c *cmdImageInfo) Run(cmd *cobra.Command, args []string) error {
 		fmt.Printf("    "+i18n.G("Alias: %s")+"\n", info.UpdateSource.Alias)
 	}
 
+	if info.Provenance != nil {
+		fmt.Println(i18n.G("Provenance:"))
+		if info.Provenance.Builder != "" {
+			fmt.Printf("    "+i18n.G("Builder: %s")+"\n", info.Provenance.Builder)
+		}
+
+		if info.Provenance.SourceHash != "" {
+			fmt.Printf("    "+i18n.G("Source hash: %s")+"\n", info.Provenance.SourceHash)
+		}
+	}
+
+	if info.SBOM {
+		fmt.Printf(i18n.G("SBOM: %s")+"\n", i18n.G("yes"))
+	}
+
 	if len(info.Profiles) == 0 {
 		fmt.Print(i18n.G("Profiles: ") + "[]\n")
 	} else {
@@ -1514,6 +1591,64 @@ func (c *cmdImageRefresh) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// SBOM.
+type cmdImageSBOM struct {
+	global *cmdGlobal
+	image  *cmdImage
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdImageSBOM) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("sbom", i18n.G("[<remote>:]<image>"))
+	cmd.Short = i18n.G("Show the SBOM document of images")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the SBOM document of images`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpImages(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdImageSBOM) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return errors.New(i18n.G("Image identifier missing"))
+	}
+
+	// Get the SBOM document
+	image := c.image.dereferenceAlias(resource.server, "", resource.name)
+	sbom, err := resource.server.GetImageSBOM(image)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(sbom))
+
+	return nil
+}
+
 // Show.
 type cmdImageShow struct {
 	global *cmdGlobal
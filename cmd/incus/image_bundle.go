@@ -0,0 +1,906 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	incus "github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/revert"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// Names of the entries describing an image bundle, in the order they are stored in the archive.
+const (
+	imageBundleSigner    = "signer.crt"
+	imageBundleIndex     = "index.yaml"
+	imageBundleSignature = "index.yaml.sig"
+)
+
+// imageBundleVersion is the version of the image bundle format.
+const imageBundleVersion = 1
+
+// imageBundleMaxIndexSize is the maximum size of the entries describing an image bundle.
+const imageBundleMaxIndexSize = 16 * 1024 * 1024
+
+// imageBundleManifest describes the content of an image bundle.
+type imageBundleManifest struct {
+	Version  int                `yaml:"version"`
+	Created  time.Time          `yaml:"created"`
+	Images   []imageBundleImage `yaml:"images"`
+	Profiles []api.ProfilesPost `yaml:"profiles,omitempty"`
+}
+
+// imageBundleImage describes an image of a bundle.
+type imageBundleImage struct {
+	Fingerprint string            `yaml:"fingerprint"`
+	Type        string            `yaml:"type"`
+	Public      bool              `yaml:"public"`
+	Properties  map[string]string `yaml:"properties,omitempty"`
+	Profiles    []string          `yaml:"profiles,omitempty"`
+	Aliases     []api.ImageAlias  `yaml:"aliases,omitempty"`
+	Files       []imageBundleFile `yaml:"files"`
+}
+
+// imageBundleFile describes a file of a bundled image.
+type imageBundleFile struct {
+	Name     string `yaml:"name"`
+	Filename string `yaml:"filename"`
+	Rootfs   bool   `yaml:"rootfs,omitempty"`
+	Size     int64  `yaml:"size"`
+	SHA256   string `yaml:"sha256"`
+}
+
+type cmdImageBundle struct {
+	global *cmdGlobal
+	image  *cmdImage
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdImageBundle) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("bundle")
+	cmd.Short = i18n.G("Manage image bundles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage image bundles
+
+Image bundles are single signed archives holding a set of images together
+with their aliases and profiles. They are meant to transfer images to
+servers which can't reach any image server.`))
+
+	// Export
+	imageBundleExportCmd := cmdImageBundleExport{global: c.global, image: c.image, imageBundle: c}
+	cmd.AddCommand(imageBundleExportCmd.Command())
+
+	// Import
+	imageBundleImportCmd := cmdImageBundleImport{global: c.global, image: c.image, imageBundle: c}
+	cmd.AddCommand(imageBundleImportCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// imageBundleDigest returns the digest of the bundle index which gets signed.
+func imageBundleDigest(index []byte) []byte {
+	digest := sha256.Sum256(index)
+	return digest[:]
+}
+
+// imageBundleSign signs the bundle index with the provided key pair.
+func imageBundleSign(keyPair tls.Certificate, index []byte) ([]byte, error) {
+	signer, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New(i18n.G("Unsupported private key type"))
+	}
+
+	_, isEd25519 := signer.Public().(ed25519.PublicKey)
+	if isEd25519 {
+		return signer.Sign(rand.Reader, index, crypto.Hash(0))
+	}
+
+	return signer.Sign(rand.Reader, imageBundleDigest(index), crypto.SHA256)
+}
+
+// imageBundleVerify checks that the bundle index was signed by the certificate.
+func imageBundleVerify(cert *x509.Certificate, index []byte, signature []byte) error {
+	var valid bool
+
+	switch pub := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(pub, imageBundleDigest(index), signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(pub, crypto.SHA256, imageBundleDigest(index), signature) == nil
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(pub, index, signature)
+	default:
+		return errors.New(i18n.G("Unsupported public key type"))
+	}
+
+	if !valid {
+		return errors.New(i18n.G("Invalid bundle signature"))
+	}
+
+	return nil
+}
+
+// Export.
+type cmdImageBundleExport struct {
+	global      *cmdGlobal
+	image       *cmdImage
+	imageBundle *cmdImageBundle
+
+	flagVM         bool
+	flagNoProfiles bool
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdImageBundleExport) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("export", i18n.G("[<remote>:]<image> [[<remote>:]<image>...] <target>"))
+	cmd.Short = i18n.G("Export images into a bundle")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Export images into a bundle
+
+The images are exported along with their aliases and the profiles they use.
+The bundle is signed with the client certificate.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus image bundle export images:debian/12 images:alpine/edge offline.bundle
+    Export two images from the "images" remote into the offline.bundle file.`))
+
+	cmd.Flags().BoolVar(&c.flagVM, "vm", false, i18n.G("Query virtual machine images"))
+	cmd.Flags().BoolVar(&c.flagNoProfiles, "no-profiles", false, i18n.G("Don't include the profiles used by the images"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return c.global.cmpImages(toComplete)
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdImageBundleExport) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	target := args[len(args)-1]
+
+	// Load the signing key.
+	err = conf.GenerateClientCertificate()
+	if err != nil {
+		return err
+	}
+
+	keyPair, err := tls.LoadX509KeyPair(conf.ConfigPath("client.crt"), conf.ConfigPath("client.key"))
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed loading the client certificate: %w"), err)
+	}
+
+	imageType := ""
+	if c.flagVM {
+		imageType = "virtual-machine"
+	}
+
+	tmpDir, err := os.MkdirTemp("", "incus_bundle_")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	manifest := imageBundleManifest{
+		Version: imageBundleVersion,
+		Created: time.Now().UTC(),
+	}
+
+	servers := map[string]incus.ImageServer{}
+
+	for _, arg := range args[:len(args)-1] {
+		remoteName, name, err := conf.ParseRemote(arg)
+		if err != nil {
+			return err
+		}
+
+		remoteServer, ok := servers[remoteName]
+		if !ok {
+			remoteServer, err = conf.GetImageServer(remoteName)
+			if err != nil {
+				return err
+			}
+
+			servers[remoteName] = remoteServer
+		}
+
+		fingerprint := c.image.dereferenceAlias(remoteServer, imageType, name)
+
+		info, _, err := remoteServer.GetImage(fingerprint)
+		if err != nil {
+			return err
+		}
+
+		if slices.ContainsFunc(manifest.Images, func(entry imageBundleImage) bool { return entry.Fingerprint == info.Fingerprint }) {
+			continue
+		}
+
+		entry, err := c.download(remoteServer, info, tmpDir)
+		if err != nil {
+			return err
+		}
+
+		manifest.Images = append(manifest.Images, *entry)
+
+		// Include the profiles used by the image.
+		instanceServer, isInstanceServer := remoteServer.(incus.InstanceServer)
+		if c.flagNoProfiles || !isInstanceServer {
+			continue
+		}
+
+		for _, profileName := range info.Profiles {
+			if slices.ContainsFunc(manifest.Profiles, func(profile api.ProfilesPost) bool { return profile.Name == profileName }) {
+				continue
+			}
+
+			profile, _, err := instanceServer.GetProfile(profileName)
+			if err != nil {
+				return fmt.Errorf(i18n.G("Failed getting profile %q: %w"), profileName, err)
+			}
+
+			manifest.Profiles = append(manifest.Profiles, api.ProfilesPost{Name: profile.Name, ProfilePut: profile.Writable()})
+		}
+	}
+
+	// Sign the index.
+	index, err := yaml.Marshal(&manifest)
+	if err != nil {
+		return err
+	}
+
+	signature, err := imageBundleSign(keyPair, index)
+	if err != nil {
+		return err
+	}
+
+	signer := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: keyPair.Certificate[0]})
+
+	// Write the bundle, only putting it in place once complete.
+	err = c.write(target+".tmp", manifest, signer, index, signature, tmpDir)
+	if err != nil {
+		_ = os.Remove(target + ".tmp")
+		return err
+	}
+
+	err = os.Rename(target+".tmp", target)
+	if err != nil {
+		_ = os.Remove(target + ".tmp")
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Bundle exported with %d images")+"\n", len(manifest.Images))
+	}
+
+	return nil
+}
+
+// download retrieves the files of the image into the temporary directory.
+func (c *cmdImageBundleExport) download(remoteServer incus.ImageServer, info *api.Image, tmpDir string) (*imageBundleImage, error) {
+	metaPath := filepath.Join(tmpDir, info.Fingerprint)
+	rootfsPath := metaPath + ".root"
+
+	meta, err := os.Create(metaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = meta.Close() }()
+
+	rootfs, err := os.Create(rootfsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rootfs.Close() }()
+
+	progress := cli.ProgressRenderer{
+		Format: fmt.Sprintf(i18n.G("Exporting image %s: %%s"), info.Fingerprint[:12]),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	req := incus.ImageFileRequest{
+		MetaFile:        io.WriteSeeker(meta),
+		RootfsFile:      io.WriteSeeker(rootfs),
+		ProgressHandler: progress.UpdateProgress,
+	}
+
+	resp, err := remoteServer.GetImageFile(info.Fingerprint, req)
+	if err != nil {
+		progress.Done("")
+		return nil, err
+	}
+
+	progress.Done("")
+
+	entry := &imageBundleImage{
+		Fingerprint: info.Fingerprint,
+		Type:        info.Type,
+		Public:      info.Public,
+		Properties:  info.Properties,
+		Profiles:    info.Profiles,
+		Aliases:     info.Aliases,
+	}
+
+	files := []struct {
+		file     *os.File
+		filename string
+		size     int64
+		rootfs   bool
+	}{
+		{file: meta, filename: resp.MetaName, size: resp.MetaSize},
+		{file: rootfs, filename: resp.RootfsName, size: resp.RootfsSize, rootfs: true},
+	}
+
+	for _, file := range files {
+		if file.rootfs && file.size == 0 {
+			continue
+		}
+
+		// Truncate down to size and compute the hash.
+		err = file.file.Truncate(file.size)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = file.file.Seek(0, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := sha256.New()
+		_, err = io.Copy(hash, file.file)
+		if err != nil {
+			return nil, err
+		}
+
+		filename := file.filename
+		if filename == "" {
+			filename = filepath.Base(file.file.Name())
+		}
+
+		entry.Files = append(entry.Files, imageBundleFile{
+			Name:     path.Join("images", filepath.Base(file.file.Name())),
+			Filename: filename,
+			Rootfs:   file.rootfs,
+			Size:     file.size,
+			SHA256:   hex.EncodeToString(hash.Sum(nil)),
+		})
+	}
+
+	return entry, nil
+}
+
+// write creates the bundle archive.
+func (c *cmdImageBundleExport) write(target string, manifest imageBundleManifest, signer []byte, index []byte, signature []byte, tmpDir string) error {
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = out.Close() }()
+
+	tw := tar.NewWriter(out)
+
+	writeEntry := func(name string, size int64, content io.Reader) error {
+		err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o600,
+			Size:    size,
+			ModTime: manifest.Created,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tw, content)
+		return err
+	}
+
+	// The signature entries come first so that the bundle can be verified before reading the images.
+	for _, entry := range []struct {
+		name    string
+		content []byte
+	}{
+		{name: imageBundleSigner, content: signer},
+		{name: imageBundleIndex, content: index},
+		{name: imageBundleSignature, content: signature},
+	} {
+		err = writeEntry(entry.name, int64(len(entry.content)), bytes.NewReader(entry.content))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, image := range manifest.Images {
+		for _, file := range image.Files {
+			f, err := os.Open(filepath.Join(tmpDir, filepath.Base(file.Name)))
+			if err != nil {
+				return err
+			}
+
+			err = writeEntry(file.Name, file.Size, f)
+			_ = f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+// Import.
+type cmdImageBundleImport struct {
+	global      *cmdGlobal
+	image       *cmdImage
+	imageBundle *cmdImageBundle
+
+	flagTrust      []string
+	flagForce      bool
+	flagReuse      bool
+	flagNoProfiles bool
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdImageBundleImport) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("import", i18n.G("<bundle> [<remote>:]"))
+	cmd.Short = i18n.G("Import images from a bundle")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Import images from a bundle
+
+The signature and content of the bundle are verified before anything is imported.
+Unless trusted certificates are provided with --trust, the fingerprint of the
+certificate that signed the bundle is shown for confirmation.
+
+Images and profiles which already exist are kept as they are.
+If any part of the import fails, everything that was imported is removed again.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus image bundle import offline.bundle --trust exporter.crt
+    Import the images of the offline.bundle file, checking that it was signed by the certificate in exporter.crt.`))
+
+	cmd.Flags().StringArrayVar(&c.flagTrust, "trust", nil, i18n.G("Certificate trusted to sign the bundle")+"``")
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, i18n.G("Import the bundle without confirming its signer"))
+	cmd.Flags().BoolVar(&c.flagReuse, "reuse", false, i18n.G("If an image alias already exists, move it to the bundled image"))
+	cmd.Flags().BoolVar(&c.flagNoProfiles, "no-profiles", false, i18n.G("Don't create the profiles included in the bundle"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// readEntry reads the next entry of the bundle, checking its name.
+func (c *cmdImageBundleImport) readEntry(tr *tar.Reader, name string) ([]byte, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Invalid bundle: %w"), err)
+	}
+
+	if hdr.Name != name {
+		return nil, fmt.Errorf(i18n.G("Invalid bundle: expected %q, got %q"), name, hdr.Name)
+	}
+
+	if hdr.Size > imageBundleMaxIndexSize {
+		return nil, fmt.Errorf(i18n.G("Invalid bundle: %q is too large"), name)
+	}
+
+	return io.ReadAll(tr)
+}
+
+// checkSigner validates that the signer of the bundle is trusted.
+func (c *cmdImageBundleImport) checkSigner(cert *x509.Certificate) error {
+	fingerprint := localtls.CertFingerprint(cert)
+
+	if len(c.flagTrust) > 0 {
+		for _, path := range c.flagTrust {
+			trusted, err := localtls.ReadCert(path)
+			if err != nil {
+				return fmt.Errorf(i18n.G("Failed reading trusted certificate %q: %w"), path, err)
+			}
+
+			if localtls.CertFingerprint(trusted) == fingerprint {
+				return nil
+			}
+		}
+
+		return fmt.Errorf(i18n.G("Bundle signed by untrusted certificate %s"), fingerprint)
+	}
+
+	if c.flagForce {
+		return nil
+	}
+
+	trust, err := c.global.asker.AskBool(fmt.Sprintf(i18n.G("Bundle signed by certificate %s, import it?"), fingerprint)+" (yes/no) [default=no]: ", "no")
+	if err != nil {
+		return err
+	}
+
+	if !trust {
+		return errors.New(i18n.G("Bundle import aborted"))
+	}
+
+	return nil
+}
+
+// extract verifies the bundled image files while storing them in the temporary directory.
+func (c *cmdImageBundleImport) extract(tr *tar.Reader, manifest imageBundleManifest, tmpDir string) error {
+	files := map[string]imageBundleFile{}
+	for _, image := range manifest.Images {
+		for _, file := range image.Files {
+			files[file.Name] = file
+		}
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf(i18n.G("Invalid bundle: %w"), err)
+		}
+
+		file, ok := files[hdr.Name]
+		if !ok {
+			return fmt.Errorf(i18n.G("Invalid bundle: unexpected entry %q"), hdr.Name)
+		}
+
+		delete(files, hdr.Name)
+
+		f, err := os.Create(filepath.Join(tmpDir, filepath.Base(file.Name)))
+		if err != nil {
+			return err
+		}
+
+		hash := sha256.New()
+		size, err := io.Copy(io.MultiWriter(f, hash), io.LimitReader(tr, file.Size+1))
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+
+		if size != file.Size || hex.EncodeToString(hash.Sum(nil)) != file.SHA256 {
+			return fmt.Errorf(i18n.G("Invalid bundle: %q doesn't match the bundle index"), hdr.Name)
+		}
+	}
+
+	if len(files) > 0 {
+		missing := slices.Sorted(maps.Keys(files))
+		return fmt.Errorf(i18n.G("Invalid bundle: missing entry %q"), missing[0])
+	}
+
+	return nil
+}
+
+// Run runs the actual command logic.
+func (c *cmdImageBundleImport) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	remote := conf.DefaultRemote
+	if len(args) > 1 {
+		remote, _, err = conf.ParseRemote(args[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = bundle.Close() }()
+
+	tr := tar.NewReader(bundle)
+
+	// Verify the signature.
+	signer, err := c.readEntry(tr, imageBundleSigner)
+	if err != nil {
+		return err
+	}
+
+	index, err := c.readEntry(tr, imageBundleIndex)
+	if err != nil {
+		return err
+	}
+
+	signature, err := c.readEntry(tr, imageBundleSignature)
+	if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode(signer)
+	if block == nil {
+		return errors.New(i18n.G("Invalid bundle: bad signer certificate"))
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Invalid bundle: bad signer certificate: %w"), err)
+	}
+
+	err = imageBundleVerify(cert, index, signature)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkSigner(cert)
+	if err != nil {
+		return err
+	}
+
+	manifest := imageBundleManifest{}
+	err = yaml.Unmarshal(index, &manifest)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Invalid bundle: %w"), err)
+	}
+
+	if manifest.Version != imageBundleVersion {
+		return fmt.Errorf(i18n.G("Unsupported bundle version %d"), manifest.Version)
+	}
+
+	for _, image := range manifest.Images {
+		_, err := hex.DecodeString(image.Fingerprint)
+		if err != nil || len(image.Fingerprint) != 64 {
+			return fmt.Errorf(i18n.G("Invalid bundle: bad image fingerprint %q"), image.Fingerprint)
+		}
+
+		for _, file := range image.Files {
+			if path.Dir(file.Name) != "images" || !strings.HasPrefix(path.Base(file.Name), image.Fingerprint) {
+				return fmt.Errorf(i18n.G("Invalid bundle: bad entry name %q"), file.Name)
+			}
+		}
+	}
+
+	// Verify the content.
+	tmpDir, err := os.MkdirTemp("", "incus_bundle_")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	err = c.extract(tr, manifest, tmpDir)
+	if err != nil {
+		return err
+	}
+
+	// Check for conflicting aliases before changing anything.
+	existingAliases, err := d.GetImageAliases()
+	if err != nil {
+		return err
+	}
+
+	aliasTargets := map[string]api.ImageAliasesEntry{}
+	for _, alias := range existingAliases {
+		aliasTargets[alias.Name] = alias
+	}
+
+	for _, image := range manifest.Images {
+		for _, alias := range image.Aliases {
+			existing, ok := aliasTargets[alias.Name]
+			if ok && existing.Target != image.Fingerprint && !c.flagReuse {
+				return fmt.Errorf(i18n.G("Image alias %q already exists, use --reuse to move it"), alias.Name)
+			}
+		}
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Create the missing profiles first as the images refer to them.
+	if !c.flagNoProfiles {
+		for _, profile := range manifest.Profiles {
+			_, _, err := d.GetProfile(profile.Name)
+			if err == nil {
+				continue
+			}
+
+			if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			err = d.CreateProfile(profile)
+			if err != nil {
+				return fmt.Errorf(i18n.G("Failed creating profile %q: %w"), profile.Name, err)
+			}
+
+			reverter.Add(func() { _ = d.DeleteProfile(profile.Name) })
+		}
+	}
+
+	for _, image := range manifest.Images {
+		err = c.importImage(d, image, tmpDir, reverter)
+		if err != nil {
+			return err
+		}
+
+		for _, alias := range image.Aliases {
+			existing, ok := aliasTargets[alias.Name]
+			if ok && existing.Target == image.Fingerprint {
+				continue
+			}
+
+			if ok {
+				err = d.UpdateImageAlias(alias.Name, api.ImageAliasesEntryPut{Description: alias.Description, Target: image.Fingerprint}, "")
+				if err != nil {
+					return fmt.Errorf(i18n.G("Failed moving alias %q: %w"), alias.Name, err)
+				}
+
+				reverter.Add(func() { _ = d.UpdateImageAlias(alias.Name, existing.ImageAliasesEntryPut, "") })
+				continue
+			}
+
+			aliasPost := api.ImageAliasesPost{}
+			aliasPost.Name = alias.Name
+			aliasPost.Description = alias.Description
+			aliasPost.Target = image.Fingerprint
+			err = d.CreateImageAlias(aliasPost)
+			if err != nil {
+				return fmt.Errorf(i18n.G("Failed to create alias %s: %w"), alias.Name, err)
+			}
+
+			reverter.Add(func() { _ = d.DeleteImageAlias(alias.Name) })
+		}
+	}
+
+	reverter.Success()
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Bundle imported with %d images")+"\n", len(manifest.Images))
+	}
+
+	return nil
+}
+
+// importImage uploads a bundled image unless the server already has it.
+func (c *cmdImageBundleImport) importImage(d incus.InstanceServer, image imageBundleImage, tmpDir string, reverter *revert.Reverter) error {
+	_, _, err := d.GetImage(image.Fingerprint)
+	if err == nil {
+		return nil
+	}
+
+	if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: fmt.Sprintf(i18n.G("Importing image %s: %%s"), image.Fingerprint[:12]),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	createArgs := &incus.ImageCreateArgs{
+		ProgressHandler: progress.UpdateProgress,
+		Type:            image.Type,
+	}
+
+	for _, file := range image.Files {
+		f, err := os.Open(filepath.Join(tmpDir, filepath.Base(file.Name)))
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		if file.Rootfs {
+			createArgs.RootfsFile = f
+			createArgs.RootfsName = file.Filename
+		} else {
+			createArgs.MetaFile = f
+			createArgs.MetaName = file.Filename
+		}
+	}
+
+	req := api.ImagesPost{}
+	req.Filename = createArgs.MetaName
+	req.Public = image.Public
+	req.Properties = image.Properties
+	req.Profiles = image.Profiles
+	if c.flagNoProfiles {
+		req.Profiles = nil
+	}
+
+	op, err := d.CreateImage(req, createArgs)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	fingerprint, _ := op.Get().Metadata["fingerprint"].(string)
+	if fingerprint != "" {
+		reverter.Add(func() { _ = deleteImage(d, fingerprint) })
+	}
+
+	if fingerprint != image.Fingerprint {
+		return fmt.Errorf(i18n.G("Imported image has fingerprint %q instead of %q"), fingerprint, image.Fingerprint)
+	}
+
+	return nil
+}
+
+// deleteImage deletes an image, waiting for the removal to complete.
+func deleteImage(d incus.InstanceServer, fingerprint string) error {
+	op, err := d.DeleteImage(fingerprint)
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+func TestImageBundleSignature(t *testing.T) {
+	certPEM, keyPEM, err := localtls.GenerateMemCert(true, false)
+	require.NoError(t, err)
+
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	require.NoError(t, err)
+
+	index := []byte("version: 1\n")
+
+	signature, err := imageBundleSign(keyPair, index)
+	require.NoError(t, err)
+
+	assert.NoError(t, imageBundleVerify(cert, index, signature))
+	assert.Error(t, imageBundleVerify(cert, []byte("version: 2\n"), signature))
+}
@@ -719,7 +719,13 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, name string, showLog bool
 		// Memory usage
 		memoryInfo := ""
 		if inst.State.Memory.Usage != 0 {
-			memoryInfo += fmt.Sprintf("    %s: %s\n", i18n.G("Memory (current)"), units.GetByteSizeStringIEC(inst.State.Memory.Usage, 2))
+			memoryLimited := inst.Type == string(api.InstanceTypeVM) || inst.ExpandedConfig["limits.memory"] != ""
+			if memoryLimited && inst.State.Memory.Total > 0 {
+				ratio := float64(inst.State.Memory.Usage) / float64(inst.State.Memory.Total) * 100
+				memoryInfo += fmt.Sprintf("    %s: %s "+i18n.G("(%.0f%% of %s limit)")+"\n", i18n.G("Memory (current)"), units.GetByteSizeStringIEC(inst.State.Memory.Usage, 2), ratio, units.GetByteSizeStringIEC(inst.State.Memory.Total, 2))
+			} else {
+				memoryInfo += fmt.Sprintf("    %s: %s\n", i18n.G("Memory (current)"), units.GetByteSizeStringIEC(inst.State.Memory.Usage, 2))
+			}
 		}
 
 		if inst.State.Memory.UsagePeak != 0 {
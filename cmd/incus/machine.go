@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	config "github.com/lxc/incus/v6/shared/cliconfig"
+)
+
+// machineName is the name of the Lima instance running the local server on non-Linux systems.
+const machineName = "incus"
+
+// limaInstance is an entry of "limactl list --json".
+type limaInstance struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Dir    string `json:"dir"`
+	CPUs   int    `json:"cpus"`
+	Memory int64  `json:"memory"`
+	Disk   int64  `json:"disk"`
+	Arch   string `json:"arch"`
+}
+
+// machineGet returns the Lima instance running the local server, or nil if it doesn't exist.
+func machineGet() (*limaInstance, error) {
+	out, err := exec.Command("limactl", "list", "--json").Output()
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Failed listing Lima instances: %w"), err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		inst := limaInstance{}
+		err := json.Unmarshal(scanner.Bytes(), &inst)
+		if err != nil {
+			return nil, err
+		}
+
+		if inst.Name == machineName {
+			return &inst, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// machineSocket returns the path of the forwarded unix socket of the local server.
+func machineSocket(inst *limaInstance) string {
+	return filepath.Join(inst.Dir, "sock", "incus.sock")
+}
+
+// machineLimactl runs limactl with its output shown to the user.
+func machineLimactl(args ...string) error {
+	cmd := exec.Command("limactl", args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// machineStart creates the Lima instance running the local server if missing and starts it.
+func machineStart(cpus int, memory string, disk string) (*limaInstance, error) {
+	_, err := exec.LookPath("limactl")
+	if err != nil {
+		return nil, errors.New(i18n.G("Managing a local server requires Lima (https://lima-vm.io)"))
+	}
+
+	inst, err := machineGet()
+	if err != nil {
+		return nil, err
+	}
+
+	if inst != nil && inst.Status == "Running" {
+		return inst, nil
+	}
+
+	args := []string{"start", "--tty=false"}
+	if inst == nil {
+		fmt.Fprintln(os.Stderr, i18n.G("Creating the local Incus server, this may take a few minutes"))
+
+		// The Lima incus template forwards the unix socket of the server to the host.
+		args = append(args, "--name="+machineName)
+		if runtime.GOOS == "windows" {
+			args = append(args, "--vm-type=wsl2")
+		}
+
+		if cpus > 0 {
+			args = append(args, "--cpus="+strconv.Itoa(cpus))
+		}
+
+		if memory != "" {
+			args = append(args, "--memory="+memory)
+		}
+
+		if disk != "" {
+			args = append(args, "--disk="+disk)
+		}
+
+		args = append(args, "template://incus")
+	} else {
+		fmt.Fprintln(os.Stderr, i18n.G("Starting the local Incus server"))
+		args = append(args, machineName)
+	}
+
+	err = machineLimactl(args...)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Failed starting the local Incus server: %w"), err)
+	}
+
+	return machineGet()
+}
+
+// localMachine returns the unix socket of the local server, which gets created and started when needed.
+func (c *cmdGlobal) localMachine() (string, error) {
+	_, err := exec.LookPath("limactl")
+	if err != nil {
+		return "", config.ErrNotLinux
+	}
+
+	inst, err := machineStart(0, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	if inst == nil {
+		return "", errors.New(i18n.G("The local Incus server couldn't be found after starting it"))
+	}
+
+	return machineSocket(inst), nil
+}
+
+type cmdMachine struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdMachine) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("machine")
+	cmd.Short = i18n.G("Manage the local server virtual machine")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage the local server virtual machine
+
+On systems that can't run Linux instances natively, like macOS and Windows,
+the "local" remote is served by a virtual machine managed through Lima.
+The virtual machine is created and started automatically when needed.`))
+
+	// Start
+	machineStartCmd := cmdMachineStart{global: c.global}
+	cmd.AddCommand(machineStartCmd.Command())
+
+	// Stop
+	machineStopCmd := cmdMachineStop{global: c.global}
+	cmd.AddCommand(machineStopCmd.Command())
+
+	// Delete
+	machineDeleteCmd := cmdMachineDelete{global: c.global}
+	cmd.AddCommand(machineDeleteCmd.Command())
+
+	// Info
+	machineInfoCmd := cmdMachineInfo{global: c.global}
+	cmd.AddCommand(machineInfoCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Start.
+type cmdMachineStart struct {
+	global *cmdGlobal
+
+	flagCPUs   int
+	flagMemory string
+	flagDisk   string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdMachineStart) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("start")
+	cmd.Short = i18n.G("Create and start the local server virtual machine")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create and start the local server virtual machine
+
+The resources of the virtual machine can only be set when creating it.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus machine start --cpus 4 --memory 8 --disk 100
+    Create a virtual machine with 4 CPUs, 8GiB of memory and a 100GiB disk.`))
+
+	cmd.Flags().IntVar(&c.flagCPUs, "cpus", 0, i18n.G("Number of CPUs")+"``")
+	cmd.Flags().StringVar(&c.flagMemory, "memory", "", i18n.G("Memory in GiB")+"``")
+	cmd.Flags().StringVar(&c.flagDisk, "disk", "", i18n.G("Disk size in GiB")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdMachineStart) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 0)
+	if exit {
+		return err
+	}
+
+	_, err = machineStart(c.flagCPUs, c.flagMemory, c.flagDisk)
+
+	return err
+}
+
+// Stop.
+type cmdMachineStop struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdMachineStop) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("stop")
+	cmd.Short = i18n.G("Stop the local server virtual machine")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Stop the local server virtual machine`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdMachineStop) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 0)
+	if exit {
+		return err
+	}
+
+	inst, err := machineGet()
+	if err != nil {
+		return err
+	}
+
+	if inst == nil || inst.Status != "Running" {
+		return errors.New(i18n.G("The local server virtual machine isn't running"))
+	}
+
+	return machineLimactl("stop", machineName)
+}
+
+// Delete.
+type cmdMachineDelete struct {
+	global *cmdGlobal
+
+	flagForce bool
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdMachineDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete")
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Delete the local server virtual machine")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete the local server virtual machine
+
+All the instances, images and volumes of the local server are deleted with it.`))
+
+	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("Don't require user confirmation"))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdMachineDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 0)
+	if exit {
+		return err
+	}
+
+	inst, err := machineGet()
+	if err != nil {
+		return err
+	}
+
+	if inst == nil {
+		return errors.New(i18n.G("The local server virtual machine doesn't exist"))
+	}
+
+	if !c.flagForce {
+		confirm, err := c.global.asker.AskBool(i18n.G("All the instances of the local server will be deleted, continue?")+" (yes/no) [default=no]: ", "no")
+		if err != nil {
+			return err
+		}
+
+		if !confirm {
+			return nil
+		}
+	}
+
+	return machineLimactl("delete", "--force", machineName)
+}
+
+// Info.
+type cmdMachineInfo struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdMachineInfo) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("info")
+	cmd.Short = i18n.G("Show the local server virtual machine")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the local server virtual machine`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdMachineInfo) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 0)
+	if exit {
+		return err
+	}
+
+	inst, err := machineGet()
+	if err != nil {
+		return err
+	}
+
+	if inst == nil {
+		return errors.New(i18n.G("The local server virtual machine doesn't exist"))
+	}
+
+	fmt.Printf(i18n.G("Status: %s")+"\n", inst.Status)
+	fmt.Printf(i18n.G("Architecture: %s")+"\n", inst.Arch)
+	fmt.Printf(i18n.G("CPUs: %d")+"\n", inst.CPUs)
+	fmt.Printf(i18n.G("Memory: %dGiB")+"\n", inst.Memory/1024/1024/1024)
+	fmt.Printf(i18n.G("Disk: %dGiB")+"\n", inst.Disk/1024/1024/1024)
+	fmt.Printf(i18n.G("Socket: %s")+"\n", machineSocket(inst))
+
+	return nil
+}
@@ -139,6 +139,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	adminCmd := cmdAdmin{global: &globalCmd}
 	app.AddCommand(adminCmd.Command())
 
+	// backup-point sub-command
+	backupPointCmd := cmdBackupPoint{global: &globalCmd}
+	app.AddCommand(backupPointCmd.Command())
+
 	// cluster sub-command
 	clusterCmd := cmdCluster{global: &globalCmd}
 	app.AddCommand(clusterCmd.Command())
@@ -195,6 +199,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	listCmd := cmdList{global: &globalCmd}
 	app.AddCommand(listCmd.Command())
 
+	// machine sub-command
+	machineCmd := cmdMachine{global: &globalCmd}
+	app.AddCommand(machineCmd.Command())
+
 	// manpage sub-command
 	manpageCmd := cmdManpage{global: &globalCmd}
 	app.AddCommand(manpageCmd.Command())
@@ -235,6 +243,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	queryCmd := cmdQuery{global: &globalCmd}
 	app.AddCommand(queryCmd.Command())
 
+	// report sub-command
+	reportCmd := cmdReport{global: &globalCmd}
+	app.AddCommand(reportCmd.Command())
+
 	// rebuild sub-command
 	rebuildCmd := cmdRebuild{global: &globalCmd}
 	app.AddCommand(rebuildCmd.Command())
@@ -338,14 +350,27 @@ func main() {
 	}
 
 	// Run the main command and handle errors
-	err = app.Execute()
+	cmd, err := app.ExecuteC()
 	if err != nil {
+		// Queue mutating commands if their remote is temporarily unreachable
+		var unreachableErr *config.UnreachableError
+		if errors.As(err, &unreachableErr) {
+			queued, queueErr := globalCmd.queueCommand(cmd, unreachableErr.Remote)
+			if queueErr != nil {
+				fmt.Fprintf(os.Stderr, i18n.G("Failed to queue the command: %v\n"), queueErr)
+			} else if queued {
+				fmt.Fprintf(os.Stderr, i18n.G("Remote %q is unreachable, the command was queued (replay it with \"incus remote queue replay\")")+"\n", unreachableErr.Remote)
+				os.Exit(0)
+			}
+		}
+
 		// Handle non-Linux systems
 		if errors.Is(err, config.ErrNotLinux) {
 			fmt.Fprintf(os.Stderr, "%s", i18n.G(`This client hasn't been configured to use a remote server yet.
 As your platform can't run native Linux instances, you must connect to a remote server.
 
-If you already added a remote server, make it the default with "incus remote switch NAME".`)+"\n")
+If you already added a remote server, make it the default with "incus remote switch NAME".
+Alternatively, install Lima (https://lima-vm.io) to have a local server managed through "incus machine".`)+"\n")
 			os.Exit(1)
 		}
 
@@ -422,6 +447,9 @@ func (c *cmdGlobal) PreRun(cmd *cobra.Command, _ []string) error {
 		c.conf.ProjectOverride = os.Getenv("INCUS_PROJECT")
 	}
 
+	// Setup the local server of non-Linux systems
+	c.conf.LocalMachine = c.localMachine
+
 	// Setup password helper
 	c.conf.PromptPassword = func(filename string) (string, error) {
 		return c.asker.AskPasswordOnce(fmt.Sprintf(i18n.G("Password for %s: "), filename)), nil
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"slices"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -25,6 +26,7 @@ type cmdMonitor struct {
 	flagLogLevel    string
 	flagAllProjects bool
 	flagFormat      string
+	flagSince       string
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -44,7 +46,13 @@ incus monitor --pretty --type=logging --loglevel=info
     Show a pretty log of messages with info level or higher.
 
 incus monitor --type=lifecycle
-    Only show lifecycle events.`))
+    Only show lifecycle events.
+
+incus monitor --type=lifecycle --since=1h
+    Show the lifecycle events of the last hour, followed by the new ones.
+
+incus monitor --type=storage
+    Only show storage events, like volume resizes or storage pools crossing their usage threshold.`))
 	cmd.Hidden = true
 
 	cmd.RunE = c.Run
@@ -53,6 +61,7 @@ incus monitor --type=lifecycle
 	cmd.Flags().StringArrayVar(&c.flagType, "type", nil, i18n.G("Event type to listen for")+"``")
 	cmd.Flags().StringVar(&c.flagLogLevel, "loglevel", "", i18n.G("Minimum level for log messages (only available when using pretty format)")+"``")
 	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "yaml", i18n.G("Format (json|pretty|yaml)")+"``")
+	cmd.Flags().StringVar(&c.flagSince, "since", "", i18n.G("Replay the recorded lifecycle events since that time (RFC3339 time or duration like 1h)")+"``")
 
 	return cmd
 }
@@ -101,15 +110,17 @@ func (c *cmdMonitor) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	var listener *incus.EventListener
-	if c.flagAllProjects {
-		listener, err = d.GetEventsAllProjects()
-	} else {
-		listener, err = d.GetEvents()
-	}
+	var since time.Time
+	if c.flagSince != "" {
+		since, err = time.Parse(time.RFC3339, c.flagSince)
+		if err != nil {
+			duration, durationErr := time.ParseDuration(c.flagSince)
+			if durationErr != nil {
+				return fmt.Errorf(i18n.G("Invalid since time %q: %w"), c.flagSince, err)
+			}
 
-	if err != nil {
-		return err
+			since = time.Now().Add(-duration)
+		}
 	}
 
 	logLevel := logrus.DebugLevel
@@ -211,9 +222,27 @@ func (c *cmdMonitor) Run(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s\n\n", render)
 	}
 
-	_, err = listener.AddHandler(c.flagType, handler)
-	if err != nil {
-		return err
+	var listener *incus.EventListener
+	if !since.IsZero() {
+		listener, err = d.GetEventsSince(since, c.flagAllProjects, c.flagType, handler)
+		if err != nil {
+			return err
+		}
+	} else {
+		if c.flagAllProjects {
+			listener, err = d.GetEventsAllProjects()
+		} else {
+			listener, err = d.GetEvents()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = listener.AddHandler(c.flagType, handler)
+		if err != nil {
+			return err
+		}
 	}
 
 	go func() {
@@ -1,22 +1,35 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	incus "github.com/lxc/incus/v6/client"
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 type cmdMove struct {
 	global *cmdGlobal
 
 	flagNoProfiles        bool
+	flagCopyProfiles      bool
+	flagRelay             string
 	flagProfile           []string
 	flagConfig            []string
 	flagInstanceOnly      bool
@@ -26,7 +39,14 @@ type cmdMove struct {
 	flagStorage           string
 	flagTarget            string
 	flagTargetProject     string
+	flagTargetPool        []string
+	flagTargetNetwork     []string
+	flagMapping           string
 	flagAllowInconsistent bool
+	flagCheck             bool
+	flagLiveFallback      bool
+	flagWithVolumes       bool
+	flagVerify            bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -42,6 +62,7 @@ Transfer modes (--mode):
  - pull: Target server pulls the data from the source server (source must listen on network)
  - push: Source server pushes the data to the target server (target must listen on network)
  - relay: The CLI connects to both source and server and proxies the data (both source and target must listen on network)
+ - relay with --relay: Another server connects to both source and target and proxies the data (both source and target must be reachable from it)
 
 The pull transfer mode is the default as it is compatible with all server versions.
 `))
@@ -53,20 +74,35 @@ incus move <old name> <new name> [--instance-only]
     Rename a local instance.
 
 incus move <instance>/<old snapshot name> <instance>/<new snapshot name>
-    Rename a snapshot.`))
+    Rename a snapshot.
+
+incus move <instance> <remote>: --check
+    Check whether an instance can be moved to another server, without moving it.
+
+incus move <instance> <remote>: --with-volumes
+    Move a stopped instance to another server along with its attached custom volumes.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().StringArrayVarP(&c.flagConfig, "config", "c", nil, i18n.G("Config key/value to apply to the target instance")+"``")
 	cmd.Flags().StringArrayVarP(&c.flagDevice, "device", "d", nil, i18n.G("New key/value to apply to a specific device")+"``")
 	cmd.Flags().StringArrayVarP(&c.flagProfile, "profile", "p", nil, i18n.G("Profile to apply to the target instance")+"``")
 	cmd.Flags().BoolVar(&c.flagNoProfiles, "no-profiles", false, i18n.G("Unset all profiles on the target instance"))
+	cmd.Flags().BoolVar(&c.flagCopyProfiles, "copy-profiles", false, i18n.G("Copy the profiles missing on the target from the source"))
 	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false, i18n.G("Move the instance without its snapshots"))
 	cmd.Flags().StringVar(&c.flagMode, "mode", moveDefaultMode, i18n.G("Transfer mode. One of pull, push or relay.")+"``")
+	cmd.Flags().StringVar(&c.flagRelay, "relay", "", i18n.G("Remote server relaying the transfer instead of the client (implies --mode=relay)")+"``")
 	cmd.Flags().BoolVar(&c.flagStateless, "stateless", false, i18n.G("Copy a stateful instance stateless"))
 	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", i18n.G("Storage pool name")+"``")
 	cmd.Flags().StringVar(&c.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.Flags().StringVar(&c.flagTargetProject, "target-project", "", i18n.G("Copy to a project different from the source")+"``")
+	cmd.Flags().StringArrayVar(&c.flagTargetPool, "target-pool", nil, i18n.G("Storage pool to use on the target in place of a source pool (<source>=<target>)")+"``")
+	cmd.Flags().StringArrayVar(&c.flagTargetNetwork, "target-network", nil, i18n.G("Network to use on the target in place of a source network (<source>=<target>)")+"``")
+	cmd.Flags().StringVar(&c.flagMapping, "mapping", "", i18n.G("YAML file mapping source pools, networks and profiles to those of the target")+"``")
 	cmd.Flags().BoolVar(&c.flagAllowInconsistent, "allow-inconsistent", false, i18n.G("Ignore copy errors for volatile files"))
+	cmd.Flags().BoolVar(&c.flagCheck, "check", false, i18n.G("Only check whether the instance can be moved"))
+	cmd.Flags().BoolVar(&c.flagLiveFallback, "live-fallback", false, i18n.G("Stop, move and start the instance if its live migration fails"))
+	cmd.Flags().BoolVar(&c.flagWithVolumes, "with-volumes", false, i18n.G("Move the custom volumes attached to the instance along with it"))
+	cmd.Flags().BoolVar(&c.flagVerify, "verify", false, i18n.G("Compare the checksums of the volumes once transferred"))
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -127,10 +163,18 @@ func (c *cmdMove) Run(cmd *cobra.Command, args []string) error {
 	// course, this changing of hostname isn't supported right now, so this
 	// simply won't work).
 	if sourceRemote == destRemote && c.flagTarget == "" && c.flagStorage == "" && c.flagTargetProject == "" {
-		if c.flagConfig != nil || c.flagDevice != nil || c.flagProfile != nil || c.flagNoProfiles {
+		if c.flagCheck {
+			return errors.New(i18n.G("--check can only be used when moving instances to another server, pool or project"))
+		}
+
+		if c.flagConfig != nil || c.flagDevice != nil || c.flagProfile != nil || c.flagNoProfiles || c.flagTargetPool != nil || c.flagTargetNetwork != nil || c.flagMapping != "" {
 			return errors.New(i18n.G("Can't override configuration or profiles in local rename"))
 		}
 
+		if c.flagWithVolumes {
+			return errors.New(i18n.G("--with-volumes can only be used when moving instances to another server, pool or project"))
+		}
+
 		source, err := conf.GetInstanceServer(sourceRemote)
 		if err != nil {
 			return err
@@ -160,7 +204,12 @@ func (c *cmdMove) Run(cmd *cobra.Command, args []string) error {
 		}
 
 		// Check if asked for specific client mode.
-		if c.flagMode != moveDefaultMode {
+		if c.flagMode != moveDefaultMode || c.flagRelay != "" {
+			return false
+		}
+
+		// Resource mappings are applied by the client.
+		if c.flagTargetPool != nil || c.flagTargetNetwork != nil || c.flagMapping != "" {
 			return false
 		}
 
@@ -198,11 +247,59 @@ func (c *cmdMove) Run(cmd *cobra.Command, args []string) error {
 		return true
 	}()
 
+	if c.flagCheck {
+		if isServerSide {
+			return c.moveInstance(sourceResource, destResource, stateful)
+		}
+
+		return c.checkMove(sourceResource, destResource, stateful)
+	}
+
+	if !c.flagWithVolumes {
+		return c.move(cmd, args, sourceResource, destResource, isServerSide, stateful, mode)
+	}
+
+	// The attached volumes are copied first as the instance can't be created on the target without them.
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	volumes, err := c.copyAttachedVolumes(sourceRemote, sourceName, destRemote, mode, reverter)
+	if err != nil {
+		return err
+	}
+
+	err = c.move(cmd, args, sourceResource, destResource, isServerSide, stateful, mode)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	// Remove the original volumes now that the instance moved.
+	for _, vol := range volumes {
+		err = vol.server.DeleteStoragePoolVolume(vol.pool, "custom", vol.name)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed deleting source volume %q after moving it: %w"), vol.name, err)
+		}
+	}
+
+	return nil
+}
+
+// move moves the instance, either through the migration API or as a copy followed by a delete.
+func (c *cmdMove) move(cmd *cobra.Command, args []string, sourceResource string, destResource string, isServerSide bool, stateful bool, mode string) error {
+	conf := c.global.conf
+
 	// Support for server-side move in clusters.
 	if isServerSide {
 		return c.moveInstance(sourceResource, destResource, stateful)
 	}
 
+	sourceRemote, sourceName, err := conf.ParseRemote(sourceResource)
+	if err != nil {
+		return err
+	}
+
 	cpy := cmdCopy{}
 	cpy.global = c.global
 	cpy.flagTarget = c.flagTarget
@@ -211,15 +308,39 @@ func (c *cmdMove) Run(cmd *cobra.Command, args []string) error {
 	cpy.flagDevice = c.flagDevice
 	cpy.flagProfile = c.flagProfile
 	cpy.flagNoProfiles = c.flagNoProfiles
+	cpy.flagCopyProfiles = c.flagCopyProfiles
+	cpy.flagRelay = c.flagRelay
 	cpy.flagAllowInconsistent = c.flagAllowInconsistent
+	cpy.flagVerify = c.flagVerify
+	cpy.flagTargetPool = c.flagTargetPool
+	cpy.flagTargetNetwork = c.flagTargetNetwork
+	cpy.flagMapping = c.flagMapping
 
 	instanceOnly := c.flagInstanceOnly
 
 	// A move is just a copy followed by a delete; however, we want to
 	// keep the volatile entries around since we are moving the instance.
+	fallback := false
+	fallbackStateful := false
+
 	err = cpy.copyInstance(conf, sourceResource, destResource, true, -1, stateful, instanceOnly, mode, c.flagStorage, true)
 	if err != nil {
-		return err
+		if !stateful || !c.flagLiveFallback {
+			return err
+		}
+
+		// Fall back to moving the instance while stopped.
+		fallbackStateful, err = c.stopForFallback(sourceRemote, sourceName, err)
+		if err != nil {
+			return err
+		}
+
+		fallback = true
+
+		err = cpy.copyInstance(conf, sourceResource, destResource, true, -1, false, instanceOnly, mode, c.flagStorage, true)
+		if err != nil {
+			return err
+		}
 	}
 
 	del := cmdDelete{global: c.global}
@@ -230,6 +351,205 @@ func (c *cmdMove) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("Failed to delete original instance after copying it: %w"), err)
 	}
 
+	// Start the instance again if it had to be stopped.
+	if fallback {
+		return c.startAfterFallback(destResource, sourceName, fallbackStateful)
+	}
+
+	return nil
+}
+
+// moveVolume is a custom volume moved along with an instance.
+type moveVolume struct {
+	server incus.InstanceServer
+	pool   string
+	name   string
+}
+
+// copyAttachedVolumes copies the custom volumes attached to the instance to the destination.
+// Volumes which can be used in place (on shared storage of the same cluster) are skipped.
+// The copies are removed by the reverter, and the returned source volumes are to be removed once the instance moved.
+func (c *cmdMove) copyAttachedVolumes(sourceRemote string, sourceName string, destRemote string, mode string, reverter *revert.Reverter) ([]moveVolume, error) {
+	conf := c.global.conf
+
+	source, err := conf.GetInstanceServer(sourceRemote)
+	if err != nil {
+		return nil, err
+	}
+
+	dest, err := conf.GetInstanceServer(destRemote)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.flagTarget != "" {
+		dest = dest.UseTarget(c.flagTarget)
+	}
+
+	if c.flagTargetProject != "" {
+		dest = dest.UseProject(c.flagTargetProject)
+	}
+
+	inst, _, err := source.GetInstance(sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Volumes can't be consistently copied while in use.
+	if inst.StatusCode != api.Stopped && inst.StatusCode != api.Suspended {
+		return nil, errors.New(i18n.G("The instance must be stopped to move its attached volumes"))
+	}
+
+	mapping, err := parseResourceMapping(c.flagMapping, c.flagTargetPool, c.flagTargetNetwork)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := []moveVolume{}
+	for _, devName := range slices.Sorted(maps.Keys(inst.ExpandedDevices)) {
+		dev := inst.ExpandedDevices[devName]
+		if dev["type"] != "disk" || dev["pool"] == "" || dev["source"] == "" || dev["path"] == "/" {
+			continue
+		}
+
+		vol, _, err := source.GetStoragePoolVolume(dev["pool"], "custom", dev["source"])
+		if err != nil {
+			return nil, fmt.Errorf(i18n.G("Failed getting the volume of device %q: %w"), devName, err)
+		}
+
+		// Volumes shared with other instances must stay where they are.
+		for _, entry := range vol.UsedBy {
+			u, err := url.Parse(entry)
+			if err != nil || !strings.HasPrefix(u.Path, "/1.0/instances/") {
+				continue
+			}
+
+			if path.Base(u.Path) != sourceName {
+				return nil, fmt.Errorf(i18n.G("Volume %q is also used by instance %q"), vol.Name, path.Base(u.Path))
+			}
+		}
+
+		targetPool := dev["pool"]
+		if mapping.Pools[targetPool] != "" {
+			targetPool = mapping.Pools[targetPool]
+		}
+
+		volSource := source
+		localVolume := vol.Location != "" && vol.Location != "none"
+		if localVolume {
+			volSource = source.UseTarget(vol.Location)
+		}
+
+		// The volume is already available to the instance when it stays on the same server, pool and project.
+		if sourceRemote == destRemote && c.flagTargetProject == "" && targetPool == dev["pool"] && (!localVolume || c.flagTarget == "" || c.flagTarget == vol.Location) {
+			continue
+		}
+
+		args := &incus.StoragePoolVolumeCopyArgs{
+			Name:       vol.Name,
+			Mode:       mode,
+			VolumeOnly: c.flagInstanceOnly,
+		}
+
+		op, err := dest.CopyStoragePoolVolume(targetPool, volSource, dev["pool"], *vol, args)
+		if err != nil {
+			return nil, err
+		}
+
+		progress := cli.ProgressRenderer{
+			Format: fmt.Sprintf(i18n.G("Transferring volume %s: %%s"), vol.Name),
+			Quiet:  c.global.flagQuiet,
+		}
+
+		_, err = op.AddHandler(progress.UpdateOp)
+		if err != nil {
+			progress.Done("")
+			return nil, err
+		}
+
+		err = cli.CancelableWait(op, &progress)
+		if err != nil {
+			progress.Done("")
+			return nil, fmt.Errorf(i18n.G("Failed moving volume %q: %w"), vol.Name, err)
+		}
+
+		progress.Done("")
+
+		reverter.Add(func() { _ = dest.DeleteStoragePoolVolume(targetPool, "custom", vol.Name) })
+
+		volumes = append(volumes, moveVolume{server: volSource, pool: dev["pool"], name: vol.Name})
+	}
+
+	return volumes, nil
+}
+
+// stopForFallback stops the source instance after its live migration failed, so it can be moved while stopped.
+// It returns whether the instance was stopped statefully.
+func (c *cmdMove) stopForFallback(remote string, name string, migrationErr error) (bool, error) {
+	source, err := c.global.conf.GetInstanceServer(remote)
+	if err != nil {
+		return false, err
+	}
+
+	inst, _, err := source.GetInstance(name)
+	if err != nil {
+		return false, err
+	}
+
+	// Only fall back if the instance survived the failed migration.
+	if inst.StatusCode != api.Running {
+		return false, migrationErr
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Live migration failed, moving the instance while stopped: %v")+"\n", migrationErr)
+	}
+
+	stateful := inst.Type == string(api.InstanceTypeVM) && util.IsTrue(inst.ExpandedConfig["migration.stateful"])
+
+	op, err := source.UpdateInstanceState(name, api.InstanceStatePut{Action: "stop", Stateful: stateful, Timeout: -1}, "")
+	if err != nil {
+		return false, err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return false, fmt.Errorf(i18n.G("Failed stopping instance after live migration failure: %w"), err)
+	}
+
+	return stateful, nil
+}
+
+// startAfterFallback starts the moved instance again once a fallback move completed.
+func (c *cmdMove) startAfterFallback(destResource string, sourceName string, stateful bool) error {
+	destRemote, destName, err := c.global.conf.ParseRemote(destResource)
+	if err != nil {
+		return err
+	}
+
+	if destName == "" {
+		destName = sourceName
+	}
+
+	dest, err := c.global.conf.GetInstanceServer(destRemote)
+	if err != nil {
+		return err
+	}
+
+	if c.flagTargetProject != "" {
+		dest = dest.UseProject(c.flagTargetProject)
+	}
+
+	op, err := dest.UpdateInstanceState(destName, api.InstanceStatePut{Action: "start", Stateful: stateful}, "")
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed starting the moved instance: %w"), err)
+	}
+
 	return nil
 }
 
@@ -282,6 +602,12 @@ func (c *cmdMove) moveInstance(sourceResource string, destResource string, state
 		Pool:         c.flagStorage,
 		Project:      c.flagTargetProject,
 		Live:         stateful,
+		LiveFallback: c.flagLiveFallback,
+		Verify:       c.flagVerify,
+	}
+
+	if c.flagLiveFallback && !source.HasExtension("instance_live_fallback") {
+		return errors.New(i18n.G("The server doesn't support falling back from live migrations"))
 	}
 
 	// Override profiles.
@@ -334,6 +660,16 @@ func (c *cmdMove) moveInstance(sourceResource string, destResource string, state
 		}
 	}
 
+	// Only check the move if requested.
+	if c.flagCheck {
+		report, err := source.CheckMigrateInstance(sourceName, req)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Migration API failure: %w"), err)
+		}
+
+		return c.renderCheck(report)
+	}
+
 	// Move the instance.
 	op, err := source.MigrateInstance(sourceName, req)
 	if err != nil {
@@ -346,7 +682,7 @@ func (c *cmdMove) moveInstance(sourceResource string, destResource string, state
 		Quiet:  c.global.flagQuiet,
 	}
 
-	_, err = op.AddHandler(progress.UpdateOp)
+	_, err = op.AddHandler(migrationProgressHandler(&progress))
 	if err != nil {
 		progress.Done("")
 		return err
@@ -364,5 +700,335 @@ func (c *cmdMove) moveInstance(sourceResource string, destResource string, state
 	return nil
 }
 
+// checkMove checks whether an instance can be moved in between servers, without moving it.
+func (c *cmdMove) checkMove(sourceResource string, destResource string, stateful bool) error {
+	conf := c.global.conf
+
+	sourceRemote, sourceName, err := conf.ParseRemote(sourceResource)
+	if err != nil {
+		return err
+	}
+
+	destRemote, destName, err := conf.ParseRemote(destResource)
+	if err != nil {
+		return err
+	}
+
+	if sourceName == "" {
+		return errors.New(i18n.G("You must specify a source instance name"))
+	}
+
+	if instance.IsSnapshot(sourceName) {
+		return errors.New(i18n.G("--check can't be used with snapshots"))
+	}
+
+	if destName == "" {
+		destName = sourceName
+	}
+
+	source, err := conf.GetInstanceServer(sourceRemote)
+	if err != nil {
+		return err
+	}
+
+	dest, err := conf.GetInstanceServer(destRemote)
+	if err != nil {
+		return err
+	}
+
+	if c.flagTargetProject != "" {
+		dest = dest.UseProject(c.flagTargetProject)
+	}
+
+	if c.flagTarget != "" {
+		if !dest.IsClustered() {
+			return errors.New(i18n.G("To use --target, the destination remote must be a cluster"))
+		}
+
+		dest = dest.UseTarget(c.flagTarget)
+	}
+
+	inst, _, err := source.GetInstance(sourceName)
+	if err != nil {
+		return err
+	}
+
+	deviceMap, err := parseDeviceOverrides(c.flagDevice)
+	if err != nil {
+		return err
+	}
+
+	mapping, err := parseResourceMapping(c.flagMapping, c.flagTargetPool, c.flagTargetNetwork)
+	if err != nil {
+		return err
+	}
+
+	// Work out the devices and profiles the instance will have on the target.
+	devices := map[string]map[string]string{}
+	for name, dev := range inst.Devices {
+		devices[name] = maps.Clone(dev)
+	}
+
+	profiles := mapping.apply(devices, inst.ExpandedDevices, inst.Profiles)
+	if len(c.flagProfile) > 0 {
+		profiles = c.flagProfile
+	} else if c.flagNoProfiles {
+		profiles = []string{}
+	}
+
+	expandedDevices := map[string]map[string]string{}
+	for name, dev := range inst.ExpandedDevices {
+		expandedDevices[name] = maps.Clone(dev)
+	}
+
+	maps.Copy(expandedDevices, devices)
+	for name, dev := range deviceMap {
+		if expandedDevices[name] == nil {
+			expandedDevices[name] = map[string]string{}
+		}
+
+		maps.Copy(expandedDevices[name], dev)
+	}
+
+	report := &api.InstanceMigrationCheck{Ready: true}
+	live := stateful && inst.StatusCode == api.Running
+
+	// Check the instance state.
+	if inst.StatusCode != api.Running {
+		report.Add("state", api.InstanceMigrationCheckOK, i18n.G("The instance is stopped"))
+	} else if !live {
+		report.Add("state", api.InstanceMigrationCheckWarning, i18n.G("The instance is running and will be moved without its runtime state"))
+	} else if inst.Type == string(api.InstanceTypeVM) && util.IsFalseOrEmpty(inst.ExpandedConfig["migration.stateful"]) {
+		report.Add("state", api.InstanceMigrationCheckError, i18n.G("Live migration of virtual machines requires migration.stateful to be enabled"))
+	} else {
+		report.Add("state", api.InstanceMigrationCheckOK, i18n.G("The instance is running and will be live migrated"))
+	}
+
+	// Check the target server.
+	destServer, _, err := dest.GetServer()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(destServer.Environment.Architectures, inst.Architecture) {
+		report.Add("architecture", api.InstanceMigrationCheckError, fmt.Sprintf(i18n.G("The target server doesn't support the %q architecture"), inst.Architecture))
+	} else {
+		report.Add("architecture", api.InstanceMigrationCheckOK, fmt.Sprintf(i18n.G("The target server supports the %q architecture"), inst.Architecture))
+	}
+
+	_, _, err = dest.GetInstance(destName)
+	if err == nil {
+		report.Add("name", api.InstanceMigrationCheckError, fmt.Sprintf(i18n.G("An instance named %q already exists on the target"), destName))
+	} else if api.StatusErrorCheck(err, http.StatusNotFound) {
+		report.Add("name", api.InstanceMigrationCheckOK, fmt.Sprintf(i18n.G("The name %q is available on the target"), destName))
+	} else {
+		return err
+	}
+
+	for _, profile := range profiles {
+		_, _, err := dest.GetProfile(profile)
+		if err != nil && c.flagCopyProfiles && api.StatusErrorCheck(err, http.StatusNotFound) {
+			report.Add("profiles", api.InstanceMigrationCheckWarning, fmt.Sprintf(i18n.G("Profile %q will be copied to the target"), profile))
+			continue
+		} else if err != nil {
+			report.Add("profiles", api.InstanceMigrationCheckError, fmt.Sprintf(i18n.G("Profile %q isn't available on the target: %v"), profile, err))
+			continue
+		}
+
+		report.Add("profiles", api.InstanceMigrationCheckOK, fmt.Sprintf(i18n.G("Profile %q is available on the target"), profile))
+	}
+
+	// Check the storage.
+	_, sourceRootDisk, _ := instance.GetRootDiskDevice(inst.ExpandedDevices)
+	_, destRootDisk, _ := instance.GetRootDiskDevice(expandedDevices)
+
+	destPool := c.flagStorage
+	if destPool == "" && destRootDisk != nil {
+		destPool = destRootDisk["pool"]
+	}
+
+	if sourceRootDisk != nil && destPool != "" {
+		sourcePoolInfo, _, err := source.GetStoragePool(sourceRootDisk["pool"])
+		if err != nil {
+			return err
+		}
+
+		destPoolInfo, _, err := dest.GetStoragePool(destPool)
+		if err != nil {
+			report.Add("storage", api.InstanceMigrationCheckError, fmt.Sprintf(i18n.G("Storage pool %q isn't available on the target: %v"), destPool, err))
+		} else if destPoolInfo.Status != api.StoragePoolStatusCreated {
+			report.Add("storage", api.InstanceMigrationCheckError, fmt.Sprintf(i18n.G("Storage pool %q isn't ready on the target"), destPool))
+		} else if sourcePoolInfo.Driver == destPoolInfo.Driver {
+			report.Add("storage", api.InstanceMigrationCheckOK, fmt.Sprintf(i18n.G("The data will be transferred using the optimized %q migration"), destPoolInfo.Driver))
+		} else {
+			report.Add("storage", api.InstanceMigrationCheckWarning, fmt.Sprintf(i18n.G("The data will be transferred using a generic migration from %q to %q"), sourcePoolInfo.Driver, destPoolInfo.Driver))
+		}
+	} else if destPool == "" {
+		report.Add("storage", api.InstanceMigrationCheckWarning, i18n.G("The instance has no root disk, the default profile of the target will be used"))
+	}
+
+	// Check the networks.
+	for _, devName := range slices.Sorted(maps.Keys(expandedDevices)) {
+		dev := expandedDevices[devName]
+		if dev["type"] != "nic" || dev["network"] == "" {
+			continue
+		}
+
+		network, _, err := dest.GetNetwork(dev["network"])
+		if err != nil {
+			report.Add("network", api.InstanceMigrationCheckError, fmt.Sprintf(i18n.G("Network %q of device %q isn't available on the target: %v"), dev["network"], devName, err))
+			continue
+		}
+
+		if network.Status != api.NetworkStatusCreated {
+			report.Add("network", api.InstanceMigrationCheckError, fmt.Sprintf(i18n.G("Network %q of device %q isn't ready on the target"), dev["network"], devName))
+			continue
+		}
+
+		report.Add("network", api.InstanceMigrationCheckOK, fmt.Sprintf(i18n.G("Network %q of device %q is available on the target"), dev["network"], devName))
+	}
+
+	// Live migrated virtual machines need the same CPU features on the target.
+	if live && inst.Type == string(api.InstanceTypeVM) {
+		missing, err := c.missingCPUFlags(source, dest)
+		if err != nil {
+			report.Add("cpu", api.InstanceMigrationCheckWarning, fmt.Sprintf(i18n.G("Couldn't compare the CPU features: %v"), err))
+		} else if len(missing) > 0 {
+			report.Add("cpu", api.InstanceMigrationCheckError, fmt.Sprintf(i18n.G("The target CPU is missing the following features: %s"), strings.Join(missing, ", ")))
+		} else {
+			report.Add("cpu", api.InstanceMigrationCheckOK, i18n.G("The target CPU supports all the features of the source CPU"))
+		}
+	}
+
+	// Compare the optional migration features of both servers.
+	missing := []string{}
+	for _, extension := range []string{"migration_stateful", "migration_vm_live", "migration_resume", "migration_parallel_streams", "migration_compression"} {
+		if source.HasExtension(extension) && !dest.HasExtension(extension) {
+			missing = append(missing, extension)
+		}
+	}
+
+	if len(missing) > 0 {
+		report.Add("extensions", api.InstanceMigrationCheckWarning, fmt.Sprintf(i18n.G("The target server is missing the following migration extensions: %s"), strings.Join(missing, ", ")))
+	} else {
+		report.Add("extensions", api.InstanceMigrationCheckOK, i18n.G("The target server supports all the migration extensions of the source"))
+	}
+
+	return c.renderCheck(report)
+}
+
+// missingCPUFlags returns the CPU flags of the source server which the target server lacks.
+func (c *cmdMove) missingCPUFlags(source incus.InstanceServer, dest incus.InstanceServer) ([]string, error) {
+	cpuFlags := func(server incus.InstanceServer) ([]string, error) {
+		resources, err := server.GetServerResources()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resources.CPU.Sockets) == 0 || len(resources.CPU.Sockets[0].Cores) == 0 {
+			return nil, errors.New(i18n.G("No CPU information available"))
+		}
+
+		return resources.CPU.Sockets[0].Cores[0].Flags, nil
+	}
+
+	sourceFlags, err := cpuFlags(source)
+	if err != nil {
+		return nil, err
+	}
+
+	destFlags, err := cpuFlags(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	for _, flag := range sourceFlags {
+		if !slices.Contains(destFlags, flag) {
+			missing = append(missing, flag)
+		}
+	}
+
+	slices.Sort(missing)
+
+	return missing, nil
+}
+
+// renderCheck renders a migration pre-flight check report.
+func (c *cmdMove) renderCheck(report *api.InstanceMigrationCheck) error {
+	data := [][]string{}
+	for _, check := range report.Checks {
+		data = append(data, []string{check.Name, strings.ToUpper(check.Status), check.Message})
+	}
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("STATUS"),
+		i18n.G("MESSAGE"),
+	}
+
+	err := cli.RenderTable(os.Stdout, cli.TableFormatTable, header, data, report)
+	if err != nil {
+		return err
+	}
+
+	if !report.Ready {
+		return errors.New(i18n.G("The instance can't be moved"))
+	}
+
+	return nil
+}
+
+// migrationProgressHandler returns an operation handler rendering the structured migration progress,
+// falling back to the generic progress for servers which don't provide it.
+func migrationProgressHandler(progress *cli.ProgressRenderer) func(api.Operation) {
+	return func(op api.Operation) {
+		value, ok := op.Metadata["migration"]
+		if !ok {
+			progress.UpdateOp(op)
+			return
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			progress.UpdateOp(op)
+			return
+		}
+
+		migrationProgress := api.InstanceMigrationProgress{}
+		err = json.Unmarshal(data, &migrationProgress)
+		if err != nil {
+			progress.UpdateOp(op)
+			return
+		}
+
+		switch migrationProgress.Phase {
+		case api.InstanceMigrationPhaseMemory:
+			memory := migrationProgress.Memory
+			if memory == nil {
+				break
+			}
+
+			msg := fmt.Sprintf(i18n.G("Memory (iteration %d): %s"), memory.Iteration, units.GetByteSizeString(memory.Transferred, 2))
+			if memory.Remaining > 0 {
+				msg += fmt.Sprintf(i18n.G(", %s remaining"), units.GetByteSizeString(memory.Remaining, 2))
+			}
+
+			if memory.DirtyRate > 0 {
+				msg += fmt.Sprintf(i18n.G(", %d dirty pages/s"), memory.DirtyRate)
+			}
+
+			progress.Update(msg)
+			return
+
+		case api.InstanceMigrationPhaseFinal:
+			progress.Update(i18n.G("Finalizing"))
+			return
+		}
+
+		progress.UpdateOp(op)
+	}
+}
+
 // Default migration mode when moving an instance.
 const moveDefaultMode = "pull"
@@ -93,6 +93,10 @@ func (c *cmdNetwork) Command() *cobra.Command {
 	networkRenameCmd := cmdNetworkRename{global: c.global, network: c}
 	cmd.AddCommand(networkRenameCmd.Command())
 
+	// Revisions
+	networkRevisionsCmd := cmdConfigRevisions{global: c.global, entity: networkConfigRevisionsEntity(c.global)}
+	cmd.AddCommand(networkRevisionsCmd.Command())
+
 	// Set
 	networkSetCmd := cmdNetworkSet{global: c.global, network: c}
 	cmd.AddCommand(networkSetCmd.Command())
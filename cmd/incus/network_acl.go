@@ -66,6 +66,10 @@ func (c *cmdNetworkACL) Command() *cobra.Command {
 	networkACLRenameCmd := cmdNetworkACLRename{global: c.global, networkACL: c}
 	cmd.AddCommand(networkACLRenameCmd.Command())
 
+	// Revisions.
+	networkACLRevisionsCmd := cmdConfigRevisions{global: c.global, entity: networkACLConfigRevisionsEntity(c.global)}
+	cmd.AddCommand(networkACLRevisionsCmd.Command())
+
 	// Delete.
 	networkACLDeleteCmd := cmdNetworkACLDelete{global: c.global, networkACL: c}
 	cmd.AddCommand(networkACLDeleteCmd.Command())
@@ -82,6 +82,10 @@ func (c *cmdProfile) Command() *cobra.Command {
 	profileRenameCmd := cmdProfileRename{global: c.global, profile: c}
 	cmd.AddCommand(profileRenameCmd.Command())
 
+	// Revisions
+	profileRevisionsCmd := cmdConfigRevisions{global: c.global, entity: profileConfigRevisionsEntity(c.global)}
+	cmd.AddCommand(profileRevisionsCmd.Command())
+
 	// Set
 	profileSetCmd := cmdProfileSet{global: c.global, profile: c}
 	cmd.AddCommand(profileSetCmd.Command())
@@ -64,6 +64,10 @@ func (c *cmdProject) Command() *cobra.Command {
 	projectRenameCmd := cmdProjectRename{global: c.global, project: c}
 	cmd.AddCommand(projectRenameCmd.Command())
 
+	// Revisions
+	projectRevisionsCmd := cmdConfigRevisions{global: c.global, entity: projectConfigRevisionsEntity(c.global)}
+	cmd.AddCommand(projectRevisionsCmd.Command())
+
 	// Set
 	projectSetCmd := cmdProjectSet{global: c.global, project: c}
 	cmd.AddCommand(projectSetCmd.Command())
@@ -1156,7 +1160,7 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render the output
-	byteLimits := []string{"disk", "memory"}
+	byteLimits := []string{"disk", "memory", "images", "backups"}
 	data := [][]string{}
 	for k, v := range projectState.Resources {
 		shortKey := strings.SplitN(k, ".", 2)[0]
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -24,7 +26,11 @@ type cmdPublish struct {
 	flagMakePublic           bool
 	flagForce                bool
 	flagReuse                bool
+	flagVariant              bool
 	flagFormat               string
+	flagSBOM                 string
+	flagBuilder              string
+	flagSourceHash           string
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -33,7 +39,16 @@ func (c *cmdPublish) Command() *cobra.Command {
 	cmd.Use = usage("publish", i18n.G("[<remote>:]<instance>[/<snapshot>] [<remote>:] [flags] [key=value...]"))
 	cmd.Short = i18n.G("Publish instances as images")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Publish instances as images`))
+		`Publish instances as images
+
+An SBOM document (in JSON) and the build provenance of the image can be
+attached to the new image with --sbom, --builder and --source-hash.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus publish c1 --alias app --sbom app.spdx.json --builder ci.example.com --source-hash 3f786850e387
+    Publish the instance c1 as the image "app" with its SBOM and build provenance.
+
+incus publish c1 --alias app --variant
+    Publish the instance c1 as the variant of the image "app" for its architecture.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagMakePublic, "public", false, i18n.G("Make the image public"))
@@ -42,7 +57,11 @@ func (c *cmdPublish) Command() *cobra.Command {
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Compression algorithm to use (`none` for uncompressed)"))
 	cmd.Flags().StringVar(&c.flagExpiresAt, "expire", "", i18n.G("Image expiration date (format: rfc3339)")+"``")
 	cmd.Flags().BoolVar(&c.flagReuse, "reuse", false, i18n.G("If the image alias already exists, delete and create a new one"))
+	cmd.Flags().BoolVar(&c.flagVariant, "variant", false, i18n.G("If the image alias already exists, add the image as its variant for the image architecture"))
 	cmd.Flags().StringVar(&c.flagFormat, "format", "unified", i18n.G("Image format")+"``")
+	cmd.Flags().StringVar(&c.flagSBOM, "sbom", "", i18n.G("Path to an SBOM document (in JSON) to attach to the image")+"``")
+	cmd.Flags().StringVar(&c.flagBuilder, "builder", "", i18n.G("Builder of the image to record in its provenance")+"``")
+	cmd.Flags().StringVar(&c.flagSourceHash, "source-hash", "", i18n.G("Hash of the source of the image to record in its provenance")+"``")
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -100,6 +119,19 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		return errors.New(i18n.G("There is no \"image name\".  Did you want an alias?"))
 	}
 
+	// Read the SBOM document.
+	var sbom []byte
+	if c.flagSBOM != "" {
+		sbom, err = os.ReadFile(c.flagSBOM)
+		if err != nil {
+			return err
+		}
+
+		if !json.Valid(sbom) {
+			return fmt.Errorf(i18n.G("SBOM document %q isn't valid JSON"), c.flagSBOM)
+		}
+	}
+
 	d, err := conf.GetInstanceServer(iRemote)
 	if err != nil {
 		return err
@@ -119,7 +151,7 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		wasRunning := ct.StatusCode != 0 && ct.StatusCode != api.Stopped
+		wasRunning := ct.StatusCode != 0 && ct.StatusCode != api.Stopped && ct.StatusCode != api.Suspended
 		wasEphemeral := ct.Ephemeral
 
 		if wasRunning {
@@ -225,6 +257,14 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	req.Properties = properties
+	req.SBOM = string(sbom)
+
+	if c.flagBuilder != "" || c.flagSourceHash != "" {
+		req.Provenance = &api.ImageProvenance{
+			Builder:    c.flagBuilder,
+			SourceHash: c.flagSourceHash,
+		}
+	}
 
 	if instance.IsSnapshot(cName) {
 		req.Source.Type = "snapshot"
@@ -250,7 +290,11 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("Error retrieving aliases: %w"), err)
 	}
 
-	if !c.flagReuse && len(existingAliases) > 0 {
+	if c.flagReuse && c.flagVariant {
+		return errors.New(i18n.G("--reuse and --variant can't be used together"))
+	}
+
+	if !c.flagReuse && !c.flagVariant && len(existingAliases) > 0 {
 		names := []string{}
 		for _, alias := range existingAliases {
 			names = append(names, alias.Name)
@@ -330,7 +374,12 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	err = ensureImageAliases(d, aliases, fingerprint)
+	if c.flagVariant {
+		err = addImageAliasVariants(d, aliases, fingerprint)
+	} else {
+		err = ensureImageAliases(d, aliases, fingerprint)
+	}
+
 	if err != nil {
 		return err
 	}
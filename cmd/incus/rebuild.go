@@ -3,10 +3,12 @@ package main
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	incus "github.com/lxc/incus/v6/client"
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	"github.com/lxc/incus/v6/internal/i18n"
 	"github.com/lxc/incus/v6/internal/instance"
@@ -16,9 +18,11 @@ import (
 
 // Rebuild.
 type cmdRebuild struct {
-	global    *cmdGlobal
-	flagEmpty bool
-	flagForce bool
+	global          *cmdGlobal
+	flagEmpty       bool
+	flagForce       bool
+	flagLatestImage bool
+	flagAll         bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -27,11 +31,24 @@ func (c *cmdRebuild) Command() *cobra.Command {
 	cmd.Use = usage("rebuild", i18n.G("[<remote>:]<image> [<remote>:]<instance>"))
 	cmd.Short = i18n.G("Rebuild instances")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Wipe the instance root disk and re-initialize with a new image (or empty volume).`))
+		`Wipe the instance root disk and re-initialize with a new image (or empty volume).
+
+With --latest-image, the instances are rebuilt from the latest image of the alias they were
+created or last rebuilt from, when that alias now points to a newer image. Their configuration,
+devices and custom volumes are kept. Use --all to rebuild all instances of the project which were
+built from an image alias. Running instances are skipped unless --force is given.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus rebuild images:debian/12 v1
+    Rebuild the v1 instance with the images:debian/12 image
+
+incus rebuild --latest-image --all --force
+    Rebuild all instances of the current project from the latest image of their alias`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagEmpty, "empty", false, i18n.G("Rebuild as an empty instance"))
 	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("If an instance is running, stop it and then rebuild it"))
+	cmd.Flags().BoolVar(&c.flagLatestImage, "latest-image", false, i18n.G("Rebuild from the latest image of the alias the instance was built from"))
+	cmd.Flags().BoolVar(&c.flagAll, "all", false, i18n.G("Rebuild all instances built from an image alias (with --latest-image)"))
 
 	return cmd
 }
@@ -84,32 +101,10 @@ func (c *cmdRebuild) rebuild(conf *config.Config, args []string) error {
 
 	// If the instance is running, stop it first.
 	if c.flagForce && current.StatusCode == api.Running {
-		req := api.InstanceStatePut{
-			Action: "stop",
-			Force:  true,
-		}
-
-		// Update the instance.
-		op, err := d.UpdateInstanceState(name, req, "")
+		err = c.updateState(d, name, "stop")
 		if err != nil {
 			return err
 		}
-
-		progress := cli.ProgressRenderer{
-			Quiet: c.global.flagQuiet,
-		}
-
-		_, err = op.AddHandler(progress.UpdateOp)
-		if err != nil {
-			progress.Done("")
-			return err
-		}
-
-		err = cli.CancelableWait(op, &progress)
-		if err != nil {
-			progress.Done("")
-			return err
-		}
 	}
 
 	// Base request
@@ -128,7 +123,7 @@ func (c *cmdRebuild) rebuild(conf *config.Config, args []string) error {
 			return err
 		}
 
-		if conf.Remotes[iremote].Protocol == "incus" {
+		if conf.Remotes[iremote].Protocol == "incus" && imgInfo.Type != "" {
 			if imgInfo.Type != "virtual-machine" && current.Type == "virtual-machine" {
 				return errors.New(i18n.G("Asked for a VM but image is of type container"))
 			}
@@ -177,31 +172,197 @@ func (c *cmdRebuild) rebuild(conf *config.Config, args []string) error {
 
 	// If the instance was stopped, start it back up.
 	if c.flagForce && current.StatusCode == api.Running {
-		req := api.InstanceStatePut{
-			Action: "start",
+		err = c.updateState(d, name, "start")
+		if err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// updateState stops or starts an instance around its rebuild.
+func (c *cmdRebuild) updateState(d incus.InstanceServer, name string, action string) error {
+	req := api.InstanceStatePut{
+		Action: action,
+		Force:  action == "stop",
+	}
+
+	// Update the instance.
+	op, err := d.UpdateInstanceState(name, req, "")
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Quiet: c.global.flagQuiet,
+	}
 
-		// Update the instance.
-		op, err := d.UpdateInstanceState(name, req, "")
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	return nil
+}
+
+// rebuildLatest rebuilds an instance from the latest image of the alias it was built from.
+func (c *cmdRebuild) rebuildLatest(conf *config.Config, nameArg string) error {
+	remote, name, err := conf.ParseRemote(nameArg)
+	if err != nil {
+		return err
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(name, instance.SnapshotDelimiter) {
+		return fmt.Errorf(i18n.G("Instance snapshots cannot be rebuilt: %s"), name)
+	}
+
+	current, _, err := d.GetInstance(name)
+	if err != nil {
+		return err
+	}
+
+	running := current.StatusCode == api.Running
+	if running {
+		if !c.flagForce {
+			return errors.New(i18n.G("The instance is running, use --force to stop it and rebuild it"))
+		}
+
+		err = c.updateState(d, name, "stop")
 		if err != nil {
 			return err
 		}
+	}
 
-		progress := cli.ProgressRenderer{
-			Quiet: c.global.flagQuiet,
-		}
+	op, err := d.RebuildInstance(name, api.InstanceRebuildPost{LatestImage: true})
+	if err != nil {
+		return err
+	}
 
-		_, err = op.AddHandler(progress.UpdateOp)
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if running {
+		err = c.updateState(d, name, "start")
 		if err != nil {
-			progress.Done("")
 			return err
 		}
+	}
 
-		err = cli.CancelableWait(op, &progress)
+	if c.global.flagQuiet {
+		return nil
+	}
+
+	rebuilt, _, err := d.GetInstance(name)
+	if err != nil {
+		return err
+	}
+
+	fingerprint := rebuilt.Config["volatile.base_image"]
+	if fingerprint == current.Config["volatile.base_image"] {
+		fmt.Printf(i18n.G("Instance %s already uses the latest image of %s")+"\n", name, current.Config["volatile.base_image.alias"])
+		return nil
+	}
+
+	if len(fingerprint) > 12 {
+		fingerprint = fingerprint[:12]
+	}
+
+	fmt.Printf(i18n.G("Instance %s rebuilt from image %s of %s")+"\n", name, fingerprint, current.Config["volatile.base_image.alias"])
+
+	return nil
+}
+
+// rebuildLatestAll rebuilds the listed instances, or all the instances built from an image alias with --all,
+// from the latest image of their alias.
+func (c *cmdRebuild) rebuildLatestAll(conf *config.Config, args []string) error {
+	if c.flagEmpty {
+		return errors.New(i18n.G("--empty cannot be combined with --latest-image"))
+	}
+
+	var names []string
+	if c.flagAll {
+		// If no server passed, use current default.
+		if len(args) == 0 {
+			args = []string{fmt.Sprintf("%s:", conf.DefaultRemote)}
+		}
+
+		resources, err := c.global.parseServers(args...)
 		if err != nil {
-			progress.Done("")
 			return err
 		}
+
+		for _, resource := range resources {
+			if resource.name != "" {
+				return errors.New(i18n.G("Both --all and instance name given"))
+			}
+
+			instances, err := resource.server.GetInstances(api.InstanceTypeAny)
+			if err != nil {
+				return err
+			}
+
+			for _, inst := range instances {
+				if inst.Config["volatile.base_image.alias"] == "" {
+					continue
+				}
+
+				if inst.StatusCode == api.Running && !c.flagForce {
+					if !c.global.flagQuiet {
+						fmt.Printf(i18n.G("Skipping running instance %s")+"\n", inst.Name)
+					}
+
+					continue
+				}
+
+				names = append(names, fmt.Sprintf("%s:%s", resource.remote, inst.Name))
+			}
+		}
+	} else {
+		if len(args) == 0 {
+			return errors.New(i18n.G("Missing instance name"))
+		}
+
+		names = args
+	}
+
+	results := runBatch(names, func(name string) error { return c.rebuildLatest(conf, name) })
+
+	// Single instance is easy.
+	if len(results) == 1 && !c.flagAll {
+		return results[0].err
+	}
+
+	success := true
+	for _, result := range results {
+		if result.err == nil {
+			continue
+		}
+
+		success = false
+		msg := fmt.Sprintf(i18n.G("error: %v"), result.err)
+		for _, line := range strings.Split(msg, "\n") {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", result.name, line)
+		}
+	}
+
+	if !success {
+		fmt.Fprintln(os.Stderr, "")
+		return errors.New(i18n.G("Some instances failed to rebuild"))
 	}
 
 	return nil
@@ -210,6 +371,14 @@ func (c *cmdRebuild) rebuild(conf *config.Config, args []string) error {
 // Run runs the actual command logic.
 func (c *cmdRebuild) Run(cmd *cobra.Command, args []string) error {
 	conf := c.global.conf
+	if c.flagLatestImage {
+		return c.rebuildLatestAll(conf, args)
+	}
+
+	if c.flagAll {
+		return errors.New(i18n.G("--all can only be used with --latest-image"))
+	}
+
 	if len(args) == 0 {
 		_ = cmd.Usage()
 		return nil
@@ -71,6 +71,10 @@ func (c *cmdRemote) Command() *cobra.Command {
 		cmd.AddCommand(remoteProxyCmd.Command())
 	}
 
+	// Queue
+	remoteQueueCmd := cmdRemoteQueue{global: c.global, remote: c}
+	cmd.AddCommand(remoteQueueCmd.Command())
+
 	// Rename
 	remoteRenameCmd := cmdRemoteRename{global: c.global, remote: c}
 	cmd.AddCommand(remoteRenameCmd.Command())
@@ -83,6 +87,10 @@ func (c *cmdRemote) Command() *cobra.Command {
 	remoteSwitchCmd := cmdRemoteSwitch{global: c.global, remote: c}
 	cmd.AddCommand(remoteSwitchCmd.Command())
 
+	// Set default project
+	remoteSetDefaultProjectCmd := cmdRemoteSetDefaultProject{global: c.global, remote: c}
+	cmd.AddCommand(remoteSetDefaultProjectCmd.Command())
+
 	// Set URL
 	remoteSetURLCmd := cmdRemoteSetURL{global: c.global, remote: c}
 	cmd.AddCommand(remoteSetURLCmd.Command())
@@ -132,7 +140,7 @@ Basic authentication can be used when combined with the "simplestreams" protocol
 	cmd.Flags().BoolVar(&c.flagAcceptCert, "accept-certificate", false, i18n.G("Accept certificate"))
 	cmd.Flags().StringVar(&c.flagToken, "token", "", i18n.G("Remote trust token")+"``")
 	cmd.Flags().StringVar(&c.flagProtocol, "protocol", "", i18n.G("Server protocol (incus, oci or simplestreams)")+"``")
-	cmd.Flags().StringVar(&c.flagAuthType, "auth-type", "", i18n.G("Server authentication type (tls or oidc)")+"``")
+	cmd.Flags().StringVar(&c.flagAuthType, "auth-type", "", i18n.G("Server authentication type (tls, oidc or external)")+"``")
 	cmd.Flags().BoolVar(&c.flagPublic, "public", false, i18n.G("Public image server"))
 	cmd.Flags().StringVar(&c.flagProject, "project", "", i18n.G("Project to use for the remote")+"``")
 
@@ -430,7 +438,19 @@ func (c *cmdRemoteAdd) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	conf.Remotes[server] = config.Remote{Addr: addr, Protocol: c.flagProtocol, AuthType: c.flagAuthType}
+	// Get the username to use with external authentication.
+	var username string
+	if rScheme != "unix" && !c.flagPublic && c.flagAuthType == api.AuthenticationMethodExternal {
+		username = os.Getenv("INCUS_USERNAME")
+		if username == "" {
+			username, err = c.global.asker.AskString(fmt.Sprintf(i18n.G("Username for %s: "), server), "", nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	conf.Remotes[server] = config.Remote{Addr: addr, Protocol: c.flagProtocol, AuthType: c.flagAuthType, Username: username}
 
 	// Attempt to connect
 	var d incus.ImageServer
@@ -1107,6 +1127,7 @@ func (c *cmdRemoteRename) Run(cmd *cobra.Command, args []string) error {
 	rc.Global = false
 	conf.Remotes[args[1]] = rc
 	delete(conf.Remotes, args[0])
+	conf.RenameGroupRemote(args[0], args[1])
 
 	if conf.DefaultRemote == args[0] {
 		conf.DefaultRemote = args[1]
@@ -1172,6 +1193,7 @@ func (c *cmdRemoteRemove) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	delete(conf.Remotes, args[0])
+	conf.RemoveGroupRemote(args[0])
 
 	_ = os.Remove(conf.ServerCertPath(args[0]))
 	_ = os.Remove(conf.CookiesPath(args[0]))
@@ -1229,6 +1251,69 @@ func (c *cmdRemoteSwitch) Run(cmd *cobra.Command, args []string) error {
 	return conf.SaveConfig(c.global.confPath)
 }
 
+// Set default project.
+type cmdRemoteSetDefaultProject struct {
+	global *cmdGlobal
+	remote *cmdRemote
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdRemoteSetDefaultProject) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("set-default-project", i18n.G("<remote> [<project>]"))
+	cmd.Short = i18n.G("Set the default project for the remote")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Set the default project for the remote
+
+If no project is provided, the remote goes back to using the project of its profile (if any) or the server's default project.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemoteNames()
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run is used in the RunE field of the cobra.Command returned by Command.
+func (c *cmdRemoteSetDefaultProject) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	remote, ok := conf.Remotes[args[0]]
+	if !ok {
+		return fmt.Errorf(i18n.G("Remote %s doesn't exist"), args[0])
+	}
+
+	if remote.Global {
+		err := conf.CopyGlobalCert(args[0], args[0])
+		if err != nil {
+			return err
+		}
+
+		remote.Global = false
+	}
+
+	remote.Project = ""
+	if len(args) > 1 {
+		remote.Project = args[1]
+	}
+
+	conf.Remotes[args[0]] = remote
+
+	return conf.SaveConfig(c.global.confPath)
+}
+
 // Set URL.
 type cmdRemoteSetURL struct {
 	global *cmdGlobal
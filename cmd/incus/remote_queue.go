@@ -0,0 +1,357 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	config "github.com/lxc/incus/v6/shared/cliconfig"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+// queueReadOnlyCommands lists the commands which don't alter the remote and so are never queued.
+var queueReadOnlyCommands = []string{
+	"completion",
+	"console",
+	"exec",
+	"export",
+	"get",
+	"get-default",
+	"help",
+	"info",
+	"list",
+	"monitor",
+	"pull",
+	"query",
+	"show",
+	"top",
+	"version",
+	"wait",
+}
+
+// queueCommand adds the command which just failed to the offline queue of its remote.
+// It returns false if the command isn't suitable for being replayed later.
+func (c *cmdGlobal) queueCommand(cmd *cobra.Command, remote string) (bool, error) {
+	if cmd == nil || c.conf == nil {
+		return false, nil
+	}
+
+	// Don't queue commands which are being replayed or which don't change anything.
+	if os.Getenv("INCUS_QUEUE_REPLAY") == "1" || slices.Contains(queueReadOnlyCommands, cmd.Name()) {
+		return false, nil
+	}
+
+	for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
+		if slices.Contains([]string{"alias", "remote"}, parent.Name()) {
+			return false, nil
+		}
+	}
+
+	// Commands reading their input from a pipe can't be replayed.
+	if !termios.IsTerminal(getStdinFd()) {
+		return false, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false, err
+	}
+
+	queue, err := c.conf.LoadQueue()
+	if err != nil {
+		return false, err
+	}
+
+	queue = append(queue, config.QueuedCommand{
+		Remote:    remote,
+		Args:      os.Args[1:],
+		Directory: cwd,
+		Date:      time.Now().UTC(),
+	})
+
+	err = c.conf.SaveQueue(queue)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+type cmdRemoteQueue struct {
+	global *cmdGlobal
+	remote *cmdRemote
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdRemoteQueue) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("queue")
+	cmd.Short = i18n.G("Manage the commands queued for unreachable remotes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage the commands queued for unreachable remotes
+
+Mutating commands targeting a remote with "offline_queue" enabled are queued when that remote can't be reached.
+They can then be replayed, in order, once the remote is reachable again.`))
+
+	// List
+	remoteQueueListCmd := cmdRemoteQueueList{global: c.global, remoteQueue: c}
+	cmd.AddCommand(remoteQueueListCmd.Command())
+
+	// Replay
+	remoteQueueReplayCmd := cmdRemoteQueueReplay{global: c.global, remoteQueue: c}
+	cmd.AddCommand(remoteQueueReplayCmd.Command())
+
+	// Clear
+	remoteQueueClearCmd := cmdRemoteQueueClear{global: c.global, remoteQueue: c}
+	cmd.AddCommand(remoteQueueClearCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdRemoteQueueList struct {
+	global      *cmdGlobal
+	remoteQueue *cmdRemoteQueue
+
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdRemoteQueueList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list")
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List the queued commands")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List the queued commands`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run is used in the RunE field of the cobra.Command returned by Command.
+func (c *cmdRemoteQueueList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 0)
+	if exit {
+		return err
+	}
+
+	queue, err := c.global.conf.LoadQueue()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for i, entry := range queue {
+		data = append(data, []string{fmt.Sprintf("%d", i+1), entry.Remote, entry.Date.Local().Format(dateLayout), shellquote.Join(entry.Args...)})
+	}
+
+	header := []string{
+		i18n.G("ID"),
+		i18n.G("REMOTE"),
+		i18n.G("DATE"),
+		i18n.G("COMMAND"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, queue)
+}
+
+// Replay.
+type cmdRemoteQueueReplay struct {
+	global      *cmdGlobal
+	remoteQueue *cmdRemoteQueue
+
+	flagForce bool
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdRemoteQueueReplay) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("replay", i18n.G("[<remote>]"))
+	cmd.Short = i18n.G("Replay the queued commands")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Replay the queued commands
+
+Commands are replayed in the order they were queued, after confirmation.
+Commands for remotes which still can't be reached are kept in the queue.
+Replay stops at the first failing command.`))
+
+	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("Replay the commands without confirmation"))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemoteNames()
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run is used in the RunE field of the cobra.Command returned by Command.
+func (c *cmdRemoteQueueReplay) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	if !c.flagForce && !termios.IsTerminal(getStdinFd()) {
+		return errors.New(i18n.G("Replaying queued commands requires confirmation, use --force to skip it"))
+	}
+
+	queue, err := conf.LoadQueue()
+	if err != nil {
+		return err
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	remaining := []config.QueuedCommand{}
+	reachable := map[string]bool{}
+	var replayErr error
+
+	for _, entry := range queue {
+		// Keep everything following a failure as the commands may depend on each other.
+		if replayErr != nil || (len(args) > 0 && entry.Remote != args[0]) {
+			remaining = append(remaining, entry)
+			continue
+		}
+
+		// Check that the remote is back.
+		_, checked := reachable[entry.Remote]
+		if !checked {
+			_, err := conf.GetInstanceServer(entry.Remote)
+			reachable[entry.Remote] = err == nil
+			if err != nil {
+				fmt.Printf(i18n.G("Remote %s is still unreachable: %v")+"\n", entry.Remote, err)
+			}
+		}
+
+		if !reachable[entry.Remote] {
+			remaining = append(remaining, entry)
+			continue
+		}
+
+		command := shellquote.Join(append([]string{"incus"}, entry.Args...)...)
+
+		if !c.flagForce {
+			replay, err := c.global.asker.AskBool(fmt.Sprintf(i18n.G("Replay %q (queued on %s)?"), command, entry.Date.Local().Format(dateLayout))+" (yes/no) [default=yes]: ", "yes")
+			if err != nil {
+				return err
+			}
+
+			if !replay {
+				remaining = append(remaining, entry)
+				continue
+			}
+		}
+
+		if !c.global.flagQuiet {
+			fmt.Printf(i18n.G("Replaying %q")+"\n", command)
+		}
+
+		replayCmd := exec.Command(execPath, entry.Args...)
+		replayCmd.Dir = entry.Directory
+		replayCmd.Env = append(os.Environ(), "INCUS_QUEUE_REPLAY=1")
+		replayCmd.Stdin = os.Stdin
+		replayCmd.Stdout = os.Stdout
+		replayCmd.Stderr = os.Stderr
+
+		err = replayCmd.Run()
+		if err != nil {
+			replayErr = fmt.Errorf(i18n.G("Failed replaying %q: %w"), command, err)
+			remaining = append(remaining, entry)
+		}
+	}
+
+	err = conf.SaveQueue(remaining)
+	if err != nil {
+		return err
+	}
+
+	return replayErr
+}
+
+// Clear.
+type cmdRemoteQueueClear struct {
+	global      *cmdGlobal
+	remoteQueue *cmdRemoteQueue
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdRemoteQueueClear) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("clear", i18n.G("[<remote>]"))
+	cmd.Short = i18n.G("Remove the queued commands")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Remove the queued commands
+
+If a remote is provided, only the commands queued for that remote are removed.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemoteNames()
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run is used in the RunE field of the cobra.Command returned by Command.
+func (c *cmdRemoteQueueClear) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	queue, err := conf.LoadQueue()
+	if err != nil {
+		return err
+	}
+
+	remaining := []config.QueuedCommand{}
+	if len(args) > 0 {
+		for _, entry := range queue {
+			if entry.Remote != args[0] {
+				remaining = append(remaining, entry)
+			}
+		}
+	}
+
+	return conf.SaveQueue(remaining)
+}
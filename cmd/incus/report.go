@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type cmdReport struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdReport) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("report")
+	cmd.Short = i18n.G("Manage capacity and inventory reports")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage capacity and inventory reports
+
+Reports are generated on demand or on the schedule set in the
+reports.schedule server configuration key.`))
+
+	// Delete
+	reportDeleteCmd := cmdReportDelete{global: c.global, report: c}
+	cmd.AddCommand(reportDeleteCmd.Command())
+
+	// Generate
+	reportGenerateCmd := cmdReportGenerate{global: c.global, report: c}
+	cmd.AddCommand(reportGenerateCmd.Command())
+
+	// List
+	reportListCmd := cmdReportList{global: c.global, report: c}
+	cmd.AddCommand(reportListCmd.Command())
+
+	// Show
+	reportShowCmd := cmdReportShow{global: c.global, report: c}
+	cmd.AddCommand(reportShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Delete.
+type cmdReportDelete struct {
+	global *cmdGlobal
+	report *cmdReport
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdReportDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<report>"))
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Delete reports")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete reports`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdReportDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing report name"))
+	}
+
+	err = resource.server.DeleteReport(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Report %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Generate.
+type cmdReportGenerate struct {
+	global *cmdGlobal
+	report *cmdReport
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdReportGenerate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("generate", i18n.G("[<remote>:]<type>"))
+	cmd.Short = i18n.G("Generate reports")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Generate reports
+
+The type of the report is either "capacity" or "inventory".`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus report generate capacity
+    Generate a report of the resource usage of the projects, cluster members and storage pools.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return []string{api.ReportTypeCapacity, api.ReportTypeInventory}, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdReportGenerate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing report type"))
+	}
+
+	op, err := resource.server.CreateReport(api.ReportsPost{Type: resource.name})
+	if err != nil {
+		return err
+	}
+
+	// Watch the background operation
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Generating report: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	// Show the name of the new report.
+	reports := op.Get().Resources["reports"]
+	if len(reports) == 1 && !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Report %s generated")+"\n", path.Base(reports[0]))
+	}
+
+	return nil
+}
+
+// List.
+type cmdReportList struct {
+	global *cmdGlobal
+	report *cmdReport
+
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdReportList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List reports")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List reports`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdReportList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	reports, err := resource.server.GetReports()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, report := range reports {
+		rows := 0
+		for _, section := range report.Sections {
+			rows += len(section.Rows)
+		}
+
+		data = append(data, []string{
+			report.Name,
+			report.Type,
+			report.CreatedAt.Local().Format(dateLayout),
+			fmt.Sprintf("%d", rows),
+		})
+	}
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("TYPE"),
+		i18n.G("CREATED AT"),
+		i18n.G("ROWS"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, reports)
+}
+
+// Show.
+type cmdReportShow struct {
+	global *cmdGlobal
+	report *cmdReport
+
+	flagFormat  string
+	flagSection string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdReportShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<report>"))
+	cmd.Short = i18n.G("Show reports")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show reports
+
+The CSV format requires a section of the report to be selected.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus report show capacity-20261014-120000 --section storage_pools --format csv > pools.csv
+    Save the storage pool usage of the report as CSV.`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "yaml", i18n.G("Format (csv|json|yaml)")+"``")
+	cmd.Flags().StringVar(&c.flagSection, "section", "", i18n.G("Only show this section of the report")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdReportShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	if !slices.Contains([]string{"csv", "json", "yaml"}, c.flagFormat) {
+		return fmt.Errorf(i18n.G("Invalid format %q"), c.flagFormat)
+	}
+
+	if c.flagFormat == "csv" && c.flagSection == "" {
+		return errors.New(i18n.G("A section must be selected with --section to show a report as CSV"))
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing report name"))
+	}
+
+	if c.flagFormat == "csv" {
+		content, err := resource.server.GetReportSection(resource.name, c.flagSection, "csv")
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = content.Close() }()
+
+		_, err = io.Copy(os.Stdout, content)
+		return err
+	}
+
+	report, err := resource.server.GetReport(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if c.flagSection != "" {
+		report.Sections = slices.DeleteFunc(report.Sections, func(section api.ReportSection) bool {
+			return section.Name != c.flagSection
+		})
+
+		if len(report.Sections) == 0 {
+			return fmt.Errorf(i18n.G("Report section %q not found"), c.flagSection)
+		}
+	}
+
+	var data []byte
+	if c.flagFormat == "json" {
+		data, err = json.MarshalIndent(report, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(report)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/termios"
+	"github.com/lxc/incus/v6/shared/units"
 )
 
 type cmdSnapshot struct {
@@ -302,7 +303,7 @@ func (c *cmdSnapshotList) Command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`List instance snapshots
 
-Default column layout: nTEs
+Default column layout: nTEsuU
 
 == Columns ==
 The -c option takes a comma separated list of arguments that control
@@ -318,7 +319,9 @@ Pre-defined column shorthand chars:
   n - Name
   T - Taken At
   E - Expires At
-  s - Stateful`))
+  s - Stateful
+  u - Used (space used by the snapshot data)
+  U - Unique (space freed by deleting the snapshot)`))
 
 	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
 	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultSnapshotColumns, i18n.G("Columns")+"``")
@@ -340,7 +343,7 @@ Pre-defined column shorthand chars:
 	return cmd
 }
 
-const defaultSnapshotColumns = "nTEs"
+const defaultSnapshotColumns = "nTEsuU"
 
 func (c *cmdSnapshotList) parseColumns() ([]snapshotColumn, error) {
 	columnsShorthandMap := map[rune]snapshotColumn{
@@ -348,6 +351,8 @@ func (c *cmdSnapshotList) parseColumns() ([]snapshotColumn, error) {
 		'T': {i18n.G("TAKEN AT"), c.takenAtColumnData},
 		'E': {i18n.G("EXPIRES AT"), c.expiresAtColumnData},
 		's': {i18n.G("STATEFUL"), c.statefulColumnData},
+		'u': {i18n.G("USED"), c.usedColumnData},
+		'U': {i18n.G("UNIQUE"), c.uniqueColumnData},
 	}
 
 	columnList := strings.Split(c.flagColumns, ",")
@@ -400,6 +405,22 @@ func (c *cmdSnapshotList) statefulColumnData(snapshot api.InstanceSnapshot) stri
 	return strStateful
 }
 
+func (c *cmdSnapshotList) usedColumnData(snapshot api.InstanceSnapshot) string {
+	if snapshot.Used < 0 {
+		return ""
+	}
+
+	return units.GetByteSizeStringIEC(snapshot.Used, 2)
+}
+
+func (c *cmdSnapshotList) uniqueColumnData(snapshot api.InstanceSnapshot) string {
+	if snapshot.Unique < 0 {
+		return ""
+	}
+
+	return units.GetByteSizeStringIEC(snapshot.Unique, 2)
+}
+
 // Run runs the actual command logic.
 func (c *cmdSnapshotList) Run(cmd *cobra.Command, args []string) error {
 	conf := c.global.conf
@@ -425,6 +446,14 @@ func (c *cmdSnapshotList) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Older servers don't report the snapshot usage.
+	if !d.HasExtension("snapshot_usage_breakdown") {
+		for i := range snapshots {
+			snapshots[i].Used = -1
+			snapshots[i].Unique = -1
+		}
+	}
+
 	// Parse column flags.
 	columns, err := c.parseColumns()
 	if err != nil {
@@ -571,6 +571,15 @@ func (c *cmdStorageInfo) Run(cmd *cobra.Command, args []string) error {
 	descriptionstring := i18n.G("description")
 	totalspacestring := i18n.G("total space")
 	spaceusedstring := i18n.G("space used")
+	dedupratiostring := i18n.G("deduplication ratio")
+	compressionratiostring := i18n.G("compression ratio")
+	cachedevicestring := i18n.G("cache device")
+	cachemodestring := i18n.G("cache mode")
+	cachetotalstring := i18n.G("cache total space")
+	cacheusedstring := i18n.G("cache space used")
+	cachedirtystring := i18n.G("cache dirty")
+	healthstring := i18n.G("health")
+	healthmessagesstring := i18n.G("health messages")
 
 	// Initialize the usedby map
 	poolusedby[usedbystring] = make(map[string][]string)
@@ -653,6 +662,32 @@ func (c *cmdStorageInfo) Run(cmd *cobra.Command, args []string) error {
 		poolinfo[infostring][spaceusedstring] = units.GetByteSizeStringIEC(int64(res.Space.Used), 2)
 	}
 
+	if res.Statistics != nil {
+		poolinfo[infostring][dedupratiostring] = fmt.Sprintf("%.2fx", res.Statistics.DedupRatio)
+		poolinfo[infostring][compressionratiostring] = fmt.Sprintf("%.2fx", res.Statistics.CompressionRatio)
+	}
+
+	if res.Cache != nil {
+		poolinfo[infostring][cachedevicestring] = res.Cache.Device
+		poolinfo[infostring][cachemodestring] = res.Cache.Mode
+		if c.flagBytes {
+			poolinfo[infostring][cachetotalstring] = strconv.FormatUint(res.Cache.Total, 10)
+			poolinfo[infostring][cacheusedstring] = strconv.FormatUint(res.Cache.Used, 10)
+			poolinfo[infostring][cachedirtystring] = strconv.FormatUint(res.Cache.Dirty, 10)
+		} else {
+			poolinfo[infostring][cachetotalstring] = units.GetByteSizeStringIEC(int64(res.Cache.Total), 2)
+			poolinfo[infostring][cacheusedstring] = units.GetByteSizeStringIEC(int64(res.Cache.Used), 2)
+			poolinfo[infostring][cachedirtystring] = units.GetByteSizeStringIEC(int64(res.Cache.Dirty), 2)
+		}
+	}
+
+	if pool.Health != nil {
+		poolinfo[infostring][healthstring] = pool.Health.Status
+		if len(pool.Health.Messages) > 0 {
+			poolinfo[infostring][healthmessagesstring] = strings.Join(pool.Health.Messages, "; ")
+		}
+	}
+
 	poolinfodata, err := yaml.Marshal(poolinfo)
 	if err != nil {
 		return err
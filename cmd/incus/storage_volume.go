@@ -107,6 +107,14 @@ Unless specified through a prefix, all volume operations affect "custom" (user c
 	storageVolumeImportCmd := cmdStorageVolumeImport{global: c.global, storage: c.storage, storageVolume: c}
 	cmd.AddCommand(storageVolumeImportCmd.Command())
 
+	// Import existing
+	storageVolumeImportExistingCmd := cmdStorageVolumeImportExisting{global: c.global, storage: c.storage, storageVolume: c}
+	cmd.AddCommand(storageVolumeImportExistingCmd.Command())
+
+	// Import from URL
+	storageVolumeImportURLCmd := cmdStorageVolumeImportURL{global: c.global, storage: c.storage, storageVolume: c}
+	cmd.AddCommand(storageVolumeImportURLCmd.Command())
+
 	// Info
 	storageVolumeInfoCmd := cmdStorageVolumeInfo{global: c.global, storage: c.storage, storageVolume: c}
 	cmd.AddCommand(storageVolumeInfoCmd.Command())
@@ -1479,6 +1487,26 @@ func (c *cmdStorageVolumeInfo) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if volState != nil && volState.Limits != nil {
+		fmt.Println(i18n.G("Limits:"))
+
+		if volState.Limits.ReadBytes > 0 {
+			fmt.Printf("  "+i18n.G("Read bandwidth: %s")+"\n", units.GetByteSizeStringIEC(volState.Limits.ReadBytes, 2)+"/s")
+		}
+
+		if volState.Limits.ReadIOps > 0 {
+			fmt.Printf("  "+i18n.G("Read IOPS: %d")+"\n", volState.Limits.ReadIOps)
+		}
+
+		if volState.Limits.WriteBytes > 0 {
+			fmt.Printf("  "+i18n.G("Write bandwidth: %s")+"\n", units.GetByteSizeStringIEC(volState.Limits.WriteBytes, 2)+"/s")
+		}
+
+		if volState.Limits.WriteIOps > 0 {
+			fmt.Printf("  "+i18n.G("Write IOPS: %d")+"\n", volState.Limits.WriteIOps)
+		}
+	}
+
 	if !vol.CreatedAt.IsZero() {
 		fmt.Printf(i18n.G("Created: %s")+"\n", vol.CreatedAt.Local().Format(dateLayout))
 	}
@@ -3491,3 +3519,239 @@ func (c *cmdStorageVolumeImport) Run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// Import existing.
+type cmdStorageVolumeImportExisting struct {
+	global        *cmdGlobal
+	storage       *cmdStorage
+	storageVolume *cmdStorageVolume
+
+	flagContentType string
+	flagDescription string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdStorageVolumeImportExisting) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("import-existing", i18n.G("[<remote>:]<pool> <source> <volume> [key=value...]"))
+	cmd.Short = i18n.G("Adopt existing storage as custom storage volumes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Adopt existing storage as custom storage volumes
+
+The source is a ZFS dataset of the pool's zpool, a btrfs subvolume relative to the root of the pool
+or a logical volume of the pool's volume group. It's renamed to follow the naming of the pool, without
+copying its data.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus storage volume import-existing default tank/data data
+    Adopt the ZFS dataset "tank/data" as custom storage volume "data" in pool "default"
+
+incus storage volume import-existing default vg0/disk disk --type=block
+    Adopt the logical volume "disk" as custom block storage volume "disk" in pool "default"`))
+
+	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+	cmd.Flags().StringVar(&c.flagContentType, "type", "filesystem", i18n.G("Content type, block or filesystem")+"``")
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Volume description")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpStoragePools(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdStorageVolumeImportExisting) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 3, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing pool name"))
+	}
+
+	client := resource.server
+
+	vol := api.StorageVolumesPost{
+		Name:        args[2],
+		Type:        "custom",
+		ContentType: c.flagContentType,
+		Source: api.StorageVolumeSource{
+			Type: "existing",
+			Name: args[1],
+		},
+	}
+
+	vol.Config = map[string]string{}
+	for i := 3; i < len(args); i++ {
+		entry := strings.SplitN(args[i], "=", 2)
+		if len(entry) < 2 {
+			return fmt.Errorf(i18n.G("Bad key=value pair: %s"), entry)
+		}
+
+		vol.Config[entry[0]] = entry[1]
+	}
+
+	vol.Description = c.flagDescription
+
+	// If a target was specified, import the volume on the given member.
+	if c.storage.flagTarget != "" {
+		client = client.UseTarget(c.storage.flagTarget)
+	}
+
+	err = client.CreateStoragePoolVolume(resource.name, vol)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Storage volume %s imported")+"\n", args[2])
+	}
+
+	return nil
+}
+
+// Import from URL.
+type cmdStorageVolumeImportURL struct {
+	global        *cmdGlobal
+	storage       *cmdStorage
+	storageVolume *cmdStorageVolume
+
+	flagType     string
+	flagChecksum string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdStorageVolumeImportURL) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("import-url", i18n.G("[<remote>:]<pool> <url> [<volume name>]"))
+	cmd.Short = i18n.G("Import custom storage volumes from a URL")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Import custom storage volumes from a URL
+
+The server downloads the content directly, resuming the download if it gets interrupted.
+If no volume name is given, it's derived from the name of the downloaded file.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus storage volume import-url default https://example.com/installer.iso --type=iso
+    Create a new custom volume "installer" from the downloaded ISO for use as a CD-ROM image
+
+incus storage volume import-url default https://example.com/installer.iso installer --checksum=sha256:<hash>
+    Create a new custom volume "installer" from the downloaded ISO after verifying its checksum`))
+
+	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+	cmd.Flags().StringVar(&c.flagType, "type", "iso", i18n.G("Import type, only iso is supported")+"``")
+	cmd.Flags().StringVar(&c.flagChecksum, "checksum", "", i18n.G("Expected checksum of the content, as sha256:<hash> or sha512:<hash>")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpStoragePools(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdStorageVolumeImportURL) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 3)
+	if exit {
+		return err
+	}
+
+	if c.flagType != "iso" {
+		return errors.New(i18n.G("Import type needs to be \"iso\""))
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing pool name"))
+	}
+
+	volName := ""
+	if len(args) >= 3 {
+		volName = args[2]
+	} else {
+		u, err := url.Parse(args[1])
+		if err != nil {
+			return err
+		}
+
+		volName = strings.TrimSuffix(path.Base(u.Path), ".iso")
+		if volName == "" || volName == "." || volName == "/" {
+			return errors.New(i18n.G("Unable to derive a volume name from the URL, please provide one"))
+		}
+	}
+
+	client := resource.server
+
+	// If a target was specified, import the volume on the given member.
+	if c.storage.flagTarget != "" {
+		client = client.UseTarget(c.storage.flagTarget)
+	}
+
+	vol := api.StorageVolumesPost{
+		Name:        volName,
+		Type:        "custom",
+		ContentType: c.flagType,
+		Source: api.StorageVolumeSource{
+			URL:      args[1],
+			Checksum: c.flagChecksum,
+		},
+	}
+
+	op, err := client.CreateStoragePoolVolumeFromURL(resource.name, vol)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Importing custom volume: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	// Wait for operation to finish.
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Storage volume %s imported")+"\n", volName)
+	}
+
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math/rand"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -16,6 +18,7 @@ import (
 	"strings"
 
 	"golang.org/x/crypto/ssh"
+	"gopkg.in/yaml.v2"
 
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/i18n"
@@ -124,6 +127,120 @@ func parseDeviceOverrides(deviceOverrideArgs []string) (map[string]map[string]st
 	return deviceMap, nil
 }
 
+// resourceMapping holds the translation of storage pool, network and profile names when copying to another server.
+type resourceMapping struct {
+	Pools    map[string]string `yaml:"pools"`
+	Networks map[string]string `yaml:"networks"`
+	Profiles map[string]string `yaml:"profiles"`
+}
+
+// parseResourceMapping builds the resource mapping from the mapping file (if any) and the pool and network mapping arguments.
+// Arguments take precedence over the content of the mapping file.
+func parseResourceMapping(path string, poolArgs []string, networkArgs []string) (*resourceMapping, error) {
+	mapping := &resourceMapping{}
+
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		err = yaml.Unmarshal(content, mapping)
+		if err != nil {
+			return nil, fmt.Errorf(i18n.G("Failed to parse the mapping file %q: %w"), path, err)
+		}
+	}
+
+	parseArgs := func(args []string, target *map[string]string) error {
+		for _, entry := range args {
+			from, to, found := strings.Cut(entry, "=")
+			if !found || from == "" || to == "" {
+				return fmt.Errorf(i18n.G("Bad mapping syntax, expecting <source>=<target>: %s"), entry)
+			}
+
+			if *target == nil {
+				*target = map[string]string{}
+			}
+
+			(*target)[from] = to
+		}
+
+		return nil
+	}
+
+	err := parseArgs(poolArgs, &mapping.Pools)
+	if err != nil {
+		return nil, err
+	}
+
+	err = parseArgs(networkArgs, &mapping.Networks)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+// apply translates the pools and networks used by the devices as well as the profiles.
+// Devices inherited from profiles which need translating get a local override.
+// The translated list of profiles is returned.
+func (m *resourceMapping) apply(devices map[string]map[string]string, expandedDevices map[string]map[string]string, profiles []string) []string {
+	translate := func(device map[string]string) map[string]string {
+		var key string
+		var names map[string]string
+
+		switch device["type"] {
+		case "disk":
+			key, names = "pool", m.Pools
+		case "nic":
+			key, names = "network", m.Networks
+		default:
+			return nil
+		}
+
+		name, ok := names[device[key]]
+		if !ok || device[key] == "" {
+			return nil
+		}
+
+		translated := maps.Clone(device)
+		translated[key] = name
+
+		return translated
+	}
+
+	for name, device := range devices {
+		translated := translate(device)
+		if translated != nil {
+			devices[name] = translated
+		}
+	}
+
+	for name, device := range expandedDevices {
+		_, local := devices[name]
+		if local {
+			continue
+		}
+
+		translated := translate(device)
+		if translated != nil {
+			devices[name] = translated
+		}
+	}
+
+	translatedProfiles := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		name, ok := m.Profiles[profile]
+		if ok {
+			profile = name
+		}
+
+		translatedProfiles = append(translatedProfiles, profile)
+	}
+
+	return translatedProfiles
+}
+
 // IsAliasesSubset returns true if the first array is completely contained in the second array.
 func IsAliasesSubset(a1 []api.ImageAlias, a2 []api.ImageAlias) bool {
 	set := make(map[string]any)
@@ -141,7 +258,8 @@ func IsAliasesSubset(a1 []api.ImageAlias, a2 []api.ImageAlias) bool {
 	return true
 }
 
-// GetCommonAliases returns the common aliases between a list of aliases and all the existing ones.
+// GetCommonAliases returns the common aliases between a list of aliases and the existing ones in the project of the client.
+// The existing aliases are looked up in a single request.
 func GetCommonAliases(client incus.InstanceServer, aliases ...api.ImageAlias) ([]api.ImageAliasesEntry, error) {
 	if len(aliases) == 0 {
 		return nil, nil
@@ -152,37 +270,18 @@ func GetCommonAliases(client incus.InstanceServer, aliases ...api.ImageAlias) ([
 		names[i] = alias.Name
 	}
 
-	// 'GetExistingAliases' which is using 'sort.SearchStrings' requires sorted slice
-	sort.Strings(names)
-
-	resp, err := client.GetImageAliases()
-	if err != nil {
-		return nil, err
-	}
-
-	return GetExistingAliases(names, resp), nil
+	return client.GetImageAliasesByNames(names)
 }
 
 // Create the specified image aliases, updating those that already exist.
 func ensureImageAliases(client incus.InstanceServer, aliases []api.ImageAlias, fingerprint string) error {
-	if len(aliases) == 0 {
-		return nil
-	}
-
-	names := make([]string, len(aliases))
-	for i, alias := range aliases {
-		names[i] = alias.Name
-	}
-
-	sort.Strings(names)
-
-	resp, err := client.GetImageAliases()
+	existingAliases, err := GetCommonAliases(client, aliases...)
 	if err != nil {
 		return err
 	}
 
 	// Delete existing aliases that match provided ones
-	for _, alias := range GetExistingAliases(names, resp) {
+	for _, alias := range existingAliases {
 		err := client.DeleteImageAlias(alias.Name)
 		if err != nil {
 			return fmt.Errorf(i18n.G("Failed to remove alias %s: %w"), alias.Name, err)
@@ -203,6 +302,57 @@ func ensureImageAliases(client incus.InstanceServer, aliases []api.ImageAlias, f
 	return nil
 }
 
+// addImageAliasVariants sets the image as the target of the aliases for its architecture.
+// Missing aliases are created and existing ones keep their targets for the other architectures.
+func addImageAliasVariants(client incus.InstanceServer, aliases []api.ImageAlias, fingerprint string) error {
+	image, _, err := client.GetImage(fingerprint)
+	if err != nil {
+		return err
+	}
+
+	for _, alias := range aliases {
+		entry, etag, err := client.GetImageAlias(alias.Name)
+		if err != nil {
+			if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			aliasPost := api.ImageAliasesPost{}
+			aliasPost.Name = alias.Name
+			aliasPost.Target = fingerprint
+			err := client.CreateImageAlias(aliasPost)
+			if err != nil {
+				return fmt.Errorf(i18n.G("Failed to create alias %s: %w"), alias.Name, err)
+			}
+
+			continue
+		}
+
+		target, _, err := client.GetImage(entry.Target)
+		if err != nil {
+			return err
+		}
+
+		if entry.Architectures == nil {
+			entry.Architectures = map[string]string{target.Architecture: target.Fingerprint}
+		}
+
+		// The image replaces the target if it's for the same architecture.
+		if target.Architecture == image.Architecture {
+			entry.Target = fingerprint
+		}
+
+		entry.Architectures[image.Architecture] = fingerprint
+
+		err = client.UpdateImageAlias(alias.Name, entry.ImageAliasesEntryPut, etag)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed to update alias %s: %w"), alias.Name, err)
+		}
+	}
+
+	return nil
+}
+
 // GetExistingAliases returns the intersection between a list of aliases and all the existing ones.
 func GetExistingAliases(aliases []string, allAliases []api.ImageAliasesEntry) []api.ImageAliasesEntry {
 	existing := []api.ImageAliasesEntry{}
@@ -484,12 +634,24 @@ func getImgInfo(d incus.InstanceServer, conf *config.Config, imgRemote string, i
 		alias, _, err := imgRemoteServer.GetImageAlias(imageRef)
 		if err == nil {
 			source.Alias = imageRef
+
+			// Let the server pick the architecture variant of the alias.
+			if imgRemote == instRemote && len(alias.Architectures) > 1 {
+				return imgRemoteServer, &api.Image{Type: alias.Type}, nil
+			}
+
 			imageRef = alias.Target
 		}
 
 		// Get the image info
 		imgInfo, _, err = imgRemoteServer.GetImage(imageRef)
 		if err != nil {
+			// Let the server resolve aliases which may be redirected by its image alias namespaces.
+			if imgRemote == instRemote && api.StatusErrorCheck(err, http.StatusNotFound) && d.HasExtension("image_alias_namespaces") {
+				source.Alias = imageRef
+				return imgRemoteServer, &api.Image{}, nil
+			}
+
 			return nil, nil, err
 		}
 	}
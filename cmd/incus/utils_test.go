@@ -90,3 +90,43 @@ func (s *utilsTestSuite) TestGetServerSupportedFilters() {
 	s.Equal([]string{"foo", "user.blah=a"}, supportedFilters)
 	s.Equal([]string{"type=container", "status=running,stopped"}, unsupportedFilters)
 }
+
+func (s *utilsTestSuite) TestResourceMappingApply() {
+	mapping, err := parseResourceMapping("", []string{"default=fast"}, []string{"incusbr0=uplink"})
+	s.Require().NoError(err)
+
+	mapping.Profiles = map[string]string{"default": "edge"}
+
+	devices := map[string]map[string]string{
+		"data": {"type": "disk", "pool": "default", "source": "data", "path": "/data"},
+		"eth1": {"type": "nic", "network": "other"},
+	}
+
+	expandedDevices := map[string]map[string]string{
+		"data": {"type": "disk", "pool": "default", "source": "data", "path": "/data"},
+		"eth0": {"type": "nic", "network": "incusbr0", "name": "eth0"},
+		"eth1": {"type": "nic", "network": "other"},
+		"root": {"type": "disk", "pool": "default", "path": "/"},
+	}
+
+	profiles := mapping.apply(devices, expandedDevices, []string{"default", "extra"})
+
+	s.Equal([]string{"edge", "extra"}, profiles)
+	s.Equal(map[string]map[string]string{
+		"data": {"type": "disk", "pool": "fast", "source": "data", "path": "/data"},
+		"eth0": {"type": "nic", "network": "uplink", "name": "eth0"},
+		"eth1": {"type": "nic", "network": "other"},
+		"root": {"type": "disk", "pool": "fast", "path": "/"},
+	}, devices)
+
+	// The expanded devices are left untouched.
+	s.Equal("default", expandedDevices["root"]["pool"])
+}
+
+func (s *utilsTestSuite) TestParseResourceMappingInvalid() {
+	_, err := parseResourceMapping("", []string{"default"}, nil)
+	s.Error(err)
+
+	_, err = parseResourceMapping("", nil, []string{"=uplink"})
+	s.Error(err)
+}
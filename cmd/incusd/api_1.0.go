@@ -12,6 +12,7 @@ import (
 
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/auth/external"
 	"github.com/lxc/incus/v6/internal/server/auth/oidc"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	clusterConfig "github.com/lxc/incus/v6/internal/server/cluster/config"
@@ -42,15 +43,21 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	backupPointCmd,
+	backupPointsCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
 	clusterGroupCmd,
 	clusterGroupsCmd,
 	clusterNodeCmd,
+	clusterNodeSnapshotCmd,
+	clusterNodeSnapshotsCmd,
 	clusterNodeStateCmd,
 	clusterNodesCmd,
 	clusterCertificateCmd,
+	instanceAccessLinkCmd,
+	instanceAccessLinksCmd,
 	instanceBackupCmd,
 	instanceBackupExportCmd,
 	instanceBackupsCmd,
@@ -68,10 +75,17 @@ var api10 = []APIEndpoint{
 	instanceRebuildCmd,
 	instanceSFTPCmd,
 	instanceSnapshotCmd,
+	instanceSnapshotFileCmd,
 	instanceSnapshotsCmd,
 	instanceStateCmd,
 	instanceAccessCmd,
 	instanceDebugMemoryCmd,
+	instanceDebugLXCConfigCmd,
+	instanceDebugQMPCmd,
+	instanceDebugNetnsCmd,
+	instanceDebugProfileCmd,
+	instanceDiskUsageCmd,
+	instanceDeviceMediaCmd,
 	eventsCmd,
 	imageAliasCmd,
 	imageAliasesCmd,
@@ -79,15 +93,20 @@ var api10 = []APIEndpoint{
 	imageExportCmd,
 	imageRefreshCmd,
 	imagesCmd,
+	imageSBOMCmd,
 	imageSecretCmd,
 	metadataConfigurationCmd,
 	networkCmd,
 	networkLeasesCmd,
+	networkRevisionCmd,
+	networkRevisionsCmd,
 	networksCmd,
 	networkStateCmd,
 	networkACLCmd,
 	networkACLsCmd,
 	networkACLLogCmd,
+	networkACLRevisionCmd,
+	networkACLRevisionsCmd,
 	networkAddressSetCmd,
 	networkAddressSetsCmd,
 	networkAllocationsCmd,
@@ -109,13 +128,21 @@ var api10 = []APIEndpoint{
 	operationWait,
 	operationWebsocket,
 	profileCmd,
+	profileRevisionCmd,
+	profileRevisionsCmd,
 	profilesCmd,
 	projectCmd,
 	projectsCmd,
 	projectStateCmd,
 	projectAccessCmd,
+	projectRevisionCmd,
+	projectRevisionsCmd,
+	reportCmd,
+	reportsCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
+	storagePoolMigrationCmd,
+	storagePoolStatisticsCmd,
 	storagePoolsCmd,
 	storagePoolBucketsCmd,
 	storagePoolBucketCmd,
@@ -134,9 +161,13 @@ var api10 = []APIEndpoint{
 	storagePoolVolumeTypeCustomBackupCmd,
 	storagePoolVolumeTypeCustomBackupExportCmd,
 	storagePoolVolumeTypeStateCmd,
+	storagePoolVolumeTypeDiffCmd,
+	storagePoolVolumeTypeMirrorCmd,
 	warningsCmd,
 	warningCmd,
 	metricsCmd,
+	metricsMigrationsCmd,
+	migrationRelaysCmd,
 }
 
 // swagger:operation GET /1.0?public server server_get_untrusted
@@ -236,6 +267,13 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 		authMethods = append(authMethods, api.AuthenticationMethodOIDC)
 	}
 
+	externalAuthCommand, _ := s.GlobalConfig.ExternalAuthentication()
+	if externalAuthCommand != "" {
+		authMethods = append(authMethods, api.AuthenticationMethodExternal)
+	}
+
+	authMethods = append(authMethods, api.AuthenticationMethodAccessLink)
+
 	srv := api.ServerUntrusted{
 		APIExtensions: version.APIExtensions[:d.apiExtensions],
 		APIStatus:     "stable",
@@ -328,6 +366,7 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 		OSName:                 s.OS.ReleaseInfo["NAME"],
 		OSVersion:              s.OS.ReleaseInfo["VERSION_ID"],
 		Project:                projectName,
+		Rootless:               s.OS.Rootless,
 		Server:                 "incus",
 		ServerPid:              os.Getpid(),
 		ServerVersion:          version.Version,
@@ -794,6 +833,7 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	acmeChanged := false
 	bgpChanged := false
 	dnsChanged := false
+	externalAuthChanged := false
 	oidcChanged := false
 	openFGAChanged := false
 	ovnChanged := false
@@ -831,6 +871,19 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 				d.taskPruneImages.Reset()
 			}
 
+		case "images.cache.upstreams", "images.cache.protocol", "images.cache.refresh_interval":
+			imageCacheReset()
+
+			if !s.OS.MockMode {
+				d.taskImageCache.Reset()
+			}
+
+		case "instances.reserved.cpu":
+			deviceTaskBalance(s)
+
+		case "instances.reserved.memory":
+			deviceTaskReserveMemory(s)
+
 		case "loki.api.url", "loki.auth.username", "loki.auth.password", "loki.api.ca_cert", "loki.instance", "loki.labels", "loki.loglevel", "loki.types":
 			// Notify the logging mechanism about changes to the deprecated keys for backward compatibility.
 			loggingChanges["loki"] = struct{}{}
@@ -841,6 +894,9 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim":
 			oidcChanged = true
 
+		case "authentication.external.command", "authentication.external.cache_expiry":
+			externalAuthChanged = true
+
 		case "openfga.api.url", "openfga.api.token", "openfga.store.id":
 			openFGAChanged = true
 
@@ -896,6 +952,14 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		s.Endpoints.NetworkUpdateTrustedProxy(clusterConfig.HTTPSTrustedProxy())
 	}
 
+	value, ok = nodeChanged["cluster.migration_address"]
+	if ok {
+		err := s.Endpoints.MigrationUpdateAddress(value)
+		if err != nil {
+			return err
+		}
+	}
+
 	value, ok = nodeChanged["core.debug_address"]
 	if ok {
 		err := s.Endpoints.PprofUpdateAddress(value)
@@ -984,6 +1048,16 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	if externalAuthChanged {
+		externalAuthCommand, externalAuthCacheExpiry := clusterConfig.ExternalAuthentication()
+
+		if externalAuthCommand == "" {
+			d.externalAuthVerifier = nil
+		} else {
+			d.externalAuthVerifier = external.NewVerifier(externalAuthCommand, externalAuthCacheExpiry)
+		}
+	}
+
 	if openFGAChanged {
 		openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
 		err := d.setupOpenFGA(openfgaAPIURL, openfgaAPIToken, openfgaStoreID)
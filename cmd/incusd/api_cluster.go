@@ -15,6 +15,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/gorilla/mux"
 
@@ -1526,7 +1527,7 @@ func clusterNodeGet(d *Daemon, r *http.Request) response.Response {
 //	  "500":
 //	    $ref: "#/responses/InternalServerError"
 func clusterNodePatch(d *Daemon, r *http.Request) response.Response {
-	return updateClusterNode(d.State(), d.gateway, r, true)
+	return updateClusterNode(d, r, true)
 }
 
 // swagger:operation PUT /1.0/cluster/members/{name} cluster cluster_member_put
@@ -1559,11 +1560,14 @@ func clusterNodePatch(d *Daemon, r *http.Request) response.Response {
 //	  "500":
 //	    $ref: "#/responses/InternalServerError"
 func clusterNodePut(d *Daemon, r *http.Request) response.Response {
-	return updateClusterNode(d.State(), d.gateway, r, false)
+	return updateClusterNode(d, r, false)
 }
 
 // updateClusterNode is shared between clusterNodePut and clusterNodePatch.
-func updateClusterNode(s *state.State, gateway *cluster.Gateway, r *http.Request, isPatch bool) response.Response {
+func updateClusterNode(d *Daemon, r *http.Request, isPatch bool) response.Response {
+	s := d.State()
+	gateway := d.gateway
+
 	name, err := url.PathUnescape(mux.Vars(r)["name"])
 	if err != nil {
 		return response.SmartError(err)
@@ -1675,6 +1679,11 @@ func updateClusterNode(s *state.State, gateway *cluster.Gateway, r *http.Request
 			return err
 		}
 
+		err = clusterValidateLabels(req.Labels)
+		if err != nil {
+			return err
+		}
+
 		if isPatch {
 			// Populate request config with current values.
 			if req.Config == nil {
@@ -1687,6 +1696,18 @@ func updateClusterNode(s *state.State, gateway *cluster.Gateway, r *http.Request
 					}
 				}
 			}
+
+			// Populate request labels with current values.
+			if req.Labels == nil {
+				req.Labels = nodeInfo.Labels
+			} else {
+				for k, v := range nodeInfo.Labels {
+					_, ok := req.Labels[k]
+					if !ok {
+						req.Labels[k] = v
+					}
+				}
+			}
 		}
 
 		// Update node config.
@@ -1695,6 +1716,12 @@ func updateClusterNode(s *state.State, gateway *cluster.Gateway, r *http.Request
 			return fmt.Errorf("Failed to update cluster member config: %w", err)
 		}
 
+		// Update node labels.
+		err = tx.UpdateNodeLabels(ctx, nodeInfo.ID, req.Labels)
+		if err != nil {
+			return fmt.Errorf("Failed to update cluster member labels: %w", err)
+		}
+
 		// Update the description.
 		if req.Description != memberInfo.Description {
 			err = tx.SetDescription(nodeInfo.ID, req.Description)
@@ -1731,6 +1758,11 @@ func updateClusterNode(s *state.State, gateway *cluster.Gateway, r *http.Request
 		cluster.NotifyHeartbeat(s, gateway)
 	}
 
+	err = clusterReloadMemberConfig(d, clusterMemberKeys(memberInfo.Config, req.Config))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(request.ProjectParam(r), lifecycle.ClusterMemberUpdated.Event(name, requestor, nil))
 
@@ -1791,6 +1823,16 @@ func clusterValidateConfig(config map[string]string) error {
 			continue
 		}
 
+		// Server configuration keys overridden for this member.
+		if slices.Contains(clusterConfig.MemberKeys, k) {
+			err := clusterConfig.ValidateMemberKey(k, v)
+			if err != nil {
+				return fmt.Errorf("Invalid cluster configuration key %q value: %w", k, err)
+			}
+
+			continue
+		}
+
 		validator, ok := clusterConfigKeys[k]
 		if !ok {
 			return fmt.Errorf("Invalid cluster configuration key %q", k)
@@ -1805,6 +1847,27 @@ func clusterValidateConfig(config map[string]string) error {
 	return nil
 }
 
+// clusterValidateLabels validates the inventory labels of cluster members.
+func clusterValidateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if k == "" {
+			return errors.New("Cluster member label keys cannot be empty")
+		}
+
+		for _, r := range k {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_./", r) {
+				return fmt.Errorf("Invalid cluster member label key %q: Only letters, digits and the characters -_./ are allowed", k)
+			}
+		}
+
+		if v == "" {
+			return fmt.Errorf("Cluster member label %q cannot have an empty value", k)
+		}
+	}
+
+	return nil
+}
+
 // swagger:operation POST /1.0/cluster/members/{name} cluster cluster_member_post
 //
 //	Rename the cluster member
@@ -2793,6 +2856,13 @@ func clusterNodeStatePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	if req.Snapshot != "" {
+		err = validate.IsURLSegmentSafe(req.Snapshot)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid snapshot name: %w", err))
+		}
+	}
+
 	// Validate the overrides.
 	if req.Action == "evacuate" && req.Mode != "" {
 		// Use the validator from the instance logic.
@@ -2892,6 +2962,19 @@ func clusterNodeStatePost(d *Daemon, r *http.Request) response.Response {
 		}
 
 		run := func(op *operations.Operation) error {
+			// Snapshot all the instances before moving them away.
+			if req.Snapshot != "" {
+				instances, err := clusterMemberInstances(context.Background(), s, name)
+				if err != nil {
+					return err
+				}
+
+				err = snapshotClusterMemberInstances(s, op, instances, req.Snapshot, time.Time{})
+				if err != nil {
+					return err
+				}
+			}
+
 			return evacuateClusterMember(context.Background(), s, op, name, req.Mode, stopFunc, migrateFunc)
 		}
 
@@ -2902,7 +2985,7 @@ func clusterNodeStatePost(d *Daemon, r *http.Request) response.Response {
 
 		return operations.OperationResponse(op)
 	} else if req.Action == "restore" {
-		return restoreClusterMember(d, r)
+		return restoreClusterMember(d, r, req.Snapshot)
 	}
 
 	return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
@@ -262,7 +262,7 @@ func evacuateInstancesFunc(ctx context.Context, inst instance.Instance, opts eva
 	return nil
 }
 
-func restoreClusterMember(d *Daemon, r *http.Request) response.Response {
+func restoreClusterMember(d *Daemon, r *http.Request, snapshotName string) response.Response {
 	s := d.State()
 
 	originName, err := url.PathUnescape(mux.Vars(r)["name"])
@@ -381,6 +381,19 @@ func restoreClusterMember(d *Daemon, r *http.Request) response.Response {
 
 		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberRestored.Event(originName, op.Requestor(), nil))
 
+		// Delete the snapshots taken before the evacuation now that all instances are back.
+		if snapshotName != "" {
+			restoredInstances, err := clusterMemberInstances(context.Background(), s, originName)
+			if err != nil {
+				return err
+			}
+
+			err = deleteClusterMemberSnapshots(s, op, restoredInstances, snapshotName)
+			if err != nil {
+				return fmt.Errorf("Failed to delete snapshot %q of restored instances: %w", snapshotName, err)
+			}
+		}
+
 		return nil
 	}
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"slices"
@@ -12,7 +13,10 @@ import (
 
 	"github.com/gorilla/mux"
 
+	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	clusterConfig "github.com/lxc/incus/v6/internal/server/cluster/config"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
@@ -135,6 +139,11 @@ func clusterGroupsPost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	err = clusterReloadMemberConfig(d, clusterMemberKeys(nil, req.Config))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	lc := lifecycle.ClusterGroupCreated.Event(req.Name, requestor, nil)
 	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
@@ -494,12 +503,18 @@ func clusterGroupPut(d *Daemon, r *http.Request) response.Response {
 
 	// Get the current state.
 	var dbClusterGroup *dbCluster.ClusterGroup
+	var oldConfig map[string]string
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		dbClusterGroup, err = dbCluster.GetClusterGroup(ctx, tx.Tx(), name)
 		if err != nil {
 			return err
 		}
 
+		oldConfig, err = dbCluster.GetClusterGroupConfig(ctx, tx.Tx(), dbClusterGroup.ID)
+		if err != nil {
+			return err
+		}
+
 		nodeClusterGroups, err := dbCluster.GetNodeClusterGroups(ctx, tx.Tx(), dbCluster.NodeClusterGroupFilter{GroupID: &dbClusterGroup.ID})
 		if err != nil {
 			return err
@@ -593,6 +608,11 @@ func clusterGroupPut(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	err = clusterReloadMemberConfig(d, clusterMemberKeys(oldConfig, req.Config))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterGroupUpdated.Event(name, requestor, logger.Ctx{"description": req.Description, "members": req.Members}))
 
@@ -671,6 +691,7 @@ func clusterGroupPatch(d *Daemon, r *http.Request) response.Response {
 	}
 
 	req := clusterGroup.Writable()
+	oldConfig := maps.Clone(clusterGroup.Config)
 
 	// Validate the ETag.
 	etag := []any{clusterGroup.Description, clusterGroup.Members}
@@ -795,6 +816,11 @@ func clusterGroupPatch(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	err = clusterReloadMemberConfig(d, clusterMemberKeys(oldConfig, req.Config))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterGroupUpdated.Event(name, requestor, logger.Ctx{"description": req.Description, "members": req.Members}))
 
@@ -924,6 +950,16 @@ func clusterGroupValidate(config map[string]string) error {
 			continue
 		}
 
+		// Server configuration keys overridden for the members of the group.
+		if slices.Contains(clusterConfig.MemberKeys, k) {
+			err := clusterConfig.ValidateMemberKey(k, v)
+			if err != nil {
+				return fmt.Errorf("Invalid cluster group configuration key %q value: %w", k, err)
+			}
+
+			continue
+		}
+
 		validator, ok := configKeys[k]
 		if !ok {
 			return fmt.Errorf("Invalid cluster group configuration key %q", k)
@@ -938,6 +974,76 @@ func clusterGroupValidate(config map[string]string) error {
 	return nil
 }
 
+// clusterMemberKeys returns the server configuration keys overridden by either the old or the new
+// configuration of a cluster group or member, along with their new value.
+func clusterMemberKeys(oldConfig map[string]string, newConfig map[string]string) map[string]string {
+	keys := map[string]string{}
+
+	for _, key := range clusterConfig.MemberKeys {
+		_, inOld := oldConfig[key]
+		_, inNew := newConfig[key]
+		if inOld || inNew {
+			keys[key] = newConfig[key]
+		}
+	}
+
+	return keys
+}
+
+// clusterReloadMemberConfig reloads the server configuration on all cluster members after a change to
+// the server configuration keys overridden by cluster groups or members.
+func clusterReloadMemberConfig(d *Daemon, keys map[string]string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	s := d.State()
+
+	var config *clusterConfig.Config
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		config, err = clusterConfig.Load(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to load cluster config: %w", err)
+	}
+
+	d.globalConfigMu.Lock()
+	d.globalConfig = config
+	d.globalConfigMu.Unlock()
+
+	// Run any update triggers.
+	err = doApi10UpdateTriggers(d, nil, keys, s.LocalConfig, config)
+	if err != nil {
+		return err
+	}
+
+	// Have the other members reload their configuration too.
+	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+	if err != nil {
+		return err
+	}
+
+	err = notifier(func(client incus.InstanceServer) error {
+		server, etag, err := client.GetServer()
+		if err != nil {
+			return err
+		}
+
+		serverPut := server.Writable()
+		serverPut.Config = keys
+
+		return client.UpdateServer(serverPut, etag)
+	})
+	if err != nil {
+		logger.Warn("Failed to notify other members about config change", logger.Ctx{"err": err})
+	}
+
+	return nil
+}
+
 // clusterGroupFill fills in automatic values.
 func clusterGroupFill(ctx context.Context, s *state.State, servers []string, req *api.ClusterGroupPut) error {
 	// If no config, nothing to fill.
@@ -11,6 +11,7 @@ import (
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/cluster"
+	clusterConfig "github.com/lxc/incus/v6/internal/server/cluster/config"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/instance"
@@ -26,6 +27,7 @@ import (
 type ServerScore struct {
 	NodeInfo  db.NodeInfo
 	Resources *api.Resources
+	Usage     ServerUsage
 	Score     uint8
 }
 
@@ -37,6 +39,29 @@ type ServerUsage struct {
 	CPUTotal    uint64
 }
 
+// newServerUsage returns the current load of a server, leaving the CPU threads and memory reserved
+// for the host out of its capacity.
+func newServerUsage(res *api.Resources, config *clusterConfig.Config) ServerUsage {
+	su := ServerUsage{
+		MemoryUsage: res.Memory.Used,
+		MemoryTotal: res.Memory.Total,
+		CPUUsage:    res.Load.Average1Min,
+		CPUTotal:    res.CPU.Total,
+	}
+
+	reservedCPU := uint64(config.InstancesReservedCPU())
+	if reservedCPU < su.CPUTotal {
+		su.CPUTotal -= reservedCPU
+	}
+
+	reservedMemory := uint64(config.InstancesReservedMemory())
+	if reservedMemory < su.MemoryTotal {
+		su.MemoryTotal -= reservedMemory
+	}
+
+	return su
+}
+
 // sortAndGroupByArch sorts servers by its score and groups them by cpu architecture.
 func sortAndGroupByArch(servers []*ServerScore) map[string][]*ServerScore {
 	sort.Slice(servers, func(i, j int) bool {
@@ -79,6 +104,27 @@ func calculateScore(su *ServerUsage, au *ServerUsage) uint8 {
 
 // calculateServersScore calculates score based on memory and CPU usage for servers in cluster.
 func calculateServersScore(s *state.State, members []db.NodeInfo) (map[string][]*ServerScore, error) {
+	// Load the configuration effective on each member.
+	memberConfigs := make(map[string]*clusterConfig.Config, len(members))
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, member := range members {
+			overrides, err := clusterConfig.MemberOverrides(ctx, tx, member)
+			if err != nil {
+				return err
+			}
+
+			memberConfigs[member.Name], err = s.GlobalConfig.ForMember(overrides)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load cluster member configuration: %w", err)
+	}
+
 	scores := []*ServerScore{}
 	for _, member := range members {
 		clusterMember, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
@@ -91,15 +137,10 @@ func calculateServersScore(s *state.State, members []db.NodeInfo) (map[string][]
 			return nil, fmt.Errorf("Failed to get resources for cluster member: %w", err)
 		}
 
-		su := &ServerUsage{
-			MemoryUsage: res.Memory.Used,
-			MemoryTotal: res.Memory.Total,
-			CPUUsage:    res.Load.Average1Min,
-			CPUTotal:    res.CPU.Total,
-		}
+		su := newServerUsage(res, memberConfigs[member.Name])
 
-		serverScore := calculateScore(su, nil)
-		scores = append(scores, &ServerScore{NodeInfo: member, Resources: res, Score: serverScore})
+		serverScore := calculateScore(&su, nil)
+		scores = append(scores, &ServerScore{NodeInfo: member, Resources: res, Usage: su, Score: serverScore})
 	}
 
 	return sortAndGroupByArch(scores), nil
@@ -194,12 +235,7 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 	// Calculate current and target scores.
 	targetScore := (srcServer.Score + dstServer.Score) / 2
 	currentScore := dstServer.Score
-	targetServerUsage := &ServerUsage{
-		MemoryUsage: dstServer.Resources.Memory.Used,
-		MemoryTotal: dstServer.Resources.Memory.Total,
-		CPUUsage:    dstServer.Resources.Load.Average1Min,
-		CPUTotal:    dstServer.Resources.CPU.Total,
-	}
+	targetServerUsage := dstServer.Usage
 
 	// Prepare the API client.
 	srcNode, err := cluster.Connect(srcServer.NodeInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
@@ -232,7 +268,7 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 			CPUUsage:    float64(memUsage),
 		}
 
-		expectedScore := calculateScore(targetServerUsage, additionalUsage)
+		expectedScore := calculateScore(&targetServerUsage, additionalUsage)
 		if expectedScore >= targetScore {
 			// Skip the instance as it would have too big an impact.
 			continue
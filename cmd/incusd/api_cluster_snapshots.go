@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+var clusterNodeSnapshotsCmd = APIEndpoint{
+	Path: "cluster/members/{name}/snapshots",
+
+	Post: APIEndpointAction{Handler: clusterNodeSnapshotsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var clusterNodeSnapshotCmd = APIEndpoint{
+	Path: "cluster/members/{name}/snapshots/{snapshotName}",
+
+	Delete: APIEndpointAction{Handler: clusterNodeSnapshotDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation POST /1.0/cluster/members/{name}/snapshots cluster cluster_member_snapshots_post
+//
+//	Snapshot all instances of a cluster member
+//
+//	Creates a snapshot with the same name of every instance located on the cluster member.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: snapshots
+//	    description: Snapshots request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterMemberSnapshotsPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterNodeSnapshotsPost(d *Daemon, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s := d.State()
+
+	// Forward request.
+	resp := forwardedResponseToNode(s, r, name)
+	if resp != nil {
+		return resp
+	}
+
+	// Parse the request.
+	req := api.ClusterMemberSnapshotsPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Name == "" {
+		req.Name = fmt.Sprintf("maintenance-%s", time.Now().UTC().Format("20060102-150405"))
+	}
+
+	err = validate.IsURLSegmentSafe(req.Name)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid snapshot name: %w", err))
+	}
+
+	instances, err := clusterMemberInstances(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var expiry time.Time
+	if req.ExpiresAt != nil {
+		expiry = *req.ExpiresAt
+	}
+
+	run := func(op *operations.Operation) error {
+		return snapshotClusterMemberInstances(s, op, instances, req.Name, expiry)
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterMemberSnapshot, nil, map[string]any{"snapshot": req.Name}, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation DELETE /1.0/cluster/members/{name}/snapshots/{snapshot} cluster cluster_member_snapshot_delete
+//
+//	Delete a snapshot of all instances of a cluster member
+//
+//	Deletes the snapshot with that name from every instance located on the cluster member.
+//	Instances which don't have such a snapshot are skipped.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterNodeSnapshotDelete(d *Daemon, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	snapshotName, err := url.PathUnescape(mux.Vars(r)["snapshotName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s := d.State()
+
+	// Forward request.
+	resp := forwardedResponseToNode(s, r, name)
+	if resp != nil {
+		return resp
+	}
+
+	instances, err := clusterMemberInstances(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(op *operations.Operation) error {
+		return deleteClusterMemberSnapshots(s, op, instances, snapshotName)
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterMemberSnapshotDelete, nil, map[string]any{"snapshot": snapshotName}, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// clusterMemberInstances loads all the instances located on the cluster member.
+func clusterMemberInstances(ctx context.Context, s *state.State, name string) ([]instance.Instance, error) {
+	var dbInstances []dbCluster.Instance
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbInstances, err = dbCluster.GetInstances(ctx, tx.Tx(), dbCluster.InstanceFilter{Node: &name})
+		if err != nil {
+			return fmt.Errorf("Failed to get instances: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]instance.Instance, 0, len(dbInstances))
+	for _, dbInst := range dbInstances {
+		inst, err := instance.LoadByProjectAndName(s, dbInst.Project, dbInst.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load instance: %w", err)
+		}
+
+		instances = append(instances, inst)
+	}
+
+	return instances, nil
+}
+
+// snapshotClusterMemberInstances creates a snapshot with the given name of all the instances.
+// Either all the instances get snapshotted or none do. A zero expiry uses the snapshots.expiry
+// configuration of each instance.
+func snapshotClusterMemberInstances(s *state.State, op *operations.Operation, instances []instance.Instance, snapshotName string, expiry time.Time) error {
+	// Check that all the snapshots can be created before taking any.
+	expiries := make([]time.Time, len(instances))
+	for i, inst := range instances {
+		p := inst.Project()
+		err := project.AllowSnapshotCreation(&p)
+		if err != nil {
+			return fmt.Errorf("Cannot snapshot instance %q in project %q: %w", inst.Name(), p.Name, err)
+		}
+
+		_, err = instance.LoadByProjectAndName(s, p.Name, inst.Name()+internalInstance.SnapshotDelimiter+snapshotName)
+		if err == nil {
+			return fmt.Errorf("Snapshot %q already exists for instance %q in project %q", snapshotName, inst.Name(), p.Name)
+		} else if !response.IsNotFoundError(err) {
+			return err
+		}
+
+		expiries[i] = expiry
+		if expiry.IsZero() {
+			expiries[i], err = internalInstance.GetExpiry(time.Now(), inst.ExpandedConfig()["snapshots.expiry"])
+			if err != nil {
+				return fmt.Errorf("Invalid snapshot expiry of instance %q in project %q: %w", inst.Name(), p.Name, err)
+			}
+		}
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	metadata := map[string]any{"snapshot": snapshotName}
+
+	for i, inst := range instances {
+		metadata["snapshot_progress"] = fmt.Sprintf("Snapshotting %q in project %q (%d/%d)", inst.Name(), inst.Project().Name, i+1, len(instances))
+		_ = op.UpdateMetadata(metadata)
+
+		inst.SetOperation(op)
+		err := inst.Snapshot(snapshotName, expiries[i], false)
+		if err != nil {
+			return fmt.Errorf("Failed to snapshot instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		reverter.Add(func() {
+			snapInst, err := instance.LoadByProjectAndName(s, inst.Project().Name, inst.Name()+internalInstance.SnapshotDelimiter+snapshotName)
+			if err == nil {
+				_ = snapInst.Delete(false)
+			}
+		})
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// deleteClusterMemberSnapshots deletes the snapshot with the given name from all the instances which have one.
+func deleteClusterMemberSnapshots(s *state.State, op *operations.Operation, instances []instance.Instance, snapshotName string) error {
+	metadata := map[string]any{"snapshot": snapshotName}
+
+	var errs []error
+	for i, inst := range instances {
+		snapInst, err := instance.LoadByProjectAndName(s, inst.Project().Name, inst.Name()+internalInstance.SnapshotDelimiter+snapshotName)
+		if err != nil {
+			if !response.IsNotFoundError(err) {
+				errs = append(errs, fmt.Errorf("Failed to load snapshot of instance %q in project %q: %w", inst.Name(), inst.Project().Name, err))
+			}
+
+			continue
+		}
+
+		metadata["snapshot_progress"] = fmt.Sprintf("Deleting snapshot of %q in project %q (%d/%d)", inst.Name(), inst.Project().Name, i+1, len(instances))
+		_ = op.UpdateMetadata(metadata)
+
+		snapInst.SetOperation(op)
+		err = snapInst.Delete(false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed to delete snapshot of instance %q in project %q: %w", inst.Name(), inst.Project().Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
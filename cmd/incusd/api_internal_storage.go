@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	internalRecover "github.com/lxc/incus/v6/internal/recover"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/response"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Define API endpoints for storage check actions.
+var internalStorageCheckCmd = APIEndpoint{
+	Path: "storage/check",
+
+	Post: APIEndpointAction{Handler: internalStorageCheck, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// init storage check adds API endpoints to handler slice.
+func init() {
+	apiInternal = append(apiInternal, internalStorageCheckCmd)
+}
+
+// internalStorageCheck compares the volumes on the storage pools of this server with their database records,
+// reporting the orphaned ones and optionally adopting or deleting them.
+func internalStorageCheck(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := internalRecover.StorageCheckPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !slices.Contains([]string{"", "adopt", "delete"}, req.Action) {
+		return response.BadRequest(fmt.Errorf("Invalid action %q", req.Action))
+	}
+
+	poolNames := req.Pools
+	if len(poolNames) == 0 {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			poolNames, err = tx.GetStoragePoolNames(ctx)
+			return err
+		})
+		if err != nil && !response.IsNotFoundError(err) {
+			return response.SmartError(fmt.Errorf("Failed loading storage pools: %w", err))
+		}
+	}
+
+	res := internalRecover.StorageCheckResult{
+		Volumes: []internalRecover.StorageCheckVolume{},
+		Errors:  []string{},
+	}
+
+	for _, poolName := range poolNames {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed loading storage pool %q: %w", poolName, err))
+		}
+
+		orphans, err := pool.ListOrphanVolumes(nil)
+		if err != nil {
+			if errors.Is(err, storageDrivers.ErrNotSupported) {
+				err = fmt.Errorf("Listing volumes isn't supported by the %q driver", pool.Driver().Info().Name)
+			}
+
+			res.Errors = append(res.Errors, fmt.Sprintf("Failed checking pool %q: %v", poolName, err))
+			continue
+		}
+
+		for _, orphan := range orphans {
+			vol := internalRecover.StorageCheckVolume{
+				Name:        orphan.Name,
+				Type:        orphan.Type.Singular(),
+				ContentType: string(orphan.ContentType),
+				Project:     orphan.Project,
+				Pool:        poolName,
+				Missing:     "volume",
+			}
+
+			if orphan.OnStorage {
+				vol.Missing = "record"
+			}
+
+			isInstance := orphan.Type == storageDrivers.VolumeTypeContainer || orphan.Type == storageDrivers.VolumeTypeVM
+
+			// Only custom volumes can be adopted, and instance records are only removed with their instance.
+			var err error
+			switch {
+			case req.Action == "adopt" && orphan.OnStorage && orphan.Type == storageDrivers.VolumeTypeCustom:
+				vol.Action = "adopted"
+				err = pool.AdoptOrphanVolume(orphan, nil)
+			case req.Action == "delete" && (orphan.OnStorage || !isInstance):
+				vol.Action = "deleted"
+				err = pool.DeleteOrphanVolume(orphan, nil)
+			}
+
+			if err != nil {
+				logger.Warn("Failed handling orphaned volume", logger.Ctx{"pool": poolName, "project": orphan.Project, "volume": orphan.Name, "type": orphan.Type, "action": req.Action, "err": err})
+				vol.Action = ""
+				vol.Error = err.Error()
+			}
+
+			res.Volumes = append(res.Volumes, vol)
+		}
+	}
+
+	return response.SyncResponse(true, &res)
+}
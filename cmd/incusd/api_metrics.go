@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,12 +21,14 @@ import (
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/locking"
 	"github.com/lxc/incus/v6/internal/server/metrics"
+	localMigration "github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
 	internalutil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
 )
 
 type metricsCacheEntry struct {
@@ -36,6 +39,7 @@ type metricsCacheEntry struct {
 var (
 	metricsCache     map[string]metricsCacheEntry
 	metricsCacheLock sync.Mutex
+	metricsCPUUsage  = metrics.NewCPUUsageTracker()
 )
 
 var metricsCmd = APIEndpoint{
@@ -44,6 +48,12 @@ var metricsCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: metricsGet, AccessHandler: allowMetrics, AllowUntrusted: true},
 }
 
+var metricsMigrationsCmd = APIEndpoint{
+	Path: "metrics/migrations",
+
+	Get: APIEndpointAction{Handler: metricsMigrationsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewMetrics)},
+}
+
 func allowMetrics(d *Daemon, r *http.Request) response.Response {
 	s := d.State()
 
@@ -214,6 +224,10 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	newMetrics := make(map[string]*metrics.MetricSet, len(projectsToFetch))
 	newMetricsLock := sync.Mutex{}
 
+	// Track the aggregate CPU usage of the projects.
+	projectCPUUsage := map[string]float64{}
+	projectCPUUsageIncomplete := map[string]bool{}
+
 	// Limit metrics build concurrency to number of instances or number of CPU cores (which ever is less).
 	var wg sync.WaitGroup
 	instMetricsCh := make(chan instance.Instance)
@@ -235,9 +249,19 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 						logger.Warn("Failed getting instance metrics", logger.Ctx{"instance": inst.Name(), "project": projectName, "err": err})
 					}
 				} else {
+					// Add the usage relative to the instance limits.
+					cpuUsage := metricsCPUUsage.Usage(projectName+"/"+inst.Name(), instanceMetrics, time.Now())
+					instanceMetrics.AddUsageRatios(cpuUsage)
+
 					// Add the metrics.
 					newMetricsLock.Lock()
 
+					if cpuUsage >= 0 {
+						projectCPUUsage[projectName] += cpuUsage
+					} else {
+						projectCPUUsageIncomplete[projectName] = true
+					}
+
 					// Initialize metrics set for project if needed.
 					if newMetrics[projectName] == nil {
 						newMetrics[projectName] = metrics.NewMetricSet(nil)
@@ -262,6 +286,42 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	wg.Wait()
 	close(instMetricsCh)
 
+	// Add the usage relative to the project limits.
+	projects := map[string]*api.Project{}
+	for _, inst := range instances {
+		p := inst.Project()
+		projects[p.Name] = &p
+	}
+
+	for projectName, projectMetrics := range newMetrics {
+		p := projects[projectName]
+
+		// Unset or invalid limits result in zero and are skipped.
+		var cpuLimit int64
+		if !projectCPUUsageIncomplete[projectName] {
+			cpuLimit, _ = strconv.ParseInt(p.Config["limits.cpu"], 10, 64)
+		}
+
+		memoryLimit, _ := units.ParseByteSizeString(p.Config["limits.memory"])
+		memoryUsage, _ := projectMetrics.MemoryUsage()
+		projectMetrics.AddProjectUsageRatios(projectName, projectCPUUsage[projectName], cpuLimit, memoryUsage, memoryLimit)
+	}
+
+	// Forget the CPU readings of instances which are gone from the fetched projects.
+	fetchedInstances := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		fetchedInstances[inst.Project().Name+"/"+inst.Name()] = true
+	}
+
+	metricsCPUUsage.Prune(func(key string) bool {
+		projectName, _, _ := strings.Cut(key, "/")
+		if !slices.ContainsFunc(projectsToFetch, func(filter dbCluster.InstanceFilter) bool { return *filter.Project == projectName }) {
+			return true
+		}
+
+		return fetchedInstances[key]
+	})
+
 	// Put the new data in the global cache and in response.
 	metricsCacheLock.Lock()
 
@@ -295,6 +355,75 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	return getFilteredMetrics(s, r, compress, metricSet)
 }
 
+// swagger:operation GET /1.0/metrics/migrations metrics metrics_migrations_get
+//
+//	Get the migration statistics
+//
+//	Returns the statistics of the migrations sent by the server which are running or ended in the last 24 hours.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: Migration statistics
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of migration statistics
+//	          items:
+//	            $ref: "#/definitions/MigrationStatistics"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func metricsMigrationsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.QueryParam(r, "project")
+
+	// Forward if requested.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	stats := localMigration.Statistics()
+	if projectName != "" {
+		stats = slices.DeleteFunc(stats, func(entry api.MigrationStatistics) bool {
+			return entry.Project != projectName
+		})
+	}
+
+	return response.SyncResponse(true, stats)
+}
+
 func getFilteredMetrics(s *state.State, r *http.Request, compress bool, metricSet *metrics.MetricSet) response.Response {
 	if !s.GlobalConfig.MetricsAuthentication() {
 		return response.SyncResponsePlain(true, compress, metricSet.String())
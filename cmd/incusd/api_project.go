@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"path"
 	"slices"
 	"strings"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/lxc/incus/v6/internal/filter"
 	"github.com/lxc/incus/v6/internal/jmap"
 	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
@@ -580,7 +582,12 @@ func projectPut(d *Daemon, r *http.Request) response.Response {
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
-	return projectChange(r.Context(), s, project, req)
+	resp := projectChange(r.Context(), s, project, req)
+	if resp == response.EmptySyncResponse {
+		configRevisionRecord(s, r, project.Name, db.ConfigRevisionTypeProject, "", project.ProjectPut, req)
+	}
+
+	return resp
 }
 
 // swagger:operation PATCH /1.0/projects/{name} projects project_patch
@@ -696,7 +703,12 @@ func projectPatch(d *Daemon, r *http.Request) response.Response {
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
-	return projectChange(r.Context(), s, project, req)
+	resp := projectChange(r.Context(), s, project, req)
+	if resp == response.EmptySyncResponse {
+		configRevisionRecord(s, r, project.Name, db.ConfigRevisionTypeProject, "", project.ProjectPut, req)
+	}
+
+	return resp
 }
 
 // Common logic between PUT and PATCH.
@@ -1062,7 +1074,7 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 			}
 
 			// If running, force stop it.
-			if instState.StatusCode != api.Stopped {
+			if instState.StatusCode != api.Stopped && instState.StatusCode != api.Suspended {
 				req := api.InstanceStatePut{
 					Action:  "stop",
 					Timeout: -1,
@@ -1293,6 +1305,8 @@ func projectStateGet(d *Daemon, r *http.Request) response.Response {
 	// Setup the state struct.
 	state := api.ProjectState{}
 
+	var backupsLimit int64
+
 	// Get current limits and usage.
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		result, err := projecthelpers.GetCurrentAllocations(ctx, tx, name)
@@ -1302,12 +1316,28 @@ func projectStateGet(d *Daemon, r *http.Request) response.Response {
 
 		state.Resources = result
 
+		backupsLimit, err = projecthelpers.GetBackupSpaceLimit(tx, name)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	// Get the disk space used by the backups stored on this server.
+	backupsUsage, err := backup.ProjectUsage(name)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed getting disk space used by backups: %w", err))
+	}
+
+	state.Resources["backups"] = api.ProjectStateResource{
+		Limit: backupsLimit,
+		Usage: backupsUsage,
+	}
+
 	return response.SyncResponse(true, &state)
 }
 
@@ -1440,6 +1470,15 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: When an unused cached remote image is flushed in the project
 		"images.remote_cache_expiry": validate.Optional(validate.IsInt64),
 
+		// gendoc:generate(entity=project, group=specific, key=instances.names.scope)
+		// When set to `member`, instance names only need to be unique on each cluster member.
+		// The names of new instances are then qualified with the name of the member they are placed on, as `<name>--<member>`.
+		// ---
+		//  type: string
+		//  defaultdesc: `project`
+		//  shortdesc: Scope in which instance names must be unique (`project` or `member`)
+		"instances.names.scope": validate.Optional(validate.IsOneOf("project", "member")),
+
 		// gendoc:generate(entity=project, group=limits, key=limits.instances)
 		//
 		// ---
@@ -1482,6 +1521,22 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum number of CPUs to use in the project
 		"limits.cpu": validate.Optional(validate.IsUint32),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.overcommit.cpu)
+		// Instances of the project can't be started on a cluster member if the CPU threads committed to the running instances would exceed the CPU threads available to instances multiplied by this ratio.
+		// When the member has its own {config:option}`server-miscellaneous:instances.overcommit.cpu` ratio, the lowest of the two applies.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum CPU overcommit ratio for the instances of the project
+		"limits.overcommit.cpu": validate.Optional(validate.IsPositiveFloat),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.overcommit.memory)
+		// Instances of the project can't be started on a cluster member if the memory committed to the running instances would exceed the memory available to instances multiplied by this ratio.
+		// When the member has its own {config:option}`server-miscellaneous:instances.overcommit.memory` ratio, the lowest of the two applies.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum memory overcommit ratio for the instances of the project
+		"limits.overcommit.memory": validate.Optional(validate.IsPositiveFloat),
+
 		// gendoc:generate(entity=project, group=limits, key=limits.disk)
 		// This value is the maximum value of the aggregate disk space used by all instance volumes, custom volumes, and images of the project.
 		// ---
@@ -1489,6 +1544,22 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum disk space used by the project
 		"limits.disk": validate.Optional(validate.IsSize),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.disk.backups)
+		// This value is the maximum value of the aggregate disk space used on each server by the backups of the instances, custom volumes and buckets of the project.
+		// New backups can't be created once this value is reached, and backups which would go over it are discarded.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum disk space used by the backups of the project
+		"limits.disk.backups": validate.Optional(validate.IsSize),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.disk.images)
+		// This value is the maximum value of the aggregate size of the images of the project.
+		// It only applies when {config:option}`project-features:features.images` is enabled.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum disk space used by the images of the project
+		"limits.disk.images": validate.Optional(validate.IsSize),
+
 		// gendoc:generate(entity=project, group=limits, key=limits.networks)
 		//
 		// ---
@@ -1675,6 +1746,14 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Whether to prevent using devices of type `proxy`
 		"restricted.devices.proxy": isEitherAllowOrBlock,
 
+		// gendoc:generate(entity=project, group=restricted, key=restricted.devices.shmem)
+		// Possible values are `allow` or `block`.
+		// ---
+		//  type: string
+		//  defaultdesc: `allow`
+		//  shortdesc: Whether to prevent using devices of type `shmem`
+		"restricted.devices.shmem": isEitherAllowOrBlock,
+
 		// gendoc:generate(entity=project, group=restricted, key=restricted.devices.nic)
 		// Possible values are `allow`, `block`, or `managed`.
 		//
@@ -1772,6 +1851,13 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  defaultdesc: `block`
 		//  shortdesc: Whether to prevent creating instance or volume snapshots
 		"restricted.snapshots": isEitherAllowOrBlock,
+
+		// gendoc:generate(entity=project, group=specific, key=snapshots.max_count)
+		// This limit applies to each instance of the project, in addition to the instance's own `snapshots.max_count`.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of snapshots of each instance in the project
+		"snapshots.max_count": validate.Optional(validate.IsUint32),
 	}
 
 	// Add the storage pool keys.
@@ -1802,6 +1888,34 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		return fmt.Errorf("Failed loading storage pool names: %w", err)
 	}
 
+	// Image alias namespace keys.
+	imageNamespaceKeys := map[string]func(value string) error{
+		// gendoc:generate(entity=project, group=specific, key=images.namespace.NAMESPACE.server)
+		// Image aliases of the form `NAMESPACE/<name>` used to create or rebuild instances in the project
+		// are looked up on this image server instead of the local image store.
+		// See {ref}`image-alias-namespaces` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Image server to resolve the aliases of the namespace against
+		"server": validate.Optional(validate.IsRequestURL),
+
+		// gendoc:generate(entity=project, group=specific, key=images.namespace.NAMESPACE.protocol)
+		// Possible values are `incus`, `oci` and `simplestreams`.
+		// ---
+		//  type: string
+		//  defaultdesc: `simplestreams`
+		//  shortdesc: Protocol of the image server of the namespace
+		"protocol": validate.Optional(validate.IsOneOf("incus", "oci", "simplestreams")),
+
+		// gendoc:generate(entity=project, group=specific, key=images.namespace.NAMESPACE.pins)
+		// Specify a comma-separated list of `<pattern>=<fingerprint>` entries.
+		// Image aliases of the form `NAMESPACE/<name>` whose name matches a pattern (wildcards are supported) use the pinned image fingerprint instead.
+		// ---
+		//  type: string
+		//  shortdesc: Image fingerprints pinned for the aliases of the namespace
+		"pins": validate.Optional(validate.IsListOf(projectValidateImagePin)),
+	}
+
 	for k, v := range config {
 		key := k
 
@@ -1818,6 +1932,12 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 
 		// Then validate.
 		validator, ok := projectConfigKeys[key]
+
+		_, option, isImageNamespace := projecthelpers.ImageNamespaceKey(key)
+		if isImageNamespace {
+			validator, ok = imageNamespaceKeys[option]
+		}
+
 		if !ok {
 			return fmt.Errorf("Invalid project configuration key %q", k)
 		}
@@ -1838,6 +1958,25 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 	return nil
 }
 
+// projectValidateImagePin validates an image fingerprint pinned for an image alias namespace.
+func projectValidateImagePin(value string) error {
+	pattern, fingerprint, found := strings.Cut(value, "=")
+	if !found || pattern == "" || fingerprint == "" {
+		return fmt.Errorf("Invalid image pin %q, expecting <pattern>=<fingerprint>", value)
+	}
+
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return fmt.Errorf("Invalid image pin pattern %q: %w", pattern, err)
+	}
+
+	if strings.Trim(fingerprint, "0123456789abcdef") != "" || len(fingerprint) > 64 {
+		return fmt.Errorf("Invalid image pin fingerprint %q", fingerprint)
+	}
+
+	return nil
+}
+
 func projectValidateName(name string) error {
 	if name == "" {
 		return errors.New("No name provided")
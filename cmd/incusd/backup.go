@@ -54,6 +54,12 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		args.OptimizedStorage = false
 	}
 
+	// Check that the project has disk space left for the backup.
+	err = backupCheckProjectSpace(s, sourceInst.Project().Name, false)
+	if err != nil {
+		return err
+	}
+
 	// Create the database entry.
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		return tx.CreateInstanceBackup(ctx, args)
@@ -223,12 +229,51 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error closing tar file: %w", err)
 	}
 
+	// Discard the backup if it went over the disk space limit of the project.
+	err = backupCheckProjectSpace(s, sourceInst.Project().Name, true)
+	if err != nil {
+		return err
+	}
+
 	reverter.Success()
 	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupCreated.Event(args.Name, b.Instance(), nil))
 
 	return nil
 }
 
+// backupCheckProjectSpace returns an error if the backups of the project stored on this server use the disk space
+// allowed by its "limits.disk.backups" limit. With created set, the backup which was just written is included in the
+// usage and only going over the limit fails, otherwise reaching it fails as no space is left for a new backup.
+func backupCheckProjectSpace(s *state.State, projectName string, created bool) error {
+	var limit int64
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		limit, err = project.GetBackupSpaceLimit(tx, projectName)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting backups disk space limit: %w", err)
+	}
+
+	if limit < 0 {
+		return nil
+	}
+
+	usage, err := backup.ProjectUsage(projectName)
+	if err != nil {
+		return fmt.Errorf("Failed getting disk space used by backups: %w", err)
+	}
+
+	if usage > limit || (!created && usage == limit) {
+		return fmt.Errorf("Backups of project %q would exceed the %s allowed by \"limits.disk.backups\" (currently using %s)", projectName, units.GetByteSizeStringIEC(limit, 1), units.GetByteSizeStringIEC(usage, 1))
+	}
+
+	return nil
+}
+
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
 func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, tarWriter *instancewriter.InstanceTarWriter) error {
 	// Indicate whether the driver will include a driver-specific optimized header.
@@ -408,6 +453,12 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		args.OptimizedStorage = false
 	}
 
+	// Check that the project has disk space left for the backup.
+	err = backupCheckProjectSpace(s, projectName, false)
+	if err != nil {
+		return err
+	}
+
 	// Create the database entry.
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		return tx.CreateStoragePoolVolumeBackup(ctx, args)
@@ -537,6 +588,12 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		return fmt.Errorf("Error closing tar file: %w", err)
 	}
 
+	// Discard the backup if it went over the disk space limit of the project.
+	err = backupCheckProjectSpace(s, projectName, true)
+	if err != nil {
+		return err
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -655,6 +712,12 @@ func bucketBackupCreate(s *state.State, args db.StoragePoolBucketBackup, project
 		return fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
 	}
 
+	// Check that the project has disk space left for the backup.
+	err = backupCheckProjectSpace(s, projectName, false)
+	if err != nil {
+		return err
+	}
+
 	// Create the database entry
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		return tx.CreateStoragePoolBucketBackup(ctx, args)
@@ -783,6 +846,12 @@ func bucketBackupCreate(s *state.State, args db.StoragePoolBucketBackup, project
 		return fmt.Errorf("Error closing tar file: %w", err)
 	}
 
+	// Discard the backup if it went over the disk space limit of the project.
+	err = backupCheckProjectSpace(s, projectName, true)
+	if err != nil {
+		return err
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -0,0 +1,504 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/instancewriter"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/backup/target"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/idmap"
+	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+var backupPointsCmd = APIEndpoint{
+	Path: "backup-points",
+
+	Get: APIEndpointAction{Handler: backupPointsGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanCreateInstances)},
+}
+
+var backupPointCmd = APIEndpoint{
+	Path: "backup-points/{id}",
+
+	Delete: APIEndpointAction{Handler: backupPointDelete, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanCreateInstances)},
+	Get:    APIEndpointAction{Handler: backupPointGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanCreateInstances)},
+	Post:   APIEndpointAction{Handler: backupPointPost, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanCreateInstances)},
+}
+
+// backupTargetLoad returns the backup target configured on the server.
+func backupTargetLoad(s *state.State) (target.Target, error) {
+	driver, repository, password := s.GlobalConfig.BackupsTarget()
+	if driver == "" || repository == "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "No backup target is configured")
+	}
+
+	return target.New(driver, repository, password)
+}
+
+// backupTargetPoint returns the point of the project with the given ID.
+func backupTargetPoint(ctx context.Context, tgt target.Target, projectName string, id string) (*target.Point, error) {
+	points, err := tgt.Points(ctx, projectName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, point := range points {
+		if point.ID == id {
+			return &point, nil
+		}
+	}
+
+	return nil, api.StatusErrorf(http.StatusNotFound, "Backup point not found")
+}
+
+// backupPointToAPI converts a point of the backup target to its API representation.
+func backupPointToAPI(point target.Point) *api.BackupPoint {
+	return &api.BackupPoint{
+		ID:        point.ID,
+		Name:      point.Name,
+		Instance:  point.Instance,
+		Project:   point.Project,
+		CreatedAt: point.CreatedAt,
+	}
+}
+
+// backupTargetCreate stores a backup of the instance in the backup target.
+// The backup is stored as an uncompressed tarball, leaving the deduplication and compression to the target.
+func backupTargetCreate(s *state.State, tgt target.Target, name string, instanceOnly bool, sourceInst instance.Instance, op *operations.Operation) error {
+	l := logger.AddContext(logger.Ctx{"project": sourceInst.Project().Name, "instance": sourceInst.Name(), "name": name})
+	l.Debug("Instance backup to backup target started")
+	defer l.Debug("Instance backup to backup target finished")
+
+	pool, err := storagePools.LoadByInstance(s, sourceInst)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	// Get IDMap to unshift container as the tarball is created.
+	var idmapSet *idmap.Set
+	if sourceInst.Type() == instancetype.Container {
+		c := sourceInst.(instance.Container)
+		idmapSet, err = c.DiskIdmap()
+		if err != nil {
+			return fmt.Errorf("Error getting container IDMAP: %w", err)
+		}
+	}
+
+	tarPipeReader, tarPipeWriter := io.Pipe()
+	defer func() { _ = tarPipeWriter.Close() }()
+
+	backupProgressWriter := &ioprogress.ProgressWriter{
+		WriteCloser: tarPipeWriter,
+		Tracker: &ioprogress.ProgressTracker{
+			Handler: func(value, speed int64) {
+				meta := op.Metadata()
+				if meta == nil {
+					meta = make(map[string]any)
+				}
+
+				progressText := fmt.Sprintf("%s (%s/s)", units.GetByteSizeString(value, 2), units.GetByteSizeString(speed, 2))
+				meta["create_backup_progress"] = progressText
+				_ = op.UpdateMetadata(meta)
+			},
+		},
+	}
+
+	tarWriter := instancewriter.NewInstanceTarWriter(backupProgressWriter, idmapSet)
+
+	// Store the tarball as it is written.
+	storeRes := make(chan error, 1)
+	go func() {
+		point := target.Point{Project: sourceInst.Project().Name, Instance: sourceInst.Name(), Name: name}
+
+		err := tgt.Store(context.TODO(), point, tarPipeReader)
+
+		// Unblock the tarball writer if the target stopped reading.
+		_ = tarPipeReader.CloseWithError(err)
+		storeRes <- err
+	}()
+
+	err = backupWriteIndex(sourceInst, pool, false, !instanceOnly, tarWriter)
+	if err != nil {
+		_ = tarPipeWriter.CloseWithError(err)
+		<-storeRes
+		return fmt.Errorf("Error writing backup index file: %w", err)
+	}
+
+	err = pool.BackupInstance(sourceInst, tarWriter, false, !instanceOnly, nil)
+	if err != nil {
+		_ = tarPipeWriter.CloseWithError(err)
+		<-storeRes
+		return fmt.Errorf("Backup create: %w", err)
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		_ = tarPipeWriter.CloseWithError(err)
+		<-storeRes
+		return fmt.Errorf("Error closing tarball writer: %w", err)
+	}
+
+	_ = tarPipeWriter.Close()
+
+	err = <-storeRes
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// swagger:operation GET /1.0/backup-points instances backup_points_get
+//
+//  Get the backup points
+//
+//  Returns a list of restorable points (URLs) stored in the backup target.
+//
+//  ---
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//    - in: query
+//      name: instance
+//      description: Only list the points of this instance
+//      type: string
+//      example: c1
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of endpoints
+//            items:
+//              type: string
+//            example: |-
+//              [
+//                "/1.0/backup-points/4bba301e1b62dcf7c5e5d84e4b3a6bf2a2e1a1e5b4c3d2e1f0a9b8c7d6e5f4a3"
+//              ]
+//    "400":
+//      $ref: "#/responses/BadRequest"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/backup-points?recursion=1 instances backup_points_get_recursion1
+//
+//	Get the backup points
+//
+//	Returns a list of restorable points (structs) stored in the backup target.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: instance
+//	    description: Only list the points of this instance
+//	    type: string
+//	    example: c1
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of backup points
+//	          items:
+//	            $ref: "#/definitions/BackupPoint"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupPointsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	recursion := localUtil.IsRecursionRequest(r)
+
+	tgt, err := backupTargetLoad(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	points, err := tgt.Points(r.Context(), projectName, request.QueryParam(r, "instance"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !recursion {
+		urls := make([]string, 0, len(points))
+		for _, point := range points {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "backup-points", point.ID).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	result := make([]*api.BackupPoint, 0, len(points))
+	for _, point := range points {
+		result = append(result, backupPointToAPI(point))
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation GET /1.0/backup-points/{id} instances backup_point_get
+//
+//	Get the backup point
+//
+//	Gets a specific restorable point stored in the backup target.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Backup point
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/BackupPoint"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupPointGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	tgt, err := backupTargetLoad(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	point, err := backupTargetPoint(r.Context(), tgt, projectName, id)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, backupPointToAPI(*point))
+}
+
+// swagger:operation POST /1.0/backup-points/{id} instances backup_point_post
+//
+//	Restore the backup point
+//
+//	Creates a new instance from a restorable point stored in the backup target.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: backup point
+//	    description: Restore request
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/BackupPointPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupPointPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.BackupPointPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return response.BadRequest(err)
+	}
+
+	tgt, err := backupTargetLoad(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	point, err := backupTargetPoint(r.Context(), tgt, projectName, id)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Stream the tarball of the point into the regular import of backups.
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		err := tgt.Restore(context.TODO(), point.ID, pipeWriter)
+		_ = pipeWriter.CloseWithError(err)
+	}()
+
+	defer func() { _ = pipeReader.Close() }()
+
+	return createFromBackup(s, r, projectName, pipeReader, req.Pool, req.Name)
+}
+
+// swagger:operation DELETE /1.0/backup-points/{id} instances backup_point_delete
+//
+//	Delete the backup point
+//
+//	Deletes a restorable point from the backup target.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupPointDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	tgt, err := backupTargetLoad(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	point, err := backupTargetPoint(r.Context(), tgt, projectName, id)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	remove := func(op *operations.Operation) error {
+		return tgt.Delete(context.TODO(), point.ID)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["backup-points"] = []api.URL{*api.NewURL().Path(version.APIVersion, "backup-points", point.ID)}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.BackupRemove, resources, nil, remove, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
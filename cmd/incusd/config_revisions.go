@@ -0,0 +1,690 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/network/acl"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var profileRevisionsCmd = APIEndpoint{
+	Path: "profiles/{name}/revisions",
+
+	Get: APIEndpointAction{Handler: profileRevisionsGet, AccessHandler: allowPermission(auth.ObjectTypeProfile, auth.EntitlementCanView, "name")},
+}
+
+var profileRevisionCmd = APIEndpoint{
+	Path: "profiles/{name}/revisions/{revision}",
+
+	Get: APIEndpointAction{Handler: profileRevisionsGet, AccessHandler: allowPermission(auth.ObjectTypeProfile, auth.EntitlementCanView, "name")},
+}
+
+var projectRevisionsCmd = APIEndpoint{
+	Path: "projects/{name}/revisions",
+
+	Get: APIEndpointAction{Handler: projectRevisionsGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView, "name")},
+}
+
+var projectRevisionCmd = APIEndpoint{
+	Path: "projects/{name}/revisions/{revision}",
+
+	Get: APIEndpointAction{Handler: projectRevisionsGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView, "name")},
+}
+
+var networkRevisionsCmd = APIEndpoint{
+	Path: "networks/{networkName}/revisions",
+
+	Get: APIEndpointAction{Handler: networkRevisionsGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+}
+
+var networkRevisionCmd = APIEndpoint{
+	Path: "networks/{networkName}/revisions/{revision}",
+
+	Get: APIEndpointAction{Handler: networkRevisionsGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+}
+
+var networkACLRevisionsCmd = APIEndpoint{
+	Path: "network-acls/{name}/revisions",
+
+	Get: APIEndpointAction{Handler: networkACLRevisionsGet, AccessHandler: allowPermission(auth.ObjectTypeNetworkACL, auth.EntitlementCanView, "name")},
+}
+
+var networkACLRevisionCmd = APIEndpoint{
+	Path: "network-acls/{name}/revisions/{revision}",
+
+	Get: APIEndpointAction{Handler: networkACLRevisionsGet, AccessHandler: allowPermission(auth.ObjectTypeNetworkACL, auth.EntitlementCanView, "name")},
+}
+
+// configRevisionDocument converts an entity configuration to the generic document recorded in its revisions.
+func configRevisionDocument(config any) (map[string]any, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	document := map[string]any{}
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// configRevisionChanges returns the fields which differ between two documents, sorted.
+// The changes of map fields, like config or devices, are reported per key, like "config.limits.cpu".
+func configRevisionChanges(oldDocument map[string]any, newDocument map[string]any) []string {
+	changes := []string{}
+
+	compare := func(prefix string, oldFields map[string]any, newFields map[string]any) []string {
+		var changed []string
+
+		for field, oldValue := range oldFields {
+			newValue, found := newFields[field]
+			if !found || !reflect.DeepEqual(oldValue, newValue) {
+				changed = append(changed, prefix+field)
+			}
+		}
+
+		for field := range newFields {
+			_, found := oldFields[field]
+			if !found {
+				changed = append(changed, prefix+field)
+			}
+		}
+
+		return changed
+	}
+
+	for _, field := range compare("", oldDocument, newDocument) {
+		oldValue, _ := oldDocument[field].(map[string]any)
+		newValue, _ := newDocument[field].(map[string]any)
+		if oldValue == nil && newValue == nil {
+			changes = append(changes, field)
+			continue
+		}
+
+		changes = append(changes, compare(field+".", oldValue, newValue)...)
+	}
+
+	slices.Sort(changes)
+
+	return changes
+}
+
+// configRevisionRecord records the previous configuration of an entity after it was successfully updated.
+// Failures are only logged as the update itself was already applied.
+func configRevisionRecord(s *state.State, r *http.Request, projectName string, entityType string, entityName string, oldConfig any, newConfig any) {
+	l := logger.AddContext(logger.Ctx{"project": projectName, "type": entityType, "name": entityName})
+
+	oldDocument, err := configRevisionDocument(oldConfig)
+	if err != nil {
+		l.Warn("Failed recording configuration revision", logger.Ctx{"err": err})
+		return
+	}
+
+	newDocument, err := configRevisionDocument(newConfig)
+	if err != nil {
+		l.Warn("Failed recording configuration revision", logger.Ctx{"err": err})
+		return
+	}
+
+	changes := configRevisionChanges(oldDocument, newDocument)
+	if len(changes) == 0 {
+		return
+	}
+
+	revision := api.ConfigRevision{
+		CreatedAt: time.Now(),
+		Changes:   changes,
+		Document:  oldDocument,
+	}
+
+	requestor := request.CreateRequestor(r)
+	if requestor != nil {
+		revision.Requestor = fmt.Sprintf("%s/%s", requestor.Protocol, requestor.Username)
+	}
+
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.CreateConfigRevision(ctx, projectName, entityType, entityName, revision)
+		return err
+	})
+	if err != nil {
+		l.Warn("Failed recording configuration revision", logger.Ctx{"err": err})
+	}
+}
+
+// networkRevisionConfig returns the configuration of a network as recorded in its revisions.
+// When clustered, the member specific keys are left out as they can only be updated with a target.
+func networkRevisionConfig(n network.Network, clustered bool) api.NetworkPut {
+	config := localUtil.CopyConfig(n.Config())
+	if clustered {
+		for _, key := range db.NodeSpecificNetworkConfig {
+			delete(config, key)
+		}
+	}
+
+	return api.NetworkPut{
+		Description: n.Description(),
+		Config:      config,
+	}
+}
+
+// configRevisionsResponse returns the recorded configuration revisions of an entity.
+// When the revision is part of the request path, only that revision is returned.
+// The entity path parts and project are used to generate the URLs of the revisions.
+func configRevisionsResponse(s *state.State, r *http.Request, projectName string, entityType string, entityName string, entityPath []string, entityProject string) response.Response {
+	revisionStr, found := mux.Vars(r)["revision"]
+	if found {
+		revisionNumber, err := strconv.ParseInt(revisionStr, 10, 64)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid revision %q", revisionStr))
+		}
+
+		var revision *api.ConfigRevision
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			revision, err = tx.GetConfigRevision(ctx, projectName, entityType, entityName, revisionNumber)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, revision)
+	}
+
+	var revisions []api.ConfigRevision
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		revisions, err = tx.GetConfigRevisions(ctx, projectName, entityType, entityName)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, revisions)
+	}
+
+	urls := make([]string, 0, len(revisions))
+	for _, revision := range revisions {
+		pathParts := append([]string{version.APIVersion}, entityPath...)
+		pathParts = append(pathParts, "revisions", strconv.FormatInt(revision.Revision, 10))
+		urls = append(urls, api.NewURL().Path(pathParts...).Project(entityProject).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation GET /1.0/profiles/{name}/revisions profiles profile_revisions_get
+//
+//	Get the profile revisions
+//
+//	Returns the recorded previous configurations of the profile, oldest first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: recursion
+//	    description: Return the revisions rather than their URLs
+//	    type: integer
+//	    example: 1
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints (or revisions with recursion)
+//	          items:
+//	            type: string
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/profiles/{name}/revisions/{revision} profiles profile_revision_get
+//
+//	Get the profile revision
+//
+//	Returns a recorded previous configuration of the profile.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Revision
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ConfigRevision"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func profileRevisionsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	p, err := project.ProfileProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProfile(ctx, tx.Tx(), p.Name, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return configRevisionsResponse(s, r, p.Name, db.ConfigRevisionTypeProfile, name, []string{"profiles", name}, p.Name)
+}
+
+// swagger:operation GET /1.0/projects/{name}/revisions projects project_revisions_get
+//
+//	Get the project revisions
+//
+//	Returns the recorded previous configurations of the project, oldest first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: recursion
+//	    description: Return the revisions rather than their URLs
+//	    type: integer
+//	    example: 1
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints (or revisions with recursion)
+//	          items:
+//	            type: string
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/projects/{name}/revisions/{revision} projects project_revision_get
+//
+//	Get the project revision
+//
+//	Returns a recorded previous configuration of the project.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Revision
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ConfigRevision"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectRevisionsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProject(ctx, tx.Tx(), name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return configRevisionsResponse(s, r, name, db.ConfigRevisionTypeProject, "", []string{"projects", name}, "")
+}
+
+// swagger:operation GET /1.0/networks/{name}/revisions networks network_revisions_get
+//
+//	Get the network revisions
+//
+//	Returns the recorded previous configurations of the network, oldest first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: recursion
+//	    description: Return the revisions rather than their URLs
+//	    type: integer
+//	    example: 1
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints (or revisions with recursion)
+//	          items:
+//	            type: string
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/networks/{name}/revisions/{revision} networks network_revision_get
+//
+//	Get the network revision
+//
+//	Returns a recorded previous configuration of the network.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Revision
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ConfigRevision"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkRevisionsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	networkName, err := url.PathUnescape(mux.Vars(r)["networkName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	n, err := network.LoadByName(s, projectName, networkName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading network: %w", err))
+	}
+
+	// Check if project allows access to network.
+	if !project.NetworkAllowed(reqProject.Config, networkName, n.IsManaged()) {
+		return response.SmartError(api.StatusErrorf(http.StatusNotFound, "Network not found"))
+	}
+
+	return configRevisionsResponse(s, r, projectName, db.ConfigRevisionTypeNetwork, networkName, []string{"networks", networkName}, projectName)
+}
+
+// swagger:operation GET /1.0/network-acls/{name}/revisions network-acls network_acl_revisions_get
+//
+//	Get the network ACL revisions
+//
+//	Returns the recorded previous configurations of the network ACL, oldest first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: recursion
+//	    description: Return the revisions rather than their URLs
+//	    type: integer
+//	    example: 1
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints (or revisions with recursion)
+//	          items:
+//	            type: string
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/network-acls/{name}/revisions/{revision} network-acls network_acl_revision_get
+//
+//	Get the network ACL revision
+//
+//	Returns a recorded previous configuration of the network ACL.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Revision
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ConfigRevision"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkACLRevisionsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	aclName, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	_, err = acl.LoadByName(s, projectName, aclName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return configRevisionsResponse(s, r, projectName, db.ConfigRevisionTypeNetworkACL, aclName, []string{"network-acls", aclName}, projectName)
+}
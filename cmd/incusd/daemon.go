@@ -31,7 +31,10 @@ import (
 	"github.com/lxc/incus/v6/internal/rsync"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
 	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/auth/accesslink"
+	"github.com/lxc/incus/v6/internal/server/auth/external"
 	"github.com/lxc/incus/v6/internal/server/auth/oidc"
+	"github.com/lxc/incus/v6/internal/server/auth/workload"
 	"github.com/lxc/incus/v6/internal/server/bgp"
 	"github.com/lxc/incus/v6/internal/server/certificate"
 	"github.com/lxc/incus/v6/internal/server/cluster"
@@ -104,6 +107,7 @@ type Daemon struct {
 
 	// Indexes of tasks that need to be reset when their execution interval changes
 	taskPruneImages      *task.Task
+	taskImageCache       *task.Task
 	taskClusterHeartbeat *task.Task
 
 	// Stores startup time of daemon
@@ -121,6 +125,8 @@ type Daemon struct {
 
 	oidcVerifier *oidc.Verifier
 
+	externalAuthVerifier *external.Verifier
+
 	// Stores last heartbeat node information to detect node changes.
 	lastNodeList *cluster.APIHeartbeat
 
@@ -467,6 +473,60 @@ func (d *Daemon) getTrustedCertificates() (map[certificate.Type]map[string]x509.
 	return certs, nil
 }
 
+// checkWorkloadInstance checks that the instance of a workload token still exists and allows tokens.
+func (d *Daemon) checkWorkloadInstance(claims *workload.Claims) error {
+	inst, err := instance.LoadByProjectAndName(d.State(), claims.Project, claims.Instance)
+	if err != nil {
+		return fmt.Errorf("Invalid workload token: %w", err)
+	}
+
+	if inst.LocalConfig()["volatile.uuid"] != claims.Subject {
+		return errors.New("Invalid workload token: Instance was re-created")
+	}
+
+	if util.IsFalseOrEmpty(inst.ExpandedConfig()["security.guestapi.tokens"]) {
+		return errors.New("Invalid workload token: Instance doesn't allow workload tokens")
+	}
+
+	return nil
+}
+
+// checkAccessLink checks the secret and expiry of an instance access link and returns the user name of its requests.
+func (d *Daemon) checkAccessLink(ctx context.Context, linkID string, secret string) (string, error) {
+	var link *db.InstanceAccessLink
+
+	err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		link, err = tx.GetInstanceAccessLink(ctx, linkID)
+
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("Invalid access link: %w", err)
+	}
+
+	if !accesslink.CheckHash(secret, link.SecretHash) {
+		return "", errors.New("Invalid access link: Wrong secret")
+	}
+
+	if time.Now().After(link.ExpiresAt) {
+		return "", errors.New("Invalid access link: Expired")
+	}
+
+	// Record the use at most once a minute, rather than writing to the database on every request.
+	if time.Since(link.LastUsedAt) > time.Minute {
+		err = d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateInstanceAccessLinkLastUse(ctx, linkID, time.Now())
+		})
+		if err != nil {
+			logger.Warn("Failed recording use of instance access link", logger.Ctx{"id": linkID, "err": err})
+		}
+	}
+
+	return accesslink.Username(link.Project, link.Instance, link.ID, link.Access), nil
+}
+
 // Authenticate validates an incoming http Request
 // It will check over what protocol it came, what type of request it is and
 // will validate the TLS certificate.
@@ -541,6 +601,42 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		}
 	}
 
+	// Check for workload tokens issued to instances over /dev/incus.
+	if workload.IsRequest(r) {
+		claims, err := workload.Verify(d.endpoints.NetworkCert(), r)
+		if err != nil {
+			return false, "", "", err
+		}
+
+		err = d.checkWorkloadInstance(claims)
+		if err != nil {
+			return false, "", "", err
+		}
+
+		return true, workload.Username(claims.Project, claims.Instance), api.AuthenticationMethodWorkload, nil
+	}
+
+	// Check for instance access links.
+	linkID, secret, ok := accesslink.Parse(r)
+	if ok {
+		username, err := d.checkAccessLink(r.Context(), linkID, secret)
+		if err != nil {
+			return false, "", "", err
+		}
+
+		return true, username, api.AuthenticationMethodAccessLink, nil
+	}
+
+	// Check for basic credentials validated by the external authentication command.
+	if d.externalAuthVerifier != nil && d.externalAuthVerifier.IsRequest(r) {
+		userName, err := d.externalAuthVerifier.Auth(d.shutdownCtx, r)
+		if err != nil {
+			return false, "", "", err
+		}
+
+		return true, userName, api.AuthenticationMethodExternal, nil
+	}
+
 	// Check for JWT token signed by an OpenID Connect provider.
 	if d.oidcVerifier != nil && d.oidcVerifier.IsRequest(r) {
 		userName, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
@@ -657,6 +753,14 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				_ = response.Unauthorized(err).Render(w)
 				return
 			}
+
+			var externalAuthError *external.AuthError
+			if errors.As(err, &externalAuthError) {
+				d.externalAuthVerifier.WriteHeaders(w)
+
+				_ = response.Unauthorized(err).Render(w)
+				return
+			}
 		}
 
 		// Reject internal queries to remote, non-cluster, clients
@@ -1083,6 +1187,13 @@ func (d *Daemon) init() error {
 
 	dbWarnings = append(dbWarnings, d.os.CGInfo.Warnings()...)
 
+	if d.os.Rootless {
+		dbWarnings = append(dbWarnings, dbCluster.Warning{
+			TypeCode:    warningtype.RootlessMode,
+			LastMessage: "Only the dir storage driver is supported and instances can't be reached from the host network or use host devices",
+		})
+	}
+
 	logger.Infof(" - cgroup layout: %s", d.os.CGInfo.Mode())
 
 	for _, w := range dbWarnings {
@@ -1429,6 +1540,7 @@ func (d *Daemon) init() error {
 
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := d.globalConfig.OIDCServer()
+	externalAuthCommand, externalAuthCacheExpiry := d.globalConfig.ExternalAuthentication()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 	openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
 	instancePlacementScriptlet := d.globalConfig.InstancesPlacementScriptlet()
@@ -1459,6 +1571,11 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup external authentication.
+	if externalAuthCommand != "" {
+		d.externalAuthVerifier = external.NewVerifier(externalAuthCommand, externalAuthCacheExpiry)
+	}
+
 	// Setup OpenFGA authorization.
 	if openfgaAPIURL != "" && openfgaStoreID != "" && openfgaAPIToken != "" {
 		err = d.setupOpenFGA(openfgaAPIURL, openfgaAPIToken, openfgaStoreID)
@@ -1550,6 +1667,14 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	migrationAddress := d.localConfig.MigrationAddress()
+	if migrationAddress != "" {
+		err = d.endpoints.MigrationUpdateAddress(migrationAddress)
+		if err != nil {
+			return err
+		}
+	}
+
 	storageBucketsAddress := d.localConfig.StorageBucketsAddress()
 	if storageBucketsAddress != "" {
 		err = d.endpoints.UpStorageBuckets(storageBucketsAddress)
@@ -1669,6 +1794,9 @@ func (d *Daemon) init() error {
 		// Auto-update images (every 6 hours, configurable)
 		d.tasks.Add(autoUpdateImagesTask(d))
 
+		// Refresh the upstream image catalogs (every 6 hours, configurable)
+		d.taskImageCache = d.tasks.Add(imageCacheRefreshTask(d))
+
 		// Auto-update instance types (daily)
 		d.tasks.Add(instanceRefreshTypesTask(d))
 
@@ -1681,16 +1809,49 @@ func (d *Daemon) init() error {
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d))
 
+		// Trim storage pools (minutely check of configurable cron expression)
+		d.tasks.Add(autoTrimStoragePoolsTask(d))
+
+		// Scrub storage pools (minutely check of configurable cron expression)
+		d.tasks.Add(autoScrubStoragePoolsTask(d))
+
+		// Check the health of storage pools (hourly)
+		d.tasks.Add(storagePoolHealthTask(d))
+
+		// Send storage events when storage pools cross their usage threshold (every 5 minutes)
+		d.tasks.Add(storagePoolThresholdTask(d))
+
+		// Verify the integrity of custom volumes (minutely check of configurable cron expression)
+		d.tasks.Add(autoVerifyCustomVolumesIntegrityTask(d))
+
+		// Apply scheduled NIC limits (minutely check of configurable time windows)
+		d.tasks.Add(nicLimitsScheduleTask(d))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d))
 
+		// Remove expired lifecycle events from the events history (hourly)
+		d.tasks.Add(pruneEventsHistoryTask(d))
+
 		// Auto-renew server certificate (daily)
 		d.tasks.Add(autoRenewCertificateTask(d))
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Remove expired instance access links (hourly)
+		d.tasks.Add(pruneExpiredInstanceAccessLinksTask(d))
+
+		// Generate capacity and inventory reports (minutely check of configurable cron expression)
+		d.tasks.Add(generateReportsTask(d))
+
+		// Remove expired reports (hourly)
+		d.tasks.Add(pruneExpiredReportsTask(d))
 	}
 
+	// Record the lifecycle events for replay
+	d.startEventsHistory()
+
 	// Start all background tasks
 	d.tasks.Start(d.shutdownCtx)
 
@@ -1700,6 +1861,9 @@ func (d *Daemon) init() error {
 	// Re-balance in case things changed while the daemon was down
 	deviceTaskBalance(d.State())
 
+	// Protect the memory reserved for the host.
+	deviceTaskReserveMemory(d.State())
+
 	// Unblock incoming requests
 	d.waitReady.Cancel()
 
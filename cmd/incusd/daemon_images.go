@@ -15,6 +15,7 @@ import (
 	internalIO "github.com/lxc/incus/v6/internal/io"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/locking"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/response"
@@ -117,7 +118,7 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			// Look for a matching alias.  Note, this err message is lost!
 			entry, _, err := remote.GetImageAliasType(args.Type, fp)
 			if err == nil {
-				fp = entry.Target
+				fp = instance.ImageAliasTarget(*entry, s.OS.Architectures)
 			}
 
 			// Expand partial fingerprints
@@ -199,9 +200,11 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			}
 		}
 	} else if response.IsNotFoundError(err) {
+		var srcID int
+
 		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			// Check if the image already exists in some other project.
-			_, imgInfo, err = tx.GetImageFromAnyProject(ctx, fp)
+			srcID, imgInfo, err = tx.GetImageFromAnyProject(ctx, fp)
 
 			return err
 		})
@@ -237,6 +240,11 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 					return err
 				}
 
+				err = tx.CopyImageProvenance(ctx, srcID, id)
+				if err != nil {
+					return fmt.Errorf("Failed copying image provenance: %w", err)
+				}
+
 				return tx.CreateImageSource(ctx, id, args.Server, args.Protocol, args.Certificate, alias)
 			})
 			if err != nil {
@@ -349,6 +357,8 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 		op.SetCanceler(canceler)
 	}
 
+	var imageMeta *api.ImageMetadata
+
 	if slices.Contains([]string{"incus", "lxd", "oci", "simplestreams"}, protocol) {
 		// Create the target files
 		dest, err := os.Create(destName)
@@ -450,6 +460,14 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 		if err != nil {
 			return nil, false, err
 		}
+
+		// Get the SBOM document from the image metadata.
+		if info.Provenance != nil || info.SBOM {
+			imageMeta, _, err = getImageMetadata(destName)
+			if err != nil {
+				return nil, false, err
+			}
+		}
 	} else if protocol == "direct" {
 		// Setup HTTP client
 		httpClient, err := localUtil.HTTPClient(args.Certificate, s.Proxy)
@@ -516,7 +534,8 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 		}
 
 		// Parse the image
-		imageMeta, imageType, err := getImageMetadata(destName)
+		var imageType string
+		imageMeta, imageType, err = getImageMetadata(destName)
 		if err != nil {
 			return nil, false, err
 		}
@@ -556,7 +575,12 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Create the database entry
-		return tx.CreateImage(ctx, args.ProjectName, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		err := tx.CreateImage(ctx, args.ProjectName, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		if err != nil {
+			return err
+		}
+
+		return imageCreateProvenance(ctx, tx, args.ProjectName, info.Fingerprint, imageMeta)
 	})
 	if err != nil {
 		return nil, false, fmt.Errorf("Failed creating image record: %w", err)
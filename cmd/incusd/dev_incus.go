@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,24 +13,35 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 	"golang.org/x/sys/unix"
 
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/server/auth/workload"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/events"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/internal/server/ucred"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	apiGuest "github.com/lxc/incus/v6/shared/api/guest"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
 	"github.com/lxc/incus/v6/shared/ws"
 )
 
@@ -260,6 +272,218 @@ var devIncusDevicesGet = devIncusHandler{"/1.0/devices", func(d *Daemon, c insta
 	return response.DevIncusResponse(http.StatusOK, c.ExpandedDevices(), "json", c.Type() == instancetype.VM)
 }}
 
+// workloadTokenLifetime is how long the API tokens issued to instances are valid for.
+const workloadTokenLifetime = time.Hour
+
+var devIncusTokenPost = devIncusHandler{"/1.0/token", func(d *Daemon, c instance.Instance, w http.ResponseWriter, r *http.Request) response.Response {
+	if util.IsFalse(c.ExpandedConfig()["security.guestapi"]) {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusForbidden, "not authorized"), c.Type() == instancetype.VM)
+	}
+
+	if util.IsFalseOrEmpty(c.ExpandedConfig()["security.guestapi.tokens"]) {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusForbidden, "not authorized"), c.Type() == instancetype.VM)
+	}
+
+	if r.Method != "POST" {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusMethodNotAllowed, "%s", fmt.Sprintf("method %q not allowed", r.Method)), c.Type() == instancetype.VM)
+	}
+
+	s := d.State()
+
+	// The token is only useful if the main API is reachable over the network.
+	addresses, err := localUtil.ListenAddresses(s.LocalConfig.HTTPSAddress())
+	if err != nil {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusInternalServerError, "internal server error"), c.Type() == instancetype.VM)
+	}
+
+	if len(addresses) == 0 {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusServiceUnavailable, "API isn't available on the network"), c.Type() == instancetype.VM)
+	}
+
+	token, expiresAt, err := workload.NewToken(s.Endpoints.NetworkCert(), c.Project().Name, c.Name(), c.LocalConfig()["volatile.uuid"], workloadTokenLifetime)
+	if err != nil {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusInternalServerError, "internal server error"), c.Type() == instancetype.VM)
+	}
+
+	resp := apiGuest.DevIncusToken{
+		Token:       token,
+		ExpiresAt:   expiresAt,
+		Addresses:   addresses,
+		Certificate: string(s.Endpoints.NetworkCert().PublicKey()),
+	}
+
+	return response.DevIncusResponse(http.StatusOK, resp, "json", c.Type() == instancetype.VM)
+}}
+
+var devIncusSnapshotsPost = devIncusHandler{"/1.0/snapshots", func(d *Daemon, c instance.Instance, w http.ResponseWriter, r *http.Request) response.Response {
+	if util.IsFalse(c.ExpandedConfig()["security.guestapi"]) {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusForbidden, "not authorized"), c.Type() == instancetype.VM)
+	}
+
+	if util.IsFalseOrEmpty(c.ExpandedConfig()["security.guestapi.snapshots"]) {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusForbidden, "not authorized"), c.Type() == instancetype.VM)
+	}
+
+	if r.Method != "POST" {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusMethodNotAllowed, "%s", fmt.Sprintf("method %q not allowed", r.Method)), c.Type() == instancetype.VM)
+	}
+
+	req := apiGuest.DevIncusSnapshotsPost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, "%s", err.Error()), c.Type() == instancetype.VM)
+	}
+
+	dev, ok := c.ExpandedDevices()[req.Device]
+	if !ok || dev["type"] != "disk" || dev["pool"] == "" {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, "Device %q isn't a storage volume of the instance", req.Device), c.Type() == instancetype.VM)
+	}
+
+	// The instance flushed its data before the request and waits for the response, so the snapshot is
+	// taken synchronously.
+	name, err := devIncusSnapshot(r.Context(), d.State(), c, dev, req)
+	if err != nil {
+		return response.DevIncusErrorResponse(err, c.Type() == instancetype.VM)
+	}
+
+	logger.Info("Snapshot requested by instance", logger.Ctx{"instance": c.Name(), "project": c.Project().Name, "device": req.Device, "snapshot": name})
+
+	return response.DevIncusResponse(http.StatusOK, apiGuest.DevIncusSnapshot{Device: req.Device, Name: name}, "json", c.Type() == instancetype.VM)
+}}
+
+// devIncusSnapshot snapshots the volume of the disk device of the instance and returns the name of the snapshot
+// once taken. The root disk is snapshotted with the instance, other disks with their custom volume.
+func devIncusSnapshot(ctx context.Context, s *state.State, inst instance.Instance, dev map[string]string, req apiGuest.DevIncusSnapshotsPost) (string, error) {
+	rootDisk := internalInstance.IsRootDiskDevice(dev)
+
+	projectName := inst.Project().Name
+	if !rootDisk {
+		var err error
+
+		projectName, err = project.StorageVolumeProject(s.DB.Cluster, inst.Project().Name, db.StoragePoolVolumeTypeCustom)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		p, err := dbProject.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		return project.AllowSnapshotCreation(p)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var opType operationtype.Type
+	var run func(op *operations.Operation) error
+	resources := map[string][]api.URL{}
+	expiry := time.Time{}
+
+	if rootDisk {
+		if req.Name == "" {
+			req.Name, err = instance.NextSnapshotName(s, inst, "snap%d")
+			if err != nil {
+				return "", err
+			}
+		}
+
+		err = validate.IsURLSegmentSafe(req.Name)
+		if err != nil {
+			return "", api.StatusErrorf(http.StatusBadRequest, "Invalid snapshot name: %v", err)
+		}
+
+		if req.ExpiresAt != nil {
+			expiry = *req.ExpiresAt
+		} else {
+			expiry, err = internalInstance.GetExpiry(time.Now(), inst.ExpandedConfig()["snapshots.expiry"])
+			if err != nil {
+				return "", err
+			}
+		}
+
+		opType = operationtype.SnapshotCreate
+		run = func(op *operations.Operation) error {
+			inst.SetOperation(op)
+			return inst.Snapshot(req.Name, expiry, false)
+		}
+
+		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+		resources["instances_snapshots"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name(), "snapshots", req.Name)}
+	} else {
+		pool, err := storagePools.LoadByName(s, dev["pool"])
+		if err != nil {
+			return "", err
+		}
+
+		volumeName := dev["source"]
+
+		var dbVolume *db.StorageVolume
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			dbVolume, err = tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, db.StoragePoolVolumeTypeCustom, volumeName, true)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if req.Name == "" {
+			req.Name, err = volumeDetermineNextSnapshotName(ctx, s, db.StorageVolumeArgs{Name: volumeName, PoolName: pool.Name(), Config: dbVolume.Config}, "snap%d")
+			if err != nil {
+				return "", err
+			}
+		}
+
+		err = pool.ValidateName(req.Name)
+		if err != nil {
+			return "", api.StatusErrorf(http.StatusBadRequest, "Invalid snapshot name: %v", err)
+		}
+
+		if req.ExpiresAt != nil {
+			expiry = *req.ExpiresAt
+		} else {
+			expiry, err = internalInstance.GetExpiry(time.Now(), dbVolume.Config["snapshots.expiry"])
+			if err != nil {
+				return "", err
+			}
+		}
+
+		opType = operationtype.VolumeSnapshotCreate
+		run = func(op *operations.Operation) error {
+			return pool.CreateCustomVolumeSnapshot(projectName, volumeName, req.Name, expiry, op)
+		}
+
+		resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", pool.Name(), "volumes", "custom", volumeName)}
+		resources["storage_volume_snapshots"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", pool.Name(), "volumes", "custom", volumeName, "snapshots", req.Name)}
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, opType, resources, nil, run, nil, nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	err = op.Start()
+	if err != nil {
+		return "", err
+	}
+
+	err = op.Wait(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return req.Name, nil
+}
+
 var handlers = []devIncusHandler{
 	{"/", func(d *Daemon, c instance.Instance, w http.ResponseWriter, r *http.Request) response.Response {
 		return response.DevIncusResponse(http.StatusOK, []string{"/1.0"}, "json", c.Type() == instancetype.VM)
@@ -271,6 +495,8 @@ var handlers = []devIncusHandler{
 	devIncusEventsGet,
 	devIncusImageExport,
 	devIncusDevicesGet,
+	devIncusTokenPost,
+	devIncusSnapshotsPost,
 }
 
 func hoistReq(f func(*Daemon, instance.Instance, http.ResponseWriter, *http.Request) response.Response, d *Daemon) func(http.ResponseWriter, *http.Request) {
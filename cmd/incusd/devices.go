@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -9,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unsafe"
 
 	"golang.org/x/sys/unix"
@@ -20,6 +23,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/resources"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/util"
 )
@@ -431,6 +435,20 @@ func deviceTaskBalance(s *state.State) {
 		return
 	}
 
+	// Keep the CPU threads reserved for the host free of load-balanced containers, leaving at least one.
+	balancedCpus := cpus
+	reservedCpus := int(min(s.GlobalConfig.InstancesReservedCPU(), int64(len(cpus)-1)))
+	if reservedCpus > 0 {
+		balancedCpus = cpus[reservedCpus:]
+
+		balancedCpusSlice := make([]string, 0, len(balancedCpus))
+		for _, id := range balancedCpus {
+			balancedCpusSlice = append(balancedCpusSlice, fmt.Sprintf("%d", id))
+		}
+
+		effectiveCpus = strings.Join(balancedCpusSlice, ",")
+	}
+
 	// Iterate through the instances
 	instances, err := instance.LoadNodeAll(s, instancetype.Container)
 	if err != nil {
@@ -450,8 +468,8 @@ func deviceTaskBalance(s *state.State) {
 	for _, cpu := range cpusTopology.Sockets {
 		for _, core := range cpu.Cores {
 			for _, thread := range core.Threads {
-				// Skip any isolated CPU thread.
-				if slices.Contains(isolatedCpusInt, thread.ID) {
+				// Skip any isolated or reserved CPU thread.
+				if slices.Contains(isolatedCpusInt, thread.ID) || !slices.Contains(balancedCpus, thread.ID) {
 					continue
 				}
 
@@ -510,7 +528,7 @@ func deviceTaskBalance(s *state.State) {
 		count, err := strconv.Atoi(cpulimit)
 		if err == nil {
 			// Load-balance
-			count = minFunc(count, len(cpus))
+			count = minFunc(count, len(balancedCpus))
 			if len(numaCpus) > 0 {
 				fillFixedInstances(fixedInstances, c, cpus, numaCpus, count, true)
 			} else {
@@ -565,7 +583,11 @@ func deviceTaskBalance(s *state.State) {
 	}
 
 	sortedUsage := make(deviceTaskCPUs, 0)
-	for _, value := range usage {
+	for id, value := range usage {
+		if !slices.Contains(balancedCpus, id) {
+			continue
+		}
+
 		sortedUsage = append(sortedUsage, value)
 	}
 
@@ -610,6 +632,27 @@ func deviceTaskBalance(s *state.State) {
 	}
 }
 
+// deviceTaskReserveMemory protects the memory reserved for the host from reclaim in the cgroup of the
+// systemd slice holding the daemon, so that system services keep running when the instances use up
+// the rest of the memory.
+func deviceTaskReserveMemory(s *state.State) {
+	if !s.OS.CGInfo.Supports(cgroup.Memory, nil) {
+		return
+	}
+
+	cg, err := cgroup.NewSliceFileReadWriter(os.Getpid(), true)
+	if err != nil {
+		logger.Debug("Unable to load the host slice cgroup, skipping memory reservation", logger.Ctx{"err": err})
+		return
+	}
+
+	reserved := s.GlobalConfig.InstancesReservedMemory()
+	err = cg.SetMemoryLow(reserved)
+	if err != nil && (reserved > 0 || !errors.Is(err, cgroup.ErrControllerMissing)) {
+		logger.Warn("Failed to protect the memory reserved for the host", logger.Ctx{"reserved": reserved, "err": err})
+	}
+}
+
 // deviceEventListener starts the event listener for resource scheduling.
 // Accepts stateFunc which will be called each time it needs a fresh state.State.
 func deviceEventListener(stateFunc func() *state.State) {
@@ -719,3 +762,44 @@ func ueventParseVendorProduct(props map[string]string, subsystem string, devname
 
 	return vendor, product, true
 }
+
+// nicLimitsScheduleTask re-applies the limits of the NIC devices of the running local instances whose
+// limits.schedule switched to a different time window since the last run.
+func nicLimitsScheduleTask(d *Daemon) (task.Func, task.Schedule) {
+	since := time.Now()
+
+	f := func(ctx context.Context) {
+		now := time.Now()
+		defer func() { since = now }()
+
+		s := d.State()
+
+		instances, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			logger.Error("Failed loading instances for NIC limits schedule task", logger.Ctx{"err": err})
+			return
+		}
+
+		for _, inst := range instances {
+			if !inst.IsRunning() {
+				continue
+			}
+
+			for devName, devConfig := range inst.ExpandedDevices() {
+				if devConfig["type"] != "nic" || !device.NICLimitsScheduleChanged(devConfig, since, now) {
+					continue
+				}
+
+				err := inst.ReloadDevice(devName)
+				if err != nil {
+					logger.Warn("Failed applying scheduled NIC limits", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "device": devName, "err": err})
+					continue
+				}
+
+				logger.Debug("Applied scheduled NIC limits", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "device": devName})
+			}
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -21,7 +22,7 @@ import (
 )
 
 var (
-	eventTypes           = []string{api.EventTypeLogging, api.EventTypeOperation, api.EventTypeLifecycle, api.EventTypeNetworkACL}
+	eventTypes           = []string{api.EventTypeLogging, api.EventTypeOperation, api.EventTypeLifecycle, api.EventTypeNetworkACL, api.EventTypeStorage}
 	privilegedEventTypes = []string{api.EventTypeLogging}
 )
 
@@ -106,6 +107,20 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 		return api.StatusErrorf(http.StatusForbidden, "Forbidden")
 	}
 
+	var since time.Time
+	sinceStr := request.QueryParam(r, "since")
+	if sinceStr != "" {
+		if isClusterNotification(r) {
+			return api.StatusErrorf(http.StatusBadRequest, "Recorded events can't be replayed to cluster members")
+		}
+
+		var err error
+		since, err = time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid since time %q: %w", sinceStr, err)
+		}
+	}
+
 	l := logger.AddContext(logger.Ctx{"remote": r.RemoteAddr})
 
 	var excludeLocations []string
@@ -159,12 +174,55 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 	defer func() { _ = conn.Close() }() // Ensure listener below ends when this function ends.
 
 	listenerConnection := events.NewWebsocketListenerConnection(conn)
+
+	// When replaying recorded events, the live events are held until the recorded events which happened
+	// before the listener was added have been sent.
+	var replayConnection *events.ReplayListenerConnection
+	if !since.IsZero() {
+		replayConnection = events.NewReplayListenerConnection(listenerConnection)
+		listenerConnection = replayConnection
+	}
+
+	listenerAdded := time.Now()
 	listener, err := s.Events.AddListener(projectName, allProjects, projectPermissionFunc, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
 	if err != nil {
 		l.Warn("Failed to add event listener", logger.Ctx{"err": err})
 		return nil
 	}
 
+	if replayConnection != nil {
+		var recordedEvents []api.Event
+
+		err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+			recordedEvents, err = tx.GetEvents(ctx, since, listenerAdded, types)
+			return err
+		})
+		if err != nil {
+			l.Warn("Failed getting recorded events", logger.Ctx{"err": err})
+		}
+
+		// Only replay the events the listener would have received live.
+		recordedEvents = slices.DeleteFunc(recordedEvents, func(event api.Event) bool {
+			if event.Project == "" {
+				return false
+			}
+
+			if !allProjects {
+				return event.Project != projectName
+			}
+
+			return projectPermissionFunc != nil && !projectPermissionFunc(auth.ObjectProject(event.Project))
+		})
+
+		err = replayConnection.Replay(recordedEvents)
+		if err != nil {
+			l.Warn("Failed replaying recorded events", logger.Ctx{"err": err})
+			listener.Close()
+			return nil
+		}
+	}
+
 	listener.Wait(r.Context())
 
 	return nil
@@ -187,13 +245,18 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 //	    example: default
 //	  - in: query
 //	    name: type
-//	    description: Event type(s), comma separated (valid types are logging, operation or lifecycle)
+//	    description: Event type(s), comma separated (valid types are logging, operation, lifecycle or storage)
 //	    type: string
 //	    example: logging,lifecycle
 //	  - in: query
 //	    name: all-projects
 //	    description: Retrieve instances from all projects
 //	    type: boolean
+//	  - in: query
+//	    name: since
+//	    description: Replay the recorded lifecycle events which happened since that time (RFC3339)
+//	    type: string
+//	    example: 2024-01-01T00:00:00Z
 //	responses:
 //	  "200":
 //	    description: Websocket message (JSON)
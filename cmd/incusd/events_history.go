@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// eventsHistoryQueueSize is the number of lifecycle events which can be waiting to be recorded.
+const eventsHistoryQueueSize = 1024
+
+// eventsHistoryBatchSize is the maximum number of lifecycle events recorded in a single transaction.
+const eventsHistoryBatchSize = 128
+
+// startEventsHistory records the lifecycle events produced by this member when the events history is enabled.
+// The events are queued by the events server and recorded in the background to not delay their delivery.
+func (d *Daemon) startEventsHistory() {
+	queue := make(chan api.Event, eventsHistoryQueueSize)
+
+	// The handler is called with the events server lock held, so it can't log itself as that would produce
+	// a logging event. The dropped events are reported by the recording loop instead.
+	var dropped atomic.Int64
+
+	d.events.SetHistoryHandler(func(event api.Event) {
+		select {
+		case queue <- event:
+		default:
+			dropped.Add(1)
+		}
+	})
+
+	go func() {
+		for {
+			var batch []api.Event
+
+			select {
+			case <-d.shutdownCtx.Done():
+				return
+			case event := <-queue:
+				batch = append(batch, event)
+			}
+
+			// Record the other queued events together.
+		fill:
+			for len(batch) < eventsHistoryBatchSize {
+				select {
+				case event := <-queue:
+					batch = append(batch, event)
+				default:
+					break fill
+				}
+			}
+
+			droppedCount := dropped.Swap(0)
+			if droppedCount > 0 {
+				logger.Warn("Dropped lifecycle events from the events history as the queue was full", logger.Ctx{"count": droppedCount})
+			}
+
+			s := d.State()
+			if s.GlobalConfig == nil || s.GlobalConfig.EventsHistoryExpiryDays() == 0 {
+				continue
+			}
+
+			err := s.DB.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.CreateEvents(ctx, batch)
+			})
+			if err != nil {
+				logger.Warn("Failed recording lifecycle events", logger.Ctx{"count": len(batch), "err": err})
+			}
+		}
+	}()
+}
+
+// pruneEventsHistoryTask removes the recorded lifecycle events which are older than the configured expiry.
+func pruneEventsHistoryTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		// When the events history is disabled, all the recorded events are removed.
+		before := time.Now()
+
+		expiryDays := s.GlobalConfig.EventsHistoryExpiryDays()
+		if expiryDays > 0 {
+			before = before.AddDate(0, 0, -int(expiryDays))
+		}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteEventsBefore(ctx, before)
+		})
+		if err != nil {
+			logger.Error("Failed pruning the events history", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Hourly()
+}
@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"slices"
 	"strconv"
@@ -85,6 +86,12 @@ var imageExportCmd = APIEndpoint{
 	Post: APIEndpointAction{Handler: imageExportPost, AccessHandler: allowPermission(auth.ObjectTypeImage, auth.EntitlementCanEdit, "fingerprint")},
 }
 
+var imageSBOMCmd = APIEndpoint{
+	Path: "images/{fingerprint}/sbom",
+
+	Get: APIEndpointAction{Handler: imageSBOMGet, AllowUntrusted: true},
+}
+
 var imageSecretCmd = APIEndpoint{
 	Path: "images/{fingerprint}/secret",
 
@@ -416,9 +423,9 @@ func imgPostInstanceInfo(ctx context.Context, s *state.State, r *http.Request, r
 	metaWriter = internalIO.NewQuotaWriter(metaWriter, budget)
 	rootfsWriter = internalIO.NewQuotaWriter(rootfsWriter, budget)
 	if imageType != "split" {
-		meta, err = c.Export(metaWriter, nil, req.Properties, req.ExpiresAt, tracker)
+		meta, err = c.Export(metaWriter, nil, req.Properties, req.ExpiresAt, req.Provenance, req.SBOM, tracker)
 	} else {
-		meta, err = c.Export(metaWriter, rootfsWriter, req.Properties, req.ExpiresAt, tracker)
+		meta, err = c.Export(metaWriter, rootfsWriter, req.Properties, req.ExpiresAt, req.Provenance, req.SBOM, tracker)
 	}
 
 	// Clean up file handles.
@@ -511,15 +518,42 @@ func imgPostInstanceInfo(ctx context.Context, s *state.State, r *http.Request, r
 
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Create the database entry
-		return tx.CreateImage(ctx, c.Project().Name, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		err := tx.CreateImage(ctx, c.Project().Name, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		if err != nil {
+			return err
+		}
+
+		return imageCreateProvenance(ctx, tx, c.Project().Name, info.Fingerprint, meta)
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	info.Provenance = meta.Provenance
+	info.SBOM = meta.SBOM != ""
+
 	return &info, nil
 }
 
+// imageCreateProvenance records the build provenance and the SBOM document from the metadata of a new image.
+func imageCreateProvenance(ctx context.Context, tx *db.ClusterTx, projectName string, fingerprint string, meta *api.ImageMetadata) error {
+	if meta == nil || (meta.Provenance == nil && meta.SBOM == "") {
+		return nil
+	}
+
+	id, _, err := tx.GetImage(ctx, fingerprint, dbCluster.ImageFilter{Project: &projectName})
+	if err != nil {
+		return err
+	}
+
+	provenance := api.ImageProvenance{}
+	if meta.Provenance != nil {
+		provenance = *meta.Provenance
+	}
+
+	return tx.CreateImageProvenance(ctx, id, provenance, meta.SBOM)
+}
+
 func imgPostRemoteInfo(ctx context.Context, s *state.State, r *http.Request, req api.ImagesPost, op *operations.Operation, project string, budget int64) (*api.Image, error) {
 	var err error
 	var hash string
@@ -955,11 +989,19 @@ func getImgPostInfo(ctx context.Context, s *state.State, r *http.Request, buildd
 
 		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			// Create the database entry
-			return tx.CreateImage(ctx, project, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, profileIds)
+			err := tx.CreateImage(ctx, project, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, profileIds)
+			if err != nil {
+				return err
+			}
+
+			return imageCreateProvenance(ctx, tx, project, info.Fingerprint, imageMeta)
 		})
 		if err != nil {
 			return nil, err
 		}
+
+		info.Provenance = imageMeta.Provenance
+		info.SBOM = imageMeta.SBOM != ""
 	}
 
 	return &info, nil
@@ -1216,6 +1258,19 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		return response.InternalError(errors.New("Invalid images JSON"))
 	}
 
+	// Provenance and SBOM documents can only be attached when publishing an instance.
+	if !imageUpload && (req.Provenance != nil || req.SBOM != "") {
+		if !slices.Contains([]string{"container", "instance", "virtual-machine", "snapshot"}, req.Source.Type) {
+			cleanup(builddir, post)
+			return response.BadRequest(errors.New("Provenance and SBOM can only be attached to images created from instances"))
+		}
+
+		if req.SBOM != "" && !json.Valid([]byte(req.SBOM)) {
+			cleanup(builddir, post)
+			return response.BadRequest(errors.New("SBOM document must be valid JSON"))
+		}
+	}
+
 	/* Forward requests for containers on other nodes */
 	if !imageUpload && slices.Contains([]string{"container", "instance", "virtual-machine", "snapshot"}, req.Source.Type) {
 		name := req.Source.Name
@@ -1305,19 +1360,31 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		}
 
 		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			imgID, _, err := tx.GetImageByFingerprintPrefix(ctx, info.Fingerprint, dbCluster.ImageFilter{Project: &projectName})
+			imgID, img, err := tx.GetImageByFingerprintPrefix(ctx, info.Fingerprint, dbCluster.ImageFilter{Project: &projectName})
 			if err != nil {
 				return fmt.Errorf("Fetch image %q: %w", info.Fingerprint, err)
 			}
 
 			for _, alias := range req.Aliases {
-				_, _, err := tx.GetImageAlias(ctx, projectName, alias.Name, true)
+				aliasID, existing, err := tx.GetImageAlias(ctx, projectName, alias.Name, true)
 				if !response.IsNotFoundError(err) {
 					if err != nil {
 						return fmt.Errorf("Fetch image alias %q: %w", alias.Name, err)
 					}
 
-					return fmt.Errorf("Alias already exists: %s", alias.Name)
+					if !alias.Variant {
+						return fmt.Errorf("Alias already exists: %s", alias.Name)
+					}
+
+					// Add the image as the variant of the existing alias for its architecture.
+					err = imageAliasAddVariant(ctx, tx, projectName, aliasID, existing, imgID, img)
+					if err != nil {
+						return fmt.Errorf("Add image to alias %q: %w", alias.Name, err)
+					}
+
+					s.Events.SendLifecycle(projectName, lifecycle.ImageAliasUpdated.Event(alias.Name, projectName, op.Requestor(), logger.Ctx{"target": existing.Target, "architecture": img.Architecture, "variant": info.Fingerprint}))
+
+					continue
 				}
 
 				err = tx.CreateImageAlias(ctx, projectName, alias.Name, imgID, alias.Description)
@@ -1822,6 +1889,14 @@ func imagesGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Add the images of the upstream image servers.
+	if !allProjects {
+		result, err = imageCacheAddImages(r.Context(), s, result, clauses)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	return response.SyncResponse(true, result)
 }
 
@@ -3161,6 +3236,14 @@ func imageGet(d *Daemon, r *http.Request) response.Response {
 		return nil
 	})
 	if err != nil {
+		// Look for the image in the upstream image servers.
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			upstreamInfo, _, cacheErr := imageCacheImage(r.Context(), s, fingerprint)
+			if cacheErr == nil {
+				return response.SyncResponse(true, upstreamInfo)
+			}
+		}
+
 		return response.SmartError(err)
 	}
 
@@ -3188,6 +3271,100 @@ func imageGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponseETag(true, info, etag)
 }
 
+// swagger:operation GET /1.0/images/{fingerprint}/sbom images image_sbom_get
+//
+//	Get the image SBOM
+//
+//	Gets the SBOM document attached to a specific image.
+//	Public images can be queried by untrusted clients.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: SBOM document
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: object
+//	          description: SBOM document
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func imageSBOMGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	fingerprint, err := url.PathUnescape(mux.Vars(r)["fingerprint"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the image (expand partial fingerprints).
+	var info *api.Image
+	var sbom string
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var id int
+
+		id, info, err = tx.GetImageByFingerprintPrefix(ctx, fingerprint, dbCluster.ImageFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		sbom, err = tx.GetImageSBOM(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Only public images are available to users who can't view the image.
+	if !info.Public {
+		if d.checkTrustedClient(r) != nil {
+			return response.NotFound(fmt.Errorf("Image %q not found", info.Fingerprint))
+		}
+
+		err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectImage(projectName, info.Fingerprint), auth.EntitlementCanView)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusForbidden) {
+				return response.NotFound(fmt.Errorf("Image %q not found", info.Fingerprint))
+			}
+
+			return response.SmartError(err)
+		}
+	}
+
+	return response.SyncResponse(true, json.RawMessage(sbom))
+}
+
 // swagger:operation PUT /1.0/images/{fingerprint} images image_put
 //
 //	Update the image
@@ -3418,6 +3595,68 @@ func imagePatch(d *Daemon, r *http.Request) response.Response {
 	return response.EmptySyncResponse
 }
 
+// imageAliasVariants validates the architecture variants of an alias targeting the given image.
+// It returns them as a map of architecture IDs to image IDs, leaving out the architecture of the target.
+func imageAliasVariants(ctx context.Context, tx *db.ClusterTx, projectName string, target *api.Image, architectures map[string]string) (map[int]int, error) {
+	variants := make(map[int]int, len(architectures))
+	for architectureName, fingerprint := range architectures {
+		architecture, err := osarch.ArchitectureID(architectureName)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid architecture %q", architectureName)
+		}
+
+		imageID, img, err := tx.GetImageByFingerprintPrefix(ctx, fingerprint, dbCluster.ImageFilter{Project: &projectName})
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading image %q for architecture %q: %w", fingerprint, architectureName, err)
+		}
+
+		if img.Architecture != architectureName {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Image %q is for architecture %q rather than %q", img.Fingerprint, img.Architecture, architectureName)
+		}
+
+		if img.Type != target.Type {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Image %q is of type %q while the alias target is of type %q", img.Fingerprint, img.Type, target.Type)
+		}
+
+		// The target of the alias is used for its own architecture.
+		if architectureName == target.Architecture {
+			if img.Fingerprint != target.Fingerprint {
+				return nil, api.StatusErrorf(http.StatusBadRequest, "Architecture %q must use the alias target", architectureName)
+			}
+
+			continue
+		}
+
+		variants[architecture] = imageID
+	}
+
+	return variants, nil
+}
+
+// imageAliasAddVariant sets the image as the target of an existing alias for its architecture.
+// The alias target is replaced if it's for the same architecture, otherwise the image becomes a variant.
+func imageAliasAddVariant(ctx context.Context, tx *db.ClusterTx, projectName string, aliasID int, alias api.ImageAliasesEntry, imgID int, img *api.Image) error {
+	_, target, err := tx.GetImageByFingerprintPrefix(ctx, alias.Target, dbCluster.ImageFilter{Project: &projectName})
+	if err != nil {
+		return err
+	}
+
+	if img.Type != target.Type {
+		return api.StatusErrorf(http.StatusBadRequest, "Image is of type %q while the alias target is of type %q", img.Type, target.Type)
+	}
+
+	if img.Architecture == target.Architecture {
+		return tx.UpdateImageAlias(ctx, aliasID, imgID, alias.Description)
+	}
+
+	architecture, err := osarch.ArchitectureID(img.Architecture)
+	if err != nil {
+		return err
+	}
+
+	return tx.CreateImageAliasArchitecture(ctx, aliasID, architecture, imgID)
+}
+
 // swagger:operation POST /1.0/images/aliases images images_aliases_post
 //
 //	Add an image alias
@@ -3475,7 +3714,12 @@ func imageAliasesPost(d *Daemon, r *http.Request) response.Response {
 			return api.StatusErrorf(http.StatusConflict, "Alias %q already exists", req.Name)
 		}
 
-		imgID, _, err := tx.GetImageByFingerprintPrefix(ctx, req.Target, dbCluster.ImageFilter{Project: &projectName})
+		imgID, img, err := tx.GetImageByFingerprintPrefix(ctx, req.Target, dbCluster.ImageFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		variants, err := imageAliasVariants(ctx, tx, projectName, img, req.Architectures)
 		if err != nil {
 			return err
 		}
@@ -3485,6 +3729,18 @@ func imageAliasesPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		if len(variants) > 0 {
+			aliasID, _, err := tx.GetImageAlias(ctx, projectName, req.Name, true)
+			if err != nil {
+				return err
+			}
+
+			err = tx.UpdateImageAliasArchitectures(ctx, aliasID, variants)
+			if err != nil {
+				return err
+			}
+		}
+
 		return err
 	})
 	if err != nil {
@@ -3559,6 +3815,9 @@ func imageAliasesPost(d *Daemon, r *http.Request) response.Response {
 //
 //	Returns a list of image aliases (structs).
 //
+//	The aliases can be restricted to those with the given names by repeating the "name" parameter,
+//	in which case the aliases of the upstream image servers aren't included.
+//
 //	---
 //	produces:
 //	  - application/json
@@ -3568,6 +3827,11 @@ func imageAliasesPost(d *Daemon, r *http.Request) response.Response {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: name
+//	    description: Name of an alias to look up
+//	    type: string
+//	    example: ubuntu/24.04
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -3606,6 +3870,9 @@ func imageAliasesGet(d *Daemon, r *http.Request) response.Response {
 		return response.InternalError(fmt.Errorf("Failed to get a permission checker: %w", err))
 	}
 
+	// Optionally restrict the aliases to the requested names.
+	lookupNames := r.URL.Query()["name"]
+
 	var responseStr []string
 	var responseMap []api.ImageAliasesEntry
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -3614,6 +3881,12 @@ func imageAliasesGet(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		if len(lookupNames) > 0 {
+			names = slices.DeleteFunc(names, func(name string) bool {
+				return !slices.Contains(lookupNames, name)
+			})
+		}
+
 		if recursion {
 			responseMap = make([]api.ImageAliasesEntry, 0, len(names))
 		} else {
@@ -3643,10 +3916,36 @@ func imageAliasesGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Aliases looked up by name are only those of the project.
+	if len(lookupNames) > 0 {
+		if !recursion {
+			return response.SyncResponse(true, responseStr)
+		}
+
+		return response.SyncResponse(true, responseMap)
+	}
+
+	// Add the aliases of the upstream image servers.
 	if !recursion {
+		exclude := make([]string, 0, len(responseStr))
+		for _, aliasURL := range responseStr {
+			exclude = append(exclude, path.Base(aliasURL))
+		}
+
+		for _, alias := range imageCacheAliases(r.Context(), s, exclude) {
+			responseStr = append(responseStr, api.NewURL().Path(version.APIVersion, "images", "aliases", alias.Name).String())
+		}
+
 		return response.SyncResponse(true, responseStr)
 	}
 
+	exclude := make([]string, 0, len(responseMap))
+	for _, alias := range responseMap {
+		exclude = append(exclude, alias.Name)
+	}
+
+	responseMap = append(responseMap, imageCacheAliases(r.Context(), s, exclude)...)
+
 	return response.SyncResponse(true, responseMap)
 }
 
@@ -3758,6 +4057,14 @@ func imageAliasGet(d *Daemon, r *http.Request) response.Response {
 		return err
 	})
 	if err != nil {
+		// Look for the alias in the upstream image servers.
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			upstreamAlias, _, cacheErr := imageCacheAlias(r.Context(), s, name, r.FormValue("type"))
+			if cacheErr == nil {
+				return response.SyncResponse(true, upstreamAlias)
+			}
+		}
+
 		return response.SmartError(err)
 	}
 
@@ -3895,7 +4202,12 @@ func imageAliasPut(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
-		imageID, _, err := tx.GetImageByFingerprintPrefix(ctx, req.Target, dbCluster.ImageFilter{Project: &projectName})
+		imageID, img, err := tx.GetImageByFingerprintPrefix(ctx, req.Target, dbCluster.ImageFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		variants, err := imageAliasVariants(ctx, tx, projectName, img, req.Architectures)
 		if err != nil {
 			return err
 		}
@@ -3905,6 +4217,11 @@ func imageAliasPut(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = tx.UpdateImageAliasArchitectures(ctx, imgAliasID, variants)
+		if err != nil {
+			return err
+		}
+
 		return err
 	})
 	if err != nil {
@@ -4001,7 +4318,33 @@ func imageAliasPatch(d *Daemon, r *http.Request) response.Response {
 			imgAlias.Description = description
 		}
 
-		imageID, _, err := tx.GetImage(ctx, imgAlias.Target, dbCluster.ImageFilter{Project: &projectName})
+		imageID, img, err := tx.GetImage(ctx, imgAlias.Target, dbCluster.ImageFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		_, ok = req["architectures"]
+		if ok {
+			architectures, err := req.GetMap("architectures")
+			if err != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+			}
+
+			imgAlias.Architectures = make(map[string]string, len(architectures))
+			for architecture := range architectures {
+				fingerprint, err := architectures.GetString(architecture)
+				if err != nil {
+					return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+				}
+
+				imgAlias.Architectures[architecture] = fingerprint
+			}
+		} else {
+			// Keep the existing variants, the new target replacing the one of its architecture.
+			delete(imgAlias.Architectures, img.Architecture)
+		}
+
+		variants, err := imageAliasVariants(ctx, tx, projectName, img, imgAlias.Architectures)
 		if err != nil {
 			return err
 		}
@@ -4011,6 +4354,11 @@ func imageAliasPatch(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = tx.UpdateImageAliasArchitectures(ctx, imgAliasID, variants)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -4170,12 +4518,25 @@ func imageExport(d *Daemon, r *http.Request) response.Response {
 
 	var imgInfo *api.Image
 
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	getImage := func(ctx context.Context, tx *db.ClusterTx) error {
 		// Get the image (expand the fingerprint).
 		_, imgInfo, err = tx.GetImage(ctx, fingerprint, dbCluster.ImageFilter{Project: &projectName})
 
 		return err
-	})
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), getImage)
+	if err != nil && api.StatusErrorCheck(err, http.StatusNotFound) && r.RemoteAddr != "@dev_incus" {
+		// Download the image from the upstream image servers and serve it from the local store.
+		upstreamInfo, server, cacheErr := imageCacheImage(r.Context(), s, fingerprint)
+		if cacheErr == nil {
+			err = imageCachePull(r.Context(), r, s, projectName, upstreamInfo, server)
+			if err == nil {
+				err = s.DB.Cluster.Transaction(r.Context(), getImage)
+			}
+		}
+	}
+
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/filter"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// imageCacheUpstream holds the last known catalog of an upstream image server.
+type imageCacheUpstream struct {
+	images  []api.Image
+	aliases []api.ImageAliasesEntry
+	updated time.Time
+}
+
+// imageCacheCatalog holds the catalogs of the upstream image servers the server acts as a caching proxy for.
+type imageCacheCatalog struct {
+	mu        sync.RWMutex
+	servers   []string
+	upstreams map[string]*imageCacheUpstream
+	loaded    bool
+
+	// refreshMu prevents concurrent refreshes of the catalogs.
+	refreshMu sync.Mutex
+}
+
+var imageCache imageCacheCatalog
+
+// imageCacheConnect connects to an upstream image server.
+func imageCacheConnect(s *state.State, server string) (incus.ImageServer, error) {
+	args := &incus.ConnectionArgs{
+		UserAgent:   version.UserAgent,
+		Proxy:       s.Proxy,
+		CachePath:   s.OS.CacheDir,
+		CacheExpiry: time.Hour,
+	}
+
+	if s.GlobalConfig.ImagesCacheProtocol() == "incus" {
+		return incus.ConnectPublicIncus(server, args)
+	}
+
+	return incus.ConnectSimpleStreams(server, args)
+}
+
+// imageCacheRefresh fetches the catalogs of the upstream image servers.
+// The last known catalog of a server which can't be reached is kept, until it becomes too stale.
+func imageCacheRefresh(ctx context.Context, s *state.State) error {
+	imageCache.refreshMu.Lock()
+	defer imageCache.refreshMu.Unlock()
+
+	servers := s.GlobalConfig.ImagesCacheUpstreams()
+
+	imageCache.mu.RLock()
+	upstreams := make(map[string]*imageCacheUpstream, len(servers))
+	for _, server := range servers {
+		upstream, ok := imageCache.upstreams[server]
+		if ok {
+			upstreams[server] = upstream
+		}
+	}
+
+	imageCache.mu.RUnlock()
+
+	errs := []error{}
+	for _, server := range servers {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		remote, err := imageCacheConnect(s, server)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed connecting to %q: %w", server, err))
+			continue
+		}
+
+		images, err := remote.GetImages()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed getting images from %q: %w", server, err))
+			continue
+		}
+
+		aliases, err := remote.GetImageAliases()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed getting image aliases from %q: %w", server, err))
+			continue
+		}
+
+		upstreams[server] = &imageCacheUpstream{
+			images:  images,
+			aliases: aliases,
+			updated: time.Now(),
+		}
+	}
+
+	imageCache.mu.Lock()
+	imageCache.servers = servers
+	imageCache.upstreams = upstreams
+	imageCache.loaded = true
+	imageCache.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// imageCacheReset drops the upstream catalogs, so they're fetched again on next use.
+func imageCacheReset() {
+	imageCache.mu.Lock()
+	defer imageCache.mu.Unlock()
+
+	imageCache.servers = nil
+	imageCache.upstreams = nil
+	imageCache.loaded = false
+}
+
+// imageCacheRefreshTask returns a task refreshing the upstream catalogs at the configured interval.
+func imageCacheRefreshTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := imageCacheRefresh(ctx, d.State())
+		if err != nil {
+			logger.Warn("Failed refreshing the upstream image catalogs", logger.Ctx{"err": err})
+		}
+	}
+
+	schedule := func() (time.Duration, error) {
+		s := d.State()
+
+		// The catalogs are loaded on first use when not refreshed periodically.
+		if len(s.GlobalConfig.ImagesCacheUpstreams()) == 0 {
+			return 0, nil
+		}
+
+		return time.Duration(s.GlobalConfig.ImagesCacheRefreshIntervalHours()) * time.Hour, nil
+	}
+
+	return f, schedule
+}
+
+// imageCacheLoad loads the upstream catalogs if they haven't been fetched yet.
+func imageCacheLoad(ctx context.Context, s *state.State) {
+	if len(s.GlobalConfig.ImagesCacheUpstreams()) == 0 {
+		return
+	}
+
+	imageCache.mu.RLock()
+	loaded := imageCache.loaded
+	imageCache.mu.RUnlock()
+
+	if loaded {
+		return
+	}
+
+	err := imageCacheRefresh(ctx, s)
+	if err != nil {
+		logger.Warn("Failed loading the upstream image catalogs", logger.Ctx{"err": err})
+	}
+}
+
+// imageCacheUpstreams returns the catalogs which can currently be served, in order of preference.
+func imageCacheUpstreams(ctx context.Context, s *state.State) ([]string, map[string]*imageCacheUpstream) {
+	if len(s.GlobalConfig.ImagesCacheUpstreams()) == 0 {
+		return nil, nil
+	}
+
+	imageCacheLoad(ctx, s)
+
+	maxStale := time.Duration(s.GlobalConfig.ImagesCacheMaxStaleHours()) * time.Hour
+
+	imageCache.mu.RLock()
+	defer imageCache.mu.RUnlock()
+
+	servers := make([]string, 0, len(imageCache.servers))
+	upstreams := make(map[string]*imageCacheUpstream, len(imageCache.servers))
+	for _, server := range imageCache.servers {
+		upstream, ok := imageCache.upstreams[server]
+		if !ok || (maxStale > 0 && time.Since(upstream.updated) > maxStale) {
+			continue
+		}
+
+		servers = append(servers, server)
+		upstreams[server] = upstream
+	}
+
+	return servers, upstreams
+}
+
+// imageCacheImages returns the images of the upstream catalogs, skipping the excluded fingerprints.
+func imageCacheImages(ctx context.Context, s *state.State, exclude []string) []api.Image {
+	servers, upstreams := imageCacheUpstreams(ctx, s)
+
+	seen := make(map[string]bool, len(exclude))
+	for _, fingerprint := range exclude {
+		seen[fingerprint] = true
+	}
+
+	images := []api.Image{}
+	for _, server := range servers {
+		for _, image := range upstreams[server].images {
+			if seen[image.Fingerprint] {
+				continue
+			}
+
+			seen[image.Fingerprint] = true
+			images = append(images, image)
+		}
+	}
+
+	return images
+}
+
+// imageCacheImage returns the upstream image matching the (partial) fingerprint and the server providing it.
+func imageCacheImage(ctx context.Context, s *state.State, fingerprint string) (*api.Image, string, error) {
+	servers, upstreams := imageCacheUpstreams(ctx, s)
+
+	var match *api.Image
+	var matchServer string
+	for _, server := range servers {
+		for _, image := range upstreams[server].images {
+			if !strings.HasPrefix(image.Fingerprint, fingerprint) || (match != nil && match.Fingerprint == image.Fingerprint) {
+				continue
+			}
+
+			if match != nil {
+				return nil, "", api.StatusErrorf(http.StatusBadRequest, "More than one image matches %q", fingerprint)
+			}
+
+			match = &image
+			matchServer = server
+		}
+	}
+
+	if match == nil {
+		return nil, "", api.StatusErrorf(http.StatusNotFound, "Image %q not found", fingerprint)
+	}
+
+	return match, matchServer, nil
+}
+
+// imageCacheAliases returns the aliases of the upstream catalogs, skipping the excluded names.
+func imageCacheAliases(ctx context.Context, s *state.State, exclude []string) []api.ImageAliasesEntry {
+	servers, upstreams := imageCacheUpstreams(ctx, s)
+
+	seen := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		seen[name] = true
+	}
+
+	aliases := []api.ImageAliasesEntry{}
+	for _, server := range servers {
+		for _, alias := range upstreams[server].aliases {
+			if seen[alias.Name] {
+				continue
+			}
+
+			seen[alias.Name] = true
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return aliases
+}
+
+// imageCacheAlias returns the upstream alias of the given image type (containers by default) and the server providing it.
+func imageCacheAlias(ctx context.Context, s *state.State, name string, imageType string) (*api.ImageAliasesEntry, string, error) {
+	if imageType == "" {
+		imageType = "container"
+	}
+
+	servers, upstreams := imageCacheUpstreams(ctx, s)
+	for _, server := range servers {
+		for _, alias := range upstreams[server].aliases {
+			aliasType := alias.Type
+			if aliasType == "" {
+				aliasType = "container"
+			}
+
+			if alias.Name == name && aliasType == imageType {
+				return &alias, server, nil
+			}
+		}
+	}
+
+	return nil, "", api.StatusErrorf(http.StatusNotFound, "Image alias %q not found", name)
+}
+
+// imageCacheRedirect points an image source which can't be found locally to the upstream server providing it.
+func imageCacheRedirect(ctx context.Context, s *state.State, source api.InstanceSource, instanceType string) api.InstanceSource {
+	if source.Type != "image" || source.Server != "" {
+		return source
+	}
+
+	var server string
+	if source.Fingerprint != "" {
+		_, server, _ = imageCacheImage(ctx, s, source.Fingerprint)
+	} else if source.Alias != "" {
+		_, server, _ = imageCacheAlias(ctx, s, source.Alias, instanceType)
+	}
+
+	if server == "" {
+		return source
+	}
+
+	source.Server = server
+	source.Protocol = s.GlobalConfig.ImagesCacheProtocol()
+	source.Mode = "pull"
+
+	return source
+}
+
+// imageCacheAddImages adds the images of the upstream catalogs to a local image listing.
+func imageCacheAddImages(ctx context.Context, s *state.State, result any, clauses *filter.ClauseSet) (any, error) {
+	match := func(image api.Image) (bool, error) {
+		if clauses == nil || len(clauses.Clauses) == 0 {
+			return true, nil
+		}
+
+		return filter.Match(image, *clauses)
+	}
+
+	switch images := result.(type) {
+	case []*api.Image:
+		exclude := make([]string, 0, len(images))
+		for _, image := range images {
+			exclude = append(exclude, image.Fingerprint)
+		}
+
+		for _, image := range imageCacheImages(ctx, s, exclude) {
+			ok, err := match(image)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				images = append(images, &image)
+			}
+		}
+
+		return images, nil
+
+	case []string:
+		exclude := make([]string, 0, len(images))
+		for _, imageURL := range images {
+			exclude = append(exclude, path.Base(imageURL))
+		}
+
+		for _, image := range imageCacheImages(ctx, s, exclude) {
+			ok, err := match(image)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				images = append(images, api.NewURL().Path(version.APIVersion, "images", image.Fingerprint).String())
+			}
+		}
+
+		return images, nil
+	}
+
+	return result, nil
+}
+
+// imageCachePull downloads an image of an upstream catalog into the local image store.
+func imageCachePull(ctx context.Context, r *http.Request, s *state.State, projectName string, image *api.Image, server string) error {
+	var budget int64
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		budget, err = project.GetImageSpaceBudget(tx, projectName)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	info, created, err := ImageDownload(ctx, r, s, nil, &ImageDownloadArgs{
+		ProjectName:  projectName,
+		Server:       server,
+		Protocol:     s.GlobalConfig.ImagesCacheProtocol(),
+		Alias:        image.Fingerprint,
+		Type:         image.Type,
+		SetCached:    true,
+		PreferCached: true,
+		AutoUpdate:   s.GlobalConfig.ImagesAutoUpdateCached(),
+		Public:       true,
+		Budget:       budget,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed downloading image %q from %q: %w", image.Fingerprint, server, err)
+	}
+
+	if created {
+		err = s.Authorizer.AddImage(s.ShutdownCtx, projectName, info.Fingerprint)
+		if err != nil {
+			logger.Error("Failed to add image to authorizer", logger.Ctx{"fingerprint": info.Fingerprint, "project": projectName, "error": err})
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.ImageCreated.Event(info.Fingerprint, projectName, request.CreateRequestor(r), logger.Ctx{"type": info.Type}))
+	}
+
+	return nil
+}
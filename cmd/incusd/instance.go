@@ -302,6 +302,49 @@ func instanceRebuildFromImage(ctx context.Context, s *state.State, r *http.Reque
 	return nil
 }
 
+// imageSourceConfig returns the volatile keys tracking the image alias that an instance is built from.
+// The values are empty when the source isn't an image alias.
+func imageSourceConfig(source api.InstanceSource) map[string]string {
+	config := map[string]string{
+		"volatile.base_image.alias":    "",
+		"volatile.base_image.protocol": "",
+		"volatile.base_image.server":   "",
+	}
+
+	if source.Type != "image" || source.Alias == "" {
+		return config
+	}
+
+	config["volatile.base_image.alias"] = source.Alias
+	if source.Server != "" {
+		config["volatile.base_image.server"] = source.Server
+		config["volatile.base_image.protocol"] = source.Protocol
+	}
+
+	return config
+}
+
+// imageSourceFromConfig returns the image source of the image alias that an instance was built from.
+func imageSourceFromConfig(config map[string]string) (api.InstanceSource, error) {
+	alias := config["volatile.base_image.alias"]
+	if alias == "" {
+		return api.InstanceSource{}, errors.New("Instance wasn't built from an image alias")
+	}
+
+	source := api.InstanceSource{
+		Type:   "image",
+		Alias:  alias,
+		Server: config["volatile.base_image.server"],
+	}
+
+	if source.Server != "" {
+		source.Mode = "pull"
+		source.Protocol = config["volatile.base_image.protocol"]
+	}
+
+	return source, nil
+}
+
 func instanceRebuildFromEmpty(inst instance.Instance, op *operations.Operation) error {
 	err := inst.Rebuild(nil, op) // Rebuild as empty.
 	if err != nil {
@@ -320,6 +363,7 @@ type instanceCreateAsCopyOpts struct {
 	refreshExcludeOlder  bool              // During refresh, exclude source snapshots earlier than latest target snapshot
 	applyTemplateTrigger bool              // Apply deferred TemplateTriggerCopy.
 	allowInconsistent    bool              // Ignore some copy errors
+	instant              bool              // Create the volume as a copy-on-write clone of the source.
 }
 
 // instanceCreateAsCopy create a new instance by copying from an existing instance.
@@ -500,9 +544,16 @@ func instanceCreateAsCopy(s *state.State, opts instanceCreateAsCopyOpts, op *ope
 			return nil, fmt.Errorf("Refresh instance: %w", err)
 		}
 	} else {
-		err = pool.CreateInstanceFromCopy(inst, opts.sourceInstance, !opts.instanceOnly, opts.allowInconsistent, op)
-		if err != nil {
-			return nil, fmt.Errorf("Create instance from copy: %w", err)
+		if opts.instant {
+			err = pool.CreateInstanceFromClone(inst, opts.sourceInstance, op)
+			if err != nil {
+				return nil, fmt.Errorf("Create instance from clone: %w", err)
+			}
+		} else {
+			err = pool.CreateInstanceFromCopy(inst, opts.sourceInstance, !opts.instanceOnly, opts.allowInconsistent, op)
+			if err != nil {
+				return nil, fmt.Errorf("Create instance from copy: %w", err)
+			}
 		}
 
 		reverter.Add(func() { _ = inst.Delete(true) })
@@ -581,6 +632,46 @@ func autoCreateInstanceSnapshots(ctx context.Context, s *state.State, instances
 			l.Error("Error creating snapshot", logger.Ctx{"snapshot": snapshotName, "err": err})
 			return err
 		}
+
+		err = pruneUnretainedInstanceSnapshots(inst)
+		if err != nil {
+			l.Error("Error applying snapshot retention", logger.Ctx{"err": err})
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pruneUnretainedInstanceSnapshots deletes the snapshots of the instance not kept by its snapshots.retention policy.
+func pruneUnretainedInstanceSnapshots(inst instance.Instance) error {
+	policy, err := internalInstance.ParseSnapshotRetention(inst.ExpandedConfig()["snapshots.retention"])
+	if err != nil || len(policy) == 0 {
+		return err
+	}
+
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return err
+	}
+
+	creationDates := make([]time.Time, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		creationDates = append(creationDates, snapshot.CreationDate())
+	}
+
+	retained := internalInstance.SnapshotsRetained(policy, creationDates)
+	for i, snapshot := range snapshots {
+		if retained[i] {
+			continue
+		}
+
+		err = snapshot.Delete(true)
+		if err != nil {
+			return fmt.Errorf("Failed to delete instance snapshot %q in project %q: %w", snapshot.Name(), snapshot.Project().Name, err)
+		}
+
+		logger.Debug("Deleted instance snapshot not kept by retention policy", logger.Ctx{"project": snapshot.Project().Name, "snapshot": snapshot.Name()})
 	}
 
 	return nil
@@ -804,7 +895,7 @@ func pruneExpiredAndAutoCreateInstanceSnapshotsTask(d *Daemon) (task.Func, task.
 // getSourceImageFromInstanceSource returns the image to use for an instance source.
 func getSourceImageFromInstanceSource(ctx context.Context, s *state.State, tx *db.ClusterTx, project string, source api.InstanceSource, imageRef *string, instType string) (*api.Image, error) {
 	// Resolve the image.
-	sourceImageRefUpdate, err := instance.ResolveImage(ctx, tx, project, source)
+	sourceImageRefUpdate, err := instance.ResolveImage(ctx, tx, project, source, s.OS.Architectures)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/auth/accesslink"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// instanceAccessLinkDefaultLifetime is the lifetime of the access links created without an expiry.
+const instanceAccessLinkDefaultLifetime = time.Hour
+
+// instanceAccessLinkEntitlements are the entitlements needed to create access links with the given access.
+var instanceAccessLinkEntitlements = map[string]auth.Entitlement{
+	api.InstanceAccessLinkAccessConsole: auth.EntitlementCanAccessConsole,
+	api.InstanceAccessLinkAccessExec:    auth.EntitlementCanExec,
+}
+
+// instanceAccessLinkName returns the project and instance names of an access link request.
+func instanceAccessLinkName(r *http.Request) (string, string, error) {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return "", "", err
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return "", "", api.StatusErrorf(http.StatusBadRequest, "Invalid instance name")
+	}
+
+	return request.ProjectParam(r), name, nil
+}
+
+// swagger:operation GET /1.0/instances/{name}/access-links instances instance_access_links_get
+//
+//	Get the access links
+//
+//	Returns a list of the access links of the instance (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: recursion
+//	    description: Return the access links rather than their URLs
+//	    type: integer
+//	    example: 1
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints (or access links with recursion)
+//	          items:
+//	            type: string
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceAccessLinksGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, name, err := instanceAccessLinkName(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var links []db.InstanceAccessLink
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.GetInstanceID(ctx, projectName, name)
+		if err != nil {
+			return err
+		}
+
+		links, err = tx.GetInstanceAccessLinks(ctx, projectName, name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !localUtil.IsRecursionRequest(r) {
+		urls := make([]string, 0, len(links))
+		for _, link := range links {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "instances", name, "access-links", link.ID).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	result := make([]api.InstanceAccessLink, 0, len(links))
+	for _, link := range links {
+		result = append(result, link.InstanceAccessLink)
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation POST /1.0/instances/{name}/access-links instances instance_access_links_post
+//
+//	Create an access link
+//
+//	Creates a time-limited access link to the console and/or exec of the instance.
+//	The secret of the access link is only returned by this request.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: access link
+//	    description: Access link request
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/InstanceAccessLinksPost"
+//	responses:
+//	  "200":
+//	    description: Access link token
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceAccessLinkToken"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceAccessLinksPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, name, err := instanceAccessLinkName(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.InstanceAccessLinksPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if len(req.Access) == 0 {
+		req.Access = []string{api.InstanceAccessLinkAccessConsole}
+	}
+
+	slices.Sort(req.Access)
+	req.Access = slices.Compact(req.Access)
+
+	err = accesslink.ValidateAccess(req.Access)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Links can't give more than what their creator is allowed to do.
+	for _, access := range req.Access {
+		err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectInstance(projectName, name), instanceAccessLinkEntitlements[access])
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	now := time.Now()
+	if req.ExpiresAt.IsZero() {
+		req.ExpiresAt = now.Add(instanceAccessLinkDefaultLifetime)
+	} else if !req.ExpiresAt.After(now) {
+		return response.BadRequest(errors.New("Access link expiry must be in the future"))
+	}
+
+	// Get the address handed out with the link.
+	addresses, err := localUtil.ListenAddresses(s.LocalConfig.HTTPSAddress())
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	if len(addresses) == 0 {
+		return response.BadRequest(errors.New("Can't create access links when the server isn't listening on the network"))
+	}
+
+	fingerprint, err := localtls.CertFingerprintStr(string(s.Endpoints.NetworkPublicKey()))
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	link := db.InstanceAccessLink{
+		InstanceAccessLink: api.InstanceAccessLink{
+			ID:          uuid.New().String(),
+			Description: req.Description,
+			Access:      req.Access,
+			CreatedAt:   now,
+			ExpiresAt:   req.ExpiresAt,
+		},
+		Project:  projectName,
+		Instance: name,
+	}
+
+	requestor := request.CreateRequestor(r)
+	if requestor != nil {
+		link.CreatedBy = fmt.Sprintf("%s/%s", requestor.Protocol, requestor.Username)
+	}
+
+	token, secretHash, err := accesslink.NewToken(link.ID)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	link.SecretHash = secretHash
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateInstanceAccessLink(ctx, link)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceAccessLinkCreated.Event(projectName, name, link.ID, requestor, map[string]any{"id": link.ID, "access": link.Access, "expires_at": link.ExpiresAt}))
+
+	return response.SyncResponseLocation(true, api.InstanceAccessLinkToken{
+		ID:          link.ID,
+		URL:         "https://" + addresses[0],
+		Fingerprint: fingerprint,
+		Secret:      token,
+		ExpiresAt:   link.ExpiresAt,
+	}, api.NewURL().Path(version.APIVersion, "instances", name, "access-links", link.ID).Project(projectName).String())
+}
+
+// swagger:operation GET /1.0/instances/{name}/access-links/{id} instances instance_access_link_get
+//
+//	Get the access link
+//
+//	Gets a specific access link of the instance.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Instance access link
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceAccessLink"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceAccessLinkGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, name, err := instanceAccessLinkName(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	linkID, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var link *db.InstanceAccessLink
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		link, err = tx.GetInstanceAccessLink(ctx, linkID)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if link.Project != projectName || link.Instance != name {
+		return response.NotFound(errors.New("Instance access link not found"))
+	}
+
+	return response.SyncResponse(true, link.InstanceAccessLink)
+}
+
+// swagger:operation DELETE /1.0/instances/{name}/access-links/{id} instances instance_access_link_delete
+//
+//	Revoke the access link
+//
+//	Removes the access link, which can't be used anymore.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceAccessLinkDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, name, err := instanceAccessLinkName(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	linkID, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteInstanceAccessLink(ctx, projectName, name, linkID)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceAccessLinkDeleted.Event(projectName, name, linkID, request.CreateRequestor(r), map[string]any{"id": linkID}))
+
+	return response.EmptySyncResponse
+}
+
+// pruneExpiredInstanceAccessLinksTask removes the instance access links which have expired.
+func pruneExpiredInstanceAccessLinksTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteExpiredInstanceAccessLinks(ctx, time.Now())
+		})
+		if err != nil {
+			logger.Error("Failed pruning expired instance access links", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Hourly()
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
@@ -269,6 +270,10 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	if req.Target {
+		return instanceBackupsPostTarget(s, r, inst, req)
+	}
+
 	if req.Name == "" {
 		// come up with a name.
 		backups, err := inst.Backups()
@@ -341,6 +346,54 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 	return operations.OperationResponse(op)
 }
 
+// instanceBackupsPostTarget creates a backup of the instance in the backup target of the server.
+func instanceBackupsPostTarget(s *state.State, r *http.Request, inst instance.Instance, req api.InstanceBackupsPost) response.Response {
+	tgt, err := backupTargetLoad(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Name the backup after its creation time by default, as the points of the target aren't tracked locally.
+	if req.Name == "" {
+		req.Name = "backup-" + time.Now().UTC().Format("20060102-150405")
+	}
+
+	if strings.ContainsAny(req.Name, "/,") {
+		return response.BadRequest(errors.New("Backup names stored in the backup target may not contain slashes or commas"))
+	}
+
+	points, err := tgt.Points(r.Context(), inst.Project().Name, inst.Name())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for _, point := range points {
+		if point.Name == req.Name {
+			return response.Conflict(fmt.Errorf("Backup %q already exists in the backup target", req.Name))
+		}
+	}
+
+	backup := func(op *operations.Operation) error {
+		err := backupTargetCreate(s, tgt, req.Name, req.InstanceOnly, inst, op)
+		if err != nil {
+			return fmt.Errorf("Create backup: %w", err)
+		}
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+
+	op, err := operations.OperationCreate(s, inst.Project().Name, operations.OperationClassTask,
+		operationtype.BackupCreate, resources, nil, backup, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
 // swagger:operation GET /1.0/instances/{name}/backups/{backup} instances instance_backup_get
 //
 //	Get the backup
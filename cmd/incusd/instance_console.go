@@ -68,6 +68,9 @@ type consoleWs struct {
 
 	// channel type (either console or vga)
 	protocol string
+
+	// name of the additional serial console (empty for the main console)
+	name string
 }
 
 func (s *consoleWs) metadata() any {
@@ -80,7 +83,12 @@ func (s *consoleWs) metadata() any {
 		}
 	}
 
-	return jmap.Map{"fds": fds}
+	metadata := jmap.Map{"fds": fds}
+	if s.name != "" {
+		metadata["name"] = s.name
+	}
+
+	return metadata
 }
 
 func (s *consoleWs) connect(_ *operations.Operation, r *http.Request, w http.ResponseWriter) error {
@@ -214,7 +222,20 @@ func (s *consoleWs) doConsole() error {
 	<-s.allConnected
 
 	// Get console from instance.
-	console, consoleDisconnectCh, err := s.instance.Console(s.protocol)
+	var console *os.File
+	var consoleDisconnectCh chan error
+	var err error
+	if s.name != "" {
+		v, ok := s.instance.(instance.VM)
+		if !ok {
+			return errors.New("Failed to cast inst to VM")
+		}
+
+		console, consoleDisconnectCh, err = v.SerialConsole(s.name)
+	} else {
+		console, consoleDisconnectCh, err = s.instance.Console(s.protocol)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -505,6 +526,16 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("VGA console is only supported by virtual machines"))
 	}
 
+	if post.Name != "" {
+		if post.Type != instance.ConsoleTypeConsole || inst.Type() != instancetype.VM {
+			return response.BadRequest(errors.New("Additional serial consoles are only supported by the console type of virtual machines"))
+		}
+
+		if !slices.Contains(util.SplitNTrimSpace(inst.ExpandedConfig()["console.serials"], ",", -1, true), post.Name) {
+			return response.NotFound(fmt.Errorf("Serial console %q not found", post.Name))
+		}
+	}
+
 	if !inst.IsRunning() {
 		return response.BadRequest(errors.New("Instance is not running"))
 	}
@@ -537,6 +568,12 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 			continue
 		}
 
+		// Each serial console can be connected to separately.
+		opName, _ := op.Metadata()["name"].(string)
+		if opName != post.Name {
+			continue
+		}
+
 		if !post.Force {
 			return response.SmartError(errors.New("This console is already connected. Force is required to take it over."))
 		}
@@ -566,6 +603,7 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 	ws.width = post.Width
 	ws.height = post.Height
 	ws.protocol = post.Type
+	ws.name = post.Name
 
 	resources := map[string][]api.URL{}
 	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", ws.instance.Name())}
@@ -601,6 +639,11 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 //	    enum: [log, vga]
 //	    default: log
 //	    example: vga
+//	  - in: query
+//	    name: name
+//	    description: Name of the additional serial console (log type and virtual machines only)
+//	    type: string
+//	    example: admin
 //	responses:
 //	  "200":
 //	     description: |
@@ -637,6 +680,11 @@ func instanceConsoleLogGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Invalid value for type parameter: %s", consoleLogType))
 	}
 
+	serialName := request.QueryParam(r, "name")
+	if serialName != "" && consoleLogType == "vga" {
+		return response.BadRequest(errors.New("The name parameter is only supported with the log type"))
+	}
+
 	if internalInstance.IsSnapshot(name) {
 		return response.BadRequest(errors.New("Invalid instance name"))
 	}
@@ -658,6 +706,25 @@ func instanceConsoleLogGet(d *Daemon, r *http.Request) response.Response {
 
 	ent := response.FileResponseEntry{}
 
+	// Logs of the additional serial consoles are only available for virtual machines.
+	if serialName != "" {
+		v, ok := inst.(instance.VM)
+		if !ok {
+			return response.BadRequest(errors.New("Additional serial consoles are only supported by virtual machines"))
+		}
+
+		logContents, err := v.SerialConsoleLog(serialName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		ent.File = bytes.NewReader([]byte(logContents))
+		ent.FileModified = time.Now()
+		ent.FileSize = int64(len(logContents))
+
+		return response.FileResponse(r, []response.FileResponseEntry{ent}, nil)
+	}
+
 	if !inst.IsRunning() {
 		// Check if we have data we can return.
 		consoleBufferLogPath := inst.ConsoleBufferLogPath()
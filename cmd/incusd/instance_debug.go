@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -10,10 +11,17 @@ import (
 	"github.com/gorilla/mux"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
 )
 
 // swagger:operation GET /1.0/instances/{name}/debug/memory instances instance_debug_memory_get
@@ -131,3 +139,360 @@ func instanceDebugMemoryGet(d *Daemon, r *http.Request) response.Response {
 		return nil
 	})
 }
+
+// swagger:operation GET /1.0/instances/{name}/debug/lxc-config instances instance_debug_lxc_config_get
+//
+//	Get the liblxc configuration of an instance
+//
+//	Returns the final liblxc configuration of a container, including raw.lxc,
+//	along with the keys generated by Incus which raw.lxc overrides.
+//	Only supported for containers.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: liblxc configuration
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceDebugLXCConfig"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceDebugLXCConfigGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to a container on a different node
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.Type() != instancetype.Container {
+		return response.BadRequest(errors.New("The liblxc configuration is only available for containers"))
+	}
+
+	c, ok := inst.(instance.Container)
+	if !ok {
+		return response.InternalError(errors.New("Failed to cast inst to Container"))
+	}
+
+	result, err := c.DebugLXCConfig()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation POST /1.0/instances/{name}/debug/qmp instances instance_debug_qmp_post
+//
+//	Run a QMP command on an instance
+//
+//	Sends a QMP command to a running virtual machine and returns its result.
+//	Only a limited set of commands is allowed and this requires administrative access.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: command
+//	    description: QMP command
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceDebugQMPPost"
+//	responses:
+//	  "200":
+//	    description: QMP command result
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceDebugQMP"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceDebugQMPPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to a container on a different node
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	req := api.InstanceDebugQMPPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Command == "" {
+		return response.BadRequest(errors.New("No QMP command provided"))
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.Type() != instancetype.VM {
+		return response.BadRequest(errors.New("QMP commands are only supported for virtual machines"))
+	}
+
+	v, ok := inst.(instance.VM)
+	if !ok {
+		return response.InternalError(errors.New("Failed to cast inst to VM"))
+	}
+
+	// Record who sent the command, including the refused ones.
+	requestor := request.CreateRequestor(r)
+	logger.Info("QMP passthrough command", logger.Ctx{"project": projectName, "instance": name, "command": req.Command, "username": requestor.Username, "protocol": requestor.Protocol})
+
+	result, err := v.QMPPassthrough(req.Command, req.Arguments)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	event := lifecycle.InstanceQMPCommand.Event(inst, logger.Ctx{"command": req.Command})
+	event.Requestor = requestor
+	s.Events.SendLifecycle(projectName, event)
+
+	return response.SyncResponse(true, api.InstanceDebugQMP{Return: result})
+}
+
+// swagger:operation POST /1.0/instances/{name}/debug/netns instances instance_debug_netns_post
+//
+//	Run a command in the network namespace of a container
+//
+//	Runs a command on the host, joined only to the network namespace of a running container.
+//	The command doesn't enter the filesystem, PID namespace or security context of the container,
+//	which allows using host tools like tcpdump or ss against its networking.
+//
+//	The websockets of the returned operation behave like those of the exec API.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: command
+//	    description: Command request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceDebugNetnsPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceDebugNetnsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	req := api.InstanceDebugNetnsPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Forward the request if the container is remote.
+	client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, name, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if client != nil {
+		url := api.NewURL().Path(version.APIVersion, "instances", name, "debug", "netns").Project(projectName)
+		resp, _, err := client.RawQuery("POST", url.String(), req, "")
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		opAPI, err := resp.MetadataAsOperation()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return operations.ForwardedOperationResponse(projectName, opAPI)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.Type() != instancetype.Container {
+		return response.BadRequest(errors.New("Network namespace commands are only supported for containers"))
+	}
+
+	if !inst.IsRunning() {
+		return response.BadRequest(errors.New("Instance is not running"))
+	}
+
+	// The command runs on the host, so default to a host shell and a host environment.
+	post := api.InstanceExecPost{
+		Command:     req.Command,
+		Environment: req.Environment,
+		WaitForWS:   true,
+		Interactive: req.Interactive,
+		Width:       req.Width,
+		Height:      req.Height,
+		Cwd:         req.Cwd,
+	}
+
+	if len(post.Command) == 0 {
+		post.Command = []string{"/bin/sh"}
+	}
+
+	if post.Cwd == "" {
+		post.Cwd = "/"
+	}
+
+	if post.Environment == nil {
+		post.Environment = map[string]string{}
+	}
+
+	defaultEnv := map[string]string{
+		"PATH": "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+		"HOME": "/root",
+		"USER": "root",
+		"LANG": "C.UTF-8",
+	}
+
+	for k, v := range defaultEnv {
+		_, ok := post.Environment[k]
+		if !ok {
+			post.Environment[k] = v
+		}
+	}
+
+	// Record who started the command.
+	requestor := request.CreateRequestor(r)
+	logger.Info("Network namespace command", logger.Ctx{"project": projectName, "instance": name, "command": post.Command, "username": requestor.Username, "protocol": requestor.Protocol})
+
+	ws, err := newExecWs(s, inst, post)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	ws.netns = true
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassWebsocket, operationtype.CommandExec, resources, ws.metadata(), ws.do, nil, ws.connect, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
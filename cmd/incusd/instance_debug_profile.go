@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+const (
+	// instanceProfileDefaultDuration is the default duration of a CPU profiling in seconds.
+	instanceProfileDefaultDuration = 30
+
+	// instanceProfileMaxDuration is the maximum duration of a CPU profiling in seconds.
+	instanceProfileMaxDuration = 600
+
+	// instanceProfileDefaultFrequency is the default sampling frequency of a CPU profiling in Hz.
+	instanceProfileDefaultFrequency = 99
+
+	// instanceProfileMaxFrequency is the maximum sampling frequency of a CPU profiling in Hz.
+	instanceProfileMaxFrequency = 1000
+)
+
+// swagger:operation POST /1.0/instances/{name}/debug/profile instances instance_debug_profile_post
+//
+//	Capture a CPU profile of an instance
+//
+//	Samples the call stacks of a running instance on the host with perf, scoped to the cgroup
+//	of a container or to the vCPU threads of a virtual machine.
+//
+//	Once the operation succeeds, its metadata contains the samples as folded stacks under the `profile` key,
+//	one stack per line followed by its number of samples, which can be rendered as a flame graph.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: profile
+//	    description: Profiling request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceDebugProfilePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceDebugProfilePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	req := api.InstanceDebugProfilePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Forward the request if the instance is remote.
+	client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, name, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if client != nil {
+		url := api.NewURL().Path(version.APIVersion, "instances", name, "debug", "profile").Project(projectName)
+		resp, _, err := client.RawQuery("POST", url.String(), req, "")
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		opAPI, err := resp.MetadataAsOperation()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return operations.ForwardedOperationResponse(projectName, opAPI)
+	}
+
+	if req.Duration == 0 {
+		req.Duration = instanceProfileDefaultDuration
+	}
+
+	if req.Duration < 0 || req.Duration > instanceProfileMaxDuration {
+		return response.BadRequest(fmt.Errorf("Profiling duration must be between 1 and %d seconds", instanceProfileMaxDuration))
+	}
+
+	if req.Frequency == 0 {
+		req.Frequency = instanceProfileDefaultFrequency
+	}
+
+	if req.Frequency < 0 || req.Frequency > instanceProfileMaxFrequency {
+		return response.BadRequest(fmt.Errorf("Sampling frequency must be between 1 and %d Hz", instanceProfileMaxFrequency))
+	}
+
+	_, err = exec.LookPath("perf")
+	if err != nil {
+		return response.BadRequest(errors.New("CPU profiling requires perf to be installed on the server"))
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !inst.IsRunning() {
+		return response.BadRequest(errors.New("Instance is not running"))
+	}
+
+	// Scope the profiling to the instance.
+	var scope []string
+	switch inst.Type() {
+	case instancetype.Container:
+		cgroupPath, err := instanceProfileCgroup(inst.InitPID())
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed getting the cgroup of the instance: %w", err))
+		}
+
+		scope = []string{"--all-cpus", "--cgroup", cgroupPath}
+	case instancetype.VM:
+		v, ok := inst.(instance.VM)
+		if !ok {
+			return response.InternalError(errors.New("Failed to cast inst to VM"))
+		}
+
+		threads, err := v.VCPUThreads()
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed getting the vCPU threads of the instance: %w", err))
+		}
+
+		tids := make([]string, 0, len(threads))
+		for _, tid := range threads {
+			tids = append(tids, strconv.Itoa(tid))
+		}
+
+		scope = []string{"--tid", strings.Join(tids, ",")}
+	default:
+		return response.BadRequest(errors.New("Unsupported instance type"))
+	}
+
+	// Record who started the profiling.
+	requestor := request.CreateRequestor(r)
+	logger.Info("Instance CPU profiling", logger.Ctx{"project": projectName, "instance": name, "duration": req.Duration, "frequency": req.Frequency, "username": requestor.Username, "protocol": requestor.Protocol})
+
+	ctx, cancel := context.WithCancel(s.ShutdownCtx)
+
+	run := func(op *operations.Operation) error {
+		defer cancel()
+
+		profile, err := instanceProfileCapture(ctx, scope, req.Duration, req.Frequency)
+		if err != nil {
+			return err
+		}
+
+		return op.UpdateMetadata(map[string]any{"format": "folded", "profile": profile})
+	}
+
+	onCancel := func(op *operations.Operation) error {
+		cancel()
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.InstanceDebugProfile, resources, nil, run, onCancel, nil, r)
+	if err != nil {
+		cancel()
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// instanceProfileCgroup returns the cgroup of a container relative to the cgroup mount, as expected by perf.
+// Nested cgroups created by the workload of the container are left out so that all its processes are sampled.
+func instanceProfileCgroup(pid int) (string, error) {
+	if pid <= 0 {
+		return "", errors.New("Instance has no init process")
+	}
+
+	f, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	// Prefer the perf_event controller on cgroup1 hosts, then the unified hierarchy.
+	var cgroupPath string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		if fields[1] == "" && cgroupPath == "" {
+			cgroupPath = fields[2]
+			continue
+		}
+
+		for _, controller := range strings.Split(fields[1], ",") {
+			if controller == "perf_event" {
+				cgroupPath = fields[2]
+				break
+			}
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return "", err
+	}
+
+	if cgroupPath == "" {
+		return "", errors.New("No perf_event cgroup found")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(cgroupPath, "/"), "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, "lxc.payload.") {
+			parts = parts[:i+1]
+			break
+		}
+	}
+
+	return strings.Join(parts, "/"), nil
+}
+
+// instanceProfileCapture samples the call stacks of the scoped processes for the given duration and returns them folded.
+func instanceProfileCapture(ctx context.Context, scope []string, duration int, frequency int) (string, error) {
+	dataFile, err := os.CreateTemp(internalUtil.VarPath(), "incus_profile_")
+	if err != nil {
+		return "", err
+	}
+
+	_ = dataFile.Close()
+	defer func() { _ = os.Remove(dataFile.Name()) }()
+
+	args := []string{"record", "--quiet", "--call-graph", "fp", "--freq", strconv.Itoa(frequency), "--output", dataFile.Name()}
+	args = append(args, scope...)
+	args = append(args, "--", "sleep", strconv.Itoa(duration))
+
+	_, err = subprocess.RunCommandContext(ctx, "perf", args...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		return "", fmt.Errorf("Failed recording the profile: %w", err)
+	}
+
+	out, err := subprocess.RunCommandContext(ctx, "perf", "script", "--input", dataFile.Name(), "--fields", "comm,ip,sym")
+	if err != nil {
+		return "", fmt.Errorf("Failed reading the profile: %w", err)
+	}
+
+	return instanceProfileFold(out), nil
+}
+
+// instanceProfileFold converts the output of perf script into folded stacks, with the frames of each stack
+// going from the outermost to the innermost call, prefixed by the command name and followed by the sample count.
+func instanceProfileFold(script string) string {
+	counts := map[string]int{}
+
+	var comm string
+	var frames []string
+
+	flush := func() {
+		if comm == "" {
+			return
+		}
+
+		stack := []string{comm}
+		for i := len(frames) - 1; i >= 0; i-- {
+			stack = append(stack, frames[i])
+		}
+
+		counts[strings.Join(stack, ";")]++
+
+		comm = ""
+		frames = nil
+	}
+
+	for _, line := range strings.Split(script, "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		// Frames are indented with a tab below the line of their sample, whose command name is padded with spaces.
+		if line[0] != '\t' {
+			flush()
+			comm = strings.ReplaceAll(strings.TrimSpace(line), " ", "_")
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		symbol, _, _ := strings.Cut(strings.Join(fields[1:], " "), "+0x")
+		frames = append(frames, strings.ReplaceAll(symbol, ";", ":"))
+	}
+
+	flush()
+
+	stacks := make([]string, 0, len(counts))
+	for stack := range counts {
+		stacks = append(stacks, stack)
+	}
+
+	sort.Strings(stacks)
+
+	var sb strings.Builder
+	for _, stack := range stacks {
+		fmt.Fprintf(&sb, "%s %d\n", stack, counts[stack])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// instanceDeviceMediaLoad loads the running virtual machine targeted by a device media request.
+// It returns a response instead when the request must be forwarded or is invalid.
+func instanceDeviceMediaLoad(d *Daemon, r *http.Request) (instance.VM, string, response.Response) {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	devName, err := url.PathUnescape(mux.Vars(r)["device"])
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return nil, "", response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name)
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	if resp != nil {
+		return nil, "", resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	if inst.Type() != instancetype.VM {
+		return nil, "", response.BadRequest(errors.New("Removable media is only supported for virtual machines"))
+	}
+
+	if !inst.IsRunning() {
+		return nil, "", response.BadRequest(errors.New("Instance must be running to change removable media"))
+	}
+
+	v, ok := inst.(instance.VM)
+	if !ok {
+		return nil, "", response.InternalError(errors.New("Failed to cast inst to VM"))
+	}
+
+	return v, devName, nil
+}
+
+// swagger:operation GET /1.0/instances/{name}/devices/{device}/media instances instance_device_media_get
+//
+//	Get the removable media state of a device
+//
+//	Returns whether media is inserted in the removable drive of a disk device.
+//	Only supported for running virtual machines.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Removable media state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceDeviceMedia"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceDeviceMediaGet(d *Daemon, r *http.Request) response.Response {
+	v, devName, resp := instanceDeviceMediaLoad(d, r)
+	if resp != nil {
+		return resp
+	}
+
+	inserted, err := v.DeviceMediaInserted(devName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, api.InstanceDeviceMedia{Inserted: inserted})
+}
+
+// swagger:operation POST /1.0/instances/{name}/devices/{device}/media instances instance_device_media_post
+//
+//	Eject or insert the removable media of a device
+//
+//	Ejects, inserts or toggles the media in the removable drive of a disk device.
+//	Ejected media stays out across guest reboots until the instance is stopped.
+//	Only supported for running virtual machines.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: media
+//	    description: Removable media action
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceDeviceMediaPost"
+//	responses:
+//	  "200":
+//	    description: Resulting removable media state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceDeviceMedia"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceDeviceMediaPost(d *Daemon, r *http.Request) response.Response {
+	v, devName, resp := instanceDeviceMediaLoad(d, r)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.InstanceDeviceMediaPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Action == "" {
+		req.Action = "toggle"
+	}
+
+	if req.Action != "eject" && req.Action != "insert" && req.Action != "toggle" {
+		return response.BadRequest(fmt.Errorf("Invalid removable media action %q", req.Action))
+	}
+
+	inserted := req.Action == "insert"
+	if req.Action == "toggle" {
+		current, err := v.DeviceMediaInserted(devName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		inserted = !current
+	}
+
+	err = v.SetDeviceMediaInserted(devName, inserted)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, api.InstanceDeviceMedia{Inserted: inserted})
+}
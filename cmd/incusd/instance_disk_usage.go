@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// swagger:operation GET /1.0/instances/{name}/disk-usage instances instance_disk_usage_get
+//
+//	Get the disk usage breakdown of an instance
+//
+//	Returns the disk space used by the root volume of the instance, the space
+//	unique to each of its snapshots and the usage of its attached custom volumes.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Disk usage breakdown
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceDiskUsage"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceDiskUsageGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to a container on a different node
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": name})
+
+	// usage converts the result of a usage call, reporting drivers which can't provide it with -1.
+	usage := func(pool storagePools.Pool, volName string, getUsage func() (*storagePools.VolumeUsage, error)) api.InstanceDiskUsageVolume {
+		entry := api.InstanceDiskUsageVolume{Pool: pool.Name(), Volume: volName, Usage: -1}
+
+		result, err := getUsage()
+		if err != nil {
+			if !errors.Is(err, storageDrivers.ErrNotSupported) {
+				l.Warn("Failed getting volume usage", logger.Ctx{"pool": pool.Name(), "volume": volName, "err": err})
+			}
+
+			return entry
+		}
+
+		entry.Usage = result.Used
+
+		return entry
+	}
+
+	diskUsage := api.InstanceDiskUsage{
+		Snapshots: map[string]api.InstanceDiskUsageVolume{},
+		Volumes:   map[string]api.InstanceDiskUsageVolume{},
+	}
+
+	// Root volume.
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	diskUsage.Root = usage(pool, inst.Name(), func() (*storagePools.VolumeUsage, error) { return pool.GetInstanceUsage(inst) })
+
+	// Snapshots, for which the storage drivers report the space not shared with the volume or other snapshots.
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for _, snap := range snapshots {
+		_, snapName, _ := api.GetParentAndSnapshotName(snap.Name())
+		diskUsage.Snapshots[snapName] = usage(pool, snap.Name(), func() (*storagePools.VolumeUsage, error) { return pool.GetInstanceUsage(snap) })
+	}
+
+	// Attached custom volumes.
+	volProjectName, err := project.StorageVolumeProject(s.DB.Cluster, projectName, db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for _, dev := range inst.ExpandedDevices().Sorted() {
+		if dev.Config["type"] != "disk" || dev.Config["path"] == "/" || dev.Config["pool"] == "" || dev.Config["source"] == "" {
+			continue
+		}
+
+		volPool, err := storagePools.LoadByName(s, dev.Config["pool"])
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		volName := dev.Config["source"]
+		diskUsage.Volumes[dev.Name] = usage(volPool, volName, func() (*storagePools.VolumeUsage, error) {
+			return volPool.GetCustomVolumeUsage(volProjectName, volName)
+		})
+	}
+
+	return response.SyncResponse(true, diskUsage)
+}
@@ -59,6 +59,41 @@ type execWs struct {
 	waitControlConnected  *cancel.Canceller
 	fds                   map[int]string
 	s                     *state.State
+
+	// Run the command on the host, joined only to the network namespace of the container.
+	netns bool
+}
+
+// newExecWs prepares the websockets of an exec request which has WaitForWS enabled.
+func newExecWs(s *state.State, inst instance.Instance, req api.InstanceExecPost) (*execWs, error) {
+	ws := &execWs{}
+	ws.s = s
+	ws.fds = map[int]string{}
+
+	ws.conns = map[int]*websocket.Conn{}
+	ws.conns[execWSControl] = nil
+	ws.conns[0] = nil // This is used for either TTY or Stdin.
+	if !req.Interactive {
+		ws.conns[execWSStdout] = nil
+		ws.conns[execWSStderr] = nil
+	}
+
+	ws.waitRequiredConnected = cancel.New(context.Background())
+	ws.waitControlConnected = cancel.New(context.Background())
+
+	for i := range ws.conns {
+		var err error
+
+		ws.fds[i], err = internalUtil.RandomHexString(32)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ws.instance = inst
+	ws.req = req
+
+	return ws, nil
 }
 
 func (s *execWs) metadata() any {
@@ -200,17 +235,20 @@ func (s *execWs) do(op *operations.Operation) error {
 			var rootUID, rootGID int64
 			var devptsFd *os.File
 
-			c := s.instance.(instance.Container)
-			idmapset, err := c.CurrentIdmap()
-			if err != nil {
-				return err
-			}
+			// Commands run in the network namespace only are host processes and so use a host PTY.
+			if !s.netns {
+				c := s.instance.(instance.Container)
+				idmapset, err := c.CurrentIdmap()
+				if err != nil {
+					return err
+				}
 
-			if idmapset != nil {
-				rootUID, rootGID = idmapset.ShiftIntoNS(0, 0)
-			}
+				if idmapset != nil {
+					rootUID, rootGID = idmapset.ShiftIntoNS(0, 0)
+				}
 
-			devptsFd, _ = c.DevptsFd()
+				devptsFd, _ = c.DevptsFd()
+			}
 
 			if devptsFd != nil && s.s.OS.NativeTerminals {
 				ptys[0], ttys[0], err = linux.OpenPtyInDevpts(int(devptsFd.Fd()), rootUID, rootGID)
@@ -307,7 +345,13 @@ func (s *execWs) do(op *operations.Operation) error {
 		return cmdErr
 	}
 
-	cmd, err := s.instance.Exec(s.req, stdin, stdout, stderr)
+	var cmd instance.Cmd
+	if s.netns {
+		cmd, err = s.instance.(instance.Container).ExecNetns(s.req, stdin, stdout, stderr)
+	} else {
+		cmd, err = s.instance.Exec(s.req, stdin, stdout, stderr)
+	}
+
 	if err != nil {
 		return finisher(-1, err)
 	}
@@ -520,6 +564,9 @@ func (s *execWs) do(op *operations.Operation) error {
 //	An additional "control" socket is always added on top which can be used for out of band communications.
 //	This allows sending signals and window sizing information through.
 //
+//	With the "wait" parameter, the command is run without websockets and the response is only sent once it
+//	has exited, with its exit status and up to 1MiB of each of its standard output and standard error.
+//
 //	---
 //	consumes:
 //	  - application/json
@@ -531,12 +578,37 @@ func (s *execWs) do(op *operations.Operation) error {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: wait
+//	    description: Whether to wait for the command to exit and return its output
+//	    type: boolean
+//	    example: true
 //	  - in: body
 //	    name: exec
 //	    description: Exec request
 //	    schema:
 //	      $ref: "#/definitions/InstanceExecPost"
 //	responses:
+//	  "200":
+//	    description: Command result
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceExecResult"
 //	  "202":
 //	    $ref: "#/responses/Operation"
 //	  "400":
@@ -578,6 +650,11 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Cannot use %q in combination with %q", "interactive", "record-output"))
 	}
 
+	wait := util.IsTrue(request.QueryParam(r, "wait"))
+	if wait && (post.WaitForWS || post.Interactive || post.RecordOutput) {
+		return response.BadRequest(fmt.Errorf("Cannot use %q in combination with %q, %q or %q", "wait", "wait-for-websocket", "interactive", "record-output"))
+	}
+
 	// Forward the request if the container is remote.
 	client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, name, r)
 	if err != nil {
@@ -586,11 +663,25 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 
 	if client != nil {
 		url := api.NewURL().Path(version.APIVersion, "instances", name, "exec").Project(projectName)
+		if wait {
+			url = url.WithQuery("wait", "true")
+		}
+
 		resp, _, err := client.RawQuery("POST", url.String(), post, "")
 		if err != nil {
 			return response.SmartError(err)
 		}
 
+		if wait {
+			result := api.InstanceExecResult{}
+			err = resp.MetadataAsStruct(&result)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			return response.SyncResponse(true, result)
+		}
+
 		opAPI, err := resp.MetadataAsOperation()
 		if err != nil {
 			return response.SmartError(err)
@@ -674,32 +765,16 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 		post.Environment["LANG"] = "C.UTF-8"
 	}
 
-	if post.WaitForWS {
-		ws := &execWs{}
-		ws.s = d.State()
-		ws.fds = map[int]string{}
-
-		ws.conns = map[int]*websocket.Conn{}
-		ws.conns[execWSControl] = nil
-		ws.conns[0] = nil // This is used for either TTY or Stdin.
-		if !post.Interactive {
-			ws.conns[execWSStdout] = nil
-			ws.conns[execWSStderr] = nil
-		}
-
-		ws.waitRequiredConnected = cancel.New(context.Background())
-		ws.waitControlConnected = cancel.New(context.Background())
+	if wait {
+		return instanceExecSync(r.Context(), inst, post)
+	}
 
-		for i := range ws.conns {
-			ws.fds[i], err = internalUtil.RandomHexString(32)
-			if err != nil {
-				return response.InternalError(err)
-			}
+	if post.WaitForWS {
+		ws, err := newExecWs(s, inst, post)
+		if err != nil {
+			return response.InternalError(err)
 		}
 
-		ws.instance = inst
-		ws.req = post
-
 		resources := map[string][]api.URL{}
 		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", ws.instance.Name())}
 
@@ -788,3 +863,106 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 
 	return operations.OperationResponse(op)
 }
+
+// execSyncOutputLimit is the maximum size of each of the standard output and standard error returned by
+// synchronous exec.
+const execSyncOutputLimit = 1024 * 1024
+
+// execSyncOutput captures the output of a command up to execSyncOutputLimit and discards the rest.
+type execSyncOutput struct {
+	buf       []byte
+	truncated bool
+}
+
+// Write implements io.Writer.
+func (o *execSyncOutput) Write(p []byte) (int, error) {
+	remaining := execSyncOutputLimit - len(o.buf)
+	if len(p) > remaining {
+		o.buf = append(o.buf, p[:remaining]...)
+		o.truncated = true
+
+		return len(p), nil
+	}
+
+	o.buf = append(o.buf, p...)
+
+	return len(p), nil
+}
+
+// instanceExecSync runs a command and returns its exit status and output once it has exited.
+// The command is killed if the client disconnects before.
+func instanceExecSync(ctx context.Context, inst instance.Instance, post api.InstanceExecPost) response.Response {
+	outputs := []*execSyncOutput{{}, {}}
+	readers := make([]*os.File, 0, len(outputs))
+	writers := make([]*os.File, 0, len(outputs))
+
+	defer func() {
+		for _, f := range append(readers, writers...) {
+			_ = f.Close()
+		}
+	}()
+
+	for range outputs {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		readers = append(readers, reader)
+		writers = append(writers, writer)
+	}
+
+	// Capture the output until the command and its children close the pipes.
+	wg := sync.WaitGroup{}
+	for i := range outputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = io.Copy(outputs[i], readers[i])
+		}()
+	}
+
+	cmd, err := inst.Exec(post, nil, writers[0], writers[1])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "PID": cmd.PID()})
+	l.Debug("Instance process started")
+
+	// Kill the command if the client disconnects.
+	cmdDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.Debug("Client disconnected, killing command")
+			_ = cmd.Signal(unix.SIGKILL)
+		case <-cmdDone:
+		}
+	}()
+
+	exitStatus, cmdErr := cmd.Wait()
+	close(cmdDone)
+	l.Debug("Instance process stopped", logger.Ctx{"err": cmdErr, "exitStatus": exitStatus})
+
+	// Don't wait for background processes that inherited the pipes for longer than a second.
+	for i := range outputs {
+		_ = writers[i].Close()
+		_ = readers[i].SetReadDeadline(time.Now().Add(time.Second))
+	}
+
+	writers = nil
+	wg.Wait()
+
+	if cmdErr != nil {
+		return response.SmartError(cmdErr)
+	}
+
+	return response.SyncResponse(true, api.InstanceExecResult{
+		Return:          exitStatus,
+		Stdout:          string(outputs[0].buf),
+		Stderr:          string(outputs[1].buf),
+		StdoutTruncated: outputs[0].truncated,
+		StderrTruncated: outputs[1].truncated,
+	})
+}
@@ -19,6 +19,7 @@ import (
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
@@ -81,6 +82,128 @@ func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 	}
 }
 
+// swagger:operation GET /1.0/instances/{name}/snapshots/{snapshot}/files instances instance_snapshot_files_get
+//
+//	Get a file from a snapshot
+//
+//	Gets the file content from the snapshot. If it's a directory, a json list of files will be returned instead.
+//
+//	---
+//	produces:
+//	  - application/json
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: path
+//	    description: Path to the file
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	     description: Raw file or directory listing
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation HEAD /1.0/instances/{name}/snapshots/{snapshot}/files instances instance_snapshot_files_head
+//
+//	Get metadata for a file from a snapshot
+//
+//	Gets the file or directory metadata from the snapshot.
+//
+//	---
+//	parameters:
+//	  - in: query
+//	    name: path
+//	    description: Path to the file
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	     description: Raw file or directory listing
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSnapshotFileHandler(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	instName, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	snapshotName, err := url.PathUnescape(mux.Vars(r)["snapshotName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(instName) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Redirect to correct server if needed.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, instName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	// Load the snapshot.
+	snapInst, err := instance.LoadByProjectAndName(s, projectName, instName+internalInstance.SnapshotDelimiter+snapshotName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Virtual machine files are only reachable through the agent of the running instance.
+	if snapInst.Type() != instancetype.Container {
+		return response.NotImplemented(errors.New("Reading files from virtual machine snapshots isn't supported"))
+	}
+
+	// Parse and cleanup the path.
+	path := r.FormValue("path")
+	if path == "" {
+		return response.BadRequest(errors.New("Missing path argument"))
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	// Snapshots are read-only, the snapshot volume being mounted on demand to serve the request.
+	switch r.Method {
+	case "GET":
+		return instanceFileGet(s, snapInst, path, r)
+	case "HEAD":
+		return instanceFileHead(s, snapInst, path, r)
+	default:
+		return response.NotFound(fmt.Errorf("Method %q not found", r.Method))
+	}
+}
+
 // swagger:operation GET /1.0/instances/{name}/files instances instance_files_get
 //
 //	Get a file
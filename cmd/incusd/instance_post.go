@@ -8,6 +8,7 @@ import (
 	"maps"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -23,6 +24,7 @@ import (
 	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/network"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
@@ -30,10 +32,13 @@ import (
 	"github.com/lxc/incus/v6/internal/server/scriptlet"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 // swagger:operation POST /1.0/instances/{name} instances instance_post
@@ -59,12 +64,37 @@ import (
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: dry-run
+//	    description: Only check whether the migration can be performed (returns an InstanceMigrationCheck report)
+//	    type: boolean
+//	    example: true
 //	  - in: body
 //	    name: migration
 //	    description: Migration request
 //	    schema:
 //	      $ref: "#/definitions/InstancePost"
 //	responses:
+//	  "200":
+//	    description: Migration pre-flight check report
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceMigrationCheck"
 //	  "202":
 //	    $ref: "#/responses/Operation"
 //	  "400":
@@ -82,6 +112,7 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 	// Parse the request URL.
 	projectName := request.ProjectParam(r)
 	target := request.QueryParam(r, "target")
+	dryRun := util.IsTrue(request.QueryParam(r, "dry-run"))
 
 	name, err := url.PathUnescape(mux.Vars(r)["name"])
 	if err != nil {
@@ -155,6 +186,10 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	if dryRun && !req.Migration {
+		return response.BadRequest(errors.New("Dry-run is only supported for migrations"))
+	}
+
 	// Target instance properties.
 	instProject := projectName
 	instLocation := target
@@ -479,6 +514,20 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// Only report on the migration if requested.
+	if dryRun {
+		if targetMemberInfo != nil && inst.Location() == targetMemberInfo.Name {
+			targetMemberInfo = nil
+		}
+
+		report, err := instanceMigrationCheck(s, inst, req, instProject, targetMemberInfo)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, report)
+	}
+
 	// Server-side instance migration.
 	if req.Pool != "" || req.Project != "" || target != "" {
 		// Clear targetMemberInfo if no target change required.
@@ -486,6 +535,11 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			targetMemberInfo = nil
 		}
 
+		// Pool and project moves are performed with local copies which can't be verified.
+		if req.Verify && (targetMemberInfo == nil || req.Pool != "" || req.Project != "") {
+			return response.BadRequest(errors.New("Verification is only supported when moving instances between servers or cluster members"))
+		}
+
 		// Setup the instance move operation.
 		run := func(op *operations.Operation) error {
 			inst.SetOperation(op)
@@ -503,11 +557,18 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 	}
 
 	// Cross-server instance migration.
-	ws, err := newMigrationSource(inst, req.Live, req.InstanceOnly, req.AllowInconsistent, "", "", req.Target)
+	ws, err := newMigrationSource(inst, req.Live, req.InstanceOnly, req.AllowInconsistent, "", "", req.Target, s.GlobalConfig.MigrationResumeTimeout(), s.GlobalConfig.MigrationStreams(), s.GlobalConfig.MigrationConcurrency(), s.GlobalConfig.MigrationRawTransport())
 	if err != nil {
 		return response.InternalError(err)
 	}
 
+	ws.verify = req.Verify
+
+	ws.timeouts, err = newMigrationTimeouts(s, req.Timeouts)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	resources := map[string][]api.URL{}
 	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
 	run := func(op *operations.Operation) error {
@@ -515,13 +576,13 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 	}
 
 	cancel := func(op *operations.Operation) error {
-		ws.disconnect()
+		ws.abort()
 		return nil
 	}
 
 	if req.Target != nil {
 		// Push mode.
-		op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.InstanceMigrate, resources, nil, run, nil, nil, r)
+		op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.InstanceMigrate, resources, nil, run, cancel, nil, r)
 		if err != nil {
 			return response.InternalError(err)
 		}
@@ -643,9 +704,61 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 	}
 
 	// Handle local changes (name, project, storage).
+	reverter := revert.New()
+	defer reverter.Fail()
 
-	// Handle the renames first.
+	// The final name of the instance.
+	targetName := inst.Name()
 	if req.Name != "" {
+		targetName = req.Name
+	}
+
+	copyMove := (req.Project != "" || req.Pool != "") && !req.Live
+
+	// Project moves which keep the instance on the same pool and server don't need to copy its data.
+	inPlaceMove := false
+	if copyMove && req.Project != "" && req.Pool == "" && targetMemberInfo == nil && !req.InstanceOnly {
+		inPlaceMove, err = instanceCanMoveProjectInPlace(ctx, s, inst, req.Project)
+		if err != nil {
+			return err
+		}
+	}
+
+	if inPlaceMove {
+		copyMove = false
+
+		// Keep the current root disk if the target project profiles don't provide a suitable one.
+		err = instanceProjectMoveRootDisk(ctx, s, inst, req.Project, targetInstInfo)
+		if err != nil {
+			return err
+		}
+
+		// Check the target project restrictions and limits.
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := dbCluster.GetInstanceID(ctx, tx.Tx(), req.Project, targetName)
+			if err == nil {
+				return api.StatusErrorf(http.StatusConflict, "Instance %q already exists in project %q", targetName, req.Project)
+			} else if !response.IsNotFoundError(err) {
+				return err
+			}
+
+			return project.AllowInstanceCreation(tx, req.Project, api.InstancesPost{
+				Name:        targetName,
+				InstancePut: targetInstInfo.Writable(),
+				Type:        api.InstanceType(targetInstInfo.Type),
+				Source:      api.InstanceSource{Type: "copy"},
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Instances copied to another project or pool are directly created under their new name.
+	// Otherwise rename the instance first, renaming it back should the move fail.
+	if req.Name != "" && !copyMove {
+		oldName := inst.Name()
+
 		err := inst.Rename(req.Name, true)
 		if err != nil {
 			return err
@@ -656,12 +769,64 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 			return err
 		}
 
-		// Clear the rename part of the request.
-		req.Name = ""
+		renamedInst := inst
+		reverter.Add(func() {
+			err := renamedInst.Rename(oldName, true)
+			if err != nil {
+				logger.Warn("Failed restoring instance name after failed move", logger.Ctx{"project": renamedInst.Project().Name, "instance": req.Name, "err": err})
+			}
+		})
+	}
+
+	// Clear the rename part of the request.
+	req.Name = ""
+
+	// Handle project moves which don't need any copy.
+	if inPlaceMove {
+		sourceProjectName := inst.Project().Name
+		sourceProfiles := make([]string, 0, len(inst.Profiles()))
+		for _, profile := range inst.Profiles() {
+			sourceProfiles = append(sourceProfiles, profile.Name)
+		}
+
+		err = instanceMoveProjectInPlace(s, inst, req.Project, targetInstInfo.Profiles)
+		if err != nil {
+			return err
+		}
+
+		inst, err = instance.LoadByProjectAndName(s, req.Project, targetName)
+		if err != nil {
+			return err
+		}
+
+		movedInst := inst
+		reverter.Add(func() {
+			err := instanceMoveProjectInPlace(s, movedInst, sourceProjectName, sourceProfiles)
+			if err != nil {
+				logger.Warn("Failed restoring instance project after failed move", logger.Ctx{"project": movedInst.Project().Name, "instance": movedInst.Name(), "err": err})
+			}
+		})
+
+		// Apply the overrides and validate the instance in its new project.
+		err = inst.Update(db.InstanceArgs{
+			Architecture: inst.Architecture(),
+			Config:       targetInstInfo.Config,
+			Description:  targetInstInfo.Description,
+			Devices:      deviceConfig.NewDevices(targetInstInfo.Devices),
+			Ephemeral:    targetInstInfo.Ephemeral,
+			Profiles:     inst.Profiles(),
+			Project:      req.Project,
+		}, false)
+		if err != nil {
+			return fmt.Errorf("Failed updating instance in project %q: %w", req.Project, err)
+		}
+
+		// Clear the project part of the request.
+		req.Project = ""
 	}
 
 	// Handle pool and project moves for stopped instances.
-	if (req.Project != "" || req.Pool != "") && !req.Live {
+	if copyMove {
 		// Get a local client.
 		args := &incus.ConnectionArgs{
 			SkipGetServer: true,
@@ -686,74 +851,16 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 
 		target = target.UseProject(targetProject)
 
-		// Check if we have a root disk in local config.
-		_, _, err = internalInstance.GetRootDiskDevice(targetInstInfo.Devices)
-		if err != nil && req.Project != "" {
-			// If not and we're dealing with project copy, let's get one.
-			var newRootDev map[string]string
-
-			// Get current root disk.
-			currentRootDevKey, currentRootDev, err := internalInstance.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
-			if err != nil {
-				return err
-			}
-
-			// Load the profiles.
-			profiles := []api.Profile{}
-
-			err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-				rawProfiles, err := dbCluster.GetProfilesIfEnabled(ctx, tx.Tx(), targetProject, targetInstInfo.Profiles)
-				if err != nil {
-					return err
-				}
-
-				profileConfigs, err := dbCluster.GetAllProfileConfigs(ctx, tx.Tx())
-				if err != nil {
-					return err
-				}
-
-				profileDevices, err := dbCluster.GetAllProfileDevices(ctx, tx.Tx())
-				if err != nil {
-					return err
-				}
-
-				for _, profile := range rawProfiles {
-					apiProfile, err := profile.ToAPI(ctx, tx.Tx(), profileConfigs, profileDevices)
-					if err != nil {
-						return err
-					}
-
-					profiles = append(profiles, *apiProfile)
-				}
-
-				return nil
-			})
+		// Keep the current root disk if the target project profiles don't provide a suitable one.
+		if req.Project != "" {
+			err = instanceProjectMoveRootDisk(ctx, s, inst, targetProject, targetInstInfo)
 			if err != nil {
 				return err
 			}
-
-			// Go through expected profiles and look for a root disk.
-			for _, profile := range profiles {
-				_, dev, err := internalInstance.GetRootDiskDevice(profile.Devices)
-				if err != nil {
-					continue
-				}
-
-				newRootDev = dev
-				break
-			}
-
-			// Check if root disk coming from profiles is suitable, if not, copy the current one.
-			if newRootDev == nil ||
-				newRootDev["pool"] != currentRootDev["pool"] ||
-				newRootDev["size"] != currentRootDev["size"] ||
-				newRootDev["size.state"] != currentRootDev["size.state"] {
-				targetInstInfo.Devices[currentRootDevKey] = currentRootDev
-			}
 		}
 
 		// Use a temporary instance name if needed.
-		targetInstName := inst.Name()
+		targetInstName := targetName
 		if req.Project == "" {
 			targetInstName, err = instance.MoveTemporaryName(inst)
 			if err != nil {
@@ -800,8 +907,8 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 		}
 
 		// If using a temporary name, rename it.
-		if targetInstName != inst.Name() {
-			op, err := target.RenameInstance(targetInstName, api.InstancePost{Name: inst.Name()})
+		if targetInstName != targetName {
+			op, err := target.RenameInstance(targetInstName, api.InstancePost{Name: targetName})
 			if err != nil {
 				return err
 			}
@@ -813,7 +920,7 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 		}
 
 		// Reload the instance.
-		inst, err = instance.LoadByProjectAndName(s, targetProject, inst.Name())
+		inst, err = instance.LoadByProjectAndName(s, targetProject, targetName)
 		if err != nil {
 			return err
 		}
@@ -858,82 +965,126 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 			return fmt.Errorf("Failed getting source instance snapshots: %w", err)
 		}
 
-		// Setup a new migration source.
-		sourceMigration, err := newMigrationSource(inst, req.Live, false, req.AllowInconsistent, inst.Name(), req.Pool, nil)
-		if err != nil {
-			return fmt.Errorf("Failed setting up instance migration on source: %w", err)
-		}
+		// Transfer the instance to the target member.
+		transfer := func(live bool) error {
+			// Setup a new migration source.
+			sourceMigration, err := newMigrationSource(inst, live, false, req.AllowInconsistent, inst.Name(), req.Pool, nil, s.GlobalConfig.MigrationResumeTimeout(), s.GlobalConfig.MigrationStreams(), s.GlobalConfig.MigrationConcurrency(), s.GlobalConfig.MigrationRawTransport())
+			if err != nil {
+				return fmt.Errorf("Failed setting up instance migration on source: %w", err)
+			}
 
-		run := func(op *operations.Operation) error {
-			return sourceMigration.do(op)
-		}
+			sourceMigration.verify = req.Verify
 
-		cancel := func(op *operations.Operation) error {
-			sourceMigration.disconnect()
-			return nil
-		}
+			sourceMigration.timeouts, err = newMigrationTimeouts(s, req.Timeouts)
+			if err != nil {
+				return err
+			}
 
-		resources := map[string][]api.URL{}
-		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
-		sourceOp, err := operations.OperationCreate(s, inst.Project().Name, operations.OperationClassWebsocket, operationtype.InstanceMigrate, resources, sourceMigration.Metadata(), run, cancel, sourceMigration.Connect, nil)
-		if err != nil {
-			return err
-		}
+			run := func(op *operations.Operation) error {
+				return sourceMigration.do(op)
+			}
 
-		sourceOp.CopyRequestor(op)
+			cancel := func(op *operations.Operation) error {
+				sourceMigration.abort()
+				return nil
+			}
 
-		// Start the migration source.
-		err = sourceOp.Start()
-		if err != nil {
-			return fmt.Errorf("Failed starting migration source operation: %w", err)
-		}
+			resources := map[string][]api.URL{}
+			resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+			sourceOp, err := operations.OperationCreate(s, inst.Project().Name, operations.OperationClassWebsocket, operationtype.InstanceMigrate, resources, sourceMigration.Metadata(), run, cancel, sourceMigration.Connect, nil)
+			if err != nil {
+				return err
+			}
 
-		// Extract the migration secrets.
-		sourceSecrets := make(map[string]string, len(sourceMigration.conns))
-		for connName, conn := range sourceMigration.conns {
-			sourceSecrets[connName] = conn.Secret()
-		}
+			sourceOp.CopyRequestor(op)
 
-		// Create the target instance.
-		destOp, err := target.CreateInstance(api.InstancesPost{
-			Name:        inst.Name(),
-			InstancePut: targetInstInfo.Writable(),
-			Type:        api.InstanceType(targetInstInfo.Type),
-			Source: api.InstanceSource{
-				Type:        "migration",
-				Mode:        "pull",
-				Operation:   fmt.Sprintf("https://%s%s", sourceMemberInfo.Address, sourceOp.URL()),
-				Websockets:  sourceSecrets,
-				Certificate: string(networkCert.PublicKey()),
-				Live:        req.Live,
-				Source:      inst.Name(),
-			},
-		})
-		if err != nil {
-			return fmt.Errorf("Failed requesting instance create on destination: %w", err)
-		}
+			// Start the migration source.
+			err = sourceOp.Start()
+			if err != nil {
+				return fmt.Errorf("Failed starting migration source operation: %w", err)
+			}
 
-		// Setup a progress handler.
-		handler := func(newOp api.Operation) {
-			_ = op.UpdateMetadata(newOp.Metadata)
-		}
+			// Extract the migration secrets.
+			sourceSecrets := make(map[string]string, len(sourceMigration.conns))
+			for connName, conn := range sourceMigration.conns {
+				sourceSecrets[connName] = conn.Secret()
+			}
 
-		_, err = destOp.AddHandler(handler)
-		if err != nil {
-			return err
+			// Create the target instance.
+			destOp, err := target.CreateInstance(api.InstancesPost{
+				Name:        inst.Name(),
+				InstancePut: targetInstInfo.Writable(),
+				Type:        api.InstanceType(targetInstInfo.Type),
+				Source: api.InstanceSource{
+					Type:        "migration",
+					Mode:        "pull",
+					Operation:   fmt.Sprintf("https://%s%s", migrationSourceAddress(s, sourceMemberInfo.Address), sourceOp.URL()),
+					Websockets:  sourceSecrets,
+					Certificate: string(networkCert.PublicKey()),
+					Live:        live,
+					Source:      inst.Name(),
+					Timeouts:    req.Timeouts,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("Failed requesting instance create on destination: %w", err)
+			}
+
+			// Setup a progress handler.
+			handler := func(newOp api.Operation) {
+				_ = op.UpdateMetadata(newOp.Metadata)
+			}
+
+			_, err = destOp.AddHandler(handler)
+			if err != nil {
+				return err
+			}
+
+			// Wait for the migration to complete.
+			err = sourceOp.Wait(context.Background())
+			if err != nil {
+				// Let the target revert the failed instance before it's transferred again.
+				if live && req.LiveFallback {
+					_ = destOp.Wait()
+				}
+
+				return fmt.Errorf("Instance move to destination failed on source: %w", err)
+			}
+
+			err = destOp.Wait()
+			if err != nil {
+				return fmt.Errorf("Instance move to destination failed: %w", err)
+			}
+
+			return nil
 		}
 
-		// Wait for the migration to complete.
-		err = sourceOp.Wait(context.Background())
-		if err != nil {
-			return fmt.Errorf("Instance move to destination failed on source: %w", err)
+		fallback := false
+		fallbackStateful := false
+
+		err = transfer(req.Live)
+		if err != nil && req.Live && req.LiveFallback && inst.IsRunning() {
+			// The instance is still running on the source, fall back to moving it while stopped.
+			logger.Warn("Live migration failed, moving the instance while stopped", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+
+			fallback = true
+			fallbackStateful = inst.Type() == instancetype.VM && util.IsTrue(inst.ExpandedConfig()["migration.stateful"])
+
+			err = inst.Stop(fallbackStateful)
+			if err != nil {
+				return fmt.Errorf("Failed stopping instance after live migration failure: %w", err)
+			}
+
+			err = transfer(false)
 		}
 
-		err = destOp.Wait()
 		if err != nil {
-			return fmt.Errorf("Instance move to destination failed: %w", err)
+			return err
 		}
 
+		// The instance is now on the target member, keep its new name.
+		reverter.Success()
+
 		// Update the database post-migration.
 		err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
 			// Update instance DB record to indicate its location on the new cluster member.
@@ -1010,7 +1161,310 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 				return fmt.Errorf("Failed deleting instance on source member: %w", err)
 			}
 		}
+
+		// Start the instance again if it had to be stopped.
+		if fallback {
+			startOp, err := target.UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: "start", Stateful: fallbackStateful}, "")
+			if err != nil {
+				return fmt.Errorf("Failed starting instance on target member: %w", err)
+			}
+
+			err = startOp.Wait()
+			if err != nil {
+				return fmt.Errorf("Failed starting instance on target member: %w", err)
+			}
+		}
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// instanceProjectMoveRootDisk adds the current root disk to the local devices of an instance moving to another
+// project unless the profiles it uses in that project provide a suitable one.
+func instanceProjectMoveRootDisk(ctx context.Context, s *state.State, inst instance.Instance, targetProject string, targetInstInfo *api.Instance) error {
+	// Check if we have a root disk in local config.
+	_, _, err := internalInstance.GetRootDiskDevice(targetInstInfo.Devices)
+	if err == nil {
+		return nil
+	}
+
+	// If not, let's get one.
+	var newRootDev map[string]string
+
+	// Get current root disk.
+	currentRootDevKey, currentRootDev, err := internalInstance.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
+	if err != nil {
+		return err
+	}
+
+	// Load the profiles.
+	profiles := []api.Profile{}
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		rawProfiles, err := dbCluster.GetProfilesIfEnabled(ctx, tx.Tx(), targetProject, targetInstInfo.Profiles)
+		if err != nil {
+			return err
+		}
+
+		profileConfigs, err := dbCluster.GetAllProfileConfigs(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		profileDevices, err := dbCluster.GetAllProfileDevices(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, profile := range rawProfiles {
+			apiProfile, err := profile.ToAPI(ctx, tx.Tx(), profileConfigs, profileDevices)
+			if err != nil {
+				return err
+			}
+
+			profiles = append(profiles, *apiProfile)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Go through expected profiles and look for a root disk.
+	for _, profile := range profiles {
+		_, dev, err := internalInstance.GetRootDiskDevice(profile.Devices)
+		if err != nil {
+			continue
+		}
+
+		newRootDev = dev
+		break
+	}
+
+	// Check if root disk coming from profiles is suitable, if not, copy the current one.
+	if newRootDev == nil ||
+		newRootDev["pool"] != currentRootDev["pool"] ||
+		newRootDev["size"] != currentRootDev["size"] ||
+		newRootDev["size.state"] != currentRootDev["size.state"] {
+		targetInstInfo.Devices[currentRootDevKey] = currentRootDev
 	}
 
 	return nil
 }
+
+// instanceCanMoveProjectInPlace returns whether the instance can be moved to the target project without copying its data.
+// This requires a stopped instance without backups whose devices refer to the same networks and custom volumes in both projects.
+func instanceCanMoveProjectInPlace(ctx context.Context, s *state.State, inst instance.Instance, targetProjectName string) (bool, error) {
+	if inst.IsRunning() {
+		return false, nil
+	}
+
+	var backups []string
+	var targetProject *api.Project
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		backups, err = tx.GetInstanceBackups(ctx, inst.Project().Name, inst.Name())
+		if err != nil {
+			return fmt.Errorf("Failed to fetch instance's backups: %w", err)
+		}
+
+		p, err := dbCluster.GetProject(ctx, tx.Tx(), targetProjectName)
+		if err != nil {
+			return err
+		}
+
+		targetProject, err = p.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// Backups are stored under the name of the source project.
+	if len(backups) > 0 {
+		return false, nil
+	}
+
+	sourceProject := inst.Project()
+	for _, dev := range inst.ExpandedDevices() {
+		if dev["type"] == "nic" && dev["network"] != "" && project.NetworkProjectFromRecord(&sourceProject) != project.NetworkProjectFromRecord(targetProject) {
+			return false, nil
+		}
+
+		if dev["type"] == "disk" && dev["pool"] != "" && dev["source"] != "" && project.StorageVolumeProjectFromRecord(&sourceProject, db.StoragePoolVolumeTypeCustom) != project.StorageVolumeProjectFromRecord(targetProject, db.StoragePoolVolumeTypeCustom) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// instanceMoveProjectInPlace moves a stopped instance, its snapshots and its volume to another project without
+// copying any data. The profiles of the instance are replaced with the given profiles of the target project.
+func instanceMoveProjectInPlace(s *state.State, inst instance.Instance, targetProjectName string, profiles []string) error {
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	volType, err := storagePools.InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	volDBType, err := storagePools.VolumeTypeToDBType(volType)
+	if err != nil {
+		return err
+	}
+
+	sourceProjectName := inst.Project().Name
+	sourceProfiles := make([]string, 0, len(inst.Profiles()))
+	for _, profile := range inst.Profiles() {
+		sourceProfiles = append(sourceProfiles, profile.Name)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Move the database records.
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateInstanceProject(ctx, sourceProjectName, inst.Name(), targetProjectName, profiles, pool.ID(), volDBType)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed moving instance database records: %w", err)
+	}
+
+	reverter.Add(func() {
+		_ = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateInstanceProject(ctx, targetProjectName, inst.Name(), sourceProjectName, sourceProfiles, pool.ID(), volDBType)
+		})
+	})
+
+	// Move the volume on the storage device.
+	err = pool.MoveInstanceProject(inst, targetProjectName, nil)
+	if err != nil {
+		return fmt.Errorf("Failed moving instance volume: %w", err)
+	}
+
+	// Move the log and runtime paths.
+	for _, pathFunc := range []func(...string) string{internalUtil.LogPath, internalUtil.RunPath} {
+		sourcePath := pathFunc(project.Instance(sourceProjectName, inst.Name()))
+		targetPath := pathFunc(project.Instance(targetProjectName, inst.Name()))
+
+		if !util.PathExists(sourcePath) {
+			continue
+		}
+
+		_ = os.RemoveAll(targetPath)
+		err = os.Rename(sourcePath, targetPath)
+		if err != nil {
+			return fmt.Errorf("Failed moving %q: %w", sourcePath, err)
+		}
+	}
+
+	// Record the move with the authorizer.
+	err = s.Authorizer.DeleteInstance(s.ShutdownCtx, sourceProjectName, inst.Name())
+	if err != nil {
+		logger.Error("Failed to remove instance from authorizer", logger.Ctx{"name": inst.Name(), "project": sourceProjectName, "error": err})
+	}
+
+	err = s.Authorizer.AddInstance(s.ShutdownCtx, targetProjectName, inst.Name())
+	if err != nil {
+		logger.Error("Failed to add instance to authorizer", logger.Ctx{"name": inst.Name(), "project": targetProjectName, "error": err})
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// instanceMigrationCheck checks whether the migration of the instance can be performed, without changing anything.
+func instanceMigrationCheck(s *state.State, inst instance.Instance, req api.InstancePost, targetProject string, targetMemberInfo *db.NodeInfo) (*api.InstanceMigrationCheck, error) {
+	report := &api.InstanceMigrationCheck{Ready: true}
+
+	// Check the instance state.
+	if !inst.IsRunning() {
+		report.Add("state", api.InstanceMigrationCheckOK, "The instance is stopped")
+	} else if inst.Type() == instancetype.VM && util.IsFalseOrEmpty(inst.ExpandedConfig()["migration.stateful"]) {
+		report.Add("state", api.InstanceMigrationCheckError, "Live migration of virtual machines requires migration.stateful to be enabled")
+	} else if inst.Type() == instancetype.Container && !util.PathExists("/usr/sbin/criu") && !util.PathExists("/usr/bin/criu") {
+		report.Add("state", api.InstanceMigrationCheckError, "Live migration of containers requires CRIU to be installed")
+	} else {
+		report.Add("state", api.InstanceMigrationCheckOK, "The instance is running and will be live migrated")
+	}
+
+	// Check the target member.
+	if targetMemberInfo != nil {
+		report.Add("target", api.InstanceMigrationCheckOK, fmt.Sprintf("The instance will be moved to cluster member %q", targetMemberInfo.Name))
+
+		// Live migrated virtual machines use a CPU definition common to the cluster members.
+		if inst.Type() == instancetype.VM && inst.IsRunning() && req.Live {
+			report.Add("cpu", api.InstanceMigrationCheckOK, "The instance uses the cluster CPU baseline")
+		}
+	}
+
+	// Check the storage.
+	sourcePool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	targetPool := sourcePool
+	if req.Pool != "" {
+		targetPool, err = storagePools.LoadByName(s, req.Pool)
+		if err != nil {
+			report.Add("storage", api.InstanceMigrationCheckError, fmt.Sprintf("Target storage pool %q isn't available: %v", req.Pool, err))
+		}
+	}
+
+	if targetPool != nil {
+		sourceDriver := sourcePool.Driver().Info()
+		targetDriver := targetPool.Driver().Info()
+
+		switch {
+		case targetPool.Status() != api.StoragePoolStatusCreated:
+			report.Add("storage", api.InstanceMigrationCheckError, fmt.Sprintf("Target storage pool %q isn't ready", targetPool.Name()))
+		case targetMemberInfo == nil && req.Pool == "":
+			report.Add("storage", api.InstanceMigrationCheckOK, "The instance storage stays in place")
+		case sourcePool.Name() == targetPool.Name() && sourceDriver.Remote:
+			report.Add("storage", api.InstanceMigrationCheckOK, fmt.Sprintf("Storage pool %q is shared, no data will be transferred", targetPool.Name()))
+		case sourceDriver.Name == targetDriver.Name:
+			report.Add("storage", api.InstanceMigrationCheckOK, fmt.Sprintf("The data will be transferred using the optimized %q migration", targetDriver.Name))
+		default:
+			report.Add("storage", api.InstanceMigrationCheckWarning, fmt.Sprintf("The data will be transferred using a generic migration from %q to %q", sourceDriver.Name, targetDriver.Name))
+		}
+	}
+
+	// Check the networks.
+	networkProject, _, err := project.NetworkProject(s.DB.Cluster, targetProject)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading network project: %w", err)
+	}
+
+	for _, devName := range inst.ExpandedDevices().Sorted() {
+		dev := devName.Config
+		if dev["type"] != "nic" || dev["network"] == "" {
+			continue
+		}
+
+		n, err := network.LoadByName(s, networkProject, dev["network"])
+		if err != nil {
+			report.Add("network", api.InstanceMigrationCheckError, fmt.Sprintf("Network %q of device %q isn't available: %v", dev["network"], devName.Name, err))
+			continue
+		}
+
+		if n.Status() != api.NetworkStatusCreated {
+			report.Add("network", api.InstanceMigrationCheckError, fmt.Sprintf("Network %q of device %q isn't ready", dev["network"], devName.Name))
+			continue
+		}
+
+		report.Add("network", api.InstanceMigrationCheckOK, fmt.Sprintf("Network %q of device %q is available", dev["network"], devName.Name))
+	}
+
+	return report, nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/version"
@@ -88,10 +89,15 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	if req.LatestImage && (req.Source.Type != "" || req.Source.Alias != "" || req.Source.Fingerprint != "") {
+		return response.BadRequest(errors.New("A rebuild source can't be combined with the latest image"))
+	}
+
 	var targetProject *api.Project
 	var sourceImage *api.Image
 	var inst instance.Instance
 	var sourceImageRef string
+	var imageSource api.InstanceSource
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), targetProjectName)
 		if err != nil {
@@ -108,7 +114,25 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 			return fmt.Errorf("Failed loading instance: %w", err)
 		}
 
+		// Use the image alias the instance was built from.
+		if req.LatestImage {
+			instConfig, err := dbCluster.GetInstanceConfig(ctx, tx.Tx(), dbInst.ID)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance config: %w", err)
+			}
+
+			req.Source, err = imageSourceFromConfig(instConfig)
+			if err != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "%w", err)
+			}
+		}
+
+		imageSource = req.Source
+
 		if req.Source.Type != "none" {
+			// Apply the image alias namespaces of the project.
+			req.Source = project.ImageSourceRedirect(targetProject, req.Source)
+
 			sourceImage, err = getSourceImageFromInstanceSource(ctx, s, tx, targetProject.Name, req.Source, &sourceImageRef, dbInst.Type.String())
 			if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
 				return err
@@ -132,7 +156,12 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 
 	run := func(op *operations.Operation) error {
 		if req.Source.Type == "none" {
-			return instanceRebuildFromEmpty(inst, op)
+			err := instanceRebuildFromEmpty(inst, op)
+			if err != nil {
+				return err
+			}
+
+			return inst.VolatileSet(imageSourceConfig(imageSource))
 		}
 
 		if req.Source.Server != "" {
@@ -146,7 +175,18 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 			return errors.New("Image not provided for instance rebuild")
 		}
 
-		return instanceRebuildFromImage(context.TODO(), s, r, inst, sourceImage, op)
+		// Nothing to do if the instance already uses the latest image.
+		if req.LatestImage && sourceImage.Fingerprint == inst.LocalConfig()["volatile.base_image"] {
+			return nil
+		}
+
+		err := instanceRebuildFromImage(context.TODO(), s, r, inst, sourceImage, op)
+		if err != nil {
+			return err
+		}
+
+		// Track the image alias the instance was rebuilt from.
+		return inst.VolatileSet(imageSourceConfig(imageSource))
 	}
 
 	resources := map[string][]api.URL{}
@@ -657,11 +657,16 @@ func snapshotPost(s *state.State, r *http.Request, snapInst instance.Instance) r
 			}
 		}
 
-		ws, err := newMigrationSource(snapInst, reqNew.Live, true, false, "", "", req.Target)
+		ws, err := newMigrationSource(snapInst, reqNew.Live, true, false, "", "", req.Target, s.GlobalConfig.MigrationResumeTimeout(), s.GlobalConfig.MigrationStreams(), s.GlobalConfig.MigrationConcurrency(), s.GlobalConfig.MigrationRawTransport())
 		if err != nil {
 			return response.SmartError(err)
 		}
 
+		ws.timeouts, err = newMigrationTimeouts(s, req.Timeouts)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
 		resources := map[string][]api.URL{}
 		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", parentName)}
 		resources["instances_snapshots"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", parentName, "snapshots", snapName)}
@@ -100,6 +100,14 @@ var instanceSnapshotCmd = APIEndpoint{
 	Put:    APIEndpointAction{Handler: instanceSnapshotHandler, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanManageSnapshots, "name")},
 }
 
+var instanceSnapshotFileCmd = APIEndpoint{
+	Name: "instanceSnapshotFile",
+	Path: "instances/{name}/snapshots/{snapshotName}/files",
+
+	Get:  APIEndpointAction{Handler: instanceSnapshotFileHandler, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanAccessFiles, "name")},
+	Head: APIEndpointAction{Handler: instanceSnapshotFileHandler, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanAccessFiles, "name")},
+}
+
 var instanceConsoleCmd = APIEndpoint{
 	Name: "instanceConsole",
 	Path: "instances/{name}/console",
@@ -134,6 +142,22 @@ var instanceMetadataTemplatesCmd = APIEndpoint{
 	Delete: APIEndpointAction{Handler: instanceMetadataTemplatesDelete, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
 }
 
+var instanceAccessLinksCmd = APIEndpoint{
+	Name: "instanceAccessLinks",
+	Path: "instances/{name}/access-links",
+
+	Get:  APIEndpointAction{Handler: instanceAccessLinksGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+	Post: APIEndpointAction{Handler: instanceAccessLinksPost, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
+var instanceAccessLinkCmd = APIEndpoint{
+	Name: "instanceAccessLink",
+	Path: "instances/{name}/access-links/{id}",
+
+	Get:    APIEndpointAction{Handler: instanceAccessLinkGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+	Delete: APIEndpointAction{Handler: instanceAccessLinkDelete, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
 var instanceBackupsCmd = APIEndpoint{
 	Name: "instanceBackups",
 	Path: "instances/{name}/backups",
@@ -158,6 +182,21 @@ var instanceBackupExportCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: instanceBackupExportGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanManageBackups, "name")},
 }
 
+var instanceDiskUsageCmd = APIEndpoint{
+	Name: "instanceDiskUsage",
+	Path: "instances/{name}/disk-usage",
+
+	Get: APIEndpointAction{Handler: instanceDiskUsageGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
+}
+
+var instanceDeviceMediaCmd = APIEndpoint{
+	Name: "instanceDeviceMedia",
+	Path: "instances/{name}/devices/{device}/media",
+
+	Get:  APIEndpointAction{Handler: instanceDeviceMediaGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
+	Post: APIEndpointAction{Handler: instanceDeviceMediaPost, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
 var instanceAccessCmd = APIEndpoint{
 	Name: "access",
 	Path: "instances/{name}/access",
@@ -172,6 +211,34 @@ var instanceDebugMemoryCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: instanceDebugMemoryGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
 }
 
+var instanceDebugLXCConfigCmd = APIEndpoint{
+	Name: "instanceDebugLXCConfig",
+	Path: "instances/{name}/debug/lxc-config",
+
+	Get: APIEndpointAction{Handler: instanceDebugLXCConfigGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
+var instanceDebugQMPCmd = APIEndpoint{
+	Name: "instanceDebugQMP",
+	Path: "instances/{name}/debug/qmp",
+
+	Post: APIEndpointAction{Handler: instanceDebugQMPPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var instanceDebugNetnsCmd = APIEndpoint{
+	Name: "instanceDebugNetns",
+	Path: "instances/{name}/debug/netns",
+
+	Post: APIEndpointAction{Handler: instanceDebugNetnsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var instanceDebugProfileCmd = APIEndpoint{
+	Name: "instanceDebugProfile",
+	Path: "instances/{name}/debug/profile",
+
+	Post: APIEndpointAction{Handler: instanceDebugProfilePost, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
 type instanceAutostartList []instance.Instance
 
 func (slice instanceAutostartList) Len() int {
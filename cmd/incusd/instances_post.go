@@ -17,6 +17,7 @@ import (
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/cluster"
+	clusterConfig "github.com/lxc/incus/v6/internal/server/cluster/config"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
@@ -25,6 +26,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/instance/operationlock"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
@@ -311,6 +313,31 @@ func createFromMigration(ctx context.Context, s *state.State, r *http.Request, p
 		}
 	}
 
+	// Continue an interrupted migration into the instance from the data it kept.
+	var resumeState *migrationState
+	if clusterMoveSourceName == "" {
+		resumeState, err = loadMigrationState(projectName, req.Name)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	resuming := false
+	if inst == nil && resumeState != nil {
+		inst, err = resumeMigrationPartial(s, resumeState)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed resuming interrupted migration: %w", err))
+		}
+
+		if inst != nil {
+			resuming = true
+			req.Source.Refresh = true
+		}
+	}
+
+	// Keep the partially received data of new instances on failure for the migration to be continued.
+	keepPartial := resuming || (inst == nil && clusterMoveSourceName == "" && s.GlobalConfig.MigrationResumeTimeout() > 0)
+
 	reverter := revert.New()
 	defer reverter.Fail()
 
@@ -353,6 +380,11 @@ func createFromMigration(ctx context.Context, s *state.State, r *http.Request, p
 		}
 	}
 
+	timeouts, err := newMigrationTimeouts(s, req.Source.Timeouts)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	migrationArgs := migrationSinkArgs{
 		URL:                   req.Source.Operation,
 		Dialer:                dialer,
@@ -365,6 +397,12 @@ func createFromMigration(ctx context.Context, s *state.State, r *http.Request, p
 		Refresh:               req.Source.Refresh,
 		RefreshExcludeOlder:   req.Source.RefreshExcludeOlder,
 		StoragePool:           storagePool,
+		ResumeTimeout:         s.GlobalConfig.MigrationResumeTimeout(),
+		Streams:               s.GlobalConfig.MigrationStreams(),
+		RawTransport:          s.GlobalConfig.MigrationRawTransport(),
+		Verify:                req.Source.Verify,
+		Timeouts:              timeouts,
+		KeepPartial:           keepPartial,
 	}
 
 	// Check if the pool is changing at all.
@@ -388,17 +426,52 @@ func createFromMigration(ctx context.Context, s *state.State, r *http.Request, p
 
 		sink.instance.SetOperation(op)
 
+		// Record the migration so it can be continued if interrupted, including by a restart.
+		migState := &migrationState{
+			Operation: op.ID(),
+			Project:   projectName,
+			Instance:  inst.Name(),
+		}
+
+		if resumeState != nil && resumeState.Operation != migState.Operation {
+			_ = resumeState.remove()
+		}
+
+		if keepPartial {
+			err = migState.save()
+			if err != nil {
+				return err
+			}
+		}
+
 		// And finally run the migration.
 		err = sink.do(instOp)
 		if err != nil {
 			err = fmt.Errorf("Error transferring instance data: %w", err)
 			instOp.Done(err) // Complete operation that was created earlier, to release lock.
 
+			if keepPartial && !errors.Is(err, migration.ErrAborted) {
+				if keepMigrationPartial(s, inst, migState) {
+					runReverter.Success()
+					return fmt.Errorf("%w (partially received data kept for the migration to be continued)", err)
+				}
+			} else if resuming {
+				// The instance wasn't created by this request, so the reverter doesn't remove it.
+				_ = inst.Delete(true)
+			}
+
+			_ = migState.remove()
+
 			return err
 		}
 
 		instOp.Done(nil) // Complete operation that was created earlier, to release lock.
 
+		err = migState.remove()
+		if err != nil {
+			logger.Warn("Failed removing migration state", logger.Ctx{"project": projectName, "instance": inst.Name(), "err": err})
+		}
+
 		if migrationArgs.StoragePool != "" {
 			// Update root device for the instance if needed.
 			updateNeeded := false
@@ -465,14 +538,20 @@ func createFromMigration(ctx context.Context, s *state.State, r *http.Request, p
 	resources := map[string][]api.URL{}
 	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", req.Name)}
 
+	// Cancelling tells the source to stop, the partially received instance then gets removed.
+	cancel := func(op *operations.Operation) error {
+		sink.abort()
+		return nil
+	}
+
 	var op *operations.Operation
 	if push {
-		op, err = operations.OperationCreate(s, projectName, operations.OperationClassWebsocket, operationtype.InstanceCreate, resources, sink.Metadata(), run, nil, sink.Connect, r)
+		op, err = operations.OperationCreate(s, projectName, operations.OperationClassWebsocket, operationtype.InstanceCreate, resources, sink.Metadata(), run, cancel, sink.Connect, r)
 		if err != nil {
 			return response.InternalError(err)
 		}
 	} else {
-		op, err = operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.InstanceCreate, resources, nil, run, nil, nil, r)
+		op, err = operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.InstanceCreate, resources, nil, run, cancel, nil, r)
 		if err != nil {
 			return response.InternalError(err)
 		}
@@ -518,6 +597,10 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 			}
 
 			if sourcePoolName != destPoolName {
+				if req.Source.Instant {
+					return response.BadRequest(fmt.Errorf("Instant copies must use the storage pool %q of the source instance", sourcePoolName))
+				}
+
 				// Redirect to migration
 				return clusterCopyContainerInternal(ctx, s, r, source, projectName, profiles, req)
 			}
@@ -535,6 +618,10 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 			}
 
 			if !slices.Contains(db.StorageRemoteDriverNames(), pool.Driver) {
+				if req.Source.Instant {
+					return response.BadRequest(errors.New("Instant copies must be created on the cluster member of the source instance"))
+				}
+
 				// Redirect to migration
 				return clusterCopyContainerInternal(ctx, s, r, source, projectName, profiles, req)
 			}
@@ -577,6 +664,43 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 		req.Devices[key] = value
 	}
 
+	// The clone source is only ever recorded by the server, once the project restrictions were checked.
+	delete(req.Config, "volatile.clone.source")
+
+	// Instant copies are copy-on-write clones on the storage pool of the source.
+	if req.Source.Instant {
+		if req.Source.Refresh {
+			return response.BadRequest(errors.New("Instant copies can't be refreshed"))
+		}
+
+		_, sourceRootDevice, err := internalInstance.GetRootDiskDevice(source.ExpandedDevices().CloneNative())
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		poolName, _, _, _, resp := instanceFindStoragePool(r.Context(), s, targetProject, req)
+		if resp != nil {
+			return resp
+		}
+
+		if poolName != sourceRootDevice["pool"] {
+			return response.BadRequest(fmt.Errorf("Instant copies must use the storage pool %q of the source instance", sourceRootDevice["pool"]))
+		}
+
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if !pool.Driver().Info().InstantCopy {
+			return response.BadRequest(fmt.Errorf("Storage pool %q doesn't support instant copies", poolName))
+		}
+
+		// Clones never include the snapshots of the source and record what they depend on.
+		req.Source.InstanceOnly = true
+		req.Config["volatile.clone.source"] = fmt.Sprintf("%s/%s", source.Project().Name, source.Name())
+	}
+
 	if req.Stateful {
 		sourceName, _, _ := api.GetParentAndSnapshotName(source.Name())
 		if sourceName != req.Name {
@@ -622,6 +746,7 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 			refreshExcludeOlder:  req.Source.RefreshExcludeOlder,
 			applyTemplateTrigger: true,
 			allowInconsistent:    req.Source.AllowInconsistent,
+			instant:              req.Source.Instant,
 		}, op)
 		if err != nil {
 			return err
@@ -986,6 +1111,11 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Target only allowed when clustered"))
 	}
 
+	// Load the upstream image catalogs ahead of the transaction, as fetching them may take a while.
+	if req.Source.Type == "image" {
+		imageCacheLoad(r.Context(), s)
+	}
+
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), targetProjectName)
 		if err != nil {
@@ -1045,6 +1175,20 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			}
 
 		case "image":
+			// Track the image alias the instance is built from.
+			if req.Config == nil {
+				req.Config = map[string]string{}
+			}
+
+			for k, v := range imageSourceConfig(req.Source) {
+				if v != "" {
+					req.Config[k] = v
+				}
+			}
+
+			// Apply the image alias namespaces of the project.
+			req.Source = project.ImageSourceRedirect(targetProject, req.Source)
+
 			// Check if the image has an entry in the database but fail only if the error
 			// is different than the image not being found.
 			sourceImage, err = getSourceImageFromInstanceSource(ctx, s, tx, targetProject.Name, req.Source, &sourceImageRef, string(req.Type))
@@ -1052,6 +1196,11 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 				return err
 			}
 
+			// Fetch images which aren't available locally from the upstream image servers.
+			if sourceImage == nil {
+				req.Source = imageCacheRedirect(ctx, s, req.Source, string(req.Type))
+			}
+
 			// If image has an entry in the database then use its profiles if no override provided.
 			if sourceImage != nil && req.Profiles == nil {
 				req.Architecture = sourceImage.Architecture
@@ -1197,6 +1346,12 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			candidateMembers = []db.NodeInfo{*targetMemberInfo}
 		}
 
+		// Only keep the members on which the instance fits within the overcommit policy.
+		candidateMembers, err = instancesPostOvercommitFilter(r.Context(), s, *targetProject, profiles, req, candidateMembers)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
 		// Run instance placement scriptlet if enabled.
 		if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 			leaderAddress, err := s.Cluster.LeaderAddress()
@@ -1228,6 +1383,19 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		if targetMemberInfo == nil {
 			return response.InternalError(errors.New("Couldn't find a cluster member for the instance"))
 		}
+
+		// Qualify the name with the member in projects using member-scoped instance names.
+		if !req.Source.Refresh {
+			req.Name, err = project.InstanceMemberName(targetProject, req.Name, targetMemberInfo.Name)
+			if err != nil {
+				return response.BadRequest(err)
+			}
+
+			err = instance.ValidName(req.Name, false)
+			if err != nil {
+				return response.BadRequest(err)
+			}
+		}
 	}
 
 	// Record the cluster group as a volatile config key if present.
@@ -1254,6 +1422,10 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		return operations.ForwardedOperationResponse(targetProjectName, &opAPI)
 	}
 
+	if req.Source.Verify && req.Source.Type != "migration" {
+		return response.BadRequest(errors.New("Verification is only supported for migrations"))
+	}
+
 	switch req.Source.Type {
 	case "image":
 		return createFromImage(s, r, *targetProject, profiles, sourceImage, sourceImageRef, &req)
@@ -1268,6 +1440,98 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 	}
 }
 
+// instancesPostOvercommitFilter returns the candidate members on which the new instance can run without exceeding
+// the overcommit policy of the member and project. If the instance isn't going to be started and doesn't fit on
+// any member, all the candidates are returned.
+func instancesPostOvercommitFilter(ctx context.Context, s *state.State, p api.Project, profiles []api.Profile, req api.InstancesPost, candidateMembers []db.NodeInfo) ([]db.NodeInfo, error) {
+	if len(candidateMembers) == 0 {
+		return candidateMembers, nil
+	}
+
+	// Load the configuration effective on each candidate member.
+	memberConfigs := make(map[string]*clusterConfig.Config, len(candidateMembers))
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, member := range candidateMembers {
+			overrides, err := clusterConfig.MemberOverrides(ctx, tx, member)
+			if err != nil {
+				return err
+			}
+
+			memberConfigs[member.Name], err = s.GlobalConfig.ForMember(overrides)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load cluster member configuration: %w", err)
+	}
+
+	expandedConfig := db.ExpandInstanceConfig(req.Config, profiles)
+	expandedDevices := db.ExpandInstanceDevices(deviceConfig.NewDevices(req.Devices), profiles).CloneNative()
+
+	var lastErr error
+	fitMembers := make([]db.NodeInfo, 0, len(candidateMembers))
+	for _, member := range candidateMembers {
+		memberConfig := memberConfigs[member.Name]
+
+		policy := instance.GetOvercommitPolicy(memberConfig, p.Config)
+		if !policy.Enabled() {
+			fitMembers = append(fitMembers, member)
+			continue
+		}
+
+		client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to connect to cluster member: %w", err)
+		}
+
+		res, err := client.GetServerResources()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get resources for cluster member: %w", err)
+		}
+
+		requested, err := instance.InstanceAllocation(req.Type, expandedConfig, expandedDevices, res.Memory.Total)
+		if err != nil {
+			return nil, err
+		}
+
+		var committed instance.Allocation
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			committed, err = instance.CommittedAllocation(ctx, tx, member.Name, res.Memory.Total, "", "")
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting resources committed on cluster member %q: %w", member.Name, err)
+		}
+
+		err = policy.Check(instance.AvailableAllocation(res.CPU.Total, res.Memory.Total, memberConfig), committed, requested)
+		if err != nil {
+			logger.Debug("Skipping cluster member due to overcommit policy", logger.Ctx{"member": member.Name, "err": err})
+			lastErr = fmt.Errorf("Cluster member %q can't run the instance: %w", member.Name, err)
+			continue
+		}
+
+		fitMembers = append(fitMembers, member)
+	}
+
+	if len(fitMembers) == 0 {
+		if !req.Start {
+			return candidateMembers, nil
+		}
+
+		if len(candidateMembers) == 1 {
+			return nil, api.StatusErrorf(http.StatusConflict, "%v", lastErr)
+		}
+
+		return nil, api.StatusErrorf(http.StatusConflict, "No cluster member has enough capacity for the instance under the overcommit policy")
+	}
+
+	return fitMembers, nil
+}
+
 func instanceFindStoragePool(ctx context.Context, s *state.State, projectName string, req *api.InstancesPost) (string, string, string, map[string]string, response.Response) {
 	// Grab the container's root device if one is specified
 	storagePool := ""
@@ -13,13 +13,15 @@ import (
 
 	"github.com/lxc/incus/v6/internal/server/sys"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
 )
 
 type cmdDaemon struct {
 	global *cmdGlobal
 
 	// Common options
-	flagGroup string
+	flagGroup    string
+	flagRootless bool
 }
 
 func (c *cmdDaemon) command() *cobra.Command {
@@ -34,6 +36,7 @@ func (c *cmdDaemon) command() *cobra.Command {
 `
 	cmd.RunE = c.run
 	cmd.Flags().StringVar(&c.flagGroup, "group", "", "The group of users that will be allowed to talk to Incus"+"``")
+	cmd.Flags().BoolVar(&c.flagRootless, "rootless", false, "Run as an unprivileged user inside of a user namespace")
 
 	return cmd
 }
@@ -43,11 +46,28 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("unknown command \"%s\" for \"%s\"", args[0], cmd.CommandPath())
 	}
 
+	// Unprivileged users get re-executed inside of their own namespaces.
+	if c.flagRootless && os.Getenv("INCUS_ROOTLESS") == "" {
+		if os.Geteuid() == 0 {
+			return errors.New("Rootless mode can only be used by unprivileged users")
+		}
+
+		return c.runRootless()
+	}
+
 	// Only root should run this
 	if os.Geteuid() != 0 {
 		return errors.New("This must be run as root")
 	}
 
+	// Bring up the loopback device of the namespace set up for rootless mode.
+	if c.flagRootless {
+		_, err := subprocess.RunCommand("ip", "link", "set", "lo", "up")
+		if err != nil {
+			return fmt.Errorf("Failed bringing up the loopback device: %w", err)
+		}
+	}
+
 	neededPrograms := []string{"ip", "rsync", "setfattr", "tar", "unsquashfs", "xz"}
 	for _, p := range neededPrograms {
 		_, err := exec.LookPath(p)
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// rootlessDir returns the default data directory of a rootless daemon, following the XDG base directories.
+func rootlessDir() (string, error) {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		dataDir = filepath.Join(homeDir, ".local", "share")
+	}
+
+	return filepath.Join(dataDir, "incus"), nil
+}
+
+// runRootless re-executes the daemon as root of new user, mount and network namespaces.
+// The namespace gets mapped the subordinate IDs of the user, so that unprivileged containers can be created,
+// and is connected to the host network through slirp4netns when available.
+func (c *cmdDaemon) runRootless() error {
+	for _, p := range []string{"unshare", "newuidmap", "newgidmap"} {
+		_, err := exec.LookPath(p)
+		if err != nil {
+			return fmt.Errorf("Rootless mode requires %q: %w", p, err)
+		}
+	}
+
+	// Keep all the state of the daemon under the home directory of the user.
+	if os.Getenv("INCUS_DIR") == "" {
+		incusDir, err := rootlessDir()
+		if err != nil {
+			return fmt.Errorf("Failed getting the rootless data directory: %w", err)
+		}
+
+		err = os.Setenv("INCUS_DIR", incusDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := os.MkdirAll(os.Getenv("INCUS_DIR"), 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed creating %q: %w", os.Getenv("INCUS_DIR"), err)
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"--user", "--map-root-user", "--map-auto", "--mount", "--net", "--", execPath}, os.Args[1:]...)
+	cmd := exec.Command("unshare", args...)
+	cmd.Env = append(os.Environ(), "INCUS_ROOTLESS=1")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("Failed starting the rootless daemon: %w", err)
+	}
+
+	// Forward the termination signals to the daemon.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, unix.SIGPWR, unix.SIGINT, unix.SIGQUIT, unix.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	go func() {
+		for sig := range sigCh {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	// Give the namespaces outbound connectivity.
+	slirp, err := rootlessNetwork(cmd.Process.Pid)
+	if err != nil {
+		logger.Warn("Instances won't have network access outside of the host", logger.Ctx{"err": err})
+	}
+
+	err = cmd.Wait()
+
+	if slirp != nil {
+		_ = slirp.Process.Kill()
+		_ = slirp.Wait()
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+
+	return err
+}
+
+// rootlessNetwork connects the network namespace of the process to the host network through a slirp4netns
+// tap device, which becomes the default route of the namespace.
+func rootlessNetwork(pid int) (*exec.Cmd, error) {
+	_, err := exec.LookPath("slirp4netns")
+	if err != nil {
+		return nil, errors.New("slirp4netns couldn't be found")
+	}
+
+	// Wait for unshare to have set up the namespaces.
+	nsPath := fmt.Sprintf("/proc/%d/ns/net", pid)
+	hostNS, err := os.Readlink("/proc/self/ns/net")
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; ; i++ {
+		ns, err := os.Readlink(nsPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if ns != hostNS {
+			break
+		}
+
+		if i == 50 {
+			return nil, errors.New("Timed out waiting for the network namespace")
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	cmd := exec.Command("slirp4netns", "--configure", "--mtu=65520", "--disable-host-loopback", strconv.Itoa(pid), "tap0")
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("Failed starting slirp4netns: %w", err)
+	}
+
+	return cmd, nil
+}
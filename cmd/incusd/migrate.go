@@ -7,9 +7,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -20,8 +24,10 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance"
 	localMigration "github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/idmap"
+	"github.com/lxc/incus/v6/shared/logger"
 )
 
 type migrationFields struct {
@@ -29,6 +35,13 @@ type migrationFields struct {
 
 	conns map[string]*migrationConn
 
+	// aborted is set once either side cancelled the migration.
+	aborted atomic.Bool
+
+	// timeouts applying to the migration and timedOut set once it got aborted for taking too long.
+	timeouts migrationTimeouts
+	timedOut atomic.Bool
+
 	// container specific fields
 	live         bool
 	instanceOnly bool
@@ -38,6 +51,9 @@ type migrationFields struct {
 	volumeOnly        bool
 	allowInconsistent bool
 	storagePool       string
+
+	// verify enables the comparison of the checksums of the transferred volumes.
+	verify bool
 }
 
 func (c *migrationFields) send(m proto.Message) error {
@@ -60,10 +76,10 @@ func (c *migrationFields) send(m proto.Message) error {
 		return fmt.Errorf("Control connection not initialized: %w", err)
 	}
 
-	_ = conn.SetWriteDeadline(time.Now().Add(time.Second * 30))
+	_ = conn.SetWriteDeadline(time.Now().Add(c.timeouts.controlTimeout()))
 	err = migration.ProtoSend(conn, m)
 	if err != nil {
-		return err
+		return c.timeouts.controlError(err)
 	}
 
 	return nil
@@ -75,7 +91,17 @@ func (c *migrationFields) recv(m proto.Message) error {
 		return fmt.Errorf("Control connection not initialized: %w", err)
 	}
 
-	return migration.ProtoRecv(conn, m)
+	err = migration.ProtoRecv(conn, m)
+	if err != nil {
+		return err
+	}
+
+	msg, ok := m.(*migration.MigrationControl)
+	if ok && msg.GetAborted() {
+		c.aborted.Store(true)
+	}
+
+	return nil
 }
 
 func (c *migrationFields) disconnect() {
@@ -85,7 +111,7 @@ func (c *migrationFields) disconnect() {
 	conn, _ := c.conns[api.SecretNameControl].WebSocket(ctx)
 	if conn != nil {
 		closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
-		_ = conn.SetWriteDeadline(time.Now().Add(time.Second * 30))
+		_ = conn.SetWriteDeadline(time.Now().Add(c.timeouts.controlTimeout()))
 		_ = conn.WriteMessage(websocket.CloseMessage, closeMsg)
 	}
 
@@ -104,11 +130,35 @@ func (c *migrationFields) disconnect() {
 	}
 }
 
+// abort cancels the migration, telling the peer so that both sides roll back their changes.
+func (c *migrationFields) abort() {
+	c.aborted.Store(true)
+
+	c.controlLock.Lock()
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+	conn, _ := c.conns[api.SecretNameControl].WebSocket(ctx)
+	if conn != nil {
+		msg := migration.MigrationControl{
+			Success: proto.Bool(false),
+			Message: proto.String(localMigration.ErrAborted.Error()),
+			Aborted: proto.Bool(true),
+		}
+
+		_ = conn.SetWriteDeadline(time.Now().Add(c.timeouts.controlTimeout()))
+		_ = migration.ProtoSend(conn, &msg)
+	}
+
+	c.controlLock.Unlock()
+
+	c.disconnect()
+}
+
 func (c *migrationFields) sendControl(err error) {
 	c.controlLock.Lock()
 	conn, _ := c.conns[api.SecretNameControl].WebSocket(context.TODO())
 	if conn != nil {
-		_ = conn.SetWriteDeadline(time.Now().Add(time.Second * 10))
+		_ = conn.SetWriteDeadline(time.Now().Add(c.timeouts.controlTimeout()))
 		migration.ProtoSendControl(conn, err)
 	}
 
@@ -119,6 +169,54 @@ func (c *migrationFields) sendControl(err error) {
 	}
 }
 
+// startTimers starts sending keepalive pings on the control connection and aborts the migration once it
+// reaches its maximum duration. The returned function must be called once the migration is over.
+func (c *migrationFields) startTimers() func() {
+	done := make(chan struct{})
+
+	if c.timeouts.keepalive > 0 {
+		go func() {
+			ticker := time.NewTicker(c.timeouts.keepalive)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+				}
+
+				err := c.conns[api.SecretNameControl].Ping(time.Now().Add(c.timeouts.controlTimeout()))
+				if err != nil {
+					logger.Warn("Failed sending keepalive ping on migration control connection", logger.Ctx{"err": c.timeouts.controlError(err)})
+					return
+				}
+			}
+		}()
+	}
+
+	var timer *time.Timer
+	if c.timeouts.total > 0 {
+		timer = time.AfterFunc(c.timeouts.total, func() {
+			c.timedOut.Store(true)
+			c.abort()
+		})
+	}
+
+	return func() {
+		close(done)
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// timeoutError returns the error to report for a migration which got aborted for taking too long.
+func (c *migrationFields) timeoutError() error {
+	return fmt.Errorf("Migration timed out after %s", c.timeouts.total)
+}
+
 func (c *migrationFields) controlChannel() <-chan *localMigration.ControlResponse {
 	ch := make(chan *localMigration.ControlResponse)
 	go func() {
@@ -137,10 +235,130 @@ func (c *migrationFields) controlChannel() <-chan *localMigration.ControlRespons
 	return ch
 }
 
+// filesystemStreams returns the number of filesystem connections available, including the main one.
+func (c *migrationFields) filesystemStreams() int {
+	count := 1
+	for c.conns[api.SecretNameFilesystemStream(count)] != nil {
+		count++
+	}
+
+	return count
+}
+
+// filesystemStreamConns returns the additional filesystem connections, up to the negotiated count.
+func (c *migrationFields) filesystemStreamConns(ctx context.Context, count int) ([]io.ReadWriteCloser, error) {
+	conns := make([]io.ReadWriteCloser, 0, max(count-1, 0))
+	for i := 1; i < count; i++ {
+		conn := c.conns[api.SecretNameFilesystemStream(i)]
+		if conn == nil {
+			return nil, fmt.Errorf("Migration filesystem stream %d not initialized", i)
+		}
+
+		wsConn, err := conn.WebsocketIO(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting migration filesystem stream %d: %w", i, err)
+		}
+
+		conns = append(conns, wsConn)
+	}
+
+	return conns, nil
+}
+
+// migrationStreamSecretNames returns the secret names of the additional filesystem connections to setup.
+// When connecting to the peer, only the connections it offered in its secrets are used.
+func migrationStreamSecretNames(streams int, peerSecrets map[string]string, connect bool) []string {
+	names := make([]string, 0, max(streams-1, 0))
+	for i := 1; i < streams; i++ {
+		name := api.SecretNameFilesystemStream(i)
+		if connect && peerSecrets[name] == "" {
+			break
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// migrationTimeouts holds the timeouts of a migration.
+type migrationTimeouts struct {
+	// control is how long to wait for a message to be sent on the control connection.
+	control time.Duration
+
+	// keepalive is the interval of the pings sent on the control connection, none are sent if 0.
+	keepalive time.Duration
+
+	// total is the maximum duration of the migration, not limited if 0.
+	total time.Duration
+}
+
+// newMigrationTimeouts returns the migration timeouts configured on the server, overridden by those of the request.
+func newMigrationTimeouts(s *state.State, req *api.MigrationTimeouts) (migrationTimeouts, error) {
+	timeouts := migrationTimeouts{
+		control:   s.GlobalConfig.MigrationControlTimeout(),
+		keepalive: s.GlobalConfig.MigrationKeepaliveInterval(),
+		total:     s.GlobalConfig.MigrationTimeout(),
+	}
+
+	if req == nil {
+		return timeouts, nil
+	}
+
+	if req.Control < 0 || req.Keepalive < -1 || req.Total < -1 {
+		return migrationTimeouts{}, errors.New("Invalid migration timeouts")
+	}
+
+	if req.Control > 0 {
+		timeouts.control = time.Duration(req.Control) * time.Second
+	}
+
+	if req.Keepalive != 0 {
+		timeouts.keepalive = max(time.Duration(req.Keepalive)*time.Second, 0)
+	}
+
+	if req.Total != 0 {
+		timeouts.total = max(time.Duration(req.Total)*time.Second, 0)
+	}
+
+	return timeouts, nil
+}
+
+// controlTimeout returns how long to wait for a message to be sent on the control connection.
+func (t migrationTimeouts) controlTimeout() time.Duration {
+	if t.control <= 0 {
+		return time.Second * 30
+	}
+
+	return t.control
+}
+
+// controlError reports whether an error of the control connection was caused by its timeout.
+func (t migrationTimeouts) controlError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("Timed out after %s on migration control connection: %w", t.controlTimeout(), err)
+	}
+
+	return err
+}
+
+// migrationSourceAddress returns the address other cluster members connect to for a migration sourced from this member.
+// This is the dedicated migration address when configured and the member address otherwise.
+func migrationSourceAddress(s *state.State, memberAddress string) string {
+	migrationAddress := s.LocalConfig.MigrationAddress()
+	if migrationAddress != "" {
+		return migrationAddress
+	}
+
+	return memberAddress
+}
+
 type migrationSourceWs struct {
 	migrationFields
 
 	clusterMoveSourceName string
+	concurrency           int
 
 	pushCertificate  string
 	pushOperationURL string
@@ -188,6 +406,7 @@ type migrationSink struct {
 	clusterMoveSourceName string
 	refresh               bool
 	refreshExcludeOlder   bool
+	keepPartial           bool
 }
 
 // MigrationSinkArgs arguments to configure migration sink.
@@ -215,6 +434,12 @@ type migrationSinkArgs struct {
 
 	// Transport specific fields
 	RsyncFeatures []string
+	ResumeTimeout time.Duration
+	Streams       int
+	RawTransport  bool
+	Verify        bool
+	Timeouts      migrationTimeouts
+	KeepPartial   bool // Keep the partially received data on failure for the migration to be continued.
 }
 
 // Metadata returns metadata for the migration sink.
@@ -20,7 +20,10 @@ import (
 	"github.com/lxc/incus/v6/shared/logger"
 )
 
-func newMigrationSource(inst instance.Instance, stateful bool, instanceOnly bool, allowInconsistent bool, clusterMoveSourceName string, storagePool string, pushTarget *api.InstancePostTarget) (*migrationSourceWs, error) {
+// migrationQueue limits the number of migrations sent at the same time.
+var migrationQueue = operations.NewQueue()
+
+func newMigrationSource(inst instance.Instance, stateful bool, instanceOnly bool, allowInconsistent bool, clusterMoveSourceName string, storagePool string, pushTarget *api.InstancePostTarget, resumeTimeout time.Duration, streams int, concurrency int, rawTransport bool) (*migrationSourceWs, error) {
 	ret := migrationSourceWs{
 		migrationFields: migrationFields{
 			instance:          inst,
@@ -28,6 +31,7 @@ func newMigrationSource(inst instance.Instance, stateful bool, instanceOnly bool
 			storagePool:       storagePool,
 		},
 		clusterMoveSourceName: clusterMoveSourceName,
+		concurrency:           concurrency,
 	}
 
 	if pushTarget != nil {
@@ -51,6 +55,8 @@ func newMigrationSource(inst instance.Instance, stateful bool, instanceOnly bool
 		secretNames = append(secretNames, api.SecretNameState)
 	}
 
+	secretNames = append(secretNames, migrationStreamSecretNames(streams, ret.pushSecrets, ret.pushOperationURL != "")...)
+
 	ret.conns = make(map[string]*migrationConn, len(secretNames))
 	for _, connName := range secretNames {
 		if ret.pushOperationURL != "" {
@@ -68,24 +74,26 @@ func newMigrationSource(inst instance.Instance, stateful bool, instanceOnly bool
 				return nil, fmt.Errorf("Failed parsing websocket URL for migration source %q connection: %w", connName, err)
 			}
 
-			ret.conns[connName] = newMigrationConn(ret.pushSecrets[connName], dialer, u)
+			ret.conns[connName] = newMigrationConn(ret.pushSecrets[connName], dialer, u, resumeTimeout)
 		} else {
 			secret, err := internalUtil.RandomHexString(32)
 			if err != nil {
 				return nil, fmt.Errorf("Failed creating migration source secret for %q connection: %w", connName, err)
 			}
 
-			ret.conns[connName] = newMigrationConn(secret, nil, nil)
+			ret.conns[connName] = newMigrationConn(secret, nil, nil, resumeTimeout)
 		}
 	}
 
+	enableMigrationRawTransport(ret.conns, rawTransport)
+
 	return &ret, nil
 }
 
 func (s *migrationSourceWs) do(migrateOp *operations.Operation) error {
 	l := logger.AddContext(logger.Ctx{"project": s.instance.Project().Name, "instance": s.instance.Name(), "live": s.live, "clusterMoveSourceName": s.clusterMoveSourceName, "push": s.pushOperationURL != ""})
 
-	ctx, cancel := context.WithTimeout(context.TODO(), time.Second*30)
+	ctx, cancel := context.WithTimeout(context.TODO(), s.timeouts.controlTimeout())
 	defer cancel()
 
 	l.Debug("Waiting for migration control connection on source")
@@ -100,6 +108,19 @@ func (s *migrationSourceWs) do(migrateOp *operations.Operation) error {
 	defer l.Debug("Migration channels disconnected on source")
 	defer s.disconnect()
 
+	stopTimers := s.startTimers()
+	defer stopTimers()
+
+	// Wait for our turn if too many migrations are already running.
+	release, err := migrationQueue.Wait(migrateOp, s.concurrency)
+	if err != nil {
+		return fmt.Errorf("Failed waiting for migration slot on source: %w", err)
+	}
+
+	defer release()
+
+	migration.StartStatistics(migrateOp, s.instance.Project().Name, s.instance.Name(), s.instance.Type().String(), s.instance.Location(), s.live)
+
 	stateConnFunc := func(ctx context.Context) (io.ReadWriteCloser, error) {
 		conn := s.conns[api.SecretNameState]
 		if conn == nil {
@@ -146,17 +167,44 @@ func (s *migrationSourceWs) do(migrateOp *operations.Operation) error {
 			},
 			ClusterMoveSourceName: s.clusterMoveSourceName,
 			StoragePool:           s.storagePool,
+			FilesystemStreams:     s.filesystemStreams(),
+			FilesystemStreamConns: s.filesystemStreamConns,
+			Verify:                s.verify,
 		},
 		AllowInconsistent: s.allowInconsistent,
 	})
+	if err != nil && s.aborted.Load() {
+		abortErr := migration.ErrAborted
+		if s.timedOut.Load() {
+			abortErr = s.timeoutError()
+			l.Error("Failed migration on source", logger.Ctx{"err": abortErr})
+		} else {
+			l.Warn("Migration cancelled on source", logger.Ctx{"err": err})
+		}
+
+		// Make sure the instance isn't left frozen by the interrupted live migration.
+		if s.live && s.instance.IsFrozen() {
+			err = s.instance.Unfreeze()
+			if err != nil {
+				l.Error("Failed resuming instance after cancelled migration", logger.Ctx{"err": err})
+			}
+		}
+
+		migration.FinishStatistics(migrateOp, abortErr)
+		return abortErr
+	}
+
 	if err != nil {
 		l.Error("Failed migration on source", logger.Ctx{"err": err})
 
 		errMsg := fmt.Errorf("Failed migration on source: %w", err)
+		migration.FinishStatistics(migrateOp, errMsg)
 		s.sendControl(errMsg)
 		return errMsg
 	}
 
+	migration.FinishStatistics(migrateOp, nil)
+
 	return nil
 }
 
@@ -167,12 +215,15 @@ func newMigrationSink(args *migrationSinkArgs) (*migrationSink, error) {
 			instanceOnly: args.InstanceOnly,
 			live:         args.Live,
 			storagePool:  args.StoragePool,
+			verify:       args.Verify,
+			timeouts:     args.Timeouts,
 		},
 		url:                   args.URL,
 		clusterMoveSourceName: args.ClusterMoveSourceName,
 		push:                  args.Push,
 		refresh:               args.Refresh,
 		refreshExcludeOlder:   args.RefreshExcludeOlder,
+		keepPartial:           args.KeepPartial,
 	}
 
 	secretNames := []string{api.SecretNameControl, api.SecretNameFilesystem}
@@ -187,6 +238,8 @@ func newMigrationSink(args *migrationSinkArgs) (*migrationSink, error) {
 		secretNames = append(secretNames, api.SecretNameState)
 	}
 
+	secretNames = append(secretNames, migrationStreamSecretNames(args.Streams, args.Secrets, !sink.push)...)
+
 	sink.conns = make(map[string]*migrationConn, len(secretNames))
 	for _, connName := range secretNames {
 		if !sink.push {
@@ -199,24 +252,26 @@ func newMigrationSink(args *migrationSinkArgs) (*migrationSink, error) {
 				return nil, fmt.Errorf("Failed parsing websocket URL for migration sink %q connection: %w", connName, err)
 			}
 
-			sink.conns[connName] = newMigrationConn(args.Secrets[connName], args.Dialer, u)
+			sink.conns[connName] = newMigrationConn(args.Secrets[connName], args.Dialer, u, args.ResumeTimeout)
 		} else {
 			secret, err := internalUtil.RandomHexString(32)
 			if err != nil {
 				return nil, fmt.Errorf("Failed creating migration sink secret for %q connection: %w", connName, err)
 			}
 
-			sink.conns[connName] = newMigrationConn(secret, nil, nil)
+			sink.conns[connName] = newMigrationConn(secret, nil, nil, args.ResumeTimeout)
 		}
 	}
 
+	enableMigrationRawTransport(sink.conns, args.RawTransport)
+
 	return &sink, nil
 }
 
 func (c *migrationSink) do(instOp *operationlock.InstanceOperation) error {
 	l := logger.AddContext(logger.Ctx{"project": c.instance.Project().Name, "instance": c.instance.Name(), "live": c.live, "clusterMoveSourceName": c.clusterMoveSourceName, "push": c.push})
 
-	ctx, cancel := context.WithTimeout(context.TODO(), time.Second*30)
+	ctx, cancel := context.WithTimeout(context.TODO(), c.timeouts.controlTimeout())
 	defer cancel()
 
 	l.Debug("Waiting for migration control connection on target")
@@ -234,6 +289,9 @@ func (c *migrationSink) do(instOp *operationlock.InstanceOperation) error {
 		defer c.disconnect()
 	}
 
+	stopTimers := c.startTimers()
+	defer stopTimers()
+
 	stateConnFunc := func(ctx context.Context) (io.ReadWriteCloser, error) {
 		conn := c.conns[api.SecretNameState]
 		if conn == nil {
@@ -279,11 +337,26 @@ func (c *migrationSink) do(instOp *operationlock.InstanceOperation) error {
 			},
 			ClusterMoveSourceName: c.clusterMoveSourceName,
 			StoragePool:           c.storagePool,
+			FilesystemStreams:     c.filesystemStreams(),
+			FilesystemStreamConns: c.filesystemStreamConns,
+			Verify:                c.verify,
 		},
 		InstanceOperation:   instOp,
 		Refresh:             c.refresh,
 		RefreshExcludeOlder: c.refreshExcludeOlder,
+		KeepPartial:         c.keepPartial,
 	})
+	if err != nil && c.timedOut.Load() {
+		err = c.timeoutError()
+		l.Error("Failed migration on target", logger.Ctx{"err": err})
+		return err
+	}
+
+	if err != nil && c.aborted.Load() {
+		l.Warn("Migration cancelled on target", logger.Ctx{"err": err})
+		return migration.ErrAborted
+	}
+
 	if err != nil {
 		l.Error("Failed migration on target", logger.Ctx{"err": err})
 
@@ -24,7 +24,7 @@ import (
 	"github.com/lxc/incus/v6/shared/logger"
 )
 
-func newStorageMigrationSource(volumeOnly bool, pushTarget *api.StorageVolumePostTarget) (*migrationSourceWs, error) {
+func newStorageMigrationSource(volumeOnly bool, pushTarget *api.StorageVolumePostTarget, resumeTimeout time.Duration, streams int, rawTransport bool) (*migrationSourceWs, error) {
 	ret := migrationSourceWs{
 		migrationFields: migrationFields{},
 	}
@@ -38,6 +38,8 @@ func newStorageMigrationSource(volumeOnly bool, pushTarget *api.StorageVolumePos
 	ret.volumeOnly = volumeOnly
 
 	secretNames := []string{api.SecretNameControl, api.SecretNameFilesystem}
+	secretNames = append(secretNames, migrationStreamSecretNames(streams, ret.pushSecrets, ret.pushOperationURL != "")...)
+
 	ret.conns = make(map[string]*migrationConn, len(secretNames))
 	for _, connName := range secretNames {
 		if ret.pushOperationURL != "" {
@@ -55,24 +57,26 @@ func newStorageMigrationSource(volumeOnly bool, pushTarget *api.StorageVolumePos
 				return nil, fmt.Errorf("Failed parsing websocket URL for migration source %q connection: %w", connName, err)
 			}
 
-			ret.conns[connName] = newMigrationConn(ret.pushSecrets[connName], dialer, u)
+			ret.conns[connName] = newMigrationConn(ret.pushSecrets[connName], dialer, u, resumeTimeout)
 		} else {
 			secret, err := internalUtil.RandomHexString(32)
 			if err != nil {
 				return nil, fmt.Errorf("Failed creating migration source secret for %q connection: %w", connName, err)
 			}
 
-			ret.conns[connName] = newMigrationConn(secret, nil, nil)
+			ret.conns[connName] = newMigrationConn(secret, nil, nil, resumeTimeout)
 		}
 	}
 
+	enableMigrationRawTransport(ret.conns, rawTransport)
+
 	return &ret, nil
 }
 
-func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, poolName string, volName string, migrateOp *operations.Operation) error {
+func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, poolName string, volName string, migrateOp *operations.Operation) (retErr error) {
 	l := logger.AddContext(logger.Ctx{"project": projectName, "pool": poolName, "volume": volName, "push": s.pushOperationURL != ""})
 
-	ctx, cancel := context.WithTimeout(state.ShutdownCtx, time.Second*30)
+	ctx, cancel := context.WithTimeout(state.ShutdownCtx, s.timeouts.controlTimeout())
 	defer cancel()
 
 	l.Info("Waiting for migration connections on source")
@@ -89,6 +93,16 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 	defer l.Info("Migration channels disconnected on source")
 	defer s.disconnect()
 
+	stopTimers := s.startTimers()
+	defer stopTimers()
+
+	// Report the timeout rather than the error of the interrupted transfer.
+	defer func() {
+		if retErr != nil && s.timedOut.Load() {
+			retErr = s.timeoutError()
+		}
+	}()
+
 	var poolMigrationTypes []localMigration.Type
 
 	pool, err := storagePools.LoadByName(state, poolName)
@@ -153,6 +167,18 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 	offerHeader.IndexHeaderVersion = &indexHeaderVersion
 	offerHeader.VolumeSize = &volSize
 
+	// Offer to spread the transfer over additional connections.
+	streams := s.filesystemStreams()
+	if streams > 1 {
+		offerHeader.FilesystemStreams = proto.Uint32(uint32(streams))
+	}
+
+	// Offer to compress the transfer.
+	compression := state.GlobalConfig.MigrationCompression()
+	if compression != "" && compression != migration.CompressionNone {
+		offerHeader.Compression = &compression
+	}
+
 	// Only send snapshots when requested.
 	if !s.volumeOnly {
 		offerHeader.Snapshots = make([]*migration.Snapshot, 0, len(srcConfig.VolumeSnapshots))
@@ -227,6 +253,24 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 		return err
 	}
 
+	fsConn, err = localMigration.CompressConn(respHeader, fsConn)
+	if err != nil {
+		s.sendControl(err)
+		return err
+	}
+
+	volSourceArgs.Streams, err = s.filesystemStreamConns(state.ShutdownCtx, int(respHeader.GetFilesystemStreams()))
+	if err != nil {
+		s.sendControl(err)
+		return err
+	}
+
+	err = localMigration.CompressConns(respHeader, volSourceArgs.Streams)
+	if err != nil {
+		s.sendControl(err)
+		return err
+	}
+
 	err = pool.MigrateCustomVolume(projectName, fsConn, volSourceArgs, migrateOp)
 	if err != nil {
 		s.sendControl(err)
@@ -253,6 +297,7 @@ func newStorageMigrationSink(args *migrationSinkArgs) (*migrationSink, error) {
 	sink := migrationSink{
 		migrationFields: migrationFields{
 			volumeOnly: args.VolumeOnly,
+			timeouts:   args.Timeouts,
 		},
 		url:                 args.URL,
 		push:                args.Push,
@@ -261,6 +306,8 @@ func newStorageMigrationSink(args *migrationSinkArgs) (*migrationSink, error) {
 	}
 
 	secretNames := []string{api.SecretNameControl, api.SecretNameFilesystem}
+	secretNames = append(secretNames, migrationStreamSecretNames(args.Streams, args.Secrets, !sink.push)...)
+
 	sink.conns = make(map[string]*migrationConn, len(secretNames))
 	for _, connName := range secretNames {
 		if !sink.push {
@@ -273,24 +320,26 @@ func newStorageMigrationSink(args *migrationSinkArgs) (*migrationSink, error) {
 				return nil, fmt.Errorf("Failed parsing websocket URL for migration sink %q connection: %w", connName, err)
 			}
 
-			sink.conns[connName] = newMigrationConn(args.Secrets[connName], args.Dialer, u)
+			sink.conns[connName] = newMigrationConn(args.Secrets[connName], args.Dialer, u, args.ResumeTimeout)
 		} else {
 			secret, err := internalUtil.RandomHexString(32)
 			if err != nil {
 				return nil, fmt.Errorf("Failed creating migration sink secret for %q connection: %w", connName, err)
 			}
 
-			sink.conns[connName] = newMigrationConn(secret, nil, nil)
+			sink.conns[connName] = newMigrationConn(secret, nil, nil, args.ResumeTimeout)
 		}
 	}
 
+	enableMigrationRawTransport(sink.conns, args.RawTransport)
+
 	return &sink, nil
 }
 
-func (c *migrationSink) DoStorage(state *state.State, projectName string, poolName string, req *api.StorageVolumesPost, op *operations.Operation) error {
+func (c *migrationSink) DoStorage(state *state.State, projectName string, poolName string, req *api.StorageVolumesPost, op *operations.Operation) (retErr error) {
 	l := logger.AddContext(logger.Ctx{"project": projectName, "pool": poolName, "volume": req.Name, "push": c.push})
 
-	ctx, cancel := context.WithTimeout(state.ShutdownCtx, time.Second*30)
+	ctx, cancel := context.WithTimeout(state.ShutdownCtx, c.timeouts.controlTimeout())
 	defer cancel()
 
 	l.Info("Waiting for migration connections on target")
@@ -310,6 +359,16 @@ func (c *migrationSink) DoStorage(state *state.State, projectName string, poolNa
 		defer c.disconnect()
 	}
 
+	stopTimers := c.startTimers()
+	defer stopTimers()
+
+	// Report the timeout rather than the error of the interrupted transfer.
+	defer func() {
+		if retErr != nil && c.timedOut.Load() {
+			retErr = c.timeoutError()
+		}
+	}()
+
 	offerHeader := &migration.MigrationHeader{}
 	err := c.recv(offerHeader)
 	if err != nil {
@@ -390,6 +449,22 @@ func (c *migrationSink) DoStorage(state *state.State, projectName string, poolNa
 			}
 		}
 
+		var err error
+		volTargetArgs.Streams, err = c.filesystemStreamConns(state.ShutdownCtx, int(respHeader.GetFilesystemStreams()))
+		if err != nil {
+			return err
+		}
+
+		err = localMigration.CompressConns(respHeader, volTargetArgs.Streams)
+		if err != nil {
+			return err
+		}
+
+		conn, err = localMigration.CompressConn(respHeader, conn)
+		if err != nil {
+			return err
+		}
+
 		return pool.CreateCustomVolumeFromMigration(projectName, conn, volTargetArgs, op)
 	}
 
@@ -447,6 +522,9 @@ func (c *migrationSink) DoStorage(state *state.State, projectName string, poolNa
 		offerHeader.SnapshotNames = syncSnapshotNames
 	}
 
+	localMigration.NegotiateFilesystemStreams(offerHeader, respHeader, c.filesystemStreams())
+	localMigration.NegotiateCompression(offerHeader, respHeader, state.GlobalConfig.MigrationCompression())
+
 	err = c.send(respHeader)
 	if err != nil {
 		logger.Errorf("Failed to send storage volume migration header")
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"sync"
@@ -14,6 +15,8 @@ import (
 
 	"github.com/gorilla/websocket"
 
+	"github.com/lxc/incus/v6/internal/migration"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/tcp"
@@ -52,12 +55,23 @@ func setupWebsocketDialer(certificate string) (*websocket.Dialer, error) {
 	return dialer, nil
 }
 
+// migrationWebsocket is the subset of a websocket connection used by migrations.
+type migrationWebsocket interface {
+	migration.Conn
+
+	WriteMessage(messageType int, data []byte) error
+	SetWriteDeadline(t time.Time) error
+	RemoteAddr() net.Addr
+}
+
 // newMigrationConn configures a new migration connection handler.
-func newMigrationConn(secret string, outgoingDialer *websocket.Dialer, outgoingURL *url.URL) *migrationConn {
+// If resumeTimeout is set and supported by the peer, the connection survives transient network failures.
+func newMigrationConn(secret string, outgoingDialer *websocket.Dialer, outgoingURL *url.URL, resumeTimeout time.Duration) *migrationConn {
 	return &migrationConn{
 		secret:         secret,
 		outgoingDialer: outgoingDialer,
 		outgoingURL:    outgoingURL,
+		resumeTimeout:  resumeTimeout,
 		connected:      make(chan struct{}),
 	}
 }
@@ -68,11 +82,70 @@ type migrationConn struct {
 	secret         string
 	outgoingDialer *websocket.Dialer
 	outgoingURL    *url.URL
+	resumeTimeout  time.Duration
+	rawTransport   bool
 	conn           *websocket.Conn
+	resumable      *migration.ResumableConn
+	raw            net.Conn
+	rawIO          io.ReadWriteCloser
 	connected      chan struct{}
 	disconnected   bool
 }
 
+// enableMigrationRawTransport allows the data connections to carry their data over a raw TCP connection
+// instead of the websocket, if the peer also allows it.
+func enableMigrationRawTransport(conns map[string]*migrationConn, enabled bool) {
+	for connName, conn := range conns {
+		if connName != api.SecretNameControl {
+			conn.rawTransport = enabled
+		}
+	}
+}
+
+// setRaw records the raw TCP connection carrying the data.
+// Must be called with the lock held.
+func (c *migrationConn) setRaw(conn net.Conn) {
+	if conn == nil {
+		return
+	}
+
+	remoteTCP, _ := tcp.ExtractConn(conn)
+	if remoteTCP != nil {
+		err := tcp.SetTimeouts(remoteTCP, 0)
+		if err != nil {
+			logger.Warn("Failed setting TCP timeouts on raw migration connection", logger.Ctx{"err": err})
+		}
+	}
+
+	c.raw = conn
+	c.rawIO = migration.NewRawConn(conn)
+}
+
+// listenRaw opens a listener for the raw TCP connection on the address the request was received on.
+func (c *migrationConn) listenRaw(r *http.Request) (net.Listener, string, error) {
+	localAddr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	if !ok {
+		return nil, "", errors.New("Unknown local address")
+	}
+
+	host, _, err := net.SplitHostPort(localAddr.String())
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := internalUtil.RandomHexString(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return listener, token, nil
+}
+
 // Secret returns the secret for this connection.
 func (c *migrationConn) Secret() string {
 	return c.secret
@@ -80,25 +153,117 @@ func (c *migrationConn) Secret() string {
 
 // AcceptIncoming takes an incoming HTTP request and upgrades it to a websocket.
 func (c *migrationConn) AcceptIncoming(r *http.Request, w http.ResponseWriter) error {
+	resumable, conn, err := c.accept(r, w)
+	if err != nil {
+		return err
+	}
+
+	if resumable == nil {
+		return nil
+	}
+
+	// The handshake is done without the lock as the connection remains usable meanwhile.
+	err = resumable.Resume(conn)
+	if err != nil {
+		return fmt.Errorf("Failed resuming migration connection: %w", err)
+	}
+
+	logger.Info("Migration connection resumed", logger.Ctx{"remote": conn.RemoteAddr()})
+
+	return nil
+}
+
+// accept records the websocket of an incoming request.
+// If the request re-establishes a resumable connection, the resumable connection and the new websocket are
+// returned for the caller to complete the resume handshake.
+func (c *migrationConn) accept(r *http.Request, w http.ResponseWriter) (*migration.ResumableConn, *websocket.Conn, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.disconnected {
-		return errors.New("Connection already disconnected")
+		return nil, nil, errors.New("Connection already disconnected")
+	}
+
+	resume := c.resumeTimeout > 0 && r.Header.Get(migration.ResumeHeader) != ""
+
+	// Allow the peer to re-establish a resumable connection.
+	if c.resumable != nil {
+		if !resume {
+			return nil, nil, api.StatusErrorf(http.StatusConflict, "Connection already established")
+		}
+
+		conn, _, err := c.upgrade(r, w, true)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return c.resumable, conn, nil
 	}
 
 	if c.conn != nil {
-		return api.StatusErrorf(http.StatusConflict, "Connection already established")
+		return nil, nil, api.StatusErrorf(http.StatusConflict, "Connection already established")
 	}
 
-	var err error
-	c.conn, err = ws.Upgrader.Upgrade(w, r, nil)
+	conn, raw, err := c.upgrade(r, w, resume)
 	if err != nil {
-		return fmt.Errorf("Failed upgrading incoming request to websocket: %w", err)
+		return nil, nil, err
+	}
+
+	if resume {
+		c.resumable, err = migration.NewResumableConn(conn, c.resumeTimeout, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	c.setRaw(raw)
+	c.conn = conn
+	close(c.connected)
+
+	return nil, nil, nil
+}
+
+// upgrade upgrades an incoming request to a websocket, confirming support for resuming if requested.
+// If the raw TCP transport is requested and allowed, it also waits for the raw TCP connection and returns it.
+func (c *migrationConn) upgrade(r *http.Request, w http.ResponseWriter, resume bool) (*websocket.Conn, net.Conn, error) {
+	header := http.Header{}
+	if resume {
+		header.Set(migration.ResumeHeader, "1")
+	}
+
+	var rawListener net.Listener
+	var rawToken string
+	if !resume && c.rawTransport && r.Header.Get(migration.RawTransportHeader) != "" {
+		var err error
+
+		rawListener, rawToken, err = c.listenRaw(r)
+		if err != nil {
+			logger.Warn("Failed listening for raw migration connection, using websocket", logger.Ctx{"err": err})
+		} else {
+			defer func() { _ = rawListener.Close() }()
+
+			_, port, _ := net.SplitHostPort(rawListener.Addr().String())
+			header.Set(migration.RawTransportHeader, port)
+			header.Set(migration.RawTransportTokenHeader, rawToken)
+		}
+	}
+
+	conn, err := ws.Upgrader.Upgrade(w, r, header)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed upgrading incoming request to websocket: %w", err)
+	}
+
+	var raw net.Conn
+	if rawListener != nil {
+		raw, err = migration.AcceptRawTransport(rawListener, rawToken)
+		if err != nil {
+			_ = conn.Close()
+			return nil, nil, fmt.Errorf("Failed accepting raw migration connection: %w", err)
+		}
 	}
 
 	// Set TCP timeout options.
-	remoteTCP, _ := tcp.ExtractConn(c.conn.UnderlyingConn())
+	remoteTCP, _ := tcp.ExtractConn(conn.UnderlyingConn())
 	if remoteTCP != nil {
 		err = tcp.SetTimeouts(remoteTCP, 0)
 		if err != nil {
@@ -106,63 +271,190 @@ func (c *migrationConn) AcceptIncoming(r *http.Request, w http.ResponseWriter) e
 		}
 	}
 
-	close(c.connected)
+	return conn, raw, nil
+}
 
-	return nil
+// dial initiates a new outbound websocket, requesting support for resuming if enabled.
+// The raw TCP connection is returned too if the peer agreed on using it.
+// It doesn't modify the connection handler, so it is safe to call without the lock held.
+func (c *migrationConn) dial(ctx context.Context) (*websocket.Conn, net.Conn, bool, error) {
+	outgoingURL := *c.outgoingURL
+	q := outgoingURL.Query()
+	q.Set("secret", c.secret)
+	outgoingURL.RawQuery = q.Encode()
+
+	header := http.Header{}
+	if c.resumeTimeout > 0 {
+		header.Set(migration.ResumeHeader, "1")
+	}
+
+	if c.rawTransport {
+		header.Set(migration.RawTransportHeader, "1")
+	}
+
+	conn, resp, err := c.outgoingDialer.DialContext(ctx, outgoingURL.String(), header)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	resume := c.resumeTimeout > 0 && resp.Header.Get(migration.ResumeHeader) != ""
+
+	var raw net.Conn
+	rawPort := resp.Header.Get(migration.RawTransportHeader)
+	if !resume && c.rawTransport && rawPort != "" {
+		host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+		if err != nil {
+			_ = conn.Close()
+			return nil, nil, false, err
+		}
+
+		raw, err = migration.DialRawTransport(net.JoinHostPort(host, rawPort), resp.Header.Get(migration.RawTransportTokenHeader))
+		if err != nil {
+			_ = conn.Close()
+			return nil, nil, false, fmt.Errorf("Failed connecting raw migration connection: %w", err)
+		}
+	}
+
+	if resume {
+		// Detect the loss of the connection quickly so it can be resumed.
+		remoteTCP, _ := tcp.ExtractConn(conn.UnderlyingConn())
+		if remoteTCP != nil {
+			err = tcp.SetTimeouts(remoteTCP, 0)
+			if err != nil {
+				logger.Warn("Failed setting TCP timeouts on outgoing websocket connection", logger.Ctx{"err": err})
+			}
+		}
+	}
+
+	return conn, raw, resume, nil
+}
+
+// redial re-establishes a lost resumable outbound connection.
+func (c *migrationConn) redial(ctx context.Context) (*websocket.Conn, error) {
+	conn, _, resume, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resume {
+		_ = conn.Close()
+		return nil, errors.New("Migration peer doesn't support resuming the connection")
+	}
+
+	return conn, nil
 }
 
 // WebSocket returns the underlying websocket connection.
 // If the connection isn't yet active it will either wait for an incoming connection or if configured, will attempt
 // to initiate a new outbound connection. If the context is cancelled before the connection is established it
 // will return with an error.
-func (c *migrationConn) WebSocket(ctx context.Context) (*websocket.Conn, error) {
+func (c *migrationConn) WebSocket(ctx context.Context) (migrationWebsocket, error) {
+	if c.outgoingURL != nil && c.outgoingDialer != nil {
+		return c.connect(ctx)
+	}
+
+	// Wait for AcceptIncoming to hand over the incoming connection.
+	select {
+	case <-c.connected:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
 	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.disconnected {
+		return nil, errors.New("Connection already disconnected")
+	}
+
+	return c.websocket(), nil
+}
+
+// connect returns the outbound connection, initiating it if not yet established.
+// The lock is held for the whole dial so that concurrent callers share a single connection.
+func (c *migrationConn) connect(ctx context.Context) (migrationWebsocket, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	if c.disconnected {
-		c.mu.Unlock()
 		return nil, errors.New("Connection already disconnected")
 	}
 
 	if c.conn != nil {
-		c.mu.Unlock()
-		return c.conn, nil
+		return c.websocket(), nil
 	}
 
-	if c.outgoingURL != nil && c.outgoingDialer != nil {
-		var err error
-		q := c.outgoingURL.Query()
-		q.Set("secret", c.secret)
-		c.outgoingURL.RawQuery = q.Encode()
-		c.conn, _, err = c.outgoingDialer.DialContext(ctx, c.outgoingURL.String(), http.Header{})
+	conn, raw, resume, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if resume {
+		c.resumable, err = migration.NewResumableConn(conn, c.resumeTimeout, c.redial)
 		if err != nil {
-			c.mu.Unlock()
+			_ = conn.Close()
 			return nil, err
 		}
-
-		c.mu.Unlock()
-		return c.conn, nil
 	}
 
-	c.mu.Unlock()
+	c.setRaw(raw)
+	c.conn = conn
 
-	select {
-	case <-c.connected:
-		return c.conn, nil
-	case <-ctx.Done():
-		return nil, ctx.Err()
+	return c.websocket(), nil
+}
+
+// websocket returns the resumable connection if in use or the websocket otherwise.
+// Must be called with the lock held.
+func (c *migrationConn) websocket() migrationWebsocket {
+	if c.resumable != nil {
+		return c.resumable
+	}
+
+	if c.conn == nil {
+		return nil
 	}
+
+	return c.conn
 }
 
 // WebsocketIO calls WebSocket and returns it wrapped for io.ReadWriteCloser compatibility.
+// If the data is carried over a raw TCP connection, that connection is returned instead.
 func (c *migrationConn) WebsocketIO(ctx context.Context) (io.ReadWriteCloser, error) {
 	wsConn, err := c.WebSocket(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	c.mu.Lock()
+	rawIO := c.rawIO
+	c.mu.Unlock()
+
+	if rawIO != nil {
+		return rawIO, nil
+	}
+
 	return ws.NewWrapper(wsConn), nil
 }
 
+// Ping sends a ping on the websocket to keep it alive through idle periods.
+// Resumable connections are skipped as they already recover from lost connections.
+func (c *migrationConn) Ping(deadline time.Time) error {
+	c.mu.Lock()
+	conn := c.conn
+	if c.disconnected || c.resumable != nil {
+		conn = nil
+	}
+
+	c.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	// Control messages can be written concurrently with the other messages.
+	return conn.WriteControl(websocket.PingMessage, nil, deadline)
+}
+
 // Close closes the connection (if established) and marks it as disconnected so that it cannot be used again.
 func (c *migrationConn) Close() {
 	c.mu.Lock()
@@ -170,6 +462,17 @@ func (c *migrationConn) Close() {
 
 	c.disconnected = true
 
+	if c.resumable != nil {
+		_ = c.resumable.Close()
+		c.resumable = nil
+	}
+
+	if c.raw != nil {
+		_ = c.raw.Close()
+		c.raw = nil
+		c.rawIO = nil
+	}
+
 	if c.conn != nil {
 		c.conn.Close()
 		c.conn = nil
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/ws"
+)
+
+var migrationRelaysCmd = APIEndpoint{
+	Path: "migration-relays",
+
+	Post: APIEndpointAction{Handler: migrationRelaysPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// migrationRelayPeer connects to the migration websockets of one of the servers taking part in a relayed migration.
+type migrationRelayPeer struct {
+	endpoint api.MigrationRelayEndpoint
+	dialer   *websocket.Dialer
+
+	// address is the address which worked for the first connection and is used for the following ones.
+	address string
+}
+
+// connect opens the named migration websocket of the peer.
+func (p *migrationRelayPeer) connect(name string) (*websocket.Conn, error) {
+	addresses := p.endpoint.Addresses
+	if p.address != "" {
+		addresses = []string{p.address}
+	}
+
+	if len(addresses) == 0 {
+		return nil, errors.New("No address provided")
+	}
+
+	var errs []error
+	for _, address := range addresses {
+		u, err := url.Parse(fmt.Sprintf("wss://%s/1.0/operations/%s/websocket", strings.TrimPrefix(address, "https://"), url.PathEscape(p.endpoint.Operation)))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		q := u.Query()
+		q.Set("secret", p.endpoint.Websockets[name])
+		u.RawQuery = q.Encode()
+
+		conn, _, err := p.dialer.Dial(u.String(), nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", address, err))
+			continue
+		}
+
+		p.address = address
+
+		return conn, nil
+	}
+
+	return nil, fmt.Errorf("Failed connecting %q websocket: %w", name, errors.Join(errs...))
+}
+
+// swagger:operation POST /1.0/migration-relays migration-relays migration_relays_post
+//
+//	Relay a migration
+//
+//	Connects to the migration websockets of both the source and the target
+//	servers and proxies the data between them.
+//	This allows migrating instances between servers which can't reach each other
+//	as long as both can be reached by this server.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: relay
+//	    description: Migration relay request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/MigrationRelayPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func migrationRelaysPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.MigrationRelayPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Source.Websockets[api.SecretNameControl] == "" || req.Target.Websockets[api.SecretNameControl] == "" {
+		return response.BadRequest(errors.New("Both the source and the target must provide the control websocket"))
+	}
+
+	for name := range req.Target.Websockets {
+		// Additional filesystem connections are optional and only used if offered by both sides.
+		if name != api.SecretNameFilesystem && strings.HasPrefix(name, api.SecretNameFilesystem) {
+			continue
+		}
+
+		if req.Source.Websockets[name] == "" {
+			return response.BadRequest(fmt.Errorf("Migration target expects the %q websocket but source isn't providing it", name))
+		}
+	}
+
+	peers := make([]*migrationRelayPeer, 0, 2)
+	for _, endpoint := range []api.MigrationRelayEndpoint{req.Source, req.Target} {
+		dialer, err := setupWebsocketDialer(endpoint.Certificate)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		peers = append(peers, &migrationRelayPeer{endpoint: endpoint, dialer: dialer})
+	}
+
+	source, target := peers[0], peers[1]
+
+	// Connect the control websockets right away so that unreachable servers are reported to the client.
+	sourceControl, err := source.connect(api.SecretNameControl)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed connecting to the migration source: %w", err))
+	}
+
+	targetControl, err := target.connect(api.SecretNameControl)
+	if err != nil {
+		_ = sourceControl.Close()
+		return response.SmartError(fmt.Errorf("Failed connecting to the migration target: %w", err))
+	}
+
+	run := func(op *operations.Operation) error {
+		l := logger.AddContext(logger.Ctx{"source": source.address, "target": target.address})
+		l.Info("Relaying migration")
+
+		controlDone := ws.Proxy(sourceControl, targetControl)
+
+		type proxy struct {
+			done       chan struct{}
+			sourceConn *websocket.Conn
+			targetConn *websocket.Conn
+		}
+
+		proxies := []proxy{}
+
+		// Connect the data websockets.
+		for _, name := range slices.Sorted(maps.Keys(req.Source.Websockets)) {
+			// Skip connections the target doesn't expect.
+			if name == api.SecretNameControl || req.Target.Websockets[name] == "" {
+				continue
+			}
+
+			sourceConn, err := source.connect(name)
+			if err != nil {
+				l.Warn("Failed connecting to the migration source", logger.Ctx{"err": err})
+				break
+			}
+
+			targetConn, err := target.connect(name)
+			if err != nil {
+				_ = sourceConn.Close()
+				l.Warn("Failed connecting to the migration target", logger.Ctx{"err": err})
+				break
+			}
+
+			proxies = append(proxies, proxy{done: ws.Proxy(sourceConn, targetConn), sourceConn: sourceConn, targetConn: targetConn})
+		}
+
+		// The migration is over once the control connection ends.
+		<-controlDone
+		_ = sourceControl.Close()
+		_ = targetControl.Close()
+
+		for _, proxy := range proxies {
+			<-proxy.done
+			_ = proxy.sourceConn.Close()
+			_ = proxy.targetConn.Close()
+		}
+
+		l.Info("Relayed migration finished")
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.MigrationRelay, nil, nil, run, nil, nil, r)
+	if err != nil {
+		_ = sourceControl.Close()
+		_ = targetControl.Close()
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// migrationState records the progress of a migration into a new instance.
+// It is kept on disk while the migration runs and after it got interrupted, so that a later migration of the
+// same instance continues from the data already received, including after a restart of the server.
+type migrationState struct {
+	Operation string    `json:"operation"`
+	Project   string    `json:"project"`
+	Instance  string    `json:"instance"`
+	Snapshots []string  `json:"snapshots"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// migrationStatePath returns the path of the state file of a migration operation.
+func migrationStatePath(operation string) string {
+	return internalUtil.VarPath("migrations", fmt.Sprintf("%s.json", operation))
+}
+
+// save writes the migration state to disk.
+func (m *migrationState) save() error {
+	m.UpdatedAt = time.Now().UTC()
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(migrationStatePath(m.Operation), data, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed writing migration state: %w", err)
+	}
+
+	return nil
+}
+
+// remove deletes the migration state from disk.
+func (m *migrationState) remove() error {
+	err := os.Remove(migrationStatePath(m.Operation))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed removing migration state: %w", err)
+	}
+
+	return nil
+}
+
+// loadMigrationState returns the state of an unfinished migration into the instance, or nil if there is none.
+func loadMigrationState(projectName string, instanceName string) (*migrationState, error) {
+	entries, err := os.ReadDir(internalUtil.VarPath("migrations"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(internalUtil.VarPath("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m := &migrationState{}
+		err = json.Unmarshal(data, m)
+		if err != nil {
+			logger.Warn("Ignoring invalid migration state", logger.Ctx{"file": entry.Name(), "err": err})
+			continue
+		}
+
+		if m.Project == projectName && m.Instance == instanceName {
+			return m, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// pruneMigrationPartial removes the snapshots of an interrupted migration which weren't received on storage.
+// It returns the names of the received snapshots, and false if the instance volume itself doesn't exist on
+// storage, in which case there is nothing to continue from.
+func pruneMigrationPartial(s *state.State, inst instance.Instance) ([]string, bool, error) {
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return nil, false, err
+	}
+
+	volType, err := storagePools.InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return nil, false, err
+	}
+
+	contentType := storagePools.InstanceContentType(inst)
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+
+	volExists, err := pool.Driver().HasVolume(pool.GetVolume(volType, contentType, volStorageName, nil))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !volExists {
+		return nil, false, nil
+	}
+
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return nil, false, err
+	}
+
+	received := make([]string, 0, len(snapshots))
+	for _, snap := range snapshots {
+		_, snapName, _ := api.GetParentAndSnapshotName(snap.Name())
+
+		snapVol := pool.GetVolume(volType, contentType, storageDrivers.GetSnapshotVolumeName(volStorageName, snapName), nil)
+		snapExists, err := pool.Driver().HasVolume(snapVol)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !snapExists {
+			err = snap.Delete(true)
+			if err != nil {
+				return nil, false, fmt.Errorf("Failed deleting snapshot %q which wasn't received: %w", snapName, err)
+			}
+
+			continue
+		}
+
+		received = append(received, snapName)
+	}
+
+	return received, true, nil
+}
+
+// resumeMigrationPartial loads the instance of an interrupted migration to continue it.
+// If there is nothing to continue from, the leftovers are removed and nil is returned.
+func resumeMigrationPartial(s *state.State, m *migrationState) (instance.Instance, error) {
+	inst, err := instance.LoadByProjectAndName(s, m.Project, m.Instance)
+	if err != nil {
+		if !response.IsNotFoundError(err) {
+			return nil, err
+		}
+
+		return nil, m.remove()
+	}
+
+	_, kept, err := pruneMigrationPartial(s, inst)
+	if err != nil {
+		return nil, err
+	}
+
+	if !kept {
+		err = inst.Delete(true)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, m.remove()
+	}
+
+	return inst, nil
+}
+
+// keepMigrationPartial records what a failed migration received so that it can be continued later.
+// It returns false if nothing can be continued, in which case the partially created instance is deleted.
+func keepMigrationPartial(s *state.State, inst instance.Instance, m *migrationState) bool {
+	snapshots, kept, err := pruneMigrationPartial(s, inst)
+	if err == nil && kept {
+		m.Snapshots = snapshots
+
+		err = m.save()
+		if err == nil {
+			return true
+		}
+	}
+
+	if err != nil {
+		logger.Warn("Failed keeping partially migrated instance", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+	}
+
+	err = inst.Delete(true)
+	if err != nil {
+		logger.Warn("Failed deleting partially migrated instance", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+	}
+
+	_ = m.remove()
+
+	return false
+}
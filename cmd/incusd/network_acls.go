@@ -392,6 +392,13 @@ func networkACLDelete(d *Daemon, r *http.Request) response.Response {
 		logger.Error("Failed to remove network ACL from authorizer", logger.Ctx{"name": aclName, "project": projectName, "error": err})
 	}
 
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteConfigRevisions(ctx, projectName, db.ConfigRevisionTypeNetworkACL, aclName)
+	})
+	if err != nil {
+		logger.Error("Failed to remove network ACL configuration revisions", logger.Ctx{"name": aclName, "project": projectName, "error": err})
+	}
+
 	s.Events.SendLifecycle(projectName, lifecycle.NetworkACLDeleted.Event(netACL, request.CreateRequestor(r), nil))
 
 	return response.EmptySyncResponse
@@ -579,11 +586,17 @@ func networkACLPut(d *Daemon, r *http.Request) response.Response {
 
 	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
 
+	oldACL := netACL.Info().NetworkACLPut
+
 	err = netACL.Update(&req, clientType)
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	if clientType == clusterRequest.ClientTypeNormal {
+		configRevisionRecord(s, r, projectName, db.ConfigRevisionTypeNetworkACL, aclName, oldACL, req)
+	}
+
 	s.Events.SendLifecycle(projectName, lifecycle.NetworkACLUpdated.Event(netACL, request.CreateRequestor(r), nil))
 
 	return response.EmptySyncResponse
@@ -658,6 +671,13 @@ func networkACLPost(d *Daemon, r *http.Request) response.Response {
 		logger.Error("Failed to rename network ACL in authorizer", logger.Ctx{"old_name": aclName, "new_name": req.Name, "project": projectName, "error": err})
 	}
 
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.RenameConfigRevisions(ctx, projectName, db.ConfigRevisionTypeNetworkACL, aclName, req.Name)
+	})
+	if err != nil {
+		logger.Error("Failed to rename network ACL configuration revisions", logger.Ctx{"old_name": aclName, "new_name": req.Name, "project": projectName, "error": err})
+	}
+
 	lc := lifecycle.NetworkACLRenamed.Event(netACL, request.CreateRequestor(r), logger.Ctx{"old_name": aclName})
 	s.Events.SendLifecycle(projectName, lc)
 
@@ -1137,8 +1137,11 @@ func networkDelete(d *Daemon, r *http.Request) response.Response {
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Remove the network from the database.
 		err = tx.DeleteNetwork(ctx, n.Project(), n.Name())
+		if err != nil {
+			return err
+		}
 
-		return err
+		return tx.DeleteConfigRevisions(ctx, n.Project(), db.ConfigRevisionTypeNetwork, n.Name())
 	})
 	if err != nil {
 		return response.SmartError(err)
@@ -1281,6 +1284,13 @@ func networkPost(d *Daemon, r *http.Request) response.Response {
 		logger.Error("Failed to rename network in authorizer", logger.Ctx{"old_name": networkName, "new_name": req.Name, "project": projectName, "error": err})
 	}
 
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.RenameConfigRevisions(ctx, projectName, db.ConfigRevisionTypeNetwork, networkName, req.Name)
+	})
+	if err != nil {
+		logger.Error("Failed to rename network configuration revisions", logger.Ctx{"old_name": networkName, "new_name": req.Name, "project": projectName, "error": err})
+	}
+
 	requestor := request.CreateRequestor(r)
 	lc := lifecycle.NetworkRenamed.Event(n, requestor, map[string]any{"old_name": networkName})
 	s.Events.SendLifecycle(projectName, lc)
@@ -1413,7 +1423,12 @@ func networkPut(d *Daemon, r *http.Request) response.Response {
 
 	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
 
+	oldNetwork := networkRevisionConfig(n, s.ServerClustered)
+
 	resp = doNetworkUpdate(n, req, targetNode, clientType, r.Method, s.ServerClustered)
+	if resp == response.EmptySyncResponse && targetNode == "" && !isClusterNotification(r) {
+		configRevisionRecord(s, r, projectName, db.ConfigRevisionTypeNetwork, networkName, oldNetwork, networkRevisionConfig(n, s.ServerClustered))
+	}
 
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(projectName, lifecycle.NetworkUpdated.Event(n, requestor, nil))
@@ -601,6 +601,9 @@ func profilePut(d *Daemon, r *http.Request) response.Response {
 	}
 
 	err = doProfileUpdate(r.Context(), s, *p, name, profile, req)
+	if err == nil {
+		configRevisionRecord(s, r, p.Name, db.ConfigRevisionTypeProfile, name, profile.ProfilePut, req)
+	}
 
 	if err == nil && !isClusterNotification(r) {
 		// Notify all other nodes. If a node is down, it will be ignored.
@@ -749,7 +752,14 @@ func profilePatch(d *Daemon, r *http.Request) response.Response {
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(p.Name, lifecycle.ProfileUpdated.Event(name, p.Name, requestor, nil))
 
-	return response.SmartError(doProfileUpdate(r.Context(), s, *p, name, profile, req))
+	err = doProfileUpdate(r.Context(), s, *p, name, profile, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	configRevisionRecord(s, r, p.Name, db.ConfigRevisionTypeProfile, name, profile.ProfilePut, req)
+
+	return response.EmptySyncResponse
 }
 
 // swagger:operation POST /1.0/profiles/{name} profiles profile_post
@@ -833,7 +843,12 @@ func profilePost(d *Daemon, r *http.Request) response.Response {
 			return fmt.Errorf("Profile %q already exists", req.Name)
 		}
 
-		return dbCluster.RenameProfile(ctx, tx.Tx(), p.Name, name, req.Name)
+		err = dbCluster.RenameProfile(ctx, tx.Tx(), p.Name, name, req.Name)
+		if err != nil {
+			return err
+		}
+
+		return tx.RenameConfigRevisions(ctx, p.Name, db.ConfigRevisionTypeProfile, name, req.Name)
 	})
 	if err != nil {
 		return response.SmartError(err)
@@ -907,7 +922,12 @@ func profileDelete(d *Daemon, r *http.Request) response.Response {
 			return errors.New("Profile is currently in use")
 		}
 
-		return dbCluster.DeleteProfile(ctx, tx.Tx(), p.Name, name)
+		err = dbCluster.DeleteProfile(ctx, tx.Tx(), p.Name, name)
+		if err != nil {
+			return err
+		}
+
+		return tx.DeleteConfigRevisions(ctx, p.Name, db.ConfigRevisionTypeProfile, name)
 	})
 	if err != nil {
 		return response.SmartError(err)
@@ -0,0 +1,761 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	projecthelpers "github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/osarch"
+)
+
+var reportsCmd = APIEndpoint{
+	Path: "reports",
+
+	Get:  APIEndpointAction{Handler: reportsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewResources)},
+	Post: APIEndpointAction{Handler: reportsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var reportCmd = APIEndpoint{
+	Path: "reports/{name}",
+
+	Delete: APIEndpointAction{Handler: reportDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: reportGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewResources)},
+}
+
+// swagger:operation GET /1.0/reports reports reports_get
+//
+//	Get the reports
+//
+//	Returns a list of generated reports (URLs), from the oldest to the most recent.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/reports/capacity-20261014-120000",
+//	              "/1.0/reports/inventory-20261014-120000"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/reports?recursion=1 reports reports_get_recursion1
+//
+//	Get the reports
+//
+//	Returns a list of generated reports (structs), from the oldest to the most recent.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of reports
+//	          items:
+//	            $ref: "#/definitions/Report"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func reportsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var names []string
+	var reports []api.Report
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		if recursion {
+			reports, err = tx.GetReports(ctx)
+		} else {
+			names, err = tx.GetReportNames(ctx)
+		}
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !recursion {
+		urls := make([]string, 0, len(names))
+		for _, name := range names {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "reports", name).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	if reports == nil {
+		reports = []api.Report{}
+	}
+
+	return response.SyncResponse(true, reports)
+}
+
+// swagger:operation POST /1.0/reports reports reports_post
+//
+//	Generate a report
+//
+//	Generates a new capacity or inventory report.
+//	The report is pushed to the URL set in `reports.webhook` once generated.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: report
+//	    description: Report request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ReportsPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func reportsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.ReportsPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !slices.Contains([]string{api.ReportTypeCapacity, api.ReportTypeInventory}, req.Type) {
+		return response.BadRequest(fmt.Errorf("Invalid report type %q", req.Type))
+	}
+
+	createdAt := time.Now()
+	name := reportName(req.Type, createdAt)
+
+	run := func(op *operations.Operation) error {
+		return reportGenerate(context.TODO(), s, req.Type, createdAt)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["reports"] = []api.URL{*api.NewURL().Path(version.APIVersion, "reports", name)}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ReportGenerate, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation GET /1.0/reports/{name} reports report_get
+//
+//	Get the report
+//
+//	Gets a specific report, either as JSON or, one section at a time, as CSV.
+//
+//	---
+//	produces:
+//	  - application/json
+//	  - text/csv
+//	parameters:
+//	  - in: query
+//	    name: format
+//	    description: Format of the report (json or csv)
+//	    type: string
+//	    example: csv
+//	  - in: query
+//	    name: section
+//	    description: Only return this section of the report (required for CSV)
+//	    type: string
+//	    example: members
+//	responses:
+//	  "200":
+//	    description: Report
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Report"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func reportGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	format := request.QueryParam(r, "format")
+	if format == "" {
+		format = "json"
+	}
+
+	if !slices.Contains([]string{"json", "csv"}, format) {
+		return response.BadRequest(fmt.Errorf("Invalid report format %q", format))
+	}
+
+	var report *api.Report
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		report, err = tx.GetReport(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	sectionName := request.QueryParam(r, "section")
+	if sectionName != "" {
+		report.Sections = slices.DeleteFunc(report.Sections, func(section api.ReportSection) bool {
+			return section.Name != sectionName
+		})
+
+		if len(report.Sections) == 0 {
+			return response.NotFound(fmt.Errorf("Report section %q not found", sectionName))
+		}
+	}
+
+	if format == "json" {
+		return response.SyncResponse(true, report)
+	}
+
+	if sectionName == "" {
+		return response.BadRequest(errors.New("A section must be selected to get a report as CSV"))
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s-%s.csv", report.Name, sectionName))
+
+		return reportSectionWriteCSV(w, report.Sections[0])
+	})
+}
+
+// swagger:operation DELETE /1.0/reports/{name} reports report_delete
+//
+//	Delete the report
+//
+//	Removes the report.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func reportDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteReport(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// reportName returns the name of a report of the given type generated at the given time.
+func reportName(reportType string, createdAt time.Time) string {
+	return fmt.Sprintf("%s-%s", reportType, createdAt.UTC().Format("20060102-150405"))
+}
+
+// reportSectionWriteCSV writes a section of a report as CSV, with a header line naming the columns.
+func reportSectionWriteCSV(w http.ResponseWriter, section api.ReportSection) error {
+	csvWriter := csv.NewWriter(w)
+
+	err := csvWriter.Write(section.Columns)
+	if err != nil {
+		return err
+	}
+
+	err = csvWriter.WriteAll(section.Rows)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// reportGenerate generates and stores a report of the given type, then pushes it to the configured webhook.
+func reportGenerate(ctx context.Context, s *state.State, reportType string, createdAt time.Time) error {
+	report := api.Report{
+		Name:      reportName(reportType, createdAt),
+		Type:      reportType,
+		CreatedAt: createdAt,
+	}
+
+	var err error
+
+	switch reportType {
+	case api.ReportTypeCapacity:
+		report.Sections, err = reportCapacity(ctx, s)
+	case api.ReportTypeInventory:
+		report.Sections, err = reportInventory(ctx, s)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed generating %s report: %w", reportType, err)
+	}
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateReport(ctx, report)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, webhook, _ := s.GlobalConfig.Reports()
+	if webhook != "" {
+		err = reportPush(ctx, s, webhook, report)
+		if err != nil {
+			return fmt.Errorf("Failed pushing report %q: %w", report.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// reportPush sends the report as JSON to the webhook URL.
+func reportPush(ctx context.Context, s *state.State, webhook string, report api.Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	client, err := localUtil.HTTPClient("", s.Proxy)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Webhook returned %q", resp.Status)
+	}
+
+	return nil
+}
+
+// reportCapacity returns the sections of a capacity report: the resource usage and limits of the projects, the
+// memory and load of the cluster members and the space used in the storage pools of each member.
+func reportCapacity(ctx context.Context, s *state.State) ([]api.ReportSection, error) {
+	projects := api.ReportSection{Name: "projects", Columns: []string{"project", "resource", "usage", "limit"}}
+	members := api.ReportSection{Name: "members", Columns: []string{"member", "status", "instances", "memory_total", "memory_free", "load_average"}}
+	pools := api.ReportSection{Name: "storage_pools", Columns: []string{"pool", "member", "driver", "space_used", "space_total", "inodes_used", "inodes_total"}}
+
+	var nodes []db.NodeInfo
+	var poolDrivers map[string]string
+	instanceCounts := map[string]int{}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectNames, err := dbCluster.GetProjectNames(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, projectName := range projectNames {
+			resources, err := projecthelpers.GetCurrentAllocations(ctx, tx, projectName)
+			if err != nil {
+				return fmt.Errorf("Failed getting resource usage of project %q: %w", projectName, err)
+			}
+
+			for resource, value := range resources {
+				projects.Rows = append(projects.Rows, []string{projectName, resource, strconv.FormatInt(value.Usage, 10), strconv.FormatInt(value.Limit, 10)})
+			}
+		}
+
+		nodes, err = tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		instances, err := dbCluster.GetInstances(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, inst := range instances {
+			instanceCounts[inst.Node]++
+		}
+
+		storagePools, _, err := tx.GetStoragePools(ctx, nil)
+		if err != nil && !response.IsNotFoundError(err) {
+			return err
+		}
+
+		poolDrivers = make(map[string]string, len(storagePools))
+		for _, pool := range storagePools {
+			poolDrivers[pool.Name] = pool.Driver
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	offlineThreshold := s.GlobalConfig.OfflineThreshold()
+
+	for _, node := range nodes {
+		status := "online"
+		if node.State == db.ClusterMemberStateEvacuated {
+			status = "evacuated"
+		} else if node.IsOffline(offlineThreshold) {
+			status = "offline"
+		}
+
+		memberState, err := reportMemberState(ctx, s, node, status)
+		if err != nil {
+			logger.Warn("Failed getting cluster member state for capacity report", logger.Ctx{"member": node.Name, "err": err})
+			status = "unreachable"
+		}
+
+		row := []string{node.Name, status, strconv.Itoa(instanceCounts[node.Name]), "", "", ""}
+		if memberState == nil {
+			members.Rows = append(members.Rows, row)
+			continue
+		}
+
+		row[3] = strconv.FormatUint(memberState.SysInfo.TotalRAM, 10)
+		row[4] = strconv.FormatUint(memberState.SysInfo.FreeRAM, 10)
+		if len(memberState.SysInfo.LoadAverages) > 0 {
+			row[5] = strconv.FormatFloat(memberState.SysInfo.LoadAverages[0], 'f', 2, 64)
+		}
+
+		members.Rows = append(members.Rows, row)
+
+		for poolName, poolState := range memberState.StoragePools {
+			pools.Rows = append(pools.Rows, []string{
+				poolName,
+				node.Name,
+				poolDrivers[poolName],
+				strconv.FormatUint(poolState.Space.Used, 10),
+				strconv.FormatUint(poolState.Space.Total, 10),
+				strconv.FormatUint(poolState.Inodes.Used, 10),
+				strconv.FormatUint(poolState.Inodes.Total, 10),
+			})
+		}
+	}
+
+	return []api.ReportSection{reportSortRows(projects), reportSortRows(members), reportSortRows(pools)}, nil
+}
+
+// reportMemberState returns the state of a cluster member, or nil if the member is offline.
+func reportMemberState(ctx context.Context, s *state.State, node db.NodeInfo, status string) (*api.ClusterMemberState, error) {
+	if !s.ServerClustered || node.Name == s.ServerName {
+		return cluster.MemberState(ctx, s, node.Name)
+	}
+
+	if status == "offline" {
+		return nil, nil
+	}
+
+	client, err := cluster.Connect(node.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	memberState, _, err := client.GetClusterMemberState(node.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return memberState, nil
+}
+
+// reportInventory returns the sections of an inventory report: the instances and the custom storage volumes.
+func reportInventory(ctx context.Context, s *state.State) ([]api.ReportSection, error) {
+	instances := api.ReportSection{Name: "instances", Columns: []string{"project", "name", "type", "location", "architecture", "image_os", "image_release", "limits_cpu", "limits_memory", "root_size", "created_at", "last_used_at"}}
+	volumes := api.ReportSection{Name: "storage_volumes", Columns: []string{"project", "pool", "name", "content_type", "location", "size", "created_at"}}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.InstanceList(ctx, func(inst db.InstanceArgs, _ api.Project) error {
+			config := db.ExpandInstanceConfig(inst.Config, inst.Profiles)
+			devices := db.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+
+			architecture, err := osarch.ArchitectureName(inst.Architecture)
+			if err != nil {
+				architecture = ""
+			}
+
+			rootSize := ""
+			_, rootDisk, err := internalInstance.GetRootDiskDevice(devices.CloneNative())
+			if err == nil {
+				rootSize = rootDisk["size"]
+			}
+
+			lastUsed := ""
+			if !inst.LastUsedDate.IsZero() {
+				lastUsed = inst.LastUsedDate.UTC().Format(time.RFC3339)
+			}
+
+			instances.Rows = append(instances.Rows, []string{
+				inst.Project,
+				inst.Name,
+				inst.Type.String(),
+				inst.Node,
+				architecture,
+				config["image.os"],
+				config["image.release"],
+				config["limits.cpu"],
+				config["limits.memory"],
+				rootSize,
+				inst.CreationDate.UTC().Format(time.RFC3339),
+				lastUsed,
+			})
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		poolIDs, err := tx.GetStoragePoolNames(ctx)
+		if err != nil && !response.IsNotFoundError(err) {
+			return err
+		}
+
+		customType := db.StoragePoolVolumeTypeCustom
+
+		for _, poolName := range poolIDs {
+			poolID, err := tx.GetStoragePoolID(ctx, poolName)
+			if err != nil {
+				return err
+			}
+
+			poolVolumes, err := tx.GetStoragePoolVolumes(ctx, poolID, false, db.StorageVolumeFilter{Type: &customType})
+			if err != nil {
+				return err
+			}
+
+			for _, vol := range poolVolumes {
+				if internalInstance.IsSnapshot(vol.Name) {
+					continue
+				}
+
+				volumes.Rows = append(volumes.Rows, []string{
+					vol.Project,
+					poolName,
+					vol.Name,
+					vol.ContentType,
+					vol.Location,
+					vol.Config["size"],
+					vol.CreatedAt.UTC().Format(time.RFC3339),
+				})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []api.ReportSection{reportSortRows(instances), reportSortRows(volumes)}, nil
+}
+
+// reportSortRows sorts the rows of a section by the values of their columns, left to right.
+func reportSortRows(section api.ReportSection) api.ReportSection {
+	if section.Rows == nil {
+		section.Rows = [][]string{}
+	}
+
+	slices.SortFunc(section.Rows, func(a []string, b []string) int {
+		return slices.Compare(a, b)
+	})
+
+	return section
+}
+
+// generateReportsTask generates a capacity and an inventory report on the schedule set in reports.schedule.
+// In a cluster, the reports are generated by the leader.
+func generateReportsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		schedule, _, _ := s.GlobalConfig.Reports()
+		if schedule == "" || !snapshotIsScheduledNow(schedule, 0) {
+			return
+		}
+
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil && !errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			logger.Error("Failed to get leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		if err == nil && s.LocalConfig.ClusterAddress() != leader {
+			return // Skip generating the reports if not cluster leader.
+		}
+
+		createdAt := time.Now()
+		for _, reportType := range []string{api.ReportTypeCapacity, api.ReportTypeInventory} {
+			logger.Info("Generating report", logger.Ctx{"type": reportType})
+
+			err := reportGenerate(ctx, s, reportType, createdAt)
+			if err != nil {
+				logger.Error("Failed generating report", logger.Ctx{"type": reportType, "err": err})
+			}
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// pruneExpiredReportsTask removes the reports which are older than the configured expiry.
+func pruneExpiredReportsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		_, _, expiryDays := s.GlobalConfig.Reports()
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteReportsBefore(ctx, time.Now().AddDate(0, 0, -int(expiryDays)))
+		})
+		if err != nil {
+			logger.Error("Failed pruning expired reports", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Hourly()
+}
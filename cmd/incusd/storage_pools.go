@@ -9,8 +9,10 @@ import (
 	"net/http"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -20,13 +22,20 @@ import (
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	clusterRequest "github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/internal/server/task"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/server/warnings"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -736,6 +745,11 @@ func storagePoolGet(d *Daemon, r *http.Request) response.Response {
 		poolAPI.Status = pool.LocalStatus()
 	}
 
+	// The health of local pools differs between members, so is only reported for a specific member.
+	if !s.ServerClustered || memberSpecific || pool.Driver().Info().Remote {
+		poolAPI.Health = pool.Health()
+	}
+
 	etag := []any{pool.Name(), pool.Driver().Info().Name, pool.Description(), poolAPI.Config}
 
 	return response.SyncResponseETag(true, &poolAPI, etag)
@@ -1136,3 +1150,304 @@ func storagePoolDelete(d *Daemon, r *http.Request) response.Response {
 
 	return response.EmptySyncResponse
 }
+
+func autoTrimStoragePoolsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		var poolNames []string
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+			poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+			return err
+		})
+		if err != nil {
+			if !response.IsNotFoundError(err) {
+				logger.Error("Failed getting storage pools for trim task", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		for _, poolName := range poolNames {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				logger.Error("Failed loading storage pool for trim task", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			schedule := pool.Driver().Config()["maintenance.fstrim.schedule"]
+			if schedule == "" || pool.LocalStatus() != api.StoragePoolStatusCreated {
+				continue
+			}
+
+			// Check if trim is scheduled.
+			if !snapshotIsScheduledNow(schedule, pool.ID()) {
+				continue
+			}
+
+			opRun := func(op *operations.Operation) error {
+				return pool.TrimVolumes(op)
+			}
+
+			resources := map[string][]api.URL{}
+			resources["storage_pools"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName)}
+
+			op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.StoragePoolTrim, resources, nil, opRun, nil, nil, nil)
+			if err != nil {
+				logger.Error("Failed creating storage pool trim operation", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			logger.Info("Trimming storage pool", logger.Ctx{"pool": poolName})
+			err = op.Start()
+			if err != nil {
+				logger.Error("Failed starting storage pool trim operation", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			err = op.Wait(ctx)
+			if err != nil {
+				logger.Error("Failed trimming storage pool", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			logger.Info("Done trimming storage pool", logger.Ctx{"pool": poolName})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+func storagePoolHealthTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		var poolNames []string
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+			poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+			return err
+		})
+		if err != nil {
+			if !response.IsNotFoundError(err) {
+				logger.Error("Failed getting storage pools for health check task", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		for _, poolName := range poolNames {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				logger.Error("Failed loading storage pool for health check task", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			if pool.LocalStatus() != api.StoragePoolStatusCreated {
+				continue
+			}
+
+			health, err := pool.CheckHealth()
+			if err != nil {
+				if !errors.Is(err, storageDrivers.ErrNotSupported) {
+					logger.Error("Failed checking storage pool health", logger.Ctx{"pool": poolName, "err": err})
+				}
+
+				continue
+			}
+
+			if health.Status == api.StoragePoolHealthHealthy {
+				_ = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningtype.StoragePoolDegraded, dbCluster.TypeStoragePool, int(pool.ID()))
+				continue
+			}
+
+			logger.Warn("Storage pool is degraded", logger.Ctx{"pool": poolName, "messages": health.Messages})
+			_ = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpsertWarningLocalNode(ctx, "", dbCluster.TypeStoragePool, int(pool.ID()), warningtype.StoragePoolDegraded, strings.Join(health.Messages, "; "))
+			})
+		}
+	}
+
+	return f, task.Every(time.Hour)
+}
+
+func autoScrubStoragePoolsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		var poolNames []string
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+			poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+			return err
+		})
+		if err != nil {
+			if !response.IsNotFoundError(err) {
+				logger.Error("Failed getting storage pools for scrub task", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		for _, poolName := range poolNames {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				logger.Error("Failed loading storage pool for scrub task", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			schedule := pool.Driver().Config()["maintenance.scrub.schedule"]
+			if schedule == "" || pool.LocalStatus() != api.StoragePoolStatusCreated {
+				continue
+			}
+
+			// Check if scrub is scheduled.
+			if !snapshotIsScheduledNow(schedule, pool.ID()) {
+				continue
+			}
+
+			// Scrubs run in the background of the storage driver, their results are reported by the health checks.
+			logger.Info("Scrubbing storage pool", logger.Ctx{"pool": poolName})
+			err = pool.Scrub()
+			if err != nil {
+				logger.Error("Failed scrubbing storage pool", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+func storagePoolThresholdTask(d *Daemon) (task.Func, task.Schedule) {
+	// Storage pools whose usage is above their threshold, so that events are only sent when it's crossed.
+	exceeded := map[string]bool{}
+
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		var poolNames []string
+		var memberIDs []int64
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+			poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+			if err != nil {
+				return err
+			}
+
+			members, err := tx.GetNodes(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, member := range members {
+				memberIDs = append(memberIDs, member.ID)
+			}
+
+			return nil
+		})
+		if err != nil {
+			if !response.IsNotFoundError(err) {
+				logger.Error("Failed getting storage pools for threshold task", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		localMemberID := s.DB.Cluster.GetNodeID()
+
+		for _, poolName := range poolNames {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				logger.Error("Failed loading storage pool for threshold task", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			thresholdStr := pool.Driver().Config()["storage.alert.threshold"]
+			if thresholdStr == "" || pool.LocalStatus() != api.StoragePoolStatusCreated {
+				delete(exceeded, poolName)
+				continue
+			}
+
+			// Remote pools are checked by a stable random member to only send their events once.
+			if pool.Driver().Info().Remote {
+				selectedMemberID, err := localUtil.GetStableRandomInt64FromList(pool.ID(), memberIDs)
+				if err != nil || selectedMemberID != localMemberID {
+					delete(exceeded, poolName)
+					continue
+				}
+			}
+
+			threshold, err := strconv.ParseUint(thresholdStr, 10, 64)
+			if err != nil {
+				continue
+			}
+
+			res, err := pool.GetResources()
+			if err != nil {
+				if !errors.Is(err, storageDrivers.ErrNotSupported) {
+					logger.Error("Failed getting storage pool resources for threshold task", logger.Ctx{"pool": poolName, "err": err})
+				}
+
+				continue
+			}
+
+			if res.Space.Total == 0 {
+				continue
+			}
+
+			usage := res.Space.Used * 100 / res.Space.Total
+			above := usage >= threshold
+			if above == exceeded[poolName] {
+				continue
+			}
+
+			action := api.EventStorageActionPoolThresholdRecovered
+			if above {
+				action = api.EventStorageActionPoolThresholdExceeded
+				exceeded[poolName] = true
+				logger.Warn("Storage pool usage is above its threshold", logger.Ctx{"pool": poolName, "usage": usage, "threshold": threshold})
+			} else {
+				delete(exceeded, poolName)
+			}
+
+			_ = s.Events.Send(api.ProjectDefaultName, api.EventTypeStorage, api.EventStorage{
+				Action: action,
+				Pool:   poolName,
+				Context: map[string]any{
+					"used":      res.Space.Used,
+					"total":     res.Space.Total,
+					"usage":     usage,
+					"threshold": threshold,
+				},
+			})
+		}
+	}
+
+	return f, task.Every(5 * time.Minute)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	localMigration "github.com/lxc/incus/v6/internal/server/migration"
+	"github.com/lxc/incus/v6/internal/server/response"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// storagePoolMigrationFeatures lists the optional migration protocol features supported by this server.
+var storagePoolMigrationFeatures = []string{"index_header", "parallel_streams", "compression", "encryption", "verify", "snapshot_diff", "postcopy", "warm"}
+
+var storagePoolMigrationCmd = APIEndpoint{
+	Path: "storage-pools/{poolName}/migration",
+
+	Get: APIEndpointAction{Handler: storagePoolMigrationGet, AccessHandler: allowPermission(auth.ObjectTypeStoragePool, auth.EntitlementCanView, "poolName")},
+}
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/migration storage storage_pool_migration_get
+//
+//	Get the migration features of the storage pool
+//
+//	Gets the migration transfer types and features the server supports for the storage pool.
+//	This allows picking the source and target of a migration before starting it.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: Migration features
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/StoragePoolMigration"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolMigrationGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info := pool.Driver().Info()
+
+	result := api.StoragePoolMigration{
+		Driver:       info.Name,
+		Remote:       info.Remote,
+		ContentTypes: map[string]api.StoragePoolMigrationContentType{},
+		Live:         []string{},
+		Features:     storagePoolMigrationFeatures,
+	}
+
+	for _, contentType := range []storageDrivers.ContentType{storageDrivers.ContentTypeFS, storageDrivers.ContentTypeBlock} {
+		types := storagePoolMigrationTypes(pool.MigrationTypes(contentType, false, true, false, false))
+		if len(types) == 0 {
+			continue
+		}
+
+		result.ContentTypes[string(contentType)] = api.StoragePoolMigrationContentType{
+			Types:        types,
+			RefreshTypes: storagePoolMigrationTypes(pool.MigrationTypes(contentType, true, true, false, false)),
+		}
+	}
+
+	// Containers need CRIU for live migration while virtual machines only need a working QEMU.
+	drivers := instanceDrivers.DriverStatuses()
+
+	_, err = exec.LookPath("criu")
+	if err == nil && drivers[instancetype.Container] != nil && drivers[instancetype.Container].Supported {
+		result.Live = append(result.Live, instancetype.Container.String())
+	}
+
+	if drivers[instancetype.VM] != nil && drivers[instancetype.VM].Supported {
+		result.Live = append(result.Live, instancetype.VM.String())
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// storagePoolMigrationTypes converts the migration types of a storage pool to their API representation.
+func storagePoolMigrationTypes(migrationTypes []localMigration.Type) []api.StoragePoolMigrationType {
+	types := make([]api.StoragePoolMigrationType, 0, len(migrationTypes))
+	for _, migrationType := range migrationTypes {
+		features := migrationType.Features
+		if features == nil {
+			features = []string{}
+		}
+
+		types = append(types, api.StoragePoolMigrationType{
+			Type:     strings.ToLower(migrationType.FSType.String()),
+			Features: features,
+		})
+	}
+
+	return types
+}
@@ -45,6 +45,11 @@ func storagePoolDBCreate(ctx context.Context, s *state.State, poolName string, p
 }
 
 func storagePoolValidate(s *state.State, poolName string, driverName string, config map[string]string) error {
+	// Only directories can be used without any privileges on the host.
+	if s.OS.Rootless && driverName != "dir" {
+		return fmt.Errorf("Storage driver %q isn't supported in rootless mode, only \"dir\" is", driverName)
+	}
+
 	poolType, err := storagePools.LoadByType(s, driverName)
 	if err != nil {
 		return err
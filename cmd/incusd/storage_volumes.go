@@ -31,6 +31,7 @@ import (
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
@@ -699,6 +700,15 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Currently not allowed to create storage volumes of type %q", req.Type))
 	}
 
+	// Load the source volume of local copies, which may be in another project.
+	var srcVolume *db.StorageVolume
+	if req.Source.Type == "copy" && req.Source.Name != "" {
+		srcVolume, err = storagePoolVolumeCopySource(s, r, projectName, req)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	var poolID int64
 	var dbVolume *db.StorageVolume
 
@@ -714,7 +724,13 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
-		err = project.AllowVolumeCreation(tx, projectName, poolName, req)
+		// Copies without a config use the config of their source, so account for it in the project limits.
+		limitsReq := req
+		if srcVolume != nil && req.Config == nil {
+			limitsReq.Config = srcVolume.Config
+		}
+
+		err = project.AllowVolumeCreation(tx, projectName, poolName, limitsReq)
 		if err != nil {
 			return err
 		}
@@ -766,11 +782,76 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return doVolumeCreateOrCopy(s, r, request.ProjectParam(r), projectName, poolName, &req)
 	case "migration":
 		return doVolumeMigration(s, r, request.ProjectParam(r), projectName, poolName, &req)
+	case "existing":
+		return doVolumeImportExisting(s, r, projectName, poolName, &req)
+	case "url":
+		return doVolumeImportURL(s, r, request.ProjectParam(r), projectName, poolName, &req)
 	default:
 		return response.BadRequest(fmt.Errorf("Unknown source type %q", req.Source.Type))
 	}
 }
 
+// storagePoolVolumeCopySource returns the source volume of a copy request.
+// As the source volume may be in a different project than the new volume, this also checks that the requestor
+// can access it.
+func storagePoolVolumeCopySource(s *state.State, r *http.Request, projectName string, req api.StorageVolumesPost) (*db.StorageVolume, error) {
+	srcProjectName := projectName
+	if req.Source.Project != "" {
+		var err error
+
+		srcProjectName, err = project.StorageVolumeProject(s.DB.Cluster, req.Source.Project, db.StoragePoolVolumeTypeCustom)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	srcPool, err := storagePools.LoadByName(s, req.Source.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	var srcVolume *db.StorageVolume
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Copies within a cluster may use a source volume located on another member.
+		if req.Source.Location == "" {
+			srcVolume, err = tx.GetStoragePoolVolume(ctx, srcPool.ID(), srcProjectName, db.StoragePoolVolumeTypeCustom, req.Source.Name, true)
+			return err
+		}
+
+		volumeType := db.StoragePoolVolumeTypeCustom
+		volumes, err := tx.GetStoragePoolVolumes(ctx, srcPool.ID(), false, db.StorageVolumeFilter{Project: &srcProjectName, Type: &volumeType, Name: &req.Source.Name})
+		if err != nil {
+			return err
+		}
+
+		for _, volume := range volumes {
+			if volume.Location == req.Source.Location || volume.Location == "" {
+				srcVolume = volume
+				return nil
+			}
+		}
+
+		return api.StatusErrorf(http.StatusNotFound, "Storage volume not found")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading source volume %q: %w", req.Source.Name, err)
+	}
+
+	var location string
+	if s.ServerClustered && !srcPool.Driver().Info().Remote {
+		location = srcVolume.Location
+	}
+
+	srcVolumeName, _, _ := api.GetParentAndSnapshotName(req.Source.Name)
+	err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectStorageVolume(srcProjectName, srcPool.Name(), db.StoragePoolVolumeTypeNameCustom, srcVolumeName, location), auth.EntitlementCanView)
+	if err != nil {
+		return nil, err
+	}
+
+	return srcVolume, nil
+}
+
 func clusterCopyCustomVolumeInternal(s *state.State, r *http.Request, sourceAddress string, projectName string, poolName string, req *api.StorageVolumesPost) response.Response {
 	websockets := map[string]string{}
 
@@ -907,6 +988,46 @@ func doVolumeCreateOrCopy(s *state.State, r *http.Request, requestProjectName st
 	return operations.OperationResponse(op)
 }
 
+// doVolumeImportExisting adopts an existing source of the pool's storage as a new custom volume.
+// As the source can be any dataset, subvolume or logical volume of the pool's storage, this requires the
+// permission to edit the server.
+func doVolumeImportExisting(s *state.State, r *http.Request, projectName string, poolName string, req *api.StorageVolumesPost) response.Response {
+	err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectServer(), auth.EntitlementCanEdit)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if req.Source.Name == "" {
+		return response.BadRequest(errors.New("The source name is required when importing an existing volume"))
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	volumeDBContentType, err := storagePools.VolumeContentTypeNameToContentType(req.ContentType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	contentType, err := storagePools.VolumeDBContentTypeToContentType(volumeDBContentType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Use an empty operation for this sync response to pass the requestor.
+	op := &operations.Operation{}
+	op.SetRequestor(r)
+
+	err = pool.AdoptCustomVolume(projectName, req.Name, req.Source.Name, req.Description, req.Config, contentType, op)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
 func doVolumeMigration(s *state.State, r *http.Request, requestProjectName string, projectName string, poolName string, req *api.StorageVolumesPost) response.Response {
 	// Validate migration mode
 	if req.Source.Mode != "pull" && req.Source.Mode != "push" {
@@ -952,6 +1073,14 @@ func doVolumeMigration(s *state.State, r *http.Request, requestProjectName strin
 		VolumeOnly:          req.Source.VolumeOnly,
 		Refresh:             req.Source.Refresh,
 		RefreshExcludeOlder: req.Source.RefreshExcludeOlder,
+		ResumeTimeout:       s.GlobalConfig.MigrationResumeTimeout(),
+		Streams:             s.GlobalConfig.MigrationStreams(),
+		RawTransport:        s.GlobalConfig.MigrationRawTransport(),
+	}
+
+	migrationArgs.Timeouts, err = newMigrationTimeouts(s, req.Source.Timeouts)
+	if err != nil {
+		return response.BadRequest(err)
 	}
 
 	sink, err := newStorageMigrationSink(&migrationArgs)
@@ -1360,6 +1489,7 @@ func storagePoolVolumePost(d *Daemon, r *http.Request) response.Response {
 	}
 
 	// Check if a running instance is using it.
+	var runningInsts []instance.Instance
 	err = storagePools.VolumeUsedByInstanceDevices(s, srcPoolName, projectName, &dbVolume.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
 		inst, err := instance.Load(s, dbInst, project)
 		if err != nil {
@@ -1367,7 +1497,7 @@ func storagePoolVolumePost(d *Daemon, r *http.Request) response.Response {
 		}
 
 		if inst.IsRunning() {
-			return errors.New("Volume is still in use by running instances")
+			runningInsts = append(runningInsts, inst)
 		}
 
 		return nil
@@ -1378,11 +1508,29 @@ func storagePoolVolumePost(d *Daemon, r *http.Request) response.Response {
 
 	// Detect a rename request.
 	if (req.Pool == "" || req.Pool == srcPoolName) && (projectName == targetProjectName) {
+		if len(runningInsts) > 0 {
+			return response.SmartError(errors.New("Volume is still in use by running instances"))
+		}
+
 		return storagePoolVolumeTypePostRename(s, r, srcPoolName, projectName, &dbVolume.StorageVolume, req)
 	}
 
+	// Filesystem volumes used by running containers of this server can be moved live by switching the
+	// containers over to the new volume.
+	if len(runningInsts) > 0 {
+		if dbVolume.ContentType != db.StoragePoolVolumeContentTypeNameFS {
+			return response.SmartError(errors.New("Volume is still in use by running instances"))
+		}
+
+		for _, inst := range runningInsts {
+			if inst.Type() != instancetype.Container || (s.ServerClustered && inst.Location() != s.ServerName) {
+				return response.SmartError(errors.New("Volume is still in use by running instances that aren't local containers"))
+			}
+		}
+	}
+
 	// Otherwise this is a move request.
-	return storagePoolVolumeTypePostMove(s, r, srcPoolName, projectName, targetProjectName, &dbVolume.StorageVolume, req)
+	return storagePoolVolumeTypePostMove(s, r, srcPoolName, projectName, targetProjectName, &dbVolume.StorageVolume, req, runningInsts)
 }
 
 func migrateStorageVolume(s *state.State, r *http.Request, sourceVolumeName string, sourcePoolName string, targetNode string, projectName string, req api.StorageVolumePost, op *operations.Operation) error {
@@ -1417,7 +1565,7 @@ func migrateStorageVolume(s *state.State, r *http.Request, sourceVolumeName stri
 		return fmt.Errorf("Failed loading storage volume storage pool: %w", err)
 	}
 
-	f, err := storageVolumePostClusteringMigrate(s, r, srcPool, projectName, sourceVolumeName, req.Pool, req.Project, req.Name, srcMember, newMember, req.VolumeOnly)
+	f, err := storageVolumePostClusteringMigrate(s, r, srcPool, projectName, sourceVolumeName, req.Pool, req.Project, req.Name, srcMember, newMember, req.VolumeOnly, req.Timeouts)
 	if err != nil {
 		return err
 	}
@@ -1425,7 +1573,7 @@ func migrateStorageVolume(s *state.State, r *http.Request, sourceVolumeName stri
 	return f(op)
 }
 
-func storageVolumePostClusteringMigrate(s *state.State, r *http.Request, srcPool storagePools.Pool, srcProjectName string, srcVolumeName string, newPoolName string, newProjectName string, newVolumeName string, srcMember db.NodeInfo, newMember db.NodeInfo, volumeOnly bool) (func(op *operations.Operation) error, error) {
+func storageVolumePostClusteringMigrate(s *state.State, r *http.Request, srcPool storagePools.Pool, srcProjectName string, srcVolumeName string, newPoolName string, newProjectName string, newVolumeName string, srcMember db.NodeInfo, newMember db.NodeInfo, volumeOnly bool, timeouts *api.MigrationTimeouts) (func(op *operations.Operation) error, error) {
 	srcMemberOffline := srcMember.IsOffline(s.GlobalConfig.OfflineThreshold())
 
 	// Make sure that the source member is online if we end up being called from another member after a
@@ -1454,11 +1602,16 @@ func storageVolumePostClusteringMigrate(s *state.State, r *http.Request, srcPool
 		resources := map[string][]api.URL{}
 		resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", srcPool.Name(), "volumes", "custom", srcVolumeName)}
 
-		srcMigration, err := newStorageMigrationSource(volumeOnly, nil)
+		srcMigration, err := newStorageMigrationSource(volumeOnly, nil, s.GlobalConfig.MigrationResumeTimeout(), s.GlobalConfig.MigrationStreams(), s.GlobalConfig.MigrationRawTransport())
 		if err != nil {
 			return fmt.Errorf("Failed setting up storage volume migration on source: %w", err)
 		}
 
+		srcMigration.timeouts, err = newMigrationTimeouts(s, timeouts)
+		if err != nil {
+			return err
+		}
+
 		run := func(op *operations.Operation) error {
 			err := srcMigration.DoStorage(s, srcProjectName, srcPool.Name(), srcVolumeName, op)
 			if err != nil {
@@ -1500,12 +1653,13 @@ func storageVolumePostClusteringMigrate(s *state.State, r *http.Request, srcPool
 			Source: api.StorageVolumeSource{
 				Type:        "migration",
 				Mode:        "pull",
-				Operation:   fmt.Sprintf("https://%s%s", srcMember.Address, srcOp.URL()),
+				Operation:   fmt.Sprintf("https://%s%s", migrationSourceAddress(s, srcMember.Address), srcOp.URL()),
 				Websockets:  sourceSecrets,
 				Certificate: string(networkCert.PublicKey()),
 				Name:        newVolumeName,
 				Pool:        newPoolName,
 				Project:     newProjectName,
+				Timeouts:    timeouts,
 			},
 		})
 		if err != nil {
@@ -1520,11 +1674,16 @@ func storageVolumePostClusteringMigrate(s *state.State, r *http.Request, srcPool
 
 // storagePoolVolumeTypePostMigration handles volume migration type POST requests.
 func storagePoolVolumeTypePostMigration(state *state.State, r *http.Request, requestProjectName string, projectName string, poolName string, volumeName string, req api.StorageVolumePost) response.Response {
-	ws, err := newStorageMigrationSource(req.VolumeOnly, req.Target)
+	ws, err := newStorageMigrationSource(req.VolumeOnly, req.Target, state.GlobalConfig.MigrationResumeTimeout(), state.GlobalConfig.MigrationStreams(), state.GlobalConfig.MigrationRawTransport())
 	if err != nil {
 		return response.InternalError(err)
 	}
 
+	ws.timeouts, err = newMigrationTimeouts(state, req.Timeouts)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	resources := map[string][]api.URL{}
 	srcVolParentName, srcVolSnapName, srcIsSnapshot := api.GetParentAndSnapshotName(volumeName)
 	if srcIsSnapshot {
@@ -1592,7 +1751,7 @@ func storagePoolVolumeTypePostRename(s *state.State, r *http.Request, poolName s
 }
 
 // storagePoolVolumeTypePostMove handles volume move type POST requests.
-func storagePoolVolumeTypePostMove(s *state.State, r *http.Request, poolName string, requestProjectName string, projectName string, vol *api.StorageVolume, req api.StorageVolumePost) response.Response {
+func storagePoolVolumeTypePostMove(s *state.State, r *http.Request, poolName string, requestProjectName string, projectName string, vol *api.StorageVolume, req api.StorageVolumePost, runningInsts []instance.Instance) response.Response {
 	newVol := *vol
 	newVol.Name = req.Name
 
@@ -1610,6 +1769,10 @@ func storagePoolVolumeTypePostMove(s *state.State, r *http.Request, poolName str
 		reverter := revert.New()
 		defer reverter.Fail()
 
+		if len(runningInsts) > 0 {
+			return storagePoolVolumeMoveLive(s, pool, newPool, requestProjectName, projectName, vol, &newVol, runningInsts, op)
+		}
+
 		// Update devices using the volume in instances and profiles.
 		err = storagePoolVolumeUpdateUsers(context.TODO(), s, requestProjectName, pool.Name(), vol, newPool.Name(), &newVol)
 		if err != nil {
@@ -1644,6 +1807,72 @@ func storagePoolVolumeTypePostMove(s *state.State, r *http.Request, poolName str
 	return operations.OperationResponse(op)
 }
 
+// storagePoolVolumeMoveLive moves a filesystem volume used by running containers without stopping them.
+// The volume is first copied while the containers keep running, then the containers are frozen while the
+// changes made since the copy are synced and their disk devices are switched over to the new volume.
+func storagePoolVolumeMoveLive(s *state.State, pool storagePools.Pool, newPool storagePools.Pool, requestProjectName string, projectName string, vol *api.StorageVolume, newVol *api.StorageVolume, runningInsts []instance.Instance, op *operations.Operation) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Provide empty description and nil config to instruct CreateCustomVolumeFromCopy to copy it
+	// from source volume.
+	err := newPool.CreateCustomVolumeFromCopy(projectName, requestProjectName, newVol.Name, "", nil, pool.Name(), vol.Name, true, op)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = newPool.DeleteCustomVolume(projectName, newVol.Name, op) })
+
+	// Freeze the containers so that the source volume doesn't change anymore.
+	frozenInsts := make([]instance.Instance, 0, len(runningInsts))
+	for _, inst := range runningInsts {
+		if inst.IsFrozen() {
+			continue
+		}
+
+		err = inst.Freeze()
+		if err != nil {
+			return fmt.Errorf("Failed freezing instance %q: %w", inst.Name(), err)
+		}
+
+		reverter.Add(func() { _ = inst.Unfreeze() })
+		frozenInsts = append(frozenInsts, inst)
+	}
+
+	// Sync the changes made since the initial copy.
+	err = newPool.RefreshCustomVolume(projectName, requestProjectName, newVol.Name, "", nil, pool.Name(), vol.Name, true, false, op)
+	if err != nil {
+		return err
+	}
+
+	// Switch the devices of instances and profiles over to the new volume.
+	err = storagePoolVolumeUpdateUsers(context.TODO(), s, requestProjectName, pool.Name(), vol, newPool.Name(), newVol)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		_ = storagePoolVolumeUpdateUsers(context.TODO(), s, projectName, newPool.Name(), newVol, pool.Name(), vol)
+	})
+
+	// The containers now use the new volume so the move can't be reverted anymore.
+	reverter.Success()
+
+	for _, inst := range frozenInsts {
+		err = inst.Unfreeze()
+		if err != nil {
+			logger.Warn("Failed unfreezing instance after volume move", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+		}
+	}
+
+	err = pool.DeleteCustomVolume(requestProjectName, vol.Name, op)
+	if err != nil {
+		return fmt.Errorf("Failed deleting source volume %q after move: %w", vol.Name, err)
+	}
+
+	return nil
+}
+
 // swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName} storage storage_pool_volume_type_get
 //
 //	Get the storage volume
@@ -2228,16 +2457,8 @@ func createStoragePoolVolumeFromISO(s *state.State, r *http.Request, requestProj
 		return response.BadRequest(errors.New("Missing volume name"))
 	}
 
-	// Create isos directory if needed.
-	if !util.PathExists(internalUtil.VarPath("isos")) {
-		err := os.MkdirAll(internalUtil.VarPath("isos"), 0o644)
-		if err != nil {
-			return response.InternalError(err)
-		}
-	}
-
 	// Create temporary file to store uploaded ISO data.
-	isoFile, err := os.CreateTemp(internalUtil.VarPath("isos"), fmt.Sprintf("%s_", "incus_iso"))
+	isoFile, err := createISOTempFile()
 	if err != nil {
 		return response.InternalError(err)
 	}
@@ -2285,6 +2506,19 @@ func createStoragePoolVolumeFromISO(s *state.State, r *http.Request, requestProj
 	return operations.OperationResponse(op)
 }
 
+// createISOTempFile creates a temporary file in the isos directory to hold ISO data until it's written to a volume.
+func createISOTempFile() (*os.File, error) {
+	// Create isos directory if needed.
+	if !util.PathExists(internalUtil.VarPath("isos")) {
+		err := os.MkdirAll(internalUtil.VarPath("isos"), 0o644)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return os.CreateTemp(internalUtil.VarPath("isos"), fmt.Sprintf("%s_", "incus_iso"))
+}
+
 func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestProjectName string, projectName string, data io.Reader, pool string, volName string) response.Response {
 	reverter := revert.New()
 	defer reverter.Fail()
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/server/warnings"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+func autoVerifyCustomVolumesIntegrityTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+		var volumes []db.StorageVolumeArgs
+
+		localMemberID := s.DB.Cluster.GetNodeID()
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+			if err != nil {
+				return err
+			}
+
+			var memberIDs []int64
+
+			for _, v := range allVolumes {
+				schedule := v.Config["integrity.schedule"]
+				if schedule == "" || !snapshotIsScheduledNow(schedule, v.ID) {
+					continue
+				}
+
+				if v.NodeID < 0 {
+					// Remote volumes are verified by a stable random member, as their manifest is recorded
+					// on the member doing the checks. All members are considered rather than only the
+					// online ones so that the same member keeps verifying the volume.
+					if memberIDs == nil {
+						members, err := tx.GetNodes(ctx)
+						if err != nil {
+							return err
+						}
+
+						for _, member := range members {
+							memberIDs = append(memberIDs, member.ID)
+						}
+					}
+
+					selectedMemberID, err := localUtil.GetStableRandomInt64FromList(v.ID, memberIDs)
+					if err != nil || selectedMemberID != localMemberID {
+						continue
+					}
+				}
+
+				volumes = append(volumes, v)
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Error("Failed getting custom volumes for integrity task", logger.Ctx{"err": err})
+			return
+		}
+
+		for _, v := range volumes {
+			err := ctx.Err()
+			if err != nil {
+				return
+			}
+
+			l := logger.AddContext(logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+
+			pool, err := storagePools.LoadByName(s, v.PoolName)
+			if err != nil {
+				l.Error("Failed loading storage pool for integrity task", logger.Ctx{"err": err})
+				continue
+			}
+
+			l.Info("Verifying custom volume integrity")
+			mismatches, err := storagePools.VerifyCustomVolumeIntegrity(pool, v.ProjectName, v.Name)
+			if err != nil {
+				l.Error("Failed verifying custom volume integrity", logger.Ctx{"err": err})
+				continue
+			}
+
+			if len(mismatches) == 0 {
+				_ = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, v.ProjectName, warningtype.StorageVolumeIntegrityMismatch, dbCluster.TypeStorageVolume, int(v.ID))
+				continue
+			}
+
+			l.Warn("Custom volume integrity mismatch", logger.Ctx{"mismatches": mismatches})
+			_ = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpsertWarningLocalNode(ctx, v.ProjectName, dbCluster.TypeStorageVolume, int(v.ID), warningtype.StorageVolumeIntegrityMismatch, strings.Join(mismatches, "; "))
+			})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var storagePoolVolumeTypeMirrorCmd = APIEndpoint{
+	Path: "storage-pools/{poolName}/volumes/{type}/{volumeName}/mirror",
+
+	Get:  APIEndpointAction{Handler: storagePoolVolumeTypeMirrorGet, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanView, "poolName", "type", "volumeName")},
+	Post: APIEndpointAction{Handler: storagePoolVolumeTypeMirrorPost, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit, "poolName", "type", "volumeName")},
+}
+
+// storagePoolVolumeMirrorVars returns the pool, project and volume names of a custom volume mirror request.
+func storagePoolVolumeMirrorVars(d *Daemon, r *http.Request) (string, string, string, error) {
+	// Get the name of the pool the storage volume is supposed to be attached to.
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// Get the name of the volume type.
+	volumeTypeName, err := url.PathUnescape(mux.Vars(r)["type"])
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// Get the name of the volume.
+	volumeName, err := url.PathUnescape(mux.Vars(r)["volumeName"])
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// Convert the volume type name to our internal integer representation.
+	volumeType, err := storagePools.VolumeTypeNameToDBType(volumeTypeName)
+	if err != nil {
+		return "", "", "", api.StatusErrorf(http.StatusBadRequest, "%v", err)
+	}
+
+	// Only custom volumes can be mirrored.
+	if volumeType != db.StoragePoolVolumeTypeCustom {
+		return "", "", "", api.StatusErrorf(http.StatusBadRequest, "Invalid storage volume type %q", volumeTypeName)
+	}
+
+	// Get the storage project name.
+	projectName, err := project.StorageVolumeProject(d.State().DB.Cluster, request.ProjectParam(r), volumeType)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return poolName, projectName, volumeName, nil
+}
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/mirror storage storage_pool_volume_type_mirror_get
+//
+//	Get the storage volume mirroring state
+//
+//	Gets the mirroring state of a custom storage volume on the local and peer clusters.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: Storage volume mirroring state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/StorageVolumeMirror"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeMirrorGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	poolName, projectName, volumeName, err := storagePoolVolumeMirrorVars(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(s, r, poolName, projectName, volumeName, db.StoragePoolVolumeTypeCustom)
+	if resp != nil {
+		return resp
+	}
+
+	// Load the storage pool.
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	mirror, err := pool.GetCustomVolumeMirror(projectName, volumeName)
+	if err != nil {
+		if errors.Is(err, storageDrivers.ErrNotSupported) {
+			return response.NotImplemented(fmt.Errorf("Storage pool %q doesn't support volume mirroring", poolName))
+		}
+
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, mirror)
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/mirror storage storage_pool_volume_type_mirror_post
+//
+//	Promote or demote the storage volume
+//
+//	Makes the local copy of a mirrored custom storage volume the primary or a secondary one.
+//	A forced promotion can be used when the peer cluster holding the primary copy is unavailable.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: mirror
+//	    description: Mirroring action
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StorageVolumeMirrorPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeMirrorPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	poolName, projectName, volumeName, err := storagePoolVolumeMirrorVars(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.StorageVolumeMirrorPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Action != "promote" && req.Action != "demote" {
+		return response.BadRequest(fmt.Errorf("Invalid mirroring action %q", req.Action))
+	}
+
+	if req.Force && req.Action != "promote" {
+		return response.BadRequest(errors.New("Only promotions can be forced"))
+	}
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(s, r, poolName, projectName, volumeName, db.StoragePoolVolumeTypeCustom)
+	if resp != nil {
+		return resp
+	}
+
+	// Load the storage pool.
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if req.Action == "promote" {
+		err = pool.PromoteCustomVolume(projectName, volumeName, req.Force, nil)
+	} else {
+		err = pool.DemoteCustomVolume(projectName, volumeName, nil)
+	}
+
+	if err != nil {
+		if errors.Is(err, storageDrivers.ErrNotSupported) {
+			return response.NotImplemented(fmt.Errorf("Storage pool %q doesn't support volume mirroring", poolName))
+		}
+
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -1474,6 +1474,50 @@ func autoCreateCustomVolumeSnapshots(ctx context.Context, s *state.State, volume
 		if err != nil {
 			return fmt.Errorf("Error creating snapshot for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
 		}
+
+		err = pruneUnretainedCustomVolumeSnapshots(ctx, s, pool, v)
+		if err != nil {
+			return fmt.Errorf("Error applying snapshot retention for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		}
+	}
+
+	return nil
+}
+
+// pruneUnretainedCustomVolumeSnapshots deletes the snapshots of the volume not kept by its snapshots.retention policy.
+func pruneUnretainedCustomVolumeSnapshots(ctx context.Context, s *state.State, pool storagePools.Pool, volume db.StorageVolumeArgs) error {
+	policy, err := internalInstance.ParseSnapshotRetention(volume.Config["snapshots.retention"])
+	if err != nil || len(policy) == 0 {
+		return err
+	}
+
+	var snapshots []db.StorageVolumeArgs
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		snapshots, err = tx.GetLocalStoragePoolVolumeSnapshotsWithType(ctx, volume.ProjectName, volume.Name, db.StoragePoolVolumeTypeCustom, pool.ID())
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	creationDates := make([]time.Time, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		creationDates = append(creationDates, snapshot.CreationDate)
+	}
+
+	retained := internalInstance.SnapshotsRetained(policy, creationDates)
+	for i, snapshot := range snapshots {
+		if retained[i] {
+			continue
+		}
+
+		err = pool.DeleteCustomVolumeSnapshot(volume.ProjectName, snapshot.Name, nil)
+		if err != nil {
+			return fmt.Errorf("Error deleting custom volume snapshot %q: %w", snapshot.Name, err)
+		}
+
+		logger.Debug("Deleted custom volume snapshot not kept by retention policy", logger.Ctx{"volName": snapshot.Name, "project": volume.ProjectName, "pool": volume.PoolName})
 	}
 
 	return nil
@@ -117,12 +117,23 @@ func storagePoolVolumeTypeStateGet(d *Daemon, r *http.Request) response.Response
 
 	// Fetch the current usage.
 	var usage *storagePools.VolumeUsage
+	var limits *storagePools.VolumeLimits
 	if volumeType == db.StoragePoolVolumeTypeCustom {
 		// Custom volumes.
 		usage, err = pool.GetCustomVolumeUsage(projectName, volumeName)
 		if err != nil && !errors.Is(err, storageDrivers.ErrNotSupported) {
 			return response.SmartError(err)
 		}
+
+		dbVolume, err := storagePools.VolumeDBGet(pool, projectName, volumeName, storageDrivers.VolumeTypeCustom)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		limits, err = storagePools.VolumeLimitsFromConfig(dbVolume.Config)
+		if err != nil {
+			return response.SmartError(err)
+		}
 	} else {
 		resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, volumeName)
 		if err != nil {
@@ -162,5 +173,14 @@ func storagePoolVolumeTypeStateGet(d *Daemon, r *http.Request) response.Response
 		}
 	}
 
+	if limits != nil {
+		state.Limits = &api.StorageVolumeStateLimits{
+			ReadBytes:  limits.ReadBytes,
+			ReadIOps:   limits.ReadIOps,
+			WriteBytes: limits.WriteBytes,
+			WriteIOps:  limits.WriteIOps,
+		}
+	}
+
 	return response.SyncResponse(true, state)
 }
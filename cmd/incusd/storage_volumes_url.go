@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/cancel"
+	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// volumeURLDownloadAttempts is the number of attempts at downloading the content of a volume,
+// each retry resuming the download where it stopped if the web server supports it.
+const volumeURLDownloadAttempts = 5
+
+// doVolumeImportURL creates a custom ISO volume from content downloaded by the server.
+func doVolumeImportURL(s *state.State, r *http.Request, requestProjectName string, projectName string, poolName string, req *api.StorageVolumesPost) response.Response {
+	if req.ContentType != db.StoragePoolVolumeContentTypeNameISO {
+		return response.BadRequest(errors.New("Only ISO volumes can be imported from a URL"))
+	}
+
+	if req.Source.URL == "" {
+		return response.BadRequest(errors.New("Missing URL"))
+	}
+
+	sourceURL, err := url.Parse(req.Source.URL)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid URL %q: %w", req.Source.URL, err))
+	}
+
+	if sourceURL.Scheme != "http" && sourceURL.Scheme != "https" {
+		return response.BadRequest(fmt.Errorf("Unsupported URL scheme %q", sourceURL.Scheme))
+	}
+
+	hashFunc, checksum, err := parseVolumeChecksum(req.Source.Checksum)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	run := func(op *operations.Operation) error {
+		// Create temporary file to store the downloaded ISO data.
+		isoFile, err := createISOTempFile()
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = os.Remove(isoFile.Name()) }()
+		defer func() { _ = isoFile.Close() }()
+
+		size, err := downloadVolumeURL(s, op, req.Source.URL, isoFile, hashFunc, checksum)
+		if err != nil {
+			return fmt.Errorf("Failed downloading %q: %w", req.Source.URL, err)
+		}
+
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			return err
+		}
+
+		// Dump ISO to storage.
+		err = pool.CreateCustomVolumeFromISO(projectName, req.Name, isoFile, size, op)
+		if err != nil {
+			return fmt.Errorf("Failed creating custom volume from ISO: %w", err)
+		}
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", "custom", req.Name)}
+
+	op, err := operations.OperationCreate(s, requestProjectName, operations.OperationClassTask, operationtype.VolumeCreate, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// parseVolumeChecksum parses a checksum of the form ALGORITHM:HASH, defaulting to sha256 when no algorithm is given.
+// It returns the hash function to use along with the expected hash, or a nil hash function if no checksum is given.
+func parseVolumeChecksum(checksum string) (hash.Hash, string, error) {
+	if checksum == "" {
+		return nil, "", nil
+	}
+
+	algorithm, value, found := strings.Cut(checksum, ":")
+	if !found {
+		algorithm = "sha256"
+		value = checksum
+	}
+
+	var hashFunc hash.Hash
+
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		hashFunc = sha256.New()
+	case "sha512":
+		hashFunc = sha512.New()
+	default:
+		return nil, "", fmt.Errorf("Unsupported checksum algorithm %q", algorithm)
+	}
+
+	value = strings.ToLower(value)
+
+	_, err := hex.DecodeString(value)
+	if err != nil || len(value) != hashFunc.Size()*2 {
+		return nil, "", fmt.Errorf("Invalid %s checksum %q", algorithm, value)
+	}
+
+	return hashFunc, value, nil
+}
+
+// downloadVolumeURL downloads the content at the URL into the target file and returns its size.
+// Interrupted downloads are resumed through range requests when the web server supports them,
+// and the checksum of the content is verified if a hash function is given.
+func downloadVolumeURL(s *state.State, op *operations.Operation, sourceURL string, target *os.File, hashFunc hash.Hash, checksum string) (int64, error) {
+	httpClient, err := localUtil.HTTPClient("", s.Proxy)
+	if err != nil {
+		return -1, err
+	}
+
+	canceler := cancel.NewHTTPRequestCanceller()
+	op.SetCanceler(canceler)
+
+	var size int64
+	var lastErr error
+
+	for attempt := 1; attempt <= volumeURLDownloadAttempts; attempt++ {
+		if attempt > 1 {
+			logger.Warn("Retrying interrupted download", logger.Ctx{"url": sourceURL, "offset": size, "attempt": attempt, "err": lastErr})
+
+			select {
+			case <-s.ShutdownCtx.Done():
+				return -1, s.ShutdownCtx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+
+		var retry bool
+		size, retry, lastErr = downloadVolumeURLAttempt(s.ShutdownCtx, httpClient, canceler, op, sourceURL, target, hashFunc, size)
+		if lastErr == nil {
+			break
+		}
+
+		if !retry {
+			return -1, lastErr
+		}
+	}
+
+	if lastErr != nil {
+		return -1, fmt.Errorf("Giving up after %d attempts: %w", volumeURLDownloadAttempts, lastErr)
+	}
+
+	if hashFunc != nil {
+		result := hex.EncodeToString(hashFunc.Sum(nil))
+		if result != checksum {
+			return -1, fmt.Errorf("Checksum mismatch: %s != %s", result, checksum)
+		}
+	}
+
+	return size, nil
+}
+
+// downloadVolumeURLAttempt downloads the content at the URL into the target file, starting at the given offset.
+// It returns the size of the content written so far and whether the download can be retried on error.
+func downloadVolumeURLAttempt(ctx context.Context, httpClient *http.Client, canceler *cancel.HTTPRequestCanceller, op *operations.Operation, sourceURL string, target *os.File, hashFunc hash.Hash, offset int64) (int64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", sourceURL, nil)
+	if err != nil {
+		return offset, false, err
+	}
+
+	req.Header.Set("User-Agent", version.UserAgent)
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, doneCh, err := cancel.CancelableDownload(canceler, httpClient.Do, req)
+	if err != nil {
+		return offset, !errors.Is(err, context.Canceled), err
+	}
+
+	defer close(doneCh)
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0 && strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)):
+		// Resume the download.
+	case resp.StatusCode == http.StatusOK:
+		// The web server doesn't support range requests, start over.
+		offset = 0
+
+		err = target.Truncate(0)
+		if err != nil {
+			return offset, false, err
+		}
+
+		if hashFunc != nil {
+			hashFunc.Reset()
+		}
+	default:
+		return offset, resp.StatusCode >= http.StatusInternalServerError, fmt.Errorf("Unable to fetch %q: %s", sourceURL, resp.Status)
+	}
+
+	_, err = target.Seek(offset, io.SeekStart)
+	if err != nil {
+		return offset, false, err
+	}
+
+	// Report the progress of the whole download, including the part fetched by previous attempts.
+	total := int64(-1)
+	if resp.ContentLength > 0 {
+		total = offset + resp.ContentLength
+	}
+
+	body := &ioprogress.ProgressReader{
+		ReadCloser: resp.Body,
+		Tracker: &ioprogress.ProgressTracker{
+			Length: resp.ContentLength,
+			Handler: func(value int64, speed int64) {
+				var text string
+				if total > 0 {
+					text = fmt.Sprintf("%d%% (%s/s)", (offset+value*resp.ContentLength/100)*100/total, units.GetByteSizeString(speed, 2))
+				} else {
+					text = fmt.Sprintf("%s (%s/s)", units.GetByteSizeString(offset+value, 2), units.GetByteSizeString(speed, 2))
+				}
+
+				_ = op.UpdateMetadata(map[string]any{"download_progress": text})
+			},
+		},
+	}
+
+	var writer io.Writer = target
+	if hashFunc != nil {
+		writer = io.MultiWriter(target, hashFunc)
+	}
+
+	n, err := io.Copy(writer, body)
+	if err != nil {
+		return offset + n, !errors.Is(err, context.Canceled), err
+	}
+
+	return offset + n, false, nil
+}
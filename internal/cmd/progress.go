@@ -188,7 +188,12 @@ func (p *ProgressRenderer) UpdateOp(op api.Operation) {
 			continue
 		}
 
-		p.Update(value.(string))
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		p.Update(text)
 		break
 	}
 }
@@ -250,6 +250,17 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 		return nil
 	},
 
+	// gendoc:generate(entity=instance, group=migration, key=migration.encryption)
+	// Encrypts the data connections of migrations (volume data and instance state) independently of the TLS setup
+	// between the servers, with keys exchanged over the control connection.
+	// With `preferred`, encryption is used if the other server supports it. With `required`, the migration fails otherwise.
+	// ---
+	//  type: string
+	//  defaultdesc: `none`
+	//  liveupdate: yes
+	//  shortdesc: Whether to encrypt the migration data connections
+	"migration.encryption": validate.Optional(validate.IsOneOf("none", "preferred", "required")),
+
 	// gendoc:generate(entity=instance, group=migration, key=migration.stateful)
 	// Enabling this option prevents the use of some features that are incompatible with it.
 	// ---
@@ -287,6 +298,24 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Whether `/dev/incus` is present in the instance
 	"security.guestapi": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=security, key=security.guestapi.tokens)
+	// See {ref}`dev-incus-tokens` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether the instance can get API tokens restricted to itself over `/dev/incus`
+	"security.guestapi.tokens": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=security, key=security.guestapi.snapshots)
+	// See {ref}`dev-incus-snapshots` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether the instance can snapshot its volumes over `/dev/incus`
+	"security.guestapi.snapshots": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=security, key=security.protection.delete)
 	//
 	// ---
@@ -329,6 +358,19 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Template for the snapshot name
 	"snapshots.pattern": validate.IsAny,
 
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.retention)
+	// Specify a comma-separated list of `<rule>=<count>`, where the rule is one of `last`, `hourly`, `daily`, `weekly`, `monthly` or `yearly`, for example `hourly=24,daily=7,weekly=4`.
+	// After each scheduled snapshot, only the most recent snapshot of each of the last `<count>` hours, days, weeks, months or years (in UTC) is kept, `last` keeping the most recent snapshots.
+	// All snapshots of the instance that no rule keeps are deleted, including manual ones.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Grandfather-father-son retention policy of the scheduled snapshots
+	"snapshots.retention": func(value string) error {
+		_, err := ParseSnapshotRetention(value)
+		return err
+	},
+
 	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.expiry)
 	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
 	// ---
@@ -341,6 +383,26 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 		return err
 	},
 
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.max_count)
+	// When the limit is reached, creating another snapshot fails unless `snapshots.max_count.expire_oldest` is enabled.
+	// This applies to both manual and scheduled snapshots.
+	// A `snapshots.max_count` limit set on the project also applies, the lowest of the two winning.
+	// ---
+	//  type: integer
+	//  defaultdesc: `0` (unlimited)
+	//  liveupdate: yes
+	//  shortdesc: Maximum number of snapshots of the instance
+	"snapshots.max_count": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.max_count.expire_oldest)
+	// When enabled, the oldest snapshots are deleted after creating a new one so that the instance stays within `snapshots.max_count`.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether to delete the oldest snapshots when reaching the maximum number of snapshots
+	"snapshots.max_count.expire_oldest": validate.Optional(validate.IsBool),
+
 	// Volatile keys.
 
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.apply_template)
@@ -357,6 +419,36 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Hash of the base image
 	"volatile.base_image": validate.IsAny,
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.base_image.alias)
+	// The image alias that the instance was created or last rebuilt from (empty if it was built from a fingerprint).
+	// `incus rebuild --latest-image` rebuilds the instance from the current target of this alias.
+	// ---
+	//  type: string
+	//  shortdesc: Alias of the base image
+	"volatile.base_image.alias": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.base_image.protocol)
+	// The protocol of the image server of {config:option}`instance-volatile:volatile.base_image.alias`.
+	// ---
+	//  type: string
+	//  shortdesc: Protocol of the base image server
+	"volatile.base_image.protocol": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.base_image.server)
+	// The image server of {config:option}`instance-volatile:volatile.base_image.alias` (empty for local images).
+	// ---
+	//  type: string
+	//  shortdesc: Server of the base image
+	"volatile.base_image.server": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.clone.source)
+	// The instance or snapshot that the instance was created from as an instant copy-on-write clone,
+	// in the `<project>/<instance>` form. The clone shares its data with the source on the storage pool.
+	// ---
+	//  type: string
+	//  shortdesc: Source of an instant copy
+	"volatile.clone.source": validate.IsAny,
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.cloud_init.instance-id)
 	//
 	// ---
@@ -614,6 +706,16 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 	//  shortdesc: Percentage of memory to have in sync before stopping the instance
 	"migration.incremental.memory.goal": validate.Optional(validate.IsUint32),
 
+	// gendoc:generate(entity=instance, group=migration, key=migration.incremental.memory.dirty_rate)
+	// The rate is the amount of memory dirtied per second between two memory transfers, for example `64MiB`.
+	// Incus stops the instance for the final transfer as soon as the rate is at or below this value.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  condition: container
+	//  shortdesc: Memory dirty rate per second under which to stop the instance
+	"migration.incremental.memory.dirty_rate": validate.Optional(validate.IsSize),
+
 	// gendoc:generate(entity=instance, group=nvidia, key=nvidia.runtime)
 	//
 	// ---
@@ -971,6 +1073,31 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to back the instance using huge pages
 	"limits.memory.hugepages": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=migration, key=migration.postcopy)
+	// When enabled and supported by the target, the instance resumes on the target after a first pass over its memory
+	// and the remaining memory pages are transferred on demand. This reduces downtime for memory-heavy workloads,
+	// but a failure of either server or of the network during the post-copy phase causes the instance to be lost.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to use post-copy memory transfer during live migration
+	"migration.postcopy": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=migration, key=migration.warm)
+	// When enabled and supported by the target, the root disk is transferred while the instance keeps running,
+	// the instance is then statefully stopped and only the writes that happened in the meantime and its state
+	// are transferred before it's started on the target. This doesn't rely on QEMU live migration but causes
+	// a longer pause of the instance.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to use warm migration instead of live migration
+	"migration.warm": validate.Optional(validate.IsBool),
+
 	// Caller is responsible for full validation of any raw.* value.
 
 	// gendoc:generate(entity=instance, group=raw, key=raw.qemu)
@@ -1118,6 +1245,43 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to use the name and MTU of the default network interfaces
 	"agent.nic_config": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=agent.clock_sync)
+	// The guest clock doesn't advance while the instance is paused or while its state is saved, so it lags behind after
+	// resuming, restoring a stateful snapshot or stop, or completing a live migration.
+	// Set this option to have the agent correct it at those times: `step` sets the guest clock to the host time right away,
+	// while `slew` gradually corrects offsets of up to half a second and steps larger ones.
+	// On x86_64, `slew` also has the emulated RTC catch up on missed ticks (applied on the next start).
+	// ---
+	//  type: string
+	//  defaultdesc: `none`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: How to correct the guest clock after pauses, state restores and live migrations
+	"agent.clock_sync": validate.Optional(validate.IsOneOf("none", "step", "slew")),
+
+	// gendoc:generate(entity=instance, group=miscellaneous, key=agent.disk_grow)
+	// When a disk of the running virtual machine is grown, have the agent grow its last partition and the filesystem mounted from it.
+	// This supports `ext4`, `xfs` and `btrfs` filesystems, and requires the `growpart` tool in the guest for partitioned disks.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to grow the guest partition and filesystem when a disk is grown
+	"agent.disk_grow": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=miscellaneous, key=console.serials)
+	// Comma-separated list of names of additional serial consoles.
+	// Each of them gets its own serial port in the guest and can be accessed with `incus console --serial <name>`.
+	// On `x86_64`, those are the serial ports following the main console (`ttyS1` onwards), up to three of them.
+	// On other architectures, those are virtio consoles (`hvc0` onwards).
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Names of additional serial consoles
+	"console.serials": validate.Optional(validate.IsListOf(validate.And(validate.IsHostname, validate.IsShorterThan(16)))),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.apply_nvram)
 	//
 	// ---
@@ -1257,6 +1421,15 @@ func ConfigKeyChecker(key string, instanceType api.InstanceType) (func(value str
 			return validate.IsListOf(validate.IsNetworkAddress), nil
 		}
 
+		// gendoc:generate(entity=instance, group=volatile, key=volatile.<name>.last_state.media_ejected)
+		// Set when the media of a removable drive was ejected while the instance was running.
+		// ---
+		//  type: bool
+		//  shortdesc: Whether the device media is ejected until the instance stops
+		if strings.HasSuffix(key, ".last_state.media_ejected") {
+			return validate.Optional(validate.IsBool), nil
+		}
+
 		// gendoc:generate(entity=instance, group=volatile, key=volatile.<name>.last_state.mtu)
 		// The original MTU that was used when moving a physical device into an instance.
 		// ---
@@ -1541,6 +1714,10 @@ func InstanceIncludeWhenCopying(configKey string, remoteCopy bool) bool {
 		return true // Include volatile.base_image always as it can help optimize copies.
 	}
 
+	if strings.HasPrefix(configKey, "volatile.base_image.") {
+		return true // Include the base image alias so that copies can be rebuilt from its latest image.
+	}
+
 	if configKey == "volatile.last_state.idmap" && !remoteCopy {
 		return true // Include volatile.last_state.idmap when doing local copy to avoid needless remapping.
 	}
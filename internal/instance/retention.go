@@ -0,0 +1,99 @@
+package instance
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// snapshotRetentionPeriods maps the retention rules to the function returning the period a snapshot date
+// belongs to. The "last" rule puts each snapshot in its own period.
+var snapshotRetentionPeriods = map[string]func(t time.Time) string{
+	"last":   func(t time.Time) string { return t.Format(time.RFC3339Nano) },
+	"hourly": func(t time.Time) string { return t.Format("2006-01-02 15") },
+	"daily":  func(t time.Time) string { return t.Format("2006-01-02") },
+	"weekly": func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	},
+	"monthly": func(t time.Time) string { return t.Format("2006-01") },
+	"yearly":  func(t time.Time) string { return t.Format("2006") },
+}
+
+// ParseSnapshotRetention parses a snapshot retention policy.
+// The policy format is a comma-separated list of "<rule>=<count>", where the rule is one of "last", "hourly",
+// "daily", "weekly", "monthly" or "yearly", e.g. "hourly=24,daily=7,weekly=4".
+func ParseSnapshotRetention(s string) (map[string]int, error) {
+	policy := map[string]int{}
+
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		rule, value, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("Invalid retention rule %q, expected <rule>=<count>", entry)
+		}
+
+		_, ok := snapshotRetentionPeriods[rule]
+		if !ok {
+			return nil, fmt.Errorf("Unknown retention rule %q", rule)
+		}
+
+		_, ok = policy[rule]
+		if ok {
+			return nil, fmt.Errorf("Retention rule %q is set multiple times", rule)
+		}
+
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("Invalid count %q for retention rule %q", value, rule)
+		}
+
+		policy[rule] = count
+	}
+
+	return policy, nil
+}
+
+// SnapshotsRetained returns which of the snapshots with the given creation dates are kept by the retention policy.
+// Each rule keeps the most recent snapshot of each of the last periods (in UTC) containing snapshots, up to its
+// count, and a snapshot is retained as soon as one of the rules keeps it.
+func SnapshotsRetained(policy map[string]int, creationDates []time.Time) []bool {
+	retained := make([]bool, len(creationDates))
+
+	// Go through the snapshots from the most recent one.
+	order := make([]int, len(creationDates))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a int, b int) int {
+		return creationDates[b].Compare(creationDates[a])
+	})
+
+	for rule, count := range policy {
+		period := snapshotRetentionPeriods[rule]
+		seen := map[string]bool{}
+
+		for _, i := range order {
+			if len(seen) >= count {
+				break
+			}
+
+			key := period(creationDates[i].UTC())
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+			retained[i] = true
+		}
+	}
+
+	return retained
+}
@@ -0,0 +1,48 @@
+package instance_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/instance"
+)
+
+func ExampleSnapshotsRetained() {
+	policy, err := instance.ParseSnapshotRetention("hourly=2, daily=3")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	start := time.Date(2024, 5, 10, 22, 0, 0, 0, time.UTC)
+
+	// Snapshots every 6 hours over 2 days.
+	dates := []time.Time{}
+	for i := range 9 {
+		dates = append(dates, start.Add(time.Duration(i)*6*time.Hour))
+	}
+
+	retained := instance.SnapshotsRetained(policy, dates)
+	for i, date := range dates {
+		fmt.Println(date.Format("2006-01-02 15:04"), retained[i])
+	}
+
+	for _, value := range []string{"hourly", "minutely=2", "daily=1,daily=2", "weekly=-1"} {
+		_, err := instance.ParseSnapshotRetention(value)
+		fmt.Println(err)
+	}
+
+	// Output: 2024-05-10 22:00 true
+	// 2024-05-11 04:00 false
+	// 2024-05-11 10:00 false
+	// 2024-05-11 16:00 false
+	// 2024-05-11 22:00 true
+	// 2024-05-12 04:00 false
+	// 2024-05-12 10:00 false
+	// 2024-05-12 16:00 true
+	// 2024-05-12 22:00 true
+	// Invalid retention rule "hourly", expected <rule>=<count>
+	// Unknown retention rule "minutely"
+	// Retention rule "daily" is set multiple times
+	// Invalid count "-1" for retention rule "weekly"
+}
@@ -0,0 +1,114 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ChecksumFilesystem returns a checksum of the directory tree at the given path.
+// It covers the name, type, permissions and content of each entry but not their ownership or times, as those may
+// legitimately differ between the source and the target of a transfer.
+// The entries listed in exclude, relative to the path, are skipped.
+func ChecksumFilesystem(path string, exclude []string) (string, error) {
+	h := sha256.New()
+
+	err := filepath.WalkDir(path, func(entryPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(path, entryPath)
+		if err != nil {
+			return err
+		}
+
+		// The root directory is created by the storage driver.
+		if relPath == "." {
+			return nil
+		}
+
+		if slices.Contains(exclude, relPath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(h, "%s\x00%o\x00", relPath, info.Mode())
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.Mode().IsRegular():
+			_, err = fmt.Fprintf(h, "%d\x00", info.Size())
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Open(entryPath)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(h, f)
+			_ = f.Close()
+			if err != nil {
+				return fmt.Errorf("Failed reading %q: %w", entryPath, err)
+			}
+
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(entryPath)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(h, "%s\x00", target)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ChecksumBlock returns a checksum of the first size bytes of the given block device or file.
+func ChecksumBlock(path string, size int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	h := sha256.New()
+
+	n, err := io.Copy(h, io.LimitReader(f, size))
+	if err != nil {
+		return "", fmt.Errorf("Failed reading %q: %w", path, err)
+	}
+
+	if n != size {
+		return "", fmt.Errorf("%q is smaller than the expected %d bytes", path, size)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,71 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksumFilesystem(t *testing.T) {
+	// newTree creates a directory tree whose files have the given content.
+	newTree := func(files map[string]string) string {
+		root := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(root, "rootfs"), 0o755))
+		require.NoError(t, os.Symlink("rootfs/a", filepath.Join(root, "link")))
+
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0o644))
+		}
+
+		return root
+	}
+
+	exclude := []string{"backup.yaml"}
+
+	reference, err := ChecksumFilesystem(newTree(map[string]string{"rootfs/a": "a", "backup.yaml": "source"}), exclude)
+	require.NoError(t, err)
+
+	// Excluded entries don't change the checksum.
+	same, err := ChecksumFilesystem(newTree(map[string]string{"rootfs/a": "a", "backup.yaml": "target"}), exclude)
+	require.NoError(t, err)
+	assert.Equal(t, reference, same)
+
+	// Content and names do.
+	changed, err := ChecksumFilesystem(newTree(map[string]string{"rootfs/a": "b", "backup.yaml": "source"}), exclude)
+	require.NoError(t, err)
+	assert.NotEqual(t, reference, changed)
+
+	renamed, err := ChecksumFilesystem(newTree(map[string]string{"rootfs/b": "a", "backup.yaml": "source"}), exclude)
+	require.NoError(t, err)
+	assert.NotEqual(t, reference, renamed)
+
+	// So do permissions.
+	root := newTree(map[string]string{"rootfs/a": "a", "backup.yaml": "source"})
+	require.NoError(t, os.Chmod(filepath.Join(root, "rootfs/a"), 0o600))
+
+	chmoded, err := ChecksumFilesystem(root, exclude)
+	require.NoError(t, err)
+	assert.NotEqual(t, reference, chmoded)
+}
+
+func TestChecksumBlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "root.img")
+	require.NoError(t, os.WriteFile(path, []byte("data-padding"), 0o600))
+
+	other := filepath.Join(t.TempDir(), "root.img")
+	require.NoError(t, os.WriteFile(other, []byte("data"), 0o600))
+
+	// Only the requested size is covered, allowing for larger target devices.
+	checksum, err := ChecksumBlock(path, 4)
+	require.NoError(t, err)
+
+	expected, err := ChecksumBlock(other, 4)
+	require.NoError(t, err)
+	assert.Equal(t, expected, checksum)
+
+	_, err = ChecksumBlock(other, 12)
+	assert.Error(t, err)
+}
@@ -0,0 +1,227 @@
+package migration
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Compression algorithms supported on the migration data connections.
+const (
+	CompressionNone = "none"
+	CompressionLZ4  = "lz4"
+	CompressionZstd = "zstd"
+)
+
+// lz4Levels maps the lz4 compression levels to their library value.
+var lz4Levels = []lz4.CompressionLevel{lz4.Fast, lz4.Level1, lz4.Level2, lz4.Level3, lz4.Level4, lz4.Level5, lz4.Level6, lz4.Level7, lz4.Level8, lz4.Level9}
+
+// ParseCompression parses a compression setting of the form "<algorithm>[:<level>]".
+// A level of 0 means that the default level of the algorithm is used.
+func ParseCompression(value string) (string, int, error) {
+	algorithm, levelStr, hasLevel := strings.Cut(value, ":")
+
+	if algorithm == "" {
+		algorithm = CompressionNone
+	}
+
+	level := 0
+	if hasLevel {
+		var err error
+
+		level, err = strconv.Atoi(levelStr)
+		if err != nil {
+			return "", 0, fmt.Errorf("Invalid compression level %q", levelStr)
+		}
+	}
+
+	switch algorithm {
+	case CompressionNone:
+		if hasLevel {
+			return "", 0, fmt.Errorf("Compression level can't be set without an algorithm")
+		}
+
+	case CompressionLZ4:
+		if level < 0 || level >= len(lz4Levels) {
+			return "", 0, fmt.Errorf("Compression level for %q must be between 0 and %d", algorithm, len(lz4Levels)-1)
+		}
+
+	case CompressionZstd:
+		if level < 0 || level > 22 {
+			return "", 0, fmt.Errorf("Compression level for %q must be between 0 and 22", algorithm)
+		}
+
+	default:
+		return "", 0, fmt.Errorf("Unsupported compression algorithm %q", algorithm)
+	}
+
+	return algorithm, level, nil
+}
+
+// ValidateCompression validates a compression setting.
+func ValidateCompression(value string) error {
+	_, _, err := ParseCompression(value)
+
+	return err
+}
+
+// flushWriter is a compressing writer which can flush its pending data.
+type flushWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// eofReader records whether the end of the underlying stream was reached.
+type eofReader struct {
+	r   io.Reader
+	eof bool
+}
+
+// Read reads from the underlying reader.
+func (r *eofReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+
+	return n, err
+}
+
+// compressedConn compresses the data written to a connection and decompresses the data read from it.
+//
+// Closing the connection ends the current compressed stream, so that connections which can carry
+// successive streams (such as the websocket wrapper with its barrier messages) keep working.
+type compressedConn struct {
+	conn io.ReadWriteCloser
+
+	readMu    sync.Mutex
+	newReader func(r io.Reader) (io.Reader, error)
+	reader    io.Reader
+	source    *eofReader
+
+	writeMu sync.Mutex
+	writer  flushWriter
+}
+
+// NewCompressedConn wraps the connection so that its data is compressed with the given compression setting.
+// Both ends of the connection must use the same algorithm.
+func NewCompressedConn(conn io.ReadWriteCloser, compression string) (io.ReadWriteCloser, error) {
+	algorithm, level, err := ParseCompression(compression)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &compressedConn{conn: conn}
+
+	switch algorithm {
+	case CompressionNone:
+		return conn, nil
+
+	case CompressionLZ4:
+		writer := lz4.NewWriter(conn)
+		err = writer.Apply(lz4.CompressionLevelOption(lz4Levels[level]))
+		if err != nil {
+			return nil, err
+		}
+
+		c.writer = writer
+		c.newReader = func(r io.Reader) (io.Reader, error) {
+			return lz4.NewReader(r), nil
+		}
+
+	case CompressionZstd:
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if level > 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+
+		c.writer, err = zstd.NewWriter(conn, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		c.newReader = func(r io.Reader) (io.Reader, error) {
+			return zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		}
+	}
+
+	return c, nil
+}
+
+// Read decompresses data from the connection.
+func (c *compressedConn) Read(p []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	// The decompressor is setup for each stream as it may start reading from the connection.
+	if c.reader == nil {
+		c.source = &eofReader{r: c.conn}
+
+		reader, err := c.newReader(c.source)
+		if err != nil {
+			return 0, err
+		}
+
+		c.reader = reader
+	}
+
+	n, err := c.reader.Read(p)
+	if err == io.EOF {
+		// Consume the end of the underlying stream if the decompressor stopped before it.
+		if !c.source.eof {
+			_, err := io.Copy(io.Discard, c.source)
+			if err != nil {
+				return n, err
+			}
+		}
+
+		c.reader = nil
+		c.source = nil
+
+		return n, io.EOF
+	}
+
+	return n, err
+}
+
+// Write compresses the data and sends it right away, as the peer may be waiting for it.
+func (c *compressedConn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	n, err := c.writer.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	err = c.writer.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Close ends the compressed stream and closes the connection.
+func (c *compressedConn) Close() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	err := c.writer.Close()
+	closeErr := c.conn.Close()
+
+	// Get ready for a new stream on the same connection.
+	c.writer.Reset(c.conn)
+
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
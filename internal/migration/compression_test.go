@@ -0,0 +1,126 @@
+package migration
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/ws"
+)
+
+func TestParseCompression(t *testing.T) {
+	tests := []struct {
+		value     string
+		algorithm string
+		level     int
+		valid     bool
+	}{
+		{value: "", algorithm: CompressionNone, valid: true},
+		{value: "none", algorithm: CompressionNone, valid: true},
+		{value: "lz4", algorithm: CompressionLZ4, valid: true},
+		{value: "lz4:9", algorithm: CompressionLZ4, level: 9, valid: true},
+		{value: "zstd:19", algorithm: CompressionZstd, level: 19, valid: true},
+		{value: "none:1"},
+		{value: "lz4:10"},
+		{value: "zstd:fast"},
+		{value: "gzip"},
+	}
+
+	for _, test := range tests {
+		algorithm, level, err := ParseCompression(test.value)
+		if !test.valid {
+			assert.Error(t, err, test.value)
+			continue
+		}
+
+		require.NoError(t, err, test.value)
+		assert.Equal(t, test.algorithm, algorithm, test.value)
+		assert.Equal(t, test.level, level, test.value)
+	}
+}
+
+func TestCompressedConn(t *testing.T) {
+	for _, compression := range []string{"lz4", "zstd:3"} {
+		t.Run(compression, func(t *testing.T) {
+			left, right := net.Pipe()
+
+			client, err := NewCompressedConn(left, compression)
+			require.NoError(t, err)
+
+			server, err := NewCompressedConn(right, compression)
+			require.NoError(t, err)
+
+			// Sparse disk content is mostly zeroes.
+			data := append(bytes.Repeat([]byte{0}, 1024*1024), []byte("end of data")...)
+
+			go func() {
+				_, _ = client.Write(data)
+				_ = client.Close()
+			}()
+
+			received, err := io.ReadAll(server)
+			require.NoError(t, err)
+			assert.Equal(t, data, received)
+
+			// Data must be readable as soon as it's written, in both directions.
+			left, right = net.Pipe()
+
+			client, err = NewCompressedConn(left, compression)
+			require.NoError(t, err)
+
+			server, err = NewCompressedConn(right, compression)
+			require.NoError(t, err)
+
+			go func() {
+				buf := make([]byte, 4)
+				_, err := io.ReadFull(server, buf)
+				if err == nil {
+					_, _ = server.Write(buf)
+				}
+			}()
+
+			_, err = client.Write([]byte("ping"))
+			require.NoError(t, err)
+
+			reply := make([]byte, 4)
+			_, err = io.ReadFull(client, reply)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("ping"), reply)
+		})
+	}
+}
+
+func TestCompressedConnStreams(t *testing.T) {
+	for _, compression := range []string{"lz4", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			clientConn, serverConn := resumePair(t)
+
+			defer func() { _ = clientConn.Close() }()
+			defer func() { _ = serverConn.Close() }()
+
+			client, err := NewCompressedConn(ws.NewWrapper(clientConn), compression)
+			require.NoError(t, err)
+
+			server, err := NewCompressedConn(ws.NewWrapper(serverConn), compression)
+			require.NoError(t, err)
+
+			// Closing the connection ends the stream, the next one being sent on the same connection.
+			go func() {
+				for i := range 3 {
+					_, _ = client.Write(resumeTestMessage(i))
+					_ = client.Close()
+				}
+			}()
+
+			for i := range 3 {
+				received, err := io.ReadAll(server)
+				require.NoError(t, err)
+				assert.Equal(t, resumeTestMessage(i), received)
+			}
+		})
+	}
+}
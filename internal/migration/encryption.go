@@ -0,0 +1,217 @@
+package migration
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Encryption modes of the migration data connections.
+const (
+	EncryptionNone      = "none"
+	EncryptionPreferred = "preferred"
+	EncryptionRequired  = "required"
+)
+
+// encryptionFrameSize is the maximum amount of data sealed in a single frame.
+const encryptionFrameSize = 64 * 1024
+
+// ValidateEncryption validates an encryption mode.
+func ValidateEncryption(value string) error {
+	switch value {
+	case "", EncryptionNone, EncryptionPreferred, EncryptionRequired:
+		return nil
+	}
+
+	return fmt.Errorf("Invalid encryption mode %q (must be %q, %q or %q)", value, EncryptionNone, EncryptionPreferred, EncryptionRequired)
+}
+
+// Encryption holds the key exchange state for the encryption of the migration data connections.
+//
+// Both servers generate an ephemeral X25519 key and exchange the public half in the migration header.
+// A separate key is then derived for each connection and direction from the shared secret.
+type Encryption struct {
+	private *ecdh.PrivateKey
+	secret  []byte
+	source  bool
+}
+
+// NewEncryption generates the ephemeral key used to agree on the encryption keys.
+// The source argument indicates whether the local server is the migration source.
+func NewEncryption(source bool) (*Encryption, error) {
+	private, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating migration encryption key: %w", err)
+	}
+
+	return &Encryption{private: private, source: source}, nil
+}
+
+// PublicKey returns the public key to send to the peer.
+func (e *Encryption) PublicKey() []byte {
+	return e.private.PublicKey().Bytes()
+}
+
+// SetPeerKey computes the shared secret from the public key of the peer.
+func (e *Encryption) SetPeerKey(key []byte) error {
+	peer, err := ecdh.X25519().NewPublicKey(key)
+	if err != nil {
+		return fmt.Errorf("Invalid migration encryption key: %w", err)
+	}
+
+	e.secret, err = e.private.ECDH(peer)
+	if err != nil {
+		return fmt.Errorf("Failed computing migration encryption secret: %w", err)
+	}
+
+	return nil
+}
+
+// aead returns the cipher for the named connection, in the direction of the given side.
+func (e *Encryption) aead(name string, source bool) (cipher.AEAD, error) {
+	side := "target"
+	if source {
+		side = "source"
+	}
+
+	key := make([]byte, 32)
+	_, err := io.ReadFull(hkdf.New(sha256.New, e.secret, nil, []byte("incus-migration/"+name+"/"+side)), key)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// NewEncryptedConn wraps the named connection so that its data is encrypted.
+// Both ends must use the same connection name.
+func (e *Encryption) NewEncryptedConn(conn io.ReadWriteCloser, name string) (io.ReadWriteCloser, error) {
+	if e.secret == nil {
+		return nil, errors.New("Migration encryption key exchange isn't complete")
+	}
+
+	writer, err := e.aead(name, e.source)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := e.aead(name, !e.source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedConn{conn: conn, writer: writer, reader: reader}, nil
+}
+
+// encryptedConn encrypts the data written to a connection and decrypts the data read from it.
+//
+// Data is sent in frames made of the length of the sealed data followed by the sealed data itself.
+// The frames are numbered in each direction so that they can't be replayed, reordered or dropped.
+// As frames don't span streams, connections which can carry successive streams (such as the
+// websocket wrapper with its barrier messages) keep working.
+type encryptedConn struct {
+	conn io.ReadWriteCloser
+
+	readMu      sync.Mutex
+	reader      cipher.AEAD
+	readCount   uint64
+	readPending []byte
+
+	writeMu    sync.Mutex
+	writer     cipher.AEAD
+	writeCount uint64
+}
+
+// nonce returns the nonce of the numbered frame.
+func (c *encryptedConn) nonce(aead cipher.AEAD, count uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], count)
+
+	return nonce
+}
+
+// Read decrypts data from the connection.
+func (c *encryptedConn) Read(p []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	if len(c.readPending) == 0 {
+		header := make([]byte, 4)
+		_, err := io.ReadFull(c.conn, header)
+		if err != nil {
+			// The end of the stream is only valid between frames.
+			return 0, err
+		}
+
+		size := binary.BigEndian.Uint32(header)
+		if size > encryptionFrameSize+uint32(c.reader.Overhead()) {
+			return 0, errors.New("Invalid encrypted migration frame")
+		}
+
+		sealed := make([]byte, size)
+		_, err = io.ReadFull(c.conn, sealed)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return 0, err
+		}
+
+		c.readPending, err = c.reader.Open(sealed[:0], c.nonce(c.reader, c.readCount), sealed, header)
+		if err != nil {
+			return 0, errors.New("Failed authenticating encrypted migration frame")
+		}
+
+		c.readCount++
+	}
+
+	n := copy(p, c.readPending)
+	c.readPending = c.readPending[n:]
+
+	return n, nil
+}
+
+// Write encrypts the data and sends it in as many frames as needed.
+func (c *encryptedConn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	written := 0
+	for written < len(p) {
+		chunk := p[written:min(len(p), written+encryptionFrameSize)]
+
+		frame := make([]byte, 4, 4+len(chunk)+c.writer.Overhead())
+		binary.BigEndian.PutUint32(frame, uint32(len(chunk)+c.writer.Overhead()))
+		frame = c.writer.Seal(frame, c.nonce(c.writer, c.writeCount), chunk, frame[:4])
+		c.writeCount++
+
+		_, err := c.conn.Write(frame)
+		if err != nil {
+			return written, err
+		}
+
+		written += len(chunk)
+	}
+
+	return written, nil
+}
+
+// Close closes the connection.
+func (c *encryptedConn) Close() error {
+	return c.conn.Close()
+}
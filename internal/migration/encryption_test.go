@@ -0,0 +1,126 @@
+package migration
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/ws"
+)
+
+// encryptionPair returns the key exchange state of both ends of a migration.
+func encryptionPair(t *testing.T) (*Encryption, *Encryption) {
+	source, err := NewEncryption(true)
+	require.NoError(t, err)
+
+	target, err := NewEncryption(false)
+	require.NoError(t, err)
+
+	require.NoError(t, source.SetPeerKey(target.PublicKey()))
+	require.NoError(t, target.SetPeerKey(source.PublicKey()))
+
+	return source, target
+}
+
+func TestEncryptedConn(t *testing.T) {
+	source, target := encryptionPair(t)
+
+	left, right := net.Pipe()
+
+	client, err := source.NewEncryptedConn(left, "fs")
+	require.NoError(t, err)
+
+	server, err := target.NewEncryptedConn(right, "fs")
+	require.NoError(t, err)
+
+	// Larger than a single frame.
+	data := append(bytes.Repeat([]byte("incus"), 64*1024), []byte("end of data")...)
+
+	go func() {
+		_, _ = client.Write(data)
+		_ = client.Close()
+	}()
+
+	received, err := io.ReadAll(server)
+	require.NoError(t, err)
+	assert.Equal(t, data, received)
+
+	// Data must be readable as soon as it's written, in both directions.
+	left, right = net.Pipe()
+
+	client, err = source.NewEncryptedConn(left, "criu")
+	require.NoError(t, err)
+
+	server, err = target.NewEncryptedConn(right, "criu")
+	require.NoError(t, err)
+
+	go func() {
+		buf := make([]byte, 4)
+		_, err := io.ReadFull(server, buf)
+		if err == nil {
+			_, _ = server.Write(buf)
+		}
+	}()
+
+	_, err = client.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(client, reply)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ping"), reply)
+}
+
+func TestEncryptedConnMismatch(t *testing.T) {
+	source, target := encryptionPair(t)
+
+	left, right := net.Pipe()
+
+	// Connections with different names use different keys.
+	client, err := source.NewEncryptedConn(left, "fs")
+	require.NoError(t, err)
+
+	server, err := target.NewEncryptedConn(right, "fs1")
+	require.NoError(t, err)
+
+	go func() {
+		_, _ = client.Write([]byte("secret"))
+		_ = client.Close()
+	}()
+
+	_, err = io.ReadAll(server)
+	assert.Error(t, err)
+}
+
+func TestEncryptedConnStreams(t *testing.T) {
+	source, target := encryptionPair(t)
+
+	clientConn, serverConn := resumePair(t)
+
+	defer func() { _ = clientConn.Close() }()
+	defer func() { _ = serverConn.Close() }()
+
+	client, err := source.NewEncryptedConn(ws.NewWrapper(clientConn), "fs")
+	require.NoError(t, err)
+
+	server, err := target.NewEncryptedConn(ws.NewWrapper(serverConn), "fs")
+	require.NoError(t, err)
+
+	// Closing the connection ends the stream, the next one being sent on the same connection.
+	go func() {
+		for i := range 3 {
+			_, _ = client.Write(resumeTestMessage(i))
+			_ = client.Close()
+		}
+	}()
+
+	for i := range 3 {
+		received, err := io.ReadAll(server)
+		require.NoError(t, err)
+		assert.Equal(t, resumeTestMessage(i), received)
+	}
+}
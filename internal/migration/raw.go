@@ -0,0 +1,256 @@
+package migration
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// RawTransportHeader is the HTTP header used to negotiate the raw TCP transport of migration data connections.
+// The client sets it to request the transport and the server replies with the port it listens on.
+const RawTransportHeader = "X-Incus-Migration-Raw"
+
+// RawTransportTokenHeader is the HTTP header holding the token the client must send on the raw TCP connection.
+const RawTransportTokenHeader = "X-Incus-Migration-Raw-Token"
+
+// rawTransportTimeout is how long to wait for the raw TCP connection to be established.
+const rawTransportTimeout = 10 * time.Second
+
+// rawTransportAck is sent by the server once it accepted the raw TCP connection.
+const rawTransportAck = 1
+
+// rawFrameHeaderSize is the size of the length preceding each frame.
+const rawFrameHeaderSize = 8
+
+// rawReadFromChunkSize is the amount of data sent in a single frame when copying from a reader.
+const rawReadFromChunkSize = 4 * 1024 * 1024
+
+// AcceptRawTransport waits on the listener for the connection carrying the expected token.
+// Connections presenting another token are dropped.
+func AcceptRawTransport(listener net.Listener, token string) (net.Conn, error) {
+	deadline := time.Now().Add(rawTransportTimeout)
+
+	tcpListener, ok := listener.(*net.TCPListener)
+	if ok {
+		_ = tcpListener.SetDeadline(deadline)
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		_ = conn.SetDeadline(deadline)
+
+		received := make([]byte, len(token))
+		_, err = io.ReadFull(conn, received)
+		if err != nil || subtle.ConstantTimeCompare(received, []byte(token)) != 1 {
+			_ = conn.Close()
+			continue
+		}
+
+		_, err = conn.Write([]byte{rawTransportAck})
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		_ = conn.SetDeadline(time.Time{})
+
+		return conn, nil
+	}
+}
+
+// DialRawTransport connects to the raw TCP transport offered by the server and presents the token.
+func DialRawTransport(address string, token string) (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", address, rawTransportTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(rawTransportTimeout))
+
+	_, err = conn.Write([]byte(token))
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	ack := make([]byte, 1)
+	_, err = io.ReadFull(conn, ack)
+	if err != nil || ack[0] != rawTransportAck {
+		_ = conn.Close()
+		return nil, errors.New("Raw migration connection wasn't accepted")
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	return conn, nil
+}
+
+// NewRawConn wraps a raw TCP connection so that it can carry successive streams.
+//
+// Data is sent in frames made of their length followed by the data itself, with an empty frame
+// marking the end of a stream. Like the websocket wrapper, closing the returned connection only ends
+// the current stream and reading returns io.EOF at the end of each stream.
+// As the data isn't transformed, copying to a file or a socket from it relies on splice.
+func NewRawConn(conn net.Conn) io.ReadWriteCloser {
+	return &rawConn{conn: conn}
+}
+
+// rawConn implements the framing of streams on a raw TCP connection.
+type rawConn struct {
+	conn net.Conn
+
+	readMu    sync.Mutex
+	remaining int64
+
+	writeMu sync.Mutex
+}
+
+// nextFrame reads the header of the next frame, returning io.EOF at the end of a stream.
+// Must be called with the read lock held.
+func (c *rawConn) nextFrame() error {
+	header := make([]byte, rawFrameHeaderSize)
+	_, err := io.ReadFull(c.conn, header)
+	if err != nil {
+		return err
+	}
+
+	size := binary.BigEndian.Uint64(header)
+	if size == 0 {
+		return io.EOF
+	}
+
+	c.remaining = int64(size)
+
+	return nil
+}
+
+// Read reads data of the current stream.
+func (c *rawConn) Read(p []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if c.remaining == 0 {
+		err := c.nextFrame()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := c.conn.Read(p[:min(int64(len(p)), c.remaining)])
+	c.remaining -= int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
+// WriteTo copies the rest of the current stream to the writer.
+func (c *rawConn) WriteTo(w io.Writer) (int64, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	var total int64
+	for {
+		if c.remaining == 0 {
+			err := c.nextFrame()
+			if err != nil {
+				if err == io.EOF {
+					return total, nil
+				}
+
+				return total, err
+			}
+		}
+
+		// Copying from a limited TCP connection lets files and sockets use splice.
+		n, err := io.Copy(w, &io.LimitedReader{R: c.conn, N: c.remaining})
+		total += n
+		c.remaining -= n
+		if err != nil {
+			return total, err
+		}
+
+		if c.remaining > 0 {
+			return total, io.ErrUnexpectedEOF
+		}
+	}
+}
+
+// writeFrame sends a frame without copying the data.
+// Must be called with the write lock held.
+func (c *rawConn) writeFrame(p []byte) error {
+	header := make([]byte, rawFrameHeaderSize)
+	binary.BigEndian.PutUint64(header, uint64(len(p)))
+
+	buffers := net.Buffers{header, p}
+	_, err := buffers.WriteTo(c.conn)
+
+	return err
+}
+
+// Write sends data on the current stream.
+func (c *rawConn) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	err := c.writeFrame(p)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// ReadFrom sends the data of the reader on the current stream in large frames.
+func (c *rawConn) ReadFrom(r io.Reader) (int64, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	buf := make([]byte, rawReadFromChunkSize)
+
+	var total int64
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			writeErr := c.writeFrame(buf[:n])
+			if writeErr != nil {
+				return total, writeErr
+			}
+
+			total += int64(n)
+		}
+
+		if err != nil {
+			if err == io.EOF {
+				return total, nil
+			}
+
+			return total, err
+		}
+	}
+}
+
+// Close ends the current stream without closing the connection so that it can be used for another stream.
+func (c *rawConn) Close() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return c.writeFrame(nil)
+}
@@ -0,0 +1,82 @@
+package migration
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawConnStreams(t *testing.T) {
+	left, right := net.Pipe()
+	defer left.Close()
+	defer right.Close()
+
+	sender := NewRawConn(left)
+	receiver := NewRawConn(right)
+
+	first := bytes.Repeat([]byte("a"), 3*rawReadFromChunkSize/2)
+	second := []byte("second stream")
+
+	go func() {
+		_, _ = io.Copy(sender, bytes.NewReader(first))
+		_ = sender.Close()
+
+		_, _ = sender.Write(second)
+		_ = sender.Close()
+	}()
+
+	// The first stream is read until its end, without returning data of the second one.
+	data, err := io.ReadAll(receiver)
+	require.NoError(t, err)
+	assert.Equal(t, first, data)
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, receiver)
+	require.NoError(t, err)
+	assert.Equal(t, second, buf.Bytes())
+}
+
+func TestRawTransportToken(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, err := AcceptRawTransport(listener, "good-token")
+		if err != nil {
+			close(accepted)
+			return
+		}
+
+		accepted <- conn
+	}()
+
+	// A connection with the wrong token is dropped.
+	_, err = DialRawTransport(listener.Addr().String(), "bad--token")
+	assert.Error(t, err)
+
+	conn, err := DialRawTransport(listener.Addr().String(), "good-token")
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	serverConn := <-accepted
+	require.NotNil(t, serverConn)
+
+	defer serverConn.Close()
+
+	go func() {
+		_, _ = conn.Write([]byte("hello"))
+	}()
+
+	data := make([]byte, 5)
+	_, err = io.ReadFull(serverConn, data)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), data)
+}
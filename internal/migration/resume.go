@@ -0,0 +1,573 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// ResumeHeader is the HTTP header used to negotiate resumable migration connections.
+const ResumeHeader = "X-Incus-Migration-Resume"
+
+// Frame types used on the websockets of a resumable connection.
+const (
+	resumeFrameHello byte = iota + 1
+	resumeFrameBinary
+	resumeFrameText
+	resumeFrameAck
+	resumeFrameClose
+)
+
+// resumeFrameHeaderSize is the size of the frame type and sequence number preceding each frame's payload.
+const resumeFrameHeaderSize = 9
+
+// resumeWindow is the maximum amount of unacknowledged data kept by the sender.
+const resumeWindow = 32 * 1024 * 1024
+
+// resumeAckBytes and resumeAckMessages control how often received messages are acknowledged.
+const (
+	resumeAckBytes    = 1024 * 1024
+	resumeAckMessages = 128
+)
+
+// resumeHandshakeTimeout is the maximum time to wait for the peer's hello on a new websocket.
+const resumeHandshakeTimeout = 10 * time.Second
+
+// ErrResumeImpossible is returned when a peer reconnects but the connection state cannot be recovered.
+var ErrResumeImpossible = errors.New("Migration connection cannot be resumed")
+
+type resumeMessage struct {
+	seq   uint64
+	frame byte
+	data  []byte
+}
+
+// ResumableConn is a message based connection which survives the loss of its underlying websocket.
+//
+// Every message is numbered and kept by the sender until the receiver acknowledges it. When the websocket
+// is lost, the dialing side reconnects (or the accepting side waits for the peer to do so) and both sides
+// exchange the number of messages they received so that the transfer continues from where it stopped.
+// If no new websocket is established within the resume timeout, the connection fails.
+type ResumableConn struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	// wmu serializes writes to the current websocket.
+	wmu sync.Mutex
+
+	conn       *websocket.Conn
+	connDone   chan struct{}
+	generation uint64
+	remoteAddr net.Addr
+	timeout    time.Duration
+	redial     func(ctx context.Context) (*websocket.Conn, error)
+
+	// Sending side, sendBuf holds the unacknowledged messages up to sendSeq.
+	sendBuf   []resumeMessage
+	sendBytes int
+	sendSeq   uint64
+	sendNext  uint64
+
+	// Receiving side.
+	recvQueue   []resumeMessage
+	recvSeq     uint64
+	ackSeq      uint64
+	ackBytes    int
+	ackMessages int
+	ackPending  bool
+
+	peerClosed bool
+	closed     bool
+	err        error
+}
+
+// NewResumableConn sets up a resumable connection on top of an established websocket.
+// The redial function is used to establish a new websocket after a connection loss, if nil the peer is
+// expected to reconnect and the new websocket must be provided through Resume.
+func NewResumableConn(conn *websocket.Conn, timeout time.Duration, redial func(ctx context.Context) (*websocket.Conn, error)) (*ResumableConn, error) {
+	c := &ResumableConn{
+		timeout: timeout,
+		redial:  redial,
+	}
+
+	c.cond = sync.NewCond(&c.mu)
+
+	err := c.attach(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	go c.writer()
+
+	return c, nil
+}
+
+// Resume replaces the underlying websocket with a new one established by the peer.
+func (c *ResumableConn) Resume(conn *websocket.Conn) error {
+	return c.attach(conn)
+}
+
+// NextReader returns the next message received from the peer.
+func (c *ResumableConn) NextReader() (int, io.Reader, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.recvQueue) == 0 && !c.closed && !c.peerClosed && c.err == nil {
+		c.cond.Wait()
+	}
+
+	if len(c.recvQueue) > 0 {
+		msg := c.recvQueue[0]
+		c.recvQueue[0] = resumeMessage{}
+		c.recvQueue = c.recvQueue[1:]
+
+		// Acknowledge consumed messages regularly so the peer can release them.
+		c.ackSeq = msg.seq + 1
+		c.ackBytes += len(msg.data)
+		c.ackMessages++
+		if c.ackBytes >= resumeAckBytes || c.ackMessages >= resumeAckMessages {
+			c.ackPending = true
+			c.ackBytes = 0
+			c.ackMessages = 0
+			c.cond.Broadcast()
+		}
+
+		messageType := websocket.BinaryMessage
+		if msg.frame == resumeFrameText {
+			messageType = websocket.TextMessage
+		}
+
+		return messageType, bytes.NewReader(msg.data), nil
+	}
+
+	if c.closed {
+		return 0, nil, net.ErrClosed
+	}
+
+	if c.err != nil {
+		return 0, nil, c.err
+	}
+
+	return 0, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}
+}
+
+// NextWriter returns a writer for the next message to send to the peer.
+// The message is queued once the writer is closed.
+func (c *ResumableConn) NextWriter(messageType int) (io.WriteCloser, error) {
+	return &resumeWriter{conn: c, messageType: messageType}, nil
+}
+
+// WriteMessage queues a message to be sent to the peer.
+// Writing a close message closes the connection.
+func (c *ResumableConn) WriteMessage(messageType int, data []byte) error {
+	var frame byte
+
+	switch messageType {
+	case websocket.BinaryMessage:
+		frame = resumeFrameBinary
+	case websocket.TextMessage:
+		frame = resumeFrameText
+	case websocket.CloseMessage:
+		return c.Close()
+	default:
+		return fmt.Errorf("Unsupported message type %d", messageType)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Wait for the peer to acknowledge enough data to fit the message.
+	for c.sendBytes > 0 && c.sendBytes+len(data) > resumeWindow && !c.closed && !c.peerClosed && c.err == nil {
+		c.cond.Wait()
+	}
+
+	if c.closed {
+		return net.ErrClosed
+	}
+
+	if c.err != nil {
+		return c.err
+	}
+
+	if c.peerClosed {
+		return websocket.ErrCloseSent
+	}
+
+	c.sendBuf = append(c.sendBuf, resumeMessage{seq: c.sendSeq, frame: frame, data: bytes.Clone(data)})
+	c.sendBytes += len(data)
+	c.sendSeq++
+	c.cond.Broadcast()
+
+	return nil
+}
+
+// SetWriteDeadline is a no-op as writes are buffered, how long a lost connection is waited for is instead
+// limited by the resume timeout.
+func (c *ResumableConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// RemoteAddr returns the address of the peer.
+func (c *ResumableConn) RemoteAddr() net.Addr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.remoteAddr
+}
+
+// Close notifies the peer and closes the connection, it cannot be resumed afterwards.
+func (c *ResumableConn) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+
+	c.closed = true
+	conn := c.conn
+	connDone := c.connDone
+	c.conn = nil
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	c.wmu.Lock()
+	_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_ = resumeWriteFrame(conn, resumeFrameClose, 0, nil)
+	c.wmu.Unlock()
+
+	// Wait for the peer to acknowledge the close so that it doesn't mistake it for a connection loss.
+	select {
+	case <-connDone:
+	case <-time.After(5 * time.Second):
+	}
+
+	return conn.Close()
+}
+
+// attach performs the handshake on a new websocket and makes it the active one.
+func (c *ResumableConn) attach(conn *websocket.Conn) error {
+	c.mu.Lock()
+	recvSeq := c.recvSeq
+	c.mu.Unlock()
+
+	peerSeq, err := resumeHandshake(conn, recvSeq)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("Failed migration connection handshake: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || c.err != nil {
+		_ = conn.Close()
+		return net.ErrClosed
+	}
+
+	// The peer must have received all the messages it acknowledged and no more than what was sent.
+	base := c.sendSeq - uint64(len(c.sendBuf))
+	if peerSeq < base || peerSeq > c.sendSeq {
+		_ = conn.Close()
+		return fmt.Errorf("%w: peer received %d messages, expected between %d and %d", ErrResumeImpossible, peerSeq, base, c.sendSeq)
+	}
+
+	// Release the messages which the peer already received.
+	for len(c.sendBuf) > 0 && c.sendBuf[0].seq < peerSeq {
+		c.sendBytes -= len(c.sendBuf[0].data)
+		c.sendBuf[0] = resumeMessage{}
+		c.sendBuf = c.sendBuf[1:]
+	}
+
+	if c.conn != nil {
+		// The peer reconnected before the loss of the previous websocket was detected.
+		_ = c.conn.Close()
+	}
+
+	c.conn = conn
+	c.connDone = make(chan struct{})
+	c.generation++
+	c.remoteAddr = conn.RemoteAddr()
+	c.sendNext = peerSeq
+	c.ackPending = c.ackSeq > 0
+	c.cond.Broadcast()
+
+	go c.reader(conn, c.connDone, c.generation)
+
+	return nil
+}
+
+// reader processes the frames received on a websocket until it fails or the peer closes the connection.
+func (c *ResumableConn) reader(conn *websocket.Conn, done chan struct{}, generation uint64) {
+	defer close(done)
+
+	for {
+		mt, r, err := conn.NextReader()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				c.mu.Lock()
+				c.peerClosed = true
+				c.cond.Broadcast()
+				c.mu.Unlock()
+
+				return
+			}
+
+			c.lost(generation, err)
+			return
+		}
+
+		data, err := io.ReadAll(r)
+		if err != nil {
+			c.lost(generation, err)
+			return
+		}
+
+		if mt != websocket.BinaryMessage || len(data) < resumeFrameHeaderSize {
+			c.fail(generation, errors.New("Invalid frame received on resumable migration connection"))
+			return
+		}
+
+		frame := data[0]
+		seq := binary.BigEndian.Uint64(data[1:resumeFrameHeaderSize])
+
+		c.mu.Lock()
+		if generation != c.generation {
+			c.mu.Unlock()
+			return
+		}
+
+		switch frame {
+		case resumeFrameBinary, resumeFrameText:
+			if seq > c.recvSeq {
+				c.mu.Unlock()
+				c.fail(generation, fmt.Errorf("Unexpected message %d received on resumable migration connection, expected %d", seq, c.recvSeq))
+				return
+			}
+
+			// Skip messages sent again by the peer which were already received.
+			if seq == c.recvSeq {
+				c.recvQueue = append(c.recvQueue, resumeMessage{seq: seq, frame: frame, data: data[resumeFrameHeaderSize:]})
+				c.recvSeq++
+			}
+
+		case resumeFrameAck:
+			for len(c.sendBuf) > 0 && c.sendBuf[0].seq < seq {
+				c.sendBytes -= len(c.sendBuf[0].data)
+				c.sendBuf[0] = resumeMessage{}
+				c.sendBuf = c.sendBuf[1:]
+			}
+
+		case resumeFrameClose:
+			c.peerClosed = true
+			closed := c.closed
+			c.cond.Broadcast()
+			c.mu.Unlock()
+
+			// Acknowledge the close if initiated by the peer.
+			if !closed {
+				c.wmu.Lock()
+				_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+				_ = resumeWriteFrame(conn, resumeFrameClose, 0, nil)
+				c.wmu.Unlock()
+			}
+
+			return
+		}
+
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	}
+}
+
+// writer sends the queued messages and acknowledgements on the active websocket.
+func (c *ResumableConn) writer() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		for !c.closed && c.err == nil && (c.conn == nil || (!c.ackPending && c.sendNext >= c.sendSeq)) {
+			c.cond.Wait()
+		}
+
+		if c.closed || c.err != nil {
+			return
+		}
+
+		conn := c.conn
+		generation := c.generation
+
+		var frame byte
+		var seq uint64
+		var data []byte
+
+		if c.ackPending {
+			frame = resumeFrameAck
+			seq = c.ackSeq
+			c.ackPending = false
+		} else {
+			msg := c.sendBuf[c.sendNext-(c.sendSeq-uint64(len(c.sendBuf)))]
+			frame = msg.frame
+			seq = msg.seq
+			data = msg.data
+			c.sendNext++
+		}
+
+		c.mu.Unlock()
+
+		c.wmu.Lock()
+		err := resumeWriteFrame(conn, frame, seq, data)
+		c.wmu.Unlock()
+
+		if err != nil {
+			c.lost(generation, err)
+		}
+
+		c.mu.Lock()
+	}
+}
+
+// lost handles the loss of a websocket and waits for a new one to be established.
+func (c *ResumableConn) lost(generation uint64, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation || c.conn == nil || c.closed || c.peerClosed || c.err != nil {
+		return
+	}
+
+	_ = c.conn.Close()
+	c.conn = nil
+	c.cond.Broadcast()
+
+	logger.Warn("Migration connection lost, waiting for it to be resumed", logger.Ctx{"remote": c.remoteAddr, "timeout": c.timeout, "err": err})
+
+	go c.resume(generation, time.Now().Add(c.timeout))
+}
+
+// resume re-establishes the websocket (if dialing) and fails the connection if not resumed before the deadline.
+func (c *ResumableConn) resume(generation uint64, deadline time.Time) {
+	for c.redial != nil && time.Now().Before(deadline) {
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		conn, err := c.redial(ctx)
+		cancel()
+
+		if err == nil {
+			err = c.attach(conn)
+			if err == nil {
+				logger.Info("Migration connection resumed", logger.Ctx{"remote": conn.RemoteAddr()})
+				return
+			}
+
+			if errors.Is(err, ErrResumeImpossible) || errors.Is(err, net.ErrClosed) {
+				c.fail(generation, err)
+				return
+			}
+		}
+
+		logger.Debug("Failed resuming migration connection", logger.Ctx{"err": err})
+
+		time.Sleep(min(time.Second, time.Until(deadline)))
+	}
+
+	time.Sleep(time.Until(deadline))
+
+	c.fail(generation, fmt.Errorf("Migration connection lost and not resumed within %s", c.timeout))
+}
+
+// fail marks the connection as failed unless a newer websocket was established in the meantime.
+func (c *ResumableConn) fail(generation uint64, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation || c.closed || c.err != nil {
+		return
+	}
+
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+
+	c.conn = nil
+	c.err = err
+	c.cond.Broadcast()
+}
+
+// resumeWriter buffers a message until it's closed.
+type resumeWriter struct {
+	conn        *ResumableConn
+	messageType int
+	buf         bytes.Buffer
+}
+
+func (w *resumeWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *resumeWriter) Close() error {
+	return w.conn.WriteMessage(w.messageType, w.buf.Bytes())
+}
+
+// resumeWriteFrame writes a single frame to a websocket.
+func resumeWriteFrame(conn *websocket.Conn, frame byte, seq uint64, data []byte) error {
+	w, err := conn.NextWriter(websocket.BinaryMessage)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, resumeFrameHeaderSize)
+	header[0] = frame
+	binary.BigEndian.PutUint64(header[1:], seq)
+
+	_, err = w.Write(header)
+	if err != nil {
+		_ = w.Close()
+		return err
+	}
+
+	if len(data) > 0 {
+		_, err = w.Write(data)
+		if err != nil {
+			_ = w.Close()
+			return err
+		}
+	}
+
+	return w.Close()
+}
+
+// resumeHandshake exchanges the number of received messages with the peer.
+func resumeHandshake(conn *websocket.Conn, recvSeq uint64) (uint64, error) {
+	_ = conn.SetWriteDeadline(time.Now().Add(resumeHandshakeTimeout))
+	err := resumeWriteFrame(conn, resumeFrameHello, recvSeq, nil)
+	_ = conn.SetWriteDeadline(time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(resumeHandshakeTimeout))
+	mt, data, err := conn.ReadMessage()
+	_ = conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	if mt != websocket.BinaryMessage || len(data) != resumeFrameHeaderSize || data[0] != resumeFrameHello {
+		return 0, errors.New("Invalid handshake received")
+	}
+
+	return binary.BigEndian.Uint64(data[1:]), nil
+}
@@ -0,0 +1,154 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resumePair returns both ends of a resumable connection, the client side redials the test server.
+func resumePair(t *testing.T) (*ResumableConn, *ResumableConn) {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	serverConns := make(chan *websocket.Conn)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		serverConns <- conn
+	}))
+
+	t.Cleanup(srv.Close)
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	dial := func(ctx context.Context) (*websocket.Conn, error) {
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+		return conn, err
+	}
+
+	var server *ResumableConn
+	serverReady := make(chan error, 1)
+
+	go func() {
+		conn := <-serverConns
+
+		var err error
+		server, err = NewResumableConn(conn, 10*time.Second, nil)
+		serverReady <- err
+
+		// Hand over any reconnection to the server side.
+		for conn := range serverConns {
+			_ = server.Resume(conn)
+		}
+	}()
+
+	conn, err := dial(context.Background())
+	require.NoError(t, err)
+
+	client, err := NewResumableConn(conn, 10*time.Second, dial)
+	require.NoError(t, err)
+	require.NoError(t, <-serverReady)
+
+	return client, server
+}
+
+// resumeTestMessage returns a large enough message for the transfer to still be ongoing when dropping the websocket.
+func resumeTestMessage(i int) []byte {
+	return append(fmt.Appendf(nil, "message %d:", i), bytes.Repeat([]byte{byte(i)}, 16*1024)...)
+}
+
+func TestResumableConn(t *testing.T) {
+	client, server := resumePair(t)
+
+	defer func() { _ = client.Close() }()
+	defer func() { _ = server.Close() }()
+
+	const count = 1000
+
+	go func() {
+		for i := range count {
+			_ = client.WriteMessage(websocket.BinaryMessage, resumeTestMessage(i))
+		}
+
+		_ = client.WriteMessage(websocket.TextMessage, nil)
+	}()
+
+	for i := range count {
+		// Drop the underlying websocket a few times during the transfer, from either side.
+		if i%250 == 100 {
+			side := client
+			if i > count/2 {
+				side = server
+			}
+
+			side.mu.Lock()
+			conn := side.conn
+			side.mu.Unlock()
+
+			if conn != nil {
+				_ = conn.UnderlyingConn().Close()
+			}
+		}
+
+		mt, r, err := server.NextReader()
+		require.NoError(t, err)
+		assert.Equal(t, websocket.BinaryMessage, mt)
+
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, resumeTestMessage(i), data)
+	}
+
+	mt, _, err := server.NextReader()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, mt)
+
+	// Closing one side ends the stream on the other side, once it's done resuming.
+	require.Eventually(t, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+
+		return client.conn != nil
+	}, 10*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, client.Close())
+
+	_, _, err = server.NextReader()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+}
+
+func TestResumableConnTimeout(t *testing.T) {
+	client, server := resumePair(t)
+
+	defer func() { _ = client.Close() }()
+
+	server.mu.Lock()
+	server.timeout = 100 * time.Millisecond
+	server.mu.Unlock()
+
+	// Lose the connection without the client being able to redial.
+	client.mu.Lock()
+	client.redial = nil
+	client.timeout = 100 * time.Millisecond
+	conn := client.conn
+	client.mu.Unlock()
+
+	_ = conn.UnderlyingConn().Close()
+
+	_, _, err := server.NextReader()
+	assert.ErrorContains(t, err, "not resumed")
+}
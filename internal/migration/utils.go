@@ -15,6 +15,12 @@ const ZFSFeatureMigrationHeader = "migration_header"
 // ZFSFeatureZvolFilesystems indicates migration can send/recv zvols.
 const ZFSFeatureZvolFilesystems = "header_zvol_filesystems"
 
+// ZFSFeatureResumeTokens indicates that interrupted refreshes can be resumed with ZFS resume tokens.
+const ZFSFeatureResumeTokens = "resume_tokens"
+
+// RsyncFeatureSparseBlocks indicates that block volumes are sent as data extents, skipping holes and zero filled blocks.
+const RsyncFeatureSparseBlocks = "sparse_blocks"
+
 // GetRsyncFeaturesSlice returns a slice of strings representing the supported RSYNC features.
 func (m *MigrationHeader) GetRsyncFeaturesSlice() []string {
 	features := []string{}
@@ -38,6 +44,10 @@ func (m *MigrationHeader) GetRsyncFeaturesSlice() []string {
 		if m.RsyncFeatures.Bidirectional != nil && *m.RsyncFeatures.Bidirectional {
 			features = append(features, "bidirectional")
 		}
+
+		if m.RsyncFeatures.SparseBlocks != nil && *m.RsyncFeatures.SparseBlocks {
+			features = append(features, RsyncFeatureSparseBlocks)
+		}
 	}
 
 	return features
@@ -62,6 +72,10 @@ func (m *MigrationHeader) GetZfsFeaturesSlice() []string {
 		if m.ZfsFeatures.HeaderZvols != nil && *m.ZfsFeatures.HeaderZvols {
 			features = append(features, ZFSFeatureZvolFilesystems)
 		}
+
+		if m.ZfsFeatures.ResumeTokens != nil && *m.ZfsFeatures.ResumeTokens {
+			features = append(features, ZFSFeatureResumeTokens)
+		}
 	}
 
 	return features
@@ -10,8 +10,14 @@ import (
 	internalIO "github.com/lxc/incus/v6/internal/io"
 )
 
+// Conn represents a message based connection, such as a websocket, used to exchange migration messages.
+type Conn interface {
+	NextReader() (int, io.Reader, error)
+	NextWriter(messageType int) (io.WriteCloser, error)
+}
+
 // ProtoRecv gets a protobuf message from a websocket.
-func ProtoRecv(ws *websocket.Conn, msg proto.Message) error {
+func ProtoRecv(ws Conn, msg proto.Message) error {
 	if ws == nil {
 		return errors.New("Empty websocket connection")
 	}
@@ -39,7 +45,7 @@ func ProtoRecv(ws *websocket.Conn, msg proto.Message) error {
 }
 
 // ProtoSend sends a protobuf message over a websocket.
-func ProtoSend(ws *websocket.Conn, msg proto.Message) error {
+func ProtoSend(ws Conn, msg proto.Message) error {
 	if ws == nil {
 		return errors.New("Empty websocket connection")
 	}
@@ -65,7 +71,7 @@ func ProtoSend(ws *websocket.Conn, msg proto.Message) error {
 }
 
 // ProtoSendControl sends a migration control message over a websocket.
-func ProtoSendControl(ws *websocket.Conn, err error) {
+func ProtoSendControl(ws Conn, err error) {
 	message := ""
 	if err != nil {
 		message = err.Error()
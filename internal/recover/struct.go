@@ -28,3 +28,27 @@ type ValidateResult struct {
 type ImportPost struct {
 	Pools []api.StoragePoolsPost `json:"pools" yaml:"pools"`
 }
+
+// StorageCheckPost is used to check storage pools for orphaned volumes.
+type StorageCheckPost struct {
+	Pools  []string `json:"pools" yaml:"pools"`   // Names of the pools to check, all pools if empty.
+	Action string   `json:"action" yaml:"action"` // What to do with the orphaned volumes (empty to only report them, adopt or delete).
+}
+
+// StorageCheckVolume provides info about an orphaned volume that the storage check found.
+type StorageCheckVolume struct {
+	Name        string `json:"name" yaml:"name"`                 // Name of volume.
+	Type        string `json:"type" yaml:"type"`                 // Type of volume (container, virtual-machine, custom or image).
+	ContentType string `json:"content_type" yaml:"content_type"` // Content type of volume (filesystem, block or iso).
+	Project     string `json:"project" yaml:"project"`           // Project the volume belongs to.
+	Pool        string `json:"pool" yaml:"pool"`                 // Pool the volume belongs to.
+	Missing     string `json:"missing" yaml:"missing"`           // What is missing for the volume (record or volume).
+	Action      string `json:"action" yaml:"action"`             // Action taken on the volume (adopted or deleted), if any.
+	Error       string `json:"error" yaml:"error"`               // Error of the action taken on the volume, if any.
+}
+
+// StorageCheckResult returns the result of the storage check.
+type StorageCheckResult struct {
+	Volumes []StorageCheckVolume `json:"volumes" yaml:"volumes"` // Orphaned volumes found.
+	Errors  []string             `json:"errors" yaml:"errors"`   // Errors that prevented checking some pools.
+}
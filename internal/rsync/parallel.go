@@ -0,0 +1,201 @@
+package rsync
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lxc/incus/v6/shared/ioprogress"
+)
+
+// SendParallel sends the directory pointed to by path over multiple connections at once.
+// The content is split in as many shards as there are connections, each being sent by its own rsync.
+// The first shard handles everything not explicitly assigned to another one.
+// The receiving end must call RecvParallel with the same number of connections.
+// If files isn't nil, it's called with the total number of files transferred by all the shards.
+func SendParallel(name string, path string, conns []io.ReadWriteCloser, tracker *ioprogress.ProgressTracker, files func(int64), features []string, bwlimit string, execPath string, rsyncArgs ...string) error {
+	if len(conns) < 2 {
+		return Send(name, path, conns[0], tracker, files, features, bwlimit, execPath, rsyncArgs...)
+	}
+
+	filters, err := shardFilters(path, len(conns))
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(conns))
+	wg := sync.WaitGroup{}
+
+	shardFiles := make([]int64, len(conns))
+	filesLock := sync.Mutex{}
+	shardFilesHandler := func(i int) func(int64) {
+		if files == nil {
+			return nil
+		}
+
+		return func(count int64) {
+			filesLock.Lock()
+			defer filesLock.Unlock()
+
+			shardFiles[i] = count
+
+			total := int64(0)
+			for _, count := range shardFiles {
+				total += count
+			}
+
+			files(total)
+		}
+	}
+
+	trackers := shardTrackers(tracker, len(conns))
+
+	for i, conn := range conns {
+		wg.Add(1)
+		go func(i int, conn io.ReadWriteCloser) {
+			defer wg.Done()
+
+			errs[i] = Send(name, path, conn, trackers[i], shardFilesHandler(i), features, bwlimit, execPath, append(slices.Clone(rsyncArgs), filters[i]...)...)
+		}(i, conn)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// RecvParallel receives the shards sent by SendParallel into the directory specified by path.
+func RecvParallel(path string, conns []io.ReadWriteCloser, tracker *ioprogress.ProgressTracker, features []string) error {
+	if len(conns) < 2 {
+		return Recv(path, conns[0], tracker, features)
+	}
+
+	errs := make([]error, len(conns))
+	wg := sync.WaitGroup{}
+
+	trackers := shardTrackers(tracker, len(conns))
+
+	for i, conn := range conns {
+		wg.Add(1)
+		go func(i int, conn io.ReadWriteCloser) {
+			defer wg.Done()
+
+			errs[i] = Recv(path, conn, trackers[i], features)
+		}(i, conn)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// shardTrackers returns a progress tracker for each shard, reporting the combined progress of all the shards
+// to tracker. The progress tracker isn't safe for concurrent use, so it can't be shared by the shards.
+func shardTrackers(tracker *ioprogress.ProgressTracker, count int) []*ioprogress.ProgressTracker {
+	trackers := make([]*ioprogress.ProgressTracker, count)
+	if tracker == nil || tracker.Handler == nil {
+		return trackers
+	}
+
+	lock := sync.Mutex{}
+	shardBytes := make([]int64, count)
+	shardSpeeds := make([]int64, count)
+
+	for i := range trackers {
+		// Without a length, the shard trackers report the number of bytes transferred so far.
+		trackers[i] = &ioprogress.ProgressTracker{
+			Handler: func(bytes int64, speed int64) {
+				lock.Lock()
+				defer lock.Unlock()
+
+				shardBytes[i] = bytes
+				shardSpeeds[i] = speed
+
+				var total, totalSpeed int64
+				for shard := range shardBytes {
+					total += shardBytes[shard]
+					totalSpeed += shardSpeeds[shard]
+				}
+
+				if tracker.Length > 0 {
+					tracker.Handler(min(1+total*100/tracker.Length, 100), totalSpeed)
+					return
+				}
+
+				tracker.Handler(total, totalSpeed)
+			},
+		}
+	}
+
+	return trackers
+}
+
+// shardFilters returns the rsync filter arguments splitting the content of path into count shards.
+//
+// The entries of the top-level directories (such as a container's rootfs) are spread over the shards.
+// Every shard but the first one only includes its own entries (and their parent directory), while the
+// first one excludes all the entries assigned to other shards. As excluded files are also protected from
+// deletion on the receiving end, the shards can safely be used together with the "delete" feature.
+func shardFilters(path string, count int) ([][]string, error) {
+	root, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the list of units to distribute, descending one level into real directories.
+	units := []string{}
+	for _, entry := range root {
+		if !entry.IsDir() {
+			units = append(units, entry.Name())
+			continue
+		}
+
+		children, err := os.ReadDir(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			units = append(units, entry.Name()+"/"+child.Name())
+		}
+	}
+
+	filters := make([][]string, count)
+	parents := make([][]string, count)
+
+	for i, unit := range units {
+		// Names with wildcard characters can't be safely matched, leave them to the first shard.
+		if strings.ContainsAny(unit, "*?[\\") {
+			continue
+		}
+
+		shard := i % count
+		if shard == 0 {
+			continue
+		}
+
+		parent, _, found := strings.Cut(unit, "/")
+		if found && !slices.Contains(parents[shard], parent) {
+			parents[shard] = append(parents[shard], parent)
+			filters[shard] = append(filters[shard], "--include=/"+parent+"/")
+		}
+
+		filters[shard] = append(filters[shard], "--include=/"+unit)
+		filters[0] = append(filters[0], "--exclude=/"+unit)
+	}
+
+	// Exclude everything else from the additional shards.
+	for shard := 1; shard < count; shard++ {
+		for _, parent := range parents[shard] {
+			filters[shard] = append(filters[shard], "--exclude=/"+parent+"/*")
+		}
+
+		filters[shard] = append(filters[shard], "--exclude=/*")
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,65 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/ioprogress"
+)
+
+func TestShardFilters(t *testing.T) {
+	path := t.TempDir()
+
+	for _, dir := range []string{"rootfs/etc", "rootfs/usr", "rootfs/var", "templates"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(path, dir), 0o755))
+	}
+
+	for _, file := range []string{"backup.yaml", "metadata.yaml", "rootfs/file[1]"} {
+		require.NoError(t, os.WriteFile(filepath.Join(path, file), nil, 0o644))
+	}
+
+	// Units are: backup.yaml, metadata.yaml, rootfs/etc, rootfs/file[1], rootfs/usr, rootfs/var.
+	filters, err := shardFilters(path, 3)
+	require.NoError(t, err)
+	require.Len(t, filters, 3)
+
+	assert.Equal(t, []string{"--exclude=/metadata.yaml", "--exclude=/rootfs/etc", "--exclude=/rootfs/usr", "--exclude=/rootfs/var"}, filters[0])
+	assert.Equal(t, []string{"--include=/metadata.yaml", "--include=/rootfs/", "--include=/rootfs/usr", "--exclude=/rootfs/*", "--exclude=/*"}, filters[1])
+	assert.Equal(t, []string{"--include=/rootfs/", "--include=/rootfs/etc", "--include=/rootfs/var", "--exclude=/rootfs/*", "--exclude=/*"}, filters[2])
+}
+
+func TestShardTrackers(t *testing.T) {
+	assert.Equal(t, []*ioprogress.ProgressTracker{nil, nil}, shardTrackers(nil, 2))
+
+	var progress, speed int64
+	tracker := &ioprogress.ProgressTracker{
+		Handler: func(p int64, s int64) {
+			progress = p
+			speed = s
+		},
+	}
+
+	trackers := shardTrackers(tracker, 2)
+	require.Len(t, trackers, 2)
+
+	trackers[0].Handler(100, 10)
+	trackers[1].Handler(50, 5)
+	assert.Equal(t, int64(150), progress)
+	assert.Equal(t, int64(15), speed)
+
+	trackers[0].Handler(200, 20)
+	assert.Equal(t, int64(250), progress)
+	assert.Equal(t, int64(25), speed)
+
+	// With a length, the combined progress is reported as a percentage.
+	tracker.Length = 1000
+	trackers = shardTrackers(tracker, 2)
+	trackers[0].Handler(300, 10)
+	trackers[1].Handler(200, 10)
+	assert.Equal(t, int64(51), progress)
+	assert.Equal(t, int64(20), speed)
+}
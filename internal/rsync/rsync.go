@@ -122,7 +122,7 @@ func LocalCopy(source string, dest string, bwlimit string, xattrs bool, rsyncArg
 	return msg, nil
 }
 
-func sendSetup(name string, path string, bwlimit string, execPath string, features []string, rsyncArgs ...string) (*exec.Cmd, net.Conn, io.ReadCloser, error) {
+func sendSetup(name string, path string, bwlimit string, execPath string, features []string, stdout io.Writer, rsyncArgs ...string) (*exec.Cmd, net.Conn, io.ReadCloser, error) {
 	/*
 	 * The way rsync works, it invokes a subprocess that does the actual
 	 * talking (given to it by a -E argument). Since there isn't an easy
@@ -198,6 +198,8 @@ func sendSetup(name string, path string, bwlimit string, execPath string, featur
 		defer cleanup()
 	}
 
+	cmd.Stdout = stdout
+
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
 		return nil, nil, nil, err
@@ -240,10 +242,38 @@ func sendSetup(name string, path string, bwlimit string, execPath string, featur
 	return cmd, *conn, stderr, nil
 }
 
+// fileCounter counts the lines of the rsync output, rsync printing one line per transferred file.
+type fileCounter struct {
+	handler func(int64)
+	count   int64
+	last    time.Time
+}
+
+// Write counts the lines and reports the current count at most once a second.
+func (c *fileCounter) Write(p []byte) (int, error) {
+	c.count += int64(bytes.Count(p, []byte("\n")))
+
+	if time.Since(c.last) >= time.Second {
+		c.last = time.Now()
+		c.handler(c.count)
+	}
+
+	return len(p), nil
+}
+
 // Send sets up the sending half of an rsync, to recursively send the
 // directory pointed to by path over the websocket.
-func Send(name string, path string, conn io.ReadWriteCloser, tracker *ioprogress.ProgressTracker, features []string, bwlimit string, execPath string, rsyncArgs ...string) error {
-	cmd, netcatConn, stderr, err := sendSetup(name, path, bwlimit, execPath, features, rsyncArgs...)
+// If files isn't nil, it's regularly called with the number of files transferred so far.
+func Send(name string, path string, conn io.ReadWriteCloser, tracker *ioprogress.ProgressTracker, files func(int64), features []string, bwlimit string, execPath string, rsyncArgs ...string) error {
+	var counter *fileCounter
+	var stdout io.Writer
+	if files != nil {
+		counter = &fileCounter{handler: files}
+		stdout = counter
+		rsyncArgs = append(slices.Clone(rsyncArgs), "--out-format=%n")
+	}
+
+	cmd, netcatConn, stderr, err := sendSetup(name, path, bwlimit, execPath, features, stdout, rsyncArgs...)
 	if err != nil {
 		return err
 	}
@@ -305,6 +335,10 @@ func Send(name string, path string, conn io.ReadWriteCloser, tracker *ioprogress
 		return fmt.Errorf("Rsync send failed: %s, %s: %v (%s)", name, path, errs, string(output))
 	}
 
+	if counter != nil {
+		files(counter.count)
+	}
+
 	return nil
 }
 
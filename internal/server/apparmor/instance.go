@@ -265,6 +265,7 @@ func instanceProfile(sysOS *sys.OS, inst instance, extraBinaries []string) (stri
 			"libraryPath":    strings.Split(os.Getenv("LD_LIBRARY_PATH"), ":"),
 			"logPath":        inst.LogPath(),
 			"runPath":        inst.RunPath(),
+			"shmemPath":      internalUtil.RunPath("shmem", inst.Project().Name),
 			"name":           InstanceProfileName(inst),
 			"path":           path,
 			"raw":            rawContent,
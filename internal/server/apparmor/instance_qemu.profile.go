@@ -71,6 +71,9 @@ profile "{{ .name }}" flags=(attach_disconnected,mediate_deleted) {
   {{ .path }}/** rwk,
   {{ .devicesPath }}/** rwk,
 
+  # Shared memory regions of the project
+  {{ .shmemPath }}/* rw,
+
   # Needed for the fork sub-commands
   {{ .exePath }} mr,
   @{PROC}/@{pid}/cmdline r,
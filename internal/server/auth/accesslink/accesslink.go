@@ -0,0 +1,110 @@
+// Package accesslink issues and parses the tokens of instance access links.
+//
+// Access links give the console and/or exec of a single instance to someone who isn't otherwise trusted.
+// Tokens are made of the link identifier and of a random secret, of which only a hash is stored.
+package accesslink
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// secretSize is the number of random bytes of the secrets.
+const secretSize = 32
+
+// NewToken returns a new token for the access link, along with the hash of its secret.
+func NewToken(linkID string) (string, string, error) {
+	secret := make([]byte, secretSize)
+
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	encoded := hex.EncodeToString(secret)
+
+	return linkID + "." + encoded, Hash(encoded), nil
+}
+
+// Hash returns the hash of the secret of a token, as stored in the database.
+func Hash(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// CheckHash returns whether the secret matches the stored hash.
+func CheckHash(secret string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(secret)), []byte(hash)) == 1
+}
+
+// parseToken returns the link identifier and the secret of a token.
+func parseToken(token string) (string, string, bool) {
+	linkID, secret, ok := strings.Cut(token, ".")
+	if !ok || len(secret) != 2*secretSize {
+		return "", "", false
+	}
+
+	_, err := uuid.Parse(linkID)
+	if err != nil {
+		return "", "", false
+	}
+
+	_, err = hex.DecodeString(secret)
+	if err != nil {
+		return "", "", false
+	}
+
+	return linkID, secret, true
+}
+
+// Parse returns the link identifier and the secret of the bearer token of the request.
+// The last return value is false if the request doesn't use an access link.
+func Parse(r *http.Request) (string, string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return "", "", false
+	}
+
+	return parseToken(token)
+}
+
+// ValidateAccess checks the access types of a link.
+func ValidateAccess(access []string) error {
+	if len(access) == 0 {
+		return api.StatusErrorf(http.StatusBadRequest, "Access links must allow console or exec")
+	}
+
+	for _, entry := range access {
+		if !slices.Contains([]string{api.InstanceAccessLinkAccessConsole, api.InstanceAccessLinkAccessExec}, entry) {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid access link access %q", entry)
+		}
+	}
+
+	return nil
+}
+
+// Username returns the user name of the requests authenticated with an access link.
+// It carries what the link allows, so that requests forwarded within a cluster remain restricted.
+func Username(projectName string, instanceName string, linkID string, access []string) string {
+	return strings.Join([]string{projectName, instanceName, linkID, strings.Join(access, ",")}, "/")
+}
+
+// ParseUsername returns the project, instance and access types of the user name of an access link.
+func ParseUsername(username string) (string, string, []string, bool) {
+	fields := strings.Split(username, "/")
+	if len(fields) != 4 || fields[0] == "" || fields[1] == "" || fields[3] == "" {
+		return "", "", nil, false
+	}
+
+	return fields[0], fields[1], strings.Split(fields[3], ","), true
+}
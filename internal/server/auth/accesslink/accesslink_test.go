@@ -0,0 +1,54 @@
+package accesslink
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(token string) *http.Request {
+	r := &http.Request{Header: http.Header{}}
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	return r
+}
+
+func TestToken(t *testing.T) {
+	token, hash, err := NewToken("8c9bd4a8-2ab5-4bcd-8273-0b8c3f2a3c3e")
+	require.NoError(t, err)
+
+	linkID, secret, ok := Parse(newRequest(token))
+	require.True(t, ok)
+	assert.Equal(t, "8c9bd4a8-2ab5-4bcd-8273-0b8c3f2a3c3e", linkID)
+	assert.True(t, CheckHash(secret, hash))
+	assert.False(t, CheckHash(secret[1:]+"0", hash))
+
+	_, _, ok = Parse(&http.Request{Header: http.Header{}})
+	assert.False(t, ok)
+
+	_, _, ok = Parse(newRequest("not-a-link"))
+	assert.False(t, ok)
+
+	_, _, ok = Parse(newRequest("header.payload.signature"))
+	assert.False(t, ok)
+}
+
+func TestParseUsername(t *testing.T) {
+	projectName, instanceName, access, ok := ParseUsername(Username("foo", "c1", "8c9bd4a8-2ab5-4bcd-8273-0b8c3f2a3c3e", []string{"console", "exec"}))
+	assert.True(t, ok)
+	assert.Equal(t, "foo", projectName)
+	assert.Equal(t, "c1", instanceName)
+	assert.Equal(t, []string{"console", "exec"}, access)
+
+	_, _, _, ok = ParseUsername("foo/c1")
+	assert.False(t, ok)
+}
+
+func TestValidateAccess(t *testing.T) {
+	assert.NoError(t, ValidateAccess([]string{"console"}))
+	assert.NoError(t, ValidateAccess([]string{"console", "exec"}))
+	assert.Error(t, ValidateAccess(nil))
+	assert.Error(t, ValidateAccess([]string{"file"}))
+}
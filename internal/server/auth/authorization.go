@@ -176,5 +176,6 @@ func LoadAuthorizer(ctx context.Context, driver string, logger logger.Logger, ce
 		return nil, fmt.Errorf("Failed to load authorizer: %w", err)
 	}
 
-	return d, nil
+	// Workload tokens and access links are restricted to their instance whatever the driver.
+	return &workloadAuthorizer{authorizer: &accessLinkAuthorizer{authorizer: d}}, nil
 }
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/lxc/incus/v6/internal/server/auth/accesslink"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// accessLinkAuthorizer restricts the requests authenticated with an instance access link to the console and/or
+// exec of its instance, regardless of the authorization driver. All other requests are handled by the driver.
+type accessLinkAuthorizer struct {
+	authorizer
+
+	common commonAuthorizer
+}
+
+// accessLinkAllowed returns whether the access link has the entitlement on the object.
+func accessLinkAllowed(username string, object Object, entitlement Entitlement) bool {
+	projectName, instanceName, access, ok := accesslink.ParseUsername(username)
+	if !ok {
+		return false
+	}
+
+	if object.Type() == ObjectTypeServer {
+		return entitlement == EntitlementCanView
+	}
+
+	if object.Project() != projectName {
+		return false
+	}
+
+	switch object.Type() {
+	case ObjectTypeProject:
+		return entitlement == EntitlementCanView
+	case ObjectTypeInstance:
+		if object.Elements()[0] != instanceName {
+			return false
+		}
+
+		switch entitlement {
+		case EntitlementCanView:
+			return true
+		case EntitlementCanAccessConsole:
+			return slices.Contains(access, api.InstanceAccessLinkAccessConsole)
+		case EntitlementCanExec:
+			return slices.Contains(access, api.InstanceAccessLinkAccessExec)
+		}
+	}
+
+	return false
+}
+
+// CheckPermission returns an error if the access link doesn't have the entitlement on the object.
+func (a *accessLinkAuthorizer) CheckPermission(ctx context.Context, r *http.Request, object Object, entitlement Entitlement) error {
+	details, err := a.common.requestDetails(r)
+	if err != nil || details.authenticationProtocol() != api.AuthenticationMethodAccessLink {
+		return a.authorizer.CheckPermission(ctx, r, object, entitlement)
+	}
+
+	if details.IsAllProjectsRequest || !accessLinkAllowed(details.username(), object, entitlement) {
+		return api.StatusErrorf(http.StatusForbidden, "Access link is restricted to the console and exec of its instance")
+	}
+
+	return nil
+}
+
+// GetPermissionChecker returns a function that checks whether the access link has the entitlement on an object.
+func (a *accessLinkAuthorizer) GetPermissionChecker(ctx context.Context, r *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
+	details, err := a.common.requestDetails(r)
+	if err != nil || details.authenticationProtocol() != api.AuthenticationMethodAccessLink {
+		return a.authorizer.GetPermissionChecker(ctx, r, entitlement, objectType)
+	}
+
+	if details.IsAllProjectsRequest {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Access link is restricted to the console and exec of its instance")
+	}
+
+	username := details.username()
+
+	return func(object Object) bool {
+		return accessLinkAllowed(username, object, entitlement)
+	}, nil
+}
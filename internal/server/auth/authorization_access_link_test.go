@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLinkAllowed(t *testing.T) {
+	console := "default/c1/8c9bd4a8-2ab5-4bcd-8273-0b8c3f2a3c3e/console"
+	both := "default/c1/8c9bd4a8-2ab5-4bcd-8273-0b8c3f2a3c3e/console,exec"
+
+	tests := []struct {
+		username    string
+		object      Object
+		entitlement Entitlement
+		allowed     bool
+	}{
+		{console, ObjectServer(), EntitlementCanView, true},
+		{console, ObjectServer(), EntitlementCanEdit, false},
+		{console, ObjectProject("default"), EntitlementCanView, true},
+		{console, ObjectProject("default"), EntitlementCanCreateInstances, false},
+		{console, ObjectProject("other"), EntitlementCanView, false},
+		{console, ObjectInstance("default", "c1"), EntitlementCanView, true},
+		{console, ObjectInstance("default", "c1"), EntitlementCanAccessConsole, true},
+		{console, ObjectInstance("default", "c1"), EntitlementCanExec, false},
+		{both, ObjectInstance("default", "c1"), EntitlementCanExec, true},
+		{both, ObjectInstance("default", "c1"), EntitlementCanEdit, false},
+		{both, ObjectInstance("default", "c1"), EntitlementCanUpdateState, false},
+		{both, ObjectInstance("default", "c2"), EntitlementCanView, false},
+		{both, ObjectInstance("other", "c1"), EntitlementCanView, false},
+		{both, ObjectStorageVolume("default", "pool", "container", "c1", ""), EntitlementCanView, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.allowed, accessLinkAllowed(test.username, test.object, test.entitlement), "%s %s %s", test.username, test.object, test.entitlement)
+	}
+
+	assert.False(t, accessLinkAllowed("default/c1", ObjectInstance("default", "c1"), EntitlementCanView))
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/lxc/incus/v6/internal/server/auth/workload"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// workloadInstanceEntitlements are the entitlements that workloads have on their own instance.
+var workloadInstanceEntitlements = []Entitlement{
+	EntitlementCanView,
+	EntitlementCanUpdateState,
+	EntitlementCanManageSnapshots,
+	EntitlementCanManageBackups,
+}
+
+// workloadAuthorizer restricts the requests authenticated with a workload token to the instance the token
+// was issued to, regardless of the authorization driver. All other requests are handled by the driver.
+type workloadAuthorizer struct {
+	authorizer
+
+	common commonAuthorizer
+}
+
+// workloadAllowed returns whether the workload has the entitlement on the object.
+func workloadAllowed(username string, object Object, entitlement Entitlement) bool {
+	projectName, instanceName, ok := workload.ParseUsername(username)
+	if !ok {
+		return false
+	}
+
+	if object.Type() == ObjectTypeServer {
+		return entitlement == EntitlementCanView
+	}
+
+	if object.Project() != projectName {
+		return false
+	}
+
+	elements := object.Elements()
+
+	switch object.Type() {
+	case ObjectTypeProject:
+		return entitlement == EntitlementCanView
+	case ObjectTypeInstance:
+		return elements[0] == instanceName && slices.Contains(workloadInstanceEntitlements, entitlement)
+	case ObjectTypeStorageVolume:
+		// Volumes of the instance itself, identified by pool, type and name.
+		if !slices.Contains([]string{"container", "virtual-machine"}, elements[1]) || elements[2] != instanceName {
+			return false
+		}
+
+		return entitlement == EntitlementCanView || entitlement == EntitlementCanManageSnapshots
+	}
+
+	return false
+}
+
+// CheckPermission returns an error if the workload doesn't have the entitlement on the object.
+func (w *workloadAuthorizer) CheckPermission(ctx context.Context, r *http.Request, object Object, entitlement Entitlement) error {
+	details, err := w.common.requestDetails(r)
+	if err != nil || details.authenticationProtocol() != api.AuthenticationMethodWorkload {
+		return w.authorizer.CheckPermission(ctx, r, object, entitlement)
+	}
+
+	if details.IsAllProjectsRequest || !workloadAllowed(details.username(), object, entitlement) {
+		return api.StatusErrorf(http.StatusForbidden, "Workload token is restricted to its instance")
+	}
+
+	return nil
+}
+
+// GetPermissionChecker returns a function that checks whether the workload has the entitlement on an object.
+func (w *workloadAuthorizer) GetPermissionChecker(ctx context.Context, r *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
+	details, err := w.common.requestDetails(r)
+	if err != nil || details.authenticationProtocol() != api.AuthenticationMethodWorkload {
+		return w.authorizer.GetPermissionChecker(ctx, r, entitlement, objectType)
+	}
+
+	if details.IsAllProjectsRequest {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Workload token is restricted to its instance")
+	}
+
+	username := details.username()
+
+	return func(object Object) bool {
+		return workloadAllowed(username, object, entitlement)
+	}, nil
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloadAllowed(t *testing.T) {
+	username := "default/c1"
+
+	tests := []struct {
+		object      Object
+		entitlement Entitlement
+		allowed     bool
+	}{
+		{ObjectServer(), EntitlementCanView, true},
+		{ObjectServer(), EntitlementCanEdit, false},
+		{ObjectProject("default"), EntitlementCanView, true},
+		{ObjectProject("default"), EntitlementCanCreateInstances, false},
+		{ObjectProject("other"), EntitlementCanView, false},
+		{ObjectInstance("default", "c1"), EntitlementCanManageSnapshots, true},
+		{ObjectInstance("default", "c1"), EntitlementCanEdit, false},
+		{ObjectInstance("default", "c1"), EntitlementCanExec, false},
+		{ObjectInstance("default", "c2"), EntitlementCanView, false},
+		{ObjectInstance("other", "c1"), EntitlementCanView, false},
+		{ObjectStorageVolume("default", "pool", "container", "c1", ""), EntitlementCanManageSnapshots, true},
+		{ObjectStorageVolume("default", "pool", "custom", "c1", ""), EntitlementCanView, false},
+		{ObjectStorageVolume("default", "pool", "container", "c1", ""), EntitlementCanEdit, false},
+		{ObjectStoragePool("pool"), EntitlementCanView, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.allowed, workloadAllowed(username, test.object, test.entitlement), "%s %s", test.object, test.entitlement)
+	}
+
+	assert.False(t, workloadAllowed("c1", ObjectInstance("default", "c1"), EntitlementCanView))
+}
@@ -0,0 +1,146 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// commandTimeout is the maximum time the authentication command is allowed to run for.
+const commandTimeout = 30 * time.Second
+
+// Verifier validates credentials by deferring to an external command.
+type Verifier struct {
+	command     string
+	cacheExpiry time.Duration
+
+	cache   map[[sha256.Size]byte]cacheEntry
+	cacheMu sync.Mutex
+}
+
+// cacheEntry records a successful authentication.
+type cacheEntry struct {
+	username string
+	expiry   time.Time
+}
+
+// Request is the JSON document provided to the authentication command on its standard input.
+type Request struct {
+	// Name of the user trying to authenticate.
+	Username string `json:"username"`
+
+	// Password provided by the user.
+	Password string `json:"password"`
+
+	// Address of the client.
+	RemoteAddress string `json:"remote_address"`
+}
+
+// AuthError represents an authentication error.
+type AuthError struct {
+	Err error
+}
+
+func (e AuthError) Error() string {
+	return fmt.Sprintf("Failed to authenticate: %s", e.Err.Error())
+}
+
+func (e AuthError) Unwrap() error {
+	return e.Err
+}
+
+// NewVerifier returns a Verifier running the provided command.
+// Successful authentications are cached for cacheExpiry (no caching if zero).
+func NewVerifier(command string, cacheExpiry time.Duration) *Verifier {
+	return &Verifier{
+		command:     command,
+		cacheExpiry: cacheExpiry,
+		cache:       map[[sha256.Size]byte]cacheEntry{},
+	}
+}
+
+// IsRequest checks if the request is using HTTP basic authentication.
+func (v *Verifier) IsRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Authorization"), "Basic ")
+}
+
+// Auth validates the credentials of the request and returns the username.
+//
+// The command is fed a JSON encoded Request on its standard input and must exit with a zero status
+// for the credentials to be accepted. It may print a username on its standard output to override the
+// one provided by the client (such as when mapping to a canonical directory name).
+func (v *Verifier) Auth(ctx context.Context, r *http.Request) (string, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok || username == "" {
+		return "", &AuthError{errors.New("Bad authorization header, expected basic credentials")}
+	}
+
+	key := sha256.Sum256([]byte(username + "\x00" + password))
+
+	// Check for a recent successful authentication.
+	if v.cacheExpiry > 0 {
+		v.cacheMu.Lock()
+		entry, found := v.cache[key]
+		v.cacheMu.Unlock()
+
+		if found && time.Now().Before(entry.expiry) {
+			return entry.username, nil
+		}
+	}
+
+	req, err := json.Marshal(Request{Username: username, Password: password, RemoteAddress: r.RemoteAddr})
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	err = subprocess.RunCommandWithFds(ctx, bytes.NewReader(req), &stdout, v.command)
+	if err != nil {
+		return "", &AuthError{fmt.Errorf("Credentials rejected for %q", username)}
+	}
+
+	// Allow the command to map the user to a different name.
+	canonical := strings.TrimSpace(stdout.String())
+	if canonical != "" {
+		username, _, _ = strings.Cut(canonical, "\n")
+		username = strings.TrimSpace(username)
+	}
+
+	if v.cacheExpiry > 0 {
+		v.cacheMu.Lock()
+		v.pruneCache()
+		v.cache[key] = cacheEntry{username: username, expiry: time.Now().Add(v.cacheExpiry)}
+		v.cacheMu.Unlock()
+	}
+
+	return username, nil
+}
+
+// WriteHeaders sets the headers asking the client to provide basic credentials.
+func (v *Verifier) WriteHeaders(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="Incus", charset="UTF-8"`)
+}
+
+// pruneCache removes expired entries from the cache.
+// Must be called with the cache lock held.
+func (v *Verifier) pruneCache() {
+	now := time.Now()
+
+	for key, entry := range v.cache {
+		if now.After(entry.expiry) {
+			delete(v.cache, key)
+		}
+	}
+}
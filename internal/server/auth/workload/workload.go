@@ -0,0 +1,159 @@
+// Package workload issues and verifies the API tokens handed to instances through /dev/incus.
+//
+// Tokens are JWTs signed with the server (or cluster) certificate and identify a single instance.
+// They let workloads call back into the API with permissions limited to their own instance.
+package workload
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// Issuer is the issuer of the workload tokens.
+const Issuer = "incus-workload"
+
+// Claims represents the claims of a workload token.
+// The subject is the UUID of the instance, which changes if the instance is re-created under the same name.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	Project  string `json:"project"`
+	Instance string `json:"instance"`
+}
+
+// signingMethod returns the JWT signing method matching the key of the certificate.
+func signingMethod(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			return jwt.SigningMethodES256, nil
+		case 384:
+			return jwt.SigningMethodES384, nil
+		case 521:
+			return jwt.SigningMethodES512, nil
+		}
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported certificate key type %T", key)
+}
+
+// NewToken returns a token for the instance, valid for the lifetime, and its expiry time.
+func NewToken(cert *localtls.CertInfo, projectName string, instanceName string, instanceUUID string, lifetime time.Duration) (string, time.Time, error) {
+	x509Cert, err := cert.PublicKeyX509()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	method, err := signingMethod(x509Cert.PublicKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(lifetime)
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Subject:   instanceUUID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Project:  projectName,
+		Instance: instanceName,
+	}
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(cert.KeyPair().PrivateKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Failed signing workload token: %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+// bearerToken returns the bearer token of the request, if any.
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return ""
+	}
+
+	return token
+}
+
+// IsRequest checks if the request is using a workload token.
+func IsRequest(r *http.Request) bool {
+	token := bearerToken(r)
+	if token == "" {
+		return false
+	}
+
+	claims := Claims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token, &claims)
+	if err != nil {
+		return false
+	}
+
+	return claims.Issuer == Issuer
+}
+
+// Verify checks the signature and validity of the workload token of the request and returns its claims.
+func Verify(cert *localtls.CertInfo, r *http.Request) (*Claims, error) {
+	x509Cert, err := cert.PublicKeyX509()
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := signingMethod(x509Cert.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := Claims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{method.Alg()}), jwt.WithIssuer(Issuer), jwt.WithExpirationRequired())
+
+	_, err = parser.ParseWithClaims(bearerToken(r), &claims, func(token *jwt.Token) (any, error) {
+		return x509Cert.PublicKey, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Invalid workload token: %w", err)
+	}
+
+	if claims.Project == "" || claims.Instance == "" || claims.Subject == "" {
+		return nil, errors.New("Invalid workload token: Missing instance")
+	}
+
+	return &claims, nil
+}
+
+// Username returns the user name of the requests authenticated with the token of the instance.
+func Username(projectName string, instanceName string) string {
+	return projectName + "/" + instanceName
+}
+
+// ParseUsername returns the project and instance of the user name of a workload.
+func ParseUsername(username string) (string, string, bool) {
+	projectName, instanceName, ok := strings.Cut(username, "/")
+	if !ok || projectName == "" || instanceName == "" {
+		return "", "", false
+	}
+
+	return projectName, instanceName, true
+}
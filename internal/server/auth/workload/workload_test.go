@@ -0,0 +1,62 @@
+package workload
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/tls/tlstest"
+)
+
+func newRequest(token string) *http.Request {
+	r := &http.Request{Header: http.Header{}}
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	return r
+}
+
+func TestToken(t *testing.T) {
+	cert := tlstest.TestingKeyPair(t)
+
+	token, expiresAt, err := NewToken(cert, "default", "c1", "4f4ea3b5-5fc8-4525-9a12-d2f2a0c2e4b8", time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	r := newRequest(token)
+	assert.True(t, IsRequest(r))
+
+	claims, err := Verify(cert, r)
+	require.NoError(t, err)
+	assert.Equal(t, "default", claims.Project)
+	assert.Equal(t, "c1", claims.Instance)
+	assert.Equal(t, "4f4ea3b5-5fc8-4525-9a12-d2f2a0c2e4b8", claims.Subject)
+
+	// Tokens signed by another certificate are rejected.
+	_, err = Verify(tlstest.TestingAltKeyPair(t), r)
+	assert.Error(t, err)
+
+	// Expired tokens are rejected.
+	token, _, err = NewToken(cert, "default", "c1", "4f4ea3b5-5fc8-4525-9a12-d2f2a0c2e4b8", -time.Minute)
+	require.NoError(t, err)
+
+	_, err = Verify(cert, newRequest(token))
+	assert.Error(t, err)
+}
+
+func TestIsRequest(t *testing.T) {
+	assert.False(t, IsRequest(&http.Request{Header: http.Header{}}))
+	assert.False(t, IsRequest(newRequest("not-a-jwt")))
+}
+
+func TestParseUsername(t *testing.T) {
+	projectName, instanceName, ok := ParseUsername(Username("foo", "c1"))
+	assert.True(t, ok)
+	assert.Equal(t, "foo", projectName)
+	assert.Equal(t, "c1", instanceName)
+
+	_, _, ok = ParseUsername("c1")
+	assert.False(t, ok)
+}
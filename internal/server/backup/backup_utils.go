@@ -5,8 +5,13 @@ import (
 	"context"
 	"errors"
 	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 
+	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/sys"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/archive"
 )
 
@@ -33,3 +38,99 @@ func TarReader(r io.ReadSeeker, sysOS *sys.OS, outputPath string) (*tar.Reader,
 
 	return tr, cancelFunc, nil
 }
+
+// ProjectUsage returns the disk space used by the backups of the instances, custom volumes and buckets
+// of a project which are stored on this server, whether in the backups directory or in the volume
+// configured through storage.backups_volume.
+func ProjectUsage(projectName string) (int64, error) {
+	var usage int64
+
+	// Instance backups are stored in a directory per instance.
+	entries, err := os.ReadDir(internalUtil.VarPath("backups", "instances"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return -1, err
+	}
+
+	for _, entry := range entries {
+		entryProject, _ := project.InstanceParts(entry.Name())
+		if entryProject != projectName {
+			continue
+		}
+
+		size, err := backupsDirSize(internalUtil.VarPath("backups", "instances", entry.Name()))
+		if err != nil {
+			return -1, err
+		}
+
+		usage += size
+	}
+
+	// Volume and bucket backups are stored in a directory per volume or bucket, grouped by pool.
+	for _, kind := range []string{"custom", "buckets"} {
+		pools, err := os.ReadDir(internalUtil.VarPath("backups", kind))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return -1, err
+		}
+
+		for _, pool := range pools {
+			entries, err := os.ReadDir(internalUtil.VarPath("backups", kind, pool.Name()))
+			if err != nil {
+				return -1, err
+			}
+
+			for _, entry := range entries {
+				entryProject, _ := project.StorageVolumeParts(entry.Name())
+				if entryProject != projectName {
+					continue
+				}
+
+				size, err := backupsDirSize(internalUtil.VarPath("backups", kind, pool.Name(), entry.Name()))
+				if err != nil {
+					return -1, err
+				}
+
+				usage += size
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// backupsDirSize returns the total size of the regular files in a backups directory.
+func backupsDirSize(path string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Backups may get deleted while walking the directory.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	return size, nil
+}
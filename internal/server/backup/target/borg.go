@@ -0,0 +1,131 @@
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// borgArchivePrefix is the prefix of the names of the archives created by Incus.
+const borgArchivePrefix = "incus"
+
+// borgList represents the output of "borg list --json".
+type borgList struct {
+	Archives []struct {
+		Name  string `json:"name"`
+		Start string `json:"start"`
+	} `json:"archives"`
+}
+
+// borg stores the backups as archives of a borg repository, named after the project, instance and
+// name of the backup.
+type borg struct {
+	repository string
+	password   string
+}
+
+func (t *borg) run(ctx context.Context, stdin io.Reader, stdout io.Writer, args ...string) (string, error) {
+	env := []string{"BORG_REPO=" + t.repository, "BORG_PASSPHRASE=" + t.password}
+
+	return run(ctx, env, stdin, stdout, "borg", args...)
+}
+
+// Store stores the backup tarball read from r as a new archive.
+func (t *borg) Store(ctx context.Context, point Point, r io.Reader) error {
+	_, err := t.run(ctx, r, nil, "create", "--stdin-name", backupFileName, "::"+borgArchiveName(point), "-")
+	if err != nil {
+		return fmt.Errorf("Failed storing backup in borg repository: %w", err)
+	}
+
+	return nil
+}
+
+// Points returns the archives of the project, limited to the given instance if not empty.
+func (t *borg) Points(ctx context.Context, projectName string, instanceName string) ([]Point, error) {
+	out, err := t.run(ctx, nil, nil, "list", "--json", "--glob-archives", fmt.Sprintf("%s_%s_*", borgArchivePrefix, projectName))
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing borg archives: %w", err)
+	}
+
+	points, err := borgParseList([]byte(out))
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]Point, 0, len(points))
+	for _, point := range points {
+		if point.Project != projectName || (instanceName != "" && point.Instance != instanceName) {
+			continue
+		}
+
+		filtered = append(filtered, point)
+	}
+
+	return filtered, nil
+}
+
+// Restore writes the backup tarball of the archive to w.
+func (t *borg) Restore(ctx context.Context, id string, w io.Writer) error {
+	_, err := t.run(ctx, nil, w, "extract", "--stdout", "::"+id, backupFileName)
+	if err != nil {
+		return fmt.Errorf("Failed restoring borg archive %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// Delete deletes the archive and frees the space only it used.
+func (t *borg) Delete(ctx context.Context, id string) error {
+	_, err := t.run(ctx, nil, nil, "delete", "::"+id)
+	if err != nil {
+		return fmt.Errorf("Failed deleting borg archive %q: %w", id, err)
+	}
+
+	// Older versions of borg free the space on delete and don't have the compact command.
+	_, _ = t.run(ctx, nil, nil, "compact")
+
+	return nil
+}
+
+// borgArchiveName returns the name of the archive of a point.
+// Project and instance names can't contain underscores, so they are used as separator.
+func borgArchiveName(point Point) string {
+	return strings.Join([]string{borgArchivePrefix, point.Project, point.Instance, point.Name}, "_")
+}
+
+// borgParseList converts the output of "borg list --json" to points, ignoring the archives not created by Incus.
+func borgParseList(data []byte) ([]Point, error) {
+	list := borgList{}
+
+	err := json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing borg archives: %w", err)
+	}
+
+	points := make([]Point, 0, len(list.Archives))
+	for _, archive := range list.Archives {
+		fields := strings.SplitN(archive.Name, "_", 4)
+		if len(fields) != 4 || fields[0] != borgArchivePrefix {
+			continue
+		}
+
+		// Borg reports times in the local time zone of the repository.
+		createdAt, err := time.ParseInLocation("2006-01-02T15:04:05.999999", archive.Start, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing start time of borg archive %q: %w", archive.Name, err)
+		}
+
+		points = append(points, Point{
+			ID:        archive.Name,
+			Project:   fields[1],
+			Instance:  fields[2],
+			Name:      fields[3],
+			CreatedAt: createdAt,
+		})
+	}
+
+	return points, nil
+}
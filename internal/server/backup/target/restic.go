@@ -0,0 +1,122 @@
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// resticSnapshot represents a snapshot as listed by "restic snapshots --json".
+type resticSnapshot struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+	Tags []string  `json:"tags"`
+}
+
+// restic stores the backups as snapshots of a restic repository, tagged with the project, instance and
+// name of the backup.
+type restic struct {
+	repository string
+	password   string
+}
+
+func (t *restic) run(ctx context.Context, stdin io.Reader, stdout io.Writer, args ...string) (string, error) {
+	env := []string{"RESTIC_REPOSITORY=" + t.repository, "RESTIC_PASSWORD=" + t.password}
+
+	return run(ctx, env, stdin, stdout, "restic", args...)
+}
+
+// Store stores the backup tarball read from r as a new snapshot.
+func (t *restic) Store(ctx context.Context, point Point, r io.Reader) error {
+	args := []string{"backup", "--quiet", "--stdin", "--stdin-filename", backupFileName, "--tag", "incus"}
+
+	for _, tag := range resticTags(point.Project, point.Instance, point.Name) {
+		args = append(args, "--tag", tag)
+	}
+
+	_, err := t.run(ctx, r, nil, args...)
+	if err != nil {
+		return fmt.Errorf("Failed storing backup in restic repository: %w", err)
+	}
+
+	return nil
+}
+
+// Points returns the snapshots of the project, limited to the given instance if not empty.
+func (t *restic) Points(ctx context.Context, projectName string, instanceName string) ([]Point, error) {
+	out, err := t.run(ctx, nil, nil, "snapshots", "--json", "--tag", strings.Join(append([]string{"incus"}, resticTags(projectName, instanceName, "")...), ","))
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing restic snapshots: %w", err)
+	}
+
+	return resticParseSnapshots([]byte(out))
+}
+
+// Restore writes the backup tarball of the snapshot to w.
+func (t *restic) Restore(ctx context.Context, id string, w io.Writer) error {
+	_, err := t.run(ctx, nil, w, "dump", id, "/"+backupFileName)
+	if err != nil {
+		return fmt.Errorf("Failed restoring restic snapshot %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// Delete forgets the snapshot and prunes the data only it used.
+func (t *restic) Delete(ctx context.Context, id string) error {
+	_, err := t.run(ctx, nil, nil, "forget", "--prune", id)
+	if err != nil {
+		return fmt.Errorf("Failed deleting restic snapshot %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// resticTags returns the tags identifying the project, instance and name of a backup.
+// Empty values are left out.
+func resticTags(projectName string, instanceName string, name string) []string {
+	tags := []string{}
+
+	for _, tag := range [][2]string{{"project", projectName}, {"instance", instanceName}, {"name", name}} {
+		if tag[1] != "" {
+			tags = append(tags, tag[0]+"="+tag[1])
+		}
+	}
+
+	return tags
+}
+
+// resticParseSnapshots converts the output of "restic snapshots --json" to points.
+func resticParseSnapshots(data []byte) ([]Point, error) {
+	snapshots := []resticSnapshot{}
+
+	err := json.Unmarshal(data, &snapshots)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing restic snapshots: %w", err)
+	}
+
+	points := make([]Point, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		point := Point{ID: snapshot.ID, CreatedAt: snapshot.Time}
+
+		for _, tag := range snapshot.Tags {
+			key, value, _ := strings.Cut(tag, "=")
+
+			switch key {
+			case "project":
+				point.Project = value
+			case "instance":
+				point.Instance = value
+			case "name":
+				point.Name = value
+			}
+		}
+
+		points = append(points, point)
+	}
+
+	return points, nil
+}
@@ -0,0 +1,78 @@
+package target
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// backupFileName is the name under which the backup tarball is stored in the repository.
+const backupFileName = "backup.tar"
+
+// Point represents a restorable point of an instance stored in a backup target.
+type Point struct {
+	ID        string
+	Name      string
+	Project   string
+	Instance  string
+	CreatedAt time.Time
+}
+
+// Target represents a repository that instance backups are stored in.
+type Target interface {
+	// Store stores the backup tarball read from r as a new point.
+	Store(ctx context.Context, point Point, r io.Reader) error
+
+	// Points returns the points of the project, limited to the given instance if not empty.
+	Points(ctx context.Context, projectName string, instanceName string) ([]Point, error)
+
+	// Restore writes the backup tarball of the point to w.
+	Restore(ctx context.Context, id string, w io.Writer) error
+
+	// Delete removes the point from the repository.
+	Delete(ctx context.Context, id string) error
+}
+
+// New returns the backup target for the given driver and repository.
+func New(driver string, repository string, password string) (Target, error) {
+	switch driver {
+	case "restic":
+		return &restic{repository: repository, password: password}, nil
+	case "borg":
+		return &borg{repository: repository, password: password}, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported backup target driver %q", driver)
+}
+
+// run runs a command with the given extra environment and optional stdin and stdout.
+// When stdout is nil, the output of the command is returned.
+func run(ctx context.Context, env []string, stdin io.Reader, stdout io.Writer, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = stdin
+
+	var output bytes.Buffer
+	var stderr bytes.Buffer
+
+	if stdout != nil {
+		cmd.Stdout = stdout
+	} else {
+		cmd.Stdout = &output
+	}
+
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", subprocess.NewRunError(name, args, err, nil, &stderr)
+	}
+
+	return output.String(), nil
+}
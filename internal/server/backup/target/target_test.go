@@ -0,0 +1,42 @@
+package target
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResticParseSnapshots(t *testing.T) {
+	data := `[{"time":"2026-10-14T12:00:00.5+00:00","paths":["/backup.tar"],"hostname":"server01","tags":["incus","project=default","instance=c1","name=daily"],"id":"4bba301e1b62dcf7c5e5d84e4b3a6bf2a2e1a1e5b4c3d2e1f0a9b8c7d6e5f4a3","short_id":"4bba301e"}]`
+
+	points, err := resticParseSnapshots([]byte(data))
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+
+	assert.Equal(t, "4bba301e1b62dcf7c5e5d84e4b3a6bf2a2e1a1e5b4c3d2e1f0a9b8c7d6e5f4a3", points[0].ID)
+	assert.Equal(t, "default", points[0].Project)
+	assert.Equal(t, "c1", points[0].Instance)
+	assert.Equal(t, "daily", points[0].Name)
+	assert.True(t, points[0].CreatedAt.Equal(time.Date(2026, time.October, 14, 12, 0, 0, 500000000, time.UTC)))
+
+	assert.Equal(t, []string{"project=default", "instance=c1"}, resticTags("default", "c1", ""))
+}
+
+func TestBorgParseList(t *testing.T) {
+	point := Point{Project: "default", Instance: "c1", Name: "backup_2026"}
+	assert.Equal(t, "incus_default_c1_backup_2026", borgArchiveName(point))
+
+	data := `{"archives":[{"archive":"incus_default_c1_backup_2026","id":"e3b0c442","name":"incus_default_c1_backup_2026","start":"2026-10-14T12:00:00.000000","time":"2026-10-14T12:00:00.000000"},{"archive":"home-2026","id":"98fc1c14","name":"home-2026","start":"2026-10-14T13:00:00.000000","time":"2026-10-14T13:00:00.000000"}]}`
+
+	points, err := borgParseList([]byte(data))
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+
+	assert.Equal(t, "incus_default_c1_backup_2026", points[0].ID)
+	assert.Equal(t, "default", points[0].Project)
+	assert.Equal(t, "c1", points[0].Instance)
+	assert.Equal(t, "backup_2026", points[0].Name)
+	assert.True(t, points[0].CreatedAt.Equal(time.Date(2026, time.October, 14, 12, 0, 0, 0, time.Local)))
+}
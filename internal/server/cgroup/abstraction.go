@@ -93,6 +93,21 @@ func (cg *CGroup) SetMemorySoftLimit(limit int64) error {
 	return ErrUnknownVersion
 }
 
+// SetMemoryLow sets the amount of memory protected from reclaim.
+func (cg *CGroup) SetMemoryLow(limit int64) error {
+	version := cgControllers["memory"]
+	switch version {
+	case Unavailable:
+		return ErrControllerMissing
+	case V1:
+		return ErrControllerMissing
+	case V2:
+		return cg.rw.Set(version, "memory", "memory.low", fmt.Sprintf("%d", limit))
+	}
+
+	return ErrUnknownVersion
+}
+
 // GetMemoryLimit return the hard limit for memory.
 func (cg *CGroup) GetMemoryLimit() (int64, error) {
 	version := cgControllers["memory"]
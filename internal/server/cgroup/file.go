@@ -62,6 +62,29 @@ func NewFileReadWriter(pid int, unifiedCapable bool) (*CGroup, error) {
 	return cg, nil
 }
 
+// NewSliceFileReadWriter returns a CGroup instance for the systemd slice holding the given process,
+// using the filesystem as its backend.
+func NewSliceFileReadWriter(pid int, unifiedCapable bool) (*CGroup, error) {
+	cg, err := NewFileReadWriter(pid, unifiedCapable)
+	if err != nil {
+		return nil, err
+	}
+
+	rw, ok := cg.rw.(*fileReadWriter)
+	if !ok {
+		return nil, ErrUnknownVersion
+	}
+
+	slice := filepath.Dir(rw.paths["unified"])
+	if !strings.HasSuffix(slice, ".slice") {
+		return nil, fmt.Errorf("Process %d isn't part of a systemd slice", pid)
+	}
+
+	rw.paths["unified"] = slice
+
+	return cg, nil
+}
+
 type fileReadWriter struct {
 	paths map[string]string
 }
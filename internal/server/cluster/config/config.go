@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -12,20 +14,47 @@ import (
 	"github.com/sirupsen/logrus"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/migration"
 	"github.com/lxc/incus/v6/internal/server/config"
 	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
 
+// MemberKeys lists the server configuration keys which can be overridden by cluster groups and members.
+var MemberKeys = []string{
+	"core.migration_compression",
+	"core.migration_concurrency",
+	"core.migration_control_timeout",
+	"core.migration_keepalive_interval",
+	"core.migration_raw_transport",
+	"core.migration_resume_timeout",
+	"core.migration_streams",
+	"core.migration_timeout",
+	"core.shutdown_timeout",
+	"instances.lxcfs.per_instance",
+	"instances.nic.host_name",
+	"instances.overcommit.cpu",
+	"instances.overcommit.memory",
+	"instances.reserved.cpu",
+	"instances.reserved.memory",
+}
+
 // Config holds cluster-wide configuration values.
 type Config struct {
-	tx *db.ClusterTx // DB transaction the values in this config are bound to.
-	m  config.Map    // Low-level map holding the config values.
+	tx        *db.ClusterTx     // DB transaction the values in this config are bound to.
+	m         config.Map        // Low-level map holding the config values.
+	overrides map[string]string // Values overridden by the cluster groups and the local member.
+	local     config.Map        // Low-level map holding the config values effective on the local member.
 }
 
 // Load loads a new Config object with the current cluster configuration
-// values fetched from the database.
+// values fetched from the database, along with the values overridden for
+// the local member by its cluster groups and its own configuration.
 func Load(ctx context.Context, tx *db.ClusterTx) (*Config, error) {
 	// Load current raw values from the database, any error is fatal.
 	values, err := tx.Config(ctx)
@@ -38,7 +67,123 @@ func Load(ctx context.Context, tx *db.ClusterTx) (*Config, error) {
 		return nil, fmt.Errorf("failed to load node config: %w", err)
 	}
 
-	return &Config{tx: tx, m: m}, nil
+	c := &Config{tx: tx, m: m}
+
+	c.overrides, err = loadMemberOverrides(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load member config overrides: %w", err)
+	}
+
+	err = c.loadLocal()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// loadMemberOverrides returns the server configuration values set by the cluster groups of the local member
+// and by the member itself. The member configuration takes precedence over its groups and, when several
+// groups set the same key, the value of the first group in alphabetical order is used.
+func loadMemberOverrides(ctx context.Context, tx *db.ClusterTx) (map[string]string, error) {
+	overrides := map[string]string{}
+
+	name, err := tx.GetLocalNodeName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return overrides, nil
+	}
+
+	member, err := tx.GetNodeByName(ctx, name)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return overrides, nil
+		}
+
+		return nil, err
+	}
+
+	return MemberOverrides(ctx, tx, member)
+}
+
+// MemberOverrides returns the server configuration values set by the cluster groups of the given member
+// and by the member itself, following the same precedence rules as for the local member.
+func MemberOverrides(ctx context.Context, tx *db.ClusterTx, member db.NodeInfo) (map[string]string, error) {
+	overrides := map[string]string{}
+
+	groups := slices.Clone(member.Groups)
+	slices.Sort(groups)
+
+	for _, groupName := range groups {
+		group, err := dbCluster.GetClusterGroup(ctx, tx.Tx(), groupName)
+		if err != nil {
+			return nil, err
+		}
+
+		groupConfig, err := dbCluster.GetClusterGroupConfig(ctx, tx.Tx(), group.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range MemberKeys {
+			_, found := overrides[key]
+			value, ok := groupConfig[key]
+			if ok && !found {
+				overrides[key] = value
+			}
+		}
+	}
+
+	for _, key := range MemberKeys {
+		value, ok := member.Config[key]
+		if ok {
+			overrides[key] = value
+		}
+	}
+
+	return overrides, nil
+}
+
+// loadLocal builds the configuration effective on the local member from the cluster-wide values and the overrides.
+func (c *Config) loadLocal() error {
+	values := c.m.Dump()
+	maps.Copy(values, c.overrides)
+
+	local, err := config.SafeLoad(ConfigSchema, values)
+	if err != nil {
+		return fmt.Errorf("failed to load member config: %w", err)
+	}
+
+	c.local = local
+
+	return nil
+}
+
+// ForMember returns a copy of the configuration holding the values effective on another member,
+// given the values overridden by its cluster groups and its own configuration.
+func (c *Config) ForMember(overrides map[string]string) (*Config, error) {
+	member := &Config{tx: c.tx, m: c.m, overrides: overrides}
+
+	err := member.loadLocal()
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// ValidateMemberKey validates a server configuration value set by a cluster group or member.
+func ValidateMemberKey(key string, value string) error {
+	if !slices.Contains(MemberKeys, key) {
+		return fmt.Errorf("Server configuration key %q can't be set on cluster groups or members", key)
+	}
+
+	_, err := config.Load(config.Schema{key: ConfigSchema[key]}, map[string]string{key: value})
+
+	return err
 }
 
 // BackupsCompressionAlgorithm returns the compression algorithm to use for backups.
@@ -46,6 +191,11 @@ func (c *Config) BackupsCompressionAlgorithm() string {
 	return c.m.GetString("backups.compression_algorithm")
 }
 
+// BackupsTarget returns the driver, repository and password of the backup target.
+func (c *Config) BackupsTarget() (string, string, string) {
+	return c.m.GetString("backups.target.driver"), c.m.GetString("backups.target.repository"), c.m.GetString("backups.target.password")
+}
+
 // MetricsAuthentication checks whether metrics API requires authentication.
 func (c *Config) MetricsAuthentication() bool {
 	return c.m.GetBool("core.metrics_authentication")
@@ -110,6 +260,11 @@ func (c *Config) OfflineThreshold() time.Duration {
 	return time.Duration(n) * time.Second
 }
 
+// EventsHistoryExpiryDays returns the number of days during which lifecycle events are kept in the database.
+func (c *Config) EventsHistoryExpiryDays() int64 {
+	return c.m.GetInt64("core.events_history_expiry")
+}
+
 // ImagesMinimalReplica returns the numbers of nodes for cluster images replication.
 func (c *Config) ImagesMinimalReplica() int64 {
 	return c.m.GetInt64("cluster.images_minimal_replica")
@@ -163,6 +318,12 @@ func (c *Config) NetworkOVNSSL() (string, string, string) {
 	return c.m.GetString("network.ovn.ca_cert"), c.m.GetString("network.ovn.client_cert"), c.m.GetString("network.ovn.client_key")
 }
 
+// Reports returns the schedule of the report generation, the URL the reports are pushed to and the number of days
+// during which the reports are kept.
+func (c *Config) Reports() (string, string, int64) {
+	return c.m.GetString("reports.schedule"), c.m.GetString("reports.webhook"), c.m.GetInt64("reports.expiry")
+}
+
 // LinstorControllerConnection returns the Linstor controller connection string.
 func (c *Config) LinstorControllerConnection() string {
 	return c.m.GetString("storage.linstor.controller_connection")
@@ -176,15 +337,79 @@ func (c *Config) LinstorSSL() (string, string, string) {
 // ShutdownTimeout returns the number of minutes to wait for running operation to complete
 // before the server shuts down.
 func (c *Config) ShutdownTimeout() time.Duration {
-	n := c.m.GetInt64("core.shutdown_timeout")
+	n := c.local.GetInt64("core.shutdown_timeout")
 	return time.Duration(n) * time.Minute
 }
 
+// MigrationResumeTimeout returns how long to wait for an interrupted migration to resume.
+func (c *Config) MigrationResumeTimeout() time.Duration {
+	n := c.local.GetInt64("core.migration_resume_timeout")
+	return time.Duration(n) * time.Second
+}
+
+// MigrationControlTimeout returns how long to wait for a write on the migration control connection.
+func (c *Config) MigrationControlTimeout() time.Duration {
+	n := c.local.GetInt64("core.migration_control_timeout")
+	return time.Duration(n) * time.Second
+}
+
+// MigrationKeepaliveInterval returns the interval of the pings sent on the migration control connection.
+func (c *Config) MigrationKeepaliveInterval() time.Duration {
+	n := c.local.GetInt64("core.migration_keepalive_interval")
+	return time.Duration(n) * time.Second
+}
+
+// MigrationTimeout returns the maximum duration of a migration.
+func (c *Config) MigrationTimeout() time.Duration {
+	n := c.local.GetInt64("core.migration_timeout")
+	return time.Duration(n) * time.Second
+}
+
+// MigrationRawTransport returns whether the migration data connections may use raw TCP connections.
+func (c *Config) MigrationRawTransport() bool {
+	return c.local.GetBool("core.migration_raw_transport")
+}
+
+// MigrationCompression returns the compression to use on the migration data connections.
+func (c *Config) MigrationCompression() string {
+	return c.local.GetString("core.migration_compression")
+}
+
+// MigrationConcurrency returns the maximum number of migrations sent at the same time, 0 meaning no limit.
+func (c *Config) MigrationConcurrency() int {
+	return int(c.local.GetInt64("core.migration_concurrency"))
+}
+
+// MigrationStreams returns the maximum number of parallel data connections to use for migrations.
+func (c *Config) MigrationStreams() int {
+	return int(c.local.GetInt64("core.migration_streams"))
+}
+
 // ImagesDefaultArchitecture returns the default architecture.
 func (c *Config) ImagesDefaultArchitecture() string {
 	return c.m.GetString("images.default_architecture")
 }
 
+// ImagesCacheUpstreams returns the URLs of the upstream image servers to act as a caching proxy for.
+func (c *Config) ImagesCacheUpstreams() []string {
+	return util.SplitNTrimSpace(c.m.GetString("images.cache.upstreams"), ",", -1, true)
+}
+
+// ImagesCacheProtocol returns the protocol used to talk to the upstream image servers.
+func (c *Config) ImagesCacheProtocol() string {
+	return c.m.GetString("images.cache.protocol")
+}
+
+// ImagesCacheRefreshIntervalHours returns the interval in hours at which the upstream catalogs are refreshed.
+func (c *Config) ImagesCacheRefreshIntervalHours() int64 {
+	return c.m.GetInt64("images.cache.refresh_interval")
+}
+
+// ImagesCacheMaxStaleHours returns the number of hours an upstream catalog keeps being used when its server can't be reached.
+func (c *Config) ImagesCacheMaxStaleHours() int64 {
+	return c.m.GetInt64("images.cache.max_stale")
+}
+
 // ImagesCompressionAlgorithm returns the compression algorithm to use for images.
 func (c *Config) ImagesCompressionAlgorithm() string {
 	return c.m.GetString("images.compression_algorithm")
@@ -207,7 +432,7 @@ func (c *Config) ImagesRemoteCacheExpiryDays() int64 {
 
 // InstancesNICHostname returns hostname mode to use for instance NICs.
 func (c *Config) InstancesNICHostname() string {
-	return c.m.GetString("instances.nic.host_name")
+	return c.local.GetString("instances.nic.host_name")
 }
 
 // InstancesPlacementScriptlet returns the instances placement scriptlet source code.
@@ -222,7 +447,34 @@ func (c *Config) AuthorizationScriptlet() string {
 
 // InstancesLXCFSPerInstance returns whether LXCFS should be run on a per-instance basis.
 func (c *Config) InstancesLXCFSPerInstance() bool {
-	return c.m.GetBool("instances.lxcfs.per_instance")
+	return c.local.GetBool("instances.lxcfs.per_instance")
+}
+
+// InstancesOvercommitCPU returns the ratio of committed to available CPU threads allowed on this member.
+// It returns 0 if no ratio is set.
+func (c *Config) InstancesOvercommitCPU() float64 {
+	return parseRatio(c.local.GetString("instances.overcommit.cpu"))
+}
+
+// InstancesOvercommitMemory returns the ratio of committed to available memory allowed on this member.
+// It returns 0 if no ratio is set.
+func (c *Config) InstancesOvercommitMemory() float64 {
+	return parseRatio(c.local.GetString("instances.overcommit.memory"))
+}
+
+// InstancesReservedCPU returns the number of CPU threads reserved for the host on this member.
+func (c *Config) InstancesReservedCPU() int64 {
+	return c.local.GetInt64("instances.reserved.cpu")
+}
+
+// InstancesReservedMemory returns the amount of memory in bytes reserved for the host on this member.
+func (c *Config) InstancesReservedMemory() int64 {
+	n, err := units.ParseByteSizeString(c.local.GetString("instances.reserved.memory"))
+	if err != nil {
+		return 0
+	}
+
+	return n
 }
 
 // LokiServer returns all the Loki settings needed to connect to a server.
@@ -299,6 +551,11 @@ func (c *Config) OIDCServer() (string, string, string, string, string) {
 	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim")
 }
 
+// ExternalAuthentication returns the external authentication command and cache expiry.
+func (c *Config) ExternalAuthentication() (string, time.Duration) {
+	return c.m.GetString("authentication.external.command"), time.Duration(c.m.GetInt64("authentication.external.cache_expiry")) * time.Second
+}
+
 // ClusterHealingThreshold returns the configured healing threshold, i.e. the
 // number of seconds after which an offline node will be evacuated automatically. If the config key
 // is set but its value is lower than cluster.offline_threshold it returns
@@ -436,6 +693,11 @@ func (c *Config) update(values map[string]string) (map[string]string, error) {
 		return nil, fmt.Errorf("cannot persist configuration changes: %w", err)
 	}
 
+	err = c.loadLocal()
+	if err != nil {
+		return nil, err
+	}
+
 	return changed, nil
 }
 
@@ -537,6 +799,31 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Compression algorithm to use for backups
 	"backups.compression_algorithm": {Default: "gzip", Validator: validate.IsCompressionAlgorithm},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.target.driver)
+	// Possible values are `restic` and `borg`.
+	// Instance backups can then be stored in the repository set in `backups.target.repository` instead of as tarballs on the server.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Tool used to store backups in the backup target
+	"backups.target.driver": {Validator: validate.Optional(validate.IsOneOf("restic", "borg"))},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.target.password)
+	// The password is used to encrypt and decrypt the backups stored in the repository.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Password of the backup target repository
+	"backups.target.password": {},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.target.repository)
+	// The repository must already be initialized, and is passed to the tool as is (for example, `sftp:backup@host:/srv/restic` or `ssh://backup@host/./borg`).
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Repository of the backup target
+	"backups.target.repository": {},
+
 	// gendoc:generate(entity=server, group=cluster, key=cluster.offline_threshold)
 	// Specify the number of seconds after which an unresponsive member is considered offline.
 	// ---
@@ -648,6 +935,17 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: BGP Autonomous System Number for the local server
 	"core.bgp_asn": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 4294967294))},
 
+	// gendoc:generate(entity=server, group=core, key=core.events_history_expiry)
+	// Specify the number of days during which lifecycle events are kept in the database.
+	// Recorded events can be replayed by event listeners connecting with the `since` parameter (see {ref}`events-history`).
+	// Set this option to `0` to not record lifecycle events.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: How long to keep lifecycle events for replay
+	"core.events_history_expiry": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 3650))},
+
 	// gendoc:generate(entity=server, group=core, key=core.https_allowed_headers)
 	//
 	// ---
@@ -689,6 +987,92 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Trusted servers to provide the client's address
 	"core.https_trusted_proxy": {},
 
+	// gendoc:generate(entity=server, group=core, key=core.migration_resume_timeout)
+	// Specify the number of seconds during which an interrupted migration can be resumed by re-establishing its connections.
+	// Both servers must support resuming migrations and have this option set for it to take effect.
+	// Set this option to `0` to abort migrations as soon as a connection is lost.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: How long to wait for an interrupted migration to resume
+	"core.migration_resume_timeout": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 86400))},
+
+	// gendoc:generate(entity=server, group=core, key=core.migration_control_timeout)
+	// Specify the number of seconds to wait for a message to be sent on the control connection of a migration, and for that connection to be established.
+	// Increase this value for migrations over congested or high latency networks.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `30`
+	//  shortdesc: How long to wait on the migration control connection
+	"core.migration_control_timeout": {Type: config.Int64, Default: "30", Validator: validate.Optional(validate.IsInRange(1, 3600))},
+
+	// gendoc:generate(entity=server, group=core, key=core.migration_keepalive_interval)
+	// Specify the number of seconds between the pings sent on the control connection of a migration.
+	// This keeps the connection alive through firewalls and NAT gateways that drop idle connections while the volumes are transferred.
+	// Set this option to `0` to not send any pings.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Interval of the pings on the migration control connection
+	"core.migration_keepalive_interval": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 3600))},
+
+	// gendoc:generate(entity=server, group=core, key=core.migration_timeout)
+	// Specify the maximum number of seconds a migration can take, after which it's aborted on both servers.
+	// Set this option to `0` to not limit the duration of migrations.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Maximum duration of a migration
+	"core.migration_timeout": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 604800))},
+
+	// gendoc:generate(entity=server, group=core, key=core.migration_compression)
+	// Specify the compression used on the volume data connections of migrations sent by this server, in the form `<algorithm>[:<level>]`.
+	// Supported algorithms are `none`, `lz4` (levels 0 to 9) and `zstd` (levels 1 to 22).
+	// Compression is only used if the target server also has a value other than `none` and knows about the algorithm.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `none`
+	//  shortdesc: Compression of migration data connections
+	"core.migration_compression": {Default: "none", Validator: migration.ValidateCompression},
+
+	// gendoc:generate(entity=server, group=core, key=core.migration_concurrency)
+	// Specify the maximum number of instance migrations sent by this server at the same time.
+	// Further migrations wait until one of the running migrations completes and report their position in the `queue` key of their operation metadata.
+	// Set this option to `0` to not limit the number of migrations.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Maximum number of simultaneous outgoing migrations
+	"core.migration_concurrency": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 1024))},
+
+	// gendoc:generate(entity=server, group=core, key=core.migration_raw_transport)
+	// Carry the data connections of migrations over plain TCP connections instead of websockets.
+	// This avoids the overhead of the websocket framing but the data isn't protected by TLS, so only enable it on trusted networks or together with {config:option}`instance-migration:migration.encryption`.
+	// Both servers must have this option enabled for it to take effect and the server receiving the data connections must be reachable on any TCP port.
+	// The control connection always uses a websocket, and resumable connections take precedence over the raw TCP transport.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to use raw TCP connections for migration data
+	"core.migration_raw_transport": {Type: config.Bool, Default: "false"},
+
+	// gendoc:generate(entity=server, group=core, key=core.migration_streams)
+	// Specify the maximum number of parallel connections used to transfer volume data during migrations.
+	// The number actually used is the lowest value of both servers and only applies to non-optimized transfers (`rsync` and raw block data).
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `1`
+	//  shortdesc: Number of parallel data connections for migrations
+	"core.migration_streams": {Type: config.Int64, Default: "1", Validator: validate.Optional(validate.IsInRange(1, 16))},
+
 	// gendoc:generate(entity=server, group=core, key=core.proxy_http)
 	// If this option is not specified, the daemon falls back to the `HTTP_PROXY` environment variable (if set).
 	// ---
@@ -761,6 +1145,45 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Interval at which to look for updates to cached images
 	"images.auto_update_interval": {Type: config.Int64, Default: "6"},
 
+	// gendoc:generate(entity=server, group=images, key=images.cache.max_stale)
+	// Specify the number of hours.
+	// When the upstream image servers can't be reached, their last known catalog keeps being served for that long.
+	// To keep serving it for as long as needed, set this option to `0`.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: How long to serve a stale upstream catalog for
+	"images.cache.max_stale": {Type: config.Int64, Default: "0"},
+
+	// gendoc:generate(entity=server, group=images, key=images.cache.protocol)
+	// Possible values are `simplestreams` or `incus`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `simplestreams`
+	//  shortdesc: Protocol of the upstream image servers
+	"images.cache.protocol": {Default: "simplestreams", Validator: validate.IsOneOf("simplestreams", "incus")},
+
+	// gendoc:generate(entity=server, group=images, key=images.cache.refresh_interval)
+	// Specify the interval in hours.
+	// To only fetch the upstream catalogs once, set this option to `0`.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `6`
+	//  shortdesc: Interval at which to refresh the upstream catalogs
+	"images.cache.refresh_interval": {Type: config.Int64, Default: "6"},
+
+	// gendoc:generate(entity=server, group=images, key=images.cache.upstreams)
+	// Specify a comma-separated list of image server URLs.
+	// When set, the images and aliases of those servers are served alongside the local ones, and their images are downloaded and cached on first use.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Upstream image servers to act as a caching proxy for
+	"images.cache.upstreams": {Validator: validate.Optional(validate.IsListOf(validate.IsRequestURL))},
+
 	// gendoc:generate(entity=server, group=images, key=images.compression_algorithm)
 	// Possible values are `bzip2`, `gzip`, `lz4`, `lzma`, `xz`, `zstd` or `none`.
 	// ---
@@ -815,6 +1238,52 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: How to set the host name for a NIC
 	"instances.nic.host_name": {Validator: validate.Optional(validate.IsOneOf("random", "mac"))},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=instances.overcommit.cpu)
+	// The CPU threads committed to running instances, through {config:option}`instance-resource-limits:limits.cpu`,
+	// can't exceed the CPU threads available to instances multiplied by this ratio.
+	// When not set, CPU overcommit isn't limited.
+	// See {ref}`cluster-overcommit` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Maximum CPU overcommit ratio (for example, `4.0`)
+	"instances.overcommit.cpu": {Validator: validate.Optional(validate.IsPositiveFloat)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=instances.overcommit.memory)
+	// The memory committed to running instances, through {config:option}`instance-resource-limits:limits.memory`,
+	// can't exceed the memory available to instances multiplied by this ratio.
+	// When not set, memory overcommit isn't limited.
+	// See {ref}`cluster-overcommit` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Maximum memory overcommit ratio (for example, `1.5`)
+	"instances.overcommit.memory": {Validator: validate.Optional(validate.IsPositiveFloat)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=instances.reserved.cpu)
+	// The reserved CPU threads are kept free of load-balanced containers and
+	// are left out of the capacity considered when re-balancing the cluster.
+	// This is usually set on cluster groups or members.
+	// See {ref}`cluster-host-reservation` for more information.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Number of CPU threads reserved for the host
+	"instances.reserved.cpu": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsUint32)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=instances.reserved.memory)
+	// The reserved memory is protected from reclaim in the cgroup of the system slice and
+	// is left out of the capacity considered when re-balancing the cluster.
+	// This is usually set on cluster groups or members.
+	// See {ref}`cluster-host-reservation` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Amount of memory reserved for the host (for example, `2GiB`)
+	"instances.reserved.memory": {Validator: validate.Optional(validate.IsSize)},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=instances.placement.scriptlet)
 	// When using custom automatic instance placement logic, this option stores the scriptlet.
 	// See {ref}`clustering-instance-placement-scriptlet` for more information.
@@ -956,6 +1425,25 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OpenID Connect claim to use as the username
 	"oidc.claim": {},
 
+	// gendoc:generate(entity=server, group=authentication, key=authentication.external.command)
+	// The command is provided with a JSON document containing the username and password on its standard input.
+	// A zero exit status accepts the credentials.
+	// The command can print a username on its standard output to override the one provided by the client.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Command used to validate HTTP basic authentication credentials
+	"authentication.external.command": {},
+
+	// gendoc:generate(entity=server, group=authentication, key=authentication.external.cache_expiry)
+	// Set this option to `0` to run the authentication command on every request.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `60`
+	//  shortdesc: Number of seconds during which successful authentications are cached
+	"authentication.external.cache_expiry": {Type: config.Int64, Default: "60", Validator: validate.Optional(validate.IsInRange(0, 86400))},
+
 	// OVN networking global keys.
 
 	// gendoc:generate(entity=server, group=miscellaneous, key=network.ovn.integration_bridge)
@@ -1003,6 +1491,32 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OVN SSL client key
 	"network.ovn.client_key": {Default: ""},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=reports.expiry)
+	// Specify the number of days during which the generated reports are kept.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `30`
+	//  shortdesc: How long to keep the generated reports
+	"reports.expiry": {Type: config.Int64, Default: "30", Validator: validate.Optional(validate.IsInRange(1, 3650))},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=reports.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable the periodic generation of reports.
+	// A capacity and an inventory report are generated at each run (see {ref}`reports`).
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Schedule for the generation of reports
+	"reports.schedule": {Validator: validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=reports.webhook)
+	// Each generated report is sent as JSON in a `POST` request to this URL.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL the generated reports are pushed to
+	"reports.webhook": {Validator: validate.Optional(validate.IsRequestURL)},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=storage.linstor.controller_connection)
 	//
 	// ---
@@ -1117,3 +1631,13 @@ func rebalanceThresholdValidator(value string) error {
 
 	return nil
 }
+
+// parseRatio returns the positive ratio in the value or 0 if it's empty or invalid.
+func parseRatio(value string) float64 {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil || ratio <= 0 {
+		return 0
+	}
+
+	return ratio
+}
@@ -9,6 +9,7 @@ import (
 
 	clusterConfig "github.com/lxc/incus/v6/internal/server/cluster/config"
 	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 )
 
 // The server configuration is initially empty.
@@ -121,3 +122,104 @@ func TestConfig_PatchKeepsValues(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, map[string]string{"core.proxy_http": "foo.bar"}, values)
 }
+
+// Cluster groups and members can override some of the server configuration keys.
+func TestConfigLoad_MemberOverrides(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	err := tx.UpdateClusterConfig(map[string]string{"core.migration_streams": "2"})
+	require.NoError(t, err)
+
+	group, err := dbCluster.GetClusterGroup(context.Background(), tx.Tx(), "default")
+	require.NoError(t, err)
+
+	err = dbCluster.UpdateClusterGroupConfig(context.Background(), tx.Tx(), int64(group.ID), map[string]string{"core.migration_streams": "4"})
+	require.NoError(t, err)
+
+	config, err := clusterConfig.Load(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, config.MigrationStreams())
+	assert.Equal(t, map[string]string{"core.migration_streams": "2"}, config.Dump())
+
+	err = tx.UpdateNodeConfig(context.Background(), 1, map[string]string{"core.migration_streams": "8"})
+	require.NoError(t, err)
+
+	config, err = clusterConfig.Load(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, 8, config.MigrationStreams())
+
+	// Changing the cluster-wide value doesn't affect the overridden one.
+	_, err = config.Patch(map[string]string{"core.migration_streams": "3"})
+	require.NoError(t, err)
+	assert.Equal(t, 8, config.MigrationStreams())
+
+	values, err := tx.Config(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"core.migration_streams": "3"}, values)
+}
+
+// The configuration effective on another member can be derived from its overrides.
+func TestConfig_ForMember(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	err := tx.UpdateClusterConfig(map[string]string{"instances.reserved.cpu": "2"})
+	require.NoError(t, err)
+
+	err = tx.UpdateNodeConfig(context.Background(), 1, map[string]string{"instances.reserved.memory": "1GiB"})
+	require.NoError(t, err)
+
+	config, err := clusterConfig.Load(context.Background(), tx)
+	require.NoError(t, err)
+
+	member, err := tx.GetNodeWithID(context.Background(), 1)
+	require.NoError(t, err)
+
+	overrides, err := clusterConfig.MemberOverrides(context.Background(), tx, member)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"instances.reserved.memory": "1GiB"}, overrides)
+
+	memberConfig, err := config.ForMember(overrides)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), memberConfig.InstancesReservedCPU())
+	assert.Equal(t, int64(1024*1024*1024), memberConfig.InstancesReservedMemory())
+
+	memberConfig, err = config.ForMember(map[string]string{"instances.reserved.cpu": "4"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), memberConfig.InstancesReservedCPU())
+	assert.Equal(t, int64(0), memberConfig.InstancesReservedMemory())
+}
+
+// The overcommit ratios default to no limit and can be overridden by members.
+func TestConfig_InstancesOvercommit(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	config, err := clusterConfig.Load(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), config.InstancesOvercommitCPU())
+	assert.Equal(t, float64(0), config.InstancesOvercommitMemory())
+
+	err = tx.UpdateClusterConfig(map[string]string{"instances.overcommit.cpu": "4"})
+	require.NoError(t, err)
+
+	config, err = clusterConfig.Load(context.Background(), tx)
+	require.NoError(t, err)
+
+	memberConfig, err := config.ForMember(map[string]string{"instances.overcommit.memory": "1.5"})
+	require.NoError(t, err)
+	assert.Equal(t, float64(4), memberConfig.InstancesOvercommitCPU())
+	assert.Equal(t, 1.5, memberConfig.InstancesOvercommitMemory())
+
+	_, err = config.Patch(map[string]string{"instances.overcommit.cpu": "0"})
+	assert.Error(t, err)
+}
+
+// Only some keys can be overridden by cluster groups and members.
+func TestValidateMemberKey(t *testing.T) {
+	assert.NoError(t, clusterConfig.ValidateMemberKey("core.migration_streams", "4"))
+	assert.Error(t, clusterConfig.ValidateMemberKey("core.migration_streams", "100"))
+	assert.Error(t, clusterConfig.ValidateMemberKey("core.migration_streams", "foo"))
+	assert.Error(t, clusterConfig.ValidateMemberKey("core.proxy_http", "foo.bar"))
+}
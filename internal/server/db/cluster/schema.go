@@ -43,6 +43,28 @@ CREATE TABLE config (
     value TEXT,
     UNIQUE (key)
 );
+CREATE TABLE "config_revisions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_name TEXT NOT NULL,
+    revision INTEGER NOT NULL,
+    date DATETIME NOT NULL,
+    requestor TEXT NOT NULL,
+    changes TEXT NOT NULL,
+    document TEXT NOT NULL,
+    UNIQUE (project_id, entity_type, entity_name, revision),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE TABLE "events" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    type TEXT NOT NULL,
+    project TEXT NOT NULL,
+    location TEXT NOT NULL,
+    metadata TEXT NOT NULL
+);
+CREATE INDEX events_timestamp_idx ON events (timestamp);
 CREATE TABLE "images" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -71,6 +93,15 @@ CREATE TABLE "images_aliases" (
     FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE,
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
 );
+CREATE TABLE "images_aliases_architectures" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    image_alias_id INTEGER NOT NULL,
+    architecture INTEGER NOT NULL,
+    image_id INTEGER NOT NULL,
+    UNIQUE (image_alias_id, architecture),
+    FOREIGN KEY (image_alias_id) REFERENCES "images_aliases" (id) ON DELETE CASCADE,
+    FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE
+);
 CREATE INDEX images_aliases_project_id_idx ON images_aliases (project_id);
 CREATE TABLE "images_nodes" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
@@ -96,6 +127,15 @@ CREATE TABLE "images_properties" (
     value TEXT,
     FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE
 );
+CREATE TABLE "images_provenance" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    image_id INTEGER NOT NULL,
+    builder TEXT NOT NULL,
+    source_hash TEXT NOT NULL,
+    sbom TEXT NOT NULL,
+    FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE,
+    UNIQUE (image_id)
+);
 CREATE TABLE "images_source" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     image_id INTEGER NOT NULL,
@@ -122,6 +162,20 @@ CREATE TABLE "instances" (
     FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE,
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
 );
+CREATE TABLE "instances_access_links" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    instance_id INTEGER NOT NULL,
+    secret_hash TEXT NOT NULL,
+    access TEXT NOT NULL,
+    description TEXT NOT NULL,
+    requestor TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME NOT NULL,
+    last_use_date DATETIME,
+    UNIQUE (uuid),
+    FOREIGN KEY (instance_id) REFERENCES instances (id) ON DELETE CASCADE
+);
 CREATE TABLE "instances_backups" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     instance_id INTEGER NOT NULL,
@@ -421,6 +475,14 @@ CREATE TABLE nodes_failure_domains (
     name TEXT NOT NULL,
     UNIQUE (name)
 );
+CREATE TABLE "nodes_labels" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    node_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE,
+    UNIQUE (node_id, key)
+);
 CREATE TABLE "nodes_roles" (
     node_id INTEGER NOT NULL,
     role INTEGER NOT NULL,
@@ -484,6 +546,14 @@ CREATE TABLE "projects_config" (
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
     UNIQUE (project_id, key)
 );
+CREATE TABLE "reports" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    sections TEXT NOT NULL,
+    UNIQUE (name)
+);
 CREATE TABLE "storage_buckets" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
@@ -674,5 +744,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (76, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (83, strftime("%s"))
 `
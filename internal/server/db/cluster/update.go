@@ -113,6 +113,167 @@ var updates = map[int]schema.Update{
 	74: updateFromV73,
 	75: updateFromV74,
 	76: updateFromV75,
+	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+	81: updateFromV80,
+	82: updateFromV81,
+	83: updateFromV82,
+}
+
+// updateFromV82 adds a table storing the generated capacity and inventory reports.
+func updateFromV82(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "reports" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    sections TEXT NOT NULL,
+    UNIQUE (name)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding reports table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV81 adds a table recording the access links to the console and exec of instances.
+func updateFromV81(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "instances_access_links" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    instance_id INTEGER NOT NULL,
+    secret_hash TEXT NOT NULL,
+    access TEXT NOT NULL,
+    description TEXT NOT NULL,
+    requestor TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME NOT NULL,
+    last_use_date DATETIME,
+    UNIQUE (uuid),
+    FOREIGN KEY (instance_id) REFERENCES instances (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding instances_access_links table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV80 adds a table recording the previous configuration of profiles, projects, networks and network ACLs.
+func updateFromV80(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "config_revisions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_name TEXT NOT NULL,
+    revision INTEGER NOT NULL,
+    date DATETIME NOT NULL,
+    requestor TEXT NOT NULL,
+    changes TEXT NOT NULL,
+    document TEXT NOT NULL,
+    UNIQUE (project_id, entity_type, entity_name, revision),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding config_revisions table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV79 adds a table recording the lifecycle events for replay.
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "events" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    type TEXT NOT NULL,
+    project TEXT NOT NULL,
+    location TEXT NOT NULL,
+    metadata TEXT NOT NULL
+);
+CREATE INDEX events_timestamp_idx ON events (timestamp);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding events table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV78 adds a table of per-architecture targets to image aliases.
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "images_aliases_architectures" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    image_alias_id INTEGER NOT NULL,
+    architecture INTEGER NOT NULL,
+    image_id INTEGER NOT NULL,
+    UNIQUE (image_alias_id, architecture),
+    FOREIGN KEY (image_alias_id) REFERENCES "images_aliases" (id) ON DELETE CASCADE,
+    FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding image alias architectures table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV77 adds a provenance table to images.
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "images_provenance" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    image_id INTEGER NOT NULL,
+    builder TEXT NOT NULL,
+    source_hash TEXT NOT NULL,
+    sbom TEXT NOT NULL,
+    FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE,
+    UNIQUE (image_id)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding image provenance table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV76 adds a labels table to cluster members.
+func updateFromV76(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "nodes_labels" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    node_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE,
+    UNIQUE (node_id, key)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding cluster member labels table: %w", err)
+	}
+
+	return nil
 }
 
 func updateFromV75(ctx context.Context, tx *sql.Tx) error {
@@ -0,0 +1,156 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Entity types whose configuration revisions are recorded.
+const (
+	ConfigRevisionTypeProfile    = "profile"
+	ConfigRevisionTypeProject    = "project"
+	ConfigRevisionTypeNetwork    = "network"
+	ConfigRevisionTypeNetworkACL = "network-acl"
+)
+
+// ConfigRevisionsMax is the number of configuration revisions kept per entity.
+const ConfigRevisionsMax = 50
+
+// CreateConfigRevision records a new configuration revision of an entity and returns its revision number.
+// Only the ConfigRevisionsMax most recent revisions of the entity are kept.
+// The entity name of projects is empty, as they're identified by the project name.
+func (c *ClusterTx) CreateConfigRevision(ctx context.Context, projectName string, entityType string, entityName string, revision api.ConfigRevision) (int64, error) {
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return -1, err
+	}
+
+	document, err := json.Marshal(revision.Document)
+	if err != nil {
+		return -1, err
+	}
+
+	var projectID int64
+	var lastRevision int64
+	err = c.tx.QueryRowContext(ctx, `
+SELECT projects.id, COALESCE((
+  SELECT MAX(revision) FROM config_revisions
+   WHERE project_id = projects.id AND entity_type = ? AND entity_name = ?
+), 0)
+  FROM projects
+ WHERE projects.name = ?
+`, entityType, entityName, projectName).Scan(&projectID, &lastRevision)
+	if err != nil {
+		return -1, fmt.Errorf("Failed getting last configuration revision: %w", err)
+	}
+
+	_, err = c.tx.ExecContext(ctx, "INSERT INTO config_revisions (project_id, entity_type, entity_name, revision, date, requestor, changes, document) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		projectID, entityType, entityName, lastRevision+1, revision.CreatedAt.UTC(), revision.Requestor, string(changes), string(document))
+	if err != nil {
+		return -1, fmt.Errorf("Failed recording configuration revision: %w", err)
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM config_revisions WHERE project_id = ? AND entity_type = ? AND entity_name = ? AND revision <= ?",
+		projectID, entityType, entityName, lastRevision+1-ConfigRevisionsMax)
+	if err != nil {
+		return -1, fmt.Errorf("Failed pruning configuration revisions: %w", err)
+	}
+
+	return lastRevision + 1, nil
+}
+
+// GetConfigRevisions returns the recorded configuration revisions of an entity, oldest first.
+func (c *ClusterTx) GetConfigRevisions(ctx context.Context, projectName string, entityType string, entityName string) ([]api.ConfigRevision, error) {
+	q := `
+SELECT config_revisions.revision, config_revisions.date, config_revisions.requestor, config_revisions.changes, config_revisions.document
+  FROM config_revisions
+  JOIN projects ON projects.id = config_revisions.project_id
+ WHERE projects.name = ? AND config_revisions.entity_type = ? AND config_revisions.entity_name = ?
+ ORDER BY config_revisions.revision
+`
+
+	return c.getConfigRevisions(ctx, q, projectName, entityType, entityName)
+}
+
+// GetConfigRevision returns a recorded configuration revision of an entity.
+func (c *ClusterTx) GetConfigRevision(ctx context.Context, projectName string, entityType string, entityName string, revision int64) (*api.ConfigRevision, error) {
+	q := `
+SELECT config_revisions.revision, config_revisions.date, config_revisions.requestor, config_revisions.changes, config_revisions.document
+  FROM config_revisions
+  JOIN projects ON projects.id = config_revisions.project_id
+ WHERE projects.name = ? AND config_revisions.entity_type = ? AND config_revisions.entity_name = ? AND config_revisions.revision = ?
+`
+
+	revisions, err := c.getConfigRevisions(ctx, q, projectName, entityType, entityName, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(revisions) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Configuration revision not found")
+	}
+
+	return &revisions[0], nil
+}
+
+func (c *ClusterTx) getConfigRevisions(ctx context.Context, q string, args ...any) ([]api.ConfigRevision, error) {
+	revisions := []api.ConfigRevision{}
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var revision api.ConfigRevision
+		var changes string
+		var document string
+
+		err := scan(&revision.Revision, &revision.CreatedAt, &revision.Requestor, &changes, &document)
+		if err != nil {
+			return err
+		}
+
+		err = json.Unmarshal([]byte(changes), &revision.Changes)
+		if err != nil {
+			return fmt.Errorf("Failed parsing changes of configuration revision %d: %w", revision.Revision, err)
+		}
+
+		err = json.Unmarshal([]byte(document), &revision.Document)
+		if err != nil {
+			return fmt.Errorf("Failed parsing document of configuration revision %d: %w", revision.Revision, err)
+		}
+
+		revisions = append(revisions, revision)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting configuration revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// RenameConfigRevisions moves the recorded configuration revisions of an entity to its new name.
+func (c *ClusterTx) RenameConfigRevisions(ctx context.Context, projectName string, entityType string, oldName string, newName string) error {
+	_, err := c.tx.ExecContext(ctx, "UPDATE config_revisions SET entity_name = ? WHERE project_id = (SELECT id FROM projects WHERE name = ?) AND entity_type = ? AND entity_name = ?", newName, projectName, entityType, oldName)
+	if err != nil {
+		return fmt.Errorf("Failed renaming configuration revisions: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteConfigRevisions removes the recorded configuration revisions of an entity.
+// The revisions of a project and of its entities are removed with the project itself.
+func (c *ClusterTx) DeleteConfigRevisions(ctx context.Context, projectName string, entityType string, entityName string) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM config_revisions WHERE project_id = (SELECT id FROM projects WHERE name = ?) AND entity_type = ? AND entity_name = ?", projectName, entityType, entityName)
+	if err != nil {
+		return fmt.Errorf("Failed deleting configuration revisions: %w", err)
+	}
+
+	return nil
+}
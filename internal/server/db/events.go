@@ -0,0 +1,78 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// CreateEvents records the events in the events history.
+func (c *ClusterTx) CreateEvents(ctx context.Context, events []api.Event) error {
+	stmt, err := c.tx.PrepareContext(ctx, "INSERT INTO events (timestamp, type, project, location, metadata) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = stmt.Close() }()
+
+	for _, event := range events {
+		_, err := stmt.ExecContext(ctx, event.Timestamp.UTC(), event.Type, event.Project, event.Location, string(event.Metadata))
+		if err != nil {
+			return fmt.Errorf("Failed recording event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetEvents returns the recorded events of the given types which happened in the time range, in the order they
+// happened. An empty list of types returns the events of all types.
+func (c *ClusterTx) GetEvents(ctx context.Context, since time.Time, until time.Time, types []string) ([]api.Event, error) {
+	var events []api.Event
+
+	q := `
+SELECT timestamp, type, project, location, metadata
+  FROM events
+ WHERE timestamp >= ? AND timestamp < ?
+ ORDER BY timestamp, id
+`
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var event api.Event
+		var metadata string
+
+		err := scan(&event.Timestamp, &event.Type, &event.Project, &event.Location, &metadata)
+		if err != nil {
+			return err
+		}
+
+		if len(types) > 0 && !slices.Contains(types, event.Type) {
+			return nil
+		}
+
+		event.Metadata = []byte(metadata)
+		events = append(events, event)
+
+		return nil
+	}, since.UTC(), until.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting recorded events: %w", err)
+	}
+
+	return events, nil
+}
+
+// DeleteEventsBefore removes the recorded events which happened before the given time.
+func (c *ClusterTx) DeleteEventsBefore(ctx context.Context, before time.Time) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM events WHERE timestamp < ?", before.UTC())
+	if err != nil {
+		return fmt.Errorf("Failed deleting recorded events: %w", err)
+	}
+
+	return nil
+}
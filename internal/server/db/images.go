@@ -142,6 +142,28 @@ func (c *ClusterTx) imageFill(ctx context.Context, id int, image *api.Image, cre
 		return err
 	}
 
+	// Get the aliases having the image as an architecture variant
+	q = `
+SELECT images_aliases.name, images_aliases.description
+  FROM images_aliases_architectures
+  JOIN images_aliases ON images_aliases.id=images_aliases_architectures.image_alias_id
+ WHERE images_aliases_architectures.image_id=?
+`
+	err = query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		alias := api.ImageAlias{Variant: true}
+
+		err := scan(&alias.Name, &alias.Description)
+		if err != nil {
+			return err
+		}
+
+		aliases = append(aliases, alias)
+		return nil
+	}, id)
+	if err != nil {
+		return err
+	}
+
 	image.Aliases = aliases
 
 	_, source, err := c.GetImageSource(ctx, id)
@@ -149,6 +171,26 @@ func (c *ClusterTx) imageFill(ctx context.Context, id int, image *api.Image, cre
 		image.UpdateSource = &source
 	}
 
+	// Get the provenance
+	q = "SELECT builder, source_hash, sbom != '' FROM images_provenance WHERE image_id=?"
+	err = query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		provenance := api.ImageProvenance{}
+
+		err := scan(&provenance.Builder, &provenance.SourceHash, &image.SBOM)
+		if err != nil {
+			return err
+		}
+
+		if provenance.Builder != "" || provenance.SourceHash != "" {
+			image.Provenance = &provenance
+		}
+
+		return nil
+	}, id)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -241,6 +283,39 @@ func (c *ClusterTx) CreateImageSource(ctx context.Context, id int, server string
 	return err
 }
 
+// CreateImageProvenance records the build provenance and the SBOM document of an image.
+func (c *ClusterTx) CreateImageProvenance(ctx context.Context, id int, provenance api.ImageProvenance, sbom string) error {
+	_, err := query.UpsertObject(c.tx, "images_provenance", []string{
+		"image_id",
+		"builder",
+		"source_hash",
+		"sbom",
+	}, []any{
+		id,
+		provenance.Builder,
+		provenance.SourceHash,
+		sbom,
+	})
+
+	return err
+}
+
+// GetImageSBOM returns the SBOM document attached to an image.
+func (c *ClusterTx) GetImageSBOM(ctx context.Context, id int) (string, error) {
+	q := `SELECT sbom FROM images_provenance WHERE image_id=? AND sbom != ''`
+
+	sboms, err := query.SelectStrings(ctx, c.tx, q, id)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sboms) == 0 {
+		return "", api.StatusErrorf(http.StatusNotFound, "Image SBOM not found")
+	}
+
+	return sboms[0], nil
+}
+
 // GetCachedImageSourceFingerprint tries to find a source entry of a locally
 // cached image that matches the given remote details (server, protocol and
 // alias). Return the fingerprint linked to the matching entry, if any.
@@ -629,7 +704,7 @@ SELECT images_aliases.name
 func (c *ClusterTx) GetImageAlias(ctx context.Context, projectName string, imageName string, isTrustedClient bool) (int, api.ImageAliasesEntry, error) {
 	id := -1
 	entry := api.ImageAliasesEntry{}
-	q := `SELECT images_aliases.id, images.fingerprint, images.type, images_aliases.description
+	q := `SELECT images_aliases.id, images.fingerprint, images.type, images.architecture, images_aliases.description
 			 FROM images_aliases
 			 INNER JOIN images
 			 ON images_aliases.image_id=images.id
@@ -650,10 +725,10 @@ func (c *ClusterTx) GetImageAlias(ctx context.Context, projectName string, image
 	}
 
 	var fingerprint, description string
-	var imageType int
+	var imageType, architecture int
 
 	arg1 := []any{projectName, imageName}
-	arg2 := []any{&id, &fingerprint, &imageType, &description}
+	arg2 := []any{&id, &fingerprint, &imageType, &architecture, &description}
 	err = c.tx.QueryRowContext(ctx, q, arg1...).Scan(arg2...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -668,6 +743,49 @@ func (c *ClusterTx) GetImageAlias(ctx context.Context, projectName string, image
 	entry.Description = description
 	entry.Type = instancetype.Type(imageType).String()
 
+	// Get the architecture variants, in which case the target is listed for its own architecture.
+	q = `
+SELECT images_aliases_architectures.architecture, images.fingerprint
+  FROM images_aliases_architectures
+  JOIN images ON images.id=images_aliases_architectures.image_id
+ WHERE images_aliases_architectures.image_alias_id=?
+`
+	if !isTrustedClient {
+		q = q + ` AND images.public=1`
+	}
+
+	variants := map[string]string{}
+	err = query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var variantArchitecture int
+		var variantFingerprint string
+
+		err := scan(&variantArchitecture, &variantFingerprint)
+		if err != nil {
+			return err
+		}
+
+		architectureName, err := osarch.ArchitectureName(variantArchitecture)
+		if err != nil {
+			return err
+		}
+
+		variants[architectureName] = variantFingerprint
+		return nil
+	}, id)
+	if err != nil {
+		return -1, api.ImageAliasesEntry{}, err
+	}
+
+	if len(variants) > 0 {
+		architectureName, err := osarch.ArchitectureName(architecture)
+		if err != nil {
+			return -1, api.ImageAliasesEntry{}, err
+		}
+
+		variants[architectureName] = fingerprint
+		entry.Architectures = variants
+	}
+
 	return id, entry, nil
 }
 
@@ -703,10 +821,16 @@ DELETE
 	return nil
 }
 
-// MoveImageAlias changes the image ID associated with an alias.
+// MoveImageAlias changes the image ID associated with an alias, including as an architecture variant.
 func (c *ClusterTx) MoveImageAlias(ctx context.Context, source int, destination int) error {
 	q := "UPDATE images_aliases SET image_id=? WHERE image_id=?"
 	_, err := c.tx.ExecContext(ctx, q, destination, source)
+	if err != nil {
+		return err
+	}
+
+	q = "UPDATE images_aliases_architectures SET image_id=? WHERE image_id=?"
+	_, err = c.tx.ExecContext(ctx, q, destination, source)
 
 	return err
 }
@@ -740,6 +864,31 @@ func (c *ClusterTx) UpdateImageAlias(ctx context.Context, aliasID int, imageID i
 	return err
 }
 
+// UpdateImageAliasArchitectures replaces the architecture variants of the alias with the given ID.
+// The variants map architecture IDs to image IDs.
+func (c *ClusterTx) UpdateImageAliasArchitectures(ctx context.Context, aliasID int, variants map[int]int) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM images_aliases_architectures WHERE image_alias_id=?", aliasID)
+	if err != nil {
+		return err
+	}
+
+	for architecture, imageID := range variants {
+		err := c.CreateImageAliasArchitecture(ctx, aliasID, architecture, imageID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateImageAliasArchitecture sets the image used by the alias with the given ID for an architecture.
+func (c *ClusterTx) CreateImageAliasArchitecture(ctx context.Context, aliasID int, architecture int, imageID int) error {
+	stmt := `INSERT OR REPLACE INTO images_aliases_architectures (image_alias_id, architecture, image_id) VALUES (?, ?, ?)`
+	_, err := c.tx.ExecContext(ctx, stmt, aliasID, architecture, imageID)
+	return err
+}
+
 // CopyDefaultImageProfiles copies default profiles from id to new_id.
 func (c *ClusterTx) CopyDefaultImageProfiles(ctx context.Context, id int, newID int) error {
 	// Delete all current associations.
@@ -757,6 +906,16 @@ func (c *ClusterTx) CopyDefaultImageProfiles(ctx context.Context, id int, newID
 	return nil
 }
 
+// CopyImageProvenance copies the build provenance and the SBOM document of an image to another one.
+func (c *ClusterTx) CopyImageProvenance(ctx context.Context, id int, newID int) error {
+	_, err := c.tx.ExecContext(ctx, "INSERT OR REPLACE INTO images_provenance (image_id, builder, source_hash, sbom) SELECT ?, builder, source_hash, sbom FROM images_provenance WHERE image_id=?", newID, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateImageLastUseDate updates the last_use_date field of the image with the
 // given fingerprint.
 func (c *ClusterTx) UpdateImageLastUseDate(ctx context.Context, projectName string, fingerprint string, lastUsed time.Time) error {
@@ -4,6 +4,7 @@ package db_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
 )
 
 func TestLocateImage(t *testing.T) {
@@ -106,3 +108,38 @@ func TestGetImage(t *testing.T) {
 		return nil
 	})
 }
+
+func TestImageProvenance(t *testing.T) {
+	dbCluster, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+	project := "default"
+
+	_ = dbCluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.CreateImage(ctx, project, "abcd1", "x.gz", 16, false, false, "amd64", time.Now(), time.Now(), map[string]string{}, "container", nil)
+		require.NoError(t, err)
+
+		// No provenance recorded.
+		id, img, err := tx.GetImage(ctx, "abcd1", cluster.ImageFilter{Project: &project})
+		require.NoError(t, err)
+		assert.Nil(t, img.Provenance)
+		assert.False(t, img.SBOM)
+
+		_, err = tx.GetImageSBOM(ctx, id)
+		assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+		// Record the provenance and the SBOM.
+		err = tx.CreateImageProvenance(ctx, id, api.ImageProvenance{Builder: "ci", SourceHash: "abc"}, `{"spdxVersion": "SPDX-2.3"}`)
+		require.NoError(t, err)
+
+		_, img, err = tx.GetImage(ctx, "abcd1", cluster.ImageFilter{Project: &project})
+		require.NoError(t, err)
+		assert.Equal(t, &api.ImageProvenance{Builder: "ci", SourceHash: "abc"}, img.Provenance)
+		assert.True(t, img.SBOM)
+
+		sbom, err := tx.GetImageSBOM(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, `{"spdxVersion": "SPDX-2.3"}`, sbom)
+
+		return nil
+	})
+}
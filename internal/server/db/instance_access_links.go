@@ -0,0 +1,153 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// InstanceAccessLink is an instance access link along with its instance and the hash of its secret.
+type InstanceAccessLink struct {
+	api.InstanceAccessLink
+
+	Project    string
+	Instance   string
+	SecretHash string
+}
+
+// CreateInstanceAccessLink records a new access link to an instance.
+func (c *ClusterTx) CreateInstanceAccessLink(ctx context.Context, link InstanceAccessLink) error {
+	res, err := c.tx.ExecContext(ctx, `
+INSERT INTO instances_access_links (uuid, instance_id, secret_hash, access, description, requestor, creation_date, expiry_date)
+  SELECT ?, instances.id, ?, ?, ?, ?, ?, ?
+    FROM instances
+    JOIN projects ON projects.id = instances.project_id
+   WHERE projects.name = ? AND instances.name = ?
+`, link.ID, link.SecretHash, strings.Join(link.Access, ","), link.Description, link.CreatedBy, link.CreatedAt.UTC(), link.ExpiresAt.UTC(), link.Project, link.Instance)
+	if err != nil {
+		return fmt.Errorf("Failed creating instance access link: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Instance not found")
+	}
+
+	return nil
+}
+
+const instanceAccessLinksQuery = `
+SELECT instances_access_links.uuid, instances_access_links.access, instances_access_links.description, instances_access_links.requestor,
+       instances_access_links.creation_date, instances_access_links.expiry_date, instances_access_links.last_use_date,
+       projects.name, instances.name, instances_access_links.secret_hash
+  FROM instances_access_links
+  JOIN instances ON instances.id = instances_access_links.instance_id
+  JOIN projects ON projects.id = instances.project_id
+`
+
+// GetInstanceAccessLinks returns the access links of an instance, including the expired ones.
+func (c *ClusterTx) GetInstanceAccessLinks(ctx context.Context, projectName string, instanceName string) ([]InstanceAccessLink, error) {
+	q := instanceAccessLinksQuery + " WHERE projects.name = ? AND instances.name = ? ORDER BY instances_access_links.creation_date"
+
+	return c.getInstanceAccessLinks(ctx, q, projectName, instanceName)
+}
+
+// GetInstanceAccessLink returns the access link with the given identifier.
+func (c *ClusterTx) GetInstanceAccessLink(ctx context.Context, linkID string) (*InstanceAccessLink, error) {
+	links, err := c.getInstanceAccessLinks(ctx, instanceAccessLinksQuery+" WHERE instances_access_links.uuid = ?", linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(links) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Instance access link not found")
+	}
+
+	return &links[0], nil
+}
+
+func (c *ClusterTx) getInstanceAccessLinks(ctx context.Context, q string, args ...any) ([]InstanceAccessLink, error) {
+	links := []InstanceAccessLink{}
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var link InstanceAccessLink
+		var access string
+		var lastUsed sql.NullTime
+
+		err := scan(&link.ID, &access, &link.Description, &link.CreatedBy, &link.CreatedAt, &link.ExpiresAt, &lastUsed, &link.Project, &link.Instance, &link.SecretHash)
+		if err != nil {
+			return err
+		}
+
+		link.Access = strings.Split(access, ",")
+		if lastUsed.Valid {
+			link.LastUsedAt = lastUsed.Time
+		}
+
+		links = append(links, link)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting instance access links: %w", err)
+	}
+
+	return links, nil
+}
+
+// UpdateInstanceAccessLinkLastUse records when the access link was last used.
+func (c *ClusterTx) UpdateInstanceAccessLinkLastUse(ctx context.Context, linkID string, date time.Time) error {
+	_, err := c.tx.ExecContext(ctx, "UPDATE instances_access_links SET last_use_date = ? WHERE uuid = ?", date.UTC(), linkID)
+	if err != nil {
+		return fmt.Errorf("Failed updating instance access link: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteInstanceAccessLink removes (revokes) an access link of an instance.
+func (c *ClusterTx) DeleteInstanceAccessLink(ctx context.Context, projectName string, instanceName string, linkID string) error {
+	res, err := c.tx.ExecContext(ctx, `
+DELETE FROM instances_access_links
+ WHERE uuid = ? AND instance_id = (
+   SELECT instances.id FROM instances JOIN projects ON projects.id = instances.project_id
+    WHERE projects.name = ? AND instances.name = ?
+ )
+`, linkID, projectName, instanceName)
+	if err != nil {
+		return fmt.Errorf("Failed deleting instance access link: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Instance access link not found")
+	}
+
+	return nil
+}
+
+// DeleteExpiredInstanceAccessLinks removes the access links which expired before the given date.
+func (c *ClusterTx) DeleteExpiredInstanceAccessLinks(ctx context.Context, before time.Time) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM instances_access_links WHERE expiry_date < ?", before.UTC())
+	if err != nil {
+		return fmt.Errorf("Failed deleting expired instance access links: %w", err)
+	}
+
+	return nil
+}
@@ -686,6 +686,43 @@ func (c *ClusterTx) UpdateInstanceNode(ctx context.Context, project string, oldN
 	return nil
 }
 
+// UpdateInstanceProject moves an instance, its snapshots and its root volume to another project.
+// The profiles are looked up in the new project and applied in the order they are given.
+// It's meant to be used when moving a non-running instance whose volume doesn't have to be copied.
+func (c *ClusterTx) UpdateInstanceProject(ctx context.Context, project string, name string, newProject string, profiles []string, poolID int64, volumeType int) error {
+	instanceID, err := cluster.GetInstanceID(ctx, c.tx, project, name)
+	if err != nil {
+		return fmt.Errorf("Failed to get instance's ID: %w", err)
+	}
+
+	newProjectID, err := cluster.GetProjectID(ctx, c.tx, newProject)
+	if err != nil {
+		return fmt.Errorf("Failed to get new project %q ID: %w", newProject, err)
+	}
+
+	volume, err := c.GetStoragePoolVolume(ctx, poolID, project, volumeType, name, true)
+	if err != nil {
+		return fmt.Errorf("Failed to get instance's volume: %w", err)
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE instances SET project_id=? WHERE id=?", newProjectID, instanceID)
+	if err != nil {
+		return fmt.Errorf("Failed to update instance's project: %w", err)
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE storage_volumes SET project_id=? WHERE id=?", newProjectID, volume.ID)
+	if err != nil {
+		return fmt.Errorf("Failed to update instance's volume project: %w", err)
+	}
+
+	err = cluster.UpdateInstanceProfiles(ctx, c.tx, int(instanceID), newProject, profiles)
+	if err != nil {
+		return fmt.Errorf("Failed to update instance's profiles: %w", err)
+	}
+
+	return nil
+}
+
 // GetLocalInstancesInProject retuurns all instances of the given type on the local member in the given project.
 // If projectName is empty then all instances in all projects are returned.
 func (c *ClusterTx) GetLocalInstancesInProject(ctx context.Context, filter cluster.InstanceFilter) ([]cluster.Instance, error) {
@@ -71,6 +71,7 @@ type NodeInfo struct {
 	State         int               // Node state
 	Config        map[string]string // Configuration for the node
 	Groups        []string          // Cluster groups
+	Labels        map[string]string // Inventory labels
 }
 
 // IsOffline returns true if the last successful heartbeat time of the node is
@@ -113,6 +114,7 @@ func (n NodeInfo) ToAPI(ctx context.Context, tx *ClusterTx, args NodeInfoArgs) (
 	result.URL = fmt.Sprintf("https://%s", n.Address)
 	result.Database = false
 	result.Config = n.Config
+	result.Labels = n.Labels
 
 	result.Roles = make([]string, 0, len(n.Roles))
 	for _, r := range n.Roles {
@@ -549,6 +551,39 @@ JOIN cluster_groups ON cluster_groups.id = nodes_cluster_groups.group_id`
 		}
 	}
 
+	// Add the labels
+	nodeLabels := map[int64]map[string]string{}
+	err = query.Scan(ctx, c.Tx(), "SELECT node_id, key, value FROM nodes_labels", func(scan func(dest ...any) error) error {
+		var nodeID int64
+		var key, value string
+
+		err := scan(&nodeID, &key, &value)
+		if err != nil {
+			return err
+		}
+
+		if nodeLabels[nodeID] == nil {
+			nodeLabels[nodeID] = map[string]string{}
+		}
+
+		nodeLabels[nodeID][key] = value
+
+		return nil
+	})
+	if err != nil && err.Error() != "no such table: nodes_labels" {
+		// Don't fail on a missing table, we need to handle updates
+		return nil, err
+	}
+
+	for i := range nodes {
+		labels, ok := nodeLabels[nodes[i].ID]
+		if !ok {
+			nodes[i].Labels = map[string]string{}
+		} else {
+			nodes[i].Labels = labels
+		}
+	}
+
 	return nodes, nil
 }
 
@@ -626,6 +661,23 @@ func (c *ClusterTx) UpdateNodeConfig(ctx context.Context, id int64, config map[s
 	return nil
 }
 
+// UpdateNodeLabels replaces the labels of a member with the specified labels.
+func (c *ClusterTx) UpdateNodeLabels(ctx context.Context, id int64, labels map[string]string) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM nodes_labels WHERE node_id=?", id)
+	if err != nil {
+		return fmt.Errorf("Unable to delete node labels: %w", err)
+	}
+
+	for key, value := range labels {
+		_, err := c.tx.ExecContext(ctx, "INSERT INTO nodes_labels (node_id, key, value) VALUES (?, ?, ?)", id, key, value)
+		if err != nil {
+			return fmt.Errorf("Unable to add node label %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
 // UpdateNodeRoles changes the list of roles on a member.
 func (c *ClusterTx) UpdateNodeRoles(id int64, roles []ClusterRole) error {
 	getRoleID := func(role ClusterRole) (int, error) {
@@ -444,6 +444,31 @@ func TestUpdateNodeFailureDomain(t *testing.T) {
 	assert.Equal(t, map[string]uint64{"0.0.0.0": 0, "1.2.3.4:666": 0}, domains)
 }
 
+func TestUpdateNodeLabels(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	id, err := tx.CreateNode("buzz", "1.2.3.4:666")
+	require.NoError(t, err)
+
+	member, err := tx.GetNodeByName(context.Background(), "buzz")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{}, member.Labels)
+
+	labels := map[string]string{"rack": "r12", "power-feed": "b"}
+	require.NoError(t, tx.UpdateNodeLabels(context.Background(), id, labels))
+
+	member, err = tx.GetNodeByName(context.Background(), "buzz")
+	require.NoError(t, err)
+	assert.Equal(t, labels, member.Labels)
+
+	require.NoError(t, tx.UpdateNodeLabels(context.Background(), id, map[string]string{"rack": "r13"}))
+
+	member, err = tx.GetNodeByName(context.Background(), "buzz")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"rack": "r13"}, member.Labels)
+}
+
 func TestGetCandidateMembers_DefaultArch(t *testing.T) {
 	tx, cleanup := db.NewTestClusterTx(t)
 	defer cleanup()
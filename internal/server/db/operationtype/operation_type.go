@@ -79,6 +79,12 @@ const (
 	BucketBackupRemove
 	BucketBackupRename
 	BucketBackupRestore
+	MigrationRelay
+	StoragePoolTrim
+	ReportGenerate
+	ClusterMemberSnapshot
+	ClusterMemberSnapshotDelete
+	InstanceDebugProfile
 )
 
 // Description return a human-readable description of the operation type.
@@ -210,6 +216,18 @@ func (t Type) Description() string {
 		return "Renaming bucket backup"
 	case BucketBackupRestore:
 		return "Restoring bucket backup"
+	case MigrationRelay:
+		return "Relaying migration"
+	case StoragePoolTrim:
+		return "Trimming storage pool"
+	case ReportGenerate:
+		return "Generating report"
+	case ClusterMemberSnapshot:
+		return "Snapshotting cluster member instances"
+	case ClusterMemberSnapshotDelete:
+		return "Deleting cluster member instance snapshots"
+	case InstanceDebugProfile:
+		return "Profiling instance"
 	default:
 		return "Executing operation"
 	}
@@ -267,6 +285,8 @@ func (t Type) Permission() (auth.ObjectType, auth.Entitlement) {
 		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
 	case SnapshotRestore:
 		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
+	case InstanceDebugProfile:
+		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
 
 	case ImageDownload:
 		return auth.ObjectTypeImage, auth.EntitlementCanEdit
@@ -0,0 +1,121 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// CreateReport stores a generated report.
+func (c *ClusterTx) CreateReport(ctx context.Context, report api.Report) error {
+	sections, err := json.Marshal(report.Sections)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "INSERT INTO reports (name, type, creation_date, sections) VALUES (?, ?, ?, ?)", report.Name, report.Type, report.CreatedAt.UTC(), string(sections))
+	if err != nil {
+		return fmt.Errorf("Failed storing report: %w", err)
+	}
+
+	return nil
+}
+
+// GetReportNames returns the names of the stored reports, from the oldest to the most recent.
+func (c *ClusterTx) GetReportNames(ctx context.Context) ([]string, error) {
+	names, err := query.SelectStrings(ctx, c.tx, "SELECT name FROM reports ORDER BY creation_date, id")
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting reports: %w", err)
+	}
+
+	return names, nil
+}
+
+// GetReports returns the stored reports, from the oldest to the most recent.
+func (c *ClusterTx) GetReports(ctx context.Context) ([]api.Report, error) {
+	var reports []api.Report
+
+	err := query.Scan(ctx, c.tx, "SELECT name, type, creation_date, sections FROM reports ORDER BY creation_date, id", func(scan func(dest ...any) error) error {
+		var report api.Report
+		var sections string
+
+		err := scan(&report.Name, &report.Type, &report.CreatedAt, &sections)
+		if err != nil {
+			return err
+		}
+
+		err = json.Unmarshal([]byte(sections), &report.Sections)
+		if err != nil {
+			return fmt.Errorf("Failed parsing sections of report %q: %w", report.Name, err)
+		}
+
+		reports = append(reports, report)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetReport returns the stored report with the given name.
+func (c *ClusterTx) GetReport(ctx context.Context, name string) (*api.Report, error) {
+	report := api.Report{Name: name}
+	var sections string
+
+	err := c.tx.QueryRowContext(ctx, "SELECT type, creation_date, sections FROM reports WHERE name = ?", name).Scan(&report.Type, &report.CreatedAt, &sections)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Report not found")
+		}
+
+		return nil, fmt.Errorf("Failed getting report: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(sections), &report.Sections)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing sections of report %q: %w", name, err)
+	}
+
+	return &report, nil
+}
+
+// DeleteReport removes the stored report with the given name.
+func (c *ClusterTx) DeleteReport(ctx context.Context, name string) error {
+	result, err := c.tx.ExecContext(ctx, "DELETE FROM reports WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("Failed deleting report: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Report not found")
+	}
+
+	return nil
+}
+
+// DeleteReportsBefore removes the stored reports which were generated before the given time.
+func (c *ClusterTx) DeleteReportsBefore(ctx context.Context, before time.Time) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM reports WHERE creation_date < ?", before.UTC())
+	if err != nil {
+		return fmt.Errorf("Failed deleting reports: %w", err)
+	}
+
+	return nil
+}
@@ -885,6 +885,7 @@ var NodeSpecificStorageConfig = []string{
 	"lvm.thinpool_name",
 	"lvm.vg_name",
 	"lvm.vg.force_reuse",
+	"lvm.cache.device",
 }
 
 // IsRemoteStorage return whether a given pool is backed by remote storage.
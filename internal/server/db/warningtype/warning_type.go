@@ -56,6 +56,14 @@ const (
 	StoragePoolUnvailable
 	// UnableToUpdateClusterCertificate represents the unable to update cluster certificate warning.
 	UnableToUpdateClusterCertificate
+	// StorageVolumeQuotaNotEnforced represents the volume size limits which can't be enforced on a storage pool.
+	StorageVolumeQuotaNotEnforced
+	// RootlessMode represents the features that are unavailable when running without root privileges.
+	RootlessMode
+	// StoragePoolDegraded represents a storage pool whose health check reported problems.
+	StoragePoolDegraded
+	// StorageVolumeIntegrityMismatch represents a storage volume whose content doesn't match its integrity manifest.
+	StorageVolumeIntegrityMismatch
 )
 
 // TypeNames associates a warning code to its name.
@@ -84,6 +92,10 @@ var TypeNames = map[Type]string{
 	InstanceTypeNotOperational:        "Instance type not operational",
 	StoragePoolUnvailable:             "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:  "Unable to update cluster certificate",
+	StorageVolumeQuotaNotEnforced:     "Storage volume size limits not enforced",
+	RootlessMode:                      "Running in rootless mode",
+	StoragePoolDegraded:               "Storage pool degraded",
+	StorageVolumeIntegrityMismatch:    "Storage volume integrity mismatch",
 }
 
 // Severity returns the severity of the warning type.
@@ -137,6 +149,14 @@ func (t Type) Severity() Severity {
 		return SeverityHigh
 	case UnableToUpdateClusterCertificate:
 		return SeverityLow
+	case StorageVolumeQuotaNotEnforced:
+		return SeverityModerate
+	case RootlessMode:
+		return SeverityModerate
+	case StoragePoolDegraded:
+		return SeverityHigh
+	case StorageVolumeIntegrityMismatch:
+		return SeverityHigh
 	}
 
 	return SeverityLow
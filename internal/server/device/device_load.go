@@ -88,6 +88,8 @@ func newByType(state *state.State, projectName string, conf deviceConfig.Device)
 		dev = &tpm{}
 	case "pci":
 		dev = &pci{}
+	case "shmem":
+		dev = &shmem{}
 	}
 
 	// Check a valid device type has been found.
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -83,6 +84,44 @@ func DiskGetRBDFormat(clusterName string, userName string, poolName string, volu
 	return fmt.Sprintf("%s%s%s/%s%s%s", RBDFormatPrefix, RBDFormatSeparator, optEscaper.Replace(poolName), optEscaper.Replace(volumeName), RBDFormatSeparator, strings.Join(opts, ":"))
 }
 
+// validateDiskSerial validates the serial number of a VM disk.
+// The length is limited by the virtio-blk and NVMe buses which only expose 20 characters.
+func validateDiskSerial(value string) error {
+	if len(value) > 20 {
+		return errors.New("Disk serial can't be longer than 20 characters")
+	}
+
+	for _, r := range value {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && !strings.ContainsRune("-_.", r) {
+			return fmt.Errorf("Disk serial contains invalid character %q", r)
+		}
+	}
+
+	return nil
+}
+
+// validateDiskWWN validates the World Wide Name of a VM disk.
+func validateDiskWWN(value string) error {
+	_, err := DiskParseWWN(value)
+
+	return err
+}
+
+// DiskParseWWN parses a World Wide Name in its "0x" prefixed 64-bit hexadecimal form.
+func DiskParseWWN(value string) (uint64, error) {
+	hex, ok := strings.CutPrefix(strings.ToLower(value), "0x")
+	if !ok || len(hex) != 16 {
+		return 0, fmt.Errorf("Invalid disk WWN %q (must be 0x followed by 16 hexadecimal digits)", value)
+	}
+
+	wwn, err := strconv.ParseUint(hex, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid disk WWN %q: %w", value, err)
+	}
+
+	return wwn, nil
+}
+
 // BlockFsDetect detects the type of block device.
 func BlockFsDetect(dev string) (string, error) {
 	out, err := subprocess.RunCommand("blkid", "-s", "TYPE", "-o", "value", dev)
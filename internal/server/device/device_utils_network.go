@@ -503,24 +503,24 @@ func networkSetupHostVethLimits(d *deviceCommon, oldConfig deviceConfig.Device,
 		return fmt.Errorf("Unknown or missing host side veth device %q", veth)
 	}
 
-	// Apply max limit
-	if d.config["limits.max"] != "" {
-		d.config["limits.ingress"] = d.config["limits.max"]
-		d.config["limits.egress"] = d.config["limits.max"]
+	// Get the limits currently in effect according to the schedule.
+	ingress, egress, err := nicScheduledLimits(d.config, time.Now())
+	if err != nil {
+		return err
 	}
 
 	// Parse the values
 	var ingressInt int64
-	if d.config["limits.ingress"] != "" {
-		ingressInt, err = units.ParseBitSizeString(d.config["limits.ingress"])
+	if ingress != "" {
+		ingressInt, err = units.ParseBitSizeString(ingress)
 		if err != nil {
 			return err
 		}
 	}
 
 	var egressInt int64
-	if d.config["limits.egress"] != "" {
-		egressInt, err = units.ParseBitSizeString(d.config["limits.egress"])
+	if egress != "" {
+		egressInt, err = units.ParseBitSizeString(egress)
 		if err != nil {
 			return err
 		}
@@ -533,7 +533,7 @@ func networkSetupHostVethLimits(d *deviceCommon, oldConfig deviceConfig.Device,
 	_ = qdisc.Delete()
 
 	// Apply new limits
-	if d.config["limits.ingress"] != "" {
+	if ingress != "" {
 		qdiscHTB := &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: veth, Handle: "1:0", Root: true}, Default: "10"}
 		err := qdiscHTB.Add()
 		if err != nil {
@@ -553,7 +553,7 @@ func networkSetupHostVethLimits(d *deviceCommon, oldConfig deviceConfig.Device,
 		}
 	}
 
-	if d.config["limits.egress"] != "" {
+	if egress != "" {
 		qdisc = &ip.Qdisc{Dev: veth, Handle: "ffff:0", Ingress: true}
 		err := qdisc.Add()
 		if err != nil {
@@ -599,6 +599,223 @@ func networkSetupHostVethLimits(d *deviceCommon, oldConfig deviceConfig.Device,
 	return nil
 }
 
+// nicLimitsScheduleDays are the day names of the limits.schedule option, in time.Weekday order.
+var nicLimitsScheduleDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// nicLimitsScheduleEntry is an entry of the limits.schedule option of a NIC device.
+type nicLimitsScheduleEntry struct {
+	days [7]bool
+
+	// start and end are the minutes since midnight of the time window, which spans midnight if end isn't after start.
+	start int
+	end   int
+
+	// limits holds the "ingress", "egress" and "max" limits of the entry, "none" lifting the limit.
+	limits map[string]string
+}
+
+// active returns whether the entry applies at the given time.
+func (e nicLimitsScheduleEntry) active(t time.Time) bool {
+	minutes := t.Hour()*60 + t.Minute()
+	day := int(t.Weekday())
+
+	if e.start < e.end {
+		return e.days[day] && minutes >= e.start && minutes < e.end
+	}
+
+	return (e.days[day] && minutes >= e.start) || (e.days[(day+6)%7] && minutes < e.end)
+}
+
+// parseNICLimitsScheduleDays parses a comma separated list of days and day ranges, or "*" for every day.
+func parseNICLimitsScheduleDays(value string) ([7]bool, error) {
+	var days [7]bool
+
+	if value == "*" {
+		for i := range days {
+			days[i] = true
+		}
+
+		return days, nil
+	}
+
+	for _, field := range strings.Split(value, ",") {
+		first, last, isRange := strings.Cut(field, "-")
+		if !isRange {
+			last = first
+		}
+
+		firstIndex := slices.Index(nicLimitsScheduleDays, first)
+		lastIndex := slices.Index(nicLimitsScheduleDays, last)
+		if firstIndex < 0 || lastIndex < 0 {
+			return days, fmt.Errorf("Invalid days %q", field)
+		}
+
+		// Ranges can wrap around the end of the week, like "sat-mon".
+		for i := firstIndex; ; i = (i + 1) % 7 {
+			days[i] = true
+
+			if i == lastIndex {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+// parseNICLimitsScheduleTime parses a time of day in the "HH:MM" form and returns the minutes since midnight.
+func parseNICLimitsScheduleTime(value string) (int, error) {
+	hours, minutes, found := strings.Cut(value, ":")
+	if !found || len(hours) != 2 || len(minutes) != 2 {
+		return -1, fmt.Errorf("Invalid time %q, expected \"HH:MM\"", value)
+	}
+
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 || h > 24 {
+		return -1, fmt.Errorf("Invalid time %q, expected \"HH:MM\"", value)
+	}
+
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return -1, fmt.Errorf("Invalid time %q, expected \"HH:MM\"", value)
+	}
+
+	return h*60 + m, nil
+}
+
+// parseNICLimitsSchedule parses the limits.schedule option of a NIC device.
+// The entries are separated by semicolons and have the "<days> <start>-<end> <key>=<limit>..." form, where the
+// keys are "ingress", "egress" or "max" and the limit is a bit rate or "none".
+func parseNICLimitsSchedule(value string) ([]nicLimitsScheduleEntry, error) {
+	entries := []nicLimitsScheduleEntry{}
+
+	for _, entryValue := range util.SplitNTrimSpace(value, ";", -1, true) {
+		fields := strings.Fields(entryValue)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("Invalid schedule entry %q, expected \"<days> <start>-<end> <key>=<limit>...\"", entryValue)
+		}
+
+		days, err := parseNICLimitsScheduleDays(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		startValue, endValue, found := strings.Cut(fields[1], "-")
+		if !found {
+			return nil, fmt.Errorf("Invalid time window %q, expected \"<start>-<end>\"", fields[1])
+		}
+
+		entry := nicLimitsScheduleEntry{days: days, limits: map[string]string{}}
+
+		entry.start, err = parseNICLimitsScheduleTime(startValue)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.end, err = parseNICLimitsScheduleTime(endValue)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.start == entry.end {
+			return nil, fmt.Errorf("Empty time window %q", fields[1])
+		}
+
+		for _, limitValue := range fields[2:] {
+			key, limit, found := strings.Cut(limitValue, "=")
+			if !found || !slices.Contains([]string{"ingress", "egress", "max"}, key) {
+				return nil, fmt.Errorf("Invalid limit %q, expected \"ingress\", \"egress\" or \"max\"", limitValue)
+			}
+
+			if limit != "none" {
+				_, err := units.ParseBitSizeString(limit)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid %s limit %q: %w", key, limit, err)
+				}
+			}
+
+			entry.limits[key] = limit
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// validateNICLimitsSchedule validates the limits.schedule option of a NIC device.
+func validateNICLimitsSchedule(value string) error {
+	_, err := parseNICLimitsSchedule(value)
+	return err
+}
+
+// nicScheduledLimits returns the ingress and egress limits of the NIC device at the given time.
+// The first entry of limits.schedule which applies at that time overrides the limits of the device.
+// An empty limit means that the traffic isn't limited.
+func nicScheduledLimits(config deviceConfig.Device, t time.Time) (string, string, error) {
+	ingress := config["limits.ingress"]
+	egress := config["limits.egress"]
+
+	if config["limits.max"] != "" {
+		ingress = config["limits.max"]
+		egress = config["limits.max"]
+	}
+
+	entries, err := parseNICLimitsSchedule(config["limits.schedule"])
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, entry := range entries {
+		if !entry.active(t) {
+			continue
+		}
+
+		for _, key := range []string{"max", "ingress", "egress"} {
+			limit, ok := entry.limits[key]
+			if !ok {
+				continue
+			}
+
+			if limit == "none" {
+				limit = ""
+			}
+
+			if key != "egress" {
+				ingress = limit
+			}
+
+			if key != "ingress" {
+				egress = limit
+			}
+		}
+
+		break
+	}
+
+	return ingress, egress, nil
+}
+
+// NICLimitsScheduleChanged returns whether the limits of the NIC device changed between the two times because of
+// its limits.schedule option.
+func NICLimitsScheduleChanged(config deviceConfig.Device, since time.Time, now time.Time) bool {
+	if config["limits.schedule"] == "" {
+		return false
+	}
+
+	ingressSince, egressSince, err := nicScheduledLimits(config, since)
+	if err != nil {
+		return false
+	}
+
+	ingressNow, egressNow, err := nicScheduledLimits(config, now)
+	if err != nil {
+		return false
+	}
+
+	return ingressSince != ingressNow || egressSince != egressNow
+}
+
 // networkClearHostVethLimits clears any network rate limits to the veth device specified in the config.
 func networkClearHostVethLimits(d *deviceCommon) error {
 	err := d.state.Firewall.InstanceClearNetPrio(d.inst.Project().Name, d.inst.Name(), d.config["host_name"])
@@ -0,0 +1,62 @@
+package device
+
+import (
+	"fmt"
+	"time"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+)
+
+func Example_nicScheduledLimits() {
+	config := deviceConfig.Device{
+		"limits.max":      "100Mbit",
+		"limits.schedule": "mon-fri 09:00-17:00 max=10Mbit; sat-sun 22:00-06:00 ingress=none egress=1Gbit",
+	}
+
+	tests := []string{
+		"2026-10-14 10:00", // Wednesday, business hours
+		"2026-10-14 17:00", // Wednesday, after business hours
+		"2026-10-17 23:00", // Saturday night
+		"2026-10-19 05:00", // Monday morning, after Sunday night
+		"2026-10-20 05:00", // Tuesday morning
+	}
+
+	for _, v := range tests {
+		t, _ := time.Parse("2006-01-02 15:04", v)
+		ingress, egress, err := nicScheduledLimits(config, t)
+		fmt.Printf("%s: %q %q %v\n", v, ingress, egress, err)
+	}
+
+	// Output: 2026-10-14 10:00: "10Mbit" "10Mbit" <nil>
+	// 2026-10-14 17:00: "100Mbit" "100Mbit" <nil>
+	// 2026-10-17 23:00: "" "1Gbit" <nil>
+	// 2026-10-19 05:00: "" "1Gbit" <nil>
+	// 2026-10-20 05:00: "100Mbit" "100Mbit" <nil>
+}
+
+func Example_validateNICLimitsSchedule() {
+	tests := []string{
+		"* 00:00-24:00 max=1Gbit",
+		"mon,wed,fri-sun 08:30-12:00 ingress=10Mbit egress=5Mbit",
+		"mon 09:00-17:00",
+		"monday 09:00-17:00 max=10Mbit",
+		"mon 9:00-17:00 max=10Mbit",
+		"mon 09:00-09:00 max=10Mbit",
+		"mon 09:00-17:00 limit=10Mbit",
+		"mon 09:00-17:00 max=fast",
+	}
+
+	for _, v := range tests {
+		err := validateNICLimitsSchedule(v)
+		fmt.Printf("%s, %t\n", v, err == nil)
+	}
+
+	// Output: * 00:00-24:00 max=1Gbit, true
+	// mon,wed,fri-sun 08:30-12:00 ingress=10Mbit egress=5Mbit, true
+	// mon 09:00-17:00, false
+	// monday 09:00-17:00 max=10Mbit, false
+	// mon 9:00-17:00 max=10Mbit, false
+	// mon 09:00-09:00 max=10Mbit, false
+	// mon 09:00-17:00 limit=10Mbit, false
+	// mon 09:00-17:00 max=fast, false
+}
@@ -363,6 +363,25 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 		//  required: no
 		//  shortdesc: Only for VMs: Override the bus for the device
 		"io.bus": validate.Optional(validate.IsOneOf("nvme", "virtio-blk", "virtio-scsi", "auto", "9p", "virtiofs", "usb")),
+
+		// gendoc:generate(entity=devices, group=disk, key=io.serial)
+		// This sets the serial number of the disk as seen by the guest, in place of the one derived from the device name.
+		// It's made of up to 20 letters, digits, `-`, `_` or `.` characters.
+		// The guest uses it to name the disk in `/dev/disk/by-id/`.
+		// ---
+		//  type: string
+		//  required: no
+		//  shortdesc: Only for VMs: Serial number of the disk
+		"io.serial": validate.Optional(validateDiskSerial),
+
+		// gendoc:generate(entity=devices, group=disk, key=io.wwn)
+		// This sets the World Wide Name of the disk as seen by the guest, as a 64-bit hexadecimal value (for example, `0x5000c500a1b2c3d4`).
+		// It's only supported on the `virtio-scsi` bus.
+		// ---
+		//  type: string
+		//  required: no
+		//  shortdesc: Only for VMs: World Wide Name of the disk
+		"io.wwn": validate.Optional(validateDiskWWN),
 	}
 
 	err := d.config.Validate(rules)
@@ -378,6 +397,14 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 		return errors.New("IO cache configuration cannot be applied to containers")
 	}
 
+	if instConf.Type() == instancetype.Container && (d.config["io.serial"] != "" || d.config["io.wwn"] != "") {
+		return errors.New("Disk serial and WWN cannot be applied to containers")
+	}
+
+	if d.config["io.wwn"] != "" && !slices.Contains([]string{"", "virtio-scsi"}, d.config["io.bus"]) {
+		return errors.New("Disk WWN can only be set on the virtio-scsi bus")
+	}
+
 	if d.config["required"] != "" && d.config["optional"] != "" {
 		return errors.New(`Cannot use both "required" and deprecated "optional" properties at the same time`)
 	}
@@ -514,6 +541,11 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 				if len(usedBy) > 0 {
 					return errors.New("Cannot add un-shared custom storage block volume to more than one instance")
 				}
+			} else if contentType == db.StoragePoolVolumeContentTypeBlock && instConf.Type() != instancetype.Any {
+				err = d.validateSharedBlockVolume(storageProjectName, dbVolume)
+				if err != nil {
+					return err
+				}
 			}
 		}
 
@@ -666,6 +698,39 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 	return nil
 }
 
+// validateSharedBlockVolume checks that a shared custom block volume can be attached to instances on several
+// cluster members at once. This requires a storage driver which makes the volume available on all members and
+// direct I/O, so that no member caches data which another member may modify.
+func (d *disk) validateSharedBlockVolume(storageProjectName string, dbVolume *db.StorageVolume) error {
+	var remoteMembers []string
+
+	err := storagePools.VolumeUsedByInstanceDevices(d.state, d.pool.Name(), storageProjectName, &dbVolume.StorageVolume, true, func(inst db.InstanceArgs, project api.Project, usedByDevices []string) error {
+		if inst.Node != d.state.ServerName && !slices.Contains(remoteMembers, inst.Node) {
+			remoteMembers = append(remoteMembers, inst.Node)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(remoteMembers) == 0 {
+		return nil
+	}
+
+	info := d.pool.Driver().Info()
+	if !info.VolumeMultiNode || !info.DirectIO {
+		return fmt.Errorf("Storage driver %q can't guarantee the coherence of shared custom block volumes used on several cluster members", info.Name)
+	}
+
+	if !slices.Contains([]string{"", "none"}, d.config["io.cache"]) {
+		return fmt.Errorf("Shared custom block volumes used on several cluster members (%s) require io.cache to be none", strings.Join(remoteMembers, ", "))
+	}
+
+	return nil
+}
+
 // getDevicePath returns the absolute path on the host for this instance and supplied device config.
 func (d *disk) getDevicePath(devName string, devConfig deviceConfig.Device) string {
 	relativeDestPath := strings.TrimPrefix(devConfig["path"], "/")
@@ -738,7 +803,8 @@ func (d *disk) UpdatableFields(oldDevice Type) []string {
 		return []string{}
 	}
 
-	return []string{"limits.max", "limits.read", "limits.write", "size", "size.state"}
+	// The boot priority is only used when the instance starts.
+	return []string{"boot.priority", "limits.max", "limits.read", "limits.write", "size", "size.state"}
 }
 
 // Register calls mount for the disk volume (which should already be mounted) to reinitialize the reference counter
@@ -1043,15 +1109,25 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 		opts = append(opts, fmt.Sprintf("cache=%s", d.config["io.cache"]))
 	}
 
+	// Allow the user to set a stable identity for the disk.
+	if d.config["io.serial"] != "" {
+		opts = append(opts, fmt.Sprintf("serial=%s", d.config["io.serial"]))
+	}
+
+	if d.config["io.wwn"] != "" {
+		opts = append(opts, fmt.Sprintf("wwn=%s", d.config["io.wwn"]))
+	}
+
 	// Add I/O limits if set.
 	var diskLimits *deviceConfig.DiskLimits
-	if d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "" {
-		// Parse the limits into usable values.
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
-		if err != nil {
-			return nil, err
-		}
 
+	// Parse the limits into usable values.
+	readBps, readIops, writeBps, writeIops, err := d.effectiveLimit(d.config)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "" || readBps > 0 || readIops > 0 || writeBps > 0 || writeIops > 0 {
 		diskLimits = &deviceConfig.DiskLimits{
 			ReadBytes:  readBps,
 			ReadIOps:   readIops,
@@ -1197,6 +1273,18 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 					mount.FSType = "iso9660"
 				}
 
+				// Don't use a shared block volume while a cluster member which may still be writing to it isn't fenced.
+				if contentType == db.StoragePoolVolumeContentTypeBlock && util.IsTrue(dbVolume.Config["security.shared"]) {
+					remoteInstance, err := storagePools.VolumeUsedByUnfencedRemoteInstance(d.state, d.pool.Name(), storageProjectName, &dbVolume.StorageVolume)
+					if err != nil {
+						return nil, fmt.Errorf("Failed checking fencing of shared custom volume: %w", err)
+					}
+
+					if remoteInstance != nil {
+						return nil, fmt.Errorf("Shared custom volume %q is used by instance %q on offline cluster member %q, evacuate the member before using the volume elsewhere", volName, remoteInstance.Name, remoteInstance.Node)
+					}
+				}
+
 				// If the pool is ceph backed and a block device, don't mount it, instead pass config to QEMU instance
 				// to use the built in RBD support.
 				if d.pool.Driver().Info().Name == "ceph" && (contentType == db.StoragePoolVolumeContentTypeBlock || contentType == db.StoragePoolVolumeContentTypeISO) {
@@ -1253,6 +1341,10 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 					return nil, err
 				}
 
+				if d.config["io.serial"] != "" || d.config["io.wwn"] != "" {
+					return nil, errors.New("Disk serial and WWN can only be set on block devices")
+				}
+
 				err = validate.Optional(validate.IsOneOf("none", "metadata", "unsafe"))(d.config["io.cache"])
 				if err != nil {
 					return nil, err
@@ -1505,7 +1597,7 @@ func (d *disk) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 
 		if d.inst.Type() == instancetype.VM {
 			// Parse the limits into usable values.
-			readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
+			readBps, readIops, writeBps, writeIops, err := d.effectiveLimit(d.config)
 			if err != nil {
 				return err
 			}
@@ -1612,6 +1704,18 @@ func (d *disk) generateLimits(runConf *deviceConfig.RunConfig) error {
 
 		if dev["limits.read"] != "" || dev["limits.write"] != "" || dev["limits.max"] != "" {
 			hasDiskLimits = true
+			break
+		}
+
+		// Check for limits set on the custom volume.
+		volLimits, err := d.volumeLimits(dev)
+		if err != nil {
+			return err
+		}
+
+		if volLimits != nil {
+			hasDiskLimits = true
+			break
 		}
 	}
 
@@ -2285,7 +2389,7 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 		}
 
 		// Parse the user input
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(dev)
+		readBps, readIops, writeBps, writeIops, err := d.effectiveLimit(dev)
 		if err != nil {
 			return nil, err
 		}
@@ -2449,6 +2553,70 @@ func (d *disk) parseLimit(dev deviceConfig.Device) (int64, int64, int64, int64,
 	return readBps, readIops, writeBps, writeIops, nil
 }
 
+// volumeLimits returns the I/O limits of the custom volume used as the source of the disk configuration.
+// Nil is returned when the source isn't a custom volume or when the volume doesn't have limits.
+func (d *disk) volumeLimits(dev deviceConfig.Device) (*storagePools.VolumeLimits, error) {
+	if dev["pool"] == "" || dev["source"] == "" || internalInstance.IsRootDiskDevice(dev) {
+		return nil, nil
+	}
+
+	pool, err := storagePools.LoadByName(d.state, dev["pool"])
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get storage pool %q: %w", dev["pool"], err)
+	}
+
+	// Derive the effective storage project name from the instance config's project.
+	storageProjectName, err := project.StorageVolumeProject(d.state.DB.Cluster, d.inst.Project().Name, db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the volume name and path.
+	volFields := strings.SplitN(dev["source"], "/", 2)
+	volName := volFields[0]
+
+	// GetStoragePoolVolume returns a volume with an empty Location field for remote drivers.
+	var dbVolume *db.StorageVolume
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVolume, err = tx.GetStoragePoolVolume(ctx, pool.ID(), storageProjectName, db.StoragePoolVolumeTypeCustom, volName, true)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading custom volume: %w", err)
+	}
+
+	return storagePools.VolumeLimitsFromConfig(dbVolume.Config)
+}
+
+// effectiveLimit returns the I/O bytes/iops limits of the disk configuration, combined with those of its
+// custom volume. When both set a limit, the lowest one applies.
+func (d *disk) effectiveLimit(dev deviceConfig.Device) (int64, int64, int64, int64, error) {
+	readBps, readIops, writeBps, writeIops, err := d.parseLimit(dev)
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+
+	volLimits, err := d.volumeLimits(dev)
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+
+	if volLimits == nil {
+		return readBps, readIops, writeBps, writeIops, nil
+	}
+
+	// lowestLimit returns the lowest of two limits, zero meaning no limit.
+	lowestLimit := func(a int64, b int64) int64 {
+		if a == 0 || (b > 0 && b < a) {
+			return b
+		}
+
+		return a
+	}
+
+	return lowestLimit(readBps, volLimits.ReadBytes), lowestLimit(readIops, volLimits.ReadIOps), lowestLimit(writeBps, volLimits.WriteBytes), lowestLimit(writeIops, volLimits.WriteIOps), nil
+}
+
 func (d *disk) getParentBlocks(path string) ([]string, error) {
 	var devices []string
 	var dev []string
@@ -29,6 +29,7 @@ func nicValidationRules(requiredFields []string, optionalFields []string, instCo
 		"limits.egress":                        validate.IsAny,
 		"limits.max":                           validate.IsAny,
 		"limits.priority":                      validate.Optional(validate.IsUint32),
+		"limits.schedule":                      validate.Optional(validateNICLimitsSchedule),
 		"security.mac_filtering":               validate.IsAny,
 		"security.ipv4_filtering":              validate.IsAny,
 		"security.ipv6_filtering":              validate.IsAny,
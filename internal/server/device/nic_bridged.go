@@ -164,6 +164,14 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 		//  shortdesc: The priority for outgoing traffic, to be used by the kernel queuing discipline to prioritize network packets
 		"limits.priority",
 
+		// gendoc:generate(entity=devices, group=nic_bridged, key=limits.schedule)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: Time windows with different I/O limits (see {ref}devices-nic-limits-schedule)
+		"limits.schedule",
+
 		// gendoc:generate(entity=devices, group=nic_bridged, key=ipv4.address)
 		//
 		// ---
@@ -693,7 +701,7 @@ func (d *nicBridged) UpdatableFields(oldDevice Type) []string {
 		return []string{}
 	}
 
-	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering", "security.acls", "security.acls.default.egress.action", "security.acls.default.egress.logged", "security.acls.default.ingress.action", "security.acls.default.ingress.logged"}
+	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "limits.schedule", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering", "security.acls", "security.acls.default.egress.action", "security.acls.default.egress.logged", "security.acls.default.ingress.action", "security.acls.default.ingress.logged"}
 }
 
 // Add is run when a device is added to a non-snapshot instance whether or not the instance is running.
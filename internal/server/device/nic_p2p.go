@@ -97,6 +97,13 @@ func (d *nicP2P) validateConfig(instConf instance.ConfigReader) error {
 		//  shortdesc: The priority for outgoing traffic, to be used by the kernel queuing discipline to prioritize network packets
 		"limits.priority",
 
+		// gendoc:generate(entity=devices, group=nic_p2p, key=limits.schedule)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: Time windows with different I/O limits (see {ref}devices-nic-limits-schedule)
+		"limits.schedule",
+
 		// gendoc:generate(entity=devices, group=nic_p2p, key=ipv4.routes)
 		//
 		// ---
@@ -152,7 +159,7 @@ func (d *nicP2P) UpdatableFields(oldDevice Type) []string {
 		return []string{}
 	}
 
-	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "ipv4.routes", "ipv6.routes"}
+	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "limits.schedule", "ipv4.routes", "ipv6.routes"}
 }
 
 // Start is run when the device is added to a running instance or instance is starting up.
@@ -44,7 +44,7 @@ func (d *nicRouted) UpdatableFields(oldDevice Type) []string {
 		return []string{}
 	}
 
-	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority"}
+	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "limits.schedule"}
 }
 
 // validateConfig checks the supplied config for correctness.
@@ -141,6 +141,13 @@ func (d *nicRouted) validateConfig(instConf instance.ConfigReader) error {
 		//  shortdesc: The priority for outgoing traffic, to be used by the kernel queuing discipline to prioritize network packets
 		"limits.priority",
 
+		// gendoc:generate(entity=devices, group=nic_routed, key=limits.schedule)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: Time windows with different I/O limits (see {ref}devices-nic-limits-schedule)
+		"limits.schedule",
+
 		// gendoc:generate(entity=devices, group=nic_routed, key=ipv4.gateway)
 		//
 		// ---
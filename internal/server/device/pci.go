@@ -32,6 +32,14 @@ func (d *pci) validateConfig(instConf instance.ConfigReader) error {
 		//  required: yes
 		//  shortdesc: PCI address of the device
 		"address": validate.IsPCIAddress,
+
+		// gendoc:generate(entity=devices, group=pci, key=boot.priority)
+		//
+		// ---
+		//  type: integer
+		//  required: no
+		//  shortdesc: Boot priority for VMs (higher value boots first)
+		"boot.priority": validate.Optional(validate.IsUint32),
 	}
 
 	err := d.config.Validate(rules)
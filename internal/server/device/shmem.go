@@ -0,0 +1,276 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/idmap"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// shmemDefaultSize is the size of shared memory regions when not specified.
+const shmemDefaultSize = "4MiB"
+
+type shmem struct {
+	deviceCommon
+}
+
+// CanMigrate returns whether the device can be migrated to any other cluster member.
+func (d *shmem) CanMigrate() bool {
+	return true
+}
+
+// validateConfig checks the supplied config for correctness.
+func (d *shmem) validateConfig(instConf instance.ConfigReader) error {
+	if !instanceSupported(instConf.Type(), instancetype.Container, instancetype.VM) {
+		return ErrUnsupportedDevType
+	}
+
+	rules := map[string]func(string) error{
+		// gendoc:generate(entity=devices, group=shmem, key=name)
+		// Instances of the same project using a region with the same name share its memory.
+		// ---
+		//  type: string
+		//  default: device name
+		//  shortdesc: Name of the shared memory region
+		"name": validate.Optional(validate.IsHostname),
+
+		// gendoc:generate(entity=devices, group=shmem, key=size)
+		// The size must be a power of two and be the same for all instances sharing the region.
+		// ---
+		//  type: string
+		//  default: `4MiB`
+		//  shortdesc: Size of the shared memory region
+		"size": validate.Optional(func(value string) error {
+			size, err := units.ParseByteSizeString(value)
+			if err != nil {
+				return err
+			}
+
+			if size <= 0 || size&(size-1) != 0 {
+				return errors.New("Size must be a power of two")
+			}
+
+			return nil
+		}),
+	}
+
+	if instConf.Type() == instancetype.Container {
+		// gendoc:generate(entity=devices, group=shmem, key=path)
+		//
+		// ---
+		//  type: string
+		//  required: for containers
+		//  shortdesc: Only for containers: path of the region file inside the instance (for example, `/srv/shmem`)
+		rules["path"] = validate.IsNotEmpty
+
+		// gendoc:generate(entity=devices, group=shmem, key=uid)
+		//
+		// ---
+		//  type: int
+		//  default: 0
+		//  shortdesc: Only for containers: UID of the region file owner in the instance
+		rules["uid"] = unixValidUserID
+
+		// gendoc:generate(entity=devices, group=shmem, key=gid)
+		//
+		// ---
+		//  type: int
+		//  default: 0
+		//  shortdesc: Only for containers: GID of the region file owner in the instance
+		rules["gid"] = unixValidUserID
+
+		// gendoc:generate(entity=devices, group=shmem, key=mode)
+		//
+		// ---
+		//  type: int
+		//  default: 0660
+		//  shortdesc: Only for containers: mode of the region file in the instance
+		rules["mode"] = unixValidOctalFileMode
+	}
+
+	err := d.config.Validate(rules)
+	if err != nil {
+		return fmt.Errorf("Failed to validate config: %w", err)
+	}
+
+	return nil
+}
+
+// regionPath returns the path of the file backing the shared memory region on the host.
+func (d *shmem) regionPath() string {
+	name := d.config["name"]
+	if name == "" {
+		name = d.name
+	}
+
+	return internalUtil.RunPath("shmem", d.inst.Project().Name, name)
+}
+
+// regionSize returns the size of the shared memory region.
+func (d *shmem) regionSize() (int64, error) {
+	size := d.config["size"]
+	if size == "" {
+		size = shmemDefaultSize
+	}
+
+	return units.ParseByteSizeString(size)
+}
+
+// setupRegion creates the file backing the shared memory region unless another instance already did.
+func (d *shmem) setupRegion() (string, int64, error) {
+	path := d.regionPath()
+
+	size, err := d.regionSize()
+	if err != nil {
+		return "", -1, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o711)
+	if err != nil {
+		return "", -1, fmt.Errorf("Failed to create shared memory path: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return "", -1, fmt.Errorf("Failed to open shared memory region %q: %w", path, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", -1, err
+	}
+
+	if fi.Size() == 0 {
+		err = f.Truncate(size)
+		if err != nil {
+			return "", -1, fmt.Errorf("Failed to resize shared memory region %q: %w", path, err)
+		}
+	} else if fi.Size() != size {
+		return "", -1, fmt.Errorf("Shared memory region %q is already in use with a size of %d bytes", filepath.Base(path), fi.Size())
+	}
+
+	return path, size, nil
+}
+
+// Start is run when the device is added to the instance.
+func (d *shmem) Start() (*deviceConfig.RunConfig, error) {
+	path, size, err := d.setupRegion()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.inst.Type() == instancetype.VM {
+		return &deviceConfig.RunConfig{
+			ShmemDevice: []deviceConfig.RunConfigItem{
+				{Key: "devName", Value: d.name},
+				{Key: "path", Value: path},
+				{Key: "size", Value: strconv.FormatInt(size, 10)},
+			},
+		}, nil
+	}
+
+	err = d.setupOwnership(path)
+	if err != nil {
+		return nil, err
+	}
+
+	runConf := deviceConfig.RunConfig{}
+	runConf.Mounts = append(runConf.Mounts, deviceConfig.MountEntryItem{
+		DevName:    d.name,
+		DevPath:    path,
+		TargetPath: strings.TrimPrefix(d.config["path"], "/"),
+		FSType:     "none",
+		Opts:       []string{"bind", "create=file"},
+		OwnerShift: deviceConfig.MountOwnerShiftNone,
+	})
+
+	return &runConf, nil
+}
+
+// setupOwnership applies the ownership and mode of the region file requested for the container.
+func (d *shmem) setupOwnership(path string) error {
+	mode := os.FileMode(unixDefaultMode)
+	if d.config["mode"] != "" {
+		tmp, err := unixDeviceModeOct(d.config["mode"])
+		if err != nil {
+			return err
+		}
+
+		mode = os.FileMode(tmp)
+	}
+
+	var uid, gid int64
+	var err error
+	if d.config["uid"] != "" {
+		uid, err = strconv.ParseInt(d.config["uid"], 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.config["gid"] != "" {
+		gid, err = strconv.ParseInt(d.config["gid"], 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Get the container's idmap.
+	c, ok := d.inst.(instance.Container)
+	if !ok {
+		return errors.New("Failed to cast instance to container")
+	}
+
+	var idmapSet *idmap.Set
+	if c.IsRunning() {
+		idmapSet, err = c.CurrentIdmap()
+	} else {
+		idmapSet, err = c.NextIdmap()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if idmapSet != nil {
+		uid, gid = idmapSet.ShiftFromNS(uid, gid)
+	}
+
+	err = os.Chown(path, int(uid), int(gid))
+	if err != nil {
+		return fmt.Errorf("Failed to set ownership of shared memory region: %w", err)
+	}
+
+	err = os.Chmod(path, mode)
+	if err != nil {
+		return fmt.Errorf("Failed to set mode of shared memory region: %w", err)
+	}
+
+	return nil
+}
+
+// Stop is run when the device is removed from the instance.
+func (d *shmem) Stop() (*deviceConfig.RunConfig, error) {
+	runConf := deviceConfig.RunConfig{}
+
+	if d.inst.Type() == instancetype.Container {
+		// Request an unmount of the region file inside the instance.
+		runConf.Mounts = append(runConf.Mounts, deviceConfig.MountEntryItem{
+			TargetPath: strings.TrimPrefix(d.config["path"], "/"),
+		})
+	}
+
+	return &runConf, nil
+}
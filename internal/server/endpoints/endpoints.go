@@ -180,6 +180,7 @@ func (e *Endpoints) up(config *Config) error {
 		metrics:        config.MetricsServer,
 		storageBuckets: config.StorageBucketsServer,
 		vmvsock:        config.VsockServer,
+		migration:      config.RestServer,
 	}
 
 	e.cert = config.Cert
@@ -396,6 +397,13 @@ func (e *Endpoints) Down() error {
 		}
 	}
 
+	if e.listeners[migration] != nil {
+		err := e.closeListener(migration)
+		if err != nil {
+			return err
+		}
+	}
+
 	if e.tomb != nil {
 		e.tomb.Kill(nil)
 		_ = e.tomb.Wait()
@@ -487,6 +495,7 @@ const (
 	metrics
 	vmvsock
 	storageBuckets
+	migration
 )
 
 // Human-readable descriptions of the various kinds of endpoints.
@@ -499,6 +508,7 @@ var descriptions = map[kind]string{
 	metrics:        "metrics socket",
 	vmvsock:        "VM socket",
 	storageBuckets: "Storage buckets socket",
+	migration:      "migration socket",
 }
 
 // Tomb tracks the lifecycle of one or more goroutines.
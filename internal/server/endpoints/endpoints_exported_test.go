@@ -45,6 +45,13 @@ func (e *Endpoints) ClusterAddressAndCert() (string, *localtls.CertInfo) {
 	return e.clusterAddress(), e.cert
 }
 
+// Return the migration address and server certificate of the migration
+// endpoint. This method is supposed to be used in conjunction with
+// the httpGetOverTLSSocket test helper.
+func (e *Endpoints) MigrationAddressAndCert() (string, *localtls.CertInfo) {
+	return e.MigrationAddress(), e.cert
+}
+
 // Set the file descriptor number marker that will be used when detecting
 // socket activation. Needed because "go test" might open unrelated file
 // descriptor starting at number 3.
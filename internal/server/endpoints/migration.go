@@ -0,0 +1,103 @@
+package endpoints
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/ports"
+	"github.com/lxc/incus/v6/internal/server/endpoints/listeners"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// MigrationAddress returns the network address of the migration endpoint, or an
+// empty string if there's no migration endpoint or it's provided by the network or cluster listener.
+func (e *Endpoints) MigrationAddress() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	listener := e.listeners[migration]
+	if listener == nil {
+		return ""
+	}
+
+	return listener.Addr().String()
+}
+
+// MigrationUpdateAddress updates the address for the migration endpoint, shutting
+// it down and restarting it.
+func (e *Endpoints) MigrationUpdateAddress(address string) error {
+	networkAddress := e.NetworkAddress()
+	clusterAddress := e.clusterAddress()
+
+	if address != "" {
+		address = internalUtil.CanonicalNetworkAddress(address, ports.HTTPSDefaultPort)
+	}
+
+	oldAddress := e.MigrationAddress()
+	if address == oldAddress {
+		return nil
+	}
+
+	logger.Infof("Update migration address")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Close the previous socket
+	_ = e.closeListener(migration)
+
+	// If turning off listening, we're done
+	if address == "" {
+		return nil
+	}
+
+	// If the address is already served by the network or cluster listener, we don't need a new listener.
+	for _, listenAddress := range []string{networkAddress, clusterAddress} {
+		if listenAddress != "" && internalUtil.IsAddressCovered(address, listenAddress) {
+			return nil
+		}
+	}
+
+	// Attempt to setup the new listening socket
+	getListener := func(address string) (*net.Listener, error) {
+		var err error
+		var listener net.Listener
+
+		for range 10 { // Ten retries over a second seems reasonable.
+			listener, err = net.Listen("tcp", address)
+			if err == nil {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Cannot listen on migration HTTPS socket %q: %w", address, err)
+		}
+
+		return &listener, nil
+	}
+
+	// set up the listener
+	listener, err := getListener(address)
+	if err != nil {
+		// Attempt to revert to the previous address
+		if oldAddress != "" {
+			listener, err1 := getListener(oldAddress)
+			if err1 == nil {
+				e.listeners[migration] = listeners.NewFancyTLSListener(*listener, e.cert)
+				e.serve(migration)
+			}
+		}
+
+		return err
+	}
+
+	e.listeners[migration] = listeners.NewFancyTLSListener(*listener, e.cert)
+	e.serve(migration)
+
+	return nil
+}
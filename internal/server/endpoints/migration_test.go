@@ -0,0 +1,36 @@
+package endpoints_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A migration address which differs from the network one gets its own TCP socket.
+func TestEndpoints_MigrationUpdateAddress(t *testing.T) {
+	endpoints, config, cleanup := newEndpoints(t)
+	defer cleanup()
+
+	config.NetworkAddress = "127.0.0.1:12345"
+	require.NoError(t, endpoints.Up(config))
+
+	require.NoError(t, endpoints.MigrationUpdateAddress("127.0.0.1:54321"))
+	assert.NoError(t, httpGetOverTLSSocket(endpoints.MigrationAddressAndCert()))
+
+	require.NoError(t, endpoints.MigrationUpdateAddress(""))
+	assert.Equal(t, "", endpoints.MigrationAddress())
+}
+
+// When the migration address is covered by the network one, no new port is opened.
+func TestEndpoints_MigrationUpdateAddressIsCovered(t *testing.T) {
+	endpoints, config, cleanup := newEndpoints(t)
+	defer cleanup()
+
+	config.NetworkAddress = "[::]:12345"
+	require.NoError(t, endpoints.Up(config))
+
+	require.NoError(t, endpoints.MigrationUpdateAddress("127.0.0.1:12345"))
+	assert.Equal(t, "", endpoints.MigrationAddress())
+	assert.NoError(t, httpGetOverTLSSocket(endpoints.NetworkAddressAndCert()))
+}
@@ -138,7 +138,7 @@ func (e *Endpoints) NetworkUpdateCert(cert *localtls.CertInfo) {
 	defer e.mu.Unlock()
 	e.cert = cert
 
-	for _, listenerKey := range []kind{network, cluster, vmvsock, storageBuckets, metrics} {
+	for _, listenerKey := range []kind{network, cluster, vmvsock, storageBuckets, metrics, migration} {
 		listener, found := e.listeners[listenerKey]
 		if found {
 			listener.(*listeners.FancyTLSListener).Config(cert)
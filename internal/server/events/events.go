@@ -40,6 +40,7 @@ type Server struct {
 
 	listeners map[string]*Listener
 	notify    NotifyFunc
+	history   NotifyFunc
 	location  string
 }
 
@@ -66,6 +67,15 @@ func (s *Server) SetLocalLocation(location string) {
 	s.location = location
 }
 
+// SetHistoryHandler sets the function called with the lifecycle events produced locally so they can be
+// recorded for later replay. The function is called with the server lock held and so mustn't block.
+func (s *Server) SetHistoryHandler(history NotifyFunc) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.history = history
+}
+
 // AddListener creates and returns a new event listener.
 func (s *Server) AddListener(projectName string, allProjects bool, projectPermissionFunc auth.PermissionChecker, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
 	if allProjects && projectName != "" {
@@ -175,6 +185,11 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 		s.notify(event)
 	}
 
+	// If a history hook is present, then call it for locally produced lifecycle events.
+	if s.history != nil && eventSource == EventSourceLocal && event.Type == api.EventTypeLifecycle {
+		s.history(event)
+	}
+
 	listeners := s.listeners
 	for _, listener := range listeners {
 		// If the event is project specific, check if the listener is requesting events from that project.
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// ReplayListenerConnection is a listener connection which holds the live events until the recorded ones have
+// been replayed, so that the listener receives them in order.
+type ReplayListenerConnection struct {
+	EventListenerConnection
+
+	replayed     chan struct{}
+	replayedOnce sync.Once
+}
+
+// NewReplayListenerConnection returns a new replay listener connection wrapping the connection.
+func NewReplayListenerConnection(connection EventListenerConnection) *ReplayListenerConnection {
+	return &ReplayListenerConnection{
+		EventListenerConnection: connection,
+		replayed:                make(chan struct{}),
+	}
+}
+
+// WriteJSON sends a live event once the recorded events have been replayed.
+func (c *ReplayListenerConnection) WriteJSON(event any) error {
+	<-c.replayed
+
+	return c.EventListenerConnection.WriteJSON(event)
+}
+
+// Replay sends the recorded events and then releases the live events.
+// The live events are also released if the events can't be sent.
+func (c *ReplayListenerConnection) Replay(events []api.Event) error {
+	defer c.replayedOnce.Do(func() { close(c.replayed) })
+
+	for _, event := range events {
+		err := c.EventListenerConnection.WriteJSON(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
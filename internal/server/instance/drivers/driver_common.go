@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"os"
@@ -20,6 +21,7 @@ import (
 	"github.com/google/uuid"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/migration"
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -31,6 +33,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance/operationlock"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/locking"
+	localMigration "github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/resources"
@@ -784,11 +787,56 @@ func (d *common) runHooks(hooks []func() error) error {
 	return nil
 }
 
+// snapshotsMaxCount returns the maximum number of snapshots of the instance, 0 meaning unlimited.
+// When both the instance and its project set a limit, the lowest one applies.
+func (d *common) snapshotsMaxCount() (int, error) {
+	maxCount := 0
+	for _, value := range []string{d.expandedConfig["snapshots.max_count"], d.project.Config["snapshots.max_count"]} {
+		if value == "" {
+			continue
+		}
+
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid snapshots.max_count %q: %w", value, err)
+		}
+
+		if count > 0 && (maxCount == 0 || count < maxCount) {
+			maxCount = count
+		}
+	}
+
+	return maxCount, nil
+}
+
 // snapshot handles the common part of the snapshotting process.
 func (d *common) snapshotCommon(inst instance.Instance, name string, expiry time.Time, stateful bool) error {
 	reverter := revert.New()
 	defer reverter.Fail()
 
+	// Enforce the maximum number of snapshots.
+	maxCount, err := d.snapshotsMaxCount()
+	if err != nil {
+		return err
+	}
+
+	var expiredSnapshots []instance.Instance
+	if maxCount > 0 {
+		snapshots, err := inst.Snapshots()
+		if err != nil {
+			return err
+		}
+
+		if len(snapshots) >= maxCount {
+			if util.IsFalseOrEmpty(d.expandedConfig["snapshots.max_count.expire_oldest"]) {
+				return api.StatusErrorf(http.StatusBadRequest, "Instance already has %d snapshots out of the maximum of %d (snapshots.max_count)", len(snapshots), maxCount)
+			}
+
+			// Snapshots are sorted by creation date, the oldest ones get deleted once the new one exists.
+			expiredSnapshots = snapshots[:len(snapshots)-maxCount+1]
+		}
+	}
+
 	// Setup the arguments.
 	args := db.InstanceArgs{
 		Project:      inst.Project().Name,
@@ -841,6 +889,15 @@ func (d *common) snapshotCommon(inst instance.Instance, name string, expiry time
 
 	reverter.Success()
 
+	for _, expiredSnapshot := range expiredSnapshots {
+		err = expiredSnapshot.Delete(true)
+		if err != nil {
+			return fmt.Errorf("Failed deleting oldest instance snapshot %q: %w", expiredSnapshot.Name(), err)
+		}
+
+		d.logger.Info("Deleted oldest instance snapshot to stay within snapshots.max_count", logger.Ctx{"snapshot": expiredSnapshot.Name()})
+	}
+
 	return nil
 }
 
@@ -891,7 +948,17 @@ func (d *common) insertConfigkey(key string, value string) (string, error) {
 
 // isRunningStatusCode returns if instance is running from status code.
 func (d *common) isRunningStatusCode(statusCode api.StatusCode) bool {
-	return statusCode != api.Error && statusCode != api.Stopped
+	return statusCode != api.Error && statusCode != api.Stopped && statusCode != api.Suspended
+}
+
+// renderStatusCode returns the status code reported through the API.
+// A stopped instance with a stored stateful checkpoint is reported as suspended.
+func (d *common) renderStatusCode(statusCode api.StatusCode) api.StatusCode {
+	if statusCode == api.Stopped && d.stateful {
+		return api.Suspended
+	}
+
+	return statusCode
 }
 
 // isStartableStatusCode returns an error if the status code means the instance cannot be started currently.
@@ -961,9 +1028,50 @@ func (d *common) validateStartup(stateful bool, statusCode api.StatusCode) error
 		return err
 	}
 
+	// Check that the instance fits within the overcommit policy of the server and project.
+	err = d.checkOvercommit()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// checkOvercommit checks that starting the instance doesn't commit more CPU or memory than allowed by the
+// overcommit ratios of the server and project.
+func (d *common) checkOvercommit() error {
+	policy := instance.GetOvercommitPolicy(d.state.GlobalConfig, d.project.Config)
+	if !policy.Enabled() {
+		return nil
+	}
+
+	cpu, err := resources.GetCPU()
+	if err != nil {
+		return fmt.Errorf("Failed getting CPU information: %w", err)
+	}
+
+	memory, err := resources.GetMemory()
+	if err != nil {
+		return fmt.Errorf("Failed getting memory information: %w", err)
+	}
+
+	requested, err := instance.InstanceAllocation(api.InstanceType(d.dbType.String()), d.expandedConfig, d.expandedDevices.CloneNative(), memory.Total)
+	if err != nil {
+		return err
+	}
+
+	var committed instance.Allocation
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		committed, err = instance.CommittedAllocation(ctx, tx, d.node, memory.Total, d.project.Name, d.name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting resources committed to running instances: %w", err)
+	}
+
+	return policy.Check(instance.AvailableAllocation(cpu.Total, memory.Total, d.state.GlobalConfig), committed, requested)
+}
+
 // onStopOperationSetup creates or picks up the relevant operation. This is used in the stopns and stop hooks to
 // ensure that a lock on their activities is held before the instance process is stopped. This prevents a start
 // request run at the same time from overlapping with the stop process.
@@ -1668,3 +1776,32 @@ func (d *common) ETag() []any {
 
 	return etag
 }
+
+// migrationStreams returns the additional filesystem connections agreed on in the migration header.
+// The connections are setup with the encryption and compression agreed on in the header.
+func (d *common) migrationStreams(args instance.MigrateArgs, header *migration.MigrationHeader, encryption *migration.Encryption) ([]io.ReadWriteCloser, error) {
+	count := int(header.GetFilesystemStreams())
+	if count < 2 || args.FilesystemStreamConns == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	conns, err := args.FilesystemStreamConns(ctx, count)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting additional migration filesystem connections: %w", err)
+	}
+
+	err = localMigration.EncryptConns(encryption, conns)
+	if err != nil {
+		return nil, err
+	}
+
+	err = localMigration.CompressConns(header, conns)
+	if err != nil {
+		return nil, err
+	}
+
+	return conns, nil
+}
@@ -436,6 +436,9 @@ type lxc struct {
 
 	cConfig  bool
 	idmapset *idmap.Set
+
+	// Generated keys overridden by raw.lxc when it was last loaded.
+	rawLXCConflicts []api.InstanceDebugLXCConfigConflict
 }
 
 var idmapLock sync.Mutex
@@ -2606,6 +2609,10 @@ ff02::2 ip6-allrouters
 		return "", nil, err
 	}
 
+	for _, conflict := range d.rawLXCConflicts {
+		d.logger.Warn("raw.lxc overrides configuration generated by Incus", logger.Ctx{"key": conflict.Key, "value": conflict.Value, "rawValue": conflict.RawValue})
+	}
+
 	// Generate the LXC config
 	configPath := filepath.Join(d.RunPath(), "lxc.conf")
 	err = cc.SaveConfigFile(configPath)
@@ -2878,6 +2885,11 @@ func (d *lxc) Start(stateful bool) error {
 
 		d.logger.Error("Failed starting instance", ctxMap)
 
+		// Point at the raw.lxc keys which may be causing the failure.
+		if len(d.rawLXCConflicts) > 0 {
+			err = fmt.Errorf("%w (%s)", err, d.rawLXCConflictsSummary())
+		}
+
 		// Return the actual error
 		op.Done(err)
 		return err
@@ -3675,6 +3687,15 @@ func (d *lxc) RenderWithUsage() (any, any, error) {
 	// It is important that the snapshot not be mounted here as mounting a snapshot can trigger a very
 	// expensive filesystem UUID regeneration, so we rely on the driver implementation to get the info
 	// we are requesting as cheaply as possible.
+	snapResp.Used = -1
+	snapResp.Unique = -1
+
+	snapUsage, err := pool.GetInstanceSnapshotUsage(d)
+	if err == nil {
+		snapResp.Used = snapUsage.Used
+		snapResp.Unique = snapUsage.Unique
+	}
+
 	volumeState, err := pool.GetInstanceUsage(d)
 	if err != nil {
 		return resp, etag, nil
@@ -3716,7 +3737,7 @@ func (d *lxc) Render() (any, any, error) {
 	}
 
 	// Prepare the response.
-	statusCode := d.statusCode()
+	statusCode := d.renderStatusCode(d.statusCode())
 	instState := api.Instance{
 		ExpandedConfig:  d.expandedConfig,
 		ExpandedDevices: d.expandedDevices.CloneNative(),
@@ -3845,7 +3866,7 @@ func (d *lxc) renderState(statusCode api.StatusCode, hostInterfaces []net.Interf
 
 // RenderState renders just the running state of the instance.
 func (d *lxc) RenderState(hostInterfaces []net.Interface) (*api.InstanceState, error) {
-	return d.renderState(d.statusCode(), hostInterfaces)
+	return d.renderState(d.renderStatusCode(d.statusCode()), hostInterfaces)
 }
 
 // snapshot creates a snapshot of the instance.
@@ -5306,7 +5327,7 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 }
 
 // Export backs up the instance.
-func (d *lxc) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties map[string]string, expiration time.Time, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error) {
+func (d *lxc) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties map[string]string, expiration time.Time, provenance *api.ImageProvenance, sbom string, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error) {
 	ctxMap := logger.Ctx{
 		"created":   d.creationDate,
 		"ephemeral": d.ephemeral,
@@ -5453,6 +5474,10 @@ func (d *lxc) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties ma
 		meta.ExpiryDate = expiration.UTC().Unix()
 	}
 
+	// The provenance of the image the instance was created from doesn't apply to the new image.
+	meta.Provenance = provenance
+	meta.SBOM = sbom
+
 	// Write the new metadata.yaml.
 	tempDir, err := os.MkdirTemp("", "incus_metadata_")
 	if err != nil {
@@ -5715,6 +5740,26 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 	indexHeaderVersion := localMigration.IndexHeaderVersion
 	offerHeader.IndexHeaderVersion = &indexHeaderVersion
 
+	// Offer to spread the storage transfer over additional connections.
+	if args.FilesystemStreams > 1 {
+		offerHeader.FilesystemStreams = proto.Uint32(uint32(args.FilesystemStreams))
+	}
+
+	// Offer to compress the storage transfer.
+	compression := d.state.GlobalConfig.MigrationCompression()
+	if compression != "" && compression != migration.CompressionNone {
+		offerHeader.Compression = &compression
+	}
+
+	// Offer to encrypt the data connections.
+	encryption, err := localMigration.OfferEncryption(offerHeader, d.expandedConfig["migration.encryption"])
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	localMigration.OfferVerify(offerHeader, args.Verify)
+
 	// Add CRIU and predump info to source header.
 	maxDumpIterations := 0
 	if args.Live {
@@ -5791,6 +5836,24 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 
 	d.logger.Debug("Got migration offer response from target")
 
+	encryption, err = localMigration.AcceptEncryption(encryption, respHeader, d.expandedConfig["migration.encryption"])
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	verify, err := localMigration.AcceptVerify(respHeader, args.Verify)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	if verify && args.Live {
+		err := errors.New("Migration verification isn't supported for live migrations")
+		op.Done(err)
+		return err
+	}
+
 	// Negotiated migration types.
 	migrationTypes, err := localMigration.MatchTypes(respHeader, migration.MigrationFSType_RSYNC, poolMigrationTypes)
 	if err != nil {
@@ -5799,6 +5862,8 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 		return err
 	}
 
+	localMigration.SetStatisticsTransferType(d.op, migrationTypes[0].FSType)
+
 	volSourceArgs := &localMigration.VolumeSourceArgs{
 		IndexHeaderVersion: respHeader.GetIndexHeaderVersion(), // Enable index header frame if supported.
 		Name:               d.Name(),
@@ -5806,11 +5871,37 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 		Snapshots:          offerHeader.SnapshotNames,
 		TrackProgress:      true,
 		Refresh:            respHeader.GetRefresh(),
+		RefreshBase:        respHeader.GetRefreshBase(),
 		AllowInconsistent:  args.AllowInconsistent,
 		VolumeOnly:         !args.Snapshots,
 		Info:               &localMigration.Info{Config: srcConfig},
 		ClusterMove:        clusterMove,
 		StorageMove:        storageMove,
+		Verify:             verify,
+	}
+
+	volSourceArgs.Streams, err = d.migrationStreams(args.MigrateArgs, respHeader, encryption)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	filesystemConn, err = localMigration.EncryptConn(encryption, filesystemConn, api.SecretNameFilesystem)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	stateConn, err = localMigration.EncryptConn(encryption, stateConn, api.SecretNameState)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	filesystemConn, err = localMigration.CompressConn(respHeader, filesystemConn)
+	if err != nil {
+		op.Done(err)
+		return err
 	}
 
 	// Only send the snapshots that the target requests when refreshing.
@@ -5989,7 +6080,9 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 				}
 
 				preDumpCounter := 0
+				preDumpIteration := int64(0)
 				preDumpDir := ""
+				var preDumpStarted time.Time
 
 				// Check if the other side knows about pre-dumping and the associated
 				// rsync protocol.
@@ -5998,6 +6091,7 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 					final := false
 					for !final {
 						preDumpCounter++
+						preDumpIteration++
 						if preDumpCounter < maxDumpIterations {
 							final = false
 						} else {
@@ -6013,6 +6107,8 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 							dumpDir:       dumpDir,
 							final:         final,
 							rsyncFeatures: rsyncFeatures,
+							iteration:     preDumpIteration,
+							previousStart: preDumpStarted,
 						}
 
 						final, err = d.migrateSendPreDumpLoop(&loopArgs)
@@ -6021,6 +6117,8 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 							return err
 						}
 
+						preDumpStarted = loopArgs.started
+
 						preDumpDir = fmt.Sprintf("%03d", preDumpCounter)
 						preDumpCounter++
 					}
@@ -6034,6 +6132,8 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 					return err
 				}
 
+				localMigration.SetProgressPhase(d.op, api.InstanceMigrationPhaseFinal)
+
 				go func() {
 					d.logger.Debug("Final CRIU dump started")
 					defer d.logger.Debug("Final CRIU dump stopped")
@@ -6086,7 +6186,7 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 			// parallel. In the future when we're using p.haul's protocol, it will make sense
 			// to do these in parallel.
 			ctName, _, _ := api.GetParentAndSnapshotName(d.Name())
-			err = rsync.Send(ctName, internalUtil.AddSlash(checkpointDir), stateConn, nil, rsyncFeatures, rsyncBwlimit, d.state.OS.ExecPath)
+			err = rsync.Send(ctName, internalUtil.AddSlash(checkpointDir), stateConn, nil, nil, rsyncFeatures, rsyncBwlimit, d.state.OS.ExecPath)
 			if err != nil {
 				return err
 			}
@@ -6097,6 +6197,7 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 		// Perform final sync if in multi sync mode.
 		if volSourceArgs.MultiSync {
 			d.logger.Debug("Starting final storage migration phase")
+			localMigration.SetProgressPhase(d.op, api.InstanceMigrationPhaseFinal)
 
 			// Indicate to the storage driver we are doing final sync and because of this don't send
 			// snapshots as they don't need to have a final sync as not being modified.
@@ -6151,6 +6252,9 @@ type preDumpLoopArgs struct {
 	dumpDir       string
 	final         bool
 	rsyncFeatures []string
+	iteration     int64
+	previousStart time.Time // When the previous pre-dump started (zero for the first one).
+	started       time.Time // Set to when this pre-dump started.
 }
 
 // migrateSendPreDumpLoop is the main logic behind the pre-copy migration.
@@ -6176,6 +6280,7 @@ func (d *lxc) migrateSendPreDumpLoop(args *preDumpLoopArgs) (bool, error) {
 		return false, errors.New("Instance is not container type")
 	}
 
+	args.started = time.Now()
 	err := d.migrate(&criuMigrationArgs)
 	if err != nil {
 		return final, fmt.Errorf("Failed sending instance: %w", err)
@@ -6183,7 +6288,7 @@ func (d *lxc) migrateSendPreDumpLoop(args *preDumpLoopArgs) (bool, error) {
 
 	// Send the pre-dump.
 	ctName, _, _ := api.GetParentAndSnapshotName(d.Name())
-	err = rsync.Send(ctName, internalUtil.AddSlash(args.checkpointDir), args.stateConn, nil, args.rsyncFeatures, args.bwlimit, d.state.OS.ExecPath)
+	err = rsync.Send(ctName, internalUtil.AddSlash(args.checkpointDir), args.stateConn, nil, nil, args.rsyncFeatures, args.bwlimit, d.state.OS.ExecPath)
 	if err != nil {
 		return final, err
 	}
@@ -6216,6 +6321,34 @@ func (d *lxc) migrateSendPreDumpLoop(args *preDumpLoopArgs) (bool, error) {
 
 	d.logger.Debug("CRIU pages", logger.Ctx{"pages": written, "skipped": skippedParent, "skippedPerc": percentageSkipped})
 
+	// The pages written by a pre-dump are the ones dirtied since the previous pre-dump started.
+	pageSize := int64(os.Getpagesize())
+	dirtyRate := int64(-1)
+	if !args.previousStart.IsZero() {
+		elapsed := args.started.Sub(args.previousStart).Seconds()
+		if elapsed > 0 {
+			dirtyRate = int64(float64(written) / elapsed)
+		}
+	}
+
+	// Publish the memory transfer progress.
+	localMigration.UpdateProgress(d.op, func(progress *api.InstanceMigrationProgress) {
+		transferred := int64(0)
+		if progress.Memory != nil {
+			transferred = progress.Memory.Transferred
+		}
+
+		progress.Phase = api.InstanceMigrationPhaseMemory
+		progress.Memory = &api.InstanceMigrationMemoryProgress{
+			Iteration:    args.iteration,
+			Transferred:  transferred + int64(written)*pageSize,
+			Total:        int64(totalPages) * pageSize,
+			PagesWritten: int64(written),
+			PagesSkipped: int64(skippedParent),
+			DirtyRate:    max(dirtyRate, 0),
+		}
+	})
+
 	// threshold is the percentage of memory pages that needs
 	// to be pre-copied for the pre-copy migration to stop.
 	var threshold int
@@ -6233,6 +6366,17 @@ func (d *lxc) migrateSendPreDumpLoop(args *preDumpLoopArgs) (bool, error) {
 		final = true
 	}
 
+	// Also stop once memory gets dirtied slowly enough for the final dump to be quick.
+	tmp = d.ExpandedConfig()["migration.incremental.memory.dirty_rate"]
+	if tmp != "" && dirtyRate >= 0 {
+		maxDirtyRate, err := units.ParseByteSizeString(tmp)
+		if err == nil && dirtyRate*pageSize <= maxDirtyRate {
+			d.logger.Debug("Memory dirty rate is below threshold", logger.Ctx{"dirtyRate": dirtyRate * pageSize, "threshold": maxDirtyRate})
+			d.logger.Debug("This was the last pre-dump; next dump is the final dump")
+			final = true
+		}
+	}
+
 	// If in pre-dump mode, the receiving side expects a message to know if this was the last pre-dump.
 	logger.Debug("Sending another CRIU pre-dump header")
 	sync := migration.MigrationSync{
@@ -6347,33 +6491,6 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 	// However, to determine the correct migration type Refresh needs to be set.
 	offerHeader.Refresh = &args.Refresh
 
-	clusterMove := args.ClusterMoveSourceName != ""
-	storageMove := args.StoragePool != ""
-
-	// Extract the source's migration type and then match it against our pool's supported types and features.
-	// If a match is found the combined features list will be sent back to requester.
-	contentType := storagePools.InstanceContentType(d)
-	respTypes, err := localMigration.MatchTypes(offerHeader, storagePools.FallbackMigrationType(contentType), pool.MigrationTypes(contentType, args.Refresh, args.Snapshots, clusterMove, storageMove))
-	if err != nil {
-		return err
-	}
-
-	// The migration header to be sent back to source with our target options.
-	// Convert response type to response header and copy snapshot info into it.
-	respHeader := localMigration.TypesToHeader(respTypes...)
-
-	// Respond with our maximum supported header version if the requested version is higher than ours.
-	// Otherwise just return the requested header version to the source.
-	indexHeaderVersion := min(offerHeader.GetIndexHeaderVersion(), localMigration.IndexHeaderVersion)
-
-	respHeader.IndexHeaderVersion = &indexHeaderVersion
-	respHeader.SnapshotNames = offerHeader.SnapshotNames
-	respHeader.Snapshots = offerHeader.Snapshots
-	respHeader.Refresh = &args.Refresh
-
-	// Add CRIU info to response.
-	respHeader.Criu = criuType
-
 	if args.Refresh {
 		// Get the remote snapshots on the source.
 		sourceSnapshots := offerHeader.GetSnapshots()
@@ -6404,6 +6521,10 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 		// Compare the two sets.
 		syncSourceSnapshotIndexes, deleteTargetSnapshotIndexes := storagePools.CompareSnapshots(sourceSnapshotComparable, targetSnapshotsComparable, args.RefreshExcludeOlder)
 
+		// Look for a snapshot from which the changes can be received as a diff.
+		// This is done before matching the migration types as some of them depend on it.
+		offerHeader.RefreshBase = proto.String(storagePools.RefreshBaseSnapshot(sourceSnapshotComparable, targetSnapshotsComparable, syncSourceSnapshotIndexes))
+
 		// Delete the extra local snapshots first.
 		for _, deleteTargetSnapshotIndex := range deleteTargetSnapshotIndexes {
 			err := targetSnapshots[deleteTargetSnapshotIndex].Delete(true)
@@ -6420,12 +6541,55 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 			syncSnapshots = append(syncSnapshots, sourceSnapshots[syncSourceSnapshotIndex])
 		}
 
-		respHeader.Snapshots = syncSnapshots
-		respHeader.SnapshotNames = syncSnapshotNames
 		offerHeader.Snapshots = syncSnapshots
 		offerHeader.SnapshotNames = syncSnapshotNames
 	}
 
+	clusterMove := args.ClusterMoveSourceName != ""
+	storageMove := args.StoragePool != ""
+
+	// Extract the source's migration type and then match it against our pool's supported types and features.
+	// If a match is found the combined features list will be sent back to requester.
+	contentType := storagePools.InstanceContentType(d)
+	respTypes, err := localMigration.MatchTypes(offerHeader, storagePools.FallbackMigrationType(contentType), pool.MigrationTypes(contentType, args.Refresh, args.Snapshots, clusterMove, storageMove))
+	if err != nil {
+		return err
+	}
+
+	// The migration header to be sent back to source with our target options.
+	// Convert response type to response header and copy snapshot info into it.
+	respHeader := localMigration.TypesToHeader(respTypes...)
+
+	// Respond with our maximum supported header version if the requested version is higher than ours.
+	// Otherwise just return the requested header version to the source.
+	indexHeaderVersion := min(offerHeader.GetIndexHeaderVersion(), localMigration.IndexHeaderVersion)
+
+	respHeader.IndexHeaderVersion = &indexHeaderVersion
+	localMigration.NegotiateFilesystemStreams(offerHeader, respHeader, args.FilesystemStreams)
+	localMigration.NegotiateCompression(offerHeader, respHeader, d.state.GlobalConfig.MigrationCompression())
+
+	encryption, err := localMigration.NegotiateEncryption(offerHeader, respHeader, d.expandedConfig["migration.encryption"])
+	if err != nil {
+		return err
+	}
+
+	verify, err := localMigration.NegotiateVerify(offerHeader, respHeader, args.Verify)
+	if err != nil {
+		return err
+	}
+
+	if verify && args.Live {
+		return errors.New("Migration verification isn't supported for live migrations")
+	}
+
+	respHeader.SnapshotNames = offerHeader.SnapshotNames
+	respHeader.Snapshots = offerHeader.Snapshots
+	respHeader.Refresh = &args.Refresh
+	respHeader.RefreshBase = offerHeader.RefreshBase
+
+	// Add CRIU info to response.
+	respHeader.Criu = criuType
+
 	if offerHeader.GetPredump() {
 		// If the other side wants pre-dump and if this side supports it, let's use it.
 		respHeader.Predump = proto.Bool(true)
@@ -6446,6 +6610,27 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 
 	d.logger.Debug("Sent migration response to source")
 
+	// Establish the additional filesystem connections if agreed on.
+	streams, err := d.migrationStreams(args.MigrateArgs, respHeader, encryption)
+	if err != nil {
+		return err
+	}
+
+	filesystemConn, err = localMigration.EncryptConn(encryption, filesystemConn, api.SecretNameFilesystem)
+	if err != nil {
+		return err
+	}
+
+	stateConn, err = localMigration.EncryptConn(encryption, stateConn, api.SecretNameState)
+	if err != nil {
+		return err
+	}
+
+	filesystemConn, err = localMigration.CompressConn(respHeader, filesystemConn)
+	if err != nil {
+		return err
+	}
+
 	srcIdmap := &idmap.Set{}
 	for _, idmapSet := range offerHeader.Idmap {
 		e := idmap.Entry{
@@ -6569,12 +6754,16 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 			Name:                  d.Name(),
 			MigrationType:         respTypes[0],
 			Refresh:               args.Refresh,                // Indicate to receiver volume should exist.
+			RefreshBase:           respHeader.GetRefreshBase(), // Snapshot on top of which changes are received.
 			TrackProgress:         true,                        // Use a progress tracker on receiver to get in-cluster progress information.
 			Live:                  sendFinalFsDelta,            // Indicates we will get a final rootfs sync.
 			VolumeSize:            offerHeader.GetVolumeSize(), // Block size setting override.
 			VolumeOnly:            !args.Snapshots,
 			ClusterMoveSourceName: args.ClusterMoveSourceName,
 			StoragePool:           args.StoragePool,
+			Streams:               streams,
+			Verify:                verify,
+			KeepPartial:           args.KeepPartial,
 		}
 
 		// At this point we have already figured out the parent container's root
@@ -6622,7 +6811,12 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 						return fmt.Errorf("Failed creating instance snapshot record %q: %w", snapArgs.Name, err)
 					}
 
-					reverter.Add(cleanup)
+					// Snapshots that were received are kept when resuming is possible.
+					// The others are removed by the caller.
+					if !args.KeepPartial {
+						reverter.Add(cleanup)
+					}
+
 					defer snapInstOp.Done(err)
 				}
 			}
@@ -6636,8 +6830,8 @@ func (d *lxc) MigrateReceive(args instance.MigrateReceiveArgs) error {
 		isRemoteClusterMove := clusterMove && pool.Driver().Info().Remote
 
 		// Only delete all instance volumes on error if the pool volume creation has succeeded to
-		// avoid deleting an existing conflicting volume.
-		if !volTargetArgs.Refresh && !isRemoteClusterMove {
+		// avoid deleting an existing conflicting volume, and keep them if the migration can be resumed.
+		if !volTargetArgs.Refresh && !isRemoteClusterMove && !args.KeepPartial {
 			reverter.Add(func() {
 				snapshots, _ := d.Snapshots()
 				snapshotCount := len(snapshots)
@@ -7714,6 +7908,73 @@ func (d *lxc) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, st
 	return instCmd, nil
 }
 
+// ExecNetns executes a command on the host, joined only to the network namespace of the container.
+// The command keeps the host filesystem, PID namespace and credentials, so the user and group of the
+// request are ignored.
+func (d *lxc) ExecNetns(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
+	if len(req.Command) == 0 {
+		return nil, errors.New("No command provided")
+	}
+
+	pid := d.InitPID()
+	if pid <= 0 {
+		return nil, errors.New("Container isn't running")
+	}
+
+	netns, err := os.Open(fmt.Sprintf("/proc/%d/ns/net", pid))
+	if err != nil {
+		return nil, fmt.Errorf("Failed opening the network namespace of the container: %w", err)
+	}
+
+	defer func() { _ = netns.Close() }()
+
+	cmd := exec.Command(req.Command[0], req.Command[1:]...)
+	cmd.Dir = req.Cwd
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: req.Interactive}
+
+	for k, v := range req.Environment {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	// Start the command from a dedicated thread which joins the network namespace of the container.
+	// The thread is never unlocked so that it gets terminated rather than reused once done.
+	chErr := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+
+		err := unix.Setns(int(netns.Fd()), unix.CLONE_NEWNET)
+		if err != nil {
+			chErr <- fmt.Errorf("Failed joining the network namespace of the container: %w", err)
+			return
+		}
+
+		chErr <- cmd.Start()
+	}()
+
+	err = <-chErr
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, ErrExecCommandNotFound
+		}
+
+		return nil, err
+	}
+
+	d.logger.Debug("Started command in the network namespace", logger.Ctx{"pid": cmd.Process.Pid, "command": req.Command})
+
+	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceExec.Event(d, logger.Ctx{"command": req.Command, "netns": true}))
+
+	instCmd := &lxcCmd{
+		cmd:              cmd,
+		attachedChildPid: cmd.Process.Pid,
+	}
+
+	return instCmd, nil
+}
+
 func (d *lxc) cpuStateUsage(cg *cgroup.CGroup) (int64, bool) {
 	if !d.state.OS.CGInfo.Supports(cgroup.CPUAcct, cg) {
 		return -1, false
@@ -8609,7 +8870,7 @@ func (d *lxc) LockExclusive() (*operationlock.InstanceOperation, error) {
 func (d *lxc) InitPID() int {
 	// Load the go-lxc struct
 	cc, err := d.initLXC(false)
-	if err != nil {
+	if err != nil || cc == nil {
 		return -1
 	}
 
@@ -8624,6 +8885,11 @@ func (d *lxc) InitPidFd() (*os.File, error) {
 		return nil, err
 	}
 
+	// Snapshots never have a running init process.
+	if cc == nil {
+		return nil, errors.New("Instance is a snapshot")
+	}
+
 	return cc.InitPidFd()
 }
 
@@ -9146,9 +9412,16 @@ func (d *lxc) loadRawLXCConfig(cc *liblxc.Container) error {
 		return err
 	}
 
+	// Record the generated keys which get overridden before loading the config.
+	d.rawLXCConflicts = d.findRawLXCConflicts(cc, lxcConfig)
+
 	// Load the config.
 	err = cc.LoadConfigFile(f.Name())
 	if err != nil {
+		if len(d.rawLXCConflicts) > 0 {
+			return fmt.Errorf("Failed to load config file %q (%s): %w", f.Name(), d.rawLXCConflictsSummary(), err)
+		}
+
 		return fmt.Errorf("Failed to load config file %q: %w", f.Name(), err)
 	}
 
@@ -9157,6 +9430,126 @@ func (d *lxc) loadRawLXCConfig(cc *liblxc.Container) error {
 	return nil
 }
 
+// lxcRawConfigListPrefixes are the liblxc keys which hold a list of values.
+// Setting them in raw.lxc adds to the values generated by Incus rather than replacing them.
+var lxcRawConfigListPrefixes = []string{
+	"lxc.apparmor.raw",
+	"lxc.cap.",
+	"lxc.cgroup.",
+	"lxc.cgroup2.",
+	"lxc.environment",
+	"lxc.group",
+	"lxc.hook.",
+	"lxc.idmap",
+	"lxc.include",
+	"lxc.mount.entry",
+	"lxc.net.",
+	"lxc.seccomp.notify.",
+	"lxc.sysctl.",
+}
+
+// findRawLXCConflicts returns the keys of the raw.lxc config which replace a different value already set on the
+// liblxc container. It must be called before the raw.lxc config is loaded.
+func (d *lxc) findRawLXCConflicts(cc *liblxc.Container, lxcConfig string) []api.InstanceDebugLXCConfigConflict {
+	keys := []string{}
+	rawValues := map[string]string{}
+	for _, line := range strings.Split(lxcConfig, "\n") {
+		key, value, err := instance.ParseRawLXC(line)
+		if err != nil || key == "" {
+			continue
+		}
+
+		isList := slices.ContainsFunc(lxcRawConfigListPrefixes, func(prefix string) bool {
+			return strings.HasPrefix(key, prefix)
+		})
+
+		if isList {
+			continue
+		}
+
+		_, found := rawValues[key]
+		if !found {
+			keys = append(keys, key)
+		}
+
+		// When a key is repeated, the last value is used.
+		rawValues[key] = value
+	}
+
+	conflicts := []api.InstanceDebugLXCConfigConflict{}
+	for _, key := range keys {
+		value := strings.Join(cc.ConfigItem(key), "\n")
+		if value == "" || value == rawValues[key] {
+			continue
+		}
+
+		conflicts = append(conflicts, api.InstanceDebugLXCConfigConflict{Key: key, Value: value, RawValue: rawValues[key]})
+	}
+
+	return conflicts
+}
+
+// rawLXCConflictsSummary returns a description of the generated keys overridden by raw.lxc.
+func (d *lxc) rawLXCConflictsSummary() string {
+	keys := make([]string, 0, len(d.rawLXCConflicts))
+	for _, conflict := range d.rawLXCConflicts {
+		keys = append(keys, fmt.Sprintf("%s=%q replaced by %q", conflict.Key, conflict.Value, conflict.RawValue))
+	}
+
+	return "raw.lxc overrides configuration generated by Incus: " + strings.Join(keys, ", ")
+}
+
+// DebugLXCConfig returns the final liblxc configuration of the container along with the generated keys overridden
+// by raw.lxc.
+func (d *lxc) DebugLXCConfig() (*api.InstanceDebugLXCConfig, error) {
+	// Release liblxc container once done.
+	defer func() {
+		d.release()
+	}()
+
+	cc, err := d.initLXC(true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.loadRawLXCConfig(cc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := api.InstanceDebugLXCConfig{Conflicts: d.rawLXCConflicts}
+	if result.Conflicts == nil {
+		result.Conflicts = []api.InstanceDebugLXCConfigConflict{}
+	}
+
+	// A running container uses the config generated on start, which also includes its devices.
+	configPath := filepath.Join(d.RunPath(), "lxc.conf")
+	if !d.IsRunning() || !util.PathExists(configPath) {
+		f, err := os.CreateTemp("", "incus_config_")
+		if err != nil {
+			return nil, err
+		}
+
+		configPath = f.Name()
+		_ = f.Close()
+		defer func() { _ = os.Remove(configPath) }()
+
+		err = cc.SaveConfigFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Config = string(content)
+
+	return &result, nil
+}
+
 // forfileRunningLockName returns the forkfile-running_ID lock name.
 func (d *common) forkfileRunningLockName() string {
 	return fmt.Sprintf("forkfile-running_%d", d.id)
@@ -27,6 +27,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
@@ -48,6 +49,7 @@ import (
 	"github.com/lxc/incus/v6/internal/linux"
 	"github.com/lxc/incus/v6/internal/migration"
 	"github.com/lxc/incus/v6/internal/ports"
+	"github.com/lxc/incus/v6/internal/rsync"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
 	"github.com/lxc/incus/v6/internal/server/cgroup"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -115,6 +117,9 @@ const qemuBlockDevIDPrefix = "incus_"
 // qemuMigrationNBDExportName is the name of the disk device export by the migration NBD server.
 const qemuMigrationNBDExportName = "incus_root"
 
+// qemuMigrationSnapshotFile is the name of the file in the config volume holding the writes of a warm migration.
+const qemuMigrationSnapshotFile = "migration_snapshot.qcow2"
+
 // qemuSparseUSBPorts is the amount of sparse USB ports for VMs.
 // 4 are reserved, and the other 4 can be used for any USB device.
 const qemuSparseUSBPorts = 8
@@ -346,10 +351,13 @@ type qemu struct {
 
 	// Stateful migration streams.
 	migrationReceiveStateful map[string]io.ReadWriteCloser
+	migrationReceivePostcopy bool
 
-	// Keep a reference to the console socket when switching backends, so we can properly cleanup when switching back to a ring buffer.
-	consoleSocket     *net.UnixListener
-	consoleSocketFile *os.File
+	// Keep a reference to the console sockets when switching backends, so we can properly cleanup when switching back to a ring buffer.
+	// They are keyed on the serial console name, the main console being unnamed.
+	consoleSockets     map[string]*net.UnixListener
+	consoleSocketFiles map[string]*os.File
+	consoleSocketsMu   sync.Mutex
 
 	// Keep a record of QEMU configuration.
 	cmdArgs []string
@@ -673,10 +681,21 @@ func (d *qemu) onStop(target string) error {
 	}
 
 	// Record power state.
-	err = d.VolatileSet(map[string]string{
+	volatileChanges := map[string]string{
 		"volatile.last_state.power": instance.PowerStateStopped,
 		"volatile.last_state.ready": "false",
-	})
+	}
+
+	// Ejected media is only kept out across guest reboots.
+	if target != "reboot" {
+		for key := range d.localConfig {
+			if strings.HasPrefix(key, "volatile.") && strings.HasSuffix(key, ".last_state.media_ejected") {
+				volatileChanges[key] = ""
+			}
+		}
+	}
+
+	err = d.VolatileSet(volatileChanges)
 	if err != nil {
 		// Don't return an error here as we still want to cleanup the instance even if DB not available.
 		d.logger.Error("Failed recording last power state", logger.Ctx{"err": err})
@@ -769,6 +788,8 @@ func (d *qemu) Shutdown(timeout time.Duration) error {
 		return err
 	}
 
+	d.saveSerialConsoleLogs()
+
 	// Setup a new operation.
 	// Allow inheriting of ongoing restart operation (we are called from restartCommon).
 	// Allow reuse when creating a new stop operation. This allows the Stop() function to inherit operation.
@@ -956,20 +977,55 @@ func (d *qemu) restoreState(monitor *qmp.Monitor) error {
 		}
 
 		// Receive checkpoint from QEMU process on source.
-		d.logger.Debug("Stateful migration checkpoint receive starting")
-		pipeRead, pipeWrite, err := os.Pipe()
-		if err != nil {
-			return err
-		}
+		d.logger.Debug("Stateful migration checkpoint receive starting", logger.Ctx{"postcopy": d.migrationReceivePostcopy})
 
-		go func() {
-			_, _ = io.Copy(pipeWrite, stateConn)
+		var stateFile *os.File
+		if d.migrationReceivePostcopy {
+			// Post-copy requires a return path so the target can request missing pages from the source.
+			err := monitor.MigrateSetCapabilities(map[string]bool{"postcopy-ram": true})
+			if err != nil {
+				return fmt.Errorf("Failed setting migration capabilities: %w", err)
+			}
 
-			_ = pipeRead.Close()
-			_ = pipeWrite.Close()
-		}()
+			localSocket, remoteSocket, err := qemuMigrationSocketPair()
+			if err != nil {
+				return err
+			}
 
-		err = d.restoreStateHandle(context.Background(), monitor, pipeRead)
+			defer func() { _ = remoteSocket.Close() }()
+
+			go func() {
+				go func() { _, _ = io.Copy(stateConn, localSocket) }()
+
+				_, _ = io.Copy(localSocket, stateConn)
+				_ = localSocket.Close()
+			}()
+
+			// Resume the guest as soon as the migration switches to post-copy rather than when
+			// the whole memory has been received.
+			err = monitor.Start()
+			if err != nil {
+				return fmt.Errorf("Failed enabling guest autostart for post-copy migration: %w", err)
+			}
+
+			stateFile = remoteSocket
+		} else {
+			pipeRead, pipeWrite, err := os.Pipe()
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				_, _ = io.Copy(pipeWrite, stateConn)
+
+				_ = pipeRead.Close()
+				_ = pipeWrite.Close()
+			}()
+
+			stateFile = pipeRead
+		}
+
+		err := d.restoreStateHandle(context.Background(), monitor, stateFile)
 		if err != nil {
 			return fmt.Errorf("Failed restoring checkpoint from source: %w", err)
 		}
@@ -1037,6 +1093,17 @@ func (d *qemu) saveStateHandle(monitor *qmp.Monitor, f *os.File) error {
 	return nil
 }
 
+// qemuMigrationSocketPair returns a connected pair of unix sockets suitable for a bidirectional migration
+// stream. The first socket is used locally to proxy the stream, the second is to be handed over to QEMU.
+func qemuMigrationSocketPair() (*os.File, *os.File, error) {
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed creating migration socket pair: %w", err)
+	}
+
+	return os.NewFile(uintptr(fds[0]), "migration-local"), os.NewFile(uintptr(fds[1]), "migration-qemu"), nil
+}
+
 // saveState dumps the current VM state to the state file.
 // Once dumped, the VM is in a paused state and it's up to the caller to resume or kill it.
 func (d *qemu) saveState(monitor *qmp.Monitor) error {
@@ -1281,7 +1348,12 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 	defer reverter.Fail()
 
 	// Rotate the log files.
-	for _, logfile := range []string{d.LogFilePath(), d.ConsoleBufferLogPath(), d.QMPLogFilePath()} {
+	logFiles := []string{d.LogFilePath(), d.ConsoleBufferLogPath(), d.QMPLogFilePath()}
+	for _, name := range d.serialConsoleNames() {
+		logFiles = append(logFiles, d.consoleLogPath(name))
+	}
+
+	for _, logfile := range logFiles {
 		if util.PathExists(logfile) {
 			_ = os.Remove(logfile + ".old")
 			err := os.Rename(logfile, logfile+".old")
@@ -1306,6 +1378,10 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		_ = os.Remove(socketPath)
 	}
 
+	for _, name := range d.serialConsoleNames() {
+		_ = os.Remove(d.consoleSocketPath(name))
+	}
+
 	// Mount the instance's config volume.
 	mountInfo, err := d.mount()
 	if err != nil {
@@ -1697,8 +1773,18 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 	}
 
 	// Set RTC to localtime on Windows.
+	rtcOpts := []string{}
 	if d.isWindows() {
-		qemuArgs = append(qemuArgs, "-rtc", "base=localtime")
+		rtcOpts = append(rtcOpts, "base=localtime")
+	}
+
+	// Have the emulated RTC catch up on the ticks missed by the guest.
+	if d.expandedConfig["agent.clock_sync"] == "slew" && d.architecture == osarch.ARCH_64BIT_INTEL_X86 {
+		rtcOpts = append(rtcOpts, "driftfix=slew")
+	}
+
+	if len(rtcOpts) > 0 {
+		qemuArgs = append(qemuArgs, "-rtc", strings.Join(rtcOpts, ","))
 	}
 
 	// SMBIOS only on x86_64 and aarch64.
@@ -2088,6 +2174,11 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStarted.Event(d, nil))
 	}
 
+	// Correct the guest clock after restoring a saved or migrated state.
+	if stateful {
+		go d.syncGuestClock()
+	}
+
 	// The VM started cleanly so now enable the unexpected disconnection event to ensure the onStop hook is
 	// run if QMP unexpectedly disconnects.
 	monitor.SetOnDisconnectEvent(true)
@@ -2236,6 +2327,78 @@ func (d *qemu) advertiseVsockAddress() error {
 	return nil
 }
 
+// syncGuestClock has the agent correct the guest clock as configured in agent.clock_sync.
+// It's meant to run in the background after the guest was paused or had its state restored, as its
+// clock then lags behind.
+func (d *qemu) syncGuestClock() {
+	mode := d.expandedConfig["agent.clock_sync"]
+	if mode == "" || mode == "none" {
+		return
+	}
+
+	// The agent may take a moment to be reachable again once the guest resumed.
+	var err error
+	for range 30 {
+		err = d.setGuestClock(mode)
+		if err == nil || api.StatusErrorCheck(err, http.StatusNotFound) {
+			break
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	if err != nil {
+		d.logger.Warn("Failed synchronizing the guest clock", logger.Ctx{"err": err})
+	}
+}
+
+// setGuestClock sends the host time to the agent.
+func (d *qemu) setGuestClock(mode string) error {
+	client, err := d.getAgentClient()
+	if err != nil {
+		return fmt.Errorf("Failed getting agent client handle: %w", err)
+	}
+
+	agentArgs := &incus.ConnectionArgs{SkipGetServer: true}
+	agent, err := incus.ConnectIncusHTTP(agentArgs, client)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to the agent: %w", err)
+	}
+
+	defer agent.Disconnect()
+
+	_, _, err = agent.RawQuery("PUT", "/1.0/clock", agentAPI.ClockPut{Time: time.Now().UTC(), Mode: mode}, "")
+	if err != nil {
+		return fmt.Errorf("Failed sending the host time to the agent: %w", err)
+	}
+
+	return nil
+}
+
+// growGuestDisk has the agent grow the last partition and the filesystem of a disk after it was resized.
+// It's meant to run in the background as the guest may take a moment to pick up the new size.
+func (d *qemu) growGuestDisk(serial string) {
+	client, err := d.getAgentClient()
+	if err != nil {
+		d.logger.Warn("Failed getting agent client handle", logger.Ctx{"err": err})
+		return
+	}
+
+	agentArgs := &incus.ConnectionArgs{SkipGetServer: true}
+	agent, err := incus.ConnectIncusHTTP(agentArgs, client)
+	if err != nil {
+		d.logger.Warn("Failed connecting to the agent", logger.Ctx{"err": err})
+		return
+	}
+
+	defer agent.Disconnect()
+
+	_, _, err = agent.RawQuery("POST", "/1.0/disks/grow", agentAPI.DiskGrowPost{Serial: serial}, "")
+	if err != nil {
+		d.logger.Warn("Failed growing the guest disk", logger.Ctx{"serial": serial, "err": err})
+	}
+}
+
 // AgentCertificate returns the server certificate of the agent.
 func (d *qemu) AgentCertificate() *x509.Certificate {
 	agentCert := filepath.Join(d.Path(), "config", "agent.crt")
@@ -3423,7 +3586,10 @@ func (d *qemu) deviceBootPriorities(base int) (map[string]int, error) {
 
 	for _, dev := range d.expandedDevices.Sorted() {
 		if dev.Config["type"] != "disk" && dev.Config["type"] != "nic" {
-			continue
+			// PCI devices are only bootable when explicitly requested.
+			if dev.Config["type"] != "pci" || dev.Config["boot.priority"] == "" {
+				continue
+			}
 		}
 
 		bootPrio := uint32(0) // Default to lowest priority.
@@ -3641,6 +3807,21 @@ func (d *qemu) generateQemuConfig(machineDefinition string, cpuInfo *cpuTopology
 
 	conf = append(conf, qemuSerial(&serialOpts)...)
 
+	// Additional serial consoles.
+	serialNames := d.serialConsoleNames()
+	isaSerial := d.architecture == osarch.ARCH_64BIT_INTEL_X86
+	if isaSerial && len(serialNames) > 3 {
+		return nil, errors.New("At most three additional serial consoles are supported on x86_64")
+	}
+
+	for i, name := range serialNames {
+		if slices.Contains(serialNames[:i], name) {
+			return nil, fmt.Errorf("Duplicate serial console name %q", name)
+		}
+
+		conf = append(conf, qemuSerialConsole(&qemuSerialConsoleOpts{name: name, index: i + 1, isa: isaSerial})...)
+	}
+
 	// s390x doesn't really have USB.
 	if d.architecture != osarch.ARCH_64BIT_S390_BIG_ENDIAN {
 		devBus, devAddr, multi = bus.allocate(busFunctionGroupGeneric)
@@ -3859,7 +4040,15 @@ func (d *qemu) generateQemuConfig(machineDefinition string, cpuInfo *cpuTopology
 
 		// Add PCI device.
 		if len(runConf.PCIDevice) > 0 {
-			err = d.addPCIDevConfig(&conf, bus, runConf.PCIDevice)
+			err = d.addPCIDevConfig(&conf, bus, bootIndexes, runConf.PCIDevice)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Add shared memory device.
+		if len(runConf.ShmemDevice) > 0 {
+			err = d.addShmemDevConfig(&conf, bus, runConf.ShmemDevice)
 			if err != nil {
 				return nil, err
 			}
@@ -4340,6 +4529,22 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 		break
 	}
 
+	// Check if the user has set the identity of the disk.
+	serial := ""
+	wwn := ""
+	for _, opt := range driveConf.Opts {
+		value, ok := strings.CutPrefix(opt, "serial=")
+		if ok {
+			serial = value
+			continue
+		}
+
+		value, ok = strings.CutPrefix(opt, "wwn=")
+		if ok {
+			wwn = value
+		}
+	}
+
 	// QMP uses two separate values for the cache.
 	directCache := true   // Bypass host cache, use O_DIRECT semantics by default.
 	noFlushCache := false // Don't ignore any flush requests for the device.
@@ -4446,6 +4651,22 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 	qemuDev["id"] = fmt.Sprintf("%s%s", qemuDeviceIDPrefix, escapedDeviceName)
 	qemuDev["drive"] = blockDev["node-name"].(string)
 	qemuDev["serial"] = fmt.Sprintf("%s%s", qemuBlockDevIDPrefix, escapedDeviceName)
+	if serial != "" {
+		qemuDev["serial"] = serial
+	}
+
+	if wwn != "" {
+		if bus != "virtio-scsi" {
+			return nil, fmt.Errorf("Disk WWN isn't supported on the %q bus", bus)
+		}
+
+		wwnValue, err := device.DiskParseWWN(wwn)
+		if err != nil {
+			return nil, err
+		}
+
+		qemuDev["wwn"] = wwnValue
+	}
 
 	if bus == "virtio-scsi" {
 		qemuDev["device_id"] = d.blockNodeName(escapedDeviceName)
@@ -4538,6 +4759,14 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 			}
 		}
 
+		// Keep the media ejected if it was ejected before a guest reboot.
+		if media == "cdrom" && util.IsTrue(d.localConfig[fmt.Sprintf("volatile.%s.last_state.media_ejected", driveConf.DevName)]) {
+			err = m.Eject(qemuDev["id"].(string))
+			if err != nil {
+				return fmt.Errorf("Failed ejecting media of disk device %q: %w", driveConf.DevName, err)
+			}
+		}
+
 		reverter.Success()
 		return nil
 	}
@@ -4885,7 +5114,7 @@ func (d *qemu) writeNICDevConfig(mtuStr string, devName string, nicName string,
 }
 
 // addPCIDevConfig adds the qemu config required for adding a raw PCI device.
-func (d *qemu) addPCIDevConfig(conf *[]cfg.Section, bus *qemuBus, pciConfig []deviceConfig.RunConfigItem) error {
+func (d *qemu) addPCIDevConfig(conf *[]cfg.Section, bus *qemuBus, bootIndexes map[string]int, pciConfig []deviceConfig.RunConfigItem) error {
 	var devName, pciSlotName string
 	for _, pciItem := range pciConfig {
 		if pciItem.Key == "devName" {
@@ -4906,11 +5135,52 @@ func (d *qemu) addPCIDevConfig(conf *[]cfg.Section, bus *qemuBus, pciConfig []de
 		devName:     devName,
 		pciSlotName: pciSlotName,
 	}
+
+	bootIndex, found := bootIndexes[devName]
+	if found {
+		pciPhysicalOpts.bootIndex = strconv.Itoa(bootIndex)
+	}
+
 	*conf = append(*conf, qemuPCIPhysical(&pciPhysicalOpts)...)
 
 	return nil
 }
 
+// addShmemDevConfig adds the qemu config required for adding a shared memory device.
+func (d *qemu) addShmemDevConfig(conf *[]cfg.Section, bus *qemuBus, shmemConfig []deviceConfig.RunConfigItem) error {
+	var devName, path, size string
+	for _, shmemItem := range shmemConfig {
+		if shmemItem.Key == "devName" {
+			devName = shmemItem.Value
+		} else if shmemItem.Key == "path" {
+			path = shmemItem.Value
+		} else if shmemItem.Key == "size" {
+			size = shmemItem.Value
+		}
+	}
+
+	if bus.name == "ccw" {
+		return errors.New("Shared memory devices aren't supported on this architecture")
+	}
+
+	devBus, devAddr, multi := bus.allocate(busFunctionGroupNone)
+	shmemOpts := qemuShmemOpts{
+		dev: qemuDevOpts{
+			busName:       bus.name,
+			devBus:        devBus,
+			devAddr:       devAddr,
+			multifunction: multi,
+		},
+		devName: devName,
+		path:    path,
+		size:    size,
+	}
+
+	*conf = append(*conf, qemuShmem(&shmemOpts)...)
+
+	return nil
+}
+
 // addGPUDevConfig adds the qemu config required for adding a GPU device.
 func (d *qemu) addGPUDevConfig(conf *[]cfg.Section, bus *qemuBus, gpuConfig []deviceConfig.RunConfigItem) error {
 	var devName, pciSlotName, vgpu string
@@ -5174,6 +5444,7 @@ func (d *qemu) Stop(stateful bool) error {
 
 	// Attempt to save the console log from ring buffer before the instance is stopped. Must be run prior to creating the operation lock.
 	_, _ = d.ConsoleLog()
+	d.saveSerialConsoleLogs()
 
 	// Setup a new operation.
 	// Allow inheriting of ongoing restart or restore operation (we are called from restartCommon and Restore).
@@ -5310,6 +5581,8 @@ func (d *qemu) Unfreeze() error {
 		return err
 	}
 
+	go d.syncGuestClock()
+
 	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceResumed.Event(d, nil))
 	return nil
 }
@@ -5373,6 +5646,8 @@ func (d *qemu) snapshot(name string, expiry time.Time, stateful bool) error {
 		if err != nil {
 			return err
 		}
+
+		go d.syncGuestClock()
 	}
 
 	return nil
@@ -6626,7 +6901,7 @@ func (d *qemu) delete(force bool) error {
 }
 
 // Export publishes the instance.
-func (d *qemu) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties map[string]string, expiration time.Time, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error) {
+func (d *qemu) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties map[string]string, expiration time.Time, provenance *api.ImageProvenance, sbom string, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error) {
 	ctxMap := logger.Ctx{
 		"created":   d.creationDate,
 		"ephemeral": d.ephemeral,
@@ -6728,6 +7003,10 @@ func (d *qemu) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties m
 		meta.ExpiryDate = expiration.UTC().Unix()
 	}
 
+	// The provenance of the image the instance was created from doesn't apply to the new image.
+	meta.Provenance = provenance
+	meta.SBOM = sbom
+
 	// Write the new metadata.yaml.
 	tempDir, err := os.MkdirTemp("", "incus_metadata_")
 	if err != nil {
@@ -6919,6 +7198,26 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 	indexHeaderVersion := localMigration.IndexHeaderVersion
 	offerHeader.IndexHeaderVersion = &indexHeaderVersion
 
+	// Offer to spread the storage transfer over additional connections (not used for live migration).
+	if args.FilesystemStreams > 1 && !args.Live {
+		offerHeader.FilesystemStreams = proto.Uint32(uint32(args.FilesystemStreams))
+	}
+
+	// Offer to compress the storage transfer.
+	compression := d.state.GlobalConfig.MigrationCompression()
+	if compression != "" && compression != migration.CompressionNone {
+		offerHeader.Compression = &compression
+	}
+
+	// Offer to encrypt the data connections.
+	encryption, err := localMigration.OfferEncryption(offerHeader, d.expandedConfig["migration.encryption"])
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	localMigration.OfferVerify(offerHeader, args.Verify)
+
 	// For VMs, send block device size hint in offer header so that target can create the volume the same size.
 	blockSize, err := storagePools.InstanceDiskBlockSize(pool, d, d.op)
 	if err != nil {
@@ -6964,8 +7263,18 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 	// If the request is for live migration, then offer that live QEMU to QEMU state transfer can proceed.
 	// Otherwise we'll fallback to doing stateful stop, migrate, and then stateful start, which will still
 	// fulfil the "live" part of the request, albeit with longer pause of the instance during the process.
+	// When warm migration is enabled and the root disk needs to be transferred, offer it instead.
 	if args.Live {
-		offerHeader.Criu = migration.CRIUType_VM_QEMU.Enum()
+		if util.IsTrue(d.expandedConfig["migration.warm"]) && (!remoteClusterMove || storageMove) {
+			offerHeader.Warm = proto.Bool(true)
+		} else {
+			offerHeader.Criu = migration.CRIUType_VM_QEMU.Enum()
+
+			// Offer post-copy memory transfer if enabled on the instance.
+			if util.IsTrue(d.expandedConfig["migration.postcopy"]) {
+				offerHeader.Postcopy = proto.Bool(true)
+			}
+		}
 	}
 
 	// Send offer to target.
@@ -6989,6 +7298,24 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 
 	d.logger.Debug("Got migration offer response from target")
 
+	encryption, err = localMigration.AcceptEncryption(encryption, respHeader, d.expandedConfig["migration.encryption"])
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	verify, err := localMigration.AcceptVerify(respHeader, args.Verify)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	if verify && args.Live {
+		err := errors.New("Migration verification isn't supported for live migrations")
+		op.Done(err)
+		return err
+	}
+
 	// Negotiated migration types.
 	migrationTypes, err := localMigration.MatchTypes(respHeader, migration.MigrationFSType_RSYNC, poolMigrationTypes)
 	if err != nil {
@@ -6997,6 +7324,8 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 		return err
 	}
 
+	localMigration.SetStatisticsTransferType(d.op, migrationTypes[0].FSType)
+
 	volSourceArgs := &localMigration.VolumeSourceArgs{
 		IndexHeaderVersion: respHeader.GetIndexHeaderVersion(), // Enable index header frame if supported.
 		Name:               d.Name(),
@@ -7004,11 +7333,31 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 		Snapshots:          offerHeader.SnapshotNames,
 		TrackProgress:      true,
 		Refresh:            respHeader.GetRefresh(),
+		RefreshBase:        respHeader.GetRefreshBase(),
 		AllowInconsistent:  args.AllowInconsistent,
 		VolumeOnly:         !args.Snapshots,
 		Info:               &localMigration.Info{Config: srcConfig},
 		ClusterMove:        clusterMove,
 		StorageMove:        storageMove,
+		Verify:             verify,
+	}
+
+	volSourceArgs.Streams, err = d.migrationStreams(args.MigrateArgs, respHeader, encryption)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	filesystemConn, err = localMigration.EncryptConn(encryption, filesystemConn, api.SecretNameFilesystem)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	filesystemConn, err = localMigration.CompressConn(respHeader, filesystemConn)
+	if err != nil {
+		op.Done(err)
+		return err
 	}
 
 	// Only send the snapshots that the target requests when refreshing.
@@ -7034,6 +7383,12 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 			op.Done(err)
 			return err
 		}
+
+		stateConn, err = localMigration.EncryptConn(encryption, stateConn, api.SecretNameState)
+		if err != nil {
+			op.Done(err)
+			return err
+		}
 	}
 
 	g, ctx := errgroup.WithContext(context.Background())
@@ -7100,7 +7455,12 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 				defer instanceRefClear(d)
 			}
 
-			err = d.migrateSendLive(pool, args.ClusterMoveSourceName, args.StoragePool, blockSize, filesystemConn, stateConn, volSourceArgs)
+			err = d.migrateSendLive(pool, args.ClusterMoveSourceName, args.StoragePool, blockSize, filesystemConn, stateConn, volSourceArgs, respHeader.GetPostcopy())
+			if err != nil {
+				return err
+			}
+		} else if args.Live && respHeader.GetWarm() {
+			err = d.migrateSendWarm(pool, blockSize, filesystemConn, volSourceArgs, respHeader.GetRsyncFeaturesSlice())
 			if err != nil {
 				return err
 			}
@@ -7138,8 +7498,29 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 	}
 }
 
+// migrationProgress publishes the memory transfer progress of a live migration.
+func (d *qemu) migrationProgress(status qmp.MigrateStatus) {
+	if status.RAM == nil {
+		return
+	}
+
+	localMigration.UpdateProgress(d.op, func(progress *api.InstanceMigrationProgress) {
+		if progress.Phase != api.InstanceMigrationPhaseFinal {
+			progress.Phase = api.InstanceMigrationPhaseMemory
+		}
+
+		progress.Memory = &api.InstanceMigrationMemoryProgress{
+			Iteration:   status.RAM.DirtySyncCount,
+			Transferred: status.RAM.Transferred,
+			Remaining:   status.RAM.Remaining,
+			Total:       status.RAM.Total,
+			DirtyRate:   status.RAM.DirtyPagesRate,
+		}
+	})
+}
+
 // migrateSendLive performs live migration send process.
-func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName string, storagePool string, rootDiskSize int64, filesystemConn io.ReadWriteCloser, stateConn io.ReadWriteCloser, volSourceArgs *localMigration.VolumeSourceArgs) error {
+func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName string, storagePool string, rootDiskSize int64, filesystemConn io.ReadWriteCloser, stateConn io.ReadWriteCloser, volSourceArgs *localMigration.VolumeSourceArgs, postcopy bool) error {
 	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
 	if err != nil {
 		return err
@@ -7170,6 +7551,9 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 
 			// During storage migration encode blocks of zeroes efficiently.
 			"zero-blocks": true,
+
+			// Allow switching to post-copy memory transfer if negotiated with the target.
+			"postcopy-ram": postcopy,
 		}
 
 		err = monitor.MigrateSetCapabilities(capabilities)
@@ -7180,7 +7564,7 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 		// Create snapshot of the root disk.
 		// We use the VM's config volume for this so that the maximum size of the snapshot can be limited
 		// by setting the root disk's `size.state` property.
-		snapshotFile := filepath.Join(d.Path(), "migration_snapshot.qcow2")
+		snapshotFile := filepath.Join(d.Path(), qemuMigrationSnapshotFile)
 
 		// Ensure there are no existing migration snapshot files.
 		err = os.Remove(snapshotFile)
@@ -7267,6 +7651,9 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 		capabilities := map[string]bool{
 			// Automatically throttle down the guest to speed up convergence of RAM migration.
 			"auto-converge": true,
+
+			// Allow switching to post-copy memory transfer if negotiated with the target.
+			"postcopy-ram": postcopy,
 		}
 
 		err = monitor.MigrateSetCapabilities(capabilities)
@@ -7397,43 +7784,72 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 		d.logger.Debug("Migration storage snapshot transfer finished")
 	}
 
-	d.logger.Debug("Stateful migration checkpoint send starting")
+	d.logger.Debug("Stateful migration checkpoint send starting", logger.Ctx{"postcopy": postcopy})
 
 	// Send checkpoint to QEMU process on target. This will pause the guest OS (if not already paused).
-	pipeRead, pipeWrite, err := os.Pipe()
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = pipeRead.Close()
-		_ = pipeWrite.Close()
-	}()
+	if postcopy {
+		// Post-copy needs a bidirectional stream as the target requests pages from the source.
+		localSocket, remoteSocket, err := qemuMigrationSocketPair()
+		if err != nil {
+			return err
+		}
 
-	go func() { _, _ = io.Copy(stateConn, pipeRead) }()
+		defer func() {
+			_ = localSocket.Close()
+			_ = remoteSocket.Close()
+		}()
 
-	err = d.saveStateHandle(monitor, pipeWrite)
-	if err != nil {
-		return fmt.Errorf("Failed starting state transfer to target: %w", err)
-	}
+		go func() { _, _ = io.Copy(stateConn, localSocket) }()
+		go func() { _, _ = io.Copy(localSocket, stateConn) }()
 
-	// Non-shared storage snapshot transfer finalization.
-	if !sameSharedStorage {
-		// Wait until state transfer has reached pre-switchover state (the guest OS will remain paused).
-		err = monitor.MigrateWait("pre-switchover")
+		err = d.saveStateHandle(monitor, remoteSocket)
 		if err != nil {
-			return fmt.Errorf("Failed waiting for state transfer to reach pre-switchover stage: %w", err)
+			return fmt.Errorf("Failed starting state transfer to target: %w", err)
 		}
 
-		d.logger.Debug("Stateful migration checkpoint reached pre-switchover phase")
-
-		// Complete the migration snapshot sync process (the guest OS will remain paused).
-		d.logger.Debug("Migration storage snapshot transfer commit started")
-		err = monitor.BlockJobCancel(rootSnapshotDiskName)
+		// Request the switch to post-copy. QEMU performs it after the first pass over the guest memory,
+		// at which point the guest is resumed on the target and the remaining pages are pulled on demand.
+		err = monitor.MigrateStartPostcopy()
 		if err != nil {
-			return fmt.Errorf("Failed cancelling block job: %w", err)
+			return fmt.Errorf("Failed switching state transfer to post-copy: %w", err)
 		}
-
+	} else {
+		pipeRead, pipeWrite, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = pipeRead.Close()
+			_ = pipeWrite.Close()
+		}()
+
+		go func() { _, _ = io.Copy(stateConn, pipeRead) }()
+
+		err = d.saveStateHandle(monitor, pipeWrite)
+		if err != nil {
+			return fmt.Errorf("Failed starting state transfer to target: %w", err)
+		}
+	}
+
+	// Non-shared storage snapshot transfer finalization.
+	if !sameSharedStorage {
+		// Wait until state transfer has reached pre-switchover state (the guest OS will remain paused).
+		err = monitor.MigrateWaitProgress("pre-switchover", d.migrationProgress)
+		if err != nil {
+			return fmt.Errorf("Failed waiting for state transfer to reach pre-switchover stage: %w", err)
+		}
+
+		d.logger.Debug("Stateful migration checkpoint reached pre-switchover phase")
+		localMigration.SetProgressPhase(d.op, api.InstanceMigrationPhaseFinal)
+
+		// Complete the migration snapshot sync process (the guest OS will remain paused).
+		d.logger.Debug("Migration storage snapshot transfer commit started")
+		err = monitor.BlockJobCancel(rootSnapshotDiskName)
+		if err != nil {
+			return fmt.Errorf("Failed cancelling block job: %w", err)
+		}
+
 		d.logger.Debug("Migration storage snapshot transfer commit finished")
 
 		// Finalise the migration state transfer (the guest OS will remain paused).
@@ -7446,7 +7862,7 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	}
 
 	// Wait until the migration state transfer has completed (the guest OS will remain paused).
-	err = monitor.MigrateWait("completed")
+	err = monitor.MigrateWaitProgress("completed", d.migrationProgress)
 	if err != nil {
 		return fmt.Errorf("Failed waiting for state transfer to reach completed stage: %w", err)
 	}
@@ -7492,6 +7908,185 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	return nil
 }
 
+// migrateSendWarm performs the warm migration send process.
+// The root disk is transferred while the instance keeps running with its writes redirected to a temporary
+// snapshot. The instance is then statefully stopped and only the snapshot and the state are transferred.
+func (d *qemu) migrateSendWarm(pool storagePools.Pool, rootDiskSize int64, filesystemConn io.ReadWriteCloser, volSourceArgs *localMigration.VolumeSourceArgs, rsyncFeatures []string) error {
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return err
+	}
+
+	rootDiskName := "incus_root"                  // Name of source disk device to sync from
+	rootSnapshotDiskName := "incus_root_snapshot" // Name of snapshot disk device to use.
+
+	// Create snapshot of the root disk in the config volume.
+	// Unlike for live migration, the file is kept around as it's transferred once the instance is stopped.
+	snapshotFile := filepath.Join(d.Path(), qemuMigrationSnapshotFile)
+
+	// Ensure there are no existing migration snapshot files.
+	err = os.Remove(snapshotFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	_, err = subprocess.RunCommand("qemu-img", "create", "-f", "qcow2", snapshotFile, fmt.Sprintf("%d", rootDiskSize))
+	if err != nil {
+		return fmt.Errorf("Failed opening file image for migration storage snapshot %q: %w", snapshotFile, err)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(func() { _ = os.Remove(snapshotFile) })
+
+	// Pass the snapshot file to the running QEMU process.
+	snapFile, err := os.OpenFile(snapshotFile, unix.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("Failed opening file descriptor for migration storage snapshot %q: %w", snapshotFile, err)
+	}
+
+	info, err := monitor.SendFileWithFDSet(rootSnapshotDiskName, snapFile, false)
+	_ = snapFile.Close() // Don't prevent clean unmount when instance is stopped.
+	if err != nil {
+		return fmt.Errorf("Failed sending file descriptor of %q for migration storage snapshot: %w", snapshotFile, err)
+	}
+
+	reverter.Add(func() { _ = monitor.RemoveFDFromFDSet(rootSnapshotDiskName) })
+
+	// Add the snapshot file as a block device (not visible to the guest OS).
+	err = monitor.AddBlockDevice(map[string]any{
+		"driver":    "qcow2",
+		"node-name": rootSnapshotDiskName,
+		"read-only": false,
+		"file": map[string]any{
+			"driver":   "file",
+			"filename": fmt.Sprintf("/dev/fdset/%d", info.ID),
+		},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("Failed adding migration storage snapshot block device: %w", err)
+	}
+
+	reverter.Add(func() { _ = monitor.RemoveBlockDevice(rootSnapshotDiskName) })
+
+	// Take a snapshot of the root disk and redirect writes to the snapshot disk.
+	err = monitor.BlockDevSnapshot(rootDiskName, rootSnapshotDiskName)
+	if err != nil {
+		return fmt.Errorf("Failed taking temporary migration storage snapshot: %w", err)
+	}
+
+	reverter.Add(func() {
+		// Resume guest (this is needed as it will prevent merging the snapshot if paused).
+		err = monitor.Start()
+		if err != nil {
+			d.logger.Warn("Failed resuming instance", logger.Ctx{"err": err})
+		}
+
+		// Try and merge snapshot back to the source disk on failure so we don't lose writes.
+		err = monitor.BlockCommit(rootSnapshotDiskName)
+		if err != nil {
+			d.logger.Error("Failed merging migration storage snapshot", logger.Ctx{"err": err})
+		}
+	})
+
+	d.logger.Debug("Setup temporary migration storage snapshot")
+
+	// Perform storage transfer while instance is still running.
+	// We enable AllowInconsistent mode as the snapshot we took earlier is designed to provide consistency.
+	volSourceArgs.AllowInconsistent = true
+	err = pool.MigrateInstance(d, filesystemConn, volSourceArgs, d.op)
+	if err != nil {
+		return err
+	}
+
+	localMigration.SetProgressPhase(d.op, api.InstanceMigrationPhaseFinal)
+
+	// Statefully stop the instance, this leaves the snapshot file with the writes since the transfer started.
+	d.logger.Debug("Warm migration stateful stop started")
+	err = d.Stop(true)
+	if err != nil {
+		return fmt.Errorf("Failed statefully stopping instance: %w", err)
+	}
+
+	d.logger.Debug("Warm migration stateful stop finished")
+
+	// The QEMU process is gone, so the snapshot can now only be merged offline.
+	reverter.Success()
+
+	mountInfo, err := pool.MountInstance(d, d.op)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = pool.UnmountInstance(d, d.op) }()
+
+	// Merge the snapshot back into the source disk once transferred so that the instance can be started again.
+	defer func() {
+		err := d.mergeMigrationSnapshot(mountInfo.DiskPath)
+		if err != nil {
+			d.logger.Error("Failed merging migration storage snapshot", logger.Ctx{"err": err})
+		}
+	}()
+
+	// Transfer the config volume, which now holds the snapshot and the instance state.
+	d.logger.Debug("Warm migration final sync started")
+	err = rsync.Send(d.name, internalUtil.AddSlash(d.Path()), filesystemConn, nil, nil, rsyncFeatures, pool.Driver().Config()["rsync.bwlimit"], d.state.OS.ExecPath)
+	if err != nil {
+		return err
+	}
+
+	d.logger.Debug("Warm migration final sync finished")
+
+	return nil
+}
+
+// migrateReceiveWarm receives the final sync of a warm migration and applies it to the root disk.
+func (d *qemu) migrateReceiveWarm(pool storagePools.Pool, filesystemConn io.ReadWriteCloser, rsyncFeatures []string) error {
+	mountInfo, err := pool.MountInstance(d, d.op)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = pool.UnmountInstance(d, d.op) }()
+
+	d.logger.Debug("Warm migration final sync receive started")
+	err = rsync.Recv(internalUtil.AddSlash(d.Path()), filesystemConn, nil, rsyncFeatures)
+	if err != nil {
+		return err
+	}
+
+	d.logger.Debug("Warm migration final sync receive finished")
+
+	return d.mergeMigrationSnapshot(mountInfo.DiskPath)
+}
+
+// mergeMigrationSnapshot merges the warm migration snapshot file of the config volume into the root disk.
+// The instance's volumes must be mounted.
+func (d *qemu) mergeMigrationSnapshot(diskPath string) error {
+	snapshotFile := filepath.Join(d.Path(), qemuMigrationSnapshotFile)
+	if !util.PathExists(snapshotFile) {
+		return nil
+	}
+
+	if diskPath == "" {
+		return errors.New("No disk path available from mount")
+	}
+
+	// Point the snapshot to the local root disk as the recorded one is only valid in the QEMU process.
+	_, err := subprocess.RunCommand("qemu-img", "rebase", "-u", "-f", "qcow2", "-b", diskPath, "-F", "raw", snapshotFile)
+	if err != nil {
+		return fmt.Errorf("Failed setting backing disk of migration storage snapshot: %w", err)
+	}
+
+	_, err = subprocess.RunCommand("qemu-img", "commit", "-f", "qcow2", snapshotFile)
+	if err != nil {
+		return fmt.Errorf("Failed merging migration storage snapshot: %w", err)
+	}
+
+	return os.Remove(snapshotFile)
+}
+
 func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 	d.logger.Debug("Migration receive starting")
 	defer d.logger.Debug("Migration receive stopped")
@@ -7549,30 +8144,6 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 	// However, to determine the correct migration type Refresh needs to be set.
 	offerHeader.Refresh = &args.Refresh
 
-	clusterMove := args.ClusterMoveSourceName != ""
-	storageMove := args.StoragePool != ""
-
-	// Extract the source's migration type and then match it against our pool's supported types and features.
-	// If a match is found the combined features list will be sent back to requester.
-	contentType := storagePools.InstanceContentType(d)
-	respTypes, err := localMigration.MatchTypes(offerHeader, storagePools.FallbackMigrationType(contentType), pool.MigrationTypes(contentType, args.Refresh, args.Snapshots, clusterMove, storageMove))
-	if err != nil {
-		return err
-	}
-
-	// The migration header to be sent back to source with our target options.
-	// Convert response type to response header and copy snapshot info into it.
-	respHeader := localMigration.TypesToHeader(respTypes...)
-
-	// Respond with our maximum supported header version if the requested version is higher than ours.
-	// Otherwise just return the requested header version to the source.
-	indexHeaderVersion := min(offerHeader.GetIndexHeaderVersion(), localMigration.IndexHeaderVersion)
-
-	respHeader.IndexHeaderVersion = &indexHeaderVersion
-	respHeader.SnapshotNames = offerHeader.SnapshotNames
-	respHeader.Snapshots = offerHeader.Snapshots
-	respHeader.Refresh = &args.Refresh
-
 	if args.Refresh {
 		// Get the remote snapshots on the source.
 		sourceSnapshots := offerHeader.GetSnapshots()
@@ -7603,6 +8174,10 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 		// Compare the two sets.
 		syncSourceSnapshotIndexes, deleteTargetSnapshotIndexes := storagePools.CompareSnapshots(sourceSnapshotComparable, targetSnapshotsComparable, args.RefreshExcludeOlder)
 
+		// Look for a snapshot from which the changes can be received as a diff.
+		// This is done before matching the migration types as some of them depend on it.
+		offerHeader.RefreshBase = proto.String(storagePools.RefreshBaseSnapshot(sourceSnapshotComparable, targetSnapshotsComparable, syncSourceSnapshotIndexes))
+
 		// Delete the extra local snapshots first.
 		for _, deleteTargetSnapshotIndex := range deleteTargetSnapshotIndexes {
 			err := targetSnapshots[deleteTargetSnapshotIndex].Delete(true)
@@ -7619,21 +8194,74 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 			syncSnapshots = append(syncSnapshots, sourceSnapshots[syncSourceSnapshotIndex])
 		}
 
-		respHeader.Snapshots = syncSnapshots
-		respHeader.SnapshotNames = syncSnapshotNames
 		offerHeader.Snapshots = syncSnapshots
 		offerHeader.SnapshotNames = syncSnapshotNames
 	}
 
+	clusterMove := args.ClusterMoveSourceName != ""
+	storageMove := args.StoragePool != ""
+
+	// Extract the source's migration type and then match it against our pool's supported types and features.
+	// If a match is found the combined features list will be sent back to requester.
+	contentType := storagePools.InstanceContentType(d)
+	respTypes, err := localMigration.MatchTypes(offerHeader, storagePools.FallbackMigrationType(contentType), pool.MigrationTypes(contentType, args.Refresh, args.Snapshots, clusterMove, storageMove))
+	if err != nil {
+		return err
+	}
+
+	// The migration header to be sent back to source with our target options.
+	// Convert response type to response header and copy snapshot info into it.
+	respHeader := localMigration.TypesToHeader(respTypes...)
+
+	// Respond with our maximum supported header version if the requested version is higher than ours.
+	// Otherwise just return the requested header version to the source.
+	indexHeaderVersion := min(offerHeader.GetIndexHeaderVersion(), localMigration.IndexHeaderVersion)
+
+	respHeader.IndexHeaderVersion = &indexHeaderVersion
+	localMigration.NegotiateFilesystemStreams(offerHeader, respHeader, args.FilesystemStreams)
+	localMigration.NegotiateCompression(offerHeader, respHeader, d.state.GlobalConfig.MigrationCompression())
+
+	encryption, err := localMigration.NegotiateEncryption(offerHeader, respHeader, d.expandedConfig["migration.encryption"])
+	if err != nil {
+		return err
+	}
+
+	verify, err := localMigration.NegotiateVerify(offerHeader, respHeader, args.Verify)
+	if err != nil {
+		return err
+	}
+
+	if verify && args.Live {
+		return errors.New("Migration verification isn't supported for live migrations")
+	}
+
+	respHeader.SnapshotNames = offerHeader.SnapshotNames
+	respHeader.Snapshots = offerHeader.Snapshots
+	respHeader.Refresh = &args.Refresh
+	respHeader.RefreshBase = offerHeader.RefreshBase
+
 	// Negotiate support for QEMU to QEMU live state transfer.
 	// If the request is for live migration, then respond that live QEMU to QEMU state transfer can proceed.
 	// Otherwise we'll fallback to doing stateful stop, migrate, and then stateful start, which will still
 	// fulfil the "live" part of the request, albeit with longer pause of the instance during the process.
 	poolInfo := pool.Driver().Info()
 	var useStateConn bool
+	var usePostcopy bool
 	if args.Live && offerHeader.Criu != nil && *offerHeader.Criu == migration.CRIUType_VM_QEMU {
 		respHeader.Criu = migration.CRIUType_VM_QEMU.Enum()
 		useStateConn = true
+
+		// Accept post-copy memory transfer if offered by the source.
+		if offerHeader.GetPostcopy() {
+			respHeader.Postcopy = proto.Bool(true)
+			usePostcopy = true
+		}
+	}
+
+	// Accept warm migration if offered by the source.
+	useWarm := args.Live && offerHeader.GetWarm()
+	if useWarm {
+		respHeader.Warm = proto.Bool(true)
 	}
 
 	// Send response to source.
@@ -7645,6 +8273,22 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 
 	d.logger.Debug("Sent migration response to source")
 
+	// Establish the additional filesystem connections if agreed on.
+	streams, err := d.migrationStreams(args.MigrateArgs, respHeader, encryption)
+	if err != nil {
+		return err
+	}
+
+	filesystemConn, err = localMigration.EncryptConn(encryption, filesystemConn, api.SecretNameFilesystem)
+	if err != nil {
+		return err
+	}
+
+	filesystemConn, err = localMigration.CompressConn(respHeader, filesystemConn)
+	if err != nil {
+		return err
+	}
+
 	// Establish state transfer connection if needed.
 	var stateConn io.ReadWriteCloser
 	if args.Live && useStateConn {
@@ -7652,6 +8296,11 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 		if err != nil {
 			return err
 		}
+
+		stateConn, err = localMigration.EncryptConn(encryption, stateConn, api.SecretNameState)
+		if err != nil {
+			return err
+		}
 	}
 
 	reverter := revert.New()
@@ -7748,12 +8397,16 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 			Name:                  d.Name(),
 			MigrationType:         respTypes[0],
 			Refresh:               args.Refresh,                // Indicate to receiver volume should exist.
+			RefreshBase:           respHeader.GetRefreshBase(), // Snapshot on top of which changes are received.
 			TrackProgress:         true,                        // Use a progress tracker on receiver to get in-cluster progress information.
 			Live:                  false,                       // Indicates we won't get a final rootfs sync.
 			VolumeSize:            offerHeader.GetVolumeSize(), // Block size setting override.
 			VolumeOnly:            !args.Snapshots,
 			ClusterMoveSourceName: args.ClusterMoveSourceName,
 			StoragePool:           args.StoragePool,
+			Streams:               streams,
+			Verify:                verify,
+			KeepPartial:           args.KeepPartial,
 		}
 
 		// At this point we have already figured out the parent instances's root
@@ -7810,7 +8463,12 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 						return fmt.Errorf("Failed creating instance snapshot record %q: %w", snapArgs.Name, err)
 					}
 
-					reverter.Add(cleanup)
+					// Snapshots that were received are kept when resuming is possible.
+					// The others are removed by the caller.
+					if !args.KeepPartial {
+						reverter.Add(cleanup)
+					}
+
 					defer snapInstOp.Done(err)
 				}
 			}
@@ -7860,9 +8518,9 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 		}
 
 		// Only delete all instance volumes on error if the pool volume creation has succeeded to
-		// avoid deleting an existing conflicting volume.
+		// avoid deleting an existing conflicting volume, and keep them if the migration can be resumed.
 		isRemoteClusterMove := clusterMove && poolInfo.Remote
-		if !volTargetArgs.Refresh && !isRemoteClusterMove {
+		if !volTargetArgs.Refresh && !isRemoteClusterMove && !args.KeepPartial {
 			reverter.Add(func() {
 				snapshots, _ := d.Snapshots()
 				snapshotCount := len(snapshots)
@@ -7884,12 +8542,22 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 		}
 
 		if args.Live {
+			// Receive the writes and the state of a warm migration.
+			if useWarm {
+				err = d.migrateReceiveWarm(pool, filesystemConn, respHeader.GetRsyncFeaturesSlice())
+				if err != nil {
+					return err
+				}
+			}
+
 			// Start live state transfer using state connection if supported.
 			if stateConn != nil {
 				d.migrationReceiveStateful = map[string]io.ReadWriteCloser{
 					api.SecretNameState: stateConn,
 				}
 
+				d.migrationReceivePostcopy = usePostcopy
+
 				// Populate the filesystem connection handle if doing non-shared storage migration.
 				sameSharedStorage := args.ClusterMoveSourceName != "" && poolInfo.Remote && args.StoragePool == ""
 				if !sameSharedStorage {
@@ -8057,10 +8725,24 @@ func (d *qemu) FileSFTP() (*sftp.Client, error) {
 
 // Console gets access to the instance's console.
 func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
+	return d.console(protocol, "")
+}
+
+// SerialConsole attaches to one of the additional serial consoles of the instance.
+func (d *qemu) SerialConsole(name string) (*os.File, chan error, error) {
+	if !slices.Contains(d.serialConsoleNames(), name) {
+		return nil, nil, api.StatusErrorf(http.StatusNotFound, "Serial console %q not found", name)
+	}
+
+	return d.console(instance.ConsoleTypeConsole, name)
+}
+
+// console attaches to the VGA console or to one of the serial consoles, the main one being unnamed.
+func (d *qemu) console(protocol string, name string) (*os.File, chan error, error) {
 	var path string
 	switch protocol {
 	case instance.ConsoleTypeConsole:
-		path = d.consolePath()
+		path = d.consoleSocketPath(name)
 	case instance.ConsoleTypeVGA:
 		path = d.spicePath()
 	default:
@@ -8072,16 +8754,16 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 		// Look for existing connections and reset.
 		conn, err := net.Dial("unix", path)
 		if err == nil {
-			_ = d.consoleSwapSocketWithRB()
+			_ = d.consoleSwapSocketWithRB(name)
 			_ = conn.Close()
 
 			// Allow for cleanup to complete on the existing connection.
 			time.Sleep(time.Second)
 		}
 
-		err = d.consoleSwapRBWithSocket()
+		err = d.consoleSwapRBWithSocket(name)
 		if err != nil {
-			_ = d.consoleSwapSocketWithRB()
+			_ = d.consoleSwapSocketWithRB(name)
 			return nil, nil, fmt.Errorf("Failed to swap console ring buffer with socket: %w", err)
 		}
 	}
@@ -8093,7 +8775,7 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 	conn, err := net.Dial("unix", path)
 	if err != nil {
 		if protocol == instance.ConsoleTypeConsole {
-			_ = d.consoleSwapSocketWithRB()
+			_ = d.consoleSwapSocketWithRB(name)
 		}
 
 		return nil, nil, fmt.Errorf("Connect to console socket %q: %w", path, err)
@@ -8102,7 +8784,7 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 	file, err := (conn.(*net.UnixConn)).File()
 	if err != nil {
 		if protocol == instance.ConsoleTypeConsole {
-			_ = d.consoleSwapSocketWithRB()
+			_ = d.consoleSwapSocketWithRB(name)
 		}
 
 		return nil, nil, fmt.Errorf("Get socket file: %w", err)
@@ -8113,10 +8795,15 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 	// Handle disconnections.
 	go func() {
 		<-chDisconnect
-		_ = d.consoleSwapSocketWithRB()
+		_ = d.consoleSwapSocketWithRB(name)
 	}()
 
-	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceConsole.Event(d, logger.Ctx{"type": protocol}))
+	eventCtx := logger.Ctx{"type": protocol}
+	if name != "" {
+		eventCtx["name"] = name
+	}
+
+	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceConsole.Event(d, eventCtx))
 
 	return file, chDisconnect, nil
 }
@@ -8215,6 +8902,15 @@ func (d *qemu) RenderWithUsage() (any, any, error) {
 	// It is important that the snapshot not be mounted here as mounting a snapshot can trigger a very
 	// expensive filesystem UUID regeneration, so we rely on the driver implementation to get the info
 	// we are requesting as cheaply as possible.
+	snapResp.Used = -1
+	snapResp.Unique = -1
+
+	snapUsage, err := pool.GetInstanceSnapshotUsage(d)
+	if err == nil {
+		snapResp.Used = snapUsage.Used
+		snapResp.Unique = snapUsage.Unique
+	}
+
 	volumeState, err := pool.GetInstanceUsage(d)
 	if err != nil {
 		return resp, etag, nil
@@ -8254,7 +8950,7 @@ func (d *qemu) Render() (any, any, error) {
 	}
 
 	// Prepare the response.
-	statusCode := d.statusCode()
+	statusCode := d.renderStatusCode(d.statusCode())
 	instState := api.Instance{
 		ExpandedConfig:  d.expandedConfig,
 		ExpandedDevices: d.expandedDevices.CloneNative(),
@@ -8440,7 +9136,7 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 
 // RenderState returns just state info about the instance.
 func (d *qemu) RenderState(hostInterfaces []net.Interface) (*api.InstanceState, error) {
-	return d.renderState(d.statusCode())
+	return d.renderState(d.renderStatusCode(d.statusCode()))
 }
 
 // diskState gets disk usage info.
@@ -8596,6 +9292,11 @@ func (d *qemu) DeviceEventHandler(runConf *deviceConfig.RunConfig) error {
 			if err != nil {
 				return fmt.Errorf("Failed updating disk size %q: %w", mount.DevName, err)
 			}
+
+			// Have the agent grow the partition and filesystem onto the new space.
+			if util.IsTrue(d.expandedConfig["agent.disk_grow"]) {
+				go d.growGuestDisk(qemuBlockDevIDPrefix + linux.PathNameEncode(mount.DevName))
+			}
 		}
 	}
 
@@ -9713,6 +10414,43 @@ func (d *qemu) postCPUHotplug(monitor *qmp.Monitor) error {
 
 // ConsoleLog returns all output sent to the instance's console's ring buffer since startup.
 func (d *qemu) ConsoleLog() (string, error) {
+	return d.consoleLog("")
+}
+
+// SerialConsoleLog returns all output sent to one of the additional serial consoles since startup.
+func (d *qemu) SerialConsoleLog(name string) (string, error) {
+	if !slices.Contains(d.serialConsoleNames(), name) {
+		return "", api.StatusErrorf(http.StatusNotFound, "Serial console %q not found", name)
+	}
+
+	if !d.IsRunning() {
+		fullLog, err := os.ReadFile(d.consoleLogPath(name))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return "", nil
+			}
+
+			return "", err
+		}
+
+		return string(fullLog), nil
+	}
+
+	return d.consoleLog(name)
+}
+
+// saveSerialConsoleLogs saves the ring buffers of the additional serial consoles to their log files.
+func (d *qemu) saveSerialConsoleLogs() {
+	for _, name := range d.serialConsoleNames() {
+		_, err := d.consoleLog(name)
+		if err != nil {
+			d.logger.Warn("Failed saving serial console log", logger.Ctx{"name": name, "err": err})
+		}
+	}
+}
+
+// consoleLog saves the ring buffer of a serial console to its log file and returns the complete log.
+func (d *qemu) consoleLog(name string) (string, error) {
 	// Setup a new operation.
 	op, err := operationlock.CreateWaitGet(d.Project().Name, d.Name(), d.op, operationlock.ActionConsoleRetrieve, []operationlock.Action{operationlock.ActionRestart, operationlock.ActionRestore, operationlock.ActionMigrate}, false, true)
 	if err != nil {
@@ -9730,7 +10468,7 @@ func (d *qemu) ConsoleLog() (string, error) {
 		return "", err
 	}
 
-	logString, err := monitor.RingbufRead("console")
+	logString, err := monitor.RingbufRead(consoleChardevName(name))
 	if err != nil {
 		// If a VM was started by an older version of Incus which was then upgraded, its
 		// console device won't be a ring buffer. We don't want to cause an error in this
@@ -9744,7 +10482,7 @@ func (d *qemu) ConsoleLog() (string, error) {
 
 	// If we got data back, append it to the log file for this instance.
 	if logString != "" {
-		logFile, err := os.OpenFile(d.ConsoleBufferLogPath(), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+		logFile, err := os.OpenFile(d.consoleLogPath(name), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
 		if err != nil {
 			return "", err
 		}
@@ -9758,7 +10496,7 @@ func (d *qemu) ConsoleLog() (string, error) {
 	}
 
 	// Read and return the complete log for this instance.
-	fullLog, err := os.ReadFile(d.ConsoleBufferLogPath())
+	fullLog, err := os.ReadFile(d.consoleLogPath(name))
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			// If there's no log file yet, such as right at VM creation, return an empty string.
@@ -9771,10 +10509,42 @@ func (d *qemu) ConsoleLog() (string, error) {
 	return string(fullLog), nil
 }
 
-// consoleSwapRBWithSocket swaps the qemu backend for the instance's console to a unix socket.
-func (d *qemu) consoleSwapRBWithSocket() error {
+// consoleChardevName returns the name of the chardev backing a serial console, the main console being unnamed.
+func consoleChardevName(name string) string {
+	if name == "" {
+		return "console"
+	}
+
+	return fmt.Sprintf("console_%s", name)
+}
+
+// consoleSocketPath returns the path of the socket used to connect to a serial console.
+func (d *qemu) consoleSocketPath(name string) string {
+	if name == "" {
+		return d.consolePath()
+	}
+
+	return filepath.Join(d.RunPath(), fmt.Sprintf("qemu.console.%s", name))
+}
+
+// consoleLogPath returns the path of the log file of a serial console.
+func (d *qemu) consoleLogPath(name string) string {
+	if name == "" {
+		return d.ConsoleBufferLogPath()
+	}
+
+	return filepath.Join(d.LogPath(), fmt.Sprintf("console.%s.log", name))
+}
+
+// serialConsoleNames returns the names of the additional serial consoles.
+func (d *qemu) serialConsoleNames() []string {
+	return util.SplitNTrimSpace(d.expandedConfig["console.serials"], ",", -1, true)
+}
+
+// consoleSwapRBWithSocket swaps the qemu backend for a serial console to a unix socket.
+func (d *qemu) consoleSwapRBWithSocket(name string) error {
 	// This will wipe out anything in the existing ring buffer; save any buffered data to log file first.
-	_, err := d.ConsoleLog()
+	_, err := d.consoleLog(name)
 	if err != nil {
 		return err
 	}
@@ -9785,24 +10555,41 @@ func (d *qemu) consoleSwapRBWithSocket() error {
 		return err
 	}
 
+	d.consoleSocketsMu.Lock()
+	defer d.consoleSocketsMu.Unlock()
+
+	if d.consoleSockets == nil {
+		d.consoleSockets = map[string]*net.UnixListener{}
+		d.consoleSocketFiles = map[string]*os.File{}
+	}
+
 	// Create the unix socket here, which will be passed via file descriptor to qemu.
-	d.consoleSocket, err = net.ListenUnix("unix", &net.UnixAddr{Name: d.consolePath(), Net: "unix"})
+	socketPath := d.consoleSocketPath(name)
+	socket, err := net.ListenUnix("unix", &net.UnixAddr{Name: socketPath, Net: "unix"})
 	if err != nil {
 		return err
 	}
 
-	d.consoleSocketFile, err = d.consoleSocket.File()
+	socketFile, err := socket.File()
 	if err != nil {
-		_ = d.consoleSocket.Close()
-		_ = os.Remove(d.consolePath())
+		_ = socket.Close()
+		_ = os.Remove(socketPath)
 		return err
 	}
 
-	return monitor.ChardevChange("console", qmp.ChardevChangeInfo{Type: "socket", FDName: "consoleSocket", File: d.consoleSocketFile})
+	d.consoleSockets[name] = socket
+	d.consoleSocketFiles[name] = socketFile
+
+	fdName := "consoleSocket"
+	if name != "" {
+		fdName = fmt.Sprintf("consoleSocket_%s", name)
+	}
+
+	return monitor.ChardevChange(consoleChardevName(name), qmp.ChardevChangeInfo{Type: "socket", FDName: fdName, File: socketFile})
 }
 
-// consoleSwapSocketWithRB swaps the qemu backend for the instance's console to a ring buffer.
-func (d *qemu) consoleSwapSocketWithRB() error {
+// consoleSwapSocketWithRB swaps the qemu backend for a serial console to a ring buffer.
+func (d *qemu) consoleSwapSocketWithRB(name string) error {
 	// Check if the agent is running.
 	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
 	if err != nil {
@@ -9810,13 +10597,26 @@ func (d *qemu) consoleSwapSocketWithRB() error {
 	}
 
 	defer func() {
+		d.consoleSocketsMu.Lock()
+		defer d.consoleSocketsMu.Unlock()
+
 		// Clean up the old socket.
-		_ = d.consoleSocketFile.Close()
-		_ = d.consoleSocket.Close()
-		_ = os.Remove(d.consolePath())
+		socketFile := d.consoleSocketFiles[name]
+		if socketFile != nil {
+			_ = socketFile.Close()
+		}
+
+		socket := d.consoleSockets[name]
+		if socket != nil {
+			_ = socket.Close()
+		}
+
+		delete(d.consoleSocketFiles, name)
+		delete(d.consoleSockets, name)
+		_ = os.Remove(d.consoleSocketPath(name))
 	}()
 
-	return monitor.ChardevChange("console", qmp.ChardevChangeInfo{Type: "ringbuf"})
+	return monitor.ChardevChange(consoleChardevName(name), qmp.ChardevChangeInfo{Type: "ringbuf"})
 }
 
 // ConsoleScreenshot returns a screenshot of the current VGA console in PNG format.
@@ -9855,6 +10655,122 @@ func (d *qemu) ReloadDevice(devName string) error {
 	return dev.Update(d.expandedDevices, true)
 }
 
+// QMPPassthrough runs one of the allowed QMP commands against the running VM and returns its result.
+func (d *qemu) QMPPassthrough(command string, args map[string]any) (any, error) {
+	if !d.IsRunning() {
+		return nil, errors.New("Instance is not running")
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	return monitor.Passthrough(command, args)
+}
+
+// VCPUThreads returns the host thread IDs of the vCPUs of the running VM.
+func (d *qemu) VCPUThreads() ([]int, error) {
+	if !d.IsRunning() {
+		return nil, errors.New("Instance is not running")
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	return monitor.GetCPUs()
+}
+
+// deviceMediaBlock returns the block info of the removable drive backing a disk device.
+func (d *qemu) deviceMediaBlock(monitor *qmp.Monitor, devName string) (*qmp.BlockInfo, error) {
+	dev, ok := d.expandedDevices[devName]
+	if !ok || dev["type"] != "disk" {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Disk device %q not found", devName)
+	}
+
+	blocks, err := monitor.QueryBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	qemuDevID := fmt.Sprintf("%s%s", qemuDeviceIDPrefix, linux.PathNameEncode(devName))
+	for _, block := range blocks {
+		if block.QDev != qemuDevID {
+			continue
+		}
+
+		if !block.Removable {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Disk device %q doesn't have removable media", devName)
+		}
+
+		return &block, nil
+	}
+
+	return nil, api.StatusErrorf(http.StatusBadRequest, "Disk device %q isn't attached as a removable drive", devName)
+}
+
+// DeviceMediaInserted returns whether media is inserted in the removable drive of a disk device.
+func (d *qemu) DeviceMediaInserted(devName string) (bool, error) {
+	if !d.IsRunning() {
+		return false, errors.New("Instance is not running")
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return false, err
+	}
+
+	block, err := d.deviceMediaBlock(monitor, devName)
+	if err != nil {
+		return false, err
+	}
+
+	return block.Inserted != nil, nil
+}
+
+// SetDeviceMediaInserted inserts or ejects the media of the removable drive of a disk device.
+// Ejected media stays out across guest reboots and is inserted again on the next start.
+func (d *qemu) SetDeviceMediaInserted(devName string, inserted bool) error {
+	if !d.IsRunning() {
+		return errors.New("Instance is not running")
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return err
+	}
+
+	block, err := d.deviceMediaBlock(monitor, devName)
+	if err != nil {
+		return err
+	}
+
+	if inserted != (block.Inserted != nil) {
+		if inserted {
+			// The block node is kept around by QEMU once ejected and can be inserted again.
+			err = monitor.InsertMedium(block.QDev, d.blockNodeName(linux.PathNameEncode(devName)))
+			if err != nil {
+				return fmt.Errorf("Failed inserting media of disk device %q: %w", devName, err)
+			}
+		} else {
+			err = monitor.Eject(block.QDev)
+			if err != nil {
+				return fmt.Errorf("Failed ejecting media of disk device %q: %w", devName, err)
+			}
+		}
+	}
+
+	// Record the state so that ejected media stays out across guest reboots.
+	ejected := ""
+	if !inserted {
+		ejected = "true"
+	}
+
+	return d.VolatileSet(map[string]string{fmt.Sprintf("volatile.%s.last_state.media_ejected", devName): ejected})
+}
+
 // DumpGuestMemory dumps the guest memory to a file in the specified format.
 func (d *qemu) DumpGuestMemory(w *os.File, format string) error {
 	if !d.IsRunning() {
@@ -173,6 +173,41 @@ func TestQemuConfigTemplates(t *testing.T) {
 		}
 	})
 
+	t.Run("qemu_serial_console", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuSerialConsoleOpts
+			expected string
+		}{{
+			qemuSerialConsoleOpts{"admin", 1, true},
+			`# Serial console ("admin")
+			[chardev "console_admin"]
+			backend = "ringbuf"
+			size = "1048576"
+
+			[device "qemu_serial-console_admin"]
+			chardev = "console_admin"
+			driver = "isa-serial"
+			index = "1"
+			`,
+		}, {
+			qemuSerialConsoleOpts{"log", 2, false},
+			`# Serial console ("log")
+			[chardev "console_log"]
+			backend = "ringbuf"
+			size = "1048576"
+
+			[device "qemu_serial-console_log"]
+			bus = "dev-qemu_serial.0"
+			chardev = "console_log"
+			driver = "virtconsole"
+			name = "log"
+			`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuSerialConsole(&tc.opts))
+		}
+	})
+
 	t.Run("qemu_pcie", func(t *testing.T) {
 		testCases := []struct {
 			opts     qemuPCIeOpts
@@ -964,6 +999,35 @@ func TestQemuConfigTemplates(t *testing.T) {
 		}
 	})
 
+	t.Run("qemu_shmem", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuShmemOpts
+			expected string
+		}{{
+			qemuShmemOpts{
+				dev:     qemuDevOpts{"pcie", "qemu_pcie1", "00.0", false},
+				devName: "ipc",
+				path:    "/run/incus/shmem/default/ipc",
+				size:    "4194304",
+			},
+			`[object "qemu_shmem-mem_ipc"]
+			mem-path = "/run/incus/shmem/default/ipc"
+			qom-type = "memory-backend-file"
+			share = "on"
+			size = "4194304"
+
+			# Shared memory ("ipc" device)
+			[device "dev-incus_ipc"]
+			addr = "00.0"
+			bus = "qemu_pcie1"
+			driver = "ivshmem-plain"
+			memdev = "qemu_shmem-mem_ipc"`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuShmem(&tc.opts))
+		}
+	})
+
 	t.Run("qemu_gpu_dev_physical", func(t *testing.T) {
 		testCases := []struct {
 			opts     qemuGPUDevPhysicalOpts
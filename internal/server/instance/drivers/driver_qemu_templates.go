@@ -666,6 +666,45 @@ func qemuControlSocket(opts *qemuControlSocketOpts) []cfg.Section {
 	}}
 }
 
+type qemuSerialConsoleOpts struct {
+	name  string
+	index int
+	isa   bool
+}
+
+func qemuSerialConsole(opts *qemuSerialConsoleOpts) []cfg.Section {
+	chardev := consoleChardevName(opts.name)
+
+	var entries map[string]string
+	if opts.isa {
+		// Follow the main console, which is the first serial port.
+		entries = map[string]string{
+			"driver":  "isa-serial",
+			"chardev": chardev,
+			"index":   fmt.Sprintf("%d", opts.index),
+		}
+	} else {
+		entries = map[string]string{
+			"driver":  "virtconsole",
+			"chardev": chardev,
+			"name":    opts.name,
+			"bus":     "dev-qemu_serial.0",
+		}
+	}
+
+	return []cfg.Section{{
+		Name:    fmt.Sprintf(`chardev "%s"`, chardev),
+		Comment: fmt.Sprintf(`Serial console ("%s")`, opts.name),
+		Entries: map[string]string{
+			"backend": "ringbuf",
+			"size":    "1048576",
+		},
+	}, {
+		Name:    fmt.Sprintf(`device "qemu_serial-%s"`, chardev),
+		Entries: entries,
+	}}
+}
+
 func qemuConsole() []cfg.Section {
 	return []cfg.Section{{
 		Name:    `chardev "console"`,
@@ -827,6 +866,7 @@ type qemuPCIPhysicalOpts struct {
 	dev         qemuDevOpts
 	devName     string
 	pciSlotName string
+	bootIndex   string
 }
 
 func qemuPCIPhysical(opts *qemuPCIPhysicalOpts) []cfg.Section {
@@ -838,6 +878,9 @@ func qemuPCIPhysical(opts *qemuPCIPhysicalOpts) []cfg.Section {
 
 	entries := qemuDeviceEntries(&deviceOpts)
 	entries["host"] = opts.pciSlotName
+	if opts.bootIndex != "" {
+		entries["bootindex"] = opts.bootIndex
+	}
 
 	return []cfg.Section{{
 		Name:    fmt.Sprintf(`device "%s%s"`, qemuDeviceIDPrefix, opts.devName),
@@ -846,6 +889,39 @@ func qemuPCIPhysical(opts *qemuPCIPhysicalOpts) []cfg.Section {
 	}}
 }
 
+type qemuShmemOpts struct {
+	dev     qemuDevOpts
+	devName string
+	path    string
+	size    string
+}
+
+func qemuShmem(opts *qemuShmemOpts) []cfg.Section {
+	memdev := fmt.Sprintf("qemu_shmem-mem_%s", opts.devName)
+
+	deviceOpts := qemuDevEntriesOpts{
+		dev:     opts.dev,
+		pciName: "ivshmem-plain",
+	}
+
+	entries := qemuDeviceEntries(&deviceOpts)
+	entries["memdev"] = memdev
+
+	return []cfg.Section{{
+		Name: fmt.Sprintf(`object "%s"`, memdev),
+		Entries: map[string]string{
+			"qom-type": "memory-backend-file",
+			"mem-path": opts.path,
+			"size":     opts.size,
+			"share":    "on",
+		},
+	}, {
+		Name:    fmt.Sprintf(`device "%s%s"`, qemuDeviceIDPrefix, opts.devName),
+		Comment: fmt.Sprintf(`Shared memory ("%s" device)`, opts.devName),
+		Entries: entries,
+	}}
+}
+
 type qemuGPUDevPhysicalOpts struct {
 	dev         qemuDevOpts
 	devName     string
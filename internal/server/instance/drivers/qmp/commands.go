@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -400,17 +401,36 @@ func (m *Monitor) Migrate(name string) error {
 	return nil
 }
 
+// MigrateRAMStatus represents the RAM transfer statistics of a migration job.
+type MigrateRAMStatus struct {
+	Transferred    int64 `json:"transferred"`
+	Remaining      int64 `json:"remaining"`
+	Total          int64 `json:"total"`
+	DirtyPagesRate int64 `json:"dirty-pages-rate"`
+	DirtySyncCount int64 `json:"dirty-sync-count"`
+}
+
+// MigrateStatus represents the status of a migration job.
+type MigrateStatus struct {
+	Status string            `json:"status"`
+	RAM    *MigrateRAMStatus `json:"ram"`
+}
+
 // MigrateWait waits until migration job reaches the specified status.
 // Returns nil if the migraton job reaches the specified status or an error if the migration job is in the failed
 // status.
 func (m *Monitor) MigrateWait(state string) error {
+	return m.MigrateWaitProgress(state, nil)
+}
+
+// MigrateWaitProgress waits until migration job reaches the specified status, calling handler (if set) with the
+// status of the migration job on every check.
+func (m *Monitor) MigrateWaitProgress(state string, handler func(status MigrateStatus)) error {
 	// Wait until it completes or fails.
 	for {
 		// Prepare the response.
 		var resp struct {
-			Return struct {
-				Status string `json:"status"`
-			} `json:"return"`
+			Return MigrateStatus `json:"return"`
 		}
 
 		err := m.Run("query-migrate", nil, &resp)
@@ -422,6 +442,10 @@ func (m *Monitor) MigrateWait(state string) error {
 			return errors.New("Migrate call failed")
 		}
 
+		if handler != nil {
+			handler(resp.Return)
+		}
+
 		if resp.Return.Status == state {
 			return nil
 		}
@@ -446,6 +470,17 @@ func (m *Monitor) MigrateContinue(fromState string) error {
 	return nil
 }
 
+// MigrateStartPostcopy switches an active migration stream to post-copy mode.
+// Once switched, the guest resumes on the target and remaining memory pages are fetched on demand.
+func (m *Monitor) MigrateStartPostcopy() error {
+	err := m.Run("migrate-start-postcopy", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // MigrateIncoming starts the receiver of a migration stream.
 func (m *Monitor) MigrateIncoming(ctx context.Context, name string) error {
 	type migrateArgsChannel struct {
@@ -1209,6 +1244,62 @@ func (m *Monitor) Eject(id string) error {
 	return nil
 }
 
+// BlockInfo represents a block device as seen by a guest device.
+type BlockInfo struct {
+	QDev      string `json:"qdev"`
+	Removable bool   `json:"removable"`
+	TrayOpen  bool   `json:"tray_open"`
+	Inserted  *struct {
+		NodeName string `json:"node-name"`
+	} `json:"inserted"`
+}
+
+// QueryBlock returns info about the block devices attached to guest devices.
+func (m *Monitor) QueryBlock() ([]BlockInfo, error) {
+	var resp struct {
+		Return []BlockInfo `json:"return"`
+	}
+
+	err := m.Run("query-block", nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed querying block devices: %w", err)
+	}
+
+	return resp.Return, nil
+}
+
+// InsertMedium inserts the block node as the medium of a removable drive and closes its tray.
+func (m *Monitor) InsertMedium(id string, nodeName string) error {
+	var args struct {
+		ID       string `json:"id"`
+		NodeName string `json:"node-name,omitempty"`
+	}
+
+	args.ID = id
+
+	// The tray must be open for the medium to be inserted.
+	err := m.Run("blockdev-open-tray", args, nil)
+	if err != nil {
+		return err
+	}
+
+	args.NodeName = nodeName
+
+	err = m.Run("blockdev-insert-medium", args, nil)
+	if err != nil {
+		return err
+	}
+
+	args.NodeName = ""
+
+	err = m.Run("blockdev-close-tray", args, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateBlockSize updates the size of a disk.
 func (m *Monitor) UpdateBlockSize(id string) error {
 	var args struct {
@@ -1436,3 +1527,21 @@ func (m *Monitor) DumpGuestMemory(path string, format string) error {
 
 	return m.Run("dump-guest-memory", args, &queryResp)
 }
+
+// Passthrough runs one of the allowed passthrough commands and returns its result.
+func (m *Monitor) Passthrough(cmd string, args map[string]any) (any, error) {
+	if !slices.Contains(PassthroughCommands, cmd) {
+		return nil, api.StatusErrorf(http.StatusForbidden, "QMP command %q isn't allowed", cmd)
+	}
+
+	var queryResp struct {
+		Return any `json:"return"`
+	}
+
+	err := m.Run(cmd, args, &queryResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return queryResp.Return, nil
+}
@@ -40,6 +40,40 @@ var EventDiskEjected = "DEVICE_TRAY_MOVED"
 // ExcludedCommands is used to filter verbose commands from the QMP logs.
 var ExcludedCommands = []string{"ringbuf-read"}
 
+// PassthroughCommands is the list of commands which can be sent through the QMP passthrough API.
+// It's limited to commands which can't compromise the host or the state tracked by Incus.
+var PassthroughCommands = []string{
+	"inject-nmi",
+	"qom-get",
+	"qom-list",
+	"query-balloon",
+	"query-block",
+	"query-block-jobs",
+	"query-blockstats",
+	"query-chardev",
+	"query-commands",
+	"query-cpus-fast",
+	"query-hotpluggable-cpus",
+	"query-iothreads",
+	"query-kvm",
+	"query-memdev",
+	"query-memory-devices",
+	"query-memory-size-summary",
+	"query-migrate",
+	"query-migrate-capabilities",
+	"query-migrate-parameters",
+	"query-name",
+	"query-named-block-nodes",
+	"query-pci",
+	"query-rx-filter",
+	"query-status",
+	"query-stats",
+	"query-stats-schemas",
+	"query-version",
+	"send-key",
+	"system_wakeup",
+}
+
 // Monitor represents a QMP monitor.
 type Monitor struct {
 	path string
@@ -115,10 +149,11 @@ func (m *Monitor) start() error {
 			case <-m.chDisconnect:
 				return
 			case e, more := <-chEvents:
-				// Handle media ejection.
+				// Handle media ejection, the tray also moves when media gets inserted.
 				if e.Event == EventDiskEjected {
 					id, ok := e.Data["id"].(string)
-					if ok {
+					trayOpen, _ := e.Data["tray-open"].(bool)
+					if ok && trayOpen {
 						go func() {
 							err = m.Eject(id)
 							if err != nil {
@@ -103,7 +103,7 @@ type Instance interface {
 	Update(newConfig db.InstanceArgs, userRequested bool) error
 
 	Delete(force bool) error
-	Export(meta io.Writer, roofs io.Writer, properties map[string]string, expiration time.Time, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error)
+	Export(meta io.Writer, roofs io.Writer, properties map[string]string, expiration time.Time, provenance *api.ImageProvenance, sbom string, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error)
 
 	// Live configuration.
 	CGroup() (*cgroup.CGroup, error)
@@ -188,6 +188,8 @@ type Container interface {
 	InsertSeccompUnixDevice(prefix string, m deviceConfig.Device, pid int) error
 	DevptsFd() (*os.File, error)
 	IdmappedStorage(path string, fstype string) idmap.StorageType
+	DebugLXCConfig() (*api.InstanceDebugLXCConfig, error)
+	ExecNetns(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (Cmd, error)
 }
 
 // VM interface is for VM specific functions.
@@ -197,7 +199,13 @@ type VM interface {
 	AgentCertificate() *x509.Certificate
 	ConsoleLog() (string, error)
 	ConsoleScreenshot(screenshotFile *os.File) error
+	SerialConsole(name string) (*os.File, chan error, error)
+	SerialConsoleLog(name string) (string, error)
 	DumpGuestMemory(w *os.File, format string) error
+	QMPPassthrough(command string, args map[string]any) (any, error)
+	VCPUThreads() ([]int, error)
+	DeviceMediaInserted(devName string) (bool, error)
+	SetDeviceMediaInserted(devName string, inserted bool) error
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
@@ -233,6 +241,12 @@ type MigrateArgs struct {
 	Disconnect            func()
 	ClusterMoveSourceName string // Will be empty if not a cluster move, othwise indicates the source instance.
 	StoragePool           string
+	Verify                bool // Compare the checksums of the volumes once transferred.
+
+	// FilesystemStreams is the number of filesystem connections available, including FilesystemConn.
+	// FilesystemStreamConns returns the additional connections once the number to use has been negotiated.
+	FilesystemStreams     int
+	FilesystemStreamConns func(ctx context.Context, count int) ([]io.ReadWriteCloser, error)
 }
 
 // MigrateSendArgs represent arguments for instance migration send.
@@ -249,4 +263,5 @@ type MigrateReceiveArgs struct {
 	InstanceOperation   *operationlock.InstanceOperation
 	Refresh             bool
 	RefreshExcludeOlder bool
+	KeepPartial         bool // Keep the partially received volume and snapshots on failure so they can be refreshed.
 }
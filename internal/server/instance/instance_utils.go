@@ -179,7 +179,8 @@ func validConfigKey(os *sys.OS, key string, value string, instanceType instancet
 	return nil
 }
 
-func lxcParseRawLXC(line string) (string, string, error) {
+// ParseRawLXC parses a line of raw.lxc, returning an empty key for empty lines and comments.
+func ParseRawLXC(line string) (string, string, error) {
 	// Ignore empty lines
 	if len(line) == 0 {
 		return "", "", nil
@@ -206,7 +207,7 @@ func lxcParseRawLXC(line string) (string, string, error) {
 
 func lxcValidConfig(rawLxc string) error {
 	for _, line := range strings.Split(rawLxc, "\n") {
-		key, _, err := lxcParseRawLXC(line)
+		key, _, err := ParseRawLXC(line)
 		if err != nil {
 			return err
 		}
@@ -507,8 +508,27 @@ func BackupLoadByName(s *state.State, project, name string) (*backup.InstanceBac
 	return backup.NewInstanceBackup(s, instance, args.ID, name, args.CreationDate, args.ExpiryDate, args.InstanceOnly, args.OptimizedStorage), nil
 }
 
+// ImageAliasTarget returns the target of the alias for the first of the architectures that it has a variant for.
+// The default target of the alias is returned if none match.
+func ImageAliasTarget(alias api.ImageAliasesEntry, architectures []int) string {
+	for _, architecture := range architectures {
+		architectureName, err := osarch.ArchitectureName(architecture)
+		if err != nil {
+			continue
+		}
+
+		fingerprint, ok := alias.Architectures[architectureName]
+		if ok {
+			return fingerprint
+		}
+	}
+
+	return alias.Target
+}
+
 // ResolveImage takes an instance source and returns a hash suitable for instance creation or download.
-func ResolveImage(ctx context.Context, tx *db.ClusterTx, projectName string, source api.InstanceSource) (string, error) {
+// Aliases with architecture variants resolve to the variant of the first supported architecture.
+func ResolveImage(ctx context.Context, tx *db.ClusterTx, projectName string, source api.InstanceSource, architectures []int) (string, error) {
 	if source.Fingerprint != "" {
 		return source.Fingerprint, nil
 	}
@@ -523,7 +543,7 @@ func ResolveImage(ctx context.Context, tx *db.ClusterTx, projectName string, sou
 			return "", err
 		}
 
-		return alias.Target, nil
+		return ImageAliasTarget(alias, architectures), nil
 	}
 
 	if source.Properties != nil {
@@ -606,6 +626,30 @@ func SuitableArchitectures(ctx context.Context, s *state.State, tx *db.ClusterTx
 	if req.Source.Type == "image" {
 		// Handle local images.
 		if req.Source.Server == "" {
+			// Aliases with architecture variants are suitable for all their architectures.
+			if req.Source.Fingerprint == "" && req.Source.Alias != "" {
+				_, alias, err := tx.GetImageAlias(ctx, projectName, req.Source.Alias, true)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(alias.Architectures) > 0 {
+					architectures := make([]int, 0, len(alias.Architectures))
+					for architectureName := range alias.Architectures {
+						id, err := osarch.ArchitectureID(architectureName)
+						if err != nil {
+							return nil, err
+						}
+
+						architectures = append(architectures, id)
+					}
+
+					slices.Sort(architectures)
+
+					return architectures, nil
+				}
+			}
+
 			_, img, err := tx.GetImageByFingerprintPrefix(ctx, sourceImageRef, cluster.ImageFilter{Project: &projectName})
 			if err != nil {
 				return nil, err
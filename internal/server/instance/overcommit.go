@@ -0,0 +1,150 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	clusterConfig "github.com/lxc/incus/v6/internal/server/cluster/config"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// Allocation represents an amount of CPU threads and memory.
+type Allocation struct {
+	CPU    int64
+	Memory int64
+}
+
+// OvercommitPolicy holds the maximum ratios of committed to available resources.
+// A ratio of 0 means that the resource can be overcommitted without limit.
+type OvercommitPolicy struct {
+	CPU    float64
+	Memory float64
+}
+
+// GetOvercommitPolicy returns the overcommit policy applying to the instances of a project on a member.
+// The lowest ratio applies when both the member and the project set one.
+func GetOvercommitPolicy(memberConfig *clusterConfig.Config, projectConfig map[string]string) OvercommitPolicy {
+	lowestRatio := func(memberRatio float64, projectValue string) float64 {
+		projectRatio, err := strconv.ParseFloat(projectValue, 64)
+		if err != nil || projectRatio <= 0 {
+			return memberRatio
+		}
+
+		if memberRatio > 0 && memberRatio < projectRatio {
+			return memberRatio
+		}
+
+		return projectRatio
+	}
+
+	return OvercommitPolicy{
+		CPU:    lowestRatio(memberConfig.InstancesOvercommitCPU(), projectConfig["limits.overcommit.cpu"]),
+		Memory: lowestRatio(memberConfig.InstancesOvercommitMemory(), projectConfig["limits.overcommit.memory"]),
+	}
+}
+
+// Enabled returns whether the policy limits the overcommit of any resource.
+func (p OvercommitPolicy) Enabled() bool {
+	return p.CPU > 0 || p.Memory > 0
+}
+
+// Check returns an error if the requested resources can't be committed on top of the already committed ones
+// without exceeding the available resources multiplied by the policy ratios.
+func (p OvercommitPolicy) Check(available Allocation, committed Allocation, requested Allocation) error {
+	if p.CPU > 0 && requested.CPU > 0 {
+		maxCPU := int64(float64(available.CPU) * p.CPU)
+		if committed.CPU+requested.CPU > maxCPU {
+			return fmt.Errorf("Not enough CPU capacity under the overcommit ratio of %s (%d of %d CPU threads committed, %d requested)", strconv.FormatFloat(p.CPU, 'f', -1, 64), committed.CPU, maxCPU, requested.CPU)
+		}
+	}
+
+	if p.Memory > 0 && requested.Memory > 0 {
+		maxMemory := int64(float64(available.Memory) * p.Memory)
+		if committed.Memory+requested.Memory > maxMemory {
+			return fmt.Errorf("Not enough memory capacity under the overcommit ratio of %s (%s of %s committed, %s requested)", strconv.FormatFloat(p.Memory, 'f', -1, 64), units.GetByteSizeStringIEC(committed.Memory, 2), units.GetByteSizeStringIEC(maxMemory, 2), units.GetByteSizeStringIEC(requested.Memory, 2))
+		}
+	}
+
+	return nil
+}
+
+// AvailableAllocation returns the CPU threads and memory of a member which are available to instances,
+// leaving out the resources reserved for the host in the member configuration.
+func AvailableAllocation(totalCPU uint64, totalMemory uint64, memberConfig *clusterConfig.Config) Allocation {
+	available := Allocation{CPU: int64(totalCPU), Memory: int64(totalMemory)}
+
+	reservedCPU := memberConfig.InstancesReservedCPU()
+	if reservedCPU < available.CPU {
+		available.CPU -= reservedCPU
+	}
+
+	reservedMemory := memberConfig.InstancesReservedMemory()
+	if reservedMemory < available.Memory {
+		available.Memory -= reservedMemory
+	}
+
+	return available
+}
+
+// InstanceAllocation returns the CPU threads and memory committed to an instance while it's running.
+// Containers without limits don't commit any resources, virtual machines without limits use the default size.
+// Memory limits set as a percentage are relative to the total memory of the host.
+func InstanceAllocation(instType api.InstanceType, expandedConfig map[string]string, expandedDevices map[string]map[string]string, totalMemory uint64) (Allocation, error) {
+	limitsMemory := expandedConfig["limits.memory"]
+	if strings.HasSuffix(limitsMemory, "%") {
+		percent, err := strconv.ParseInt(strings.TrimSuffix(limitsMemory, "%"), 10, 64)
+		if err != nil {
+			return Allocation{}, fmt.Errorf("Failed parsing instance resources limits.memory: %w", err)
+		}
+
+		expandedConfig = maps.Clone(expandedConfig)
+		expandedConfig["limits.memory"] = strconv.FormatUint(totalMemory*uint64(percent)/100, 10)
+	}
+
+	cpu, memory, _, err := ResourceUsage(expandedConfig, expandedDevices, instType)
+	if err != nil {
+		return Allocation{}, err
+	}
+
+	return Allocation{CPU: cpu, Memory: memory}, nil
+}
+
+// CommittedAllocation returns the CPU threads and memory committed to the instances running on a member.
+// The instance in skipProject with name skipName is left out.
+func CommittedAllocation(ctx context.Context, tx *db.ClusterTx, memberName string, totalMemory uint64, skipProject string, skipName string) (Allocation, error) {
+	var committed Allocation
+
+	err := tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+		if inst.Project == skipProject && inst.Name == skipName {
+			return nil
+		}
+
+		if inst.Config["volatile.last_state.power"] != PowerStateRunning {
+			return nil
+		}
+
+		expandedConfig := db.ExpandInstanceConfig(inst.Config, inst.Profiles)
+		expandedDevices := db.ExpandInstanceDevices(inst.Devices, inst.Profiles).CloneNative()
+
+		allocation, err := InstanceAllocation(api.InstanceType(inst.Type.String()), expandedConfig, expandedDevices, totalMemory)
+		if err != nil {
+			return fmt.Errorf("Failed getting resources of instance %q in project %q: %w", inst.Name, inst.Project, err)
+		}
+
+		committed.CPU += allocation.CPU
+		committed.Memory += allocation.Memory
+
+		return nil
+	}, cluster.InstanceFilter{Node: &memberName})
+	if err != nil {
+		return Allocation{}, err
+	}
+
+	return committed, nil
+}
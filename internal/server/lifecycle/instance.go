@@ -29,6 +29,7 @@ const (
 	InstanceFileRetrieved    = InstanceAction(api.EventLifecycleInstanceFileRetrieved)
 	InstanceMigrated         = InstanceAction(api.EventLifecycleInstanceMigrated)
 	InstancePaused           = InstanceAction(api.EventLifecycleInstancePaused)
+	InstanceQMPCommand       = InstanceAction(api.EventLifecycleInstanceQMPCommand)
 	InstanceReady            = InstanceAction(api.EventLifecycleInstanceReady)
 	InstanceRenamed          = InstanceAction(api.EventLifecycleInstanceRenamed)
 	InstanceRestarted        = InstanceAction(api.EventLifecycleInstanceRestarted)
@@ -0,0 +1,27 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// InstanceAccessLinkAction represents a lifecycle event action for instance access links.
+type InstanceAccessLinkAction string
+
+// All supported lifecycle events for instance access links.
+const (
+	InstanceAccessLinkCreated = InstanceAccessLinkAction(api.EventLifecycleInstanceAccessLinkCreated)
+	InstanceAccessLinkDeleted = InstanceAccessLinkAction(api.EventLifecycleInstanceAccessLinkDeleted)
+)
+
+// Event creates the lifecycle event for an action on an instance access link.
+func (a InstanceAccessLinkAction) Event(projectName string, instanceName string, linkID string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "instances", instanceName, "access-links", linkID).Project(projectName)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -56,3 +57,39 @@ func TestMetricSet_FilterSamples(t *testing.T) {
 		require.Contains(t, hasKeys, "project")
 	}
 }
+
+func TestMetricSet_AddUsageRatios(t *testing.T) {
+	m := NewMetricSet(map[string]string{"project": "default", "name": "jammy"})
+	m.AddSamples(CPUs, Sample{Value: 4})
+	m.AddSamples(MemoryMemTotalBytes, Sample{Value: 1000})
+	m.AddSamples(MemoryMemAvailableBytes, Sample{Value: 220})
+	m.AddSamples(CPUSecondsTotal,
+		Sample{Value: 10, Labels: map[string]string{"mode": "user"}},
+		Sample{Value: 5, Labels: map[string]string{"mode": "system"}},
+		Sample{Value: 100, Labels: map[string]string{"mode": "idle"}})
+
+	// The first reading doesn't provide a CPU usage.
+	tracker := NewCPUUsageTracker()
+	now := time.Now()
+	require.Equal(t, float64(-1), tracker.Usage("default/jammy", m, now))
+
+	m.AddUsageRatios(-1)
+	require.Empty(t, m.set[CPUUsageRatio])
+	require.Equal(t, 0.78, m.set[MemoryUsageRatio][0].Value)
+
+	// Two CPUs were busy during the following 10 seconds.
+	n := NewMetricSet(nil)
+	n.AddSamples(CPUs, Sample{Value: 4})
+	n.AddSamples(CPUSecondsTotal, Sample{Value: 35, Labels: map[string]string{"mode": "user"}})
+	require.Equal(t, float64(2), tracker.Usage("default/jammy", n, now.Add(10*time.Second)))
+
+	n.AddUsageRatios(2)
+	require.Equal(t, 0.5, n.set[CPUUsageRatio][0].Value)
+	require.Empty(t, n.set[MemoryUsageRatio])
+
+	// Project ratios are only added for the set limits.
+	p := NewMetricSet(nil)
+	p.AddProjectUsageRatios("default", 2, 8, 780, 0)
+	require.Equal(t, []Sample{{Value: 0.25, Labels: map[string]string{"project": "default"}}}, p.set[ProjectCPUUsageRatio])
+	require.Empty(t, p.set[ProjectMemoryUsageRatio])
+}
@@ -21,6 +21,8 @@ const (
 	CPUSecondsTotal MetricType = iota
 	// CPUs represents the total number of effective CPUs.
 	CPUs
+	// CPUUsageRatio represents the CPU usage relative to the effective CPUs.
+	CPUUsageRatio
 	// DiskReadBytesTotal represents the read bytes for a disk.
 	DiskReadBytesTotal
 	// DiskReadsCompletedTotal represents the completed for a disk.
@@ -75,6 +77,8 @@ const (
 	MemoryWritebackBytes
 	// MemoryOOMKillsTotal represents the amount of oom kills.
 	MemoryOOMKillsTotal
+	// MemoryUsageRatio represents the memory usage relative to the total memory.
+	MemoryUsageRatio
 	// NetworkReceiveBytesTotal represents the amount of received bytes on a given interface.
 	NetworkReceiveBytesTotal
 	// NetworkReceiveDropTotal represents the amount of received dropped bytes on a given interface.
@@ -93,6 +97,10 @@ const (
 	NetworkTransmitPacketsTotal
 	// ProcsTotal represents the number of running processes.
 	ProcsTotal
+	// ProjectCPUUsageRatio represents the CPU usage of a project relative to its CPU limit.
+	ProjectCPUUsageRatio
+	// ProjectMemoryUsageRatio represents the memory usage of a project relative to its memory limit.
+	ProjectMemoryUsageRatio
 	// OperationsTotal represents the number of running operations.
 	OperationsTotal
 	// WarningsTotal represents the number of active warnings.
@@ -151,6 +159,7 @@ const (
 var MetricNames = map[MetricType]string{
 	CPUSecondsTotal:             "incus_cpu_seconds_total",
 	CPUs:                        "incus_cpu_effective_total",
+	CPUUsageRatio:               "incus_cpu_usage_ratio",
 	DiskReadBytesTotal:          "incus_disk_read_bytes_total",
 	DiskReadsCompletedTotal:     "incus_disk_reads_completed_total",
 	DiskWrittenBytesTotal:       "incus_disk_written_bytes_total",
@@ -201,6 +210,7 @@ var MetricNames = map[MetricType]string{
 	MemoryUnevictableBytes:      "incus_memory_Unevictable_bytes",
 	MemoryWritebackBytes:        "incus_memory_Writeback_bytes",
 	MemoryOOMKillsTotal:         "incus_memory_OOM_kills_total",
+	MemoryUsageRatio:            "incus_memory_usage_ratio",
 	NetworkReceiveBytesTotal:    "incus_network_receive_bytes_total",
 	NetworkReceiveDropTotal:     "incus_network_receive_drop_total",
 	NetworkReceiveErrsTotal:     "incus_network_receive_errs_total",
@@ -211,6 +221,8 @@ var MetricNames = map[MetricType]string{
 	NetworkTransmitPacketsTotal: "incus_network_transmit_packets_total",
 	OperationsTotal:             "incus_operations_total",
 	ProcsTotal:                  "incus_procs_total",
+	ProjectCPUUsageRatio:        "incus_project_cpu_usage_ratio",
+	ProjectMemoryUsageRatio:     "incus_project_memory_usage_ratio",
 	UptimeSeconds:               "incus_uptime_seconds",
 	WarningsTotal:               "incus_warnings_total",
 }
@@ -219,6 +231,7 @@ var MetricNames = map[MetricType]string{
 var MetricHeaders = map[MetricType]string{
 	CPUSecondsTotal:             "# HELP incus_cpu_seconds_total The total number of CPU time used in seconds.",
 	CPUs:                        "# HELP incus_cpu_effective_total The total number of effective CPUs.",
+	CPUUsageRatio:               "# HELP incus_cpu_usage_ratio The CPU usage relative to the number of effective CPUs.",
 	DiskReadBytesTotal:          "# HELP incus_disk_read_bytes_total The total number of bytes read.",
 	DiskReadsCompletedTotal:     "# HELP incus_disk_reads_completed_total The total number of completed reads.",
 	DiskWrittenBytesTotal:       "# HELP incus_disk_written_bytes_total The total number of bytes written.",
@@ -269,6 +282,7 @@ var MetricHeaders = map[MetricType]string{
 	MemoryUnevictableBytes:      "# HELP incus_memory_Unevictable_bytes The amount of unevictable memory.",
 	MemoryWritebackBytes:        "# HELP incus_memory_Writeback_bytes The amount of memory queued for syncing to disk.",
 	MemoryOOMKillsTotal:         "# HELP incus_memory_OOM_kills_total The number of out of memory kills.",
+	MemoryUsageRatio:            "# HELP incus_memory_usage_ratio The memory usage relative to the memory limit.",
 	NetworkReceiveBytesTotal:    "# HELP incus_network_receive_bytes_total The amount of received bytes on a given interface.",
 	NetworkReceiveDropTotal:     "# HELP incus_network_receive_drop_total The amount of received dropped bytes on a given interface.",
 	NetworkReceiveErrsTotal:     "# HELP incus_network_receive_errs_total The amount of received errors on a given interface.",
@@ -279,6 +293,8 @@ var MetricHeaders = map[MetricType]string{
 	NetworkTransmitPacketsTotal: "# HELP incus_network_transmit_packets_total The amount of transmitted packets on a given interface.",
 	OperationsTotal:             "# HELP incus_operations_total The number of running operations",
 	ProcsTotal:                  "# HELP incus_procs_total The number of running processes.",
+	ProjectCPUUsageRatio:        "# HELP incus_project_cpu_usage_ratio The CPU usage of the project relative to its CPU limit.",
+	ProjectMemoryUsageRatio:     "# HELP incus_project_memory_usage_ratio The memory usage of the project relative to its memory limit.",
 	UptimeSeconds:               "# HELP incus_uptime_seconds The daemon uptime in seconds.",
 	WarningsTotal:               "# HELP incus_warnings_total The number of active warnings.",
 }
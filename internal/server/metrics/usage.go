@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// cpuIdleModes lists the CPU modes which don't count as CPU usage.
+var cpuIdleModes = []string{"idle", "iowait", "steal"}
+
+// sum returns the sum of the values of all samples of the given type and whether there were any.
+func (m *MetricSet) sum(metricType MetricType) (float64, bool) {
+	samples := m.set[metricType]
+	if len(samples) == 0 {
+		return 0, false
+	}
+
+	total := 0.0
+	for _, sample := range samples {
+		total += sample.Value
+	}
+
+	return total, true
+}
+
+// CPUBusySeconds returns the CPU time in seconds spent outside of the idle modes and whether any was reported.
+func (m *MetricSet) CPUBusySeconds() (float64, bool) {
+	samples := m.set[CPUSecondsTotal]
+	if len(samples) == 0 {
+		return 0, false
+	}
+
+	total := 0.0
+	for _, sample := range samples {
+		idle := false
+		for _, mode := range cpuIdleModes {
+			if sample.Labels["mode"] == mode {
+				idle = true
+				break
+			}
+		}
+
+		if !idle {
+			total += sample.Value
+		}
+	}
+
+	return total, true
+}
+
+// MemoryUsage returns the memory in use and the total memory in bytes.
+// Both are zero if the MetricSet doesn't report the memory totals.
+func (m *MetricSet) MemoryUsage() (float64, float64) {
+	total, ok := m.sum(MemoryMemTotalBytes)
+	if !ok || total <= 0 {
+		return 0, 0
+	}
+
+	available, ok := m.sum(MemoryMemAvailableBytes)
+	if !ok {
+		return 0, 0
+	}
+
+	return total - available, total
+}
+
+// AddUsageRatios adds the CPU and memory usage of an instance relative to its limits.
+// The CPU usage is the number of CPUs used since the previous reading and is skipped if negative.
+func (m *MetricSet) AddUsageRatios(cpuUsage float64) {
+	cpus, ok := m.sum(CPUs)
+	if cpuUsage >= 0 && ok && cpus > 0 {
+		m.AddSamples(CPUUsageRatio, Sample{Value: cpuUsage / cpus})
+	}
+
+	used, total := m.MemoryUsage()
+	if total > 0 {
+		m.AddSamples(MemoryUsageRatio, Sample{Value: used / total})
+	}
+}
+
+// AddProjectUsageRatios adds the aggregate CPU and memory usage of a project relative to its limits.
+// Limits which are zero or less are skipped.
+func (m *MetricSet) AddProjectUsageRatios(project string, cpuUsage float64, cpuLimit int64, memoryUsage float64, memoryLimit int64) {
+	labels := map[string]string{"project": project}
+
+	if cpuLimit > 0 {
+		m.AddSamples(ProjectCPUUsageRatio, Sample{Value: cpuUsage / float64(cpuLimit), Labels: labels})
+	}
+
+	if memoryLimit > 0 {
+		m.AddSamples(ProjectMemoryUsageRatio, Sample{Value: memoryUsage / float64(memoryLimit), Labels: labels})
+	}
+}
+
+type cpuReading struct {
+	seconds float64
+	time    time.Time
+}
+
+// CPUUsageTracker computes the CPU usage of instances from the CPU time reported by successive readings.
+type CPUUsageTracker struct {
+	mu       sync.Mutex
+	readings map[string]cpuReading
+}
+
+// NewCPUUsageTracker returns a new CPUUsageTracker.
+func NewCPUUsageTracker() *CPUUsageTracker {
+	return &CPUUsageTracker{readings: map[string]cpuReading{}}
+}
+
+// Usage records the CPU time reported in the MetricSet under the given key and returns the number of CPUs
+// used since the previous reading. It returns -1 when there is no usable previous reading.
+func (t *CPUUsageTracker) Usage(key string, m *MetricSet, now time.Time) float64 {
+	seconds, ok := m.CPUBusySeconds()
+	if !ok {
+		return -1
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, ok := t.readings[key]
+	t.readings[key] = cpuReading{seconds: seconds, time: now}
+
+	// Skip the first reading as well as counter resets caused by instance restarts.
+	if !ok || !now.After(prev.time) || seconds < prev.seconds {
+		return -1
+	}
+
+	return (seconds - prev.seconds) / now.Sub(prev.time).Seconds()
+}
+
+// Prune removes the readings of the keys for which keep returns false.
+func (t *CPUUsageTracker) Prune(keep func(key string) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key := range t.readings {
+		if !keep(key) {
+			delete(t.readings, key)
+		}
+	}
+}
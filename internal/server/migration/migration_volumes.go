@@ -1,11 +1,14 @@
 package migration
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"slices"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/lxc/incus/v6/internal/migration"
 	backupConfig "github.com/lxc/incus/v6/internal/server/backup/config"
 	"github.com/lxc/incus/v6/internal/server/operations"
@@ -38,6 +41,13 @@ func (r *InfoResponse) Err() error {
 	return nil
 }
 
+// Checksums represents the checksums of a transferred volume, sent by the source when verification is enabled.
+type Checksums struct {
+	Filesystem string `json:"filesystem,omitempty" yaml:"filesystem,omitempty"` // Checksum of the filesystem content.
+	Block      string `json:"block,omitempty" yaml:"block,omitempty"`           // Checksum of the block device content.
+	BlockSize  int64  `json:"block_size,omitempty" yaml:"block_size,omitempty"` // Number of bytes of the block device covered.
+}
+
 // Type represents the migration transport type. It indicates the method by which the migration can
 // take place and what optional features are available.
 type Type struct {
@@ -58,10 +68,13 @@ type VolumeSourceArgs struct {
 	ContentType        string
 	AllowInconsistent  bool
 	Refresh            bool
+	RefreshBase        string // Snapshot held by both sides from which changes are sent as a diff on refresh.
 	Info               *Info
 	VolumeOnly         bool
 	ClusterMove        bool
 	StorageMove        bool
+	Streams            []io.ReadWriteCloser // Additional connections to spread non-optimized transfers over.
+	Verify             bool                 // Send the checksums of the volume once transferred.
 }
 
 // VolumeTargetArgs represents the arguments needed to setup a volume migration sink.
@@ -75,12 +88,204 @@ type VolumeTargetArgs struct {
 	TrackProgress         bool
 	Refresh               bool
 	RefreshExcludeOlder   bool
+	RefreshBase           string // Snapshot held by both sides from which changes are received as a diff on refresh.
 	Live                  bool
 	VolumeSize            int64
 	ContentType           string
 	VolumeOnly            bool
 	ClusterMoveSourceName string
 	StoragePool           string
+	Streams               []io.ReadWriteCloser // Additional connections to spread non-optimized transfers over.
+	Verify                bool                 // Compare the transferred volume with the checksums sent by the source.
+	KeepPartial           bool                 // Keep the partially received volume and snapshots on failure.
+}
+
+// NegotiateFilesystemStreams sets the number of filesystem connections to use in the response header.
+// This is the lowest of the number offered by the source and the number available locally.
+// Additional connections are only used for non-optimized transfers.
+func NegotiateFilesystemStreams(offerHeader *migration.MigrationHeader, respHeader *migration.MigrationHeader, available int) {
+	fsType := respHeader.GetFs()
+	if fsType != migration.MigrationFSType_RSYNC && fsType != migration.MigrationFSType_BLOCK_AND_RSYNC {
+		return
+	}
+
+	streams := min(offerHeader.GetFilesystemStreams(), uint32(available))
+	if streams > 1 {
+		respHeader.FilesystemStreams = &streams
+	}
+}
+
+// NegotiateCompression sets the compression to use on the data connections in the response header.
+// The compression requested by the source is used unless compression is disabled locally or the
+// algorithm isn't supported.
+func NegotiateCompression(offerHeader *migration.MigrationHeader, respHeader *migration.MigrationHeader, local string) {
+	localAlgorithm, _, err := migration.ParseCompression(local)
+	if err != nil || localAlgorithm == migration.CompressionNone {
+		return
+	}
+
+	algorithm, _, err := migration.ParseCompression(offerHeader.GetCompression())
+	if err != nil || algorithm == migration.CompressionNone {
+		return
+	}
+
+	compression := offerHeader.GetCompression()
+	respHeader.Compression = &compression
+}
+
+// CompressConn wraps a data connection with the compression agreed on in the migration header.
+func CompressConn(header *migration.MigrationHeader, conn io.ReadWriteCloser) (io.ReadWriteCloser, error) {
+	if header.GetCompression() == "" {
+		return conn, nil
+	}
+
+	compressedConn, err := migration.NewCompressedConn(conn, header.GetCompression())
+	if err != nil {
+		return nil, fmt.Errorf("Failed setting up migration compression: %w", err)
+	}
+
+	return compressedConn, nil
+}
+
+// CompressConns wraps each of the data connections with the compression agreed on in the migration header.
+func CompressConns(header *migration.MigrationHeader, conns []io.ReadWriteCloser) error {
+	for i, conn := range conns {
+		compressedConn, err := CompressConn(header, conn)
+		if err != nil {
+			return err
+		}
+
+		conns[i] = compressedConn
+	}
+
+	return nil
+}
+
+// OfferVerify sets whether the source requests the verification of the transferred volumes in the offer header.
+// The field is always set to let the target know that the source supports verification.
+func OfferVerify(offerHeader *migration.MigrationHeader, verify bool) {
+	offerHeader.Verify = proto.Bool(verify)
+}
+
+// AcceptVerify returns whether the transferred volumes are to be verified based on the response header.
+func AcceptVerify(respHeader *migration.MigrationHeader, verify bool) (bool, error) {
+	if verify && respHeader.Verify == nil {
+		return false, errors.New("Migration verification was requested but the target server doesn't support it")
+	}
+
+	return respHeader.GetVerify(), nil
+}
+
+// NegotiateVerify sets whether the transferred volumes are to be verified in the response header.
+// Verification is enabled if requested by either side.
+func NegotiateVerify(offerHeader *migration.MigrationHeader, respHeader *migration.MigrationHeader, verify bool) (bool, error) {
+	if offerHeader.Verify == nil {
+		if verify {
+			return false, errors.New("Migration verification was requested but the source server doesn't support it")
+		}
+
+		return false, nil
+	}
+
+	verify = verify || offerHeader.GetVerify()
+	respHeader.Verify = proto.Bool(verify)
+
+	return verify, nil
+}
+
+// OfferEncryption sets up the encryption of the data connections in the offer header, unless disabled by the mode.
+// It returns nil if the data connections aren't to be encrypted.
+func OfferEncryption(offerHeader *migration.MigrationHeader, mode string) (*migration.Encryption, error) {
+	if mode == "" || mode == migration.EncryptionNone {
+		return nil, nil
+	}
+
+	encryption, err := migration.NewEncryption(true)
+	if err != nil {
+		return nil, err
+	}
+
+	offerHeader.EncryptionKey = encryption.PublicKey()
+
+	return encryption, nil
+}
+
+// AcceptEncryption completes the key exchange with the key of the target from the response header.
+// It returns nil if the target doesn't support encryption and the mode doesn't require it.
+func AcceptEncryption(encryption *migration.Encryption, respHeader *migration.MigrationHeader, mode string) (*migration.Encryption, error) {
+	if encryption == nil {
+		return nil, nil
+	}
+
+	if len(respHeader.GetEncryptionKey()) == 0 {
+		if mode == migration.EncryptionRequired {
+			return nil, errors.New("Migration encryption is required but the target server doesn't support it")
+		}
+
+		return nil, nil
+	}
+
+	err := encryption.SetPeerKey(respHeader.GetEncryptionKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption, nil
+}
+
+// NegotiateEncryption sets up the encryption of the data connections in the response header.
+// Encryption offered by the source is always accepted, and required by the target if the mode says so.
+// It returns nil if the data connections aren't to be encrypted.
+func NegotiateEncryption(offerHeader *migration.MigrationHeader, respHeader *migration.MigrationHeader, mode string) (*migration.Encryption, error) {
+	if len(offerHeader.GetEncryptionKey()) == 0 {
+		if mode == migration.EncryptionRequired {
+			return nil, errors.New("Migration encryption is required but the source server didn't offer it")
+		}
+
+		return nil, nil
+	}
+
+	encryption, err := migration.NewEncryption(false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = encryption.SetPeerKey(offerHeader.GetEncryptionKey())
+	if err != nil {
+		return nil, err
+	}
+
+	respHeader.EncryptionKey = encryption.PublicKey()
+
+	return encryption, nil
+}
+
+// EncryptConn wraps the named data connection with the agreed on encryption, if any.
+func EncryptConn(encryption *migration.Encryption, conn io.ReadWriteCloser, name string) (io.ReadWriteCloser, error) {
+	if encryption == nil || conn == nil {
+		return conn, nil
+	}
+
+	encryptedConn, err := encryption.NewEncryptedConn(conn, name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed setting up migration encryption: %w", err)
+	}
+
+	return encryptedConn, nil
+}
+
+// EncryptConns wraps each of the additional filesystem connections with the agreed on encryption, if any.
+func EncryptConns(encryption *migration.Encryption, conns []io.ReadWriteCloser) error {
+	for i, conn := range conns {
+		encryptedConn, err := EncryptConn(encryption, conn, api.SecretNameFilesystemStream(i+1))
+		if err != nil {
+			return err
+		}
+
+		conns[i] = encryptedConn
+	}
+
+	return nil
 }
 
 // TypesToHeader converts one or more Types to a MigrationHeader. It uses the first type argument
@@ -98,7 +303,8 @@ func TypesToHeader(types ...Type) *migration.MigrationHeader {
 		preferredType = types[0]
 	}
 
-	header := migration.MigrationHeader{Fs: &preferredType.FSType}
+	// Snapshot diffs from a common base snapshot can always be sent on refresh, the target decides whether to use them.
+	header := migration.MigrationHeader{Fs: &preferredType.FSType, SnapshotDiff: &hasFeature}
 
 	// Add ZFS features if preferred type is ZFS.
 	if preferredType.FSType == migration.MigrationFSType_ZFS {
@@ -113,6 +319,8 @@ func TypesToHeader(types ...Type) *migration.MigrationHeader {
 				features.MigrationHeader = &hasFeature
 			} else if feature == migration.ZFSFeatureZvolFilesystems {
 				features.HeaderZvols = &hasFeature
+			} else if feature == migration.ZFSFeatureResumeTokens {
+				features.ResumeTokens = &hasFeature
 			}
 		}
 
@@ -161,6 +369,8 @@ func TypesToHeader(types ...Type) *migration.MigrationHeader {
 				features.Compress = &hasFeature
 			} else if feature == "bidirectional" {
 				features.Bidirectional = &hasFeature
+			} else if feature == migration.RsyncFeatureSparseBlocks {
+				features.SparseBlocks = &hasFeature
 			}
 		}
 
@@ -199,6 +409,9 @@ func MatchTypes(offer *migration.MigrationHeader, fallbackType migration.Migrati
 				offeredFeatures = offer.GetBtrfsFeaturesSlice()
 			} else if offerFSType == migration.MigrationFSType_RSYNC {
 				offeredFeatures = offer.GetRsyncFeaturesSlice()
+			} else if offerFSType == migration.MigrationFSType_BLOCK_AND_RSYNC && slices.Contains(offer.GetRsyncFeaturesSlice(), migration.RsyncFeatureSparseBlocks) {
+				// Only the block transfer framing is negotiated, the filesystem part keeps the default rsync options.
+				offeredFeatures = []string{migration.RsyncFeatureSparseBlocks}
 			}
 
 			// Find common features in both our type and offered type.
@@ -219,6 +432,11 @@ func MatchTypes(offer *migration.MigrationHeader, fallbackType migration.Migrati
 				if ourType.FSType == migration.MigrationFSType_BTRFS && !slices.Contains(commonFeatures, migration.BTRFSFeatureSubvolumeUUIDs) {
 					continue
 				}
+
+				// Optimized refresh with rbd only works if the source can send snapshot diffs and a common base snapshot exists.
+				if ourType.FSType == migration.MigrationFSType_RBD && (!offer.GetSnapshotDiff() || offer.GetRefreshBase() == "") {
+					continue
+				}
 			}
 
 			// Append type with combined features.
@@ -262,6 +480,8 @@ func progressWrapperRender(op *operations.Operation, key string, description str
 		meta[key] = progress
 		_ = op.UpdateMetadata(meta)
 	}
+
+	updateVolumeProgress(op, description, progressInt, speedInt)
 }
 
 // ProgressReader reports the read progress.
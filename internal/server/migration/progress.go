@@ -0,0 +1,94 @@
+package migration
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// ProgressMetadataKey is the operation metadata key holding the structured migration progress.
+const ProgressMetadataKey = "migration"
+
+// progressLock serializes the updates of the structured progress as multiple transfers may run at once.
+var progressLock sync.Mutex
+
+// UpdateProgress applies the provided change to the structured migration progress of the operation.
+func UpdateProgress(op *operations.Operation, update func(progress *api.InstanceMigrationProgress)) {
+	if op == nil {
+		return
+	}
+
+	progressLock.Lock()
+	defer progressLock.Unlock()
+
+	meta := maps.Clone(op.Metadata())
+	if meta == nil {
+		meta = make(map[string]any)
+	}
+
+	progress, _ := meta[ProgressMetadataKey].(api.InstanceMigrationProgress)
+	progress.Volumes = maps.Clone(progress.Volumes)
+	if progress.Memory != nil {
+		memory := *progress.Memory
+		progress.Memory = &memory
+	}
+
+	update(&progress)
+
+	meta[ProgressMetadataKey] = progress
+	_ = op.UpdateMetadata(meta)
+}
+
+// SetProgressPhase records the current phase of the migration.
+func SetProgressPhase(op *operations.Operation, phase string) {
+	if phase == api.InstanceMigrationPhaseFinal {
+		markStatisticsPaused(op)
+	}
+
+	UpdateProgress(op, func(progress *api.InstanceMigrationProgress) {
+		progress.Phase = phase
+	})
+}
+
+// updateVolumeProgress records the transfer progress of a volume.
+func updateVolumeProgress(op *operations.Operation, volName string, bytes int64, speed int64) {
+	if volName == "" {
+		return
+	}
+
+	UpdateProgress(op, func(progress *api.InstanceMigrationProgress) {
+		if progress.Phase == "" {
+			progress.Phase = api.InstanceMigrationPhaseVolumes
+		}
+
+		if progress.Volumes == nil {
+			progress.Volumes = map[string]api.InstanceMigrationVolumeProgress{}
+		}
+
+		volume := progress.Volumes[volName]
+		volume.Bytes = bytes
+		volume.Speed = speed
+		progress.Volumes[volName] = volume
+	})
+}
+
+// FileTracker returns a function recording the number of files transferred for a volume.
+func FileTracker(op *operations.Operation, volName string) func(files int64) {
+	if op == nil {
+		return nil
+	}
+
+	return func(files int64) {
+		UpdateProgress(op, func(progress *api.InstanceMigrationProgress) {
+			if progress.Volumes == nil {
+				progress.Volumes = map[string]api.InstanceMigrationVolumeProgress{}
+			}
+
+			volume := progress.Volumes[volName]
+			volume.Files = files
+			progress.Volumes[volName] = volume
+		})
+	}
+}
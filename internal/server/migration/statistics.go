@@ -0,0 +1,174 @@
+package migration
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// statisticsRetention is how long the statistics of finished migrations are kept.
+const statisticsRetention = 24 * time.Hour
+
+// statisticsEntry holds the statistics of a migration and the internal state needed to compute them.
+type statisticsEntry struct {
+	stats   api.MigrationStatistics
+	pausing time.Time
+}
+
+var (
+	statisticsLock    sync.Mutex
+	statisticsEntries []*statisticsEntry
+)
+
+// pruneStatistics removes the statistics of the migrations which ended before the retention period.
+// Must be called with the lock held.
+func pruneStatistics() {
+	cutoff := time.Now().Add(-statisticsRetention)
+
+	statisticsEntries = slices.DeleteFunc(statisticsEntries, func(entry *statisticsEntry) bool {
+		return entry.stats.Status != api.MigrationStatisticsStatusRunning && entry.stats.FinishedAt.Before(cutoff)
+	})
+}
+
+// statisticsEntryFor returns the statistics of the migration of the operation.
+// Must be called with the lock held.
+func statisticsEntryFor(op *operations.Operation) *statisticsEntry {
+	if op == nil {
+		return nil
+	}
+
+	for _, entry := range statisticsEntries {
+		if entry.stats.Operation == op.ID() {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// StartStatistics starts recording the statistics of the migration sent by the operation.
+func StartStatistics(op *operations.Operation, projectName string, instanceName string, instanceType string, location string, live bool) {
+	if op == nil {
+		return
+	}
+
+	statisticsLock.Lock()
+	defer statisticsLock.Unlock()
+
+	pruneStatistics()
+
+	// Count the failed attempts which directly preceded this one.
+	retries := 0
+	for _, entry := range slices.Backward(statisticsEntries) {
+		if entry.stats.Project != projectName || entry.stats.Instance != instanceName {
+			continue
+		}
+
+		if entry.stats.Status != api.MigrationStatisticsStatusFailure {
+			break
+		}
+
+		retries++
+	}
+
+	statisticsEntries = append(statisticsEntries, &statisticsEntry{stats: api.MigrationStatistics{
+		Operation:    op.ID(),
+		Project:      projectName,
+		Instance:     instanceName,
+		InstanceType: instanceType,
+		Location:     location,
+		Live:         live,
+		Status:       api.MigrationStatisticsStatusRunning,
+		StartedAt:    time.Now(),
+		Retries:      retries,
+	}})
+}
+
+// SetStatisticsTransferType records the storage transfer type negotiated for the migration.
+func SetStatisticsTransferType(op *operations.Operation, fsType migration.MigrationFSType) {
+	statisticsLock.Lock()
+	defer statisticsLock.Unlock()
+
+	entry := statisticsEntryFor(op)
+	if entry == nil || entry.stats.TransferType != "" {
+		return
+	}
+
+	entry.stats.TransferType = strings.ToLower(fsType.String())
+}
+
+// FinishStatistics records the end of the migration sent by the operation.
+func FinishStatistics(op *operations.Operation, err error) {
+	statisticsLock.Lock()
+	defer statisticsLock.Unlock()
+
+	entry := statisticsEntryFor(op)
+	if entry == nil {
+		return
+	}
+
+	now := time.Now()
+	entry.stats.FinishedAt = now
+	entry.stats.Duration = now.Sub(entry.stats.StartedAt).Milliseconds()
+
+	if entry.stats.Live && !entry.pausing.IsZero() {
+		entry.stats.Downtime = now.Sub(entry.pausing).Milliseconds()
+	}
+
+	// Sum up the data transferred from the structured progress.
+	progress, _ := op.Metadata()[ProgressMetadataKey].(api.InstanceMigrationProgress)
+	for _, volume := range progress.Volumes {
+		entry.stats.Bytes += volume.Bytes
+	}
+
+	if progress.Memory != nil {
+		entry.stats.Bytes += progress.Memory.Transferred
+	}
+
+	if entry.stats.Duration > 0 {
+		entry.stats.Throughput = entry.stats.Bytes * 1000 / entry.stats.Duration
+	}
+
+	if errors.Is(err, ErrAborted) {
+		entry.stats.Status = api.MigrationStatisticsStatusCancelled
+	} else if err != nil {
+		entry.stats.Status = api.MigrationStatisticsStatusFailure
+		entry.stats.Error = err.Error()
+	} else {
+		entry.stats.Status = api.MigrationStatisticsStatusSuccess
+	}
+}
+
+// markStatisticsPaused records the time at which the instance got paused for the final phase of a live migration.
+func markStatisticsPaused(op *operations.Operation) {
+	statisticsLock.Lock()
+	defer statisticsLock.Unlock()
+
+	entry := statisticsEntryFor(op)
+	if entry == nil || !entry.pausing.IsZero() {
+		return
+	}
+
+	entry.pausing = time.Now()
+}
+
+// Statistics returns the statistics of the running and recently finished migrations, oldest first.
+func Statistics() []api.MigrationStatistics {
+	statisticsLock.Lock()
+	defer statisticsLock.Unlock()
+
+	pruneStatistics()
+
+	stats := make([]api.MigrationStatistics, 0, len(statisticsEntries))
+	for _, entry := range statisticsEntries {
+		stats = append(stats, entry.stats)
+	}
+
+	return stats
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/lxc/incus/v6/internal/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
 )
 
 // IndexHeaderVersion version of the index header to be sent/recv.
@@ -20,6 +21,9 @@ const (
 	toMigrateLive       = "To migrate the container, stop the container before migration or install CRIU"
 )
 
+// ErrAborted is returned on both sides of a migration which got cancelled by the user.
+var ErrAborted = fmt.Errorf("Migration aborted: %w", operations.ErrCancelled)
+
 var (
 	ErrNoLiveMigrationSource = fmt.Errorf("%s CRIU isn't installed on the source server. %s on the source server", unableToLiveMigrate, toMigrateLive)
 	ErrNoLiveMigrationTarget = fmt.Errorf("%s CRIU isn't installed on the target server. %s on the target server", unableToLiveMigrate, toMigrateLive)
@@ -66,6 +66,16 @@ func (c *Config) ClusterAddress() string {
 	return clusterAddress
 }
 
+// MigrationAddress returns the address and port to use for the migration traffic between cluster members.
+func (c *Config) MigrationAddress() string {
+	migrationAddress := c.m.GetString("cluster.migration_address")
+	if migrationAddress != "" {
+		return internalUtil.CanonicalNetworkAddress(migrationAddress, ports.HTTPSDefaultPort)
+	}
+
+	return migrationAddress
+}
+
 // DebugAddress returns the address and port to setup the pprof listener on.
 func (c *Config) DebugAddress() string {
 	debugAddress := c.m.GetString("core.debug_address")
@@ -116,6 +126,11 @@ func (c *Config) StorageImagesVolume() string {
 	return c.m.GetString("storage.images_volume")
 }
 
+// StorageEncryptionKeyHook returns the path of the executable managing the keys of encrypted storage volumes.
+func (c *Config) StorageEncryptionKeyHook() string {
+	return c.m.GetString("storage.encryption.key_hook")
+}
+
 // LinstorSatelliteName returns the LINSTOR satellite name override.
 func (c *Config) LinstorSatelliteName() string {
 	return c.m.GetString("storage.linstor.satellite.name")
@@ -181,6 +196,14 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Address to use for clustering traffic
 	"cluster.https_address": {Validator: validate.Optional(validate.IsListenAddress(true, false, false))},
 
+	// gendoc:generate(entity=server, group=cluster, key=cluster.migration_address)
+	// See {ref}`cluster-migration-address`.
+	// ---
+	//  type: string
+	//  scope: local
+	//  shortdesc: Address to use for migration traffic between cluster members
+	"cluster.migration_address": {Validator: validate.Optional(validate.IsListenAddress(true, false, false))},
+
 	// Network address for the BGP server
 
 	// gendoc:generate(entity=server, group=core, key=core.bgp_address)
@@ -279,6 +302,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Volume to use to store the image tarballs
 	"storage.images_volume": {},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=storage.encryption.key_hook)
+	// The executable is called with the action (`get`, `create` or `delete`) and the key reference of an encrypted storage volume.
+	// See {ref}`storage-encryption` for more information.
+	// ---
+	//  type: string
+	//  scope: local
+	//  shortdesc: Absolute path of the executable that manages the keys of encrypted storage volumes
+	"storage.encryption.key_hook": {Validator: validate.Optional(validate.IsAbsFilePath)},
+
 	// LINSTOR
 
 	// gendoc:generate(entity=server, group=miscellaneous, key=storage.linstor.satellite.name)
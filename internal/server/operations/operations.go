@@ -26,6 +26,10 @@ import (
 
 var debug bool
 
+// ErrCancelled can be wrapped by the error returned by the run hook of an operation to report it as cancelled
+// instead of failed, for example when the cancellation was requested through another server.
+var ErrCancelled = errors.New("Operation cancelled")
+
 var (
 	operationsLock sync.Mutex
 	operations     = make(map[string]*Operation)
@@ -290,7 +294,12 @@ func (op *Operation) Start() error {
 			err := op.onRun(op)
 			if err != nil {
 				op.lock.Lock()
-				op.status = api.Failure
+				if op.status == api.Cancelling || op.status == api.Cancelled || errors.Is(err, ErrCancelled) {
+					op.status = api.Cancelled
+				} else {
+					op.status = api.Failure
+				}
+
 				op.err = err
 				op.lock.Unlock()
 				op.done()
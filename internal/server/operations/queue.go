@@ -0,0 +1,132 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// QueueMetadataKey is the operation metadata key reporting that the operation is waiting in a queue.
+const QueueMetadataKey = "queue"
+
+// Queue limits the number of operations doing a given kind of work at the same time.
+// Operations waiting for a slot are let through in the order they started waiting.
+type Queue struct {
+	lock    sync.Mutex
+	running int
+	waiting []*Operation
+	changed chan struct{}
+}
+
+// NewQueue returns a new empty queue.
+func NewQueue() *Queue {
+	return &Queue{changed: make(chan struct{})}
+}
+
+// notify wakes up the waiting operations so they check the queue again.
+// Must be called with the lock held.
+func (q *Queue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// release frees a slot.
+func (q *Queue) release() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.running--
+	q.notify()
+}
+
+// remove takes the operation out of the waiting list.
+// Must be called with the lock held.
+func (q *Queue) remove(op *Operation) {
+	q.waiting = slices.DeleteFunc(q.waiting, func(entry *Operation) bool { return entry == op })
+	q.notify()
+}
+
+// Wait waits until fewer than limit operations hold a slot of the queue and then takes one.
+// A limit of zero means that there is no limit.
+// While the operation waits, its position in the queue is reported in its metadata.
+// The returned function frees the slot and must be called once the work is done.
+func (q *Queue) Wait(op *Operation, limit int) (func(), error) {
+	q.lock.Lock()
+
+	if limit <= 0 {
+		q.running++
+		q.lock.Unlock()
+
+		return sync.OnceFunc(q.release), nil
+	}
+
+	shutdown := context.Background()
+	if op.state != nil {
+		shutdown = op.state.ShutdownCtx
+	}
+
+	q.waiting = append(q.waiting, op)
+	reported := 0
+
+	for {
+		position := slices.Index(q.waiting, op) + 1
+		if position == 1 && q.running < limit {
+			q.remove(op)
+			q.running++
+			q.lock.Unlock()
+
+			if reported > 0 {
+				op.setQueueMetadata(nil)
+			}
+
+			return sync.OnceFunc(q.release), nil
+		}
+
+		changed := q.changed
+		q.lock.Unlock()
+
+		if position != reported {
+			op.setQueueMetadata(map[string]any{"status": "waiting", "position": position})
+			reported = position
+		}
+
+		select {
+		case <-changed:
+		case <-op.finished.Done():
+			q.lock.Lock()
+			q.remove(op)
+			q.lock.Unlock()
+
+			return nil, errors.New("Operation ended while waiting in queue")
+		case <-shutdown.Done():
+			q.lock.Lock()
+			q.remove(op)
+			q.lock.Unlock()
+
+			return nil, errors.New("Incus is shutting down")
+		}
+
+		q.lock.Lock()
+	}
+}
+
+// setQueueMetadata records the queue status of the operation, removing it if nil.
+func (op *Operation) setQueueMetadata(status map[string]any) {
+	op.lock.Lock()
+	metadata := maps.Clone(op.metadata)
+	op.lock.Unlock()
+
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+
+	if status == nil {
+		delete(metadata, QueueMetadataKey)
+	} else {
+		metadata[QueueMetadataKey] = status
+	}
+
+	_ = op.UpdateMetadata(metadata)
+}
@@ -211,6 +211,10 @@ func checkRestrictionsOnVolatileConfig(project api.Project, instanceType instanc
 			return true
 		}
 
+		if strings.HasPrefix(key, "volatile.base_image.") {
+			return true
+		}
+
 		if strings.HasPrefix(key, instance.ConfigVolatilePrefix) {
 			if strings.HasSuffix(key, ".apply_quota") {
 				return true
@@ -303,9 +307,27 @@ func GetImageSpaceBudget(tx *db.ClusterTx, projectName string) (int64, error) {
 		return -1, nil
 	}
 
-	// If "limits.disk" is not set, the budget is unlimited.
+	budget := int64(-1)
+
+	// If "limits.disk.images" is set, the images can only use what's left of it.
+	if info.Project.Config["limits.disk.images"] != "" {
+		parser := aggregateLimitConfigValueParsers["limits.disk.images"]
+		quota, err := parser(info.Project.Config["limits.disk.images"])
+		if err != nil {
+			return -1, err
+		}
+
+		usage, err := getImagesUsage(tx, projectName)
+		if err != nil {
+			return -1, err
+		}
+
+		budget = max(quota-usage, 0)
+	}
+
+	// If "limits.disk" is not set, the budget is only bound by "limits.disk.images".
 	if info.Project.Config["limits.disk"] == "" {
-		return -1, nil
+		return budget, nil
 	}
 
 	parser := aggregateLimitConfigValueParsers["limits.disk"]
@@ -326,11 +348,51 @@ func GetImageSpaceBudget(tx *db.ClusterTx, projectName string) (int64, error) {
 		return -1, err
 	}
 
-	if totals["limits.disk"] < quota {
-		return quota - totals["limits.disk"], nil
+	diskBudget := max(quota-totals["limits.disk"], 0)
+	if budget < 0 || diskBudget < budget {
+		budget = diskBudget
+	}
+
+	return budget, nil
+}
+
+// getImagesUsage returns the disk space used by the images of the given project.
+func getImagesUsage(tx *db.ClusterTx, projectName string) (int64, error) {
+	images, err := cluster.GetImages(context.Background(), tx.Tx(), cluster.ImageFilter{Project: &projectName})
+	if err != nil {
+		return -1, fmt.Errorf("Fetch project images from database: %w", err)
+	}
+
+	var usage int64
+	for _, image := range images {
+		usage += image.Size
+	}
+
+	return usage, nil
+}
+
+// GetBackupSpaceLimit returns the maximum disk space that the backups of the given
+// project can use on a server.
+//
+// If no limit is in place, return -1.
+func GetBackupSpaceLimit(tx *db.ClusterTx, projectName string) (int64, error) {
+	ctx := context.Background()
+	dbProject, err := cluster.GetProject(ctx, tx.Tx(), projectName)
+	if err != nil {
+		return -1, err
+	}
+
+	project, err := dbProject.ToAPI(ctx, tx.Tx())
+	if err != nil {
+		return -1, err
+	}
+
+	if project.Config["limits.disk.backups"] == "" {
+		return -1, nil
 	}
 
-	return 0, nil
+	parser := aggregateLimitConfigValueParsers["limits.disk.backups"]
+	return parser(project.Config["limits.disk.backups"])
 }
 
 // Check that we would not violate the project limits or restrictions if we
@@ -606,6 +668,15 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 				return nil
 			}
 
+		case "restricted.devices.shmem":
+			devicesChecks["shmem"] = func(device map[string]string) error {
+				if restrictionValue != "allow" {
+					return errors.New("Shared memory devices are forbidden")
+				}
+
+				return nil
+			}
+
 		case "restricted.devices.proxy":
 			devicesChecks["proxy"] = func(device map[string]string) error {
 				if restrictionValue != "allow" {
@@ -851,6 +922,7 @@ var allRestrictions = map[string]string{
 	"restricted.devices.usb":               "block",
 	"restricted.devices.pci":               "block",
 	"restricted.devices.proxy":             "block",
+	"restricted.devices.shmem":             "allow",
 	"restricted.devices.nic":               "managed",
 	"restricted.devices.disk":              "managed",
 	"restricted.devices.disk.paths":        "",
@@ -1081,6 +1153,17 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 			fallthrough
 		case "limits.disk":
 			aggregateKeys = append(aggregateKeys, key)
+
+		case "limits.disk.images":
+			usage, err := getImagesUsage(tx, projectName)
+			if err != nil {
+				return err
+			}
+
+			err = validateAggregateLimit(map[string]int64{key: usage}, key, config[key])
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -1519,6 +1602,12 @@ var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.disk": func(value string) (int64, error) {
 		return units.ParseByteSizeString(value)
 	},
+	"limits.disk.images": func(value string) (int64, error) {
+		return units.ParseByteSizeString(value)
+	},
+	"limits.disk.backups": func(value string) (int64, error) {
+		return units.ParseByteSizeString(value)
+	},
 }
 
 var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
@@ -1534,6 +1623,12 @@ var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
 	"limits.disk": func(limit int64) string {
 		return units.GetByteSizeStringIEC(limit, 1)
 	},
+	"limits.disk.images": func(limit int64) string {
+		return units.GetByteSizeStringIEC(limit, 1)
+	},
+	"limits.disk.backups": func(limit int64) string {
+		return units.GetByteSizeStringIEC(limit, 1)
+	},
 }
 
 // FilterUsedBy filters a UsedBy list based on project access.
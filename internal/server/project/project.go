@@ -3,6 +3,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"path"
 	"slices"
 	"strings"
 
@@ -18,6 +19,9 @@ const separator = "_"
 // projectLimitDiskPool is the prefix used for pool-specific disk limits.
 var projectLimitDiskPool = "limits.disk.pool."
 
+// projectImageNamespace is the prefix used for the image alias namespaces.
+var projectImageNamespace = "images.namespace."
+
 // Instance adds the "<project>_" prefix to instance name when the given project name is not "default".
 func Instance(projectName string, instanceName string) string {
 	if projectName != api.ProjectDefaultName {
@@ -261,6 +265,90 @@ func ImageProjectFromRecord(p *api.Project) string {
 	return api.ProjectDefaultName
 }
 
+// ImageSourceRedirect applies the image alias namespaces of the project to an instance image source.
+// Aliases of the form "<namespace>/<name>" which would be resolved locally are redirected to the fingerprint
+// pinned for the name by "images.namespace.<namespace>.pins" and/or to the image server set by
+// "images.namespace.<namespace>.server". Sources which don't match any namespace are returned unchanged.
+func ImageSourceRedirect(p *api.Project, source api.InstanceSource) api.InstanceSource {
+	if source.Type != "image" || source.Server != "" || source.Alias == "" || source.Fingerprint != "" {
+		return source
+	}
+
+	namespace, name, found := strings.Cut(source.Alias, "/")
+	if !found {
+		return source
+	}
+
+	prefix := fmt.Sprintf("%s%s.", projectImageNamespace, namespace)
+
+	// Pin the image to a specific fingerprint.
+	for _, pin := range util.SplitNTrimSpace(p.Config[prefix+"pins"], ",", -1, true) {
+		pattern, fingerprint, found := strings.Cut(pin, "=")
+		if !found {
+			continue
+		}
+
+		match, _ := path.Match(pattern, name)
+		if match {
+			source.Fingerprint = fingerprint
+			source.Alias = ""
+			break
+		}
+	}
+
+	// Resolve the image against another image server.
+	server := p.Config[prefix+"server"]
+	if server != "" {
+		source.Server = server
+		source.Protocol = p.Config[prefix+"protocol"]
+		source.Mode = "pull"
+
+		if source.Protocol == "" {
+			source.Protocol = "simplestreams"
+		}
+	}
+
+	return source
+}
+
+// ImageNamespaceKey splits a project configuration key for image alias namespaces into its namespace and option.
+func ImageNamespaceKey(key string) (string, string, bool) {
+	after, ok := strings.CutPrefix(key, projectImageNamespace)
+	if !ok {
+		return "", "", false
+	}
+
+	namespace, option, found := strings.Cut(after, ".")
+	if !found || namespace == "" || strings.Contains(option, ".") {
+		return "", "", false
+	}
+
+	return namespace, option, true
+}
+
+// instanceMemberSeparator delimits the instance name from the cluster member name in member-scoped names.
+const instanceMemberSeparator = "--"
+
+// InstanceMemberName returns the name to use for a new instance placed on the given cluster member.
+// When the project has "instances.names.scope" set to "member", the name is qualified as "<name>--<member>" so
+// that the same name can be used on each member while remaining unique across the cluster, both in API paths and
+// in DNS. Names which are already qualified for that member are returned unchanged.
+func InstanceMemberName(p *api.Project, instanceName string, memberName string) (string, error) {
+	if p.Config["instances.names.scope"] != "member" || memberName == "" {
+		return instanceName, nil
+	}
+
+	if strings.HasSuffix(instanceName, instanceMemberSeparator+memberName) {
+		return instanceName, nil
+	}
+
+	if strings.Contains(instanceName, instanceMemberSeparator) {
+		return "", fmt.Errorf("Instance names cannot contain %q in projects with member-scoped names", instanceMemberSeparator)
+	}
+
+	return instanceName + instanceMemberSeparator + memberName, nil
+}
+
 // ProfileProject returns the effective project to use for the profile based on the requested project.
 // If the requested project has the "features.profiles" flag enabled then the requested project's info is returned,
 // otherwise the default project's info is returned.
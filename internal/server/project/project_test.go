@@ -45,3 +45,50 @@ func ExampleStorageVolume() {
 	// Output: default_test
 	// project_name_test1
 }
+
+func ExampleImageSourceRedirect() {
+	p := &api.Project{
+		Name: "tenant",
+		ProjectPut: api.ProjectPut{
+			Config: map[string]string{
+				"images.namespace.ubuntu.server": "https://images.example.com",
+				"images.namespace.ubuntu.pins":   "20.04=abcdef, 22.*=012345",
+				"images.namespace.pinned.pins":   "*=fedcba",
+			},
+		},
+	}
+
+	for _, alias := range []string{"ubuntu/24.04", "ubuntu/22.04", "ubuntu/22.04/cloud", "pinned/stable", "debian/12", "ubuntu"} {
+		source := project.ImageSourceRedirect(p, api.InstanceSource{Type: "image", Alias: alias})
+		fmt.Printf("%s: alias=%q fingerprint=%q server=%q protocol=%q\n", alias, source.Alias, source.Fingerprint, source.Server, source.Protocol)
+	}
+
+	// Output: ubuntu/24.04: alias="ubuntu/24.04" fingerprint="" server="https://images.example.com" protocol="simplestreams"
+	// ubuntu/22.04: alias="" fingerprint="012345" server="https://images.example.com" protocol="simplestreams"
+	// ubuntu/22.04/cloud: alias="ubuntu/22.04/cloud" fingerprint="" server="https://images.example.com" protocol="simplestreams"
+	// pinned/stable: alias="" fingerprint="fedcba" server="" protocol=""
+	// debian/12: alias="debian/12" fingerprint="" server="" protocol=""
+	// ubuntu: alias="ubuntu" fingerprint="" server="" protocol=""
+}
+
+func ExampleInstanceMemberName() {
+	p := &api.Project{
+		Name: "fleet",
+		ProjectPut: api.ProjectPut{
+			Config: map[string]string{"instances.names.scope": "member"},
+		},
+	}
+
+	for _, name := range []string{"web", "web--node1", "my--web"} {
+		qualified, err := project.InstanceMemberName(p, name, "node1")
+		fmt.Printf("%s: %q %v\n", name, qualified, err)
+	}
+
+	qualified, _ := project.InstanceMemberName(&api.Project{Name: "default"}, "web", "node1")
+	fmt.Println(qualified)
+
+	// Output: web: "web--node1" <nil>
+	// web--node1: "web--node1" <nil>
+	// my--web: "" Instance names cannot contain "--" in projects with member-scoped names
+	// web
+}
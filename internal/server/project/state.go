@@ -110,5 +110,25 @@ func GetCurrentAllocations(ctx context.Context, tx *db.ClusterTx, projectName st
 		Usage: int64(len(networks[projectName])),
 	}
 
+	// Get the disk space limit and usage of the images.
+	imagesLimit := int64(-1)
+	if info.Project.Config["limits.disk.images"] != "" {
+		parser := aggregateLimitConfigValueParsers["limits.disk.images"]
+		imagesLimit, err = parser(info.Project.Config["limits.disk.images"])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	imagesUsage, err := getImagesUsage(tx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	result["images"] = api.ProjectStateResource{
+		Limit: imagesLimit,
+		Usage: imagesUsage,
+	}
+
 	return result, nil
 }
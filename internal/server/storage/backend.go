@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
@@ -21,6 +22,7 @@ import (
 	"unicode"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v2"
 
@@ -64,6 +66,12 @@ var (
 	unavailablePoolsMu = sync.Mutex{}
 )
 
+// poolHealth holds the result of the last health check of the pools on this server, keyed by pool ID.
+var (
+	poolHealth   = make(map[int64]*api.StoragePoolHealth)
+	poolHealthMu = sync.Mutex{}
+)
+
 // ConnectIfInstanceIsRemote is a reference to cluster.ConnectIfInstanceIsRemote.
 //
 //nolint:typecheck
@@ -272,6 +280,120 @@ func (b *backend) GetResources() (*api.ResourcesStoragePool, error) {
 	return b.driver.GetResources()
 }
 
+// GetStatistics returns the deduplication and compression statistics of the pool.
+func (b *backend) GetStatistics() (*api.StoragePoolStatistics, error) {
+	l := b.logger.AddContext(nil)
+	l.Debug("GetStatistics started")
+	defer l.Debug("GetStatistics finished")
+
+	if b.Status() == api.StoragePoolStatusPending {
+		return nil, errors.New("The pool is in pending state")
+	}
+
+	return b.driver.GetStatistics()
+}
+
+// CheckHealth checks the health of the pool and records the result for Health.
+func (b *backend) CheckHealth() (*api.StoragePoolHealth, error) {
+	l := b.logger.AddContext(nil)
+	l.Debug("CheckHealth started")
+	defer l.Debug("CheckHealth finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return nil, err
+	}
+
+	health, err := b.driver.GetHealth()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(health.Messages) > 0 {
+		health.Status = api.StoragePoolHealthDegraded
+	} else {
+		health.Status = api.StoragePoolHealthHealthy
+	}
+
+	health.CheckedAt = time.Now()
+
+	poolHealthMu.Lock()
+	poolHealth[b.ID()] = health
+	poolHealthMu.Unlock()
+
+	return health, nil
+}
+
+// Health returns the result of the last health check of the pool on this server, if any.
+func (b *backend) Health() *api.StoragePoolHealth {
+	poolHealthMu.Lock()
+	defer poolHealthMu.Unlock()
+
+	health, ok := poolHealth[b.ID()]
+	if !ok {
+		return nil
+	}
+
+	healthCopy := *health
+	return &healthCopy
+}
+
+// Scrub starts a background check of the integrity of the data of the pool.
+func (b *backend) Scrub() error {
+	l := b.logger.AddContext(nil)
+	l.Debug("Scrub started")
+	defer l.Debug("Scrub finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	return b.driver.Scrub()
+}
+
+// TrimVolumes discards the unused blocks of the filesystem volumes of containers and custom volumes that are
+// mounted on this server, except for those with "maintenance.fstrim" disabled.
+func (b *backend) TrimVolumes(op *operations.Operation) error {
+	l := b.logger.AddContext(nil)
+	l.Debug("TrimVolumes started")
+	defer l.Debug("TrimVolumes finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	var dbVols []*db.StorageVolume
+	err = b.state.DB.Cluster.Transaction(b.state.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVols, err = tx.GetStoragePoolVolumes(ctx, b.ID(), true)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading storage volumes: %w", err)
+	}
+
+	vols := make([]drivers.Volume, 0, len(dbVols))
+	for _, dbVol := range dbVols {
+		if dbVol.ContentType != db.StoragePoolVolumeContentTypeNameFS || internalInstance.IsSnapshot(dbVol.Name) {
+			continue
+		}
+
+		if util.IsFalse(dbVol.Config["maintenance.fstrim"]) {
+			continue
+		}
+
+		switch dbVol.Type {
+		case db.StoragePoolVolumeTypeNameContainer:
+			vols = append(vols, b.GetVolume(drivers.VolumeTypeContainer, drivers.ContentTypeFS, project.Instance(dbVol.Project, dbVol.Name), dbVol.Config))
+		case db.StoragePoolVolumeTypeNameCustom:
+			vols = append(vols, b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentTypeFS, project.StorageVolume(dbVol.Project, dbVol.Name), dbVol.Config))
+		}
+	}
+
+	return b.driver.TrimVolumes(vols, op)
+}
+
 // IsUsed returns whether the storage pool is used by any volumes or profiles (excluding image volumes).
 func (b *backend) IsUsed() (bool, error) {
 	usedBy, err := UsedBy(context.TODO(), b.state, b, true, true, db.StoragePoolVolumeTypeNameImage)
@@ -419,6 +541,10 @@ func (b *backend) Delete(clientType request.ClientType, op *operations.Operation
 	delete(unavailablePools, b.Name())
 	unavailablePoolsMu.Unlock()
 
+	poolHealthMu.Lock()
+	delete(poolHealth, b.ID())
+	poolHealthMu.Unlock()
+
 	return nil
 }
 
@@ -750,6 +876,10 @@ func (b *backend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.Rea
 	l.Debug("CreateInstanceFromBackup started")
 	defer l.Debug("CreateInstanceFromBackup finished")
 
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityNormal)
+	defer release()
+
 	// Get the volume name on storage.
 	volStorageName := project.Instance(srcBackup.Project, srcBackup.Name)
 
@@ -1023,6 +1153,10 @@ func (b *backend) CreateInstanceFromCopy(inst instance.Instance, src instance.In
 	l.Debug("CreateInstanceFromCopy started")
 	defer l.Debug("CreateInstanceFromCopy finished")
 
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityNormal)
+	defer release()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -1241,6 +1375,115 @@ func (b *backend) CreateInstanceFromCopy(inst instance.Instance, src instance.In
 	return nil
 }
 
+// CreateInstanceFromClone creates an instance volume as a copy-on-write clone of a same-pool instance volume.
+// Unlike CreateInstanceFromCopy, this never falls back to copying the data and never includes snapshots.
+func (b *backend) CreateInstanceFromClone(inst instance.Instance, src instance.Instance, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name()})
+	l.Debug("CreateInstanceFromClone started")
+	defer l.Debug("CreateInstanceFromClone finished")
+
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityNormal)
+	defer release()
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if inst.Type() != src.Type() {
+		return errors.New("Instance types must match")
+	}
+
+	if !b.driver.Info().InstantCopy {
+		return fmt.Errorf("Storage pool %q doesn't support instant copies", b.name)
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	contentType := InstanceContentType(inst)
+
+	srcPool, err := LoadByInstance(b.state, src)
+	if err != nil {
+		return err
+	}
+
+	if srcPool.Name() != b.name {
+		return fmt.Errorf("Instant copies must use the storage pool %q of the source instance", srcPool.Name())
+	}
+
+	// Check source volume exists, and get its config.
+	srcConfig, err := b.GenerateInstanceBackupConfig(src, false, op)
+	if err != nil {
+		return fmt.Errorf("Failed generating instance clone config: %w", err)
+	}
+
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, contentType, volStorageName, srcConfig.Volume.Config)
+
+	volExists, err := b.driver.HasVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if volExists {
+		return errors.New("Cannot create volume, already exists on target storage")
+	}
+
+	srcVolStorageName := project.Instance(src.Project().Name, src.Name())
+	srcVol := b.GetVolume(volType, contentType, srcVolStorageName, srcConfig.Volume.Config)
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Validate config and create database entry for new storage volume.
+	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", vol.Type(), false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, false, true)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, inst.Name(), volType) })
+
+	// Record new volume with authorizer.
+	err = b.state.Authorizer.AddStoragePoolVolume(b.state.ShutdownCtx, inst.Project().Name, b.Name(), volType.Singular(), inst.Name(), "")
+	if err != nil {
+		logger.Error("Failed to add storage volume to authorizer", logger.Ctx{"name": inst.Name(), "type": volType, "pool": b.Name(), "project": inst.Project().Name, "error": err})
+	}
+
+	reverter.Add(func() {
+		_ = b.state.Authorizer.DeleteStoragePoolVolume(b.state.ShutdownCtx, inst.Project().Name, b.Name(), volType.Singular(), inst.Name(), "")
+	})
+
+	// Generate the effective root device volume for instance.
+	err = b.applyInstanceRootDiskOverrides(inst, &vol)
+	if err != nil {
+		return err
+	}
+
+	err = b.driver.CreateVolumeFromClone(vol, srcVol, op)
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotSupported) {
+			return fmt.Errorf("Storage pool %q doesn't support instant copies", b.name)
+		}
+
+		return err
+	}
+
+	reverter.Add(func() { _ = b.DeleteInstance(inst, op) })
+
+	// Setup the symlinks.
+	err = b.ensureInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name(), vol.MountPath())
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
 // RefreshCustomVolume refreshes custom volumes (and optionally snapshots) during the custom volume copy operations.
 // Snapshots that are not present in the source but are in the destination are removed from the
 // destination if snapshots are included in the synchronization.
@@ -1770,6 +2013,10 @@ func (b *backend) CreateInstanceFromImage(inst instance.Instance, fingerprint st
 	l.Debug("CreateInstanceFromImage started")
 	defer l.Debug("CreateInstanceFromImage finished")
 
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityNormal)
+	defer release()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -2032,7 +2279,10 @@ func (b *backend) CreateInstanceFromMigration(inst instance.Instance, conn io.Re
 				return err
 			}
 
-			reverter.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, inst.Name(), volType) })
+			// The records of a partially received volume are removed along with the instance by the caller.
+			if !args.KeepPartial {
+				reverter.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, inst.Name(), volType) })
+			}
 
 			// Record new volume with authorizer.
 			err = b.state.Authorizer.AddStoragePoolVolume(b.state.ShutdownCtx, inst.Project().Name, b.Name(), volType.Singular(), inst.Name(), "")
@@ -2040,9 +2290,11 @@ func (b *backend) CreateInstanceFromMigration(inst instance.Instance, conn io.Re
 				logger.Error("Failed to add storage volume to authorizer", logger.Ctx{"name": inst.Name(), "type": volType, "pool": b.Name(), "project": inst.Project().Name, "error": err})
 			}
 
-			reverter.Add(func() {
-				_ = b.state.Authorizer.DeleteStoragePoolVolume(b.state.ShutdownCtx, inst.Project().Name, b.Name(), volType.Singular(), inst.Name(), "")
-			})
+			if !args.KeepPartial {
+				reverter.Add(func() {
+					_ = b.state.Authorizer.DeleteStoragePoolVolume(b.state.ShutdownCtx, inst.Project().Name, b.Name(), volType.Singular(), inst.Name(), "")
+				})
+			}
 		}
 	}
 
@@ -2087,7 +2339,9 @@ func (b *backend) CreateInstanceFromMigration(inst instance.Instance, conn io.Re
 				return err
 			}
 
-			reverter.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, newSnapshotName, volType) })
+			if !args.KeepPartial {
+				reverter.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, newSnapshotName, volType) })
+			}
 		}
 	}
 
@@ -2169,6 +2423,13 @@ func (b *backend) CreateInstanceFromMigration(inst instance.Instance, conn io.Re
 		reverter.Add(func() { _ = b.DeleteInstance(inst, op) })
 	}
 
+	if args.Verify {
+		err = b.migrationChecksumsVerify(l, conn, vol, op)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = b.ensureInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name(), vol.MountPath())
 	if err != nil {
 		return err
@@ -2332,6 +2593,105 @@ func (b *backend) RenameInstance(inst instance.Instance, newName string, op *ope
 	return nil
 }
 
+// MoveInstanceProject renames the instance's volume and its snapshots on the storage device to match a new project.
+// The database records of the instance and its volume must already have been moved to the new project.
+func (b *backend) MoveInstanceProject(inst instance.Instance, newProjectName string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "newProject": newProjectName})
+	l.Debug("MoveInstanceProject started")
+	defer l.Debug("MoveInstanceProject finished")
+
+	if inst.IsSnapshot() {
+		return errors.New("Instance cannot be a snapshot")
+	}
+
+	// Check we can convert the instance to the volume types needed.
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	volume, err := VolumeDBGet(b, newProjectName, inst.Name(), volType)
+	if err != nil {
+		return err
+	}
+
+	dbVolSnaps, err := VolumeDBSnapshotsGet(b, newProjectName, inst.Name(), volType)
+	if err != nil {
+		return err
+	}
+
+	// Rename the volume and its snapshots on the storage device.
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	newVolStorageName := project.Instance(newProjectName, inst.Name())
+	contentType := InstanceContentType(inst)
+
+	vol := b.GetVolume(volType, contentType, volStorageName, volume.Config)
+
+	err = b.driver.RenameVolume(vol, newVolStorageName, op)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		// There's no need to pass config as it's not needed when renaming a volume.
+		newVol := b.GetVolume(volType, contentType, newVolStorageName, nil)
+		_ = b.driver.RenameVolume(newVol, volStorageName, op)
+	})
+
+	// Remove old instance symlink and create new one.
+	err = b.removeInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name())
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		_ = b.ensureInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name(), drivers.GetVolumeMountPath(b.name, volType, volStorageName))
+	})
+
+	err = b.ensureInstanceSymlink(inst.Type(), newProjectName, inst.Name(), drivers.GetVolumeMountPath(b.name, volType, newVolStorageName))
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		_ = b.removeInstanceSymlink(inst.Type(), newProjectName, inst.Name())
+	})
+
+	// Remove old instance snapshot symlink and create a new one if needed.
+	err = b.removeInstanceSnapshotSymlinkIfUnused(inst.Type(), inst.Project().Name, inst.Name())
+	if err != nil {
+		return err
+	}
+
+	if len(dbVolSnaps) > 0 {
+		reverter.Add(func() {
+			_ = b.ensureInstanceSnapshotSymlink(inst.Type(), inst.Project().Name, inst.Name())
+		})
+
+		err = b.ensureInstanceSnapshotSymlink(inst.Type(), newProjectName, inst.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	// Record volume move with authorizer.
+	err = b.state.Authorizer.DeleteStoragePoolVolume(b.state.ShutdownCtx, inst.Project().Name, b.Name(), vol.Type().Singular(), inst.Name(), "")
+	if err != nil {
+		logger.Error("Failed to remove storage volume from authorizer", logger.Ctx{"name": inst.Name(), "type": vol.Type(), "pool": b.Name(), "project": inst.Project().Name, "error": err})
+	}
+
+	err = b.state.Authorizer.AddStoragePoolVolume(b.state.ShutdownCtx, newProjectName, b.Name(), vol.Type().Singular(), inst.Name(), "")
+	if err != nil {
+		logger.Error("Failed to add storage volume to authorizer", logger.Ctx{"name": inst.Name(), "type": vol.Type(), "pool": b.Name(), "project": newProjectName, "error": err})
+	}
+
+	reverter.Success()
+	return nil
+}
+
 // DeleteInstance removes the instance's root volume (all snapshots need to be removed first).
 func (b *backend) DeleteInstance(inst instance.Instance, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
@@ -2566,6 +2926,11 @@ func (b *backend) MigrateInstance(inst instance.Instance, conn io.ReadWriteClose
 
 	args.Name = inst.Name() // Override args.Name to ensure instance volume is sent.
 
+	// The checksums of a running instance would be outdated by the time they are compared.
+	if args.Verify && !inst.IsSnapshot() && inst.IsRunning() {
+		return errors.New("Migration verification requires the instance to be stopped")
+	}
+
 	// Send migration index header frame with volume info and wait for receipt if not doing final sync.
 	if !args.FinalSync {
 		resp, err := b.migrationIndexHeaderSend(l, args.IndexHeaderVersion, conn, args.Info)
@@ -2606,6 +2971,13 @@ func (b *backend) MigrateInstance(inst instance.Instance, conn io.ReadWriteClose
 		return err
 	}
 
+	if args.Verify && !args.FinalSync {
+		err = b.migrationChecksumsSend(l, conn, vol, op)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -2689,6 +3061,10 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityLow)
+	defer release()
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -2795,6 +3171,38 @@ func (b *backend) GetInstanceUsage(inst instance.Instance) (*VolumeUsage, error)
 	return &val, nil
 }
 
+// GetInstanceSnapshotUsage returns the space used by the data of an instance snapshot and the space unique to it.
+func (b *backend) GetInstanceSnapshotUsage(snapInst instance.Instance) (*SnapshotUsage, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": snapInst.Project().Name, "instance": snapInst.Name()})
+	l.Debug("GetInstanceSnapshotUsage started")
+	defer l.Debug("GetInstanceSnapshotUsage finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return nil, err
+	}
+
+	if !snapInst.IsSnapshot() {
+		return nil, errors.New("Instance must be a snapshot")
+	}
+
+	volType, err := InstanceTypeToVolumeType(snapInst.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	// There's no need to pass config as it's not needed when retrieving the snapshot usage.
+	volStorageName := project.Instance(snapInst.Project().Name, snapInst.Name())
+	snapVol := b.GetVolume(volType, InstanceContentType(snapInst), volStorageName, nil)
+
+	used, unique, err := b.driver.GetVolumeSnapshotUsage(snapVol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SnapshotUsage{Used: used, Unique: unique}, nil
+}
+
 // SetInstanceQuota sets the quota on the instance's root volume.
 // Returns ErrInUse if the instance is running and the storage driver doesn't support online resizing.
 func (b *backend) SetInstanceQuota(inst instance.Instance, size string, vmStateSize string, op *operations.Operation) error {
@@ -2842,6 +3250,8 @@ func (b *backend) SetInstanceQuota(inst instance.Instance, size string, vmStateS
 		}
 	}
 
+	b.sendVolumeEvent(inst.Project().Name, api.EventStorageActionVolumeResized, inst.Name(), volType, map[string]any{"size": size, "size.state": vmStateSize})
+
 	return nil
 }
 
@@ -3109,6 +3519,8 @@ func (b *backend) CreateInstanceSnapshot(inst instance.Instance, src instance.In
 		return err
 	}
 
+	b.sendVolumeEvent(inst.Project().Name, api.EventStorageActionVolumeSnapshotCreated, inst.Name(), volType, nil)
+
 	reverter.Success()
 	return nil
 }
@@ -3195,6 +3607,10 @@ func (b *backend) DeleteInstanceSnapshot(inst instance.Instance, op *operations.
 	l.Debug("DeleteInstanceSnapshot started")
 	defer l.Debug("DeleteInstanceSnapshot finished")
 
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityLow)
+	defer release()
+
 	parentName, snapName, isSnap := api.GetParentAndSnapshotName(inst.Name())
 	if !inst.IsSnapshot() || !isSnap {
 		return errors.New("Instance must be a snapshot")
@@ -3937,6 +4353,13 @@ func (b *backend) CreateBucket(projectName string, bucket api.StorageBucketsPost
 		if err != nil {
 			return err
 		}
+
+		reverter.Add(func() { _ = b.driver.DeleteBucket(bucketVol, op) })
+	}
+
+	err = b.applyBucketRules(projectName, bucket.Name, nil, bucket.Config, op)
+	if err != nil {
+		return err
 	}
 
 	reverter.Success()
@@ -4035,15 +4458,213 @@ func (b *backend) UpdateBucket(projectName string, bucketName string, bucket api
 		}
 	}
 
+	err = b.applyBucketRules(projectName, curBucket.Name, curBucket.Config, bucket.Config, op)
+	if err != nil {
+		return err
+	}
+
 	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Update the database record.
 		return tx.UpdateStoragePoolBucket(ctx, b.id, curBucket.ID, &bucket)
 	})
 	if err != nil {
-		return err
+		return err
+	}
+
+	return nil
+}
+
+// bucketReplication returns the replication of the bucket config, nil if the bucket isn't replicated.
+// The target is either the URL of a bucket or a bucket of the project on another pool of the server, as "<pool>/<bucket>".
+func (b *backend) bucketReplication(projectName string, config map[string]string) (*drivers.BucketReplication, error) {
+	target := config["replication.target"]
+	if target == "" {
+		return nil, nil
+	}
+
+	replication := drivers.BucketReplication{
+		Credentials: drivers.S3Credentials{
+			AccessKey: config["replication.access_key"],
+			SecretKey: config["replication.secret_key"],
+		},
+	}
+
+	if replication.Credentials.AccessKey == "" || replication.Credentials.SecretKey == "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Bucket replication requires both replication.access_key and replication.secret_key")
+	}
+
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid replication target %q: %v", target, err)
+		}
+
+		if !slices.Contains([]string{"http", "https"}, u.Scheme) || strings.Trim(u.Path, "/") == "" {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Replication target %q must be the HTTP(S) URL of a bucket", target)
+		}
+
+		replication.URL = u
+
+		return &replication, nil
+	}
+
+	poolName, bucketName, ok := strings.Cut(target, "/")
+	if !ok || poolName == "" || bucketName == "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Replication target %q must be a bucket URL or <pool>/<bucket>", target)
+	}
+
+	if poolName == b.name {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Replication target must be on another storage pool")
+	}
+
+	targetPool, err := LoadByName(b.state, poolName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading replication target pool: %w", err)
+	}
+
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.GetStoragePoolBucket(ctx, targetPool.ID(), projectName, !targetPool.Driver().Info().Remote, bucketName)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading replication target bucket: %w", err)
+	}
+
+	replication.URL = targetPool.GetBucketURL(bucketName)
+	if replication.URL == nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Replication target bucket %q isn't reachable over S3", target)
+	}
+
+	return &replication, nil
+}
+
+// applyBucketRules applies the lifecycle rules and the replication of the new bucket config to the bucket.
+// Only the rules whose keys differ from the current config are applied.
+func (b *backend) applyBucketRules(projectName string, bucketName string, curConfig map[string]string, newConfig map[string]string, op *operations.Operation) error {
+	lifecycleChanged := false
+	replicationChanged := false
+
+	for _, config := range []map[string]string{curConfig, newConfig} {
+		for key := range config {
+			if curConfig[key] == newConfig[key] {
+				continue
+			}
+
+			if strings.HasPrefix(key, "lifecycle.") {
+				lifecycleChanged = true
+			} else if strings.HasPrefix(key, "replication.") {
+				replicationChanged = true
+			}
+		}
+	}
+
+	if !lifecycleChanged && !replicationChanged {
+		return nil
+	}
+
+	bucketLifecycle, err := drivers.BucketLifecycleFromConfig(newConfig)
+	if err != nil {
+		return err
+	}
+
+	replication, err := b.bucketReplication(projectName, newConfig)
+	if err != nil {
+		return err
+	}
+
+	memberSpecific := !b.Driver().Info().Remote // Member specific if storage pool isn't remote.
+
+	if !memberSpecific {
+		// Handle per-driver implementation for remote storage drivers.
+		bucketVol := b.GetVolume(drivers.VolumeTypeBucket, drivers.ContentTypeFS, project.StorageVolume(projectName, bucketName), newConfig)
+
+		if lifecycleChanged {
+			err = b.driver.SetBucketLifecycle(bucketVol, bucketLifecycle, op)
+			if err != nil {
+				if errors.Is(err, drivers.ErrNotSupported) {
+					return api.StatusErrorf(http.StatusBadRequest, "Storage pool does not support bucket lifecycle rules")
+				}
+
+				return err
+			}
+		}
+
+		if replicationChanged {
+			err = b.driver.SetBucketReplication(bucketVol, replication, op)
+			if err != nil {
+				if errors.Is(err, drivers.ErrNotSupported) {
+					return api.StatusErrorf(http.StatusBadRequest, "Storage pool does not support bucket replication")
+				}
+
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Handle common MinIO implementation for local storage drivers.
+	ctx, ctxCancel := context.WithTimeout(context.TODO(), time.Duration(time.Second*30))
+	defer ctxCancel()
+
+	minioProc, err := b.ActivateBucket(projectName, bucketName, op)
+	if err != nil {
+		return err
+	}
+
+	s3Client, err := minioProc.S3Client()
+	if err != nil {
+		return err
+	}
+
+	if lifecycleChanged {
+		err = s3Client.SetBucketLifecycle(ctx, bucketName, bucketLifecycle.S3Configuration())
+		if err != nil {
+			return fmt.Errorf("Failed setting bucket lifecycle: %w", err)
+		}
+	}
+
+	if !replicationChanged {
+		return nil
+	}
+
+	adminClient, err := minioProc.AdminClient()
+	if err != nil {
+		return err
+	}
+
+	// Remove the previous replication before setting up the new one.
+	if curConfig["replication.target"] != "" {
+		err = adminClient.RemoveReplication(ctx, bucketName)
+		if err != nil {
+			return fmt.Errorf("Failed removing bucket replication: %w", err)
+		}
+	}
+
+	if replication == nil {
+		return nil
+	}
+
+	// Replication requires versioning on both the source and target buckets.
+	err = s3Client.EnableVersioning(ctx, bucketName)
+	if err != nil {
+		return fmt.Errorf("Failed enabling bucket versioning: %w", err)
+	}
+
+	targetClient, err := minio.New(replication.URL.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(replication.Credentials.AccessKey, replication.Credentials.SecretKey, ""),
+		Secure: replication.URL.Scheme == "https",
+	})
+	if err != nil {
+		return err
+	}
+
+	err = targetClient.EnableVersioning(ctx, strings.Trim(replication.URL.Path, "/"))
+	if err != nil {
+		return fmt.Errorf("Failed enabling versioning of the replication target: %w", err)
 	}
 
-	return nil
+	return adminClient.AddReplication(ctx, bucketName, replication.RemoteURL())
 }
 
 // DeleteBucket deletes an object bucket.
@@ -4710,6 +5331,96 @@ func (b *backend) CreateCustomVolume(projectName string, volName string, desc st
 	}
 
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeCreated.Event(vol, string(vol.Type()), projectName, op, eventCtx))
+	b.sendVolumeEvent(projectName, api.EventStorageActionVolumeCreated, volName, drivers.VolumeTypeCustom, nil)
+
+	reverter.Success()
+	return nil
+}
+
+// AdoptCustomVolume adopts an existing source of the pool's storage (such as a ZFS dataset, a btrfs subvolume or
+// an LVM logical volume) as a new custom volume, without copying its data.
+func (b *backend) AdoptCustomVolume(projectName string, volName string, source string, desc string, config map[string]string, contentType drivers.ContentType, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "source": source, "desc": desc, "config": config, "contentType": contentType})
+	l.Debug("AdoptCustomVolume started")
+	defer l.Debug("AdoptCustomVolume finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	storagePoolSupported := slices.Contains(b.Driver().Info().VolumeTypes, drivers.VolumeTypeCustom)
+	if !storagePoolSupported {
+		return errors.New("Storage pool does not support custom volume type")
+	}
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+	volConfig := maps.Clone(config)
+	if volConfig == nil {
+		volConfig = map[string]string{}
+	}
+
+	vol := b.GetVolume(drivers.VolumeTypeCustom, contentType, volStorageName, volConfig)
+
+	// Validate the config before touching the source.
+	err = b.driver.FillVolumeConfig(vol)
+	if err != nil {
+		return err
+	}
+
+	err = b.driver.ValidateVolume(vol, false)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Adopt the source, this records its size in the volume config when it's fixed by the source.
+	revertAdopt, err := b.driver.AdoptVolume(vol, source, op)
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotSupported) {
+			return fmt.Errorf("Importing existing %s volumes isn't supported by the %q driver", vol.ContentType(), b.driver.Info().Name)
+		}
+
+		return err
+	}
+
+	reverter.Add(revertAdopt)
+
+	// Apply the size limit of filesystem volumes whose source doesn't have a fixed size.
+	if vol.ContentType() == drivers.ContentTypeFS {
+		err = b.driver.SetVolumeQuota(vol, vol.ConfigSize(), false, op)
+		if err != nil {
+			return fmt.Errorf("Failed applying the volume size: %w", err)
+		}
+	}
+
+	// Create database entry for the imported storage volume.
+	err = VolumeDBCreate(b, projectName, volName, desc, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), false, false)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = VolumeDBDelete(b, projectName, volName, vol.Type()) })
+
+	eventCtx := logger.Ctx{"type": vol.Type(), "source": source}
+
+	var location string
+	if b.state.ServerClustered && !b.Driver().Info().Remote {
+		eventCtx["location"] = b.state.ServerName
+		location = b.state.ServerName
+	}
+
+	// Record new volume with authorizer.
+	err = b.state.Authorizer.AddStoragePoolVolume(b.state.ShutdownCtx, projectName, b.Name(), vol.Type().Singular(), volName, location)
+	if err != nil {
+		logger.Error("Failed to add storage volume to authorizer", logger.Ctx{"name": volName, "type": vol.Type(), "pool": b.Name(), "project": projectName, "error": err})
+	}
+
+	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeCreated.Event(vol, string(vol.Type()), projectName, op, eventCtx))
+	b.sendVolumeEvent(projectName, api.EventStorageActionVolumeCreated, volName, drivers.VolumeTypeCustom, nil)
 
 	reverter.Success()
 	return nil
@@ -4722,6 +5433,10 @@ func (b *backend) CreateCustomVolumeFromCopy(projectName string, srcProjectName
 	l.Debug("CreateCustomVolumeFromCopy started")
 	defer l.Debug("CreateCustomVolumeFromCopy finished")
 
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityNormal)
+	defer release()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -4846,6 +5561,7 @@ func (b *backend) CreateCustomVolumeFromCopy(projectName string, srcProjectName
 		}
 
 		b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeCreated.Event(vol, string(vol.Type()), projectName, op, eventCtx))
+		b.sendVolumeEvent(projectName, api.EventStorageActionVolumeCreated, volName, drivers.VolumeTypeCustom, nil)
 
 		reverter.Success()
 		return nil
@@ -5057,6 +5773,114 @@ func (b *backend) migrationIndexHeaderReceive(l logger.Logger, indexHeaderVersio
 	return &info, nil
 }
 
+// migrationChecksums computes the checksums of a transferred volume.
+// For block volumes, blockSize is the number of bytes to cover, the whole device being used if zero.
+func (b *backend) migrationChecksums(vol drivers.Volume, blockSize int64, op *operations.Operation) (*localMigration.Checksums, error) {
+	checksums := localMigration.Checksums{}
+
+	err := vol.MountTask(func(mountPath string, op *operations.Operation) error {
+		if vol.ContentType() == drivers.ContentTypeBlock || vol.ContentType() == drivers.ContentTypeISO {
+			diskPath, err := b.driver.GetVolumeDiskPath(vol)
+			if err != nil {
+				return err
+			}
+
+			if blockSize == 0 {
+				blockSize, err = drivers.BlockDiskSizeBytes(diskPath)
+				if err != nil {
+					return err
+				}
+			}
+
+			checksums.Block, err = migration.ChecksumBlock(diskPath, blockSize)
+			if err != nil {
+				return err
+			}
+
+			checksums.BlockSize = blockSize
+		}
+
+		// Virtual machines also have a filesystem volume holding their configuration.
+		if vol.ContentType() == drivers.ContentTypeFS || vol.IsVMBlock() {
+			var err error
+
+			// The backup file is regenerated on the target and ext4 filesystems come with a lost+found directory.
+			checksums.Filesystem, err = migration.ChecksumFilesystem(mountPath, []string{"backup.yaml", "lost+found"})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, op)
+	if err != nil {
+		return nil, fmt.Errorf("Failed computing checksums of volume %q: %w", vol.Name(), err)
+	}
+
+	return &checksums, nil
+}
+
+// migrationChecksumsSend computes the checksums of the transferred volume and sends them to the target.
+func (b *backend) migrationChecksumsSend(l logger.Logger, conn io.ReadWriteCloser, vol drivers.Volume, op *operations.Operation) error {
+	checksums, err := b.migrationChecksums(vol, 0, op)
+	if err != nil {
+		return err
+	}
+
+	checksumsJSON, err := json.Marshal(checksums)
+	if err != nil {
+		return fmt.Errorf("Failed encoding migration checksums: %w", err)
+	}
+
+	_, err = conn.Write(checksumsJSON)
+	if err != nil {
+		return fmt.Errorf("Failed sending migration checksums: %w", err)
+	}
+
+	err = conn.Close() // End the frame.
+	if err != nil {
+		return fmt.Errorf("Failed closing migration checksums frame: %w", err)
+	}
+
+	l.Debug("Sent migration checksums", logger.Ctx{"checksums": fmt.Sprintf("%+v", checksums)})
+
+	return nil
+}
+
+// migrationChecksumsVerify receives the checksums of the volume from the source and compares them with the
+// checksums of the transferred volume.
+func (b *backend) migrationChecksumsVerify(l logger.Logger, conn io.ReadWriteCloser, vol drivers.Volume, op *operations.Operation) error {
+	l.Debug("Waiting for migration checksums")
+
+	buf, err := io.ReadAll(conn)
+	if err != nil {
+		return fmt.Errorf("Failed reading migration checksums: %w", err)
+	}
+
+	srcChecksums := localMigration.Checksums{}
+	err = json.Unmarshal(buf, &srcChecksums)
+	if err != nil {
+		return fmt.Errorf("Failed decoding migration checksums: %w", err)
+	}
+
+	checksums, err := b.migrationChecksums(vol, srcChecksums.BlockSize, op)
+	if err != nil {
+		return err
+	}
+
+	if checksums.Block != srcChecksums.Block {
+		return fmt.Errorf("Migration verification failed: block device content of volume %q differs from the source", vol.Name())
+	}
+
+	if checksums.Filesystem != srcChecksums.Filesystem {
+		return fmt.Errorf("Migration verification failed: filesystem content of volume %q differs from the source", vol.Name())
+	}
+
+	l.Info("Verified migrated volume", logger.Ctx{"volName": vol.Name()})
+
+	return nil
+}
+
 // MigrateCustomVolume sends a volume for migration.
 func (b *backend) MigrateCustomVolume(projectName string, conn io.ReadWriteCloser, args *localMigration.VolumeSourceArgs, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": args.Name, "args": fmt.Sprintf("%+v", args)})
@@ -5256,6 +6080,7 @@ func (b *backend) CreateCustomVolumeFromMigration(projectName string, conn io.Re
 	}
 
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeCreated.Event(vol, string(vol.Type()), projectName, op, eventCtx))
+	b.sendVolumeEvent(projectName, api.EventStorageActionVolumeCreated, args.Name, drivers.VolumeTypeCustom, nil)
 
 	reverter.Success()
 	return nil
@@ -5368,6 +6193,12 @@ func (b *backend) RenameCustomVolume(projectName string, volName string, newVolN
 		logger.Error("Failed to rename storage volume in authorizer", logger.Ctx{"old_name": volName, "new_name": newVolStorageName, "type": vol.Type(), "pool": b.Name(), "project": projectName, "error": err})
 	}
 
+	// Keep the integrity manifest of the volume.
+	err = os.Rename(IntegrityManifestPath(b.name, projectName, volName), IntegrityManifestPath(b.name, projectName, newVolName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Error("Failed to rename storage volume integrity manifest", logger.Ctx{"old_name": volName, "new_name": newVolName, "pool": b.Name(), "project": projectName, "error": err})
+	}
+
 	vol = b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), newVolStorageName, nil)
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeRenamed.Event(vol, string(vol.Type()), projectName, op, logger.Ctx{"old_name": volName}))
 
@@ -5517,6 +6348,15 @@ func (b *backend) UpdateCustomVolume(projectName string, volName string, newDesc
 		}
 	}
 
+	// Record a new integrity manifest on the next check when the schedule changes.
+	_, ok := changedConfig["integrity.schedule"]
+	if ok {
+		err = ResetIntegrityManifest(b.name, projectName, volName)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Unset idmap keys if volume is unmapped.
 	if util.IsTrue(newConfig["security.unmapped"]) {
 		delete(newConfig, "volatile.idmap.last")
@@ -5609,6 +6449,11 @@ func (b *backend) UpdateCustomVolume(projectName string, volName string, newDesc
 
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeUpdated.Event(newVol, string(newVol.Type()), projectName, op, nil))
 
+	_, resized := changedConfig["size"]
+	if resized {
+		b.sendVolumeEvent(projectName, api.EventStorageActionVolumeResized, volName, drivers.VolumeTypeCustom, map[string]any{"size": newSize, "old_size": curVol.Config["size"]})
+	}
+
 	return nil
 }
 
@@ -5750,6 +6595,11 @@ func (b *backend) DeleteCustomVolume(projectName string, volName string, op *ope
 		logger.Error("Failed to remove storage volume from authorizer", logger.Ctx{"name": volName, "type": vol.Type(), "pool": b.Name(), "project": projectName, "error": err})
 	}
 
+	err = ResetIntegrityManifest(b.name, projectName, volName)
+	if err != nil {
+		logger.Error("Failed to remove storage volume integrity manifest", logger.Ctx{"name": volName, "pool": b.Name(), "project": projectName, "error": err})
+	}
+
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeDeleted.Event(vol, string(vol.Type()), projectName, op, nil))
 
 	return nil
@@ -5815,6 +6665,143 @@ func (b *backend) GetCustomVolumeUsage(projectName, volName string) (*VolumeUsag
 	return &val, nil
 }
 
+// GetCustomVolumeMirror returns the mirroring state of the custom volume.
+func (b *backend) GetCustomVolumeMirror(projectName string, volName string) (*api.StorageVolumeMirror, error) {
+	err := b.isStatusReady()
+	if err != nil {
+		return nil, err
+	}
+
+	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, volume.Config)
+
+	return b.driver.GetVolumeMirror(vol)
+}
+
+// PromoteCustomVolume makes the local copy of the mirrored custom volume the primary one.
+func (b *backend) PromoteCustomVolume(projectName string, volName string, force bool, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "force": force})
+	l.Debug("PromoteCustomVolume started")
+	defer l.Debug("PromoteCustomVolume finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, volume.Config)
+
+	err = b.driver.PromoteVolume(vol, force, op)
+	if err != nil {
+		return err
+	}
+
+	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeUpdated.Event(vol, string(vol.Type()), projectName, op, logger.Ctx{"action": "promote"}))
+
+	return nil
+}
+
+// DemoteCustomVolume makes the local copy of the mirrored custom volume a secondary one.
+func (b *backend) DemoteCustomVolume(projectName string, volName string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName})
+	l.Debug("DemoteCustomVolume started")
+	defer l.Debug("DemoteCustomVolume finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, volume.Config)
+
+	// The volume becomes read-only once demoted.
+	err = VolumeUsedByInstanceDevices(b.state, b.name, projectName, &volume.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
+		inst, err := instance.Load(b.state, dbInst, project)
+		if err != nil {
+			return err
+		}
+
+		if inst.IsRunning() {
+			return fmt.Errorf("Cannot demote volume used by running instance %q", inst.Name())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = b.driver.DemoteVolume(vol, op)
+	if err != nil {
+		return err
+	}
+
+	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeUpdated.Event(vol, string(vol.Type()), projectName, op, logger.Ctx{"action": "demote"}))
+
+	return nil
+}
+
+// CustomVolumeSnapshotDiff calls fn for each change of the custom volume between snapshot snapA and snapshot
+// snapB, or the volume itself if snapB is empty.
+func (b *backend) CustomVolumeSnapshotDiff(projectName string, volName string, snapA string, snapB string, fn func(change api.StorageVolumeDiffEntry) error, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "snapA": snapA, "snapB": snapB})
+	l.Debug("CustomVolumeSnapshotDiff started")
+	defer l.Debug("CustomVolumeSnapshotDiff finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	contentType := drivers.ContentType(volume.ContentType)
+	if contentType == drivers.ContentTypeISO {
+		return errors.New("Comparing ISO volumes isn't supported")
+	}
+
+	// Check that the snapshots exist.
+	for _, snapName := range []string{snapA, snapB} {
+		if snapName == "" {
+			continue
+		}
+
+		_, err = VolumeDBGet(b, projectName, drivers.GetSnapshotVolumeName(volName, snapName), drivers.VolumeTypeCustom)
+		if err != nil {
+			return fmt.Errorf("Failed loading snapshot %q: %w", snapName, err)
+		}
+	}
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+	vol := b.GetVolume(drivers.VolumeTypeCustom, contentType, volStorageName, volume.Config)
+
+	return b.driver.VolumeSnapshotDiff(vol, snapA, snapB, fn, op)
+}
+
 // MountCustomVolume mounts a custom volume.
 func (b *backend) MountCustomVolume(projectName, volName string, op *operations.Operation) (*MountInfo, error) {
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName})
@@ -6030,6 +7017,7 @@ func (b *backend) CreateCustomVolumeSnapshot(projectName, volName string, newSna
 	}
 
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeSnapshotCreated.Event(vol, string(vol.Type()), projectName, op, logger.Ctx{"type": vol.Type()}))
+	b.sendVolumeEvent(projectName, api.EventStorageActionVolumeSnapshotCreated, fullSnapshotName, drivers.VolumeTypeCustom, nil)
 
 	reverter.Success()
 	return nil
@@ -6092,6 +7080,10 @@ func (b *backend) DeleteCustomVolumeSnapshot(projectName, volName string, op *op
 	l.Debug("DeleteCustomVolumeSnapshot started")
 	defer l.Debug("DeleteCustomVolumeSnapshot finished")
 
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityLow)
+	defer release()
+
 	isSnap := internalInstance.IsSnapshot(volName)
 
 	if !isSnap {
@@ -6220,6 +7212,12 @@ func (b *backend) RestoreCustomVolume(projectName, volName string, snapshotName
 		return err
 	}
 
+	// The content of the volume legitimately changed, record a new integrity manifest on the next check.
+	err = ResetIntegrityManifest(b.name, projectName, volName)
+	if err != nil {
+		return err
+	}
+
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeRestored.Event(vol, string(vol.Type()), projectName, op, logger.Ctx{"snapshot": snapshotName}))
 
 	return nil
@@ -7096,6 +8094,10 @@ func (b *backend) BackupCustomVolume(projectName string, volName string, tarWrit
 	l.Debug("BackupCustomVolume started")
 	defer l.Debug("BackupCustomVolume finished")
 
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityLow)
+	defer release()
+
 	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
 	if err != nil {
 		return err
@@ -7222,6 +8224,7 @@ func (b *backend) CreateCustomVolumeFromISO(projectName string, volName string,
 	}
 
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeCreated.Event(vol, string(vol.Type()), projectName, op, eventCtx))
+	b.sendVolumeEvent(projectName, api.EventStorageActionVolumeCreated, volName, drivers.VolumeTypeCustom, nil)
 
 	reverter.Success()
 	return nil
@@ -7232,6 +8235,10 @@ func (b *backend) CreateCustomVolumeFromBackup(srcBackup backup.Info, srcData io
 	l.Debug("CreateCustomVolumeFromBackup started")
 	defer l.Debug("CreateCustomVolumeFromBackup finished")
 
+	// Wait for a slot of the pool, as limited by its operations.concurrency option.
+	release := b.acquireOperation(operationPriorityNormal)
+	defer release()
+
 	if srcBackup.Config == nil || srcBackup.Config.Volume == nil {
 		return errors.New("Valid volume config not found in index")
 	}
@@ -7341,6 +8348,7 @@ func (b *backend) CreateCustomVolumeFromBackup(srcBackup backup.Info, srcData io
 	}
 
 	b.state.Events.SendLifecycle(srcBackup.Project, lifecycle.StorageVolumeCreated.Event(vol, string(vol.Type()), srcBackup.Project, op, eventCtx))
+	b.sendVolumeEvent(srcBackup.Project, api.EventStorageActionVolumeCreated, srcBackup.Name, drivers.VolumeTypeCustom, nil)
 
 	reverter.Success()
 	return nil
@@ -77,6 +77,26 @@ func (b *mockBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return nil, nil
 }
 
+func (b *mockBackend) GetStatistics() (*api.StoragePoolStatistics, error) {
+	return nil, nil
+}
+
+func (b *mockBackend) CheckHealth() (*api.StoragePoolHealth, error) {
+	return nil, nil
+}
+
+func (b *mockBackend) Health() *api.StoragePoolHealth {
+	return nil
+}
+
+func (b *mockBackend) Scrub() error {
+	return nil
+}
+
+func (b *mockBackend) TrimVolumes(op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) IsUsed() (bool, error) {
 	return false, nil
 }
@@ -121,6 +141,10 @@ func (b *mockBackend) CreateInstanceFromCopy(inst instance.Instance, src instanc
 	return nil
 }
 
+func (b *mockBackend) CreateInstanceFromClone(inst instance.Instance, src instance.Instance, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) CreateInstanceFromImage(inst instance.Instance, fingerprint string, op *operations.Operation) error {
 	return nil
 }
@@ -133,6 +157,10 @@ func (b *mockBackend) RenameInstance(inst instance.Instance, newName string, op
 	return nil
 }
 
+func (b *mockBackend) MoveInstanceProject(inst instance.Instance, newProjectName string, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) DeleteInstance(inst instance.Instance, op *operations.Operation) error {
 	return nil
 }
@@ -161,6 +189,18 @@ func (b *mockBackend) ListUnknownVolumes(op *operations.Operation) (map[string][
 	return nil, nil
 }
 
+func (b *mockBackend) ListOrphanVolumes(op *operations.Operation) ([]OrphanVolume, error) {
+	return nil, nil
+}
+
+func (b *mockBackend) AdoptOrphanVolume(orphan OrphanVolume, op *operations.Operation) error {
+	return nil
+}
+
+func (b *mockBackend) DeleteOrphanVolume(orphan OrphanVolume, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) ImportInstance(inst instance.Instance, poolVol *backupConfig.Config, op *operations.Operation) (revert.Hook, error) {
 	return nil, nil
 }
@@ -190,6 +230,10 @@ func (b *mockBackend) GetInstanceUsage(inst instance.Instance) (*VolumeUsage, er
 	return nil, nil
 }
 
+func (b *mockBackend) GetInstanceSnapshotUsage(snapInst instance.Instance) (*SnapshotUsage, error) {
+	return nil, nil
+}
+
 func (b *mockBackend) SetInstanceQuota(inst instance.Instance, size string, vmStateSize string, op *operations.Operation) error {
 	return nil
 }
@@ -287,6 +331,10 @@ func (b *mockBackend) CreateCustomVolume(projectName string, volName string, des
 	return nil
 }
 
+func (b *mockBackend) AdoptCustomVolume(projectName string, volName string, source string, desc string, config map[string]string, contentType drivers.ContentType, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) CreateCustomVolumeFromCopy(projectName string, srcProjectName string, volName string, desc string, config map[string]string, srcPoolName string, srcVolName string, srcVolOnly bool, op *operations.Operation) error {
 	return nil
 }
@@ -319,6 +367,22 @@ func (b *mockBackend) GetCustomVolumeUsage(projectName string, volName string) (
 	return nil, nil
 }
 
+func (b *mockBackend) GetCustomVolumeMirror(projectName string, volName string) (*api.StorageVolumeMirror, error) {
+	return nil, nil
+}
+
+func (b *mockBackend) PromoteCustomVolume(projectName string, volName string, force bool, op *operations.Operation) error {
+	return nil
+}
+
+func (b *mockBackend) DemoteCustomVolume(projectName string, volName string, op *operations.Operation) error {
+	return nil
+}
+
+func (b *mockBackend) CustomVolumeSnapshotDiff(projectName string, volName string, snapA string, snapB string, fn func(change api.StorageVolumeDiffEntry) error, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) MountCustomVolume(projectName string, volName string, op *operations.Operation) (*MountInfo, error) {
 	return nil, nil
 }
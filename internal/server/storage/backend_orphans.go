@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	backupConfig "github.com/lxc/incus/v6/internal/server/backup/config"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// OrphanVolume is a volume of the pool which only exists either on the storage or in the database,
+// usually left behind by an interrupted operation.
+type OrphanVolume struct {
+	Project     string
+	Name        string
+	Type        drivers.VolumeType
+	ContentType drivers.ContentType
+
+	// OnStorage is true when the volume exists on the storage without a database record, and false
+	// when the database record has no matching volume on the storage.
+	OnStorage bool
+}
+
+// orphanVolumeKey identifies a volume when comparing the volumes on storage with the database records.
+type orphanVolumeKey struct {
+	volType     drivers.VolumeType
+	projectName string
+	name        string
+}
+
+// orphanVolumeTypes are the volume types whose storage volumes and database records are compared.
+var orphanVolumeTypes = []drivers.VolumeType{drivers.VolumeTypeContainer, drivers.VolumeTypeVM, drivers.VolumeTypeCustom, drivers.VolumeTypeImage}
+
+// orphanVolumeParts returns the project and volume names of a volume from its name on storage.
+func orphanVolumeParts(volType drivers.VolumeType, volStorageName string) (string, string) {
+	switch volType {
+	case drivers.VolumeTypeContainer, drivers.VolumeTypeVM:
+		return project.InstanceParts(volStorageName)
+	case drivers.VolumeTypeCustom:
+		return project.StorageVolumeParts(volStorageName)
+	default:
+		// Image volumes are named after their fingerprint and recorded in the default project.
+		return api.ProjectDefaultName, volStorageName
+	}
+}
+
+// orphanVolumeStorageName returns the name on storage of an orphan volume.
+func orphanVolumeStorageName(orphan OrphanVolume) string {
+	switch orphan.Type {
+	case drivers.VolumeTypeContainer, drivers.VolumeTypeVM:
+		return project.Instance(orphan.Project, orphan.Name)
+	case drivers.VolumeTypeCustom:
+		return project.StorageVolume(orphan.Project, orphan.Name)
+	default:
+		return orphan.Name
+	}
+}
+
+// ListOrphanVolumes compares the volumes on the storage with the database records of the pool for this server,
+// and returns the volumes only found on one side. Snapshots and buckets aren't checked.
+func (b *backend) ListOrphanVolumes(op *operations.Operation) ([]OrphanVolume, error) {
+	err := b.isStatusReady()
+	if err != nil {
+		return nil, err
+	}
+
+	poolVols, err := b.driver.ListVolumes()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting pool volumes: %w", err)
+	}
+
+	var dbVols []*db.StorageVolume
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVols, err = tx.GetStoragePoolVolumes(ctx, b.id, true)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading storage volumes: %w", err)
+	}
+
+	// Index the database records of the volumes, excluding their snapshots.
+	records := make(map[orphanVolumeKey]*db.StorageVolume, len(dbVols))
+	for _, dbVol := range dbVols {
+		_, _, isSnap := api.GetParentAndSnapshotName(dbVol.Name)
+		if isSnap {
+			continue
+		}
+
+		volDBType, err := VolumeTypeNameToDBType(dbVol.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		volType, err := VolumeDBTypeToType(volDBType)
+		if err != nil {
+			return nil, err
+		}
+
+		records[orphanVolumeKey{volType: volType, projectName: dbVol.Project, name: dbVol.Name}] = dbVol
+	}
+
+	orphans := []OrphanVolume{}
+	onStorage := make(map[orphanVolumeKey]bool, len(poolVols))
+
+	// Look for volumes on storage without a database record.
+	for _, poolVol := range poolVols {
+		volType := poolVol.Type()
+		if !isOrphanVolumeType(volType) {
+			continue
+		}
+
+		projectName, volName := orphanVolumeParts(volType, poolVol.Name())
+		key := orphanVolumeKey{volType: volType, projectName: projectName, name: volName}
+		onStorage[key] = true
+
+		if records[key] != nil {
+			continue
+		}
+
+		orphans = append(orphans, OrphanVolume{
+			Project:     projectName,
+			Name:        volName,
+			Type:        volType,
+			ContentType: poolVol.ContentType(),
+			OnStorage:   true,
+		})
+	}
+
+	// Look for database records without a volume on storage.
+	for key, dbVol := range records {
+		if onStorage[key] || !isOrphanVolumeType(key.volType) {
+			continue
+		}
+
+		volDBContentType, err := VolumeContentTypeNameToContentType(dbVol.ContentType)
+		if err != nil {
+			return nil, err
+		}
+
+		contentType, err := VolumeDBContentTypeToContentType(volDBContentType)
+		if err != nil {
+			return nil, err
+		}
+
+		orphan := OrphanVolume{
+			Project:     key.projectName,
+			Name:        key.name,
+			Type:        key.volType,
+			ContentType: contentType,
+		}
+
+		// Not all drivers list every volume they know of, so double check the volume is really missing.
+		vol := b.GetVolume(orphan.Type, orphan.ContentType, orphanVolumeStorageName(orphan), dbVol.Config)
+		exists, err := b.driver.HasVolume(vol)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			continue
+		}
+
+		orphans = append(orphans, orphan)
+	}
+
+	return orphans, nil
+}
+
+// isOrphanVolumeType returns whether volumes of this type are compared with their database records.
+func isOrphanVolumeType(volType drivers.VolumeType) bool {
+	return slices.Contains(orphanVolumeTypes, volType)
+}
+
+// AdoptOrphanVolume creates the database records of a custom volume which only exists on the storage,
+// along with those of its snapshots. The volume gets the default configuration of the pool.
+func (b *backend) AdoptOrphanVolume(orphan OrphanVolume, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": orphan.Project, "volName": orphan.Name, "type": orphan.Type})
+	l.Debug("AdoptOrphanVolume started")
+	defer l.Debug("AdoptOrphanVolume finished")
+
+	if !orphan.OnStorage {
+		return errors.New("Only volumes without a database record can be adopted")
+	}
+
+	if orphan.Type != drivers.VolumeTypeCustom {
+		return fmt.Errorf("Only custom volumes can be adopted, %s need to be recovered instead", orphan.Type)
+	}
+
+	vol := b.GetVolume(orphan.Type, orphan.ContentType, orphanVolumeStorageName(orphan), map[string]string{})
+
+	// Generate the config of the volume, as done when recovering it.
+	projectVols := make(map[string][]*backupConfig.Config)
+	err := b.detectUnknownCustomVolume(&vol, projectVols, op)
+	if err != nil {
+		return err
+	}
+
+	if len(projectVols[orphan.Project]) != 1 {
+		return errors.New("Volume already has a database record")
+	}
+
+	_, err = b.ImportCustomVolume(orphan.Project, projectVols[orphan.Project][0], op)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteOrphanVolume deletes a volume which only exists on the storage, along with its snapshots, or the
+// database record of a custom or image volume which doesn't exist on the storage.
+func (b *backend) DeleteOrphanVolume(orphan OrphanVolume, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": orphan.Project, "volName": orphan.Name, "type": orphan.Type, "onStorage": orphan.OnStorage})
+	l.Debug("DeleteOrphanVolume started")
+	defer l.Debug("DeleteOrphanVolume finished")
+
+	if !orphan.OnStorage {
+		if orphan.Type == drivers.VolumeTypeContainer || orphan.Type == drivers.VolumeTypeVM {
+			return errors.New("Records of instance volumes can't be deleted on their own, the instance needs to be deleted instead")
+		}
+
+		return VolumeDBDelete(b, orphan.Project, orphan.Name, orphan.Type)
+	}
+
+	// Make sure that the volume didn't get a database record in the meantime.
+	_, err := VolumeDBGet(b, orphan.Project, orphan.Name, orphan.Type)
+	if err == nil {
+		return errors.New("Volume now has a database record")
+	} else if !response.IsNotFoundError(err) {
+		return err
+	}
+
+	vol := b.GetVolume(orphan.Type, orphan.ContentType, orphanVolumeStorageName(orphan), map[string]string{})
+
+	snapshots, err := b.driver.VolumeSnapshots(vol, op)
+	if err != nil && !errors.Is(err, drivers.ErrNotSupported) {
+		return fmt.Errorf("Failed listing volume snapshots: %w", err)
+	}
+
+	for _, snapName := range snapshots {
+		snapVol, err := vol.NewSnapshot(snapName)
+		if err != nil {
+			return err
+		}
+
+		err = b.driver.DeleteVolumeSnapshot(snapVol, op)
+		if err != nil {
+			return fmt.Errorf("Failed deleting volume snapshot %q: %w", snapName, err)
+		}
+	}
+
+	err = b.driver.DeleteVolume(vol, op)
+	if err != nil {
+		return fmt.Errorf("Failed deleting volume: %w", err)
+	}
+
+	return nil
+}
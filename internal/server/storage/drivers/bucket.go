@@ -1,7 +1,96 @@
 package drivers
 
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
 // S3Credentials represents the credentials to access a bucket.
 type S3Credentials struct {
 	AccessKey string `json:"access_key"`
 	SecretKey string `json:"secret_key"`
 }
+
+// BucketLifecycle represents the lifecycle rules of a bucket.
+type BucketLifecycle struct {
+	// Number of days after which objects are deleted.
+	ExpiryDays int
+
+	// Number of days after which the previous versions of objects are deleted.
+	NoncurrentExpiryDays int
+
+	// Prefix of the objects the rules apply to.
+	Prefix string
+}
+
+// BucketLifecycleFromConfig returns the lifecycle rules of the bucket config.
+// Nil is returned when no lifecycle rule is set.
+func BucketLifecycleFromConfig(config map[string]string) (*BucketLifecycle, error) {
+	bucketLifecycle := BucketLifecycle{Prefix: config["lifecycle.prefix"]}
+
+	for key, days := range map[string]*int{"lifecycle.expiry": &bucketLifecycle.ExpiryDays, "lifecycle.expiry.noncurrent": &bucketLifecycle.NoncurrentExpiryDays} {
+		if config[key] == "" {
+			continue
+		}
+
+		value, err := strconv.Atoi(config[key])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for %q: %w", key, err)
+		}
+
+		*days = value
+	}
+
+	if bucketLifecycle.ExpiryDays == 0 && bucketLifecycle.NoncurrentExpiryDays == 0 {
+		return nil, nil
+	}
+
+	return &bucketLifecycle, nil
+}
+
+// S3Configuration returns the S3 lifecycle configuration of the rules.
+// An empty configuration, which removes the rules of the bucket, is returned for nil rules.
+func (l *BucketLifecycle) S3Configuration() *lifecycle.Configuration {
+	config := lifecycle.NewConfiguration()
+	if l == nil {
+		return config
+	}
+
+	rule := lifecycle.Rule{
+		ID:         "incus",
+		Status:     "Enabled",
+		RuleFilter: lifecycle.Filter{Prefix: l.Prefix},
+	}
+
+	if l.ExpiryDays > 0 {
+		rule.Expiration = lifecycle.Expiration{Days: lifecycle.ExpirationDays(l.ExpiryDays)}
+	}
+
+	if l.NoncurrentExpiryDays > 0 {
+		rule.NoncurrentVersionExpiration = lifecycle.NoncurrentVersionExpiration{NoncurrentDays: lifecycle.ExpirationDays(l.NoncurrentExpiryDays)}
+	}
+
+	config.Rules = []lifecycle.Rule{rule}
+
+	return config
+}
+
+// BucketReplication represents the replication of a bucket to another bucket.
+type BucketReplication struct {
+	// URL of the target bucket, including the bucket name as its path.
+	URL *url.URL
+
+	// Credentials to write to the target bucket.
+	Credentials S3Credentials
+}
+
+// RemoteURL returns the URL of the target bucket including its credentials.
+func (r *BucketReplication) RemoteURL() string {
+	u := *r.URL
+	u.User = url.UserPassword(r.Credentials.AccessKey, r.Credentials.SecretKey)
+
+	return u.String()
+}
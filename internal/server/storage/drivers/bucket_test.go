@@ -0,0 +1,35 @@
+package drivers
+
+import (
+	"fmt"
+)
+
+func ExampleBucketLifecycleFromConfig() {
+	for _, config := range []map[string]string{
+		{},
+		{"lifecycle.prefix": "logs/"},
+		{"lifecycle.expiry": "30", "lifecycle.prefix": "logs/"},
+		{"lifecycle.expiry.noncurrent": "7"},
+		{"lifecycle.expiry": "thirty"},
+	} {
+		bucketLifecycle, err := BucketLifecycleFromConfig(config)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+
+		if bucketLifecycle == nil {
+			fmt.Println("no rules")
+			continue
+		}
+
+		rule := bucketLifecycle.S3Configuration().Rules[0]
+		fmt.Printf("expiry=%d noncurrent=%d prefix=%q\n", rule.Expiration.Days, rule.NoncurrentVersionExpiration.NoncurrentDays, rule.RuleFilter.Prefix)
+	}
+
+	// Output: no rules
+	// no rules
+	// expiry=30 noncurrent=0 prefix="logs/"
+	// expiry=0 noncurrent=7 prefix=""
+	// Invalid value for "lifecycle.expiry": strconv.Atoi: parsing "thirty": invalid syntax
+}
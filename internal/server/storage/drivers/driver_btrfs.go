@@ -112,6 +112,7 @@ func (d *btrfs) Info() Info {
 		IOUring:                      true,
 		MountedRoot:                  true,
 		Buckets:                      true,
+		InstantCopy:                  true,
 	}
 }
 
@@ -325,8 +326,10 @@ func (d *btrfs) Delete(op *operations.Operation) error {
 // Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
 func (d *btrfs) Validate(config map[string]string) error {
 	rules := map[string]func(value string) error{
-		"size":                validate.Optional(validate.IsSize),
-		"btrfs.mount_options": validate.IsAny,
+		"size":                        validate.Optional(validate.IsSize),
+		"btrfs.mount_options":         validate.IsAny,
+		"maintenance.fstrim.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+		"maintenance.scrub.schedule":  validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
 	}
 
 	return d.validatePool(config, rules, nil)
@@ -487,6 +490,33 @@ func (d *btrfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return genericVFSGetResources(d)
 }
 
+// GetHealth returns the error counters of the devices of the filesystem and the status of its last scrub.
+func (d *btrfs) GetHealth() (*api.StoragePoolHealth, error) {
+	poolMntPath := GetPoolMountPath(d.name)
+
+	stats, err := subprocess.RunCommand("btrfs", "device", "stats", poolMntPath)
+	if err != nil {
+		return nil, err
+	}
+
+	scrub, err := subprocess.RunCommand("btrfs", "scrub", "status", poolMntPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return btrfsParseHealth(stats, scrub), nil
+}
+
+// Scrub starts a scrub of the filesystem in the background.
+func (d *btrfs) Scrub() error {
+	_, err := subprocess.RunCommand("btrfs", "scrub", "start", GetPoolMountPath(d.name))
+	if err != nil {
+		return fmt.Errorf("Failed scrubbing storage pool %q: %w", d.name, err)
+	}
+
+	return nil
+}
+
 // MigrationType returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *btrfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []localMigration.Type {
 	var rsyncFeatures []string
@@ -495,9 +525,9 @@ func (d *btrfs) MigrationTypes(contentType ContentType, refresh bool, copySnapsh
 	// Do not pass compression argument to rsync if the associated
 	// config key, that is rsync.compression, is set to false.
 	if util.IsFalse(d.Config()["rsync.compression"]) {
-		rsyncFeatures = []string{"xattrs", "delete", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	} else {
-		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	}
 
 	// Only offer rsync if running in an unprivileged container.
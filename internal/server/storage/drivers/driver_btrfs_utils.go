@@ -345,6 +345,40 @@ func (d *btrfs) getQGroup(path string) (string, int64, error) {
 	return qgroup, usage, nil
 }
 
+// getQGroupSizes returns the referenced and exclusive sizes of the qgroup of the subvolume.
+func (d *btrfs) getQGroupSizes(path string) (int64, int64, error) {
+	output, err := subprocess.RunCommand("btrfs", "qgroup", "show", "-e", "-f", "--raw", path)
+	if err != nil {
+		return -1, -1, errBtrfsNoQuota
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		// Use case-insensitive field title match because BTRFS tooling changed casing between versions.
+		if line == "" || strings.HasPrefix(strings.ToLower(line), "qgroupid") || strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		referenced, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return -1, -1, err
+		}
+
+		exclusive, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return -1, -1, err
+		}
+
+		return referenced, exclusive, nil
+	}
+
+	return -1, -1, errBtrfsNoQGroup
+}
+
 func (d *btrfs) sendSubvolume(path string, parent string, conn io.ReadWriteCloser, tracker *ioprogress.ProgressTracker) error {
 	defer func() { _ = conn.Close() }()
 
@@ -659,3 +693,52 @@ func (d *btrfs) receiveSubVolume(r io.Reader, receivePath string, tracker *iopro
 
 	return subVolPath, nil
 }
+
+// btrfsParseHealth returns the health of a filesystem from the output of "btrfs device stats" and "btrfs scrub status".
+// The filesystem is degraded if any of its error counters isn't zero or if its last scrub found errors.
+func btrfsParseHealth(stats string, scrub string) *api.StoragePoolHealth {
+	health := &api.StoragePoolHealth{
+		Messages: []string{},
+	}
+
+	// Counters are in the "[/dev/sda].write_io_errs    0" form.
+	for _, line := range strings.Split(stats, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[1] == "0" {
+			continue
+		}
+
+		device, counter, found := strings.Cut(fields[0], "].")
+		if !found {
+			continue
+		}
+
+		health.Messages = append(health.Messages, fmt.Sprintf("Device %q has %s %s", strings.TrimPrefix(device, "["), fields[1], counter))
+	}
+
+	// Scrub status is in the "Status:           finished" form.
+	fields := map[string]string{}
+	for _, line := range strings.Split(scrub, "\n") {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		fields[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	if fields["Status"] == "" {
+		health.Scrub = "none requested"
+	} else {
+		health.Scrub = fields["Status"]
+		if fields["Scrub started"] != "" {
+			health.Scrub += " (started " + fields["Scrub started"] + ")"
+		}
+	}
+
+	if fields["Error summary"] != "" && fields["Error summary"] != "no errors found" {
+		health.Messages = append(health.Messages, fmt.Sprintf("Scrub found errors: %s", fields["Error summary"]))
+	}
+
+	return health
+}
@@ -0,0 +1,36 @@
+package drivers
+
+import (
+	"fmt"
+)
+
+func Example_btrfsParseHealth() {
+	stats := `[/dev/sda].write_io_errs    0
+[/dev/sda].read_io_errs     0
+[/dev/sda].flush_io_errs    0
+[/dev/sda].corruption_errs  2
+[/dev/sda].generation_errs  0
+`
+
+	scrub := `UUID:             6e0a1b3c-7b1e-4b4a-9a3f-1f0b2c3d4e5f
+Scrub started:    Sun Oct 11 00:24:02 2026
+Status:           finished
+Duration:         0:00:12
+Total to scrub:   1.50GiB
+Rate:             128.00MiB/s
+Error summary:    csum=2
+  Corrected:      0
+  Uncorrectable:  2
+  Unverified:     0
+`
+
+	health := btrfsParseHealth(stats, scrub)
+	fmt.Println(health.Scrub)
+	for _, message := range health.Messages {
+		fmt.Println(message)
+	}
+
+	// Output: finished (started Sun Oct 11 00:24:02 2026)
+	// Device "/dev/sda" has 2 corruption_errs
+	// Scrub found errors: csum=2
+}
@@ -407,6 +407,51 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 	return nil, revertHook, nil
 }
 
+// AdoptVolume adopts an existing subvolume of the pool's filesystem as the volume.
+// The source is the path of the subvolume relative to the root of the pool.
+func (d *btrfs) AdoptVolume(vol Volume, source string, op *operations.Operation) (revert.Hook, error) {
+	if vol.volType != VolumeTypeCustom || vol.contentType != ContentTypeFS {
+		return nil, ErrNotSupported
+	}
+
+	if filepath.IsAbs(source) || isManagedPoolPath(source) {
+		return nil, fmt.Errorf("Subvolume path %q must be relative to the root of the pool and not managed by it", source)
+	}
+
+	srcPath := filepath.Join(GetPoolMountPath(d.name), source)
+	if !d.isSubvolume(srcPath) {
+		return nil, fmt.Errorf("Path %q isn't a subvolume", source)
+	}
+
+	out, err := subprocess.RunCommand("btrfs", "property", "get", "-ts", srcPath, "ro")
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(out) == "ro=true" {
+		return nil, fmt.Errorf("Subvolume %q is read-only", source)
+	}
+
+	volPath := vol.MountPath()
+	if util.PathExists(volPath) {
+		return nil, fmt.Errorf("Path %q already exists", volPath)
+	}
+
+	err = os.Rename(srcPath, volPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed moving subvolume %q: %w", source, err)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(func() { _ = os.Rename(volPath, srcPath) })
+
+	cleanup := reverter.Clone().Fail
+	reverter.Success()
+	return cleanup, nil
+}
+
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *btrfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	reverter := revert.New()
@@ -503,6 +548,11 @@ func (d *btrfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bo
 	return nil
 }
 
+// CreateVolumeFromClone creates the volume as a clone of the source, subvolume snapshots are always copy-on-write.
+func (d *btrfs) CreateVolumeFromClone(vol Volume, srcVol Volume, op *operations.Operation) error {
+	return d.CreateVolumeFromCopy(vol, srcVol, false, false, op)
+}
+
 // CreateVolumeFromMigration creates a volume being sent via a migration.
 func (d *btrfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs localMigration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
 	// Handle simple rsync and block_and_rsync through generic.
@@ -1029,6 +1079,25 @@ func (d *btrfs) GetVolumeUsage(vol Volume) (int64, error) {
 	return usage, nil
 }
 
+// GetVolumeSnapshotUsage returns the space used by the data of a snapshot and the space unique to it.
+// The values are the referenced and exclusive sizes of the qgroup of the snapshot.
+func (d *btrfs) GetVolumeSnapshotUsage(snapVol Volume) (int64, int64, error) {
+	if !snapVol.IsSnapshot() {
+		return -1, -1, ErrNotSupported
+	}
+
+	referenced, exclusive, err := d.getQGroupSizes(snapVol.MountPath())
+	if err != nil {
+		if errors.Is(err, errBtrfsNoQuota) {
+			return -1, -1, ErrNotSupported
+		}
+
+		return -1, -1, err
+	}
+
+	return referenced, exclusive, nil
+}
+
 // SetVolumeQuota applies a size limit on volume.
 // Does nothing if supplied with an empty/zero size for block volumes, and for filesystem volumes removes quota.
 func (d *btrfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
@@ -1181,6 +1250,17 @@ func (d *btrfs) GetVolumeDiskPath(vol Volume) (string, error) {
 	return genericVFSGetVolumeDiskPath(vol)
 }
 
+// TrimVolumes discards the unused blocks of the pool filesystem.
+// All volumes share the same filesystem, so it is trimmed as a whole.
+func (d *btrfs) TrimVolumes(vols []Volume, op *operations.Operation) error {
+	_, err := subprocess.RunCommand("fstrim", GetPoolMountPath(d.name))
+	if err != nil {
+		return fmt.Errorf("Failed trimming storage pool %q: %w", d.name, err)
+	}
+
+	return nil
+}
+
 // ListVolumes returns a list of volumes in storage pool.
 func (d *btrfs) ListVolumes() ([]Volume, error) {
 	return genericVFSListVolumes(d)
@@ -1,9 +1,6 @@
 package drivers
 
 import (
-	"bytes"
-	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os/exec"
@@ -97,6 +94,7 @@ func (d *ceph) Info() Info {
 		DirectIO:                     true,
 		IOUring:                      true,
 		MountedRoot:                  false,
+		InstantCopy:                  true,
 	}
 }
 
@@ -295,16 +293,18 @@ func (d *ceph) Delete(op *operations.Operation) error {
 // Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
 func (d *ceph) Validate(config map[string]string) error {
 	rules := map[string]func(value string) error{
-		"ceph.cluster_name":       validate.IsAny,
-		"ceph.osd.force_reuse":    validate.Optional(validate.IsBool), // Deprecated, should not be used.
-		"ceph.osd.pg_num":         validate.IsAny,
-		"ceph.osd.pool_name":      validate.IsAny,
-		"ceph.osd.data_pool_name": validate.IsAny,
-		"ceph.rbd.clone_copy":     validate.Optional(validate.IsBool),
-		"ceph.rbd.du":             validate.Optional(validate.IsBool),
-		"ceph.rbd.features":       validate.IsAny,
-		"ceph.user.name":          validate.IsAny,
-		"volatile.pool.pristine":  validate.IsAny,
+		"ceph.cluster_name":           validate.IsAny,
+		"ceph.osd.force_reuse":        validate.Optional(validate.IsBool), // Deprecated, should not be used.
+		"ceph.osd.pg_num":             validate.IsAny,
+		"ceph.osd.pool_name":          validate.IsAny,
+		"ceph.osd.data_pool_name":     validate.IsAny,
+		"ceph.rbd.clone_copy":         validate.Optional(validate.IsBool),
+		"ceph.rbd.du":                 validate.Optional(validate.IsBool),
+		"ceph.rbd.features":           validate.IsAny,
+		"ceph.user.name":              validate.IsAny,
+		"volatile.pool.pristine":      validate.IsAny,
+		"maintenance.fstrim.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+		"volume.activation":           validate.Optional(validate.IsOneOf("eager", "lazy")),
 	}
 
 	return d.validatePool(config, rules, d.commonVolumeRules())
@@ -338,60 +338,57 @@ func (d *ceph) Unmount() (bool, error) {
 
 // GetResources returns the pool resource usage information.
 func (d *ceph) GetResources() (*api.ResourcesStoragePool, error) {
-	var stdout bytes.Buffer
-
-	err := subprocess.RunCommandWithFds(context.TODO(), nil, &stdout,
-		"ceph",
-		"--name", fmt.Sprintf("client.%s", d.config["ceph.user.name"]),
-		"--cluster", d.config["ceph.cluster_name"],
-		"df",
-		"-f", "json")
+	stats, err := d.getOSDPoolStats()
 	if err != nil {
 		return nil, err
 	}
 
-	// Temporary structs for parsing.
-	type cephDfPoolStats struct {
-		BytesUsed      int64 `json:"bytes_used"`
-		BytesAvailable int64 `json:"max_avail"`
-	}
+	spaceUsed := uint64(stats.BytesUsed)
+	spaceAvailable := uint64(stats.BytesAvailable)
 
-	type cephDfPool struct {
-		Name  string          `json:"name"`
-		Stats cephDfPoolStats `json:"stats"`
-	}
+	res := api.ResourcesStoragePool{}
+	res.Space.Total = spaceAvailable + spaceUsed
+	res.Space.Used = spaceUsed
+	res.Statistics = stats.statistics()
 
-	type cephDf struct {
-		Pools []cephDfPool `json:"pools"`
-	}
+	return &res, nil
+}
 
-	// Parse the JSON output.
-	df := cephDf{}
-	err = json.NewDecoder(&stdout).Decode(&df)
+// GetStatistics returns the deduplication and compression statistics of the pool.
+// BlueStore doesn't deduplicate data, so only compression is reported.
+func (d *ceph) GetStatistics() (*api.StoragePoolStatistics, error) {
+	stats, err := d.getOSDPoolStats()
 	if err != nil {
 		return nil, err
 	}
 
-	var pool *cephDfPool
-	for _, entry := range df.Pools {
-		if entry.Name == d.config["ceph.osd.pool_name"] {
-			pool = &entry
-			break
-		}
+	return stats.statistics(), nil
+}
+
+// GetHealth returns the failed health checks of the Ceph cluster and whether scrubs are disabled on the OSD pool.
+// The cluster-wide scrub flags are reported by the OSDMAP_FLAGS health check.
+func (d *ceph) GetHealth() (*api.StoragePoolHealth, error) {
+	clusterHealth, err := d.getHealth()
+	if err != nil {
+		return nil, err
 	}
 
-	if pool == nil {
-		return nil, errors.New("OSD pool missing in df output")
+	health := &api.StoragePoolHealth{
+		Messages: clusterHealth.messages(),
 	}
 
-	spaceUsed := uint64(pool.Stats.BytesUsed)
-	spaceAvailable := uint64(pool.Stats.BytesAvailable)
+	for _, flag := range []string{"noscrub", "nodeep-scrub"} {
+		set, err := d.osdPoolFlag(flag)
+		if err != nil {
+			return nil, err
+		}
 
-	res := api.ResourcesStoragePool{}
-	res.Space.Total = spaceAvailable + spaceUsed
-	res.Space.Used = spaceUsed
+		if set {
+			health.Messages = append(health.Messages, fmt.Sprintf("The %q flag is set on OSD pool %q", flag, d.config["ceph.osd.pool_name"]))
+		}
+	}
 
-	return &res, nil
+	return health, nil
 }
 
 // MigrationType returns the type of transfer methods to be used when doing migrations between pools in preference order.
@@ -401,9 +398,9 @@ func (d *ceph) MigrationTypes(contentType ContentType, refresh bool, copySnapsho
 	// Do not pass compression argument to rsync if the associated
 	// config key, that is rsync.compression, is set to false.
 	if util.IsFalse(d.Config()["rsync.compression"]) {
-		rsyncFeatures = []string{"xattrs", "delete", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	} else {
-		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	}
 
 	if refresh {
@@ -415,12 +412,19 @@ func (d *ceph) MigrationTypes(contentType ContentType, refresh bool, copySnapsho
 			transportType = migration.MigrationFSType_RSYNC
 		}
 
-		return []localMigration.Type{
+		types := []localMigration.Type{
 			{
 				FSType:   transportType,
 				Features: rsyncFeatures,
 			},
 		}
+
+		// Prefer sending the changes since a common snapshot as a diff when possible.
+		if contentType == ContentTypeBlock || contentType == ContentTypeFS {
+			types = append([]localMigration.Type{{FSType: migration.MigrationFSType_RBD}}, types...)
+		}
+
+		return types
 	}
 
 	if contentType == ContentTypeBlock {
@@ -1,6 +1,8 @@
 package drivers
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -101,6 +103,151 @@ func (d *ceph) osdDeletePool() error {
 	return nil
 }
 
+// cephOSDPoolStats holds the statistics of an OSD pool as reported by "ceph df detail".
+type cephOSDPoolStats struct {
+	Stored         int64 `json:"stored"`
+	BytesUsed      int64 `json:"bytes_used"`
+	BytesAvailable int64 `json:"max_avail"`
+
+	// CompressBytesUsed is the space allocated for compressed data and CompressUnderBytes the size of that data
+	// before compression, both summed over all replicas.
+	CompressBytesUsed  int64 `json:"compress_bytes_used"`
+	CompressUnderBytes int64 `json:"compress_under_bytes"`
+}
+
+// statistics returns the deduplication and compression statistics of the OSD pool.
+func (s cephOSDPoolStats) statistics() *api.StoragePoolStatistics {
+	stats := api.StoragePoolStatistics{
+		DedupRatio:       1,
+		CompressionRatio: 1,
+		LogicalUsed:      uint64(s.Stored),
+		Used:             uint64(s.BytesUsed),
+	}
+
+	if s.BytesUsed > 0 && s.CompressBytesUsed > 0 {
+		stats.CompressionRatio = float64(s.BytesUsed-s.CompressBytesUsed+s.CompressUnderBytes) / float64(s.BytesUsed)
+	}
+
+	return &stats
+}
+
+// getOSDPoolStats returns the statistics of the OSD pool.
+func (d *ceph) getOSDPoolStats() (*cephOSDPoolStats, error) {
+	var stdout bytes.Buffer
+
+	err := subprocess.RunCommandWithFds(context.TODO(), nil, &stdout,
+		"ceph",
+		"--name", fmt.Sprintf("client.%s", d.config["ceph.user.name"]),
+		"--cluster", d.config["ceph.cluster_name"],
+		"df",
+		"detail",
+		"-f", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	// Temporary structs for parsing.
+	type cephDfPool struct {
+		Name  string           `json:"name"`
+		Stats cephOSDPoolStats `json:"stats"`
+	}
+
+	type cephDf struct {
+		Pools []cephDfPool `json:"pools"`
+	}
+
+	// Parse the JSON output.
+	df := cephDf{}
+	err = json.NewDecoder(&stdout).Decode(&df)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range df.Pools {
+		if entry.Name == d.config["ceph.osd.pool_name"] {
+			return &entry.Stats, nil
+		}
+	}
+
+	return nil, errors.New("OSD pool missing in df output")
+}
+
+// cephHealth represents the output of "ceph health detail".
+type cephHealth struct {
+	Status string `json:"status"`
+	Checks map[string]struct {
+		Summary struct {
+			Message string `json:"message"`
+		} `json:"summary"`
+	} `json:"checks"`
+}
+
+// messages returns the summary of the failed health checks, sorted by check name.
+func (h cephHealth) messages() []string {
+	names := make([]string, 0, len(h.Checks))
+	for name := range h.Checks {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	messages := make([]string, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, fmt.Sprintf("%s: %s", name, h.Checks[name].Summary.Message))
+	}
+
+	return messages
+}
+
+// getHealth returns the health of the Ceph cluster.
+func (d *ceph) getHealth() (*cephHealth, error) {
+	out, err := subprocess.RunCommand(
+		"ceph",
+		"--name", fmt.Sprintf("client.%s", d.config["ceph.user.name"]),
+		"--cluster", d.config["ceph.cluster_name"],
+		"health",
+		"detail",
+		"-f", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	health := cephHealth{}
+	err = json.Unmarshal([]byte(out), &health)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing Ceph health: %w", err)
+	}
+
+	return &health, nil
+}
+
+// osdPoolFlag returns whether the boolean flag is set on the OSD pool.
+func (d *ceph) osdPoolFlag(flag string) (bool, error) {
+	out, err := subprocess.RunCommand(
+		"ceph",
+		"--name", fmt.Sprintf("client.%s", d.config["ceph.user.name"]),
+		"--cluster", d.config["ceph.cluster_name"],
+		"osd",
+		"pool",
+		"get",
+		d.config["ceph.osd.pool_name"],
+		flag,
+		"-f", "json")
+	if err != nil {
+		return false, err
+	}
+
+	values := map[string]any{}
+	err = json.Unmarshal([]byte(out), &values)
+	if err != nil {
+		return false, fmt.Errorf("Failed parsing OSD pool flag %q: %w", flag, err)
+	}
+
+	value, _ := values[flag].(bool)
+
+	return value, nil
+}
+
 // rbdCreateVolume creates an RBD storage volume.
 // Note that the default set of features is intentionally limited
 // by passing --image-feature explicitly. This is done to ensure that
@@ -1335,3 +1482,182 @@ func (d *ceph) resizeVolume(vol Volume, sizeBytes int64, allowShrink bool) error
 
 	return err
 }
+
+// rbdMirror runs an "rbd mirror" command against the storage pool and returns its output.
+func (d *ceph) rbdMirror(args ...string) (string, error) {
+	cmd := append([]string{
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"mirror",
+	}, args...)
+
+	return subprocess.RunCommand("rbd", cmd...)
+}
+
+// rbdEnableVolumeMirror enables the mirroring of an RBD volume to the peer clusters of the pool, using the
+// mirroring mode and snapshot schedule of the volume configuration.
+func (d *ceph) rbdEnableVolumeMirror(vol Volume) error {
+	rbdName := d.getRBDVolumeName(vol, "", true)
+	mode := vol.ExpandedConfig("ceph.mirror.mode")
+	if mode == "" {
+		mode = "snapshot"
+	}
+
+	// Images can only be mirrored individually when the mirroring of the OSD pool is in "image" mode.
+	out, err := d.rbdMirror("pool", "info", "--format", "json", d.config["ceph.osd.pool_name"])
+	if err != nil {
+		return fmt.Errorf("Failed getting the mirroring mode of OSD pool %q: %w", d.config["ceph.osd.pool_name"], err)
+	}
+
+	poolInfo := struct {
+		Mode string `json:"mode"`
+	}{}
+
+	err = json.Unmarshal([]byte(out), &poolInfo)
+	if err != nil {
+		return err
+	}
+
+	switch poolInfo.Mode {
+	case "disabled":
+		_, err = d.rbdMirror("pool", "enable", d.config["ceph.osd.pool_name"], "image")
+		if err != nil {
+			return fmt.Errorf("Failed enabling mirroring of OSD pool %q: %w", d.config["ceph.osd.pool_name"], err)
+		}
+
+	case "pool":
+		return fmt.Errorf("Mirroring of all images of OSD pool %q is enabled, \"ceph.mirror\" can't be used", d.config["ceph.osd.pool_name"])
+	}
+
+	// Journal based mirroring needs the journaling feature of the image.
+	if mode == "journal" {
+		_, err = subprocess.RunCommand(
+			"rbd",
+			"--id", d.config["ceph.user.name"],
+			"--cluster", d.config["ceph.cluster_name"],
+			"feature", "enable", rbdName, "journaling")
+		if err != nil && !strings.Contains(err.Error(), "already enabled") {
+			return fmt.Errorf("Failed enabling journaling of RBD volume %q: %w", rbdName, err)
+		}
+	}
+
+	_, err = d.rbdMirror("image", "enable", rbdName, mode)
+	if err != nil {
+		return fmt.Errorf("Failed enabling mirroring of RBD volume %q: %w", rbdName, err)
+	}
+
+	schedule := vol.ExpandedConfig("ceph.mirror.schedule")
+	if mode == "snapshot" && schedule != "" {
+		_, err = d.rbdMirror("snapshot", "schedule", "add", "--pool", d.config["ceph.osd.pool_name"], "--image", CephGetRBDImageName(vol, "", false), schedule)
+		if err != nil {
+			return fmt.Errorf("Failed scheduling mirror snapshots of RBD volume %q: %w", rbdName, err)
+		}
+	}
+
+	return nil
+}
+
+// rbdDisableVolumeMirror disables the mirroring of an RBD volume, which removes it from the peer clusters.
+func (d *ceph) rbdDisableVolumeMirror(vol Volume) error {
+	rbdName := d.getRBDVolumeName(vol, "", true)
+
+	schedule := vol.ExpandedConfig("ceph.mirror.schedule")
+	if schedule != "" {
+		_, _ = d.rbdMirror("snapshot", "schedule", "remove", "--pool", d.config["ceph.osd.pool_name"], "--image", CephGetRBDImageName(vol, "", false), schedule)
+	}
+
+	_, err := d.rbdMirror("image", "disable", rbdName)
+	if err != nil {
+		return fmt.Errorf("Failed disabling mirroring of RBD volume %q: %w", rbdName, err)
+	}
+
+	return nil
+}
+
+// cephMirrorImageStatus is the output of "rbd mirror image status".
+type cephMirrorImageStatus struct {
+	State       string `json:"state"`
+	Description string `json:"description"`
+	PeerSites   []struct {
+		SiteName    string `json:"site_name"`
+		State       string `json:"state"`
+		Description string `json:"description"`
+		LastUpdate  string `json:"last_update"`
+	} `json:"peer_sites"`
+}
+
+// rbdGetVolumeMirror returns the mirroring state of an RBD volume.
+func (d *ceph) rbdGetVolumeMirror(vol Volume) (*api.StorageVolumeMirror, error) {
+	rbdName := d.getRBDVolumeName(vol, "", true)
+
+	out, err := subprocess.RunCommand(
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"info", "--format", "json", rbdName)
+	if err != nil {
+		return nil, err
+	}
+
+	info := struct {
+		Mirroring *struct {
+			Mode    string `json:"mode"`
+			State   string `json:"state"`
+			Primary bool   `json:"primary"`
+		} `json:"mirroring"`
+	}{}
+
+	err = json.Unmarshal([]byte(out), &info)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Mirroring == nil || info.Mirroring.State != "enabled" {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Mirroring isn't enabled on the volume")
+	}
+
+	out, err = d.rbdMirror("image", "status", "--format", "json", rbdName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting the mirroring status of RBD volume %q: %w", rbdName, err)
+	}
+
+	status := cephMirrorImageStatus{}
+	err = json.Unmarshal([]byte(out), &status)
+	if err != nil {
+		return nil, err
+	}
+
+	mirror := api.StorageVolumeMirror{
+		Mode:        info.Mirroring.Mode,
+		Primary:     info.Mirroring.Primary,
+		State:       status.State,
+		Description: status.Description,
+		Peers:       make([]api.StorageVolumeMirrorPeer, 0, len(status.PeerSites)),
+	}
+
+	for _, peer := range status.PeerSites {
+		mirror.Peers = append(mirror.Peers, api.StorageVolumeMirrorPeer{
+			Name:        peer.SiteName,
+			State:       peer.State,
+			Description: peer.Description,
+			LastUpdate:  peer.LastUpdate,
+		})
+	}
+
+	return &mirror, nil
+}
+
+// validateCephMirrorSchedule checks that the value is an interval of RBD mirror snapshots, in minutes ("m"),
+// hours ("h") or days ("d").
+func validateCephMirrorSchedule(value string) error {
+	if len(value) < 2 || !strings.ContainsAny(value[len(value)-1:], "mhd") {
+		return fmt.Errorf("Invalid mirror snapshot interval %q, expected a number of minutes, hours or days (for example, \"30m\")", value)
+	}
+
+	interval, err := strconv.ParseUint(value[:len(value)-1], 10, 32)
+	if err != nil || interval == 0 {
+		return fmt.Errorf("Invalid mirror snapshot interval %q, expected a number of minutes, hours or days (for example, \"30m\")", value)
+	}
+
+	return nil
+}
@@ -226,3 +226,19 @@ func Example_ceph_parseParent() {
 	//   contentType: filesystem
 	//   config: map[]
 }
+
+func Example_validateCephMirrorSchedule() {
+	for _, schedule := range []string{"30m", "1h", "7d", "", "m", "0h", "1w", "-1d", "1.5h"} {
+		fmt.Printf("%q: %v\n", schedule, validateCephMirrorSchedule(schedule) == nil)
+	}
+
+	// Output: "30m": true
+	// "1h": true
+	// "7d": true
+	// "": false
+	// "m": false
+	// "0h": false
+	// "1w": false
+	// "-1d": false
+	// "1.5h": false
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
 	"os/exec"
 	"strings"
@@ -286,6 +287,13 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Ope
 		}
 	}
 
+	if util.IsTrue(vol.ExpandedConfig("ceph.mirror")) {
+		err = d.rbdEnableVolumeMirror(vol)
+		if err != nil {
+			return err
+		}
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -324,6 +332,16 @@ func (d *ceph) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	return d.copyVolume(vol, srcVol, copySnapshots, false, op)
+}
+
+// CreateVolumeFromClone creates the volume as a clone of a new snapshot of the source, regardless of ceph.rbd.clone_copy.
+func (d *ceph) CreateVolumeFromClone(vol Volume, srcVol Volume, op *operations.Operation) error {
+	return d.copyVolume(vol, srcVol, false, true, op)
+}
+
+// copyVolume copies the source volume, always cloning a snapshot of the source when clone is set.
+func (d *ceph) copyVolume(vol Volume, srcVol Volume, copySnapshots bool, clone bool, op *operations.Operation) error {
 	var err error
 
 	reverter := revert.New()
@@ -371,7 +389,7 @@ func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots boo
 	if vol.IsVMBlock() {
 		srcFSVol := srcVol.NewVMBlockFilesystemVolume()
 		fsVol := vol.NewVMBlockFilesystemVolume()
-		err := d.CreateVolumeFromCopy(fsVol, srcFSVol, copySnapshots, false, op)
+		err := d.copyVolume(fsVol, srcFSVol, copySnapshots, clone, op)
 		if err != nil {
 			return err
 		}
@@ -392,7 +410,7 @@ func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots boo
 	// Copy without snapshots.
 	if !copySnapshots || len(snapshots) == 0 {
 		// If lightweight clone mode isn't enabled, perform a full copy of the volume.
-		if util.IsFalse(d.config["ceph.rbd.clone_copy"]) {
+		if !clone && util.IsFalse(d.config["ceph.rbd.clone_copy"]) {
 			_, err = subprocess.RunCommand(
 				"rbd",
 				"--id", d.config["ceph.user.name"],
@@ -454,6 +472,13 @@ func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots boo
 			return err
 		}
 
+		if util.IsTrue(vol.ExpandedConfig("ceph.mirror")) {
+			err = d.rbdEnableVolumeMirror(vol)
+			if err != nil {
+				return err
+			}
+		}
+
 		reverter.Success()
 		return nil
 	}
@@ -519,6 +544,13 @@ func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots boo
 		return err
 	}
 
+	if util.IsTrue(vol.ExpandedConfig("ceph.mirror")) {
+		err = d.rbdEnableVolumeMirror(vol)
+		if err != nil {
+			return err
+		}
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -569,6 +601,20 @@ func (d *ceph) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, vo
 		if err != nil {
 			return err
 		}
+	} else if volTargetArgs.Refresh && volTargetArgs.RefreshBase != "" {
+		// Discard the changes made since the common snapshot so that the incoming diffs apply on top of it.
+		_, err = subprocess.RunCommand(
+			"rbd",
+			"--id", d.config["ceph.user.name"],
+			"--cluster", d.config["ceph.cluster_name"],
+			"--pool", d.config["ceph.osd.pool_name"],
+			"snap",
+			"rollback",
+			"--snap", fmt.Sprintf("snapshot_%s", volTargetArgs.RefreshBase),
+			d.getRBDVolumeName(vol, "", false))
+		if err != nil {
+			return fmt.Errorf("Failed rolling back volume to snapshot %q for refresh: %w", volTargetArgs.RefreshBase, err)
+		}
 	}
 
 	err = vol.EnsureMountPath()
@@ -837,8 +883,12 @@ func (d *ceph) FillVolumeConfig(vol Volume) error {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *ceph) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
-		"block.filesystem":    validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
-		"block.mount_options": validate.IsAny,
+		"block.filesystem":     validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
+		"block.mount_options":  validate.IsAny,
+		"maintenance.fstrim":   validate.Optional(validate.IsBool),
+		"ceph.mirror":          validate.Optional(validate.IsBool),
+		"ceph.mirror.mode":     validate.Optional(validate.IsOneOf("journal", "snapshot")),
+		"ceph.mirror.schedule": validate.Optional(validateCephMirrorSchedule),
 	}
 }
 
@@ -853,6 +903,14 @@ func (d *ceph) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	if vol.IsVMBlock() || vol.volType == VolumeTypeCustom && vol.contentType == ContentTypeBlock {
 		delete(commonRules, "block.filesystem")
 		delete(commonRules, "block.mount_options")
+		delete(commonRules, "maintenance.fstrim")
+	}
+
+	// Only custom volumes can be mirrored, as instance volumes are usually clones of image volumes.
+	if vol.volType != VolumeTypeCustom {
+		delete(commonRules, "ceph.mirror")
+		delete(commonRules, "ceph.mirror.mode")
+		delete(commonRules, "ceph.mirror.schedule")
 	}
 
 	return d.validateVolume(vol, commonRules, removeUnknownKeys)
@@ -868,6 +926,78 @@ func (d *ceph) UpdateVolume(vol Volume, changedConfig map[string]string) error {
 		}
 	}
 
+	mirrorChanged := false
+	for _, key := range []string{"ceph.mirror", "ceph.mirror.mode", "ceph.mirror.schedule"} {
+		_, changed := changedConfig[key]
+		if changed {
+			mirrorChanged = true
+			break
+		}
+	}
+
+	if mirrorChanged {
+		newConfig := maps.Clone(vol.config)
+		for key, value := range changedConfig {
+			if value == "" {
+				delete(newConfig, key)
+			} else {
+				newConfig[key] = value
+			}
+		}
+
+		newVol := NewVolume(d, d.name, vol.volType, vol.contentType, vol.name, newConfig, vol.poolConfig)
+
+		// Re-apply the mirroring of the volume with its new settings.
+		if util.IsTrue(vol.ExpandedConfig("ceph.mirror")) {
+			if util.IsTrue(newVol.ExpandedConfig("ceph.mirror")) && vol.ExpandedConfig("ceph.mirror.mode") != newVol.ExpandedConfig("ceph.mirror.mode") {
+				return errors.New(`The "ceph.mirror.mode" property can't be changed while the volume is mirrored`)
+			}
+
+			err := d.rbdDisableVolumeMirror(vol)
+			if err != nil {
+				return err
+			}
+		}
+
+		if util.IsTrue(newVol.ExpandedConfig("ceph.mirror")) {
+			err := d.rbdEnableVolumeMirror(newVol)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetVolumeMirror returns the mirroring state of the volume.
+func (d *ceph) GetVolumeMirror(vol Volume) (*api.StorageVolumeMirror, error) {
+	return d.rbdGetVolumeMirror(vol)
+}
+
+// PromoteVolume makes the local copy of the mirrored volume the primary one.
+// With force, the volume is promoted even if the current primary copy can't be demoted.
+func (d *ceph) PromoteVolume(vol Volume, force bool, op *operations.Operation) error {
+	args := []string{"image", "promote"}
+	if force {
+		args = append(args, "--force")
+	}
+
+	_, err := d.rbdMirror(append(args, d.getRBDVolumeName(vol, "", true))...)
+	if err != nil {
+		return fmt.Errorf("Failed promoting RBD volume %q: %w", d.getRBDVolumeName(vol, "", true), err)
+	}
+
+	return nil
+}
+
+// DemoteVolume makes the local copy of the mirrored volume a secondary one.
+func (d *ceph) DemoteVolume(vol Volume, op *operations.Operation) error {
+	_, err := d.rbdMirror("image", "demote", d.getRBDVolumeName(vol, "", true))
+	if err != nil {
+		return fmt.Errorf("Failed demoting RBD volume %q: %w", d.getRBDVolumeName(vol, "", true), err)
+	}
+
 	return nil
 }
 
@@ -1043,10 +1173,6 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 			if sizeBytes < oldSizeBytes {
 				return fmt.Errorf("Block volumes cannot be shrunk: %w", ErrCannotBeShrunk)
 			}
-
-			if inUse {
-				return ErrInUse // We don't allow online resizing of block volumes.
-			}
 		}
 
 		// Resize block device.
@@ -1071,13 +1197,20 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 // GetVolumeDiskPath returns the location of a root disk block device.
 func (d *ceph) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
-		_, devPath, err := d.getRBDMappedDevPath(vol, false)
+		// Map mounted volumes which were left unmapped by lazy activation.
+		// They get unmapped by UnmountVolume.
+		_, devPath, err := d.getRBDMappedDevPath(vol, d.lazyActivation(vol) && vol.MountInUse())
 		return devPath, err
 	}
 
 	return "", ErrNotSupported
 }
 
+// TrimVolumes discards the unused blocks of the mounted volumes so that their space is released in the OSD pool.
+func (d *ceph) TrimVolumes(vols []Volume, op *operations.Operation) error {
+	return fstrimVolumes(vols)
+}
+
 // ListVolumes returns a list of volumes in storage pool.
 func (d *ceph) ListVolumes() ([]Volume, error) {
 	vols := make(map[string]Volume)
@@ -1194,13 +1327,19 @@ func (d *ceph) MountVolume(vol Volume, op *operations.Operation) error {
 	defer reverter.Fail()
 
 	// Activate RBD volume if needed.
-	activated, volDevPath, err := d.getRBDMappedDevPath(vol, true)
-	if err != nil {
-		return err
-	}
+	// With lazy activation, block volumes are only mapped once their disk path is requested.
+	var volDevPath string
+	if !d.lazyActivation(vol) {
+		var activated bool
+
+		activated, volDevPath, err = d.getRBDMappedDevPath(vol, true)
+		if err != nil {
+			return err
+		}
 
-	if activated {
-		reverter.Add(func() { _ = d.rbdUnmapVolume(vol, true) })
+		if activated {
+			reverter.Add(func() { _ = d.rbdUnmapVolume(vol, true) })
+		}
 	}
 
 	if vol.contentType == ContentTypeFS {
@@ -1418,12 +1557,16 @@ func (d *ceph) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *lo
 		return nil
 	}
 
+	// When refreshing, only the changes since the snapshot held by both sides are sent.
 	lastSnap := ""
+	if volSrcArgs.Refresh && volSrcArgs.RefreshBase != "" {
+		lastSnap = fmt.Sprintf("snapshot_%s", volSrcArgs.RefreshBase)
+	}
 
 	for i, snapName := range volSrcArgs.Snapshots {
 		snapshot, _ := vol.NewSnapshot(snapName)
 
-		prev := ""
+		prev := lastSnap
 
 		if i > 0 {
 			prev = fmt.Sprintf("snapshot_%s", volSrcArgs.Snapshots[i-1])
@@ -189,6 +189,9 @@ func (d *cephfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser,
 		_ = os.RemoveAll(volPath)
 	}()
 
+	// Receive over any additional connection agreed on with the source.
+	conns := append([]io.ReadWriteCloser{conn}, volTargetArgs.Streams...)
+
 	// Ensure the volume is mounted.
 	err = vol.MountTask(func(mountPath string, op *operations.Operation) error {
 		path := internalUtil.AddSlash(mountPath)
@@ -201,7 +204,7 @@ func (d *cephfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser,
 				wrapper = localMigration.ProgressTracker(op, "fs_progress", snapshot.GetName())
 			}
 
-			err = rsync.Recv(path, conn, wrapper, volTargetArgs.MigrationType.Features)
+			err = rsync.RecvParallel(path, conns, wrapper, volTargetArgs.MigrationType.Features)
 			if err != nil {
 				return err
 			}
@@ -233,7 +236,7 @@ func (d *cephfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser,
 			wrapper = localMigration.ProgressTracker(op, "fs_progress", vol.name)
 		}
 
-		return rsync.Recv(path, conn, wrapper, volTargetArgs.MigrationType.Features)
+		return rsync.RecvParallel(path, conns, wrapper, volTargetArgs.MigrationType.Features)
 	}, op)
 	if err != nil {
 		return err
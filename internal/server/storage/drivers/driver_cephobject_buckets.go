@@ -195,6 +195,33 @@ func (d *cephobject) UpdateBucket(bucket Volume, changedConfig map[string]string
 	return nil
 }
 
+// SetBucketLifecycle sets the lifecycle rules of a bucket (nil removes them).
+func (d *cephobject) SetBucketLifecycle(bucket Volume, lifecycle *BucketLifecycle, op *operations.Operation) error {
+	_, bucketName := project.StorageVolumeParts(bucket.name)
+	storageBucketName := d.radosgwBucketName(bucketName)
+
+	// Use the bucket user as it owns the bucket.
+	bucketUserInfo, _, err := d.radosgwadminGetUser(context.TODO(), storageBucketName)
+	if err != nil {
+		return fmt.Errorf("Failed getting bucket user: %w", err)
+	}
+
+	minioClient, err := d.s3Client(*bucketUserInfo)
+	if err != nil {
+		return err
+	}
+
+	ctx, ctxCancel := context.WithTimeout(context.TODO(), time.Duration(time.Second*30))
+	defer ctxCancel()
+
+	err = minioClient.SetBucketLifecycle(ctx, storageBucketName, lifecycle.S3Configuration())
+	if err != nil {
+		return fmt.Errorf("Failed setting bucket lifecycle: %w", err)
+	}
+
+	return nil
+}
+
 // bucketKeyRadosgwAccessRole returns the radosgw access setting for the specified role name.
 func (d *cephobject) bucketKeyRadosgwAccessRole(roleName string) (string, error) {
 	switch roleName {
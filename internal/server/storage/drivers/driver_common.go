@@ -19,6 +19,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/subprocess"
@@ -110,6 +111,11 @@ func (d *common) fillVolumeConfig(vol *Volume, excludedKeys ...string) error {
 			continue
 		}
 
+		// volume.activation is a pool wide option rather than a volume default.
+		if volKey == "activation" {
+			continue
+		}
+
 		// If volume type is not custom or bucket, don't copy "size" property to volume config.
 		if (vol.volType != VolumeTypeCustom && vol.volType != VolumeTypeBucket) && volKey == "size" {
 			continue
@@ -125,6 +131,11 @@ func (d *common) fillVolumeConfig(vol *Volume, excludedKeys ...string) error {
 			continue
 		}
 
+		// block.encryption and block.encryption.key are only relevant for block volumes of virtual machines and custom block volumes.
+		if !volumeEncryptable(*vol) && strings.HasPrefix(volKey, "block.encryption") {
+			continue
+		}
+
 		if vol.config[volKey] == "" {
 			vol.config[volKey] = d.config[k]
 		}
@@ -201,9 +212,9 @@ func (d *common) MigrationTypes(contentType ContentType, refresh bool, copySnaps
 	// Do not pass compression argument to rsync if the associated
 	// config key, that is rsync.compression, is set to false.
 	if util.IsFalse(d.Config()["rsync.compression"]) {
-		rsyncFeatures = []string{"xattrs", "delete", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	} else {
-		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	}
 
 	if IsContentBlock(contentType) {
@@ -342,11 +353,21 @@ func (d *common) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots b
 	return ErrNotSupported
 }
 
+// CreateVolumeFromClone creates a new volume as a copy-on-write clone of an existing storage volume.
+func (d *common) CreateVolumeFromClone(vol Volume, srcVol Volume, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
 // CreateVolumeFromMigration creates a new volume (with or without snapshots) from a migration data stream.
 func (d *common) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs localMigration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
 	return ErrNotSupported
 }
 
+// AdoptVolume adopts an existing source of the pool's storage as the volume.
+func (d *common) AdoptVolume(vol Volume, source string, op *operations.Operation) (revert.Hook, error) {
+	return nil, ErrNotSupported
+}
+
 // RefreshVolume updates an existing volume to match the state of another.
 func (d *common) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
 	return ErrNotSupported
@@ -377,6 +398,11 @@ func (d *common) GetVolumeUsage(vol Volume) (int64, error) {
 	return -1, ErrNotSupported
 }
 
+// GetVolumeSnapshotUsage returns the space used by the data of a snapshot and the space unique to it.
+func (d *common) GetVolumeSnapshotUsage(snapVol Volume) (int64, int64, error) {
+	return -1, -1, ErrNotSupported
+}
+
 // SetVolumeQuota applies a size limit on volume.
 func (d *common) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
 	return ErrNotSupported
@@ -392,11 +418,58 @@ func (d *common) ListVolumes() ([]Volume, error) {
 	return nil, ErrNotSupported
 }
 
+// GetStatistics returns ErrNotSupported as the deduplication and compression statistics aren't known by default.
+func (d *common) GetStatistics() (*api.StoragePoolStatistics, error) {
+	return nil, ErrNotSupported
+}
+
+// GetHealth returns ErrNotSupported as the health of the storage isn't known by default.
+func (d *common) GetHealth() (*api.StoragePoolHealth, error) {
+	return nil, ErrNotSupported
+}
+
+// Scrub returns ErrNotSupported as the integrity of the data can't be checked by default.
+func (d *common) Scrub() error {
+	return ErrNotSupported
+}
+
+// TrimVolumes discards the unused blocks of the volumes.
+func (d *common) TrimVolumes(vols []Volume, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
+// GetVolumeMirror returns ErrNotSupported as volumes can't be mirrored by default.
+func (d *common) GetVolumeMirror(vol Volume) (*api.StorageVolumeMirror, error) {
+	return nil, ErrNotSupported
+}
+
+// PromoteVolume returns ErrNotSupported as volumes can't be mirrored by default.
+func (d *common) PromoteVolume(vol Volume, force bool, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
+// DemoteVolume returns ErrNotSupported as volumes can't be mirrored by default.
+func (d *common) DemoteVolume(vol Volume, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
+// VolumeSnapshotDiff compares two states of the volume through the generic implementation.
+func (d *common) VolumeSnapshotDiff(vol Volume, snapA string, snapB string, fn func(change api.StorageVolumeDiffEntry) error, op *operations.Operation) error {
+	return genericVFSVolumeSnapshotDiff(vol, snapA, snapB, fn, op)
+}
+
 // MountVolume sets up the volume for use.
 func (d *common) MountVolume(vol Volume, op *operations.Operation) error {
 	return ErrNotSupported
 }
 
+// lazyActivation returns whether the block device of the volume is only activated once its path is requested,
+// rather than when the volume is mounted. This is controlled by the volume.activation pool option and doesn't
+// apply to encrypted volumes, as their LUKS container is opened on mount.
+func (d *common) lazyActivation(vol Volume) bool {
+	return d.config["volume.activation"] == "lazy" && vol.contentType == ContentTypeBlock && !volumeEncrypted(vol)
+}
+
 // UnmountVolume clears any runtime state for the volume.
 // As driver doesn't have volumes to unmount it returns false indicating the volume was already unmounted.
 func (d *common) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operation) (bool, error) {
@@ -502,6 +575,16 @@ func (d *common) UpdateBucket(bucket Volume, changedConfig map[string]string) er
 	return ErrNotSupported
 }
 
+// SetBucketLifecycle sets the lifecycle rules of a bucket (nil removes them).
+func (d *common) SetBucketLifecycle(bucket Volume, lifecycle *BucketLifecycle, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
+// SetBucketReplication sets the replication of a bucket (nil removes it).
+func (d *common) SetBucketReplication(bucket Volume, replication *BucketReplication, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
 // ValidateBucketKey validates the supplied bucket key config.
 func (d *common) ValidateBucketKey(keyName string, creds S3Credentials, roleName string) error {
 	if keyName == "" {
@@ -1,7 +1,9 @@
 package drivers
 
 import (
+	"context"
 	"fmt"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/subprocess"
 	"github.com/lxc/incus/v6/shared/util"
 )
 
@@ -115,6 +118,13 @@ func (d *dir) Delete(op *operations.Operation) error {
 
 // Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
 func (d *dir) Validate(config map[string]string) error {
+	// The dir driver doesn't manage the block device it is stored on, so it can't attach a cache to it.
+	for k := range config {
+		if strings.HasPrefix(k, "lvm.cache.") {
+			return fmt.Errorf("Caching (%q) isn't supported by the dir driver, set it up on the underlying block device instead", k)
+		}
+	}
+
 	return d.validatePool(config, nil, nil)
 }
 
@@ -164,3 +174,26 @@ func (d *dir) Unmount() (bool, error) {
 func (d *dir) GetResources() (*api.ResourcesStoragePool, error) {
 	return genericVFSGetResources(d)
 }
+
+// GetHealth returns the SMART health of the disk backing the storage pool.
+func (d *dir) GetHealth() (*api.StoragePoolHealth, error) {
+	_, err := exec.LookPath("smartctl")
+	if err != nil {
+		return nil, ErrNotSupported
+	}
+
+	devPath, err := smartDevice(GetPoolMountPath(d.name))
+	if err != nil {
+		return nil, err
+	}
+
+	// The exit status of smartctl is a bit mask that is also set for disk problems, so only its output is used.
+	out, _, _ := subprocess.RunCommandSplit(context.TODO(), nil, nil, "smartctl", "-H", "-j", devPath)
+
+	messages, err := smartParseHealth(devPath, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.StoragePoolHealth{Messages: messages}, nil
+}
@@ -1,12 +1,23 @@
 package drivers
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"path/filepath"
 
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
 	"github.com/lxc/incus/v6/internal/server/storage/quota"
+	"github.com/lxc/incus/v6/internal/server/warnings"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 // withoutGetVolID returns a copy of this struct but with a volIDFunc which will cause quotas to be skipped.
@@ -107,11 +118,16 @@ func (d *dir) setQuota(path string, volID int64, sizeBytes int64) error {
 		if sizeBytes > 0 {
 			// Skipping quota as underlying filesystem doesn't support project quotas.
 			d.logger.Warn("The backing filesystem doesn't support quotas, skipping set quota", logger.Ctx{"path": path, "size": sizeBytes, "volID": volID})
+			d.reportQuotaEnforcement(false)
 		}
 
 		return nil
 	}
 
+	if sizeBytes > 0 {
+		d.reportQuotaEnforcement(true)
+	}
+
 	projectID := d.quotaProjectID(volID)
 	currentProjectID, err := quota.GetProject(path)
 	if err != nil {
@@ -134,3 +150,88 @@ func (d *dir) setQuota(path string, volID int64, sizeBytes int64) error {
 	// Set the project quota size.
 	return quota.SetProjectQuota(path, projectID, sizeBytes)
 }
+
+// reportQuotaEnforcement records a warning on the storage pool when volume size limits can't be enforced,
+// and resolves it once they can.
+func (d *dir) reportQuotaEnforcement(enforced bool) {
+	if d.state == nil || d.state.DB.Cluster == nil {
+		return
+	}
+
+	var poolID int64
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		poolID, err = tx.GetStoragePoolID(ctx, d.name)
+		if err != nil {
+			return err
+		}
+
+		if enforced {
+			return nil
+		}
+
+		return tx.UpsertWarningLocalNode(ctx, "", dbCluster.TypeStoragePool, int(poolID), warningtype.StorageVolumeQuotaNotEnforced, "The backing filesystem doesn't support project quotas (ext4 and xfs need the prjquota mount option)")
+	})
+	if err != nil {
+		d.logger.Warn("Failed recording quota enforcement warning", logger.Ctx{"err": err})
+		return
+	}
+
+	if enforced {
+		_ = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(d.state.DB.Cluster, "", warningtype.StorageVolumeQuotaNotEnforced, dbCluster.TypeStoragePool, int(poolID))
+	}
+}
+
+// smartDevice returns the disk backing the path, if it is a physical disk that can report its SMART health.
+func smartDevice(path string) (string, error) {
+	var stat unix.Stat_t
+
+	err := unix.Stat(path, &stat)
+	if err != nil {
+		return "", err
+	}
+
+	// Filesystems without a backing block device (tmpfs, btrfs subvolumes...) have no entry here.
+	devPath, err := filepath.EvalSymlinks(fmt.Sprintf("/sys/dev/block/%d:%d", unix.Major(stat.Dev), unix.Minor(stat.Dev)))
+	if err != nil {
+		return "", ErrNotSupported
+	}
+
+	// Partitions report the health of their disk.
+	if util.PathExists(filepath.Join(devPath, "partition")) {
+		devPath = filepath.Dir(devPath)
+	}
+
+	// Virtual block devices (loop, device mapper...) have no underlying device.
+	if !util.PathExists(filepath.Join(devPath, "device")) {
+		return "", ErrNotSupported
+	}
+
+	return filepath.Join("/dev", filepath.Base(devPath)), nil
+}
+
+// smartParseHealth returns the problems reported in the JSON output of "smartctl -H -j".
+func smartParseHealth(devPath string, out string) ([]string, error) {
+	var smart struct {
+		SmartStatus *struct {
+			Passed bool `json:"passed"`
+		} `json:"smart_status"`
+	}
+
+	err := json.Unmarshal([]byte(out), &smart)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing SMART health of %q: %w", devPath, err)
+	}
+
+	// Devices without SMART support don't report any status.
+	if smart.SmartStatus == nil {
+		return nil, ErrNotSupported
+	}
+
+	if !smart.SmartStatus.Passed {
+		return []string{fmt.Sprintf("SMART health check of device %q failed", devPath)}, nil
+	}
+
+	return []string{}, nil
+}
@@ -0,0 +1,16 @@
+package drivers
+
+import (
+	"fmt"
+)
+
+func Example_smartParseHealth() {
+	for _, out := range []string{`{"smart_status": {"passed": true}}`, `{"smart_status": {"passed": false}}`, `{"device": {"name": "/dev/sda"}}`} {
+		messages, err := smartParseHealth("/dev/sda", out)
+		fmt.Println(messages, err)
+	}
+
+	// Output: [] <nil>
+	// [SMART health check of device "/dev/sda" failed] <nil>
+	// [] Not supported
+}
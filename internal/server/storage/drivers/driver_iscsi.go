@@ -0,0 +1,241 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+var (
+	iscsiVersion string
+	iscsiLoaded  bool
+)
+
+// iscsi consumes LUNs of an iSCSI target for block volumes. The LUNs are either pre-provisioned on the
+// storage array and assigned to volumes through the "iscsi.lun" volume option, or allocated by a provisioning
+// hook. The filesystem volumes of virtual machines are kept as directories on the host.
+type iscsi struct {
+	common
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *iscsi) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+	}
+
+	// Done if previously loaded.
+	if iscsiLoaded {
+		return nil
+	}
+
+	// Validate the required binaries.
+	_, err := exec.LookPath("iscsiadm")
+	if err != nil {
+		return errors.New("Required tool 'iscsiadm' is missing")
+	}
+
+	// Detect and record the version, "iscsiadm version 2.1.8".
+	if iscsiVersion == "" {
+		out, err := subprocess.RunCommand("iscsiadm", "--version")
+		if err != nil {
+			return err
+		}
+
+		fields := strings.Fields(strings.TrimSpace(out))
+		if len(fields) > 0 {
+			iscsiVersion = fields[len(fields)-1]
+		}
+	}
+
+	iscsiLoaded = true
+	return nil
+}
+
+// Info returns info about the driver and its environment.
+func (d *iscsi) Info() Info {
+	return Info{
+		Name:                         "iscsi",
+		Version:                      iscsiVersion,
+		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeVM},
+		VolumeMultiNode:              d.isRemote(),
+		BlockBacking:                 false,
+		RunningCopyFreeze:            true,
+		DirectIO:                     true,
+		IOUring:                      true,
+		MountedRoot:                  false,
+		Buckets:                      false,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *iscsi) FillConfig() error {
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *iscsi) Create() error {
+	err := d.FillConfig()
+	if err != nil {
+		return err
+	}
+
+	// Discover the target through the portal to check that it's reachable.
+	_, err = subprocess.RunCommand("iscsiadm", "-m", "discovery", "-t", "sendtargets", "-p", iscsiPortal(d.config["source"]))
+	if err != nil {
+		return fmt.Errorf("Failed discovering iSCSI targets on %q: %w", d.config["source"], err)
+	}
+
+	found, err := d.nodeExists()
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("iSCSI target %q isn't available on portal %q", d.config["iscsi.target"], d.config["source"])
+	}
+
+	_, err = d.Mount()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the storage pool from the storage device.
+func (d *iscsi) Delete(op *operations.Operation) error {
+	// Log out of the target.
+	_, err := d.Unmount()
+	if err != nil {
+		return err
+	}
+
+	// Wipe everything in the pool directory, which only holds the LUN assignments and the
+	// filesystem volumes of virtual machines.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provided keys are supported and that no conflicting or missing configuration is present.
+func (d *iscsi) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		"source":                  validate.Required(validateISCSIPortal),
+		"iscsi.target":            validate.Required(validateISCSITarget),
+		"iscsi.provisioning_hook": validate.Optional(validate.IsAbsFilePath),
+		"iscsi.chap.username":     validate.IsAny,
+		"iscsi.chap.password":     validate.IsAny,
+	}
+
+	return d.validatePool(config, rules, nil)
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *iscsi) Update(changedConfig map[string]string) error {
+	for _, key := range []string{"source", "iscsi.target"} {
+		_, changed := changedConfig[key]
+		if changed {
+			return fmt.Errorf("Storage pool %q can't be changed", key)
+		}
+	}
+
+	return nil
+}
+
+// Mount logs into the iSCSI target.
+func (d *iscsi) Mount() (bool, error) {
+	err := os.MkdirAll(filepath.Join(GetPoolMountPath(d.name), iscsiLUNDirectory), 0o700)
+	if err != nil {
+		return false, fmt.Errorf("Failed creating LUN assignment directory: %w", err)
+	}
+
+	active, err := d.sessionActive()
+	if err != nil {
+		return false, err
+	}
+
+	if active {
+		return false, nil
+	}
+
+	err = d.login()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unmount logs out of the iSCSI target.
+func (d *iscsi) Unmount() (bool, error) {
+	active, err := d.sessionActive()
+	if err != nil {
+		return false, err
+	}
+
+	if !active {
+		return false, nil
+	}
+
+	_, err = subprocess.RunCommand("iscsiadm", d.nodeArgs("--logout")...)
+	if err != nil {
+		return false, fmt.Errorf("Failed logging out of iSCSI target %q: %w", d.config["iscsi.target"], err)
+	}
+
+	return true, nil
+}
+
+// GetResources returns the pool resource usage information.
+// The total space is the size of all the LUNs of the target and the used space is the size of the LUNs
+// assigned to volumes.
+func (d *iscsi) GetResources() (*api.ResourcesStoragePool, error) {
+	devices, err := filepath.Glob(d.lunDevicePath("*"))
+	if err != nil {
+		return nil, err
+	}
+
+	assigned, err := d.assignedLUNs()
+	if err != nil {
+		return nil, err
+	}
+
+	res := api.ResourcesStoragePool{}
+	for _, devPath := range devices {
+		sizeBytes, err := BlockDiskSizeBytes(devPath)
+		if err != nil {
+			continue
+		}
+
+		res.Space.Total += uint64(sizeBytes)
+
+		_, ok := assigned[devPath]
+		if ok {
+			res.Space.Used += uint64(sizeBytes)
+		}
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,297 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// iscsiLUNDirectory is the directory under the pool mount path holding the LUN assignment of the volumes.
+// Each volume has a symlink to its LUN device at the same relative path as its mount path.
+const iscsiLUNDirectory = "iscsi"
+
+// iscsiDefaultPort is the default iSCSI portal port.
+const iscsiDefaultPort = "3260"
+
+// iscsiHookNotSupported is the exit status of the provisioning hook for unsupported actions.
+const iscsiHookNotSupported = 2
+
+// iscsiPortal returns the portal address in the "HOST:PORT" form, adding the default port if missing.
+func iscsiPortal(source string) string {
+	_, _, err := net.SplitHostPort(source)
+	if err == nil {
+		return source
+	}
+
+	return net.JoinHostPort(strings.Trim(source, "[]"), iscsiDefaultPort)
+}
+
+// validateISCSIPortal checks that the value is an iSCSI portal in the "HOST[:PORT]" form.
+func validateISCSIPortal(value string) error {
+	host, _, err := net.SplitHostPort(iscsiPortal(value))
+	if err != nil || host == "" {
+		return fmt.Errorf(`Invalid iSCSI portal %q, expected "HOST[:PORT]"`, value)
+	}
+
+	return nil
+}
+
+// validateISCSITarget checks that the value is an iSCSI qualified name.
+func validateISCSITarget(value string) error {
+	for _, prefix := range []string{"iqn.", "eui.", "naa."} {
+		if strings.HasPrefix(value, prefix) && len(value) > len(prefix) && !strings.ContainsAny(value, " /\t") {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid iSCSI target name %q", value)
+}
+
+// nodeArgs returns the iscsiadm arguments to act on the pool's target with the extra arguments.
+func (d *iscsi) nodeArgs(extra ...string) []string {
+	args := []string{"-m", "node", "-T", d.config["iscsi.target"], "-p", iscsiPortal(d.config["source"])}
+	return append(args, extra...)
+}
+
+// nodeExists checks whether the pool's target was discovered on the portal.
+func (d *iscsi) nodeExists() (bool, error) {
+	out, err := subprocess.RunCommand("iscsiadm", "-m", "node")
+	if err != nil {
+		// No node records.
+		status, _ := linux.ExitStatus(err)
+		if status == 21 {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return iscsiOutputHasTarget(out, iscsiPortal(d.config["source"]), d.config["iscsi.target"]), nil
+}
+
+// sessionActive checks whether a session is logged into the pool's target.
+func (d *iscsi) sessionActive() (bool, error) {
+	out, err := subprocess.RunCommand("iscsiadm", "-m", "session")
+	if err != nil {
+		// No active sessions.
+		status, _ := linux.ExitStatus(err)
+		if status == 21 {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return iscsiOutputHasTarget(out, iscsiPortal(d.config["source"]), d.config["iscsi.target"]), nil
+}
+
+// iscsiOutputHasTarget checks whether the iscsiadm node or session output contains the portal and target.
+// Node records are in the "PORTAL,TPGT TARGET" form and sessions in the "tcp: [ID] PORTAL,TPGT TARGET (non-flash)" form.
+func iscsiOutputHasTarget(out string, portal string, target string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			if i+1 >= len(fields) || fields[i+1] != target {
+				continue
+			}
+
+			fieldPortal, _, _ := strings.Cut(field, ",")
+			if fieldPortal == portal {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// login discovers the pool's target, configures the CHAP authentication and logs into it.
+func (d *iscsi) login() error {
+	portal := iscsiPortal(d.config["source"])
+
+	_, err := subprocess.RunCommand("iscsiadm", "-m", "discovery", "-t", "sendtargets", "-p", portal)
+	if err != nil {
+		return fmt.Errorf("Failed discovering iSCSI targets on %q: %w", d.config["source"], err)
+	}
+
+	if d.config["iscsi.chap.username"] != "" {
+		settings := [][]string{
+			{"node.session.auth.authmethod", "CHAP"},
+			{"node.session.auth.username", d.config["iscsi.chap.username"]},
+			{"node.session.auth.password", d.config["iscsi.chap.password"]},
+		}
+
+		for _, setting := range settings {
+			_, err := subprocess.RunCommand("iscsiadm", d.nodeArgs("-o", "update", "-n", setting[0], "-v", setting[1])...)
+			if err != nil {
+				return fmt.Errorf("Failed configuring %q of iSCSI target %q: %w", setting[0], d.config["iscsi.target"], err)
+			}
+		}
+	}
+
+	_, err = subprocess.RunCommand("iscsiadm", d.nodeArgs("--login")...)
+	if err != nil {
+		return fmt.Errorf("Failed logging into iSCSI target %q: %w", d.config["iscsi.target"], err)
+	}
+
+	return nil
+}
+
+// lunDevicePath returns the path of the block device of a LUN of the pool's target.
+func (d *iscsi) lunDevicePath(lun string) string {
+	return fmt.Sprintf("/dev/disk/by-path/ip-%s-iscsi-%s-lun-%s", iscsiPortal(d.config["source"]), d.config["iscsi.target"], lun)
+}
+
+// waitLUN rescans the pool's target and waits for the block device of the LUN to appear.
+func (d *iscsi) waitLUN(lun string) (string, error) {
+	_, err := subprocess.RunCommand("iscsiadm", d.nodeArgs("-R")...)
+	if err != nil {
+		return "", fmt.Errorf("Failed rescanning iSCSI target %q: %w", d.config["iscsi.target"], err)
+	}
+
+	devPath := d.lunDevicePath(lun)
+	if !tryExists(devPath) {
+		return "", fmt.Errorf("LUN %s of iSCSI target %q didn't appear", lun, d.config["iscsi.target"])
+	}
+
+	return devPath, nil
+}
+
+// lunLinkPath returns the path of the symlink recording the LUN assigned to the volume.
+func (d *iscsi) lunLinkPath(vol Volume) string {
+	poolPath := GetPoolMountPath(d.name)
+
+	relPath, err := filepath.Rel(poolPath, vol.MountPath())
+	if err != nil {
+		relPath = filepath.Join(string(vol.volType), vol.name)
+	}
+
+	return filepath.Join(poolPath, iscsiLUNDirectory, relPath)
+}
+
+// volumeLUN returns the LUN assigned to the volume.
+func (d *iscsi) volumeLUN(vol Volume) (string, error) {
+	devPath, err := os.Readlink(d.lunLinkPath(vol))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("Volume %q has no LUN assigned", vol.name)
+		}
+
+		return "", err
+	}
+
+	idx := strings.LastIndex(devPath, "-lun-")
+	if idx < 0 {
+		return "", fmt.Errorf("Invalid LUN device %q for volume %q", devPath, vol.name)
+	}
+
+	return devPath[idx+len("-lun-"):], nil
+}
+
+// linkVolumeLUN records the LUN assigned to the volume.
+func (d *iscsi) linkVolumeLUN(vol Volume, lun string) error {
+	linkPath := d.lunLinkPath(vol)
+
+	err := os.MkdirAll(filepath.Dir(linkPath), 0o700)
+	if err != nil {
+		return err
+	}
+
+	err = os.Symlink(d.lunDevicePath(lun), linkPath)
+	if err != nil {
+		return fmt.Errorf("Failed recording LUN %s of volume %q: %w", lun, vol.name, err)
+	}
+
+	return nil
+}
+
+// unlinkVolumeLUN removes the LUN assignment of the volume.
+func (d *iscsi) unlinkVolumeLUN(vol Volume) error {
+	linkPath := d.lunLinkPath(vol)
+
+	err := os.Remove(linkPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed removing LUN assignment of volume %q: %w", vol.name, err)
+	}
+
+	// Remove the parent directory of snapshots once empty.
+	if vol.IsSnapshot() {
+		_ = os.Remove(filepath.Dir(linkPath))
+	}
+
+	return nil
+}
+
+// assignedLUNs returns the LUN devices assigned to volumes, mapped to the path of their assignment.
+func (d *iscsi) assignedLUNs() (map[string]string, error) {
+	assigned := map[string]string{}
+
+	err := filepath.WalkDir(filepath.Join(GetPoolMountPath(d.name), iscsiLUNDirectory), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.Type()&fs.ModeSymlink == 0 {
+			return nil
+		}
+
+		devPath, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+
+		assigned[devPath] = path
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return assigned, nil
+}
+
+// usesHook returns whether LUNs are allocated by the provisioning hook.
+func (d *iscsi) usesHook() bool {
+	return d.config["iscsi.provisioning_hook"] != ""
+}
+
+// runHook runs the provisioning hook for the action on the volume and returns its trimmed output.
+// ErrNotSupported is returned when the hook doesn't support the action.
+func (d *iscsi) runHook(action string, vol Volume, extraEnv map[string]string) (string, error) {
+	env := append(os.Environ(),
+		"INCUS_POOL="+d.name,
+		"INCUS_ISCSI_PORTAL="+iscsiPortal(d.config["source"]),
+		"INCUS_ISCSI_TARGET="+d.config["iscsi.target"],
+		"INCUS_VOLUME_TYPE="+string(vol.volType),
+		"INCUS_VOLUME_NAME="+vol.name,
+	)
+
+	for k, v := range extraEnv {
+		env = append(env, k+"="+v)
+	}
+
+	out, _, err := subprocess.RunCommandSplit(context.TODO(), env, nil, d.config["iscsi.provisioning_hook"], action)
+	if err != nil {
+		status, _ := linux.ExitStatus(err)
+		if status == iscsiHookNotSupported {
+			return "", ErrNotSupported
+		}
+
+		return "", fmt.Errorf("Failed running provisioning hook %q for volume %q: %w", action, vol.name, err)
+	}
+
+	return strings.TrimSpace(out), nil
+}
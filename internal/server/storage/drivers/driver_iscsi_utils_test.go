@@ -0,0 +1,44 @@
+package drivers
+
+import (
+	"fmt"
+)
+
+func Example_iscsiPortal() {
+	for _, source := range []string{"san.example.net", "san.example.net:3261", "192.0.2.10", "2001:db8::1", "[2001:db8::1]", "[2001:db8::1]:3261"} {
+		fmt.Printf("%q: %s\n", source, iscsiPortal(source))
+	}
+
+	// Output: "san.example.net": san.example.net:3260
+	// "san.example.net:3261": san.example.net:3261
+	// "192.0.2.10": 192.0.2.10:3260
+	// "2001:db8::1": [2001:db8::1]:3260
+	// "[2001:db8::1]": [2001:db8::1]:3260
+	// "[2001:db8::1]:3261": [2001:db8::1]:3261
+}
+
+func Example_validateISCSITarget() {
+	for _, target := range []string{"", "iqn.2003-01.org.example:storage", "iqn.", "eui.02004567a425678d", "naa.60a980004335", "iqn.2003-01.org.example:a b", "storage"} {
+		fmt.Printf("%q: %v\n", target, validateISCSITarget(target) == nil)
+	}
+
+	// Output: "": false
+	// "iqn.2003-01.org.example:storage": true
+	// "iqn.": false
+	// "eui.02004567a425678d": true
+	// "naa.60a980004335": true
+	// "iqn.2003-01.org.example:a b": false
+	// "storage": false
+}
+
+func Example_iscsiOutputHasTarget() {
+	session := "tcp: [1] 192.0.2.10:3260,1 iqn.2003-01.org.example:storage (non-flash)\ntcp: [2] 192.0.2.11:3260,1 iqn.2003-01.org.example:backup (non-flash)\n"
+
+	fmt.Println(iscsiOutputHasTarget(session, "192.0.2.10:3260", "iqn.2003-01.org.example:storage"))
+	fmt.Println(iscsiOutputHasTarget(session, "192.0.2.10:3260", "iqn.2003-01.org.example:backup"))
+	fmt.Println(iscsiOutputHasTarget("192.0.2.11:3260,1 iqn.2003-01.org.example:backup\n", "192.0.2.11:3260", "iqn.2003-01.org.example:backup"))
+
+	// Output: true
+	// false
+	// true
+}
@@ -0,0 +1,689 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/lxc/incus/v6/internal/instancewriter"
+	"github.com/lxc/incus/v6/internal/rsync"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	"github.com/lxc/incus/v6/internal/server/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// CreateVolume creates an empty volume and can optionally fill it by executing the supplied filler function.
+// The block device is a LUN of the target, either allocated by the provisioning hook or set in "iscsi.lun".
+func (d *iscsi) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error {
+	if vol.volType == VolumeTypeCustom && vol.contentType != ContentTypeBlock {
+		return errors.New("Only block custom volumes are supported")
+	}
+
+	if util.PathExists(vol.MountPath()) {
+		return fmt.Errorf("Volume path %q already exists", vol.MountPath())
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Create the volume directory, holding the filesystem part of virtual machines.
+	err := vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	volPath := vol.MountPath()
+	reverter.Add(func() { _ = os.RemoveAll(volPath) })
+
+	sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+	if err != nil {
+		return err
+	}
+
+	lun, err := d.allocateLUN(vol, sizeBytes)
+	if err != nil {
+		return err
+	}
+
+	if d.usesHook() {
+		reverter.Add(func() { _, _ = d.runHook("delete", vol, map[string]string{"INCUS_LUN": lun}) })
+	}
+
+	devPath, err := d.waitLUN(lun)
+	if err != nil {
+		return err
+	}
+
+	lunSizeBytes, err := BlockDiskSizeBytes(devPath)
+	if err != nil {
+		return err
+	}
+
+	if lunSizeBytes < sizeBytes {
+		return fmt.Errorf("LUN %s has a size of %s which is less than the requested size of %s", lun, units.GetByteSizeStringIEC(lunSizeBytes, 2), units.GetByteSizeStringIEC(sizeBytes, 2))
+	}
+
+	err = d.linkVolumeLUN(vol, lun)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = d.unlinkVolumeLUN(vol) })
+
+	// Run the volume filler function if supplied.
+	err = d.runFiller(vol, devPath, filler, false)
+	if err != nil {
+		return err
+	}
+
+	// Move the GPT alt header to end of disk if needed and if filler specified.
+	if vol.IsVMBlock() && filler != nil && filler.Fill != nil {
+		err = d.moveGPTAltHeader(devPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// allocateLUN returns the LUN to use for a new volume, running the provisioning hook when set.
+func (d *iscsi) allocateLUN(vol Volume, sizeBytes int64) (string, error) {
+	if d.usesHook() {
+		lun, err := d.runHook("create", vol, map[string]string{"INCUS_VOLUME_SIZE": strconv.FormatInt(sizeBytes, 10)})
+		if err != nil {
+			return "", err
+		}
+
+		err = validate.IsUint32(lun)
+		if err != nil {
+			return "", fmt.Errorf("Provisioning hook returned an invalid LUN %q: %w", lun, err)
+		}
+
+		return lun, nil
+	}
+
+	lun := vol.config["iscsi.lun"]
+	if lun == "" {
+		return "", fmt.Errorf("Volume %q requires %q as the pool has no provisioning hook", vol.name, "iscsi.lun")
+	}
+
+	// Check that the LUN isn't used by another volume.
+	assigned, err := d.assignedLUNs()
+	if err != nil {
+		return "", err
+	}
+
+	_, found := assigned[d.lunDevicePath(lun)]
+	if found {
+		return "", fmt.Errorf("LUN %s is already used by another volume", lun)
+	}
+
+	return lun, nil
+}
+
+// CreateVolumeFromBackup restores a backup tarball onto the storage device.
+func (d *iscsi) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+}
+
+// CreateVolumeFromCopy provides same-pool volume copying functionality.
+func (d *iscsi) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	var err error
+	var srcSnapshots []Volume
+
+	if copySnapshots && !srcVol.IsSnapshot() {
+		// Get the list of snapshots from the source.
+		srcSnapshots, err = srcVol.Snapshots(op)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The LUN of the source can't be used by the new volume.
+	if !d.usesHook() && vol.config["iscsi.lun"] == srcVol.config["iscsi.lun"] {
+		return fmt.Errorf("Volume %q requires a different %q than its source", vol.name, "iscsi.lun")
+	}
+
+	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+}
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+func (d *iscsi) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
+	return genericVFSCreateVolumeFromMigration(d, nil, vol, conn, volTargetArgs, preFiller, op)
+}
+
+// RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
+func (d *iscsi) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
+	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, true, allowInconsistent, op)
+}
+
+// DeleteVolume deletes a volume of the storage device. If any snapshots of the volume remain then
+// this function will return an error.
+// Pre-provisioned LUNs are left untouched on the storage array.
+func (d *iscsi) DeleteVolume(vol Volume, op *operations.Operation) error {
+	snapshots, err := d.VolumeSnapshots(vol, op)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) > 0 {
+		return errors.New("Cannot remove a volume that has snapshots")
+	}
+
+	err = d.releaseLUN(vol)
+	if err != nil {
+		return err
+	}
+
+	volPath := vol.MountPath()
+	err = forceRemoveAll(volPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Failed to remove '%s': %w", volPath, err)
+	}
+
+	// Although the volume snapshot directory should already be removed, lets remove it here
+	// to just in case the top-level directory is left.
+	err = deleteParentSnapshotDirIfEmpty(d.name, vol.volType, vol.name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// releaseLUN removes the LUN assignment of a volume or snapshot, deleting the LUN through the provisioning
+// hook when set.
+func (d *iscsi) releaseLUN(vol Volume) error {
+	_, err := os.Lstat(d.lunLinkPath(vol))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	if d.usesHook() {
+		lun, err := d.volumeLUN(vol)
+		if err != nil {
+			return err
+		}
+
+		_, err = d.runHook("delete", vol, map[string]string{"INCUS_LUN": lun})
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.unlinkVolumeLUN(vol)
+}
+
+// HasVolume indicates whether a specific volume exists on the storage pool.
+func (d *iscsi) HasVolume(vol Volume) (bool, error) {
+	if !IsContentBlock(vol.contentType) {
+		return genericVFSHasVolume(vol)
+	}
+
+	_, err := os.Lstat(d.lunLinkPath(vol))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ValidateVolume validates the supplied volume config. Optionally removes invalid keys from the volume's config.
+func (d *iscsi) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
+	rules := map[string]func(value string) error{
+		"iscsi.lun": validate.Optional(validate.IsUint32),
+	}
+
+	return d.validateVolume(vol, rules, removeUnknownKeys)
+}
+
+// UpdateVolume applies config changes to the volume.
+func (d *iscsi) UpdateVolume(vol Volume, changedConfig map[string]string) error {
+	_, lunChanged := changedConfig["iscsi.lun"]
+	if lunChanged {
+		return fmt.Errorf("Volume option %q can't be changed", "iscsi.lun")
+	}
+
+	newSize, sizeChanged := changedConfig["size"]
+	if sizeChanged {
+		err := d.SetVolumeQuota(vol, newSize, false, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetVolumeUsage returns the disk space used by the volume, which is the size of its LUN.
+func (d *iscsi) GetVolumeUsage(vol Volume) (int64, error) {
+	if vol.IsSnapshot() || !IsContentBlock(vol.contentType) {
+		return -1, ErrNotSupported
+	}
+
+	devPath, err := d.GetVolumeDiskPath(vol)
+	if err != nil {
+		return -1, err
+	}
+
+	return BlockDiskSizeBytes(devPath)
+}
+
+// SetVolumeQuota applies a size limit on volume.
+// LUNs can only be grown, either by the provisioning hook or on the storage array beforehand.
+// Does nothing for the filesystem part of virtual machines as it's stored on the host.
+func (d *iscsi) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
+	if vol.contentType != ContentTypeBlock {
+		return nil
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	// Do nothing if size isn't specified.
+	if sizeBytes <= 0 {
+		return nil
+	}
+
+	devPath, err := d.GetVolumeDiskPath(vol)
+	if err != nil {
+		return err
+	}
+
+	oldSizeBytes, err := BlockDiskSizeBytes(devPath)
+	if err != nil {
+		return err
+	}
+
+	if sizeBytes == oldSizeBytes {
+		return nil
+	}
+
+	if sizeBytes < oldSizeBytes {
+		return fmt.Errorf("Block volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+	}
+
+	lun, err := d.volumeLUN(vol)
+	if err != nil {
+		return err
+	}
+
+	if d.usesHook() {
+		_, err = d.runHook("resize", vol, map[string]string{"INCUS_LUN": lun, "INCUS_VOLUME_SIZE": strconv.FormatInt(sizeBytes, 10)})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Pick up the new size of the LUN.
+	_, err = d.waitLUN(lun)
+	if err != nil {
+		return err
+	}
+
+	newSizeBytes, err := BlockDiskSizeBytes(devPath)
+	if err != nil {
+		return err
+	}
+
+	if newSizeBytes < sizeBytes {
+		return fmt.Errorf("LUN %s has a size of %s which is less than the requested size of %s, grow it on the storage array first", lun, units.GetByteSizeStringIEC(newSizeBytes, 2), units.GetByteSizeStringIEC(sizeBytes, 2))
+	}
+
+	// Move the GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
+	// expected the caller will do all necessary post resize actions themselves).
+	if vol.IsVMBlock() && !allowUnsafeResize {
+		err = d.moveGPTAltHeader(devPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetVolumeDiskPath returns the location of the LUN device of the volume.
+func (d *iscsi) GetVolumeDiskPath(vol Volume) (string, error) {
+	if !IsContentBlock(vol.contentType) {
+		return "", ErrNotSupported
+	}
+
+	devPath, err := os.Readlink(d.lunLinkPath(vol))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("Volume %q has no LUN assigned", vol.name)
+		}
+
+		return "", err
+	}
+
+	return devPath, nil
+}
+
+// ListVolumes returns a list of volumes in storage pool.
+func (d *iscsi) ListVolumes() ([]Volume, error) {
+	var vols []Volume
+
+	for _, volType := range d.Info().VolumeTypes {
+		volTypePath := filepath.Join(GetPoolMountPath(d.name), BaseDirectories[volType][0])
+		ents, err := os.ReadDir(volTypePath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list directory %q for volume type %q: %w", volTypePath, volType, err)
+		}
+
+		for _, ent := range ents {
+			vols = append(vols, NewVolume(d, d.name, volType, ContentTypeBlock, ent.Name(), make(map[string]string), d.config))
+		}
+	}
+
+	return vols, nil
+}
+
+// MountVolume makes sure that the LUN device of the volume is available.
+func (d *iscsi) MountVolume(vol Volume, op *operations.Operation) error {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	err = vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	if IsContentBlock(vol.contentType) {
+		devPath, err := d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		if !util.PathExists(devPath) {
+			lun, err := d.volumeLUN(vol)
+			if err != nil {
+				return err
+			}
+
+			_, err = d.waitLUN(lun)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
+	return nil
+}
+
+// UnmountVolume simulates unmounting a volume.
+// As the LUN devices remain available while logged into the target, it returns false indicating the volume
+// was already unmounted.
+func (d *iscsi) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operation) (bool, error) {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	refCount := vol.MountRefCountDecrement()
+	if refCount > 0 {
+		d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": vol.name, "refCount": refCount})
+		return false, ErrInUse
+	}
+
+	return false, nil
+}
+
+// RenameVolume renames a volume and its snapshots.
+func (d *iscsi) RenameVolume(vol Volume, newVolName string, op *operations.Operation) error {
+	if d.usesHook() {
+		_, err := d.runHook("rename", vol, map[string]string{"INCUS_VOLUME_NEW_NAME": newVolName})
+		if err != nil && !errors.Is(err, ErrNotSupported) {
+			return err
+		}
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Move the LUN assignments of the volume and its snapshots.
+	newVol := NewVolume(d, d.name, vol.volType, vol.contentType, newVolName, vol.config, vol.poolConfig)
+	srcLinkPaths := []string{d.lunLinkPath(vol), filepath.Join(GetPoolMountPath(d.name), iscsiLUNDirectory, fmt.Sprintf("%s-snapshots", vol.volType), vol.name)}
+	dstLinkPaths := []string{d.lunLinkPath(newVol), filepath.Join(GetPoolMountPath(d.name), iscsiLUNDirectory, fmt.Sprintf("%s-snapshots", vol.volType), newVolName)}
+
+	for i := range srcLinkPaths {
+		srcPath := srcLinkPaths[i]
+		dstPath := dstLinkPaths[i]
+
+		_, err := os.Lstat(srcPath)
+		if err != nil {
+			continue
+		}
+
+		err = os.Rename(srcPath, dstPath)
+		if err != nil {
+			return fmt.Errorf("Failed to rename %q to %q: %w", srcPath, dstPath, err)
+		}
+
+		reverter.Add(func() { _ = os.Rename(dstPath, srcPath) })
+	}
+
+	err := genericVFSRenameVolume(d, vol, newVolName, op)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// MigrateVolume sends a volume for migration.
+func (d *iscsi) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *migration.VolumeSourceArgs, op *operations.Operation) error {
+	return genericVFSMigrateVolume(d, d.state, vol, conn, volSrcArgs, op)
+}
+
+// BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
+// This driver does not support optimized backups.
+func (d *iscsi) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+}
+
+// CreateVolumeSnapshot creates a snapshot of a volume through the provisioning hook.
+// The filesystem part of virtual machines is copied on the host.
+func (d *iscsi) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	if !d.usesHook() {
+		return errors.New("Snapshots require a provisioning hook")
+	}
+
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+	parentVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, parentName, snapVol.config, snapVol.poolConfig)
+
+	err := createParentSnapshotDirIfMissing(d.name, snapVol.volType, parentName)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Create snapshot directory.
+	err = snapVol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	snapPath := snapVol.MountPath()
+	reverter.Add(func() { _ = os.RemoveAll(snapPath) })
+
+	// Copy the filesystem part of virtual machines.
+	if snapVol.volType == VolumeTypeVM {
+		_, err = rsync.LocalCopy(parentVol.MountPath(), snapPath, "", true, "--exclude", genericVolumeDiskFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	lun, err := d.volumeLUN(parentVol)
+	if err != nil {
+		return err
+	}
+
+	snapLUN, err := d.runHook("snapshot", snapVol, map[string]string{"INCUS_LUN": lun})
+	if err != nil {
+		if errors.Is(err, ErrNotSupported) {
+			return errors.New("Snapshots aren't supported by the provisioning hook")
+		}
+
+		return err
+	}
+
+	err = validate.IsUint32(snapLUN)
+	if err != nil {
+		return fmt.Errorf("Provisioning hook returned an invalid LUN %q: %w", snapLUN, err)
+	}
+
+	reverter.Add(func() { _, _ = d.runHook("delete", snapVol, map[string]string{"INCUS_LUN": snapLUN}) })
+
+	_, err = d.waitLUN(snapLUN)
+	if err != nil {
+		return err
+	}
+
+	err = d.linkVolumeLUN(snapVol, snapLUN)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// DeleteVolumeSnapshot removes a snapshot from the storage device. The volName and snapshotName
+// must be bare names and should not be in the format "volume/snapshot".
+func (d *iscsi) DeleteVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	err := d.releaseLUN(snapVol)
+	if err != nil {
+		return err
+	}
+
+	snapPath := snapVol.MountPath()
+	err = forceRemoveAll(snapPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Failed to remove '%s': %w", snapPath, err)
+	}
+
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+
+	// Remove the parent snapshot directory if this is the last snapshot being removed.
+	err = deleteParentSnapshotDirIfEmpty(d.name, snapVol.volType, parentName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MountVolumeSnapshot makes sure that the LUN device of the snapshot is available.
+func (d *iscsi) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	return d.MountVolume(snapVol, op)
+}
+
+// UnmountVolumeSnapshot simulates unmounting a volume snapshot.
+func (d *iscsi) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (bool, error) {
+	return d.UnmountVolume(snapVol, false, op)
+}
+
+// VolumeSnapshots returns a list of snapshots for the volume (in no particular order).
+func (d *iscsi) VolumeSnapshots(vol Volume, op *operations.Operation) ([]string, error) {
+	return genericVFSVolumeSnapshots(d, vol, op)
+}
+
+// RestoreVolume restores a volume from a snapshot through the provisioning hook.
+func (d *iscsi) RestoreVolume(vol Volume, snapshotName string, op *operations.Operation) error {
+	if !d.usesHook() {
+		return errors.New("Snapshots require a provisioning hook")
+	}
+
+	snapVol, err := vol.NewSnapshot(snapshotName)
+	if err != nil {
+		return err
+	}
+
+	lun, err := d.volumeLUN(vol)
+	if err != nil {
+		return err
+	}
+
+	snapLUN, err := d.volumeLUN(snapVol)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.runHook("restore", vol, map[string]string{"INCUS_LUN": lun, "INCUS_SNAPSHOT_LUN": snapLUN})
+	if err != nil {
+		return err
+	}
+
+	// Restore the filesystem part of virtual machines.
+	if vol.volType == VolumeTypeVM {
+		_, err = rsync.LocalCopy(snapVol.MountPath(), vol.MountPath(), "", true, "--exclude", genericVolumeDiskFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenameVolumeSnapshot renames a volume snapshot.
+func (d *iscsi) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+	newSnapVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, GetSnapshotVolumeName(parentName, newSnapshotName), snapVol.config, snapVol.poolConfig)
+
+	srcPath := d.lunLinkPath(snapVol)
+	dstPath := d.lunLinkPath(newSnapVol)
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	_, err := os.Lstat(srcPath)
+	if err == nil {
+		err = os.Rename(srcPath, dstPath)
+		if err != nil {
+			return fmt.Errorf("Failed to rename %q to %q: %w", srcPath, dstPath, err)
+		}
+
+		reverter.Add(func() { _ = os.Rename(dstPath, srcPath) })
+	}
+
+	err = genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, op)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
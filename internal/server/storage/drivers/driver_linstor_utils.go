@@ -1101,9 +1101,9 @@ func (d *linstor) rsyncMigrationType(contentType ContentType) localMigration.Typ
 	// Do not pass compression argument to rsync if the associated
 	// config key, that is rsync.compression, is set to false.
 	if util.IsFalse(d.Config()["rsync.compression"]) {
-		rsyncFeatures = []string{"xattrs", "delete", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	} else {
-		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	}
 
 	if IsContentBlock(contentType) {
@@ -148,6 +148,7 @@ func (d *lvm) Info() Info {
 		Buckets:                      !d.isRemote(),
 		Deactivate:                   d.isRemote(),
 		ZeroUnpack:                   !d.usesThinpool(),
+		InstantCopy:                  d.usesThinpool(),
 	}
 }
 
@@ -441,6 +442,16 @@ func (d *lvm) Create() error {
 		} else if d.config["size"] != "" {
 			return errors.New("Cannot specify size when using an existing thin pool")
 		}
+
+		// Attach the cache device to the thin pool if needed.
+		if d.config["lvm.cache.device"] != "" {
+			err = d.attachCache(d.config["lvm.cache.device"])
+			if err != nil {
+				return err
+			}
+
+			reverter.Add(func() { _ = d.detachCache(d.config["lvm.cache.device"]) })
+		}
 	}
 
 	// Mark the volume group with the lvmVgPoolMarker tag to indicate it is now in use by Incus.
@@ -475,6 +486,14 @@ func (d *lvm) Delete(op *operations.Operation) error {
 		return err
 	}
 
+	// Flush and detach the cache device first so that no dirty block is lost and the device is released.
+	if vgExists && d.usesThinpool() && d.config["lvm.cache.device"] != "" {
+		err = d.detachCache(d.config["lvm.cache.device"])
+		if err != nil {
+			return err
+		}
+	}
+
 	removeVg := false
 	if vgExists && util.IsFalseOrEmpty(d.config["lvm.vg.force_reuse"]) {
 		// Count normal and thin volumes.
@@ -579,6 +598,7 @@ func (d *lvm) Validate(config map[string]string) error {
 	rules := map[string]func(value string) error{
 		"lvm.vg_name":       validate.IsAny,
 		"lvm.metadata_size": validate.Optional(validate.IsSize),
+		"volume.activation": validate.Optional(validate.IsOneOf("eager", "lazy")),
 	}
 
 	if !d.clustered {
@@ -587,6 +607,9 @@ func (d *lvm) Validate(config map[string]string) error {
 		rules["lvm.thinpool_metadata_size"] = validate.Optional(validate.IsSize)
 		rules["lvm.use_thinpool"] = validate.Optional(validate.IsBool)
 		rules["lvm.vg.force_reuse"] = validate.Optional(validate.IsBool)
+		rules["maintenance.fstrim.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))
+		rules["lvm.cache.device"] = validate.Optional(validate.IsAbsFilePath)
+		rules["lvm.cache.mode"] = validate.Optional(validate.IsOneOf("writeback", "writethrough"))
 	}
 
 	err := d.validatePool(config, rules, d.commonVolumeRules())
@@ -602,6 +625,18 @@ func (d *lvm) Validate(config map[string]string) error {
 		if config["lvm.thinpool_metadata_size"] != "" {
 			return errors.New("The key lvm.use_thinpool cannot be set to false when lvm.thinpool_metadata_size is set")
 		}
+
+		if config["maintenance.fstrim.schedule"] != "" {
+			return errors.New("The key lvm.use_thinpool cannot be set to false when maintenance.fstrim.schedule is set")
+		}
+
+		if config["lvm.cache.device"] != "" {
+			return errors.New("The key lvm.use_thinpool cannot be set to false when lvm.cache.device is set")
+		}
+	}
+
+	if config["lvm.cache.device"] != "" && !linux.IsBlockdevPath(config["lvm.cache.device"]) {
+		return fmt.Errorf("Cache device %q is not a block device", config["lvm.cache.device"])
 	}
 
 	return nil
@@ -634,6 +669,31 @@ func (d *lvm) Update(changedConfig map[string]string) error {
 		return errors.New("volume.lvm.stripes.size cannot be changed when using thin pool")
 	}
 
+	cacheDevice, cacheDeviceChanged := changedConfig["lvm.cache.device"]
+	if cacheDeviceChanged && d.config["lvm.cache.device"] != "" {
+		err := d.detachCache(d.config["lvm.cache.device"])
+		if err != nil {
+			return err
+		}
+	}
+
+	cacheMode, cacheModeChanged := changedConfig["lvm.cache.mode"]
+	if cacheModeChanged {
+		d.config["lvm.cache.mode"] = cacheMode
+	}
+
+	if cacheDeviceChanged && cacheDevice != "" {
+		err := d.attachCache(cacheDevice)
+		if err != nil {
+			return err
+		}
+	} else if cacheModeChanged && !cacheDeviceChanged && d.config["lvm.cache.device"] != "" {
+		_, err := subprocess.TryRunCommand("lvchange", "--yes", "--cachemode", d.cacheMode(), fmt.Sprintf("%s/%s", d.config["lvm.vg_name"], d.thinpoolName()))
+		if err != nil {
+			return fmt.Errorf("Error changing the cache mode of thin pool %q: %w", d.thinpoolName(), err)
+		}
+	}
+
 	if changedConfig["lvm.vg_name"] != "" {
 		_, err := subprocess.TryRunCommand("vgrename", d.config["lvm.vg_name"], changedConfig["lvm.vg_name"])
 		if err != nil {
@@ -695,8 +755,8 @@ func (d *lvm) Update(changedConfig map[string]string) error {
 		if d.usesThinpool() {
 			lvPath := d.lvmDevPath(d.config["lvm.vg_name"], "", "", d.thinpoolName())
 
-			// Use the remaining space in the volume group.
-			_, err = subprocess.RunCommand("lvresize", "-f", "-l", "+100%FREE", lvPath)
+			// Use the remaining space of the loop device (leaving any cache device alone).
+			_, err = subprocess.RunCommand("lvresize", "-f", "-l", "+100%FREE", lvPath, loopDevPath)
 			if err != nil {
 				return err
 			}
@@ -809,6 +869,13 @@ func (d *lvm) GetResources() (*api.ResourcesStoragePool, error) {
 
 		res.Space.Total = totalSize
 		res.Space.Used = usedSize
+
+		if d.config["lvm.cache.device"] != "" {
+			res.Cache, err = d.cacheStatus()
+			if err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		// If thinpools are not in use, calculate used space in volume group.
 		args := []string{
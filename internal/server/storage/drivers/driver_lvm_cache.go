@@ -0,0 +1,188 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// lvmCacheModeDefault is the cache mode used when lvm.cache.mode isn't set.
+const lvmCacheModeDefault = "writeback"
+
+// cacheMode returns the dm-cache mode to use for the thin pool.
+func (d *lvm) cacheMode() string {
+	if d.config["lvm.cache.mode"] != "" {
+		return d.config["lvm.cache.mode"]
+	}
+
+	return lvmCacheModeDefault
+}
+
+// cachePoolName returns the name of the cache pool volume used to cache the thin pool.
+func (d *lvm) cachePoolName() string {
+	return fmt.Sprintf("%s_cache", d.thinpoolName())
+}
+
+// attachCache adds the cache device to the volume group and uses it as a dm-cache for the thin pool.
+func (d *lvm) attachCache(cacheDevice string) error {
+	vgName := d.config["lvm.vg_name"]
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	pvExists, err := d.pysicalVolumeExists(cacheDevice)
+	if err != nil {
+		return err
+	}
+
+	if pvExists {
+		return fmt.Errorf("Cache device %q is already used as an LVM physical volume", cacheDevice)
+	}
+
+	_, err = subprocess.TryRunCommand("pvcreate", cacheDevice)
+	if err != nil {
+		return fmt.Errorf("Failed to create the physical volume for the cache device %q: %w", cacheDevice, err)
+	}
+
+	reverter.Add(func() { _, _ = subprocess.TryRunCommand("pvremove", cacheDevice) })
+
+	_, err = subprocess.TryRunCommand("vgextend", vgName, cacheDevice)
+	if err != nil {
+		return fmt.Errorf("Failed to add the cache device %q to volume group %q: %w", cacheDevice, vgName, err)
+	}
+
+	reverter.Add(func() { _, _ = subprocess.TryRunCommand("vgreduce", vgName, cacheDevice) })
+
+	// Leave some room on the cache device for the metadata of the cache pool.
+	_, err = subprocess.TryRunCommand("lvcreate", "--type", "cache-pool", "--extents", "95%PVS", "--name", d.cachePoolName(), vgName, cacheDevice)
+	if err != nil {
+		return fmt.Errorf("Failed to create the cache pool on %q: %w", cacheDevice, err)
+	}
+
+	reverter.Add(func() { _ = d.removeLogicalVolume(d.lvmDevPath(vgName, "", "", d.cachePoolName())) })
+
+	_, err = subprocess.TryRunCommand("lvconvert", "--yes", "--type", "cache", "--cachepool", fmt.Sprintf("%s/%s", vgName, d.cachePoolName()), "--cachemode", d.cacheMode(), fmt.Sprintf("%s/%s", vgName, d.thinpoolName()))
+	if err != nil {
+		return fmt.Errorf("Failed to attach the cache to thin pool %q: %w", d.thinpoolName(), err)
+	}
+
+	d.logger.Debug("Cache attached to thin pool", logger.Ctx{"vg_name": vgName, "thinpool_name": d.thinpoolName(), "cache_device": cacheDevice, "cache_mode": d.cacheMode()})
+
+	reverter.Success()
+	return nil
+}
+
+// detachCache flushes the cache of the thin pool and removes it, along with the cache device from the volume group.
+func (d *lvm) detachCache(cacheDevice string) error {
+	vgName := d.config["lvm.vg_name"]
+
+	cached, err := d.thinpoolCached()
+	if err != nil {
+		return err
+	}
+
+	if cached {
+		// Uncaching writes back any dirty block to the thin pool before removing the cache pool.
+		_, err = subprocess.TryRunCommand("lvconvert", "--yes", "--uncache", fmt.Sprintf("%s/%s", vgName, d.thinpoolName()))
+		if err != nil {
+			return fmt.Errorf("Failed to detach the cache from thin pool %q: %w", d.thinpoolName(), err)
+		}
+
+		d.logger.Debug("Cache detached from thin pool", logger.Ctx{"vg_name": vgName, "thinpool_name": d.thinpoolName()})
+	}
+
+	pvExists, err := d.pysicalVolumeExists(cacheDevice)
+	if err != nil {
+		return err
+	}
+
+	if !pvExists {
+		return nil
+	}
+
+	_, err = subprocess.TryRunCommand("vgreduce", vgName, cacheDevice)
+	if err != nil {
+		return fmt.Errorf("Failed to remove the cache device %q from volume group %q: %w", cacheDevice, vgName, err)
+	}
+
+	_, err = subprocess.TryRunCommand("pvremove", cacheDevice)
+	if err != nil {
+		return fmt.Errorf("Failed to remove the physical volume of the cache device %q: %w", cacheDevice, err)
+	}
+
+	d.logger.Debug("Cache device removed from volume group", logger.Ctx{"vg_name": vgName, "cache_device": cacheDevice})
+
+	return nil
+}
+
+// thinpoolCached returns whether the thin pool is currently cached.
+func (d *lvm) thinpoolCached() (bool, error) {
+	out, err := subprocess.RunCommand("lvs", "--noheadings", "-o", "pool_lv", fmt.Sprintf("%s/%s_tdata", d.config["lvm.vg_name"], d.thinpoolName()))
+	if err != nil {
+		if d.isLVMNotFoundExitError(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("Error checking the cache of thin pool %q: %w", d.thinpoolName(), err)
+	}
+
+	return strings.TrimSpace(out) != "", nil
+}
+
+// cacheStatus returns the usage and statistics of the cache of the thin pool.
+func (d *lvm) cacheStatus() (*api.ResourcesStoragePoolCache, error) {
+	args := []string{
+		fmt.Sprintf("%s/%s_tdata", d.config["lvm.vg_name"], d.thinpoolName()),
+		"--noheadings",
+		"--units", "b",
+		"--nosuffix",
+		"--separator", ",",
+		"-o", "cache_mode,chunk_size,cache_total_blocks,cache_used_blocks,cache_dirty_blocks,cache_read_hits,cache_read_misses,cache_write_hits,cache_write_misses",
+	}
+
+	out, err := subprocess.RunCommand("lvs", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := util.SplitNTrimSpace(out, ",", -1, false)
+	if len(parts) < 9 {
+		return nil, errors.New("Unexpected output from lvs command")
+	}
+
+	// The cache statistics are only available while the thin pool is active.
+	if parts[2] == "" {
+		return nil, ErrNotSupported
+	}
+
+	values := make([]uint64, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		value, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing cache statistics (%q): %w", part, err)
+		}
+
+		values = append(values, value)
+	}
+
+	chunkSize := values[0]
+
+	return &api.ResourcesStoragePoolCache{
+		Device:      d.config["lvm.cache.device"],
+		Mode:        parts[0],
+		Total:       values[1] * chunkSize,
+		Used:        values[2] * chunkSize,
+		Dirty:       values[3] * chunkSize,
+		ReadHits:    values[4],
+		ReadMisses:  values[5],
+		WriteHits:   values[6],
+		WriteMisses: values[7],
+	}, nil
+}
@@ -126,6 +126,121 @@ func (d *lvm) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
 }
 
+// AdoptVolume adopts an existing logical volume of the pool's volume group as the volume.
+// The source is the name of the logical volume, optionally prefixed with the name of the volume group.
+func (d *lvm) AdoptVolume(vol Volume, source string, op *operations.Operation) (revert.Hook, error) {
+	if vol.volType != VolumeTypeCustom || vol.contentType == ContentTypeISO {
+		return nil, ErrNotSupported
+	}
+
+	vgName := d.config["lvm.vg_name"]
+	lvName := source
+
+	sourceVGName, sourceLVName, found := strings.Cut(source, "/")
+	if found {
+		if sourceVGName != vgName {
+			return nil, fmt.Errorf("Logical volume %q isn't in the volume group %q", source, vgName)
+		}
+
+		lvName = sourceLVName
+	}
+
+	// Refuse the logical volumes already managed by Incus.
+	for _, dir := range managedPoolDirs {
+		if strings.HasPrefix(lvName, dir+"_") {
+			return nil, fmt.Errorf("Logical volume %q is managed by the storage pool", source)
+		}
+	}
+
+	srcDevPath := fmt.Sprintf("/dev/%s/%s", vgName, lvName)
+	out, err := subprocess.RunCommand("lvs", "--noheadings", "--separator", "|", "-o", "lv_attr,pool_lv", fmt.Sprintf("%s/%s", vgName, lvName))
+	if err != nil {
+		if d.isLVMNotFoundExitError(err) {
+			return nil, fmt.Errorf("Logical volume %q doesn't exist", source)
+		}
+
+		return nil, err
+	}
+
+	attr, poolLV, _ := strings.Cut(strings.TrimSpace(out), "|")
+	if len(attr) < 6 {
+		return nil, fmt.Errorf("Unexpected attributes %q of logical volume %q", attr, source)
+	}
+
+	// The logical volume must match how the pool creates its volumes, so that snapshots and copies work.
+	if d.usesThinpool() {
+		if attr[0] != 'V' || poolLV != d.thinpoolName() {
+			return nil, fmt.Errorf("Logical volume %q isn't a thin volume of the thin pool %q", source, d.thinpoolName())
+		}
+	} else if attr[0] != '-' {
+		return nil, fmt.Errorf("Logical volume %q isn't a regular logical volume", source)
+	}
+
+	if attr[5] == 'o' {
+		return nil, fmt.Errorf("Logical volume %q is in use", source)
+	}
+
+	volDevPath := d.lvmDevPath(vgName, vol.volType, vol.contentType, vol.name)
+	exists, err := d.logicalVolumeExists(volDevPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, fmt.Errorf("Logical volume %q already exists", volDevPath)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	err = d.renameLogicalVolume(srcDevPath, volDevPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(func() { _ = d.renameLogicalVolume(volDevPath, srcDevPath) })
+
+	sizeBytes, err := d.logicalVolumeSize(volDevPath)
+	if err != nil {
+		return nil, err
+	}
+
+	vol.config["size"] = fmt.Sprintf("%dB", sizeBytes)
+
+	if vol.contentType == ContentTypeFS {
+		// Check that the logical volume holds the configured filesystem.
+		activated, err := d.activateVolume(vol)
+		if err != nil {
+			return nil, err
+		}
+
+		fsType, err := fsProbe(volDevPath)
+
+		if activated {
+			_, _ = d.deactivateVolume(vol)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed probing filesystem of logical volume %q: %w", source, err)
+		}
+
+		if fsType != vol.ConfigBlockFilesystem() {
+			return nil, fmt.Errorf("Logical volume %q holds a %q filesystem rather than %q, set block.filesystem accordingly", source, fsType, vol.ConfigBlockFilesystem())
+		}
+
+		err = vol.EnsureMountPath()
+		if err != nil {
+			return nil, err
+		}
+
+		reverter.Add(func() { _ = os.Remove(vol.MountPath()) })
+	}
+
+	cleanup := reverter.Clone().Fail
+	reverter.Success()
+	return cleanup, nil
+}
+
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *lvm) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	var err error
@@ -160,6 +275,16 @@ func (d *lvm) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
 }
 
+// CreateVolumeFromClone creates the volume as a thin snapshot of the source.
+func (d *lvm) CreateVolumeFromClone(vol Volume, srcVol Volume, op *operations.Operation) error {
+	// Only thin snapshots don't need to copy the data of the source.
+	if !d.usesThinpool() {
+		return ErrNotSupported
+	}
+
+	return d.CreateVolumeFromCopy(vol, srcVol, false, false, op)
+}
+
 // CreateVolumeFromMigration creates a volume being sent via a migration.
 func (d *lvm) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
 	if d.clustered && volTargetArgs.ClusterMoveSourceName != "" && volTargetArgs.StoragePool == "" {
@@ -316,6 +441,7 @@ func (d *lvm) commonVolumeRules() map[string]func(value string) error {
 		"block.filesystem":    validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
 		"lvm.stripes":         validate.Optional(validate.IsUint32),
 		"lvm.stripes.size":    validate.Optional(validate.IsSize),
+		"maintenance.fstrim":  validate.Optional(validate.IsBool),
 	}
 }
 
@@ -330,6 +456,7 @@ func (d *lvm) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 	if vol.IsVMBlock() || vol.volType == VolumeTypeCustom && vol.contentType == ContentTypeBlock {
 		delete(commonRules, "block.filesystem")
 		delete(commonRules, "block.mount_options")
+		delete(commonRules, "maintenance.fstrim")
 	}
 
 	err := d.validateVolume(vol, commonRules, removeUnknownKeys)
@@ -405,6 +532,40 @@ func (d *lvm) GetVolumeUsage(vol Volume) (int64, error) {
 	return -1, ErrNotSupported
 }
 
+// GetVolumeSnapshotUsage returns the space used by the data of a snapshot and the space unique to it.
+// Only the space allocated to thin snapshots from the thin pool is known, the thin pool metadata doesn't
+// tell which part of it is shared with the volume.
+func (d *lvm) GetVolumeSnapshotUsage(snapVol Volume) (int64, int64, error) {
+	if !snapVol.IsSnapshot() || !d.usesThinpool() {
+		return -1, -1, ErrNotSupported
+	}
+
+	unlock, err := snapVol.MountLock()
+	if err != nil {
+		return -1, -1, err
+	}
+
+	defer unlock()
+
+	// The allocated space is only reported for active logical volumes.
+	activated, err := d.activateVolume(snapVol)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	if activated {
+		defer func() { _, _ = d.deactivateVolume(snapVol) }()
+	}
+
+	volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], snapVol.volType, snapVol.contentType, snapVol.name)
+	_, usedSize, err := d.thinPoolVolumeUsage(volDevPath)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	return int64(usedSize), -1, nil
+}
+
 // SetVolumeQuota applies a size limit on volume.
 // Does nothing if supplied with an empty/zero size.
 func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
@@ -604,6 +765,15 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 // GetVolumeDiskPath returns the location of a disk volume.
 func (d *lvm) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
+		// Activate mounted volumes which were left inactive by lazy activation.
+		// They get deactivated by UnmountVolume.
+		if d.lazyActivation(vol) && vol.MountInUse() {
+			_, err := d.activateVolume(vol)
+			if err != nil {
+				return "", err
+			}
+		}
+
 		volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
 		return volDevPath, nil
 	}
@@ -611,6 +781,15 @@ func (d *lvm) GetVolumeDiskPath(vol Volume) (string, error) {
 	return "", ErrNotSupported
 }
 
+// TrimVolumes discards the unused blocks of the mounted volumes so that their space is released in the thin pool.
+func (d *lvm) TrimVolumes(vols []Volume, op *operations.Operation) error {
+	if !d.usesThinpool() {
+		return ErrNotSupported
+	}
+
+	return fstrimVolumes(vols)
+}
+
 // ListVolumes returns a list of volumes in storage pool.
 func (d *lvm) ListVolumes() ([]Volume, error) {
 	vols := make(map[string]Volume)
@@ -727,13 +906,16 @@ func (d *lvm) MountVolume(vol Volume, op *operations.Operation) error {
 	defer reverter.Fail()
 
 	// Activate LVM volume if needed.
-	activated, err := d.activateVolume(vol)
-	if err != nil {
-		return err
-	}
+	// With lazy activation, block volumes are only activated once their disk path is requested.
+	if !d.lazyActivation(vol) {
+		activated, err := d.activateVolume(vol)
+		if err != nil {
+			return err
+		}
 
-	if activated {
-		reverter.Add(func() { _, _ = d.deactivateVolume(vol) })
+		if activated {
+			reverter.Add(func() { _, _ = d.deactivateVolume(vol) })
+		}
 	}
 
 	if vol.contentType == ContentTypeFS {
@@ -138,6 +138,11 @@ func (d *mock) GetVolumeUsage(vol Volume) (int64, error) {
 	return 0, nil
 }
 
+// GetVolumeSnapshotUsage returns the space used by the data of a snapshot and the space unique to it.
+func (d *mock) GetVolumeSnapshotUsage(snapVol Volume) (int64, int64, error) {
+	return 0, 0, nil
+}
+
 // SetVolumeQuota applies a size limit on volume.
 func (d *mock) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
 	return nil
@@ -0,0 +1,206 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/state"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+var (
+	nfsVersion string
+	nfsLoaded  bool
+)
+
+// nfs stores volumes as files and directories on an NFS export, using the same layout as the dir driver.
+type nfs struct {
+	dir
+}
+
+// init initializes the driver. Project quotas aren't available on NFS so they're always skipped,
+// the volume sizes are tracked by the driver instead.
+func (d *nfs) init(state *state.State, name string, config map[string]string, logger logger.Logger, volIDFunc func(volType VolumeType, volName string) (int64, error), commonRules *Validators) {
+	skipQuota := func(volType VolumeType, volName string) (int64, error) { return volIDQuotaSkip, nil }
+	d.dir.init(state, name, config, logger, skipQuota, commonRules)
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *nfs) load() error {
+	err := d.dir.load()
+	if err != nil {
+		return err
+	}
+
+	// Done if previously loaded.
+	if nfsLoaded {
+		return nil
+	}
+
+	// Validate the required binaries.
+	for _, tool := range []string{"mount.nfs", "rsync"} {
+		_, err := exec.LookPath(tool)
+		if err != nil {
+			return fmt.Errorf("Required tool '%s' is missing", tool)
+		}
+	}
+
+	// Detect and record the version, "mount.nfs: (linux nfs-utils 2.6.2)".
+	if nfsVersion == "" {
+		out, _ := subprocess.RunCommand("mount.nfs", "-V")
+		fields := strings.Fields(strings.TrimSpace(out))
+		if len(fields) > 0 {
+			nfsVersion = strings.TrimSuffix(fields[len(fields)-1], ")")
+		}
+	}
+
+	nfsLoaded = true
+	return nil
+}
+
+// Info returns info about the driver and its environment.
+func (d *nfs) Info() Info {
+	return Info{
+		Name:                         "nfs",
+		Version:                      nfsVersion,
+		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		VolumeMultiNode:              d.isRemote(),
+		BlockBacking:                 false,
+		RunningCopyFreeze:            true,
+		DirectIO:                     true,
+		IOUring:                      false,
+		MountedRoot:                  true,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *nfs) FillConfig() error {
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *nfs) Create() error {
+	err := validateNFSSource(d.config["source"])
+	if err != nil {
+		return err
+	}
+
+	// Mount the export in a temporary location to check that it can be used.
+	mountPath, err := os.MkdirTemp("", "incus_nfs_")
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary directory under: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(mountPath) }()
+
+	err = os.Chmod(mountPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed to chmod '%s': %w", mountPath, err)
+	}
+
+	mountPoint := filepath.Join(mountPath, "mount")
+
+	err = os.Mkdir(mountPoint, 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory '%s': %w", mountPoint, err)
+	}
+
+	err = d.mountExport(mountPoint)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = forceUnmount(mountPoint) }()
+
+	// Check that the export is currently empty.
+	isEmpty, err := internalUtil.PathIsEmpty(mountPoint)
+	if err != nil {
+		return err
+	}
+
+	if !isEmpty {
+		return fmt.Errorf("NFS export '%s' isn't empty", d.config["source"])
+	}
+
+	return nil
+}
+
+// Delete removes the storage pool from the storage device.
+func (d *nfs) Delete(op *operations.Operation) error {
+	// Make sure the export is mounted so that its content can be wiped.
+	_, err := d.Mount()
+	if err != nil {
+		return err
+	}
+
+	// On delete, wipe everything in the directory.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	// Unmount the path.
+	_, err = d.Unmount()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *nfs) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		"nfs.mount_options": validate.IsAny,
+	}
+
+	return d.validatePool(config, rules, nil)
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *nfs) Update(changedConfig map[string]string) error {
+	_, ok := changedConfig["source"]
+	if ok {
+		return errors.New("Storage pool source can't be changed")
+	}
+
+	return nil
+}
+
+// Mount mounts the storage pool.
+func (d *nfs) Mount() (bool, error) {
+	path := GetPoolMountPath(d.name)
+
+	// Check if already mounted.
+	if linux.IsMountPoint(path) {
+		return false, nil
+	}
+
+	err := d.mountExport(path)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unmount unmounts the storage pool.
+func (d *nfs) Unmount() (bool, error) {
+	// Unmount until nothing is left mounted.
+	return forceUnmount(GetPoolMountPath(d.name))
+}
@@ -0,0 +1,95 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// validateNFSSource checks that the source is an NFS export in the "HOST:/PATH" form.
+func validateNFSSource(source string) error {
+	if source == "" {
+		return errors.New(`Missing required source, expected "HOST:/PATH"`)
+	}
+
+	// IPv6 addresses contain colons too, so look for the start of the path.
+	idx := strings.Index(source, ":/")
+	if idx <= 0 {
+		return fmt.Errorf(`Invalid NFS export %q, expected "HOST:/PATH"`, source)
+	}
+
+	return nil
+}
+
+// mountExport mounts the pool's NFS export on the given path.
+func (d *nfs) mountExport(path string) error {
+	args := []string{"-t", "nfs"}
+	if d.config["nfs.mount_options"] != "" {
+		args = append(args, "-o", d.config["nfs.mount_options"])
+	}
+
+	args = append(args, d.config["source"], path)
+
+	_, err := subprocess.RunCommand("mount", args...)
+	if err != nil {
+		return fmt.Errorf("Failed to mount NFS export %q: %w", d.config["source"], err)
+	}
+
+	return nil
+}
+
+// directoryUsage returns the disk space used by the files and directories under the path.
+// Hard links are only counted once.
+func directoryUsage(path string) (int64, error) {
+	var usage int64
+	seen := map[uint64]struct{}{}
+
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Files may be removed while walking the tree.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			usage += info.Size()
+			return nil
+		}
+
+		if stat.Nlink > 1 {
+			_, found := seen[stat.Ino]
+			if found {
+				return nil
+			}
+
+			seen[stat.Ino] = struct{}{}
+		}
+
+		usage += stat.Blocks * 512
+
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	return usage, nil
+}
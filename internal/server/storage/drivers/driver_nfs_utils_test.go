@@ -0,0 +1,18 @@
+package drivers
+
+import (
+	"fmt"
+)
+
+func Example_validateNFSSource() {
+	for _, source := range []string{"", "nfs.example.net:/srv/incus", "nfs.example.net", "nfs.example.net:srv", ":/srv", "[2001:db8::1]:/srv/incus"} {
+		fmt.Printf("%q: %v\n", source, validateNFSSource(source) == nil)
+	}
+
+	// Output: "": false
+	// "nfs.example.net:/srv/incus": true
+	// "nfs.example.net": false
+	// "nfs.example.net:srv": false
+	// ":/srv": false
+	// "[2001:db8::1]:/srv/incus": true
+}
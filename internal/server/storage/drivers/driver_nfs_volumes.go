@@ -0,0 +1,77 @@
+package drivers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// GetVolumeUsage returns the disk space used by the volume.
+// For filesystem volumes, this walks the volume directory as there are no project quotas to query.
+func (d *nfs) GetVolumeUsage(vol Volume) (int64, error) {
+	// Snapshot usage not supported for NFS.
+	if vol.IsSnapshot() {
+		return -1, ErrNotSupported
+	}
+
+	if IsContentBlock(vol.contentType) {
+		diskPath, err := d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return -1, err
+		}
+
+		return directoryUsage(diskPath)
+	}
+
+	return directoryUsage(vol.MountPath())
+}
+
+// SetVolumeQuota applies a size limit on volume.
+// Block volumes are resized like with the dir driver. Filesystem volume sizes can't be enforced on NFS,
+// so they're only checked against the current usage of the volume.
+func (d *nfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
+	if vol.contentType == ContentTypeBlock || vol.Type() == VolumeTypeBucket {
+		return d.dir.SetVolumeQuota(vol, size, allowUnsafeResize, op)
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	if sizeBytes <= 0 {
+		return nil
+	}
+
+	volPath := vol.MountPath()
+	_, err = os.Stat(volPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	usage, err := directoryUsage(volPath)
+	if err != nil {
+		return err
+	}
+
+	// Leave out the VM image file which isn't part of the filesystem volume size.
+	if vol.volType == VolumeTypeVM {
+		blockUsage, err := directoryUsage(filepath.Join(volPath, genericVolumeDiskFile))
+		if err == nil {
+			usage -= blockUsage
+		}
+	}
+
+	if usage > sizeBytes {
+		return fmt.Errorf("Volume uses %s which is more than the requested size of %s", units.GetByteSizeStringIEC(usage, 2), units.GetByteSizeStringIEC(sizeBytes, 2))
+	}
+
+	return nil
+}
@@ -0,0 +1,333 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/storageplugin"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// pluginCallTimeout is the maximum duration of a single call to a storage plugin.
+const pluginCallTimeout = 5 * time.Minute
+
+type plugin struct {
+	common
+}
+
+// pluginConnection is a connection to a storage plugin along with the information it reported.
+type pluginConnection struct {
+	client *storageplugin.Client
+	info   *storageplugin.InfoResponse
+}
+
+// pluginConnections holds the connections to the storage plugins, keyed by socket path.
+// They're established on first use, so that pools can be loaded while their plugin isn't running.
+var pluginConnections = map[string]*pluginConnection{}
+
+// pluginConnectionsMu protects pluginConnections.
+var pluginConnectionsMu sync.Mutex
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *plugin) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+	}
+
+	return nil
+}
+
+// connect returns the connection to the plugin of the pool, connecting to it if needed.
+func (d *plugin) connect() (*pluginConnection, error) {
+	socketPath := d.config["source"]
+	if socketPath == "" {
+		return nil, errors.New("Storage plugin isn't available")
+	}
+
+	pluginConnectionsMu.Lock()
+	defer pluginConnectionsMu.Unlock()
+
+	conn, ok := pluginConnections[socketPath]
+	if ok {
+		return conn, nil
+	}
+
+	client, err := storageplugin.NewClient(socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	info, err := client.GetInfo(ctx, &storageplugin.InfoRequest{ProtocolVersion: storageplugin.ProtocolVersion})
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("Failed getting storage plugin information: %w", err)
+	}
+
+	if info.ProtocolVersion != storageplugin.ProtocolVersion {
+		_ = client.Close()
+		return nil, fmt.Errorf("Unsupported storage plugin protocol version %d (expected %d)", info.ProtocolVersion, storageplugin.ProtocolVersion)
+	}
+
+	conn = &pluginConnection{client: client, info: info}
+	pluginConnections[socketPath] = conn
+
+	return conn, nil
+}
+
+// disconnect closes the connection to the plugin of the pool, if any.
+func (d *plugin) disconnect() {
+	pluginConnectionsMu.Lock()
+	defer pluginConnectionsMu.Unlock()
+
+	conn, ok := pluginConnections[d.config["source"]]
+	if !ok {
+		return
+	}
+
+	_ = conn.client.Close()
+	delete(pluginConnections, d.config["source"])
+}
+
+// Info returns info about the driver and its environment.
+func (d *plugin) Info() Info {
+	info := Info{
+		Name:                         "plugin",
+		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		VolumeMultiNode:              d.isRemote(),
+		BlockBacking:                 true,
+		RunningCopyFreeze:            true,
+		DirectIO:                     true,
+		IOUring:                      true,
+		MountedRoot:                  false,
+		Buckets:                      false,
+	}
+
+	// The capabilities of the plugin are only known once it's reachable.
+	conn, err := d.connect()
+	if err == nil {
+		info.Version = conn.info.Version
+		info.OptimizedImages = conn.info.Clone
+
+		// Only expose the volume types which the plugin claims to support.
+		if len(conn.info.VolumeTypes) > 0 {
+			info.VolumeTypes = slices.DeleteFunc(info.VolumeTypes, func(volType VolumeType) bool {
+				return !slices.Contains(conn.info.VolumeTypes, string(volType))
+			})
+		}
+	}
+
+	return info
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *plugin) FillConfig() error {
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *plugin) Create() error {
+	err := d.FillConfig()
+	if err != nil {
+		return err
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	_, err = conn.client.CreatePool(ctx, d.poolRequest(d.config))
+	if err != nil {
+		return fmt.Errorf("Failed creating storage pool: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the storage pool from the storage device.
+func (d *plugin) Delete(op *operations.Operation) error {
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	_, err = conn.client.DeletePool(ctx, d.poolRequest(d.config))
+	if err != nil {
+		return fmt.Errorf("Failed deleting storage pool: %w", err)
+	}
+
+	d.disconnect()
+
+	// Wipe the local pool directory.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provided keys are supported and that no conflicting or missing configuration is present.
+func (d *plugin) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		"source": validate.Required(validate.IsAbsFilePath),
+	}
+
+	// Plugin specific keys are validated by the plugin itself.
+	for k := range config {
+		if strings.HasPrefix(k, "plugin.") || strings.HasPrefix(k, "volume.plugin.") {
+			rules[k] = validate.IsAny
+		}
+	}
+
+	err := d.validatePool(config, rules, d.commonVolumeRules())
+	if err != nil {
+		return err
+	}
+
+	// The plugin specific keys can only be validated by a running plugin.
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	_, err = conn.client.ValidatePool(ctx, d.poolRequest(config))
+	if err != nil {
+		return fmt.Errorf("Invalid storage plugin configuration: %w", err)
+	}
+
+	return nil
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *plugin) Update(changedConfig map[string]string) error {
+	_, changed := changedConfig["source"]
+	if changed {
+		return errors.New("Storage pool source cannot be changed")
+	}
+
+	return nil
+}
+
+// Mount mounts the storage pool.
+func (d *plugin) Mount() (bool, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.MountPool(ctx, d.poolRequest(d.config))
+	if err != nil {
+		return false, fmt.Errorf("Failed mounting storage pool: %w", err)
+	}
+
+	return resp.Changed, nil
+}
+
+// Unmount unmounts the storage pool.
+func (d *plugin) Unmount() (bool, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.UnmountPool(ctx, d.poolRequest(d.config))
+	if err != nil {
+		return false, fmt.Errorf("Failed unmounting storage pool: %w", err)
+	}
+
+	// Nothing uses the plugin until the pool gets mounted again.
+	d.disconnect()
+
+	return resp.Changed, nil
+}
+
+// GetResources returns the pool resource usage information.
+func (d *plugin) GetResources() (*api.ResourcesStoragePool, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.GetPoolResources(ctx, d.poolRequest(d.config))
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting storage pool resources: %w", err)
+	}
+
+	res := api.ResourcesStoragePool{}
+	res.Space.Total = resp.SpaceTotal
+	res.Space.Used = resp.SpaceUsed
+
+	return &res, nil
+}
+
+// context returns a context to use for a single plugin call.
+func (d *plugin) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), pluginCallTimeout)
+}
+
+// poolRequest returns a plugin pool request for the provided pool configuration.
+func (d *plugin) poolRequest(config map[string]string) *storageplugin.PoolRequest {
+	return &storageplugin.PoolRequest{
+		Pool:   d.name,
+		Config: config,
+	}
+}
+
+// pluginVolume converts a volume into its plugin representation.
+func (d *plugin) pluginVolume(vol Volume) storageplugin.Volume {
+	return storageplugin.Volume{
+		Name:        vol.name,
+		Type:        string(vol.volType),
+		ContentType: string(vol.contentType),
+		Config:      vol.config,
+		Snapshot:    vol.IsSnapshot(),
+	}
+}
+
+// volumeRequest returns a plugin volume request for the provided volume.
+func (d *plugin) volumeRequest(vol Volume) *storageplugin.VolumeRequest {
+	return &storageplugin.VolumeRequest{
+		Pool:   d.name,
+		Volume: d.pluginVolume(vol),
+	}
+}
@@ -0,0 +1,1186 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/instancewriter"
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	"github.com/lxc/incus/v6/internal/server/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/storageplugin"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// CreateVolume creates an empty volume and can optionally fill it by executing the supplied filler function.
+func (d *plugin) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error {
+	return d.createVolume(vol, nil, filler, op)
+}
+
+// createVolume creates a new volume, optionally cloned from the supplied source volume, and runs the filler.
+func (d *plugin) createVolume(vol Volume, srcVol *Volume, filler *VolumeFiller, op *operations.Operation) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	volPath := vol.MountPath()
+	err := vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = os.RemoveAll(volPath) })
+
+	sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+	if err != nil {
+		return err
+	}
+
+	req := &storageplugin.CreateVolumeRequest{
+		Pool:      d.name,
+		Volume:    d.pluginVolume(vol),
+		SizeBytes: sizeBytes,
+	}
+
+	if srcVol != nil {
+		src := d.pluginVolume(*srcVol)
+		req.Source = &src
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	_, err = conn.client.CreateVolume(ctx, req)
+	if err != nil {
+		return fmt.Errorf("Failed creating volume: %w", err)
+	}
+
+	reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
+
+	// Create a new filesystem on empty filesystem volumes.
+	if srcVol == nil && vol.contentType == ContentTypeFS {
+		devPath, activated, err := d.activateVolume(vol)
+		if err != nil {
+			return err
+		}
+
+		if activated {
+			defer func() { _, _ = d.deactivateVolume(vol) }()
+		}
+
+		_, err = makeFSType(devPath, vol.ConfigBlockFilesystem(), nil)
+		if err != nil {
+			return fmt.Errorf("Failed making filesystem on volume: %w", err)
+		}
+	}
+
+	// For VMs, also create the filesystem volume.
+	if vol.IsVMBlock() {
+		fsVol := vol.NewVMBlockFilesystemVolume()
+
+		var srcFSVol *Volume
+		if srcVol != nil {
+			v := srcVol.NewVMBlockFilesystemVolume()
+			srcFSVol = &v
+		}
+
+		err := d.createVolume(fsVol, srcFSVol, nil, op)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = d.DeleteVolume(fsVol, op) })
+	}
+
+	err = vol.MountTask(func(mountPath string, op *operations.Operation) error {
+		// Run the volume filler function if supplied.
+		if filler != nil && filler.Fill != nil {
+			var err error
+			var devPath string
+
+			if IsContentBlock(vol.contentType) {
+				// Get the device path.
+				devPath, err = d.GetVolumeDiskPath(vol)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Allow the filler to resize the volume as it is discarded on failure.
+			err = d.runFiller(vol, devPath, filler, true)
+			if err != nil {
+				return err
+			}
+
+			// Move the GPT alt header to end of disk if needed.
+			if vol.IsVMBlock() {
+				err = d.moveGPTAltHeader(devPath)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if vol.contentType == ContentTypeFS {
+			// Run EnsureMountPath again after mounting and filling to ensure the mount directory has
+			// the correct permissions set.
+			err = vol.EnsureMountPath()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, op)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// CreateVolumeFromBackup restores a backup tarball onto the storage device.
+func (d *plugin) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+}
+
+// CreateVolumeFromCopy provides same-pool volume copying functionality.
+func (d *plugin) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	var err error
+	var srcSnapshots []Volume
+
+	if copySnapshots && !srcVol.IsSnapshot() {
+		// Get the list of snapshots from the source.
+		srcSnapshots, err = srcVol.Snapshots(op)
+		if err != nil {
+			return err
+		}
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	// Let the plugin clone the volume when it can and there are no snapshots to carry over.
+	if conn.info.Clone && len(srcSnapshots) == 0 {
+		return d.createVolume(vol, &srcVol, nil, op)
+	}
+
+	// Otherwise run the generic copy.
+	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+}
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+func (d *plugin) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
+	return genericVFSCreateVolumeFromMigration(d, nil, vol, conn, volTargetArgs, preFiller, op)
+}
+
+// RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
+func (d *plugin) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
+	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, true, allowInconsistent, op)
+}
+
+// DeleteVolume deletes a volume of the storage device. If any snapshots of the volume remain then this function
+// will return an error.
+func (d *plugin) DeleteVolume(vol Volume, op *operations.Operation) error {
+	snapshots, err := d.VolumeSnapshots(vol, op)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) > 0 {
+		return errors.New("Cannot remove a volume that has snapshots")
+	}
+
+	exists, err := d.HasVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		_, err = d.deactivateVolume(vol)
+		if err != nil {
+			return err
+		}
+
+		conn, err := d.connect()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := d.context()
+		defer cancel()
+
+		_, err = conn.client.DeleteVolume(ctx, d.volumeRequest(vol))
+		if err != nil {
+			return fmt.Errorf("Failed deleting volume: %w", err)
+		}
+	}
+
+	if vol.contentType == ContentTypeFS {
+		// Remove the volume from the storage device.
+		mountPath := vol.MountPath()
+		err = os.RemoveAll(mountPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("Failed to remove '%s': %w", mountPath, err)
+		}
+
+		// Although the volume snapshot directory should already be removed, lets remove it here
+		// to just in case the top-level directory is left.
+		err = deleteParentSnapshotDirIfEmpty(d.name, vol.volType, vol.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// For VMs, also delete the filesystem volume.
+	if vol.IsVMBlock() {
+		fsVol := vol.NewVMBlockFilesystemVolume()
+		err := d.DeleteVolume(fsVol, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HasVolume indicates whether a specific volume exists on the storage pool.
+func (d *plugin) HasVolume(vol Volume) (bool, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.HasVolume(ctx, d.volumeRequest(vol))
+	if err != nil {
+		return false, err
+	}
+
+	return resp.Exists, nil
+}
+
+// FillVolumeConfig populate volume with default config.
+func (d *plugin) FillVolumeConfig(vol Volume) error {
+	// Copy volume.* configuration options from pool.
+	// Exclude "block.filesystem" and "block.mount_options" as they depend on volume type (handled below).
+	err := d.fillVolumeConfig(&vol, "block.filesystem", "block.mount_options")
+	if err != nil {
+		return err
+	}
+
+	// Only validate filesystem config keys for filesystem volumes or VM block volumes (which have an
+	// associated filesystem volume).
+	if vol.ContentType() == ContentTypeFS || vol.IsVMBlock() {
+		// Inherit filesystem from pool if not set.
+		if vol.config["block.filesystem"] == "" {
+			vol.config["block.filesystem"] = d.config["volume.block.filesystem"]
+		}
+
+		// Default filesystem if neither volume nor pool specify an override.
+		if vol.config["block.filesystem"] == "" {
+			// Unchangeable volume property: Set unconditionally.
+			vol.config["block.filesystem"] = DefaultFilesystem
+		}
+
+		// Inherit filesystem mount options from pool if not set.
+		if vol.config["block.mount_options"] == "" {
+			vol.config["block.mount_options"] = d.config["volume.block.mount_options"]
+		}
+
+		// Default filesystem mount options if neither volume nor pool specify an override.
+		if vol.config["block.mount_options"] == "" {
+			// Unchangeable volume property: Set unconditionally.
+			vol.config["block.mount_options"] = "discard"
+		}
+	}
+
+	return nil
+}
+
+// commonVolumeRules returns validation rules which are common for pool and volume.
+func (d *plugin) commonVolumeRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		"block.mount_options": validate.IsAny,
+		"block.filesystem":    validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
+	}
+}
+
+// ValidateVolume validates the supplied volume config.
+func (d *plugin) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
+	commonRules := d.commonVolumeRules()
+
+	// Disallow block.* settings for regular custom block volumes. These settings only make sense
+	// when using custom filesystem volumes. Incus will create the filesystem
+	// for these volumes, and use the mount options. When attaching a regular block volume to a VM,
+	// these are not mounted by Incus and therefore don't need these config keys.
+	if vol.IsVMBlock() || vol.volType == VolumeTypeCustom && vol.contentType == ContentTypeBlock {
+		delete(commonRules, "block.filesystem")
+		delete(commonRules, "block.mount_options")
+	}
+
+	// Plugin specific keys are passed through to the plugin.
+	for k := range vol.config {
+		if strings.HasPrefix(k, "plugin.") {
+			commonRules[k] = validate.IsAny
+		}
+	}
+
+	return d.validateVolume(vol, commonRules, removeUnknownKeys)
+}
+
+// UpdateVolume applies config changes to the volume.
+func (d *plugin) UpdateVolume(vol Volume, changedConfig map[string]string) error {
+	newSize, sizeChanged := changedConfig["size"]
+	if sizeChanged {
+		err := d.SetVolumeQuota(vol, newSize, false, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetVolumeUsage returns the disk space used by the volume.
+func (d *plugin) GetVolumeUsage(vol Volume) (int64, error) {
+	// For filesystem volumes, report the filesystem usage when the volume is mounted.
+	if vol.contentType == ContentTypeFS && linux.IsMountPoint(vol.MountPath()) {
+		var stat unix.Statfs_t
+		err := unix.Statfs(vol.MountPath(), &stat)
+		if err != nil {
+			return -1, err
+		}
+
+		return int64(stat.Blocks-stat.Bfree) * int64(stat.Bsize), nil
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		return -1, err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.GetVolumeSize(ctx, d.volumeRequest(vol))
+	if err != nil {
+		return -1, err
+	}
+
+	if resp.UsedBytes < 0 {
+		return -1, ErrNotSupported
+	}
+
+	return resp.UsedBytes, nil
+}
+
+// SetVolumeQuota applies a size limit on volume.
+// Does nothing if supplied with an empty/zero size.
+func (d *plugin) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
+	// Do nothing if size isn't specified.
+	if size == "" || size == "0" {
+		return nil
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.GetVolumeSize(ctx, d.volumeRequest(vol))
+	if err != nil {
+		return err
+	}
+
+	oldSizeBytes := resp.SizeBytes
+	if sizeBytes == oldSizeBytes {
+		return nil
+	}
+
+	if sizeBytes < oldSizeBytes && !conn.info.Shrink {
+		return fmt.Errorf("Storage plugin doesn't support shrinking volumes: %w", ErrCannotBeShrunk)
+	}
+
+	l := d.logger.AddContext(logger.Ctx{"volName": vol.name, "size": fmt.Sprintf("%db", sizeBytes)})
+
+	devPath, activated, err := d.activateVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if activated {
+		defer func() { _, _ = d.deactivateVolume(vol) }()
+	}
+
+	resize := func() error {
+		ctx, cancel := d.context()
+		defer cancel()
+
+		_, err := conn.client.ResizeVolume(ctx, &storageplugin.ResizeVolumeRequest{
+			Pool:      d.name,
+			Volume:    d.pluginVolume(vol),
+			SizeBytes: sizeBytes,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed resizing volume: %w", err)
+		}
+
+		return nil
+	}
+
+	// Resize filesystem if needed.
+	if vol.contentType == ContentTypeFS {
+		fsType := vol.ConfigBlockFilesystem()
+
+		if sizeBytes < oldSizeBytes {
+			if !filesystemTypeCanBeShrunk(fsType) {
+				return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
+			}
+
+			if vol.MountInUse() {
+				return ErrInUse // We don't allow online shrinking of filesystem volumes.
+			}
+
+			// Shrink filesystem first.
+			err = shrinkFileSystem(fsType, devPath, vol, sizeBytes, allowUnsafeResize)
+			if err != nil {
+				return err
+			}
+
+			// Shrink the block device.
+			err = resize()
+			if err != nil {
+				return err
+			}
+		} else {
+			// Grow block device first.
+			err = resize()
+			if err != nil {
+				return err
+			}
+
+			// Grow the filesystem to fill block device.
+			err = growFileSystem(fsType, devPath, vol)
+			if err != nil {
+				return err
+			}
+		}
+
+		l.Debug("Volume filesystem resized")
+	} else {
+		// Only perform pre-resize checks if we are not in "unsafe" mode.
+		// In unsafe mode we expect the caller to know what they are doing and understand the risks.
+		if !allowUnsafeResize {
+			if sizeBytes < oldSizeBytes {
+				return fmt.Errorf("Block volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+			}
+
+			if vol.MountInUse() {
+				return ErrInUse // We don't allow online resizing of block volumes.
+			}
+		}
+
+		err = resize()
+		if err != nil {
+			return err
+		}
+
+		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
+		// expected the caller will do all necessary post resize actions themselves).
+		if vol.IsVMBlock() && !allowUnsafeResize {
+			err = d.moveGPTAltHeader(devPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		l.Debug("Block volume resized")
+	}
+
+	return nil
+}
+
+// GetVolumeDiskPath returns the location of a disk volume.
+func (d *plugin) GetVolumeDiskPath(vol Volume) (string, error) {
+	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
+		// Activation is idempotent and returns the device path of already active volumes.
+		devPath, _, err := d.activateVolume(vol)
+		if err != nil {
+			return "", err
+		}
+
+		return devPath, nil
+	}
+
+	return "", ErrNotSupported
+}
+
+// ListVolumes returns a list of volumes in storage pool.
+func (d *plugin) ListVolumes() ([]Volume, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.ListVolumes(ctx, d.poolRequest(d.config))
+	if err != nil {
+		return nil, err
+	}
+
+	vols := make([]Volume, 0, len(resp.Volumes))
+	for _, pluginVol := range resp.Volumes {
+		volType := VolumeType(pluginVol.Type)
+		contentType := ContentType(pluginVol.ContentType)
+
+		// Ignore snapshots and VM filesystem volumes as we will just return the VM's block volume.
+		if pluginVol.Snapshot || (volType == VolumeTypeVM && contentType == ContentTypeFS) {
+			continue
+		}
+
+		v := NewVolume(d, d.name, volType, contentType, pluginVol.Name, make(map[string]string), d.config)
+
+		if contentType == ContentTypeFS {
+			v.SetMountFilesystemProbe(true)
+		}
+
+		vols = append(vols, v)
+	}
+
+	return vols, nil
+}
+
+// MountVolume mounts a volume and increments ref counter. Please call UnmountVolume() when done with the volume.
+func (d *plugin) MountVolume(vol Volume, op *operations.Operation) error {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Activate the volume if needed.
+	devPath, activated, err := d.activateVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if activated {
+		reverter.Add(func() { _, _ = d.deactivateVolume(vol) })
+	}
+
+	if vol.contentType == ContentTypeFS {
+		// Check if already mounted.
+		mountPath := vol.MountPath()
+		if !linux.IsMountPoint(mountPath) {
+			fsType := vol.ConfigBlockFilesystem()
+
+			if vol.mountFilesystemProbe {
+				fsType, err = fsProbe(devPath)
+				if err != nil {
+					return fmt.Errorf("Failed probing filesystem: %w", err)
+				}
+			}
+
+			err = vol.EnsureMountPath()
+			if err != nil {
+				return err
+			}
+
+			mountFlags, mountOptions := linux.ResolveMountOptions(strings.Split(vol.ConfigBlockMountOptions(), ","))
+			err = TryMount(devPath, mountPath, fsType, mountFlags, mountOptions)
+			if err != nil {
+				return fmt.Errorf("Failed to mount plugin volume: %w", err)
+			}
+
+			d.logger.Debug("Mounted plugin volume", logger.Ctx{"volName": vol.name, "dev": devPath, "path": mountPath, "options": mountOptions})
+		}
+	} else if vol.contentType == ContentTypeBlock || vol.contentType == ContentTypeISO {
+		// For VMs, mount the filesystem volume.
+		if vol.IsVMBlock() {
+			fsVol := vol.NewVMBlockFilesystemVolume()
+			err = d.MountVolume(fsVol, op)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
+	reverter.Success()
+	return nil
+}
+
+// UnmountVolume unmounts volume if mounted and not in use. Returns true if this unmounted the volume.
+// keepBlockDev indicates if backing block device should be not be deactivated when volume is unmounted.
+func (d *plugin) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operation) (bool, error) {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	ourUnmount := false
+	mountPath := vol.MountPath()
+
+	refCount := vol.MountRefCountDecrement()
+
+	// Check if already mounted.
+	if vol.contentType == ContentTypeFS && linux.IsMountPoint(mountPath) {
+		if refCount > 0 {
+			d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": vol.name, "refCount": refCount})
+			return false, ErrInUse
+		}
+
+		err = TryUnmount(mountPath, 0)
+		if err != nil {
+			return false, fmt.Errorf("Failed to unmount plugin volume: %w", err)
+		}
+
+		d.logger.Debug("Unmounted plugin volume", logger.Ctx{"volName": vol.name, "path": mountPath, "keepBlockDev": keepBlockDev})
+
+		if !keepBlockDev {
+			_, err = d.deactivateVolume(vol)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		ourUnmount = true
+	} else if vol.contentType == ContentTypeBlock || vol.contentType == ContentTypeISO {
+		// For VMs, unmount the filesystem volume.
+		if vol.IsVMBlock() {
+			fsVol := vol.NewVMBlockFilesystemVolume()
+			ourUnmount, err = d.UnmountVolume(fsVol, false, op)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		if !keepBlockDev {
+			if refCount > 0 {
+				d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": vol.name, "refCount": refCount})
+				return false, ErrInUse
+			}
+
+			deactivated, err := d.deactivateVolume(vol)
+			if err != nil {
+				return false, err
+			}
+
+			ourUnmount = ourUnmount || deactivated
+		}
+	}
+
+	return ourUnmount, nil
+}
+
+// RenameVolume renames a volume and its snapshots.
+func (d *plugin) RenameVolume(vol Volume, newVolName string, op *operations.Operation) error {
+	return vol.UnmountTask(func(op *operations.Operation) error {
+		reverter := revert.New()
+		defer reverter.Fail()
+
+		err := d.renameVolume(vol, newVolName)
+		if err != nil {
+			return err
+		}
+
+		newVol := NewVolume(d, d.name, vol.volType, vol.contentType, newVolName, vol.config, vol.poolConfig)
+		reverter.Add(func() { _ = d.renameVolume(newVol, vol.name) })
+
+		// Rename volume dir.
+		if vol.contentType == ContentTypeFS {
+			err = genericVFSRenameVolume(d, vol, newVolName, op)
+			if err != nil {
+				return err
+			}
+		}
+
+		// For VMs, also rename the filesystem volume.
+		if vol.IsVMBlock() {
+			fsVol := vol.NewVMBlockFilesystemVolume()
+			err = d.RenameVolume(fsVol, newVolName, op)
+			if err != nil {
+				return err
+			}
+		}
+
+		reverter.Success()
+		return nil
+	}, false, op)
+}
+
+// MigrateVolume sends a volume for migration.
+func (d *plugin) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *migration.VolumeSourceArgs, op *operations.Operation) error {
+	return genericVFSMigrateVolume(d, d.state, vol, conn, volSrcArgs, op)
+}
+
+// BackupVolume creates an exported version of a volume.
+func (d *plugin) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+}
+
+// CreateVolumeSnapshot creates a snapshot of a volume.
+func (d *plugin) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	if !conn.info.Snapshots {
+		return errors.New("Storage plugin doesn't support snapshots")
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+	sourcePath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
+
+	if linux.IsMountPoint(sourcePath) {
+		// Attempt to sync and freeze filesystem, but do not error if not able to freeze (as filesystem
+		// could still be busy), as we do not guarantee the consistency of a snapshot.
+		unfreezeFS, err := d.filesystemFreeze(sourcePath)
+		if err == nil {
+			defer func() { _ = unfreezeFS() }()
+		}
+	}
+
+	// Create the parent directory.
+	err = createParentSnapshotDirIfMissing(d.name, snapVol.volType, parentName)
+	if err != nil {
+		return err
+	}
+
+	err = snapVol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	_, err = conn.client.CreateVolumeSnapshot(ctx, d.volumeRequest(snapVol))
+	if err != nil {
+		return fmt.Errorf("Failed creating volume snapshot: %w", err)
+	}
+
+	reverter.Add(func() { _ = d.DeleteVolumeSnapshot(snapVol, op) })
+
+	// For VMs, also snapshot the filesystem volume.
+	if snapVol.IsVMBlock() {
+		fsVol := snapVol.NewVMBlockFilesystemVolume()
+		err := d.CreateVolumeSnapshot(fsVol, op)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = d.DeleteVolumeSnapshot(fsVol, op) })
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// DeleteVolumeSnapshot removes a snapshot from the storage device.
+func (d *plugin) DeleteVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	exists, err := d.HasVolume(snapVol)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		conn, err := d.connect()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := d.context()
+		defer cancel()
+
+		_, err = conn.client.DeleteVolumeSnapshot(ctx, d.volumeRequest(snapVol))
+		if err != nil {
+			return fmt.Errorf("Failed deleting volume snapshot: %w", err)
+		}
+	}
+
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+	mountPath := snapVol.MountPath()
+
+	if snapVol.contentType == ContentTypeFS {
+		err = os.Remove(mountPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("Failed to remove '%s': %w", mountPath, err)
+		}
+	}
+
+	// Remove the parent snapshot directory if this is the last snapshot being removed.
+	err = deleteParentSnapshotDirIfEmpty(d.name, snapVol.volType, parentName)
+	if err != nil {
+		return err
+	}
+
+	// For VMs, also delete the filesystem volume snapshot.
+	if snapVol.IsVMBlock() {
+		fsVol := snapVol.NewVMBlockFilesystemVolume()
+		err := d.DeleteVolumeSnapshot(fsVol, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MountVolumeSnapshot mounts a volume snapshot.
+func (d *plugin) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	unlock, err := snapVol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Activate the snapshot if needed.
+	devPath, activated, err := d.activateVolume(snapVol)
+	if err != nil {
+		return err
+	}
+
+	if activated {
+		reverter.Add(func() { _, _ = d.deactivateVolume(snapVol) })
+	}
+
+	if snapVol.contentType == ContentTypeFS {
+		mountPath := snapVol.MountPath()
+		if !linux.IsMountPoint(mountPath) {
+			err = snapVol.EnsureMountPath()
+			if err != nil {
+				return err
+			}
+
+			fsType := snapVol.ConfigBlockFilesystem()
+
+			if snapVol.mountFilesystemProbe {
+				fsType, err = fsProbe(devPath)
+				if err != nil {
+					return fmt.Errorf("Failed probing filesystem: %w", err)
+				}
+			}
+
+			mountFlags, mountOptions := linux.ResolveMountOptions(strings.Split(snapVol.ConfigBlockMountOptions(), ","))
+
+			// Snapshots share the filesystem UUID of their parent which some filesystems won't allow
+			// to be mounted twice.
+			if renegerateFilesystemUUIDNeeded(fsType) {
+				if fsType == "xfs" {
+					idx := strings.Index(mountOptions, "nouuid")
+					if idx < 0 {
+						mountOptions += ",nouuid"
+					}
+				} else {
+					err = regenerateFilesystemUUID(fsType, devPath)
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			err = TryMount(devPath, mountPath, fsType, mountFlags|unix.MS_RDONLY, mountOptions)
+			if err != nil {
+				return err
+			}
+
+			d.logger.Debug("Mounted plugin volume snapshot", logger.Ctx{"volName": snapVol.name, "dev": devPath, "path": mountPath, "options": mountOptions})
+		}
+	} else if snapVol.IsVMBlock() {
+		// For VMs, mount the filesystem volume.
+		fsVol := snapVol.NewVMBlockFilesystemVolume()
+		err = d.MountVolumeSnapshot(fsVol, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	snapVol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolumeSnapshot() when done.
+	reverter.Success()
+	return nil
+}
+
+// UnmountVolumeSnapshot unmounts a volume snapshot.
+func (d *plugin) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (bool, error) {
+	unlock, err := snapVol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	ourUnmount := false
+	mountPath := snapVol.MountPath()
+	refCount := snapVol.MountRefCountDecrement()
+
+	if snapVol.contentType == ContentTypeFS && linux.IsMountPoint(mountPath) {
+		if refCount > 0 {
+			d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": snapVol.name, "refCount": refCount})
+			return false, ErrInUse
+		}
+
+		err = TryUnmount(mountPath, 0)
+		if err != nil {
+			return false, fmt.Errorf("Failed to unmount plugin volume snapshot: %w", err)
+		}
+
+		d.logger.Debug("Unmounted plugin volume snapshot", logger.Ctx{"volName": snapVol.name, "path": mountPath})
+		ourUnmount = true
+	} else if snapVol.IsVMBlock() {
+		// For VMs, unmount the filesystem volume.
+		fsVol := snapVol.NewVMBlockFilesystemVolume()
+		ourUnmount, err = d.UnmountVolumeSnapshot(fsVol, op)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if refCount > 0 {
+		return ourUnmount, nil
+	}
+
+	_, err = d.deactivateVolume(snapVol)
+	if err != nil {
+		return false, err
+	}
+
+	return ourUnmount, nil
+}
+
+// VolumeSnapshots returns a list of snapshots for the volume (in no particular order).
+func (d *plugin) VolumeSnapshots(vol Volume, op *operations.Operation) ([]string, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	if !conn.info.Snapshots {
+		return nil, nil
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.ListVolumeSnapshots(ctx, d.volumeRequest(vol))
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Snapshots, nil
+}
+
+// RestoreVolume restores a volume from a snapshot.
+func (d *plugin) RestoreVolume(vol Volume, snapshotName string, op *operations.Operation) error {
+	ourUnmount, err := d.UnmountVolume(vol, false, op)
+	if err != nil {
+		return err
+	}
+
+	if ourUnmount {
+		defer func() { _ = d.MountVolume(vol, op) }()
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	_, err = conn.client.RestoreVolume(ctx, &storageplugin.RestoreVolumeRequest{
+		Pool:     d.name,
+		Volume:   d.pluginVolume(vol),
+		Snapshot: snapshotName,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed restoring volume: %w", err)
+	}
+
+	// For VMs, also restore the filesystem volume.
+	if vol.IsVMBlock() {
+		fsVol := vol.NewVMBlockFilesystemVolume()
+		err = d.RestoreVolume(fsVol, snapshotName, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenameVolumeSnapshot renames a volume snapshot.
+func (d *plugin) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	parentName, snapshotOnlyName, _ := api.GetParentAndSnapshotName(snapVol.name)
+
+	err := d.renameVolumeSnapshot(snapVol, newSnapshotName)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		newSnapVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, GetSnapshotVolumeName(parentName, newSnapshotName), snapVol.config, snapVol.poolConfig)
+		_ = d.renameVolumeSnapshot(newSnapVol, snapshotOnlyName)
+	})
+
+	if snapVol.contentType == ContentTypeFS {
+		err = genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	// For VMs, also rename the filesystem volume snapshot.
+	if snapVol.IsVMBlock() {
+		fsVol := snapVol.NewVMBlockFilesystemVolume()
+		err := d.RenameVolumeSnapshot(fsVol, newSnapshotName, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// activateVolume maps the volume on the host and returns its device path.
+// The returned boolean indicates whether this call caused the volume to be activated.
+func (d *plugin) activateVolume(vol Volume) (string, bool, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return "", false, err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.ActivateVolume(ctx, d.volumeRequest(vol))
+	if err != nil {
+		return "", false, fmt.Errorf("Failed activating volume %q: %w", vol.name, err)
+	}
+
+	if resp.DevicePath == "" {
+		return "", false, fmt.Errorf("Storage plugin returned no device path for volume %q", vol.name)
+	}
+
+	if resp.Activated {
+		d.logger.Debug("Activated plugin volume", logger.Ctx{"volName": vol.name, "dev": resp.DevicePath})
+	}
+
+	return resp.DevicePath, resp.Activated, nil
+}
+
+// deactivateVolume unmaps the volume from the host.
+// Returns true if this call caused the volume to be deactivated.
+func (d *plugin) deactivateVolume(vol Volume) (bool, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	resp, err := conn.client.DeactivateVolume(ctx, d.volumeRequest(vol))
+	if err != nil {
+		return false, fmt.Errorf("Failed deactivating volume %q: %w", vol.name, err)
+	}
+
+	if resp.Deactivated {
+		d.logger.Debug("Deactivated plugin volume", logger.Ctx{"volName": vol.name})
+	}
+
+	return resp.Deactivated, nil
+}
+
+// renameVolume renames a volume on the storage backend.
+func (d *plugin) renameVolume(vol Volume, newVolName string) error {
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	_, err = conn.client.RenameVolume(ctx, &storageplugin.RenameVolumeRequest{
+		Pool:    d.name,
+		Volume:  d.pluginVolume(vol),
+		NewName: newVolName,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed renaming volume: %w", err)
+	}
+
+	return nil
+}
+
+// renameVolumeSnapshot renames a volume snapshot on the storage backend.
+func (d *plugin) renameVolumeSnapshot(snapVol Volume, newSnapshotName string) error {
+	conn, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	_, err = conn.client.RenameVolumeSnapshot(ctx, &storageplugin.RenameVolumeRequest{
+		Pool:    d.name,
+		Volume:  d.pluginVolume(snapVol),
+		NewName: newSnapshotName,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed renaming volume snapshot: %w", err)
+	}
+
+	return nil
+}
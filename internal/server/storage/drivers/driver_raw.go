@@ -0,0 +1,113 @@
+package drivers
+
+import (
+	"fmt"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// raw registers existing block devices of the host as block volumes, without provisioning them.
+// Each volume uses the device set in its "source" option, which is linked from the volume directory.
+// The filesystem volumes of virtual machines are kept as directories on the host.
+type raw struct {
+	common
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *raw) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+	}
+
+	return nil
+}
+
+// Info returns info about the driver and its environment.
+func (d *raw) Info() Info {
+	return Info{
+		Name:                         "raw",
+		Version:                      "1",
+		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeVM},
+		VolumeMultiNode:              d.isRemote(),
+		BlockBacking:                 false,
+		RunningCopyFreeze:            true,
+		DirectIO:                     true,
+		IOUring:                      true,
+		MountedRoot:                  false,
+		Buckets:                      false,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *raw) FillConfig() error {
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *raw) Create() error {
+	if d.config["source"] != "" {
+		return fmt.Errorf("Storage pools of driver %q don't have a source, set it on each volume instead", "raw")
+	}
+
+	return d.FillConfig()
+}
+
+// Delete removes the storage pool from the storage device.
+// The block devices of the volumes are left untouched.
+func (d *raw) Delete(op *operations.Operation) error {
+	return wipeDirectory(GetPoolMountPath(d.name))
+}
+
+// Validate checks that all provided keys are supported and that no conflicting or missing configuration is present.
+func (d *raw) Validate(config map[string]string) error {
+	return d.validatePool(config, nil, nil)
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *raw) Update(changedConfig map[string]string) error {
+	return nil
+}
+
+// Mount simulates mounting a storage pool, as the block devices are always available on the host.
+func (d *raw) Mount() (bool, error) {
+	return false, nil
+}
+
+// Unmount simulates unmounting a storage pool.
+func (d *raw) Unmount() (bool, error) {
+	return false, nil
+}
+
+// GetResources returns the pool resource usage information.
+// Both the total and used space are the size of the block devices registered as volumes.
+func (d *raw) GetResources() (*api.ResourcesStoragePool, error) {
+	devices, err := d.registeredDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	res := api.ResourcesStoragePool{}
+	for devPath := range devices {
+		sizeBytes, err := BlockDiskSizeBytes(devPath)
+		if err != nil {
+			continue
+		}
+
+		res.Space.Total += uint64(sizeBytes)
+		res.Space.Used += uint64(sizeBytes)
+	}
+
+	return &res, nil
+}
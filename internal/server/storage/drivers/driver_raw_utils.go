@@ -0,0 +1,96 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// rawDeviceLinkPath returns the path of the link to the block device of a volume.
+func rawDeviceLinkPath(vol Volume) string {
+	return filepath.Join(vol.MountPath(), genericVolumeDiskFile)
+}
+
+// validateRawDevice checks that the path is a block device of the host.
+func validateRawDevice(devPath string) error {
+	if !filepath.IsAbs(devPath) {
+		return fmt.Errorf("Block device path %q must be absolute", devPath)
+	}
+
+	fi, err := os.Stat(devPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("Block device %q doesn't exist", devPath)
+		}
+
+		return err
+	}
+
+	if fi.Mode()&os.ModeDevice == 0 || fi.Mode()&os.ModeCharDevice != 0 {
+		return fmt.Errorf("Path %q isn't a block device", devPath)
+	}
+
+	// The kernel refuses exclusive opens of block devices that are mounted or held by another device
+	// (device mapper, RAID, LVM...).
+	fd, err := unix.Open(devPath, unix.O_RDONLY|unix.O_EXCL|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if errors.Is(err, unix.EBUSY) {
+			return fmt.Errorf("Block device %q is in use on the host", devPath)
+		}
+
+		return fmt.Errorf("Failed opening block device %q: %w", devPath, err)
+	}
+
+	_ = unix.Close(fd)
+
+	return nil
+}
+
+// registeredDevices returns the block devices used by the volumes of the pool, resolved to their kernel
+// name, and the name of the volume using them.
+func (d *raw) registeredDevices() (map[string]string, error) {
+	vols, err := d.ListVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := map[string]string{}
+	for _, vol := range vols {
+		if !IsContentBlock(vol.contentType) {
+			continue
+		}
+
+		devPath, err := filepath.EvalSymlinks(rawDeviceLinkPath(vol))
+		if err != nil {
+			// Devices that are currently missing on the host.
+			continue
+		}
+
+		devices[devPath] = vol.name
+	}
+
+	return devices, nil
+}
+
+// checkDeviceUnused checks that the block device isn't already used by another volume of the pool.
+func (d *raw) checkDeviceUnused(devPath string) error {
+	resolvedPath, err := filepath.EvalSymlinks(devPath)
+	if err != nil {
+		return err
+	}
+
+	devices, err := d.registeredDevices()
+	if err != nil {
+		return err
+	}
+
+	volName, found := devices[resolvedPath]
+	if found {
+		return fmt.Errorf("Block device %q is already used by volume %q", devPath, volName)
+	}
+
+	return nil
+}
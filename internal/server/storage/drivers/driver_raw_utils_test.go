@@ -0,0 +1,16 @@
+package drivers
+
+import (
+	"fmt"
+)
+
+func Example_validateRawDevice() {
+	for _, devPath := range []string{"dev/sda", "/dev/missing-device", "/dev/null", "/dev"} {
+		fmt.Println(validateRawDevice(devPath))
+	}
+
+	// Output: Block device path "dev/sda" must be absolute
+	// Block device "/dev/missing-device" doesn't exist
+	// Path "/dev/null" isn't a block device
+	// Path "/dev" isn't a block device
+}
@@ -0,0 +1,361 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/lxc/incus/v6/internal/instancewriter"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	"github.com/lxc/incus/v6/internal/server/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// errRawSnapshots is returned for snapshot operations as block devices can't be snapshotted by the driver.
+var errRawSnapshots = errors.New("Snapshots aren't supported by raw block devices")
+
+// CreateVolume creates an empty volume and can optionally fill it by executing the supplied filler function.
+// The block device is the existing device set in "source", which is linked from the volume directory.
+func (d *raw) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error {
+	if vol.volType == VolumeTypeCustom && vol.contentType != ContentTypeBlock {
+		return errors.New("Only block custom volumes are supported")
+	}
+
+	if util.PathExists(vol.MountPath()) {
+		return fmt.Errorf("Volume path %q already exists", vol.MountPath())
+	}
+
+	devPath := vol.config["source"]
+	if devPath == "" {
+		return fmt.Errorf("Volume %q requires %q to be set to the path of a block device", vol.name, "source")
+	}
+
+	err := validateRawDevice(devPath)
+	if err != nil {
+		return err
+	}
+
+	err = d.checkDeviceUnused(devPath)
+	if err != nil {
+		return err
+	}
+
+	// Only check the size when set on the volume, the device is used whole otherwise.
+	if vol.config["size"] != "" {
+		err = d.checkDeviceSize(devPath, vol.config["size"])
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Create the volume directory, holding the filesystem part of virtual machines.
+	err = vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	volPath := vol.MountPath()
+	reverter.Add(func() { _ = os.RemoveAll(volPath) })
+
+	err = os.Symlink(devPath, rawDeviceLinkPath(vol))
+	if err != nil {
+		return fmt.Errorf("Failed linking block device %q: %w", devPath, err)
+	}
+
+	// Run the volume filler function if supplied.
+	err = d.runFiller(vol, devPath, filler, false)
+	if err != nil {
+		return err
+	}
+
+	// Move the GPT alt header to end of disk if needed and if filler specified.
+	if vol.IsVMBlock() && filler != nil && filler.Fill != nil {
+		err = d.moveGPTAltHeader(devPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// checkDeviceSize checks that the block device is at least as large as the size.
+func (d *raw) checkDeviceSize(devPath string, size string) error {
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	devSizeBytes, err := BlockDiskSizeBytes(devPath)
+	if err != nil {
+		return err
+	}
+
+	if devSizeBytes < sizeBytes {
+		return fmt.Errorf("Block device %q has a size of %s which is less than the requested size of %s", devPath, units.GetByteSizeStringIEC(devSizeBytes, 2), units.GetByteSizeStringIEC(sizeBytes, 2))
+	}
+
+	return nil
+}
+
+// CreateVolumeFromBackup restores a backup tarball onto the storage device.
+func (d *raw) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+	if len(srcBackup.Snapshots) > 0 {
+		return nil, nil, errRawSnapshots
+	}
+
+	return genericVFSBackupUnpack(d, d.state.OS, vol, nil, srcData, op)
+}
+
+// CreateVolumeFromCopy provides same-pool volume copying functionality.
+// Snapshots of the source are never copied.
+func (d *raw) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	if vol.config["source"] == srcVol.config["source"] {
+		return fmt.Errorf("Volume %q requires a different %q than its source", vol.name, "source")
+	}
+
+	return genericVFSCopyVolume(d, nil, vol, srcVol, nil, false, allowInconsistent, op)
+}
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+func (d *raw) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
+	if len(volTargetArgs.Snapshots) > 0 {
+		return errRawSnapshots
+	}
+
+	return genericVFSCreateVolumeFromMigration(d, nil, vol, conn, volTargetArgs, preFiller, op)
+}
+
+// RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
+func (d *raw) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
+	if len(srcSnapshots) > 0 {
+		return errRawSnapshots
+	}
+
+	return genericVFSCopyVolume(d, nil, vol, srcVol, nil, true, allowInconsistent, op)
+}
+
+// DeleteVolume deletes a volume of the storage device.
+// Only the volume directory is removed, the block device and its content are left untouched.
+func (d *raw) DeleteVolume(vol Volume, op *operations.Operation) error {
+	volPath := vol.MountPath()
+	err := forceRemoveAll(volPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Failed to remove '%s': %w", volPath, err)
+	}
+
+	return nil
+}
+
+// HasVolume indicates whether a specific volume exists on the storage pool.
+func (d *raw) HasVolume(vol Volume) (bool, error) {
+	return genericVFSHasVolume(vol)
+}
+
+// ValidateVolume validates the supplied volume config. Optionally removes invalid keys from the volume's config.
+func (d *raw) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
+	rules := map[string]func(value string) error{
+		"source": validate.Optional(validate.IsAbsFilePath),
+	}
+
+	return d.validateVolume(vol, rules, removeUnknownKeys)
+}
+
+// UpdateVolume applies config changes to the volume.
+func (d *raw) UpdateVolume(vol Volume, changedConfig map[string]string) error {
+	_, sourceChanged := changedConfig["source"]
+	if sourceChanged {
+		return fmt.Errorf("Volume option %q can't be changed", "source")
+	}
+
+	newSize, sizeChanged := changedConfig["size"]
+	if sizeChanged {
+		err := d.SetVolumeQuota(vol, newSize, false, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetVolumeUsage returns the disk space used by the volume, which is the size of its block device.
+func (d *raw) GetVolumeUsage(vol Volume) (int64, error) {
+	if !IsContentBlock(vol.contentType) {
+		return -1, ErrNotSupported
+	}
+
+	devPath, err := d.GetVolumeDiskPath(vol)
+	if err != nil {
+		return -1, err
+	}
+
+	return BlockDiskSizeBytes(devPath)
+}
+
+// SetVolumeQuota checks that the block device is large enough for the size, as it can't be resized by the driver.
+// Does nothing for the filesystem part of virtual machines as it's stored on the host.
+func (d *raw) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
+	if vol.contentType != ContentTypeBlock || size == "" || size == "0" {
+		return nil
+	}
+
+	devPath, err := d.GetVolumeDiskPath(vol)
+	if err != nil {
+		return err
+	}
+
+	err = d.checkDeviceSize(devPath, size)
+	if err != nil {
+		return fmt.Errorf("%w, grow the device on the host first", err)
+	}
+
+	return nil
+}
+
+// GetVolumeDiskPath returns the location of the block device of the volume.
+func (d *raw) GetVolumeDiskPath(vol Volume) (string, error) {
+	if !IsContentBlock(vol.contentType) {
+		return "", ErrNotSupported
+	}
+
+	devPath, err := os.Readlink(rawDeviceLinkPath(vol))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("Volume %q has no block device", vol.name)
+		}
+
+		return "", err
+	}
+
+	return devPath, nil
+}
+
+// ListVolumes returns a list of volumes in storage pool.
+func (d *raw) ListVolumes() ([]Volume, error) {
+	var vols []Volume
+
+	for _, volType := range d.Info().VolumeTypes {
+		volTypePath := filepath.Join(GetPoolMountPath(d.name), BaseDirectories[volType][0])
+		ents, err := os.ReadDir(volTypePath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list directory %q for volume type %q: %w", volTypePath, volType, err)
+		}
+
+		for _, ent := range ents {
+			vols = append(vols, NewVolume(d, d.name, volType, ContentTypeBlock, ent.Name(), make(map[string]string), d.config))
+		}
+	}
+
+	return vols, nil
+}
+
+// MountVolume makes sure that the block device of the volume is available.
+func (d *raw) MountVolume(vol Volume, op *operations.Operation) error {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	err = vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	if IsContentBlock(vol.contentType) {
+		devPath, err := d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		if !util.PathExists(devPath) {
+			return fmt.Errorf("Block device %q of volume %q is missing", devPath, vol.name)
+		}
+	}
+
+	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
+	return nil
+}
+
+// UnmountVolume simulates unmounting a volume.
+// As the block devices are always available, it returns false indicating the volume was already unmounted.
+func (d *raw) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operation) (bool, error) {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	refCount := vol.MountRefCountDecrement()
+	if refCount > 0 {
+		d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": vol.name, "refCount": refCount})
+		return false, ErrInUse
+	}
+
+	return false, nil
+}
+
+// RenameVolume renames a volume.
+func (d *raw) RenameVolume(vol Volume, newVolName string, op *operations.Operation) error {
+	return genericVFSRenameVolume(d, vol, newVolName, op)
+}
+
+// MigrateVolume sends a volume for migration.
+func (d *raw) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *migration.VolumeSourceArgs, op *operations.Operation) error {
+	return genericVFSMigrateVolume(d, d.state, vol, conn, volSrcArgs, op)
+}
+
+// BackupVolume copies a volume to a specified target path.
+// This driver does not support optimized backups.
+func (d *raw) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, nil, op)
+}
+
+// CreateVolumeSnapshot isn't supported as the block devices can't be snapshotted.
+func (d *raw) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	return errRawSnapshots
+}
+
+// DeleteVolumeSnapshot removes a snapshot from the storage device.
+func (d *raw) DeleteVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	return errRawSnapshots
+}
+
+// MountVolumeSnapshot isn't supported as the block devices can't be snapshotted.
+func (d *raw) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	return errRawSnapshots
+}
+
+// UnmountVolumeSnapshot isn't supported as the block devices can't be snapshotted.
+func (d *raw) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (bool, error) {
+	return false, errRawSnapshots
+}
+
+// VolumeSnapshots returns a list of snapshots for the volume, which is always empty.
+func (d *raw) VolumeSnapshots(vol Volume, op *operations.Operation) ([]string, error) {
+	return []string{}, nil
+}
+
+// RestoreVolume isn't supported as the block devices can't be snapshotted.
+func (d *raw) RestoreVolume(vol Volume, snapshotName string, op *operations.Operation) error {
+	return errRawSnapshots
+}
+
+// RenameVolumeSnapshot isn't supported as the block devices can't be snapshotted.
+func (d *raw) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
+	return errRawSnapshots
+}
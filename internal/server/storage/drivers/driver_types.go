@@ -20,6 +20,7 @@ type Info struct {
 	MountedRoot                  bool         // Whether the pool directory itself is a mount.
 	Deactivate                   bool         // Whether an unmount action is required prior to removing the pool.
 	ZeroUnpack                   bool         // Whether to write zeroes (no discard) during unpacking.
+	InstantCopy                  bool         // Whether volumes can be created as copy-on-write clones of other volumes.
 }
 
 // VolumeFiller provides a struct for filling a volume.
@@ -29,3 +30,10 @@ type VolumeFiller struct {
 
 	Fingerprint string // If the Filler will unpack an image, it should be this fingerprint.
 }
+
+// Types of the changes reported by VolumeSnapshotDiff.
+const (
+	VolumeDiffAdded    = "added"
+	VolumeDiffModified = "modified"
+	VolumeDiffDeleted  = "deleted"
+)
@@ -153,6 +153,7 @@ func (d *zfs) Info() Info {
 		DirectIO:                     zfsDirectIO,
 		MountedRoot:                  false,
 		Buckets:                      true,
+		InstantCopy:                  true,
 	}
 
 	return info
@@ -542,7 +543,9 @@ func (d *zfs) Validate(config map[string]string) error {
 
 			return validate.IsBool(value)
 		}),
-		"zfs.export": validate.Optional(validate.IsBool),
+		"zfs.export":                  validate.Optional(validate.IsBool),
+		"maintenance.fstrim.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+		"maintenance.scrub.schedule":  validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
 	}
 
 	return d.validatePool(config, rules, d.commonVolumeRules())
@@ -736,9 +739,75 @@ func (d *zfs) GetResources() (*api.ResourcesStoragePool, error) {
 	res.Space.Total = used + available
 	res.Space.Used = used
 
+	res.Statistics, err = d.GetStatistics()
+	if err != nil {
+		return nil, err
+	}
+
 	return &res, nil
 }
 
+// GetStatistics returns the deduplication and compression statistics of the pool.
+// Deduplication applies to the whole zpool while compression is reported for the pool's dataset.
+func (d *zfs) GetStatistics() (*api.StoragePoolStatistics, error) {
+	props, err := d.getDatasetProperties(d.config["zfs.pool_name"], "compressratio", "logicalused", "used")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := api.StoragePoolStatistics{}
+
+	stats.CompressionRatio, err = strconv.ParseFloat(strings.TrimSuffix(props["compressratio"], "x"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing compression ratio %q: %w", props["compressratio"], err)
+	}
+
+	stats.LogicalUsed, err = strconv.ParseUint(props["logicalused"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing logical used space %q: %w", props["logicalused"], err)
+	}
+
+	stats.Used, err = strconv.ParseUint(props["used"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing used space %q: %w", props["used"], err)
+	}
+
+	poolName := strings.Split(d.config["zfs.pool_name"], "/")[0]
+	dedupRatio, err := subprocess.RunCommand("zpool", "get", "-H", "-p", "-o", "value", "dedupratio", poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.DedupRatio, err = strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(dedupRatio), "x"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing deduplication ratio %q: %w", dedupRatio, err)
+	}
+
+	return &stats, nil
+}
+
+// GetHealth returns the state of the zpool, the errors of its devices and the status of its last scrub.
+func (d *zfs) GetHealth() (*api.StoragePoolHealth, error) {
+	poolName, _, _ := strings.Cut(d.config["zfs.pool_name"], "/")
+	out, err := subprocess.RunCommand("zpool", "status", "-p", poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return zfsParseStatus(out), nil
+}
+
+// Scrub starts a scrub of the zpool.
+func (d *zfs) Scrub() error {
+	poolName, _, _ := strings.Cut(d.config["zfs.pool_name"], "/")
+	_, err := subprocess.RunCommand("zpool", "scrub", poolName)
+	if err != nil {
+		return fmt.Errorf("Failed scrubbing zpool %q: %w", poolName, err)
+	}
+
+	return nil
+}
+
 // MigrationType returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *zfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []localMigration.Type {
 	var rsyncFeatures []string
@@ -746,13 +815,13 @@ func (d *zfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshot
 	// Do not pass compression argument to rsync if the associated
 	// config key, that is rsync.compression, is set to false.
 	if util.IsFalse(d.Config()["rsync.compression"]) {
-		rsyncFeatures = []string{"xattrs", "delete", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	} else {
-		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional"}
+		rsyncFeatures = []string{"xattrs", "delete", "compress", "bidirectional", migration.RsyncFeatureSparseBlocks}
 	}
 
 	// Detect ZFS features.
-	features := []string{migration.ZFSFeatureMigrationHeader, "compress"}
+	features := []string{migration.ZFSFeatureMigrationHeader, "compress", migration.ZFSFeatureResumeTokens}
 
 	if contentType == ContentTypeFS {
 		features = append(features, migration.ZFSFeatureZvolFilesystems)
@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
@@ -16,6 +17,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/subprocess"
 	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
@@ -350,6 +352,19 @@ func (d *zfs) sendDataset(dataset string, parent string, volSrcArgs *migration.V
 	}
 
 	args = append(args, dataset)
+
+	return d.runSend(args, conn, tracker)
+}
+
+// sendResumedDataset resumes the interrupted send matching the receive resume token of the target.
+func (d *zfs) sendResumedDataset(token string, conn io.ReadWriteCloser, tracker *ioprogress.ProgressTracker) error {
+	defer func() { _ = conn.Close() }()
+
+	return d.runSend([]string{"send", "-t", token}, conn, tracker)
+}
+
+// runSend runs zfs send with the arguments and writes the stream to the connection.
+func (d *zfs) runSend(args []string, conn io.ReadWriteCloser, tracker *ioprogress.ProgressTracker) error {
 	cmd := exec.Command("zfs", args...)
 
 	stderr, err := cmd.StderrPipe()
@@ -386,13 +401,20 @@ func (d *zfs) sendDataset(dataset string, parent string, volSrcArgs *migration.V
 	return nil
 }
 
-func (d *zfs) receiveDataset(vol Volume, r io.Reader, tracker *ioprogress.ProgressTracker) error {
+func (d *zfs) receiveDataset(vol Volume, r io.Reader, resumable bool, tracker *ioprogress.ProgressTracker) error {
 	// Assemble zfs receive command.
-	args := []string{"receive", "-x", "mountpoint", "-F", "-u", d.dataset(vol, false)}
+	args := []string{"receive", "-x", "mountpoint", "-F", "-u"}
 	if vol.ContentType() == ContentTypeBlock || d.isBlockBacked(vol) {
-		args = []string{"receive", "-F", "-u", d.dataset(vol, false)}
+		args = []string{"receive", "-F", "-u"}
+	}
+
+	// Keep the state of an interrupted receive so that it can be resumed.
+	if resumable {
+		args = append(args, "-s")
 	}
 
+	args = append(args, d.dataset(vol, false))
+
 	// Setup progress tracker.
 	stdin := r
 	if tracker != nil {
@@ -434,6 +456,111 @@ type ZFSDataset struct {
 // ZFSMetaDataHeader is the meta data header about the datasets being sent/stored.
 type ZFSMetaDataHeader struct {
 	SnapshotDatasets []ZFSDataset `json:"snapshot_datasets" yaml:"snapshot_datasets"`
+
+	// ResumeToken is the receive resume token of an interrupted refresh, sent back by the target.
+	ResumeToken string `json:"resume_token,omitempty" yaml:"resume_token,omitempty"`
+}
+
+// zfsResumeProperty is the user property flagging the temporary snapshots kept to resume interrupted migrations.
+const zfsResumeProperty = "incus:migration_resume"
+
+// zfsResumeHeader is sent by the source in reply to a resume token, to let the target know whether the
+// interrupted stream is resumed before the remaining streams of the refresh.
+type zfsResumeHeader struct {
+	Resume bool `json:"resume"`
+
+	// Snapshot is the name of the snapshot created by the resumed stream, empty for the volume itself.
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// token and dataset are the resume token and the snapshot whose send is resumed, only known to the source.
+	token   string
+	dataset string
+}
+
+// deleteResumeSnapshots deletes the temporary snapshots kept by the interrupted migrations of the volume.
+func (d *zfs) deleteResumeSnapshots(vol Volume) {
+	out, err := subprocess.RunCommand("zfs", "get", "-H", "-d", "1", "-t", "snapshot", "-o", "name,value", zfsResumeProperty, d.dataset(vol, false))
+	if err != nil {
+		return
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[1] != "true" {
+			continue
+		}
+
+		// Snapshots being sent can't be deleted.
+		_, err := subprocess.RunCommand("zfs", "destroy", "-r", "-d", fields[0])
+		if err != nil {
+			d.logger.Debug("Failed deleting temporary snapshot of interrupted migration", logger.Ctx{"snapshot": fields[0], "err": err})
+		}
+	}
+}
+
+// receiveResumeToken returns the resume token of an interrupted receive into the volume, if any.
+func (d *zfs) receiveResumeToken(vol Volume) string {
+	token, err := d.getDatasetProperty(d.dataset(vol, false), "receive_resume_token")
+	if err != nil || token == "-" {
+		return ""
+	}
+
+	return token
+}
+
+// abortReceive discards the state of an interrupted receive into the volume, if any.
+func (d *zfs) abortReceive(vol Volume) error {
+	if d.receiveResumeToken(vol) == "" {
+		return nil
+	}
+
+	_, err := subprocess.RunCommand("zfs", "receive", "-A", d.dataset(vol, false))
+	if err != nil {
+		return fmt.Errorf("Failed discarding interrupted receive of volume %q: %w", vol.name, err)
+	}
+
+	return nil
+}
+
+// resumeTransfer checks whether the interrupted send matching the target's resume token can be resumed
+// ahead of the snapshots that remain to be sent.
+// The stream must either create the first of those snapshots or, if there's none left, be the send of a
+// migration snapshot of the volume itself.
+func (d *zfs) resumeTransfer(vol Volume, token string, snapshots []string) *zfsResumeHeader {
+	// The dry run fails if the snapshot of the interrupted send doesn't exist anymore.
+	out, err := subprocess.RunCommand("zfs", "send", "-n", "-v", "-t", token)
+	if err != nil {
+		d.logger.Debug("Interrupted transfer can't be resumed", logger.Ctx{"volume": vol.name, "err": err})
+		return &zfsResumeHeader{}
+	}
+
+	var toName string
+	for _, line := range strings.Split(out, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), " = ")
+		if found && key == "toname" {
+			toName = value
+			break
+		}
+	}
+
+	snapshotName, found := strings.CutPrefix(toName, d.dataset(vol, false)+"@")
+	if !found {
+		return &zfsResumeHeader{}
+	}
+
+	if len(snapshots) > 0 {
+		if snapshotName != "snapshot-"+snapshots[0] {
+			return &zfsResumeHeader{}
+		}
+
+		return &zfsResumeHeader{Resume: true, Snapshot: snapshots[0], token: token, dataset: toName}
+	}
+
+	if !strings.HasPrefix(snapshotName, "migration-") {
+		return &zfsResumeHeader{}
+	}
+
+	return &zfsResumeHeader{Resume: true, token: token, dataset: toName}
 }
 
 func (d *zfs) datasetHeader(vol Volume, snapshots []string) (*ZFSMetaDataHeader, error) {
@@ -473,3 +600,93 @@ func (d *zfs) delegateDataset(vol Volume, pid int) error {
 func ZFSSupportsDelegation() bool {
 	return zfsDelegate
 }
+
+// zfsDiffUnescape decodes the "\0ooo" octal escapes used by "zfs diff" for special characters in paths.
+func zfsDiffUnescape(path string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+4 < len(path) {
+			value, err := strconv.ParseUint(path[i+1:i+5], 8, 8)
+			if err == nil {
+				sb.WriteByte(byte(value))
+				i += 4
+				continue
+			}
+		}
+
+		sb.WriteByte(path[i])
+	}
+
+	return sb.String()
+}
+
+// zfsParseStatus returns the health of a zpool from the output of "zpool status -p".
+// The pool is degraded if it isn't online, if it reports data errors, or if any of its devices is unavailable
+// or had read, write or checksum errors.
+func zfsParseStatus(out string) *api.StoragePoolHealth {
+	fields := map[string]string{}
+	devices := [][]string{}
+
+	var key string
+	for _, line := range strings.Split(out, "\n") {
+		// Continuation lines and the device table are indented with tabs.
+		if strings.HasPrefix(line, "\t") {
+			if key == "config" {
+				device := strings.Fields(line)
+				if len(device) >= 5 && device[0] != "NAME" {
+					devices = append(devices, device)
+				}
+			} else if key != "" {
+				fields[key] += " " + strings.TrimSpace(line)
+			}
+
+			continue
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" || strings.Contains(name, " ") {
+			continue
+		}
+
+		key = name
+		fields[key] = strings.TrimSpace(value)
+	}
+
+	health := &api.StoragePoolHealth{
+		Messages: []string{},
+		Scrub:    fields["scan"],
+	}
+
+	if fields["state"] != "ONLINE" {
+		message := fmt.Sprintf("Pool is %s", fields["state"])
+		if fields["status"] != "" {
+			message += ": " + fields["status"]
+		}
+
+		health.Messages = append(health.Messages, message)
+	}
+
+	for _, device := range devices {
+		name, state := device[0], device[1]
+		if name == fields["pool"] {
+			continue
+		}
+
+		// Parent vdevs are degraded when one of their devices is, which is reported on its own.
+		if !slices.Contains([]string{"ONLINE", "DEGRADED"}, state) {
+			health.Messages = append(health.Messages, fmt.Sprintf("Device %q is %s", name, state))
+		}
+
+		if device[2] != "0" || device[3] != "0" || device[4] != "0" {
+			health.Messages = append(health.Messages, fmt.Sprintf("Device %q has %s read, %s write and %s checksum errors", name, device[2], device[3], device[4]))
+		}
+	}
+
+	if fields["errors"] != "" && fields["errors"] != "No known data errors" {
+		health.Messages = append(health.Messages, fmt.Sprintf("Data errors: %s", fields["errors"]))
+	}
+
+	return health
+}
@@ -0,0 +1,35 @@
+package drivers
+
+import (
+	"fmt"
+)
+
+func Example_zfsParseStatus() {
+	out := `  pool: tank
+ state: DEGRADED
+status: One or more devices could not be used because the label is missing or
+	invalid.  Sufficient replicas exist for the pool to continue
+	functioning in a degraded state.
+  scan: scrub repaired 0B in 00:00:01 with 0 errors on Sun Oct 11 00:24:02 2026
+config:
+
+	NAME        STATE     READ WRITE CKSUM
+	tank        DEGRADED     0     0     0
+	  mirror-0  DEGRADED     0     0     0
+	    sda     ONLINE       0     0     3
+	    sdb     UNAVAIL      0     0     0
+
+errors: No known data errors
+`
+
+	health := zfsParseStatus(out)
+	fmt.Println(health.Scrub)
+	for _, message := range health.Messages {
+		fmt.Println(message)
+	}
+
+	// Output: scrub repaired 0B in 00:00:01 with 0 errors on Sun Oct 11 00:24:02 2026
+	// Pool is DEGRADED: One or more devices could not be used because the label is missing or invalid.  Sufficient replicas exist for the pool to continue functioning in a degraded state.
+	// Device "sda" has 0 read, 0 write and 3 checksum errors
+	// Device "sdb" is UNAVAIL
+}
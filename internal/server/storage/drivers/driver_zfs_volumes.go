@@ -569,8 +569,134 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 	return postHook, cleanup, nil
 }
 
+// AdoptVolume adopts an existing dataset of the pool's zpool as the volume.
+func (d *zfs) AdoptVolume(vol Volume, source string, op *operations.Operation) (revert.Hook, error) {
+	if vol.volType != VolumeTypeCustom || vol.contentType == ContentTypeISO {
+		return nil, ErrNotSupported
+	}
+
+	if vol.contentType == ContentTypeFS && d.isBlockBacked(vol) {
+		return nil, errors.New("Importing filesystem volumes isn't supported when using zfs.block_mode")
+	}
+
+	// The dataset is renamed, so it must be in the same zpool.
+	poolDataset := d.config["zfs.pool_name"]
+	zpoolName, _, _ := strings.Cut(poolDataset, "/")
+	if !strings.HasPrefix(source, zpoolName+"/") {
+		return nil, fmt.Errorf("Dataset %q isn't in the zpool %q", source, zpoolName)
+	}
+
+	// Refuse the datasets already managed by Incus.
+	relPath, found := strings.CutPrefix(source, poolDataset+"/")
+	if source == poolDataset || found && isManagedPoolPath(relPath) {
+		return nil, fmt.Errorf("Dataset %q is managed by the storage pool", source)
+	}
+
+	exists, err := d.datasetExists(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("Dataset %q doesn't exist", source)
+	}
+
+	children, err := d.getDatasets(source, "filesystem,volume")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(children) > 0 {
+		return nil, fmt.Errorf("Dataset %q has child datasets", source)
+	}
+
+	exists, err = d.datasetExists(d.dataset(vol, false))
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, fmt.Errorf("Dataset %q already exists", d.dataset(vol, false))
+	}
+
+	props, err := d.getDatasetProperties(source, "type", "refquota", "volsize", "mountpoint", "canmount", "volmode")
+	if err != nil {
+		return nil, err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if vol.contentType == ContentTypeFS {
+		if props["type"] != "filesystem" {
+			return nil, fmt.Errorf("Dataset %q isn't a filesystem", source)
+		}
+
+		// Unmount the dataset from its own mountpoint, this fails if it's in use.
+		err = d.setDatasetProperties(source, "mountpoint=legacy", "canmount=noauto")
+		if err != nil {
+			return nil, err
+		}
+
+		reverter.Add(func() {
+			_ = d.setDatasetProperties(source, "mountpoint="+props["mountpoint"], "canmount="+props["canmount"])
+		})
+
+		// Record the existing quota as the volume size, unless a size was requested.
+		if vol.config["size"] == "" && props["refquota"] != "" && props["refquota"] != "0" {
+			vol.config["size"] = props["refquota"] + "B"
+		}
+	} else {
+		if props["type"] != "volume" {
+			return nil, fmt.Errorf("Dataset %q isn't a volume", source)
+		}
+
+		err = d.setDatasetProperties(source, "volmode=none", fmt.Sprintf("incus:content_type=%s", vol.contentType))
+		if err != nil {
+			return nil, err
+		}
+
+		reverter.Add(func() { _ = d.setDatasetProperties(source, "volmode="+props["volmode"]) })
+
+		vol.config["size"] = props["volsize"] + "B"
+	}
+
+	_, err = subprocess.RunCommand("/proc/self/exe", "forkzfs", "--", "rename", source, d.dataset(vol, false))
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(func() {
+		_, _ = subprocess.RunCommand("/proc/self/exe", "forkzfs", "--", "rename", d.dataset(vol, false), source)
+	})
+
+	if vol.contentType == ContentTypeFS {
+		// Create the mountpoint of the volume.
+		err = vol.EnsureMountPath()
+		if err != nil {
+			return nil, err
+		}
+
+		reverter.Add(func() { _ = os.Remove(vol.MountPath()) })
+	}
+
+	cleanup := reverter.Clone().Fail
+	reverter.Success()
+	return cleanup, nil
+}
+
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	return d.copyVolume(vol, srcVol, copySnapshots, allowInconsistent, false, op)
+}
+
+// CreateVolumeFromClone creates the volume as a clone of a new snapshot of the source, regardless of zfs.clone_copy.
+func (d *zfs) CreateVolumeFromClone(vol Volume, srcVol Volume, op *operations.Operation) error {
+	return d.copyVolume(vol, srcVol, false, false, true, op)
+}
+
+// copyVolume copies the source volume, always cloning the source snapshot when clone is set.
+func (d *zfs) copyVolume(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, clone bool, op *operations.Operation) error {
 	var err error
 
 	// Revert handling
@@ -593,7 +719,7 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 		srcFSVol := srcVol.NewVMBlockFilesystemVolume()
 		fsVol := vol.NewVMBlockFilesystemVolume()
 
-		err = d.CreateVolumeFromCopy(fsVol, srcFSVol, copySnapshots, false, op)
+		err = d.copyVolume(fsVol, srcFSVol, copySnapshots, false, clone, op)
 		if err != nil {
 			return err
 		}
@@ -611,6 +737,9 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 		}
 	}
 
+	// Clones can't carry the source snapshots, so those always need a full copy.
+	fullCopy := (!clone && util.IsFalse(d.config["zfs.clone_copy"])) || len(snapshots) > 0
+
 	// When not allowing inconsistent copies and the volume has a mounted filesystem, we must ensure it is
 	// consistent by syncing and freezing the filesystem to ensure unwritten pages are flushed and that no
 	// further modifications occur while taking the source snapshot.
@@ -640,7 +769,7 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 		}
 
 		// If zfs.clone_copy is disabled delete the snapshot at the end.
-		if util.IsFalse(d.config["zfs.clone_copy"]) || len(snapshots) > 0 {
+		if fullCopy {
 			// Delete the snapshot at the end.
 			defer func() {
 				// Delete snapshot (or mark for deferred deletion if cannot be deleted currently).
@@ -670,7 +799,7 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 	reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
 
 	// If zfs.clone_copy is disabled or source volume has snapshots, then use full copy mode.
-	if util.IsFalse(d.config["zfs.clone_copy"]) || len(snapshots) > 0 {
+	if fullCopy {
 		snapName := strings.SplitN(srcSnapshot, "@", 2)[1]
 
 		// Send/receive the snapshot.
@@ -934,8 +1063,20 @@ func (d *zfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, vol
 		}
 	}
 
+	var resume *zfsResumeHeader
+
 	// If we're refreshing, send back all snapshots of the target.
 	if volTargetArgs.Refresh && slices.Contains(volTargetArgs.MigrationType.Features, migration.ZFSFeatureMigrationHeader) {
+		resumable := slices.Contains(volTargetArgs.MigrationType.Features, migration.ZFSFeatureResumeTokens)
+
+		// Discard the state of an interrupted refresh if the source can't resume it.
+		if !resumable {
+			err := d.abortReceive(vol)
+			if err != nil {
+				return err
+			}
+		}
+
 		snapshots, err := vol.Snapshots(op)
 		if err != nil {
 			return fmt.Errorf("Failed getting volume snapshots: %w", err)
@@ -989,6 +1130,12 @@ func (d *zfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, vol
 		// We therefore need to check the snapshots, and delete all target snapshots if the above
 		// scenario is true.
 		if !volumeOnly && len(respSnapshots) > 0 && len(migrationHeader.SnapshotDatasets) > 0 && respSnapshots[0].GUID != migrationHeader.SnapshotDatasets[0].GUID {
+			// An interrupted refresh can't be resumed without the snapshot it was based on.
+			err = d.abortReceive(vol)
+			if err != nil {
+				return err
+			}
+
 			for _, snapVol := range snapshots {
 				// Delete
 				err = d.DeleteVolume(snapVol, op)
@@ -1032,6 +1179,11 @@ func (d *zfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, vol
 		migrationHeader = ZFSMetaDataHeader{}
 		migrationHeader.SnapshotDatasets = respSnapshots
 
+		// Offer the source to resume an interrupted refresh.
+		if resumable && len(respSnapshots) > 0 {
+			migrationHeader.ResumeToken = d.receiveResumeToken(vol)
+		}
+
 		// Send back all target snapshots with their GUIDs.
 		headerJSON, err := json.Marshal(migrationHeader)
 		if err != nil {
@@ -1048,19 +1200,42 @@ func (d *zfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, vol
 			return fmt.Errorf("Failed closing ZFS migration header frame: %w", err)
 		}
 
+		// The source replies whether it resumes the interrupted stream.
+		if migrationHeader.ResumeToken != "" {
+			buf, err := io.ReadAll(conn)
+			if err != nil {
+				return fmt.Errorf("Failed reading ZFS resume header: %w", err)
+			}
+
+			resumeHeader := zfsResumeHeader{}
+			err = json.Unmarshal(buf, &resumeHeader)
+			if err != nil {
+				return fmt.Errorf("Failed decoding ZFS resume header: %w", err)
+			}
+
+			if resumeHeader.Resume {
+				resume = &resumeHeader
+
+				// The resumed stream creates its snapshot.
+				syncSnapshots = slices.DeleteFunc(syncSnapshots, func(snapshot *migration.Snapshot) bool {
+					return snapshot.GetName() == resumeHeader.Snapshot
+				})
+			}
+		}
+
 		// Don't pass the snapshots if it's volume only.
 		if !volumeOnly {
 			volTargetArgs.Snapshots = syncSnapshots
 		}
 	}
 
-	return d.createVolumeFromMigrationOptimized(vol, conn, volTargetArgs, volumeOnly, preFiller, op)
+	return d.createVolumeFromMigrationOptimized(vol, conn, volTargetArgs, volumeOnly, resume, preFiller, op)
 }
 
-func (d *zfs) createVolumeFromMigrationOptimized(vol Volume, conn io.ReadWriteCloser, volTargetArgs localMigration.VolumeTargetArgs, volumeOnly bool, preFiller *VolumeFiller, op *operations.Operation) error {
+func (d *zfs) createVolumeFromMigrationOptimized(vol Volume, conn io.ReadWriteCloser, volTargetArgs localMigration.VolumeTargetArgs, volumeOnly bool, resume *zfsResumeHeader, preFiller *VolumeFiller, op *operations.Operation) error {
 	if vol.IsVMBlock() {
 		fsVol := vol.NewVMBlockFilesystemVolume()
-		err := d.createVolumeFromMigrationOptimized(fsVol, conn, volTargetArgs, volumeOnly, preFiller, op)
+		err := d.createVolumeFromMigrationOptimized(fsVol, conn, volTargetArgs, volumeOnly, nil, preFiller, op)
 		if err != nil {
 			return err
 		}
@@ -1069,8 +1244,39 @@ func (d *zfs) createVolumeFromMigrationOptimized(vol Volume, conn io.ReadWriteCl
 	var snapshots []Volume
 	var err error
 
+	// The state of interrupted receives is only kept when refreshing, as new volumes are deleted on failure.
+	resumable := volTargetArgs.Refresh && slices.Contains(volTargetArgs.MigrationType.Features, migration.ZFSFeatureResumeTokens)
+
+	if resume == nil {
+		// Discard the state of an interrupted receive which isn't resumed.
+		err = d.abortReceive(vol)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Setup progress tracking.
+		var wrapper *ioprogress.ProgressTracker
+		if volTargetArgs.TrackProgress {
+			wrapper = localMigration.ProgressTracker(op, "fs_progress", vol.name)
+		}
+
+		if resume.Snapshot != "" {
+			err = createParentSnapshotDirIfMissing(d.name, vol.volType, vol.name)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Complete the interrupted stream first, on top of which the remaining streams are sent.
+		err = d.receiveDataset(vol, conn, true, wrapper)
+		if err != nil {
+			return fmt.Errorf("Failed resuming receive of volume %q: %w", vol.Name(), err)
+		}
+	}
+
 	// Rollback to the latest identical snapshot if performing a refresh.
-	if volTargetArgs.Refresh {
+	// This is skipped when resuming as the resumed stream is already based on that snapshot.
+	if volTargetArgs.Refresh && resume == nil {
 		snapshots, err = vol.Snapshots(op)
 		if err != nil {
 			return err
@@ -1111,15 +1317,21 @@ func (d *zfs) createVolumeFromMigrationOptimized(vol Volume, conn io.ReadWriteCl
 				wrapper = localMigration.ProgressTracker(op, "fs_progress", snapVol.Name())
 			}
 
-			err = d.receiveDataset(snapVol, conn, wrapper)
+			err = d.receiveDataset(snapVol, conn, resumable, wrapper)
 			if err != nil {
-				_ = d.DeleteVolume(snapVol, op)
+				if !resumable {
+					_ = d.DeleteVolume(snapVol, op)
+				}
+
 				return fmt.Errorf("Failed receiving snapshot volume %q: %w", snapVol.Name(), err)
 			}
 
-			reverter.Add(func() {
-				_ = d.DeleteVolumeSnapshot(snapVol, op)
-			})
+			// Keep the received snapshots when resumable, so that the next refresh continues from them.
+			if !resumable {
+				reverter.Add(func() {
+					_ = d.DeleteVolumeSnapshot(snapVol, op)
+				})
+			}
 		}
 	}
 
@@ -1136,7 +1348,7 @@ func (d *zfs) createVolumeFromMigrationOptimized(vol Volume, conn io.ReadWriteCl
 	}
 
 	// Transfer the main volume.
-	err = d.receiveDataset(vol, conn, wrapper)
+	err = d.receiveDataset(vol, conn, resumable, wrapper)
 	if err != nil {
 		return fmt.Errorf("Failed receiving volume %q: %w", vol.Name(), err)
 	}
@@ -1170,10 +1382,17 @@ func (d *zfs) createVolumeFromMigrationOptimized(vol Volume, conn io.ReadWriteCl
 	}
 
 	if volTargetArgs.Refresh {
-		// Only delete the latest migration snapshot.
-		_, err := subprocess.RunCommand("zfs", "destroy", "-r", fmt.Sprintf("%s%s", d.dataset(vol, false), entries[len(entries)-1]))
-		if err != nil {
-			return err
+		// Only delete the latest migration snapshot, and the one of the resumed stream if any.
+		migrationEntries := entries[len(entries)-1:]
+		if resume != nil && resume.Snapshot == "" && len(entries) > 1 {
+			migrationEntries = entries[len(entries)-2:]
+		}
+
+		for _, entry := range migrationEntries {
+			_, err := subprocess.RunCommand("zfs", "destroy", "-r", fmt.Sprintf("%s%s", d.dataset(vol, false), entry))
+			if err != nil {
+				return err
+			}
 		}
 	} else {
 		// Remove any snapshots that were transferred but are not needed.
@@ -1577,6 +1796,7 @@ func (d *zfs) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
 		"block.filesystem":     validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
 		"block.mount_options":  validate.IsAny,
+		"maintenance.fstrim":   validate.Optional(validate.IsBool),
 		"zfs.block_mode":       validate.Optional(validate.IsBool),
 		"zfs.blocksize":        validate.Optional(ValidateZfsBlocksize),
 		"zfs.remove_snapshots": validate.Optional(validate.IsBool),
@@ -1598,6 +1818,7 @@ func (d *zfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "zfs.block_mode")
 		delete(commonRules, "block.filesystem")
 		delete(commonRules, "block.mount_options")
+		delete(commonRules, "maintenance.fstrim")
 	} else if vol.volType == VolumeTypeCustom && !vol.IsBlockBacked() {
 		delete(commonRules, "block.filesystem")
 		delete(commonRules, "block.mount_options")
@@ -1751,6 +1972,31 @@ func (d *zfs) GetVolumeUsage(vol Volume) (int64, error) {
 	return valueInt, nil
 }
 
+// GetVolumeSnapshotUsage returns the space used by the data of a snapshot and the space unique to it.
+// The first value is the data written since the previous snapshot, the second the space freed by deleting it.
+func (d *zfs) GetVolumeSnapshotUsage(snapVol Volume) (int64, int64, error) {
+	if !snapVol.IsSnapshot() {
+		return -1, -1, ErrNotSupported
+	}
+
+	props, err := d.getDatasetProperties(d.dataset(snapVol, false), "written", "used")
+	if err != nil {
+		return -1, -1, err
+	}
+
+	written, err := strconv.ParseInt(props["written"], 10, 64)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	used, err := strconv.ParseInt(props["used"], 10, 64)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	return written, used, nil
+}
+
 // SetVolumeQuota sets the quota/reservation on the volume.
 // Does nothing if supplied with an empty/zero size for block volumes.
 func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
@@ -2005,6 +2251,110 @@ func (d *zfs) GetVolumeDiskPath(vol Volume) (string, error) {
 	return d.tryGetVolumeDiskPathFromDataset(ctx, d.dataset(vol, false))
 }
 
+// TrimVolumes discards the unused blocks of the mounted block-backed volumes and starts a trim of the zpool devices.
+// Datasets release their blocks to the zpool by themselves.
+func (d *zfs) TrimVolumes(vols []Volume, op *operations.Operation) error {
+	blockVols := make([]Volume, 0, len(vols))
+	for _, vol := range vols {
+		if vol.IsBlockBacked() {
+			blockVols = append(blockVols, vol)
+		}
+	}
+
+	err := fstrimVolumes(blockVols)
+	if err != nil {
+		return err
+	}
+
+	if !zfsTrim {
+		return nil
+	}
+
+	poolName, _, _ := strings.Cut(d.config["zfs.pool_name"], "/")
+	_, err = subprocess.RunCommand("zpool", "trim", poolName)
+	if err != nil {
+		return fmt.Errorf("Failed trimming zpool %q: %w", poolName, err)
+	}
+
+	return nil
+}
+
+// VolumeSnapshotDiff compares two states of a filesystem volume using "zfs diff".
+// Other volumes use the generic implementation.
+func (d *zfs) VolumeSnapshotDiff(vol Volume, snapA string, snapB string, fn func(change api.StorageVolumeDiffEntry) error, op *operations.Operation) error {
+	if vol.contentType != ContentTypeFS || d.isBlockBacked(vol) {
+		return genericVFSVolumeSnapshotDiff(vol, snapA, snapB, fn, op)
+	}
+
+	volA, err := vol.NewSnapshot(snapA)
+	if err != nil {
+		return err
+	}
+
+	target := d.dataset(vol, false)
+	if snapB != "" {
+		volB, err := vol.NewSnapshot(snapB)
+		if err != nil {
+			return err
+		}
+
+		target = d.dataset(volB, false)
+	}
+
+	// The changed paths are reported relative to where the volume is mounted.
+	return vol.MountTask(func(mountPath string, op *operations.Operation) error {
+		out, err := subprocess.RunCommand("zfs", "diff", "-H", d.dataset(volA, false), target)
+		if err != nil {
+			return fmt.Errorf("Failed comparing ZFS dataset %q to %q: %w", d.dataset(volA, false), target, err)
+		}
+
+		relPath := func(path string) string {
+			path = zfsDiffUnescape(path)
+			return "/" + strings.TrimLeft(strings.TrimPrefix(path, mountPath), "/")
+		}
+
+		for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+			fields := strings.Split(line, "\t")
+			if len(fields) < 2 {
+				continue
+			}
+
+			changes := []api.StorageVolumeDiffEntry{}
+			switch fields[0] {
+			case "+":
+				changes = append(changes, api.StorageVolumeDiffEntry{Type: VolumeDiffAdded, Path: relPath(fields[1])})
+			case "-":
+				changes = append(changes, api.StorageVolumeDiffEntry{Type: VolumeDiffDeleted, Path: relPath(fields[1])})
+			case "M":
+				changes = append(changes, api.StorageVolumeDiffEntry{Type: VolumeDiffModified, Path: relPath(fields[1])})
+			case "R":
+				if len(fields) < 3 {
+					continue
+				}
+
+				changes = append(changes,
+					api.StorageVolumeDiffEntry{Type: VolumeDiffDeleted, Path: relPath(fields[1])},
+					api.StorageVolumeDiffEntry{Type: VolumeDiffAdded, Path: relPath(fields[2])},
+				)
+			}
+
+			for _, change := range changes {
+				// The root directory of the volume is reported as modified when its content changes.
+				if change.Path == "/" {
+					continue
+				}
+
+				err = fn(change)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}, op)
+}
+
 // ListVolumes returns a list of volumes in storage pool.
 func (d *zfs) ListVolumes() ([]Volume, error) {
 	vols := make(map[string]Volume)
@@ -2585,6 +2935,7 @@ func (d *zfs) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *loc
 
 	incrementalStream := true
 	var migrationHeader ZFSMetaDataHeader
+	var resume *zfsResumeHeader
 
 	if volSrcArgs.Refresh && slices.Contains(volSrcArgs.MigrationType.Features, migration.ZFSFeatureMigrationHeader) {
 		buf, err := io.ReadAll(conn)
@@ -2622,15 +2973,39 @@ func (d *zfs) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *loc
 				}
 			}
 		}
+
+		// Let the target know whether its interrupted refresh is resumed.
+		if migrationHeader.ResumeToken != "" {
+			resume = d.resumeTransfer(vol, migrationHeader.ResumeToken, volSrcArgs.Snapshots)
+
+			resumeJSON, err := json.Marshal(resume)
+			if err != nil {
+				return fmt.Errorf("Failed encoding ZFS resume header: %w", err)
+			}
+
+			_, err = conn.Write(resumeJSON)
+			if err != nil {
+				return fmt.Errorf("Failed sending ZFS resume header: %w", err)
+			}
+
+			err = conn.Close() // End the frame.
+			if err != nil {
+				return fmt.Errorf("Failed closing ZFS resume header frame: %w", err)
+			}
+
+			if resume.Resume && resume.Snapshot != "" {
+				volSrcArgs.Snapshots = volSrcArgs.Snapshots[1:]
+			}
+		}
 	}
 
-	return d.migrateVolumeOptimized(vol, conn, volSrcArgs, incrementalStream, op)
+	return d.migrateVolumeOptimized(vol, conn, volSrcArgs, incrementalStream, resume, op)
 }
 
-func (d *zfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volSrcArgs *localMigration.VolumeSourceArgs, incremental bool, op *operations.Operation) error {
+func (d *zfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volSrcArgs *localMigration.VolumeSourceArgs, incremental bool, resume *zfsResumeHeader, op *operations.Operation) error {
 	if vol.IsVMBlock() {
 		fsVol := vol.NewVMBlockFilesystemVolume()
-		err := d.migrateVolumeOptimized(fsVol, conn, volSrcArgs, incremental, op)
+		err := d.migrateVolumeOptimized(fsVol, conn, volSrcArgs, incremental, nil, op)
 		if err != nil {
 			return err
 		}
@@ -2639,6 +3014,19 @@ func (d *zfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volSrc
 	// Handle zfs send/receive migration.
 	var finalParent string
 
+	// Resume the interrupted stream first, on top of which the remaining streams are sent.
+	if resume != nil && resume.Resume {
+		var wrapper *ioprogress.ProgressTracker
+		if volSrcArgs.TrackProgress {
+			wrapper = localMigration.ProgressTracker(op, "fs_progress", vol.name)
+		}
+
+		err := d.sendResumedDataset(resume.token, conn, wrapper)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Transfer the snapshots first.
 	for i, snapName := range volSrcArgs.Snapshots {
 		snapshot, _ := vol.NewSnapshot(snapName)
@@ -2687,6 +3075,10 @@ func (d *zfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volSrc
 		wrapper = localMigration.ProgressTracker(op, "fs_progress", vol.name)
 	}
 
+	// The target keeps the state of an interrupted refresh, keep the temporary snapshot so that it can be resumed.
+	resumable := volSrcArgs.Refresh && slices.Contains(volSrcArgs.MigrationType.Features, migration.ZFSFeatureResumeTokens)
+
+	var err error
 	srcSnapshot := d.dataset(vol, false)
 	if !vol.IsSnapshot() {
 		// Create a temporary read-only snapshot.
@@ -2697,6 +3089,15 @@ func (d *zfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volSrc
 		}
 
 		defer func() {
+			if resumable && err != nil {
+				// Flag the snapshot so that it's deleted once the volume is successfully refreshed.
+				err := d.setDatasetProperties(srcSnapshot, zfsResumeProperty+"=true")
+				if err == nil {
+					d.logger.Info("Keeping temporary snapshot to resume the interrupted migration", logger.Ctx{"snapshot": srcSnapshot})
+					return
+				}
+			}
+
 			// Delete snapshot (or mark for deferred deletion if cannot be deleted currently).
 			_, err := subprocess.RunCommand("zfs", "destroy", "-r", "-d", srcSnapshot)
 			if err != nil {
@@ -2717,12 +3118,22 @@ func (d *zfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volSrc
 		}
 	}
 
+	// The resumed stream of the volume itself is the parent of the current one.
+	if resume != nil && resume.Resume && resume.Snapshot == "" {
+		finalParent = resume.dataset
+	}
+
 	// Send the volume itself.
-	err := d.sendDataset(srcSnapshot, finalParent, volSrcArgs, conn, wrapper)
+	err = d.sendDataset(srcSnapshot, finalParent, volSrcArgs, conn, wrapper)
 	if err != nil {
 		return err
 	}
 
+	if resumable {
+		// Delete the temporary snapshots kept by interrupted migrations, which aren't needed anymore.
+		d.deleteResumeSnapshots(vol)
+	}
+
 	return nil
 }
 
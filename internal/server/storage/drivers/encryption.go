@@ -0,0 +1,654 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/migration"
+	localMigration "github.com/lxc/incus/v6/internal/server/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/refcount"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// encryptionDrivers lists the drivers whose block volumes can be encrypted.
+var encryptionDrivers = []string{"btrfs", "dir", "lvm", "zfs"}
+
+// encryptionKeyHookNotSupported is the exit status of the key hook for unsupported actions.
+const encryptionKeyHookNotSupported = 2
+
+// encryptionKeySize is the size in bytes of the keys generated into missing key files.
+const encryptionKeySize = 64
+
+// encryption wraps a driver to store the block volumes that set "block.encryption" in LUKS2 containers.
+// The wrapped driver manages the block devices holding the encrypted data, while the disk path of the
+// volumes is the one of their opened LUKS mapping so that everything else only ever sees the plaintext.
+type encryption struct {
+	Driver
+
+	state *state.State
+}
+
+// encryptionVolumeRules returns the rules of the encryption options of volumes.
+func encryptionVolumeRules() map[string]func(string) error {
+	return map[string]func(string) error{
+		"block.encryption":     validate.Optional(validate.IsOneOf("luks2")),
+		"block.encryption.key": validate.IsAny,
+	}
+}
+
+// encryptionValidators returns the validators with the encryption options added to the common rules.
+func encryptionValidators(rules *Validators) *Validators {
+	if rules == nil {
+		return nil
+	}
+
+	return &Validators{
+		PoolRules: func() map[string]func(string) error {
+			poolRules := rules.PoolRules()
+			for k, validator := range encryptionVolumeRules() {
+				poolRules["volume."+k] = validator
+			}
+
+			return poolRules
+		},
+		VolumeRules: func(vol Volume) map[string]func(string) error {
+			volRules := rules.VolumeRules(vol)
+			if volumeEncryptable(vol) {
+				maps.Copy(volRules, encryptionVolumeRules())
+			}
+
+			return volRules
+		},
+	}
+}
+
+// volumeEncryptable returns whether the volume can be encrypted, only block volumes of virtual machines
+// and custom block volumes can.
+func volumeEncryptable(vol Volume) bool {
+	return vol.contentType == ContentTypeBlock && (vol.volType == VolumeTypeVM || vol.volType == VolumeTypeCustom)
+}
+
+// volumeEncrypted returns whether the volume is encrypted.
+func volumeEncrypted(vol Volume) bool {
+	return volumeEncryptable(vol) && vol.config["block.encryption"] != ""
+}
+
+// mapperName returns the name of the device mapper target of the opened LUKS container of the volume.
+func (d *encryption) mapperName(vol Volume) string {
+	return fmt.Sprintf("incus-luks-%x", sha256.Sum256([]byte(filepath.Join(d.Name(), string(vol.volType), vol.name))))
+}
+
+// mapperPath returns the path of the opened LUKS container of the volume.
+func (d *encryption) mapperPath(vol Volume) string {
+	return filepath.Join("/dev/mapper", d.mapperName(vol))
+}
+
+// keyHook returns the path of the executable managing the keys, if any.
+func (d *encryption) keyHook() string {
+	if d.state == nil || d.state.LocalConfig == nil {
+		return ""
+	}
+
+	return d.state.LocalConfig.StorageEncryptionKeyHook()
+}
+
+// runKeyHook runs the key hook for the action on the key reference of the volume and returns its output.
+// ErrNotSupported is returned when the hook doesn't support the action.
+func (d *encryption) runKeyHook(action string, vol Volume) (string, error) {
+	env := append(os.Environ(),
+		"INCUS_POOL="+d.Name(),
+		"INCUS_VOLUME_TYPE="+string(vol.volType),
+		"INCUS_VOLUME_NAME="+vol.name,
+	)
+
+	out, _, err := subprocess.RunCommandSplit(context.TODO(), env, nil, d.keyHook(), action, vol.config["block.encryption.key"])
+	if err != nil {
+		status, _ := linux.ExitStatus(err)
+		if status == encryptionKeyHookNotSupported {
+			return "", ErrNotSupported
+		}
+
+		return "", fmt.Errorf("Failed running encryption key hook %q for volume %q: %w", action, vol.name, err)
+	}
+
+	return out, nil
+}
+
+// volumeKey returns the key of the volume. When create is true, the key is created if missing.
+func (d *encryption) volumeKey(vol Volume, create bool) ([]byte, error) {
+	keyRef := vol.config["block.encryption.key"]
+
+	var key []byte
+	if d.keyHook() != "" {
+		if create {
+			_, err := d.runKeyHook("create", vol)
+			if err != nil && !errors.Is(err, ErrNotSupported) {
+				return nil, err
+			}
+		}
+
+		out, err := d.runKeyHook("get", vol)
+		if err != nil {
+			return nil, err
+		}
+
+		key = []byte(strings.TrimSpace(out))
+	} else {
+		// Without a key hook, the key reference is the path of a key file.
+		if create && !util.PathExists(keyRef) {
+			newKey := make([]byte, encryptionKeySize)
+			_, err := rand.Read(newKey)
+			if err != nil {
+				return nil, fmt.Errorf("Failed generating encryption key: %w", err)
+			}
+
+			err = os.WriteFile(keyRef, newKey, 0o600)
+			if err != nil {
+				return nil, fmt.Errorf("Failed writing encryption key file %q: %w", keyRef, err)
+			}
+
+			d.Logger().Info("Generated encryption key file", logger.Ctx{"volName": vol.name, "path": keyRef})
+		}
+
+		var err error
+		key, err = os.ReadFile(keyRef)
+		if err != nil {
+			return nil, fmt.Errorf("Failed reading encryption key file of volume %q: %w", vol.name, err)
+		}
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("Empty encryption key for volume %q", vol.name)
+	}
+
+	return key, nil
+}
+
+// cryptsetup runs cryptsetup with the key on its standard input.
+func cryptsetup(key []byte, args ...string) error {
+	_, err := exec.LookPath("cryptsetup")
+	if err != nil {
+		return errors.New("Required tool 'cryptsetup' is missing")
+	}
+
+	var stdin io.Reader
+	if key != nil {
+		stdin = bytes.NewReader(key)
+	}
+
+	return subprocess.RunCommandWithFds(context.TODO(), stdin, nil, "cryptsetup", args...)
+}
+
+// luksFormat creates a LUKS2 container on the block device of the volume.
+func (d *encryption) luksFormat(vol Volume, devPath string) error {
+	key, err := d.volumeKey(vol, true)
+	if err != nil {
+		return err
+	}
+
+	err = cryptsetup(key, "luksFormat", "--batch-mode", "--type", "luks2", "--key-file", "-", devPath)
+	if err != nil {
+		return fmt.Errorf("Failed formatting encrypted volume %q: %w", vol.name, err)
+	}
+
+	d.Logger().Debug("Formatted LUKS container", logger.Ctx{"volName": vol.name, "dev": devPath})
+
+	return nil
+}
+
+// luksOpen opens the LUKS container of the volume unless already opened.
+func (d *encryption) luksOpen(vol Volume, devPath string, readonly bool) error {
+	if util.PathExists(d.mapperPath(vol)) {
+		return nil
+	}
+
+	key, err := d.volumeKey(vol, false)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"open", "--type", "luks2", "--key-file", "-", "--allow-discards"}
+	if readonly {
+		args = append(args, "--readonly")
+	}
+
+	err = cryptsetup(key, append(args, devPath, d.mapperName(vol))...)
+	if err != nil {
+		return fmt.Errorf("Failed opening encrypted volume %q: %w", vol.name, err)
+	}
+
+	d.Logger().Debug("Opened LUKS container", logger.Ctx{"volName": vol.name, "dev": devPath})
+
+	return nil
+}
+
+// luksClose closes the LUKS container of the volume if opened.
+func (d *encryption) luksClose(vol Volume) error {
+	if !util.PathExists(d.mapperPath(vol)) {
+		return nil
+	}
+
+	err := cryptsetup(nil, "close", d.mapperName(vol))
+	if err != nil {
+		return fmt.Errorf("Failed closing encrypted volume %q: %w", vol.name, err)
+	}
+
+	d.Logger().Debug("Closed LUKS container", logger.Ctx{"volName": vol.name})
+
+	return nil
+}
+
+// isLuks checks whether the block device of the volume holds a LUKS container.
+// The block device is activated for the check.
+func (d *encryption) isLuks(vol Volume, op *operations.Operation) (bool, error) {
+	err := d.Driver.MountVolume(vol, op)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _, _ = d.Driver.UnmountVolume(vol, false, op) }()
+
+	devPath, err := d.Driver.GetVolumeDiskPath(vol)
+	if err != nil {
+		return false, err
+	}
+
+	err = cryptsetup(nil, "isLuks", devPath)
+	if err != nil {
+		status, _ := linux.ExitStatus(err)
+		if status == 1 {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// lastMountUser returns whether the volume isn't used by anything else than the caller unmounting it.
+func lastMountUser(vol Volume) bool {
+	return refcount.Get(vol.mountLockName()) <= 1
+}
+
+// ValidateVolume validates the supplied volume config.
+func (d *encryption) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
+	err := d.Driver.ValidateVolume(vol, removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	if !volumeEncrypted(vol) {
+		return nil
+	}
+
+	keyRef := vol.config["block.encryption.key"]
+	if keyRef == "" {
+		return fmt.Errorf("Volume %q option %q is required with %q", vol.name, "block.encryption.key", "block.encryption")
+	}
+
+	if d.keyHook() == "" {
+		err := validate.IsAbsFilePath(keyRef)
+		if err != nil {
+			return fmt.Errorf("Invalid value for volume %q option %q, must be the path of the key file without %q: %w", vol.name, "block.encryption.key", "storage.encryption.key_hook", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateVolume applies config changes to the volume.
+func (d *encryption) UpdateVolume(vol Volume, changedConfig map[string]string) error {
+	for _, key := range []string{"block.encryption", "block.encryption.key"} {
+		_, changed := changedConfig[key]
+		if changed && volumeEncryptable(vol) {
+			return fmt.Errorf("Volume option %q cannot be changed", key)
+		}
+	}
+
+	return d.Driver.UpdateVolume(vol, changedConfig)
+}
+
+// CreateVolume creates an empty volume and can optionally fill it by executing the supplied filler function.
+// Encrypted volumes are created empty by the wrapped driver, then formatted and filled through their
+// opened LUKS container.
+func (d *encryption) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error {
+	if !volumeEncrypted(vol) {
+		return d.Driver.CreateVolume(vol, filler, op)
+	}
+
+	err := d.Driver.CreateVolume(vol, nil, op)
+	if err != nil {
+		return err
+	}
+
+	err = d.initVolume(vol, filler, op)
+	if err != nil {
+		_ = d.DeleteVolume(vol, op)
+		return err
+	}
+
+	return nil
+}
+
+// initVolume formats the block device of a new encrypted volume and fills it through its LUKS container.
+func (d *encryption) initVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error {
+	err := d.Driver.MountVolume(vol, op)
+	if err != nil {
+		return err
+	}
+
+	devPath, err := d.Driver.GetVolumeDiskPath(vol)
+	if err == nil {
+		err = d.luksFormat(vol, devPath)
+	}
+
+	_, _ = d.Driver.UnmountVolume(vol, false, op)
+	if err != nil {
+		return err
+	}
+
+	if filler == nil || filler.Fill == nil {
+		return nil
+	}
+
+	return vol.MountTask(func(mountPath string, op *operations.Operation) error {
+		d.Logger().Debug("Running filler function", logger.Ctx{"dev": d.mapperPath(vol), "path": mountPath})
+		volSize, err := filler.Fill(vol, d.mapperPath(vol), false)
+		if err != nil {
+			return err
+		}
+
+		filler.Size = volSize
+
+		// Move the GPT alt header to the end of the LUKS container.
+		if vol.IsVMBlock() {
+			gpt := common{logger: d.Logger()}
+			err = gpt.moveGPTAltHeader(d.mapperPath(vol))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, op)
+}
+
+// CreateVolumeFromCopy provides same-pool volume copying functionality.
+// Copies between encrypted and unencrypted volumes go through the generic copy so that the data gets
+// encrypted or decrypted on the way.
+func (d *encryption) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	if !d.needsGenericCopy(vol, srcVol) {
+		return d.Driver.CreateVolumeFromCopy(vol, srcVol, copySnapshots, allowInconsistent, op)
+	}
+
+	var err error
+	var srcSnapshots []Volume
+
+	if copySnapshots && !srcVol.IsSnapshot() {
+		srcSnapshots, err = srcVol.Snapshots(op)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Grow the volume to the size of its source, images can be larger than the default volume size.
+	vol = vol.Clone()
+	volSize, err := vol.ConfigSizeFromSource(srcVol)
+	if err != nil {
+		return err
+	}
+
+	vol.SetConfigSize(volSize)
+
+	err = genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+	if err != nil {
+		return err
+	}
+
+	// Move the GPT alt header to the end of the LUKS container when unpacking an image.
+	if volumeEncrypted(vol) && vol.IsVMBlock() && srcVol.volType == VolumeTypeImage {
+		err = vol.MountTask(func(mountPath string, op *operations.Operation) error {
+			gpt := common{logger: d.Logger()}
+			return gpt.moveGPTAltHeader(d.mapperPath(vol))
+		}, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateVolumeFromClone creates the volume as a copy-on-write clone of the source.
+// Clones share the data of their source, so they can't change its encryption.
+func (d *encryption) CreateVolumeFromClone(vol Volume, srcVol Volume, op *operations.Operation) error {
+	if d.needsGenericCopy(vol, srcVol) {
+		return ErrNotSupported
+	}
+
+	return d.Driver.CreateVolumeFromClone(vol, srcVol, op)
+}
+
+// needsGenericCopy returns whether the copy of the source volume can't be done by the wrapped driver,
+// because exactly one of them is encrypted or because they use different keys.
+func (d *encryption) needsGenericCopy(vol Volume, srcVol Volume) bool {
+	if volumeEncrypted(vol) != volumeEncrypted(srcVol) {
+		return true
+	}
+
+	return volumeEncrypted(vol) && vol.config["block.encryption.key"] != srcVol.config["block.encryption.key"]
+}
+
+// RefreshVolume updates an existing volume to match the state of another.
+func (d *encryption) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
+	if !d.needsGenericCopy(vol, srcVol) {
+		return d.Driver.RefreshVolume(vol, srcVol, srcSnapshots, allowInconsistent, op)
+	}
+
+	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, true, allowInconsistent, op)
+}
+
+// DeleteVolume deletes a volume of the storage device.
+func (d *encryption) DeleteVolume(vol Volume, op *operations.Operation) error {
+	if volumeEncrypted(vol) {
+		err := d.luksClose(vol)
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.Driver.DeleteVolume(vol, op)
+}
+
+// RenameVolume renames a volume and its snapshots.
+func (d *encryption) RenameVolume(vol Volume, newVolName string, op *operations.Operation) error {
+	if volumeEncrypted(vol) && util.PathExists(d.mapperPath(vol)) {
+		return fmt.Errorf("Encrypted volume %q can't be renamed while in use", vol.name)
+	}
+
+	return d.Driver.RenameVolume(vol, newVolName, op)
+}
+
+// SetVolumeQuota applies a size limit on volume.
+// The LUKS container of encrypted volumes in use is resized along with their block device.
+func (d *encryption) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
+	err := d.Driver.SetVolumeQuota(vol, size, allowUnsafeResize, op)
+	if err != nil {
+		return err
+	}
+
+	if !volumeEncrypted(vol) || !util.PathExists(d.mapperPath(vol)) {
+		return nil
+	}
+
+	key, err := d.volumeKey(vol, false)
+	if err != nil {
+		return err
+	}
+
+	err = cryptsetup(key, "resize", "--key-file", "-", d.mapperName(vol))
+	if err != nil {
+		return fmt.Errorf("Failed resizing encrypted volume %q: %w", vol.name, err)
+	}
+
+	return nil
+}
+
+// GetVolumeDiskPath returns the location of a root disk block device.
+// For encrypted volumes, this is the opened LUKS container.
+func (d *encryption) GetVolumeDiskPath(vol Volume) (string, error) {
+	if volumeEncrypted(vol) {
+		return d.mapperPath(vol), nil
+	}
+
+	return d.Driver.GetVolumeDiskPath(vol)
+}
+
+// MountVolume activates a volume and opens its LUKS container if encrypted.
+func (d *encryption) MountVolume(vol Volume, op *operations.Operation) error {
+	return d.mount(vol, false, d.Driver.MountVolume, func(vol Volume, op *operations.Operation) {
+		_, _ = d.Driver.UnmountVolume(vol, false, op)
+	}, op)
+}
+
+// MountVolumeSnapshot activates a volume snapshot and opens its LUKS container read-only if encrypted.
+func (d *encryption) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	return d.mount(snapVol, true, d.Driver.MountVolumeSnapshot, func(vol Volume, op *operations.Operation) {
+		_, _ = d.Driver.UnmountVolumeSnapshot(vol, op)
+	}, op)
+}
+
+// mount activates the volume with the mount function of the wrapped driver, then opens its LUKS container.
+func (d *encryption) mount(vol Volume, readonly bool, mount func(vol Volume, op *operations.Operation) error, unmount func(vol Volume, op *operations.Operation), op *operations.Operation) error {
+	err := mount(vol, op)
+	if err != nil || !volumeEncrypted(vol) {
+		return err
+	}
+
+	devPath, err := d.Driver.GetVolumeDiskPath(vol)
+	if err == nil {
+		err = d.luksOpen(vol, devPath, readonly)
+	}
+
+	if err != nil {
+		unmount(vol, op)
+		return err
+	}
+
+	return nil
+}
+
+// UnmountVolume closes the LUKS container of an encrypted volume when its last user is gone, then
+// deactivates the volume.
+func (d *encryption) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operation) (bool, error) {
+	if volumeEncrypted(vol) && !keepBlockDev && lastMountUser(vol) {
+		err := d.luksClose(vol)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return d.Driver.UnmountVolume(vol, keepBlockDev, op)
+}
+
+// UnmountVolumeSnapshot closes the LUKS container of an encrypted volume snapshot when its last user is
+// gone, then deactivates the volume snapshot.
+func (d *encryption) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (bool, error) {
+	if volumeEncrypted(snapVol) && lastMountUser(snapVol) {
+		err := d.luksClose(snapVol)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return d.Driver.UnmountVolumeSnapshot(snapVol, op)
+}
+
+// DeleteVolumeSnapshot removes a snapshot from the storage device.
+func (d *encryption) DeleteVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	if volumeEncrypted(snapVol) {
+		err := d.luksClose(snapVol)
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.Driver.DeleteVolumeSnapshot(snapVol, op)
+}
+
+// RenameVolumeSnapshot renames a volume snapshot.
+func (d *encryption) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
+	if volumeEncrypted(snapVol) && util.PathExists(d.mapperPath(snapVol)) {
+		return fmt.Errorf("Encrypted volume snapshot %q can't be renamed while in use", snapVol.name)
+	}
+
+	return d.Driver.RenameVolumeSnapshot(snapVol, newSnapshotName, op)
+}
+
+// MigrateVolume sends a volume for migration.
+// Encrypted volumes are sent decrypted with the generic transfer, optimized transfers send the LUKS container.
+func (d *encryption) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *localMigration.VolumeSourceArgs, op *operations.Operation) error {
+	if volumeEncrypted(vol) && isGenericMigration(volSrcArgs.MigrationType) {
+		return genericVFSMigrateVolume(d, d.state, vol, conn, volSrcArgs, op)
+	}
+
+	return d.Driver.MigrateVolume(vol, conn, volSrcArgs, op)
+}
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+// Encrypted volumes receiving a generic transfer are encrypted on the way, while optimized transfers must
+// carry a LUKS container.
+func (d *encryption) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs localMigration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
+	if !volumeEncrypted(vol) {
+		return d.Driver.CreateVolumeFromMigration(vol, conn, volTargetArgs, preFiller, op)
+	}
+
+	if isGenericMigration(volTargetArgs.MigrationType) {
+		return genericVFSCreateVolumeFromMigration(d, nil, vol, conn, volTargetArgs, preFiller, op)
+	}
+
+	err := d.Driver.CreateVolumeFromMigration(vol, conn, volTargetArgs, preFiller, op)
+	if err != nil {
+		return err
+	}
+
+	// Existing volumes were checked when first received.
+	if volTargetArgs.Refresh {
+		return nil
+	}
+
+	encrypted, err := d.isLuks(vol, op)
+	if err == nil && !encrypted {
+		err = fmt.Errorf("Received data of volume %q isn't encrypted, the source volume must be encrypted with the same key", vol.name)
+	}
+
+	if err != nil {
+		_ = d.DeleteVolume(vol, op)
+		return err
+	}
+
+	return nil
+}
+
+// isGenericMigration returns whether the migration type transfers the volume content with the generic
+// rsync and block transfer.
+func isGenericMigration(migrationType localMigration.Type) bool {
+	return migrationType.FSType == migration.MigrationFSType_RSYNC || migrationType.FSType == migration.MigrationFSType_BLOCK_AND_RSYNC
+}
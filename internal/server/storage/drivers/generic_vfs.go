@@ -1,16 +1,23 @@
 package drivers
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"golang.org/x/sys/unix"
+
 	"github.com/lxc/incus/v6/internal/instancewriter"
 	"github.com/lxc/incus/v6/internal/linux"
 	"github.com/lxc/incus/v6/internal/migration"
@@ -35,6 +42,9 @@ const genericVolumeBlockExtension = "img"
 // genericVolumeDiskFile used to indicate the file name used for block volume disk files.
 const genericVolumeDiskFile = "root.img"
 
+// genericVFSSparseBlockSize is the size of the zero filled blocks skipped when sending sparse block volumes.
+const genericVFSSparseBlockSize = 4096
+
 // genericISOVolumeSuffix suffix used for generic iso content type volumes.
 const genericISOVolumeSuffix = ".iso"
 
@@ -168,17 +178,22 @@ func genericVFSMigrateVolume(d Driver, s *state.State, vol Volume, conn io.ReadW
 		return ErrNotSupported
 	}
 
+	// Spread the transfer over any additional connection agreed on with the target.
+	conns := append([]io.ReadWriteCloser{conn}, volSrcArgs.Streams...)
+
 	// Define function to send a filesystem volume.
-	sendFSVol := func(vol Volume, conn io.ReadWriteCloser, mountPath string) error {
+	sendFSVol := func(vol Volume, conns []io.ReadWriteCloser, mountPath string) error {
 		var wrapper *ioprogress.ProgressTracker
+		var files func(int64)
 		if volSrcArgs.TrackProgress {
 			wrapper = localMigration.ProgressTracker(op, "fs_progress", vol.name)
+			files = localMigration.FileTracker(op, vol.name)
 		}
 
 		path := internalUtil.AddSlash(mountPath)
 
-		d.Logger().Debug("Sending filesystem volume", logger.Ctx{"volName": vol.name, "path": path, "bwlimit": bwlimit, "rsyncArgs": rsyncArgs})
-		err := rsync.Send(vol.name, path, conn, wrapper, volSrcArgs.MigrationType.Features, bwlimit, s.OS.ExecPath, rsyncArgs...)
+		d.Logger().Debug("Sending filesystem volume", logger.Ctx{"volName": vol.name, "path": path, "bwlimit": bwlimit, "rsyncArgs": rsyncArgs, "streams": len(conns)})
+		err := rsync.SendParallel(vol.name, path, conns, wrapper, files, volSrcArgs.MigrationType.Features, bwlimit, s.OS.ExecPath, rsyncArgs...)
 
 		status, _ := linux.ExitStatus(err)
 		if volSrcArgs.AllowInconsistent && status == 24 {
@@ -188,10 +203,17 @@ func genericVFSMigrateVolume(d Driver, s *state.State, vol Volume, conn io.ReadW
 		return err
 	}
 
+	// Holes and zero filled blocks of block volumes are skipped if the target supports it.
+	sparseBlocks := slices.Contains(volSrcArgs.MigrationType.Features, migration.RsyncFeatureSparseBlocks)
+
 	// Define function to send a block volume.
-	sendBlockVol := func(vol Volume, conn io.ReadWriteCloser) error {
+	sendBlockVol := func(vol Volume, conns []io.ReadWriteCloser) error {
 		// Close when done to indicate to target side we are finished sending this volume.
-		defer func() { _ = conn.Close() }()
+		defer func() {
+			for _, conn := range conns {
+				_ = conn.Close()
+			}
+		}()
 
 		var wrapper *ioprogress.ProgressTracker
 		if volSrcArgs.TrackProgress {
@@ -203,6 +225,13 @@ func genericVFSMigrateVolume(d Driver, s *state.State, vol Volume, conn io.ReadW
 			return fmt.Errorf("Error getting VM block volume disk path: %w", err)
 		}
 
+		if len(conns) > 1 {
+			d.Logger().Debug("Sending block volume", logger.Ctx{"volName": vol.name, "path": path, "streams": len(conns), "sparse": sparseBlocks})
+			return genericVFSSendBlockRanges(path, conns, wrapper, sparseBlocks)
+		}
+
+		conn := conns[0]
+
 		from, err := os.Open(path)
 		if err != nil {
 			return fmt.Errorf("Error opening file for reading %q: %w", path, err)
@@ -219,8 +248,27 @@ func genericVFSMigrateVolume(d Driver, s *state.State, vol Volume, conn io.ReadW
 			}
 		}
 
-		d.Logger().Debug("Sending block volume", logger.Ctx{"volName": vol.name, "path": path})
-		_, err = io.Copy(conn, fromPipe)
+		d.Logger().Debug("Sending block volume", logger.Ctx{"volName": vol.name, "path": path, "sparse": sparseBlocks})
+		if sparseBlocks {
+			toPipe := io.Writer(conn)
+			if wrapper != nil {
+				toPipe = &ioprogress.ProgressWriter{
+					WriteCloser: conn,
+					Tracker:     wrapper,
+				}
+			}
+
+			var size int64
+			size, err = from.Seek(0, io.SeekEnd)
+			if err != nil {
+				return fmt.Errorf("Failed getting size of %q: %w", path, err)
+			}
+
+			err = genericVFSSendSparseBlock(from, 0, size, toPipe)
+		} else {
+			_, err = io.Copy(conn, fromPipe)
+		}
+
 		if err != nil {
 			return fmt.Errorf("Error copying %q to migration connection: %w", path, err)
 		}
@@ -243,14 +291,14 @@ func genericVFSMigrateVolume(d Driver, s *state.State, vol Volume, conn io.ReadW
 		// Send snapshot to target (ensure local snapshot volume is mounted if needed).
 		err = snapshot.MountTask(func(mountPath string, op *operations.Operation) error {
 			if vol.contentType != ContentTypeBlock || vol.volType != VolumeTypeCustom {
-				err := sendFSVol(snapshot, conn, mountPath)
+				err := sendFSVol(snapshot, conns, mountPath)
 				if err != nil {
 					return err
 				}
 			}
 
 			if vol.IsVMBlock() || (vol.contentType == ContentTypeBlock && vol.volType == VolumeTypeCustom) {
-				err = sendBlockVol(snapshot, conn)
+				err = sendBlockVol(snapshot, conns)
 				if err != nil {
 					return err
 				}
@@ -266,14 +314,14 @@ func genericVFSMigrateVolume(d Driver, s *state.State, vol Volume, conn io.ReadW
 	// Send volume to target (ensure local volume is mounted if needed).
 	return vol.MountTask(func(mountPath string, op *operations.Operation) error {
 		if !IsContentBlock(vol.contentType) || vol.volType != VolumeTypeCustom {
-			err := sendFSVol(vol, conn, mountPath)
+			err := sendFSVol(vol, conns, mountPath)
 			if err != nil {
 				return err
 			}
 		}
 
 		if vol.IsVMBlock() || (IsContentBlock(vol.contentType) && vol.volType == VolumeTypeCustom) {
-			err := sendBlockVol(vol, conn)
+			err := sendBlockVol(vol, conns)
 			if err != nil {
 				return err
 			}
@@ -305,10 +353,16 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 			return err
 		}
 
-		reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
+		// Keep the partially received data for the transfer to be continued.
+		if !volTargetArgs.KeepPartial {
+			reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
+		}
 	}
 
-	recvFSVol := func(volName string, conn io.ReadWriteCloser, path string) error {
+	// Receive over any additional connection agreed on with the source.
+	conns := append([]io.ReadWriteCloser{conn}, volTargetArgs.Streams...)
+
+	recvFSVol := func(volName string, conns []io.ReadWriteCloser, path string) error {
 		var wrapper *ioprogress.ProgressTracker
 		if volTargetArgs.TrackProgress {
 			wrapper = localMigration.ProgressTracker(op, "fs_progress", volName)
@@ -317,10 +371,12 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 		d.Logger().Debug("Receiving filesystem volume started", logger.Ctx{"volName": volName, "path": path, "features": volTargetArgs.MigrationType.Features})
 		defer d.Logger().Debug("Receiving filesystem volume stopped", logger.Ctx{"volName": volName, "path": path})
 
-		return rsync.Recv(path, conn, wrapper, volTargetArgs.MigrationType.Features)
+		return rsync.RecvParallel(path, conns, wrapper, volTargetArgs.MigrationType.Features)
 	}
 
-	recvBlockVol := func(volName string, conn io.ReadWriteCloser, path string) error {
+	sparseBlocks := slices.Contains(volTargetArgs.MigrationType.Features, migration.RsyncFeatureSparseBlocks)
+
+	recvBlockVol := func(volName string, conns []io.ReadWriteCloser, path string) error {
 		var wrapper *ioprogress.ProgressTracker
 		if volTargetArgs.TrackProgress {
 			wrapper = localMigration.ProgressTracker(op, "block_progress", volName)
@@ -339,6 +395,20 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 
 		defer func() { _ = to.Close() }()
 
+		if len(conns) > 1 {
+			err = to.Close()
+			if err != nil {
+				return err
+			}
+
+			d.Logger().Debug("Receiving block volume started", logger.Ctx{"volName": volName, "path": path, "streams": len(conns)})
+			defer d.Logger().Debug("Receiving block volume stopped", logger.Ctx{"volName": volName, "path": path})
+
+			return genericVFSRecvBlockRanges(path, conns, wrapper, !d.Info().ZeroUnpack, sparseBlocks)
+		}
+
+		conn := conns[0]
+
 		// Setup progress tracker.
 		fromPipe := io.ReadCloser(conn)
 		if wrapper != nil {
@@ -348,9 +418,24 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 			}
 		}
 
-		d.Logger().Debug("Receiving block volume started", logger.Ctx{"volName": volName, "path": path})
+		d.Logger().Debug("Receiving block volume started", logger.Ctx{"volName": volName, "path": path, "sparse": sparseBlocks})
 		defer d.Logger().Debug("Receiving block volume stopped", logger.Ctx{"volName": volName, "path": path})
 
+		if sparseBlocks {
+			end, err := genericVFSRecvSparseBlock(to, fromPipe, 0, !d.Info().ZeroUnpack)
+			if err != nil {
+				return fmt.Errorf("Error copying from migration connection to %q: %w", path, err)
+			}
+
+			err = to.Close()
+			if err != nil {
+				return err
+			}
+
+			// Restore the size of the disk file as the trailing holes aren't written.
+			return enlargeVolumeBlockFile(path, end)
+		}
+
 		toPipe := io.Writer(to)
 		if !d.Info().ZeroUnpack {
 			toPipe = NewSparseFileWrapper(to)
@@ -387,7 +472,7 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 			snapVol := NewVolume(d, d.Name(), vol.volType, vol.contentType, fullSnapshotName, vol.config, vol.poolConfig)
 
 			if snapVol.contentType != ContentTypeBlock || snapVol.volType != VolumeTypeCustom { // Receive the filesystem snapshot first (as it is sent first).
-				err = recvFSVol(snapVol.name, conn, path)
+				err = recvFSVol(snapVol.name, conns, path)
 				if err != nil {
 					return err
 				}
@@ -395,7 +480,7 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 
 			// Receive the block snapshot next (if needed).
 			if vol.IsVMBlock() || (vol.contentType == ContentTypeBlock && vol.volType == VolumeTypeCustom) {
-				err = recvBlockVol(snapVol.name, conn, pathBlock)
+				err = recvBlockVol(snapVol.name, conns, pathBlock)
 				if err != nil {
 					return err
 				}
@@ -424,9 +509,11 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 			}
 
 			// Setup the revert.
-			reverter.Add(func() {
-				_ = d.DeleteVolumeSnapshot(snapVol, op)
-			})
+			if !volTargetArgs.KeepPartial {
+				reverter.Add(func() {
+					_ = d.DeleteVolumeSnapshot(snapVol, op)
+				})
+			}
 		}
 
 		// Run volume-specific init logic.
@@ -439,7 +526,7 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 
 		if !IsContentBlock(vol.contentType) || vol.volType != VolumeTypeCustom {
 			// Receive main volume.
-			err = recvFSVol(vol.name, conn, path)
+			err = recvFSVol(vol.name, conns, path)
 			if err != nil {
 				return err
 			}
@@ -448,7 +535,7 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 		// Receive the final main volume sync if needed.
 		if volTargetArgs.Live && (!IsContentBlock(vol.contentType) || vol.volType != VolumeTypeCustom) {
 			d.Logger().Debug("Starting main volume final sync", logger.Ctx{"volName": vol.name, "path": path})
-			err = recvFSVol(vol.name, conn, path)
+			err = recvFSVol(vol.name, conns, path)
 			if err != nil {
 				return err
 			}
@@ -463,7 +550,7 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 
 		// Receive the block volume next (if needed).
 		if vol.IsVMBlock() || (IsContentBlock(vol.contentType) && vol.volType == VolumeTypeCustom) {
-			err = recvBlockVol(vol.name, conn, pathBlock)
+			err = recvBlockVol(vol.name, conns, pathBlock)
 			if err != nil {
 				return err
 			}
@@ -489,6 +576,299 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 	return nil
 }
 
+// genericVFSSendBlockRanges sends a block volume over multiple connections.
+// Each connection carries a contiguous range of the disk, prefixed with its offset.
+// If sparseBlocks is set, the ranges are sent with genericVFSSendSparseBlock.
+func genericVFSSendBlockRanges(path string, conns []io.ReadWriteCloser, tracker *ioprogress.ProgressTracker, sparseBlocks bool) error {
+	from, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Error opening file for reading %q: %w", path, err)
+	}
+
+	defer func() { _ = from.Close() }()
+
+	size, err := from.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("Failed getting size of %q: %w", path, err)
+	}
+
+	// Split the disk in ranges aligned on 1MiB.
+	rangeSize := max((size/int64(len(conns))+(1024*1024)-1)/(1024*1024)*(1024*1024), 1024*1024)
+
+	errs := make([]error, len(conns))
+	wg := sync.WaitGroup{}
+
+	for i, conn := range conns {
+		offset := min(int64(i)*rangeSize, size)
+		length := min(rangeSize, size-offset)
+
+		wg.Add(1)
+		go func(i int, conn io.ReadWriteCloser) {
+			defer wg.Done()
+
+			_, err := conn.Write(binary.BigEndian.AppendUint64(nil, uint64(offset)))
+			if err != nil {
+				errs[i] = fmt.Errorf("Error sending block range offset: %w", err)
+				return
+			}
+
+			if sparseBlocks {
+				// The progress tracker isn't safe for concurrent use, only track the first range.
+				toPipe := io.Writer(conn)
+				if i == 0 && tracker != nil {
+					toPipe = &ioprogress.ProgressWriter{
+						WriteCloser: conn,
+						Tracker:     tracker,
+					}
+				}
+
+				err = genericVFSSendSparseBlock(from, offset, length, toPipe)
+				if err != nil {
+					errs[i] = fmt.Errorf("Error copying %q to migration connection: %w", path, err)
+				}
+
+				return
+			}
+
+			// The progress tracker isn't safe for concurrent use, only track the first range.
+			fromPipe := io.NopCloser(io.NewSectionReader(from, offset, length))
+			if i == 0 && tracker != nil {
+				fromPipe = &ioprogress.ProgressReader{
+					ReadCloser: fromPipe,
+					Tracker:    tracker,
+				}
+			}
+
+			_, err = io.Copy(conn, fromPipe)
+			if err != nil {
+				errs[i] = fmt.Errorf("Error copying %q to migration connection: %w", path, err)
+			}
+		}(i, conn)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// genericVFSRecvBlockRanges receives a block volume sent by genericVFSSendBlockRanges.
+func genericVFSRecvBlockRanges(path string, conns []io.ReadWriteCloser, tracker *ioprogress.ProgressTracker, sparse bool, sparseBlocks bool) error {
+	errs := make([]error, len(conns))
+	ends := make([]int64, len(conns))
+	wg := sync.WaitGroup{}
+
+	recvRange := func(i int, conn io.ReadWriteCloser) error {
+		header := make([]byte, 8)
+		_, err := io.ReadFull(conn, header)
+		if err != nil {
+			return fmt.Errorf("Error receiving block range offset: %w", err)
+		}
+
+		// Each range uses its own file descriptor as writes rely on the current file offset.
+		to, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("Error opening file for writing %q: %w", path, err)
+		}
+
+		defer func() { _ = to.Close() }()
+
+		offset := int64(binary.BigEndian.Uint64(header))
+
+		_, err = to.Seek(offset, io.SeekStart)
+		if err != nil {
+			return err
+		}
+
+		fromPipe := io.ReadCloser(conn)
+		if i == 0 && tracker != nil {
+			fromPipe = &ioprogress.ProgressReader{
+				ReadCloser: fromPipe,
+				Tracker:    tracker,
+			}
+		}
+
+		if sparseBlocks {
+			ends[i], err = genericVFSRecvSparseBlock(to, fromPipe, offset, sparse)
+			if err != nil {
+				return fmt.Errorf("Error copying from migration connection to %q: %w", path, err)
+			}
+
+			return to.Close()
+		}
+
+		toPipe := io.Writer(to)
+		if sparse {
+			toPipe = NewSparseFileWrapper(to)
+		}
+
+		_, err = io.Copy(toPipe, fromPipe)
+		if err != nil {
+			return fmt.Errorf("Error copying from migration connection to %q: %w", path, err)
+		}
+
+		return to.Close()
+	}
+
+	for i, conn := range conns {
+		wg.Add(1)
+		go func(i int, conn io.ReadWriteCloser) {
+			defer wg.Done()
+
+			errs[i] = recvRange(i, conn)
+		}(i, conn)
+	}
+
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return err
+	}
+
+	// Restore the size of the disk file as the trailing holes aren't written.
+	if sparseBlocks {
+		return enlargeVolumeBlockFile(path, slices.Max(ends))
+	}
+
+	return nil
+}
+
+// genericVFSSendSparseBlock sends the range of a block volume starting at offset as a sequence of frames,
+// each made of the offset and length of a data extent followed by its content. Holes, found with SEEK_DATA
+// and SEEK_HOLE, and zero filled blocks are skipped. A last frame with no content carries the end of the range.
+func genericVFSSendSparseBlock(from *os.File, offset int64, length int64, conn io.Writer) error {
+	end := offset + length
+	buf := make([]byte, 1024*1024)
+	zeroBlock := make([]byte, genericVFSSparseBlockSize)
+
+	sendFrame := func(frameOffset int64, data []byte) error {
+		header := binary.BigEndian.AppendUint64(nil, uint64(frameOffset))
+		header = binary.BigEndian.AppendUint64(header, uint64(len(data)))
+
+		_, err := conn.Write(header)
+		if err != nil {
+			return err
+		}
+
+		if len(data) == 0 {
+			return nil
+		}
+
+		_, err = conn.Write(data)
+		return err
+	}
+
+	pos := offset
+	for pos < end {
+		// Find the next data extent. Block devices report their whole content as data.
+		dataStart, err := from.Seek(pos, unix.SEEK_DATA)
+		if errors.Is(err, unix.ENXIO) {
+			break // Only holes are left.
+		} else if err != nil {
+			return fmt.Errorf("Failed looking for data at offset %d: %w", pos, err)
+		}
+
+		if dataStart >= end {
+			break
+		}
+
+		dataEnd, err := from.Seek(dataStart, unix.SEEK_HOLE)
+		if err != nil {
+			return fmt.Errorf("Failed looking for hole at offset %d: %w", dataStart, err)
+		}
+
+		dataEnd = min(dataEnd, end)
+
+		// Send the extent, leaving out the zero filled blocks.
+		for chunkStart := dataStart; chunkStart < dataEnd; {
+			chunk := buf[:min(int64(len(buf)), dataEnd-chunkStart)]
+
+			_, err := from.ReadAt(chunk, chunkStart)
+			if err != nil {
+				return err
+			}
+
+			runStart := -1
+			for i := 0; i < len(chunk); i += genericVFSSparseBlockSize {
+				block := chunk[i:min(i+genericVFSSparseBlockSize, len(chunk))]
+
+				if !bytes.Equal(block, zeroBlock[:len(block)]) {
+					if runStart < 0 {
+						runStart = i
+					}
+
+					continue
+				}
+
+				if runStart >= 0 {
+					err = sendFrame(chunkStart+int64(runStart), chunk[runStart:i])
+					if err != nil {
+						return err
+					}
+
+					runStart = -1
+				}
+			}
+
+			if runStart >= 0 {
+				err = sendFrame(chunkStart+int64(runStart), chunk[runStart:])
+				if err != nil {
+					return err
+				}
+			}
+
+			chunkStart += int64(len(chunk))
+		}
+
+		pos = dataEnd
+	}
+
+	return sendFrame(end, nil)
+}
+
+// genericVFSRecvSparseBlock receives a block volume range starting at offset sent by genericVFSSendSparseBlock
+// and returns its end. Unless sparse is set, the skipped ranges are filled with zeroes.
+func genericVFSRecvSparseBlock(to *os.File, conn io.Reader, offset int64, sparse bool) (int64, error) {
+	header := make([]byte, 16)
+	zeroBlock := make([]byte, 1024*1024)
+
+	pos := offset
+	for {
+		_, err := io.ReadFull(conn, header)
+		if err != nil {
+			return -1, fmt.Errorf("Error receiving block extent: %w", err)
+		}
+
+		frameOffset := int64(binary.BigEndian.Uint64(header[:8]))
+		frameLength := int64(binary.BigEndian.Uint64(header[8:]))
+
+		if frameOffset < pos {
+			return -1, fmt.Errorf("Invalid block extent at offset %d, expected at least %d", frameOffset, pos)
+		}
+
+		// Write the zeroes explicitly if the target doesn't read holes back as zeroes.
+		for !sparse && pos < frameOffset {
+			n, err := to.WriteAt(zeroBlock[:min(int64(len(zeroBlock)), frameOffset-pos)], pos)
+			if err != nil {
+				return -1, err
+			}
+
+			pos += int64(n)
+		}
+
+		if frameLength == 0 {
+			return frameOffset, nil
+		}
+
+		_, err = io.CopyN(io.NewOffsetWriter(to, frameOffset), conn, frameLength)
+		if err != nil {
+			return -1, err
+		}
+
+		pos = frameOffset + frameLength
+	}
+}
+
 // genericVFSHasVolume is a generic HasVolume implementation for VFS-only drivers.
 func genericVFSHasVolume(vol Volume) (bool, error) {
 	_, err := os.Lstat(vol.MountPath())
@@ -1174,3 +1554,235 @@ func genericVFSListVolumes(d Driver) ([]Volume, error) {
 
 	return vols, nil
 }
+
+// genericVolumeDiffChunkSize is the granularity of the changed ranges reported for block volumes.
+const genericVolumeDiffChunkSize = 1024 * 1024
+
+// genericVFSVolumeSnapshotDiff compares two states of a volume by mounting them. The files of filesystem
+// volumes are compared by type, size, modification time, ownership and permissions, and the block devices of
+// block volumes by content. An empty snapB refers to the volume itself.
+func genericVFSVolumeSnapshotDiff(vol Volume, snapA string, snapB string, fn func(change api.StorageVolumeDiffEntry) error, op *operations.Operation) error {
+	if vol.contentType != ContentTypeFS && vol.contentType != ContentTypeBlock {
+		return ErrNotSupported
+	}
+
+	volA, err := vol.NewSnapshot(snapA)
+	if err != nil {
+		return err
+	}
+
+	volB := vol
+	if snapB != "" {
+		volB, err = vol.NewSnapshot(snapB)
+		if err != nil {
+			return err
+		}
+	}
+
+	return volA.MountTask(func(pathA string, op *operations.Operation) error {
+		return volB.MountTask(func(pathB string, op *operations.Operation) error {
+			if vol.contentType == ContentTypeFS {
+				return genericDiffDirectories(pathA, pathB, fn)
+			}
+
+			diskA, err := vol.driver.GetVolumeDiskPath(volA)
+			if err != nil {
+				return err
+			}
+
+			diskB, err := vol.driver.GetVolumeDiskPath(volB)
+			if err != nil {
+				return err
+			}
+
+			return genericDiffBlockDevices(diskA, diskB, fn)
+		}, op)
+	}, op)
+}
+
+// genericDiffDirectories calls fn for each file added, modified or deleted between the pathA and pathB trees.
+func genericDiffDirectories(pathA string, pathB string, fn func(change api.StorageVolumeDiffEntry) error) error {
+	// Report the files that were added or modified.
+	err := filepath.WalkDir(pathB, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(pathB, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+
+		infoB, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		infoA, err := os.Lstat(filepath.Join(pathA, relPath))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fn(api.StorageVolumeDiffEntry{Type: VolumeDiffAdded, Path: "/" + relPath})
+			}
+
+			return err
+		}
+
+		changed, err := genericFileChanged(filepath.Join(pathA, relPath), infoA, path, infoB)
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			return fn(api.StorageVolumeDiffEntry{Type: VolumeDiffModified, Path: "/" + relPath})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Report the files that were deleted.
+	return filepath.WalkDir(pathA, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(pathA, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+
+		_, err = os.Lstat(filepath.Join(pathB, relPath))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fn(api.StorageVolumeDiffEntry{Type: VolumeDiffDeleted, Path: "/" + relPath})
+			}
+
+			return err
+		}
+
+		return nil
+	})
+}
+
+// genericFileChanged returns whether the file was changed between its two states, in the same way that rsync
+// detects changes.
+func genericFileChanged(pathA string, infoA fs.FileInfo, pathB string, infoB fs.FileInfo) (bool, error) {
+	if infoA.Mode() != infoB.Mode() {
+		return true, nil
+	}
+
+	statA, okA := infoA.Sys().(*syscall.Stat_t)
+	statB, okB := infoB.Sys().(*syscall.Stat_t)
+	if okA && okB && (statA.Uid != statB.Uid || statA.Gid != statB.Gid || statA.Rdev != statB.Rdev) {
+		return true, nil
+	}
+
+	if infoA.Mode()&fs.ModeSymlink != 0 {
+		targetA, err := os.Readlink(pathA)
+		if err != nil {
+			return false, err
+		}
+
+		targetB, err := os.Readlink(pathB)
+		if err != nil {
+			return false, err
+		}
+
+		return targetA != targetB, nil
+	}
+
+	if infoA.Mode().IsRegular() && (infoA.Size() != infoB.Size() || !infoA.ModTime().Equal(infoB.ModTime())) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// genericDiffBlockDevices calls fn for each range of content that changed between the pathA and pathB block
+// devices or files. Adjacent changed chunks are merged into a single range.
+func genericDiffBlockDevices(pathA string, pathB string, fn func(change api.StorageVolumeDiffEntry) error) error {
+	sizeA, err := BlockDiskSizeBytes(pathA)
+	if err != nil {
+		return err
+	}
+
+	sizeB, err := BlockDiskSizeBytes(pathB)
+	if err != nil {
+		return err
+	}
+
+	fileA, err := os.Open(pathA)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = fileA.Close() }()
+
+	fileB, err := os.Open(pathB)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = fileB.Close() }()
+
+	var change *api.StorageVolumeDiffEntry
+	flush := func() error {
+		if change == nil {
+			return nil
+		}
+
+		err := fn(*change)
+		change = nil
+
+		return err
+	}
+
+	bufA := make([]byte, genericVolumeDiffChunkSize)
+	bufB := make([]byte, genericVolumeDiffChunkSize)
+	commonSize := min(sizeA, sizeB)
+
+	for offset := int64(0); offset < commonSize; offset += genericVolumeDiffChunkSize {
+		length := min(genericVolumeDiffChunkSize, commonSize-offset)
+
+		_, err = io.ReadFull(fileA, bufA[:length])
+		if err != nil {
+			return err
+		}
+
+		_, err = io.ReadFull(fileB, bufB[:length])
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(bufA[:length], bufB[:length]) {
+			err = flush()
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if change == nil {
+			change = &api.StorageVolumeDiffEntry{Type: VolumeDiffModified, Offset: offset}
+		}
+
+		change.Length += length
+	}
+
+	err = flush()
+	if err != nil {
+		return err
+	}
+
+	// Report the range by which the device grew or shrank.
+	if sizeB > sizeA {
+		return fn(api.StorageVolumeDiffEntry{Type: VolumeDiffAdded, Offset: sizeA, Length: sizeB - sizeA})
+	} else if sizeA > sizeB {
+		return fn(api.StorageVolumeDiffEntry{Type: VolumeDiffDeleted, Offset: sizeB, Length: sizeA - sizeB})
+	}
+
+	return nil
+}
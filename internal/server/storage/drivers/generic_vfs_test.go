@@ -0,0 +1,55 @@
+package drivers
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenericVFSSparseBlock(t *testing.T) {
+	dir := t.TempDir()
+
+	// Build a 16MiB disk with a hole, a zero filled block and two data extents.
+	data := bytes.Repeat([]byte{0xaa}, 8192)
+	size := int64(16 * 1024 * 1024)
+
+	from, err := os.Create(filepath.Join(dir, "source.img"))
+	require.NoError(t, err)
+	defer func() { _ = from.Close() }()
+
+	require.NoError(t, from.Truncate(size))
+	_, err = from.WriteAt(data, 4096)
+	require.NoError(t, err)
+	_, err = from.WriteAt(make([]byte, 65536), 1024*1024)
+	require.NoError(t, err)
+	_, err = from.WriteAt(data, 5*1024*1024+512)
+	require.NoError(t, err)
+
+	for _, sparse := range []bool{true, false} {
+		conn := &bytes.Buffer{}
+		require.NoError(t, genericVFSSendSparseBlock(from, 0, size, conn))
+
+		// Only the data and the frame headers are sent.
+		assert.Less(t, conn.Len(), 3*len(data))
+
+		to, err := os.Create(filepath.Join(dir, "target.img"))
+		require.NoError(t, err)
+
+		end, err := genericVFSRecvSparseBlock(to, conn, 0, sparse)
+		require.NoError(t, err)
+		assert.Equal(t, size, end)
+		require.NoError(t, to.Close())
+		require.NoError(t, enlargeVolumeBlockFile(to.Name(), end))
+
+		expected, err := os.ReadFile(from.Name())
+		require.NoError(t, err)
+
+		received, err := os.ReadFile(to.Name())
+		require.NoError(t, err)
+		assert.True(t, bytes.Equal(expected, received))
+	}
+}
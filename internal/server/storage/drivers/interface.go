@@ -46,6 +46,15 @@ type Driver interface {
 	// Unmount unmounts a storage pool if needed, returns true if unmounted, false if was not mounted.
 	Unmount() (bool, error)
 	GetResources() (*api.ResourcesStoragePool, error)
+
+	// GetStatistics returns the deduplication and compression statistics of the pool.
+	GetStatistics() (*api.StoragePoolStatistics, error)
+
+	// GetHealth returns the health of the pool as reported by the storage backend.
+	GetHealth() (*api.StoragePoolHealth, error)
+
+	// Scrub starts a background check of the integrity of the data of the pool.
+	Scrub() error
 	Validate(config map[string]string) error
 	Update(changedConfig map[string]string) error
 	ApplyPatch(name string) error
@@ -56,6 +65,8 @@ type Driver interface {
 	CreateBucket(bucket Volume, op *operations.Operation) error
 	DeleteBucket(bucket Volume, op *operations.Operation) error
 	UpdateBucket(bucket Volume, changedConfig map[string]string) error
+	SetBucketLifecycle(bucket Volume, lifecycle *BucketLifecycle, op *operations.Operation) error
+	SetBucketReplication(bucket Volume, replication *BucketReplication, op *operations.Operation) error
 	ValidateBucketKey(keyName string, creds S3Credentials, roleName string) error
 	CreateBucketKey(bucket Volume, keyName string, creds S3Credentials, roleName string, op *operations.Operation) (*S3Credentials, error)
 	UpdateBucketKey(bucket Volume, keyName string, creds S3Credentials, roleName string, op *operations.Operation) (*S3Credentials, error)
@@ -66,15 +77,42 @@ type Driver interface {
 	ValidateVolume(vol Volume, removeUnknownKeys bool) error
 	CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error
 	CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error
+
+	// CreateVolumeFromClone creates the volume as a copy-on-write clone of a new snapshot of the source
+	// volume, without copying its data or its snapshots.
+	CreateVolumeFromClone(vol Volume, srcVol Volume, op *operations.Operation) error
 	RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error
+
+	// AdoptVolume adopts an existing source of the pool's storage (such as a dataset, a subvolume or a logical
+	// volume) as the volume, without copying its data. When the source has a fixed size, it's recorded in the
+	// volume's size config.
+	AdoptVolume(vol Volume, source string, op *operations.Operation) (revert.Hook, error)
+
 	DeleteVolume(vol Volume, op *operations.Operation) error
 	RenameVolume(vol Volume, newName string, op *operations.Operation) error
 	UpdateVolume(vol Volume, changedConfig map[string]string) error
 	GetVolumeUsage(vol Volume) (int64, error)
+	GetVolumeSnapshotUsage(snapVol Volume) (int64, int64, error)
 	SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error
 	GetVolumeDiskPath(vol Volume) (string, error)
 	ListVolumes() ([]Volume, error)
 
+	// TrimVolumes discards the unused blocks of the supplied mounted filesystem volumes.
+	TrimVolumes(vols []Volume, op *operations.Operation) error
+
+	// GetVolumeMirror returns the mirroring state of the volume.
+	GetVolumeMirror(vol Volume) (*api.StorageVolumeMirror, error)
+
+	// PromoteVolume makes the local copy of a mirrored volume the primary one.
+	PromoteVolume(vol Volume, force bool, op *operations.Operation) error
+
+	// DemoteVolume makes the local copy of a mirrored volume a secondary one.
+	DemoteVolume(vol Volume, op *operations.Operation) error
+
+	// VolumeSnapshotDiff calls fn for each change of the volume between snapshot snapA and snapshot snapB,
+	// or the volume itself if snapB is empty.
+	VolumeSnapshotDiff(vol Volume, snapA string, snapB string, fn func(change api.StorageVolumeDiffEntry) error, op *operations.Operation) error
+
 	// MountVolume mounts a storage volume (if not mounted) and increments reference counter.
 	MountVolume(vol Volume, op *operations.Operation) error
 
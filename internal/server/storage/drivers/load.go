@@ -1,6 +1,8 @@
 package drivers
 
 import (
+	"slices"
+
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/logger"
 )
@@ -15,6 +17,10 @@ var drivers = map[string]func() driver{
 	"lvmcluster": func() driver { return &lvm{clustered: true} },
 	"zfs":        func() driver { return &zfs{} },
 	"linstor":    func() driver { return &linstor{} },
+	"iscsi":      func() driver { return &iscsi{} },
+	"nfs":        func() driver { return &nfs{} },
+	"raw":        func() driver { return &raw{} },
+	"plugin":     func() driver { return &plugin{} },
 }
 
 // Validators contains functions used for validating a drivers's config.
@@ -39,6 +45,12 @@ func Load(state *state.State, driverName string, name string, config map[string]
 		driverFunc = df
 	}
 
+	// Block volumes of some drivers can be encrypted.
+	encrypted := !state.OS.MockMode && slices.Contains(encryptionDrivers, driverName)
+	if encrypted {
+		commonRules = encryptionValidators(commonRules)
+	}
+
 	d := driverFunc()
 	d.init(state, name, config, logger, volIDFunc, commonRules)
 
@@ -47,6 +59,10 @@ func Load(state *state.State, driverName string, name string, config map[string]
 		return nil, err
 	}
 
+	if encrypted {
+		return &encryption{Driver: d, state: state}, nil
+	}
+
 	return d, nil
 }
 
@@ -237,6 +237,16 @@ func fsProbe(path string) (string, error) {
 	return strings.TrimSpace(val), nil
 }
 
+// managedPoolDirs are the top-level directories (or datasets) of a pool holding the volumes managed by Incus.
+var managedPoolDirs = []string{"buckets", "containers", "custom", "deleted", "images", "virtual-machines"}
+
+// isManagedPoolPath returns whether the path, relative to the root of a pool, holds volumes managed by Incus.
+func isManagedPoolPath(relPath string) bool {
+	first, _, _ := strings.Cut(filepath.Clean(relPath), "/")
+
+	return first == "." || first == ".." || slices.Contains(managedPoolDirs, strings.TrimSuffix(first, "-snapshots"))
+}
+
 // GetPoolMountPath returns the mountpoint of the given pool.
 // {INCUS_DIR}/storage-pools/<pool>.
 func GetPoolMountPath(poolName string) string {
@@ -363,10 +373,6 @@ func ensureVolumeBlockFile(vol Volume, path string, sizeBytes int64, allowUnsafe
 			if sizeBytes < oldSizeBytes {
 				return false, fmt.Errorf("Block volumes cannot be shrunk: %w", ErrCannotBeShrunk)
 			}
-
-			if vol.MountInUse() {
-				return false, ErrInUse // We don't allow online resizing of block volumes.
-			}
 		}
 
 		err = ensureSparseFile(path, sizeBytes)
@@ -1002,3 +1008,22 @@ func roundAbove(above, val int64) int64 {
 
 	return rounded
 }
+
+// fstrimVolumes discards the unused blocks of the filesystems of the volumes that are mounted.
+func fstrimVolumes(vols []Volume) error {
+	var errs []error
+
+	for _, vol := range vols {
+		mountPath := vol.MountPath()
+		if !linux.IsMountPoint(mountPath) {
+			continue
+		}
+
+		_, err := subprocess.RunCommand("fstrim", mountPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed trimming volume %q: %w", vol.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -35,3 +35,15 @@ func TestGetVolumeMountPath(t *testing.T) {
 	expected = GetPoolMountPath(poolName) + "/virtual-machines/testvol"
 	assert.Equal(t, expected, path)
 }
+
+// Test isManagedPoolPath.
+func TestIsManagedPoolPath(t *testing.T) {
+	assert.True(t, isManagedPoolPath("custom/default_foo"))
+	assert.True(t, isManagedPoolPath("custom-snapshots/default_foo/snap0"))
+	assert.True(t, isManagedPoolPath("containers"))
+	assert.True(t, isManagedPoolPath("."))
+	assert.True(t, isManagedPoolPath("../data"))
+	assert.False(t, isManagedPoolPath("data"))
+	assert.False(t, isManagedPoolPath("data/custom"))
+	assert.False(t, isManagedPoolPath("customers"))
+}
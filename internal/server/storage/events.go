@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// sendVolumeEvent broadcasts a storage event about a volume of the pool.
+func (b *backend) sendVolumeEvent(projectName string, action string, volName string, volType drivers.VolumeType, ctx map[string]any) {
+	volTypeName := string(volType)
+
+	volDBType, err := VolumeTypeToDBType(volType)
+	if err == nil {
+		volTypeName = db.StoragePoolVolumeTypeNames[volDBType]
+	}
+
+	_ = b.state.Events.Send(projectName, api.EventTypeStorage, api.EventStorage{
+		Action:     action,
+		Pool:       b.name,
+		Volume:     volName,
+		VolumeType: volTypeName,
+		Project:    projectName,
+		Context:    ctx,
+	})
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/storage/drivers"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+)
+
+// integrityBlockChunkSize is the size of the chunks of block volumes which are hashed separately.
+const integrityBlockChunkSize = 4 * 1024 * 1024
+
+// integrityMaxMismatches is the maximum number of mismatches reported individually.
+const integrityMaxMismatches = 5
+
+// IntegrityManifest records the checksums of the content of a volume.
+// For filesystem volumes, the checksums are keyed by the path of the files relative to the volume.
+// For block volumes, they're keyed by the offset of the chunks of the volume.
+type IntegrityManifest struct {
+	ContentType string            `json:"content_type"`
+	Checksums   map[string]string `json:"checksums"`
+}
+
+// IntegrityManifestPath returns the path of the integrity manifest of a custom volume.
+func IntegrityManifestPath(poolName string, projectName string, volName string) string {
+	return internalUtil.VarPath("integrity", poolName, project.StorageVolume(projectName, volName)+".json")
+}
+
+// ResetIntegrityManifest removes the integrity manifest of a custom volume, if any.
+// The next integrity check then records a new manifest.
+func ResetIntegrityManifest(poolName string, projectName string, volName string) error {
+	err := os.Remove(IntegrityManifestPath(poolName, projectName, volName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// hashReader returns the SHA-256 checksum of the content of the reader.
+func hashReader(r io.Reader) (string, error) {
+	hash := sha256.New()
+
+	_, err := io.Copy(hash, r)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// NewFilesystemIntegrityManifest computes the integrity manifest of the files and symlinks under the directory.
+func NewFilesystemIntegrityManifest(dirPath string) (*IntegrityManifest, error) {
+	manifest := &IntegrityManifest{ContentType: string(drivers.ContentTypeFS), Checksums: map[string]string{}}
+
+	err := filepath.WalkDir(dirPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dirPath, path)
+		if err != nil {
+			return err
+		}
+
+		switch entry.Type() {
+		case 0:
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+
+			defer f.Close()
+
+			manifest.Checksums[relPath], err = hashReader(f)
+			if err != nil {
+				return fmt.Errorf("Failed hashing %q: %w", relPath, err)
+			}
+
+		case fs.ModeSymlink:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			manifest.Checksums[relPath] = "symlink:" + target
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// NewBlockIntegrityManifest computes the integrity manifest of the block device or disk image.
+func NewBlockIntegrityManifest(devPath string) (*IntegrityManifest, error) {
+	manifest := &IntegrityManifest{ContentType: string(drivers.ContentTypeBlock), Checksums: map[string]string{}}
+
+	f, err := os.Open(devPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var offset int64
+	for {
+		chunk := io.LimitReader(f, integrityBlockChunkSize)
+
+		hash := sha256.New()
+		n, err := io.Copy(hash, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("Failed hashing %q at offset %d: %w", devPath, offset, err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		manifest.Checksums[strconv.FormatInt(offset, 10)] = hex.EncodeToString(hash.Sum(nil))
+		offset += n
+	}
+
+	return manifest, nil
+}
+
+// LoadIntegrityManifest loads the integrity manifest at the path, returning nil if it doesn't exist.
+func LoadIntegrityManifest(path string) (*IntegrityManifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	manifest := &IntegrityManifest{}
+	err = json.Unmarshal(content, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing integrity manifest %q: %w", path, err)
+	}
+
+	return manifest, nil
+}
+
+// Save writes the integrity manifest to the path.
+func (m *IntegrityManifest) Save(path string) error {
+	content, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o600)
+}
+
+// Compare returns the differences of the current manifest compared to the recorded one.
+// At most integrityMaxMismatches differences are reported individually, followed by the number of others.
+func (m *IntegrityManifest) Compare(current *IntegrityManifest) []string {
+	var mismatches []string
+
+	if m.ContentType != current.ContentType {
+		return []string{fmt.Sprintf("Content type changed from %q to %q", m.ContentType, current.ContentType)}
+	}
+
+	name := "File"
+	if m.ContentType == string(drivers.ContentTypeBlock) {
+		name = "Chunk at offset"
+	}
+
+	for key, checksum := range m.Checksums {
+		currentChecksum, found := current.Checksums[key]
+		if !found {
+			mismatches = append(mismatches, fmt.Sprintf("%s %s is missing", name, key))
+		} else if currentChecksum != checksum {
+			mismatches = append(mismatches, fmt.Sprintf("%s %s changed", name, key))
+		}
+	}
+
+	for key := range current.Checksums {
+		_, found := m.Checksums[key]
+		if !found {
+			mismatches = append(mismatches, fmt.Sprintf("%s %s was added", name, key))
+		}
+	}
+
+	slices.Sort(mismatches)
+
+	if len(mismatches) > integrityMaxMismatches {
+		others := len(mismatches) - integrityMaxMismatches
+		mismatches = append(mismatches[:integrityMaxMismatches], fmt.Sprintf("%d other mismatches", others))
+	}
+
+	return mismatches
+}
+
+// VerifyCustomVolumeIntegrity checks the content of a custom volume against its integrity manifest, returning
+// the mismatches found. When the volume doesn't have a manifest yet, one is recorded and no mismatches are
+// returned.
+func VerifyCustomVolumeIntegrity(pool Pool, projectName string, volName string) ([]string, error) {
+	volume, err := VolumeDBGet(pool, projectName, volName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = pool.MountCustomVolume(projectName, volName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _, _ = pool.UnmountCustomVolume(projectName, volName, nil) }()
+
+	var current *IntegrityManifest
+	if drivers.ContentType(volume.ContentType) == drivers.ContentTypeFS {
+		mountPath := drivers.GetVolumeMountPath(pool.Name(), drivers.VolumeTypeCustom, project.StorageVolume(projectName, volName))
+		current, err = NewFilesystemIntegrityManifest(mountPath)
+	} else {
+		var devPath string
+		devPath, err = pool.GetCustomVolumeDisk(projectName, volName)
+		if err != nil {
+			return nil, err
+		}
+
+		current, err = NewBlockIntegrityManifest(devPath)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifestPath := IntegrityManifestPath(pool.Name(), projectName, volName)
+	recorded, err := LoadIntegrityManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if recorded == nil {
+		return nil, current.Save(manifestPath)
+	}
+
+	return recorded.Compare(current), nil
+}
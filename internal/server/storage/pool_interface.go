@@ -24,6 +24,20 @@ type VolumeUsage struct {
 	Total int64
 }
 
+// VolumeLimits contains the I/O limits of a volume, zero values meaning no limit.
+type VolumeLimits struct {
+	ReadBytes  int64
+	ReadIOps   int64
+	WriteBytes int64
+	WriteIOps  int64
+}
+
+// SnapshotUsage contains the space used by the data of a snapshot and the space unique to it.
+type SnapshotUsage struct {
+	Used   int64
+	Unique int64
+}
+
 // MountInfo represents info about the result of a mount operation.
 type MountInfo struct {
 	DiskPath  string                               // The location of the block disk (if supported).
@@ -50,7 +64,12 @@ type Pool interface {
 	ToAPI() api.StoragePool
 
 	GetResources() (*api.ResourcesStoragePool, error)
+	GetStatistics() (*api.StoragePoolStatistics, error)
+	CheckHealth() (*api.StoragePoolHealth, error)
+	Health() *api.StoragePoolHealth
+	Scrub() error
 	IsUsed() (bool, error)
+	TrimVolumes(op *operations.Operation) error
 	Delete(clientType request.ClientType, op *operations.Operation) error
 	Update(clientType request.ClientType, newDesc string, newConfig map[string]string, op *operations.Operation) error
 
@@ -66,9 +85,11 @@ type Pool interface {
 	CreateInstance(inst instance.Instance, op *operations.Operation) error
 	CreateInstanceFromBackup(srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (func(instance.Instance) error, revert.Hook, error)
 	CreateInstanceFromCopy(inst instance.Instance, src instance.Instance, snapshots bool, allowInconsistent bool, op *operations.Operation) error
+	CreateInstanceFromClone(inst instance.Instance, src instance.Instance, op *operations.Operation) error
 	CreateInstanceFromImage(inst instance.Instance, fingerprint string, op *operations.Operation) error
 	CreateInstanceFromMigration(inst instance.Instance, conn io.ReadWriteCloser, args migration.VolumeTargetArgs, op *operations.Operation) error
 	RenameInstance(inst instance.Instance, newName string, op *operations.Operation) error
+	MoveInstanceProject(inst instance.Instance, newProjectName string, op *operations.Operation) error
 	DeleteInstance(inst instance.Instance, op *operations.Operation) error
 	UpdateInstance(inst instance.Instance, newDesc string, newConfig map[string]string, op *operations.Operation) error
 	UpdateInstanceBackupFile(inst instance.Instance, snapshots bool, op *operations.Operation) error
@@ -82,6 +103,7 @@ type Pool interface {
 	BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, op *operations.Operation) error
 
 	GetInstanceUsage(inst instance.Instance) (*VolumeUsage, error)
+	GetInstanceSnapshotUsage(snapInst instance.Instance) (*SnapshotUsage, error)
 	SetInstanceQuota(inst instance.Instance, size string, vmStateSize string, op *operations.Operation) error
 
 	MountInstance(inst instance.Instance, op *operations.Operation) (*MountInfo, error)
@@ -119,11 +141,16 @@ type Pool interface {
 	// Custom volumes.
 	CreateCustomVolume(projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, op *operations.Operation) error
 	CreateCustomVolumeFromCopy(projectName string, srcProjectName string, volName, desc string, config map[string]string, srcPoolName, srcVolName string, snapshots bool, op *operations.Operation) error
+	AdoptCustomVolume(projectName string, volName string, source string, desc string, config map[string]string, contentType drivers.ContentType, op *operations.Operation) error
 	UpdateCustomVolume(projectName string, volName string, newDesc string, newConfig map[string]string, op *operations.Operation) error
 	RenameCustomVolume(projectName string, volName string, newVolName string, op *operations.Operation) error
 	DeleteCustomVolume(projectName string, volName string, op *operations.Operation) error
 	GetCustomVolumeDisk(projectName string, volName string) (string, error)
 	GetCustomVolumeUsage(projectName string, volName string) (*VolumeUsage, error)
+	GetCustomVolumeMirror(projectName string, volName string) (*api.StorageVolumeMirror, error)
+	PromoteCustomVolume(projectName string, volName string, force bool, op *operations.Operation) error
+	DemoteCustomVolume(projectName string, volName string, op *operations.Operation) error
+	CustomVolumeSnapshotDiff(projectName string, volName string, snapA string, snapB string, fn func(change api.StorageVolumeDiffEntry) error, op *operations.Operation) error
 	MountCustomVolume(projectName string, volName string, op *operations.Operation) (*MountInfo, error)
 	UnmountCustomVolume(projectName string, volName string, op *operations.Operation) (bool, error)
 	ImportCustomVolume(projectName string, poolVol *backupConfig.Config, op *operations.Operation) (revert.Hook, error)
@@ -149,4 +176,9 @@ type Pool interface {
 
 	// Storage volume recovery.
 	ListUnknownVolumes(op *operations.Operation) (map[string][]*backupConfig.Config, error)
+
+	// Orphaned storage volumes.
+	ListOrphanVolumes(op *operations.Operation) ([]OrphanVolume, error)
+	AdoptOrphanVolume(orphan OrphanVolume, op *operations.Operation) error
+	DeleteOrphanVolume(orphan OrphanVolume, op *operations.Operation) error
 }
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -249,3 +251,29 @@ func (c *AdminClient) ExportIAM(ctx context.Context) ([]byte, error) {
 
 	return iamBytes, nil
 }
+
+// AddReplication replicates the bucket to the remote bucket URL, which includes its credentials.
+func (c *AdminClient) AddReplication(ctx context.Context, bucketName string, remoteURL string) error {
+	_, err := c.runClientCommand(ctx, "", "replicate", "add", c.alias+"/"+bucketName, "--remote-bucket", remoteURL, "--priority", "1")
+	if err != nil {
+		// Don't return the command line as it contains the credentials of the remote bucket.
+		var runErr subprocess.RunError
+		if errors.As(err, &runErr) {
+			return fmt.Errorf("Failed adding bucket replication: %s", strings.TrimSpace(runErr.StdErr().String()))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// RemoveReplication removes all the replication rules of the bucket.
+func (c *AdminClient) RemoveReplication(ctx context.Context, bucketName string) error {
+	_, err := c.runClientCommand(ctx, "", "replicate", "rm", "--all", "--force", c.alias+"/"+bucketName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"strconv"
+	"sync"
+)
+
+// operationPriority is the priority of an expensive storage operation waiting for a slot of its pool.
+type operationPriority int
+
+const (
+	// operationPriorityLow is used for background work, like backups and snapshot deletions.
+	operationPriorityLow operationPriority = iota
+
+	// operationPriorityNormal is used for the creation and copy of volumes, which users are waiting on.
+	operationPriorityNormal
+)
+
+// operationScheduler limits the number of concurrent expensive operations of a storage pool.
+// Waiting operations are started by order of priority, and by order of arrival for a given priority.
+type operationScheduler struct {
+	mu      sync.Mutex
+	limit   int
+	running int
+	waiting [operationPriorityNormal + 1][]chan struct{}
+}
+
+// operationSchedulers holds the operation scheduler of each storage pool, by pool name.
+var operationSchedulers = map[string]*operationScheduler{}
+var operationSchedulersMu sync.Mutex
+
+// poolOperationScheduler returns the operation scheduler of the storage pool.
+func poolOperationScheduler(poolName string) *operationScheduler {
+	operationSchedulersMu.Lock()
+	defer operationSchedulersMu.Unlock()
+
+	scheduler, ok := operationSchedulers[poolName]
+	if !ok {
+		scheduler = &operationScheduler{}
+		operationSchedulers[poolName] = scheduler
+	}
+
+	return scheduler
+}
+
+// acquire waits for a slot to run an operation of the given priority, with at most limit operations running
+// at once (no limit if 0). The returned function must be called to release the slot once the operation is done.
+func (s *operationScheduler) acquire(limit int, priority operationPriority) func() {
+	s.mu.Lock()
+
+	// Use the latest limit, which allows starting waiting operations right away when it's raised.
+	s.limit = limit
+	s.startWaiting()
+
+	if s.limit <= 0 || (s.running < s.limit && s.waitingCount() == 0) {
+		s.running++
+		s.mu.Unlock()
+
+		return s.release
+	}
+
+	ready := make(chan struct{})
+	s.waiting[priority] = append(s.waiting[priority], ready)
+	s.mu.Unlock()
+
+	<-ready
+
+	return s.release
+}
+
+// release frees the slot of a finished operation and starts the next waiting ones.
+func (s *operationScheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running--
+	s.startWaiting()
+}
+
+// startWaiting starts waiting operations by order of priority while slots are available.
+// Must be called with the lock held.
+func (s *operationScheduler) startWaiting() {
+	for priority := operationPriorityNormal; priority >= operationPriorityLow; priority-- {
+		for len(s.waiting[priority]) > 0 && (s.limit <= 0 || s.running < s.limit) {
+			ready := s.waiting[priority][0]
+			s.waiting[priority] = s.waiting[priority][1:]
+			s.running++
+			close(ready)
+		}
+	}
+}
+
+// waitingCount returns the number of waiting operations.
+// Must be called with the lock held.
+func (s *operationScheduler) waitingCount() int {
+	count := 0
+	for _, waiting := range s.waiting {
+		count += len(waiting)
+	}
+
+	return count
+}
+
+// acquireOperation waits for a slot to run an expensive operation on the pool, as limited by its
+// operations.concurrency option. The returned function must be called once the operation is done.
+func (b *backend) acquireOperation(priority operationPriority) func() {
+	// The option is validated when set, so an invalid value can't happen and means no limit.
+	limit, _ := strconv.Atoi(b.db.Config["operations.concurrency"])
+
+	return poolOperationScheduler(b.name).acquire(limit, priority)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -35,6 +36,7 @@ import (
 	"github.com/lxc/incus/v6/shared/archive"
 	"github.com/lxc/incus/v6/shared/ioprogress"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
@@ -489,6 +491,15 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		},
 		"snapshots.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
 		"snapshots.pattern":  validate.IsAny,
+		"snapshots.retention": func(value string) error {
+			_, err := internalInstance.ParseSnapshotRetention(value)
+			return err
+		},
+	}
+
+	// Options relevant for custom volumes.
+	if vol == nil || vol.Type() == drivers.VolumeTypeCustom {
+		rules["integrity.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))
 	}
 
 	// Options relevant for custom filesystem volumes.
@@ -505,9 +516,68 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		rules["security.shared"] = validate.Optional(validate.IsBool)
 	}
 
+	// I/O limits are only relevant for custom volumes.
+	if (vol == nil) || (vol != nil && vol.Type() == drivers.VolumeTypeCustom) {
+		rules["limits.read.bandwidth"] = validate.Optional(validate.IsSize)
+		rules["limits.read.iops"] = validate.Optional(validate.IsUint32)
+		rules["limits.write.bandwidth"] = validate.Optional(validate.IsSize)
+		rules["limits.write.iops"] = validate.Optional(validate.IsUint32)
+	}
+
 	return rules
 }
 
+// VolumeLimitsFromConfig parses the I/O limits of a custom volume from its config.
+// Nil is returned when no limit is set.
+func VolumeLimitsFromConfig(config map[string]string) (*VolumeLimits, error) {
+	limits := VolumeLimits{}
+	found := false
+
+	for _, key := range []string{"limits.read.bandwidth", "limits.write.bandwidth"} {
+		if config[key] == "" {
+			continue
+		}
+
+		value, err := units.ParseByteSizeString(config[key])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for %q: %w", key, err)
+		}
+
+		if key == "limits.read.bandwidth" {
+			limits.ReadBytes = value
+		} else {
+			limits.WriteBytes = value
+		}
+
+		found = true
+	}
+
+	for _, key := range []string{"limits.read.iops", "limits.write.iops"} {
+		if config[key] == "" {
+			continue
+		}
+
+		value, err := strconv.ParseInt(config[key], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for %q: %w", key, err)
+		}
+
+		if key == "limits.read.iops" {
+			limits.ReadIOps = value
+		} else {
+			limits.WriteIOps = value
+		}
+
+		found = true
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return &limits, nil
+}
+
 // validatePoolCommonRules returns a map of pool config rules common to all drivers.
 func validatePoolCommonRules() map[string]func(string) error {
 	rules := map[string]func(string) error{
@@ -516,6 +586,8 @@ func validatePoolCommonRules() map[string]func(string) error {
 		"volatile.initial_source": validate.IsAny,
 		"rsync.bwlimit":           validate.Optional(validate.IsSize),
 		"rsync.compression":       validate.Optional(validate.IsBool),
+		"storage.alert.threshold": validate.Optional(validate.IsInRange(1, 100)),
+		"operations.concurrency":  validate.Optional(validate.IsUint32),
 	}
 
 	// Add to pool config rules (prefixed with volume.*) which are common for pool and volume.
@@ -552,6 +624,16 @@ func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error
 		rules["volatile.rootfs.size"] = validate.Optional(validate.IsInt64)
 	}
 
+	// Lifecycle rules and replication are only relevant for buckets.
+	if vol.Type() == drivers.VolumeTypeBucket {
+		rules["lifecycle.expiry"] = validate.Optional(validate.IsUint32)
+		rules["lifecycle.expiry.noncurrent"] = validate.Optional(validate.IsUint32)
+		rules["lifecycle.prefix"] = validate.IsAny
+		rules["replication.target"] = validate.IsAny
+		rules["replication.access_key"] = validate.IsAny
+		rules["replication.secret_key"] = validate.IsAny
+	}
+
 	return rules
 }
 
@@ -1009,6 +1091,50 @@ func VolumeUsedByExclusiveRemoteInstancesWithProfiles(s *state.State, poolName s
 	return remoteInstance, nil
 }
 
+// VolumeUsedByUnfencedRemoteInstance returns a running instance using the volume on another cluster member which
+// is offline and hasn't been evacuated. Such a member may still be writing to the volume, so a shared volume must
+// not be used elsewhere until the member has been fenced by evacuating it.
+func VolumeUsedByUnfencedRemoteInstance(s *state.State, poolName string, projectName string, vol *api.StorageVolume) (*db.InstanceArgs, error) {
+	var remoteInstances []db.InstanceArgs
+	err := VolumeUsedByInstanceDevices(s, poolName, projectName, vol, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
+		if dbInst.Node != s.ServerName && dbInst.Config["volatile.last_state.power"] == instance.PowerStateRunning {
+			remoteInstances = append(remoteInstances, dbInst)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(remoteInstances) == 0 {
+		return nil, nil
+	}
+
+	var members []db.NodeInfo
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		members, err = tx.GetNodes(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading cluster members: %w", err)
+	}
+
+	for _, member := range members {
+		if !member.IsOffline(s.GlobalConfig.OfflineThreshold()) || member.State == db.ClusterMemberStateEvacuated {
+			continue
+		}
+
+		for _, dbInst := range remoteInstances {
+			if dbInst.Node == member.Name {
+				return &dbInst, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
 // VolumeUsedByDaemon indicates whether the volume is used by daemon storage.
 func VolumeUsedByDaemon(s *state.State, poolName string, volumeName string) (bool, error) {
 	var storageBackups string
@@ -1161,6 +1287,33 @@ func CompareSnapshots(sourceSnapshots []ComparableSnapshot, targetSnapshots []Co
 	return syncFromSource, deleteFromTarget
 }
 
+// RefreshBaseSnapshot returns the name of the latest snapshot held identically by the source and the target, from
+// which a refresh can send the changes as a diff. The syncFromSource indexes are those returned by CompareSnapshots.
+// An empty string is returned if there is no such snapshot or if some snapshots to sync are older than it, as those
+// can't be applied on top of it.
+func RefreshBaseSnapshot(sourceSnapshots []ComparableSnapshot, targetSnapshots []ComparableSnapshot, syncFromSource []int) string {
+	// The snapshots to sync must be the latest ones of the source.
+	first := len(sourceSnapshots) - len(syncFromSource)
+	for i, sourceSnapIndex := range syncFromSource {
+		if sourceSnapIndex != first+i {
+			return ""
+		}
+	}
+
+	if first <= 0 {
+		return ""
+	}
+
+	base := sourceSnapshots[first-1]
+	for _, targetSnap := range targetSnapshots {
+		if targetSnap.Name == base.Name && targetSnap.CreationDate.Equal(base.CreationDate) && targetSnap.ID == base.ID {
+			return base.Name
+		}
+	}
+
+	return ""
+}
+
 // CalculateVolumeSnapshotSize returns the size of a volume snapshot in bytes.
 func CalculateVolumeSnapshotSize(projectName string, pool Pool, contentType drivers.ContentType, volumeType drivers.VolumeType, volName string, snapName string) (int64, error) {
 	if contentType != drivers.ContentTypeBlock {
@@ -51,6 +51,7 @@ func (s *OS) initDirs() error {
 		{filepath.Join(s.VarDir, "guestapi"), 0o755},
 		{filepath.Join(s.VarDir, "images"), 0o700},
 		{s.LogDir, 0o700},
+		{filepath.Join(s.VarDir, "migrations"), 0o700},
 		{filepath.Join(s.VarDir, "networks"), 0o711},
 		{s.RunDir, 0o711},
 		{filepath.Join(s.VarDir, "security"), 0o700},
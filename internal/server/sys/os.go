@@ -57,6 +57,7 @@ type OS struct {
 	MockMode        bool   // If true some APIs will be mocked (for testing)
 	Nodev           bool
 	RunningInUserNS bool
+	Rootless        bool // If true the daemon was started by an unprivileged user through "incusd --rootless"
 	Hostname        string
 
 	// Privilege dropping
@@ -174,6 +175,7 @@ func (s *OS) Init() ([]cluster.Warning, error) {
 	s.IdmapSet = getIdmapset()
 	s.ExecPath = localUtil.GetExecPath()
 	s.RunningInUserNS = linux.RunningInUserNS()
+	s.Rootless = s.RunningInUserNS && os.Getenv("INCUS_ROOTLESS") != ""
 	s.Hostname, err = os.Hostname()
 	if err != nil {
 		return nil, err
@@ -484,6 +484,92 @@ var APIExtensions = []string{
 	"instance_publish_split",
 	"init_preseed_certificates",
 	"custom_volume_sftp",
+	"migration_postcopy",
+	"storage_driver_plugin",
+	"authentication_external",
+	"migration_resume",
+	"migration_parallel_streams",
+	"migration_compression",
+	"instance_migration_check",
+	"clustering_groups_server_config",
+	"image_alias_namespaces",
+	"instance_migration_progress",
+	"instance_live_fallback",
+	"images_cache",
+	"migration_encryption",
+	"agent_clock_sync",
+	"migration_concurrency",
+	"disk_io_identity",
+	"migration_snapshot_diff_refresh",
+	"storage_dir_quota_warning",
+	"migration_statistics",
+	"instance_disk_usage",
+	"snapshot_usage_breakdown",
+	"migration_raw_transport",
+	"migration_incremental_memory_dirty_rate",
+	"instance_snapshot_files",
+	"migration_relay",
+	"instance_move_atomic_rename",
+	"snapshots_max_count",
+	"instance_debug_qmp",
+	"migration_abort",
+	"cluster_migration_address",
+	"instance_debug_lxc_config",
+	"migration_verify",
+	"instance_device_media",
+	"instance_console_serials",
+	"migration_warm",
+	"storage_pool_migration",
+	"device_shmem",
+	"metrics_usage_ratios",
+	"instance_project_move_in_place",
+	"clustering_labels",
+	"instances_reserved_resources",
+	"storage_driver_nfs",
+	"instances_overcommit",
+	"storage_driver_iscsi",
+	"instance_copy_instant",
+	"storage_volume_encryption",
+	"instance_rebuild_latest_image",
+	"storage_volume_limits",
+	"image_sbom",
+	"storage_volume_move_live",
+	"storage_fstrim_schedule",
+	"instance_exec_sync",
+	"storage_volume_snapshot_diff",
+	"server_rootless",
+	"storage_volume_mirror",
+	"instance_suspended_status",
+	"migration_zfs_resume_tokens",
+	"network_nic_limits_schedule",
+	"storage_pool_statistics",
+	"image_alias_architectures",
+	"instance_workload_tokens",
+	"storage_pool_health",
+	"storage_driver_raw",
+	"instance_guest_snapshots",
+	"instances_names_scope",
+	"storage_online_block_resize",
+	"snapshots_retention",
+	"storage_volume_integrity",
+	"event_history",
+	"storage_volume_import_existing",
+	"storage_events",
+	"config_revisions",
+	"instance_access_links",
+	"storage_bucket_lifecycle",
+	"storage_pool_cache",
+	"backup_target",
+	"reports",
+	"storage_lazy_activation",
+	"instance_debug_netns",
+	"image_aliases_lookup",
+	"storage_operations_concurrency",
+	"migration_timeouts",
+	"clustering_member_snapshots",
+	"storage_volume_import_url",
+	"instance_debug_profile",
+	"projects_limits_disk_artifacts",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
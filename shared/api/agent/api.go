@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // API10Put contains the fields which are needed for the incus-agent to connect to Incus.
 type API10Put struct {
 	// Context ID
@@ -18,3 +22,21 @@ type API10Put struct {
 	// Example: true
 	DevIncus bool `json:"dev_incus" yaml:"dev_incus"`
 }
+
+// ClockPut contains the fields which are needed for the incus-agent to correct the guest clock.
+type ClockPut struct {
+	// Current time on the host
+	// Example: 2021-03-23T20:00:00-04:00
+	Time time.Time `json:"time" yaml:"time"`
+
+	// How to correct the clock (step or slew)
+	// Example: step
+	Mode string `json:"mode" yaml:"mode"`
+}
+
+// DiskGrowPost contains the fields which are needed for the incus-agent to grow a disk after it was resized.
+type DiskGrowPost struct {
+	// Serial of the disk in the guest
+	// Example: incus_root
+	Serial string `json:"serial" yaml:"serial"`
+}
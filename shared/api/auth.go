@@ -6,4 +6,20 @@ const (
 
 	// AuthenticationMethodOIDC is a token based authentication method.
 	AuthenticationMethodOIDC = "oidc"
+
+	// AuthenticationMethodExternal is a username and password authentication method validated by an external command.
+	//
+	// API extension: authentication_external.
+	AuthenticationMethodExternal = "external"
+
+	// AuthenticationMethodWorkload is a token based authentication method for instances, with tokens issued over /dev/incus.
+	//
+	// API extension: instance_workload_tokens.
+	AuthenticationMethodWorkload = "workload"
+
+	// AuthenticationMethodAccessLink is a token based authentication method restricted to the console and exec
+	// of a single instance, with tokens issued as instance access links.
+	//
+	// API extension: instance_access_links.
+	AuthenticationMethodAccessLink = "access-link"
 )
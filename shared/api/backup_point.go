@@ -0,0 +1,47 @@
+package api
+
+import (
+	"time"
+)
+
+// BackupPoint represents a restorable point of an instance stored in the backup target
+//
+// swagger:model
+//
+// API extension: backup_target.
+type BackupPoint struct {
+	// Identifier of the point in the backup target
+	// Example: 4bba301e1b62dcf7c5e5d84e4b3a6bf2a2e1a1e5b4c3d2e1f0a9b8c7d6e5f4a3
+	ID string `json:"id" yaml:"id"`
+
+	// Backup name
+	// Example: backup0
+	Name string `json:"name" yaml:"name"`
+
+	// Name of the backed up instance
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Project of the backed up instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// When the backup was created
+	// Example: 2021-03-23T16:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// BackupPointPost represents the fields available to restore an instance from a backup point
+//
+// swagger:model
+//
+// API extension: backup_target.
+type BackupPointPost struct {
+	// Name of the new instance (defaults to the name of the backed up instance)
+	// Example: c1-restored
+	Name string `json:"name" yaml:"name"`
+
+	// Storage pool to restore the instance to (defaults to the pool of the backed up instance)
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+}
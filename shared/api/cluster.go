@@ -222,6 +222,12 @@ type ClusterMemberPut struct {
 	//
 	// API extension: clustering_groups
 	Groups []string `json:"groups" yaml:"groups"`
+
+	// Inventory labels of the cluster member
+	// Example: {"rack": "r12", "power-feed": "b"}
+	//
+	// API extension: clustering_labels
+	Labels map[string]string `json:"labels" yaml:"labels"`
 }
 
 // ClusterCertificatePut represents the certificate and key pair for all cluster members
@@ -254,6 +260,28 @@ type ClusterMemberStatePost struct {
 	//
 	// API extension: clustering_evacuate_mode
 	Mode string `json:"mode" yaml:"mode"`
+
+	// Name of the snapshot taken of all instances of the member before evacuation,
+	// or deleted from them once restored
+	// Example: maintenance
+	//
+	// API extension: clustering_member_snapshots
+	Snapshot string `json:"snapshot" yaml:"snapshot"`
+}
+
+// ClusterMemberSnapshotsPost represents the fields required to snapshot all instances of a cluster member.
+//
+// swagger:model
+//
+// API extension: clustering_member_snapshots.
+type ClusterMemberSnapshotsPost struct {
+	// Name of the snapshot to create on every instance (generated if empty)
+	// Example: maintenance
+	Name string `json:"name" yaml:"name"`
+
+	// When the snapshots expire (gets auto-deleted)
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ExpiresAt *time.Time `json:"expires_at" yaml:"expires_at"`
 }
 
 // ClusterGroupsPost represents the fields available for a new cluster group.
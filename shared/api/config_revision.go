@@ -0,0 +1,32 @@
+package api
+
+import (
+	"time"
+)
+
+// ConfigRevision represents a recorded previous configuration of a profile, project, network or network ACL.
+//
+// swagger:model
+//
+// API extension: config_revisions.
+type ConfigRevision struct {
+	// Revision number
+	// Example: 3
+	Revision int64 `json:"revision" yaml:"revision"`
+
+	// When the configuration was changed
+	// Example: 2021-03-23T20:00:00-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Who changed the configuration
+	// Example: unix/root
+	Requestor string `json:"requestor" yaml:"requestor"`
+
+	// Fields which were changed
+	// Example: ["config.limits.cpu", "devices.eth0"]
+	Changes []string `json:"changes" yaml:"changes"`
+
+	// Configuration before the change (the same fields as when updating the entity)
+	// Example: {"config": {"limits.cpu": "4"}, "description": "Default profile", "devices": {}}
+	Document map[string]any `json:"document" yaml:"document"`
+}
@@ -12,6 +12,16 @@ const (
 	EventTypeLogging    = "logging"
 	EventTypeOperation  = "operation"
 	EventTypeNetworkACL = "network-acl"
+	EventTypeStorage    = "storage"
+)
+
+// Storage event actions.
+const (
+	EventStorageActionVolumeCreated          = "volume-created"
+	EventStorageActionVolumeResized          = "volume-resized"
+	EventStorageActionVolumeSnapshotCreated  = "volume-snapshot-created"
+	EventStorageActionPoolThresholdExceeded  = "pool-threshold-exceeded"
+	EventStorageActionPoolThresholdRecovered = "pool-threshold-recovered"
 )
 
 // Event represents an event entry (over websocket)
@@ -26,7 +36,7 @@ type Event struct {
 	// Example: 2021-02-24T19:00:45.452649098-05:00
 	Timestamp time.Time `yaml:"timestamp" json:"timestamp"`
 
-	// JSON encoded metadata (see EventLogging, EventLifecycle or Operation)
+	// JSON encoded metadata (see EventLogging, EventLifecycle, EventStorage or Operation)
 	// Example: {"action": "instance-started", "source": "/1.0/instances/c1", "context": {}}
 	Metadata json.RawMessage `yaml:"metadata" json:"metadata"`
 
@@ -96,6 +106,37 @@ func (event *Event) ToLogging() (EventLogRecord, error) {
 
 		return record, nil
 
+	case EventTypeStorage:
+		e := &EventStorage{}
+		err := json.Unmarshal(event.Metadata, &e)
+		if err != nil {
+			return EventLogRecord{}, err
+		}
+
+		ctx := []any{}
+		for k, v := range e.Context {
+			ctx = append(ctx, k)
+			ctx = append(ctx, v)
+		}
+
+		record := EventLogRecord{
+			Time: event.Timestamp,
+			Lvl:  "info",
+			Ctx:  ctx,
+		}
+
+		if e.Action == EventStorageActionPoolThresholdExceeded {
+			record.Lvl = "warning"
+		}
+
+		if e.Volume != "" {
+			record.Msg = fmt.Sprintf("Action: %s, Pool: %s, Volume: %s/%s", e.Action, e.Pool, e.VolumeType, e.Volume)
+		} else {
+			record.Msg = fmt.Sprintf("Action: %s, Pool: %s", e.Action, e.Pool)
+		}
+
+		return record, nil
+
 	case EventTypeOperation:
 		e := &Operation{}
 		err := json.Unmarshal(event.Metadata, &e)
@@ -157,6 +198,35 @@ type EventLifecycle struct {
 	Project string `yaml:"project,omitempty" json:"project,omitempty"`
 }
 
+// EventStorage represents a storage type event entry.
+//
+// API extension: storage_events.
+type EventStorage struct {
+	// Action which happened
+	// Example: volume-resized
+	Action string `yaml:"action" json:"action"`
+
+	// Name of the storage pool
+	// Example: default
+	Pool string `yaml:"pool" json:"pool"`
+
+	// Name of the storage volume (empty for storage pool events)
+	// Example: data
+	Volume string `yaml:"volume,omitempty" json:"volume,omitempty"`
+
+	// Type of the storage volume (empty for storage pool events)
+	// Example: custom
+	VolumeType string `yaml:"volume_type,omitempty" json:"volume_type,omitempty"`
+
+	// Project of the storage volume (empty for storage pool events)
+	// Example: default
+	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+
+	// Additional details about the event
+	// Example: {"size": 10737418240}
+	Context map[string]any `yaml:"context,omitempty" json:"context,omitempty"`
+}
+
 // EventLifecycleRequestor represents the initial requestor for an event
 //
 // API extension: event_lifecycle_requestor.
@@ -31,6 +31,8 @@ const (
 	EventLifecycleImageRetrieved                    = "image-retrieved"
 	EventLifecycleImageSecretCreated                = "image-secret-created"
 	EventLifecycleImageUpdated                      = "image-updated"
+	EventLifecycleInstanceAccessLinkCreated         = "instance-access-link-created"
+	EventLifecycleInstanceAccessLinkDeleted         = "instance-access-link-deleted"
 	EventLifecycleInstanceBackupCreated             = "instance-backup-created"
 	EventLifecycleInstanceBackupDeleted             = "instance-backup-deleted"
 	EventLifecycleInstanceBackupRenamed             = "instance-backup-renamed"
@@ -53,6 +55,7 @@ const (
 	EventLifecycleInstanceMetadataUpdated           = "instance-metadata-updated"
 	EventLifecycleInstanceMigrated                  = "instance-migrated"
 	EventLifecycleInstancePaused                    = "instance-paused"
+	EventLifecycleInstanceQMPCommand                = "instance-qmp-command"
 	EventLifecycleInstanceReady                     = "instance-ready"
 	EventLifecycleInstanceRenamed                   = "instance-renamed"
 	EventLifecycleInstanceRestarted                 = "instance-restarted"
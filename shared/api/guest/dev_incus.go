@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // DevIncusPut represents the modifiable data.
 type DevIncusPut struct {
 	// Instance state
@@ -23,3 +27,54 @@ type DevIncusGet struct {
 	// Example: server01
 	Location string `json:"location" yaml:"location"`
 }
+
+// DevIncusToken represents an API token issued to the instance.
+//
+// API extension: instance_workload_tokens.
+type DevIncusToken struct {
+	// Bearer token for the main API, restricted to the instance
+	// Example: eyJhbGciOiJFUzM4NCIsInR5cCI6IkpXVCJ9...
+	Token string `json:"token" yaml:"token"`
+
+	// When the token expires
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+
+	// Addresses of the main API
+	// Example: ["10.0.0.1:8443"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+
+	// Certificate of the main API (PEM encoded)
+	// Example: X509 PEM certificate
+	Certificate string `json:"certificate" yaml:"certificate"`
+}
+
+// DevIncusSnapshotsPost represents a snapshot request of the instance, sent once it flushed its data.
+//
+// API extension: instance_guest_snapshots.
+type DevIncusSnapshotsPost struct {
+	// Name of the disk device whose volume is snapshotted (the root disk snapshots the instance)
+	// Example: root
+	Device string `json:"device" yaml:"device"`
+
+	// Snapshot name (defaults to the snapshot pattern)
+	// Example: backup0
+	Name string `json:"name" yaml:"name"`
+
+	// When the snapshot expires (defaults to the snapshot expiry)
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ExpiresAt *time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// DevIncusSnapshot represents a snapshot taken at the request of the instance.
+//
+// API extension: instance_guest_snapshots.
+type DevIncusSnapshot struct {
+	// Name of the disk device whose volume was snapshotted
+	// Example: root
+	Device string `json:"device" yaml:"device"`
+
+	// Snapshot name
+	// Example: backup0
+	Name string `json:"name" yaml:"name"`
+}
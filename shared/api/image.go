@@ -68,6 +68,17 @@ type ImagesPost struct {
 	//
 	// API extension: image_create_aliases
 	Aliases []ImageAlias `json:"aliases" yaml:"aliases"`
+
+	// Build provenance to attach to an image created from an instance
+	//
+	// API extension: image_sbom
+	Provenance *ImageProvenance `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+
+	// SBOM document (in JSON) to attach to an image created from an instance
+	// Example: {"spdxVersion": "SPDX-2.3", "packages": []}
+	//
+	// API extension: image_sbom
+	SBOM string `json:"sbom,omitempty" yaml:"sbom,omitempty"`
 }
 
 // ImagesPostSource represents the source of a new image
@@ -191,6 +202,17 @@ type Image struct {
 	//
 	// API extension: images_all_projects
 	Project string `json:"project" yaml:"project"`
+
+	// Build provenance of the image
+	//
+	// API extension: image_sbom
+	Provenance *ImageProvenance `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+
+	// Whether an SBOM document is attached to the image
+	// Example: true
+	//
+	// API extension: image_sbom
+	SBOM bool `json:"sbom" yaml:"sbom"`
 }
 
 // Writable converts a full Image struct into a ImagePut struct (filters read-only fields).
@@ -203,6 +225,21 @@ func (img *Image) URL(apiVersion string, project string) *URL {
 	return NewURL().Path(apiVersion, "images", img.Fingerprint).Project(project)
 }
 
+// ImageProvenance represents the build provenance of an image
+//
+// swagger:model
+//
+// API extension: image_sbom.
+type ImageProvenance struct {
+	// Name of the builder of the image
+	// Example: ci.example.com/pipelines/42
+	Builder string `json:"builder" yaml:"builder"`
+
+	// Hash of the source the image was built from
+	// Example: 3f786850e387550fdab836ed7e6dc881de23001b
+	SourceHash string `json:"source_hash" yaml:"source_hash"`
+}
+
 // ImageAlias represents an alias from the alias list of an image
 //
 // swagger:model
@@ -214,6 +251,12 @@ type ImageAlias struct {
 	// Description of the alias
 	// Example: Our preferred Ubuntu image
 	Description string `json:"description" yaml:"description"`
+
+	// Whether the image is an architecture variant of the alias rather than its target
+	// Example: false
+	//
+	// API extension: image_alias_architectures
+	Variant bool `json:"variant,omitempty" yaml:"variant,omitempty"`
 }
 
 // ImageSource represents the source of an image
@@ -270,6 +313,12 @@ type ImageAliasesEntryPut struct {
 	// Target fingerprint for the alias
 	// Example: 06b86454720d36b20f94e31c6812e05ec51c1b568cf3a8abd273769d213394bb
 	Target string `json:"target" yaml:"target"`
+
+	// Target fingerprints for each architecture, the target being used for the architectures without a variant
+	// Example: {"aarch64": "a8f0b7a3c4c1c2fe349a0bca3e0bbd3a5c03c3fc2d8f2b39a5c77a4fd4b8d11c"}
+	//
+	// API extension: image_alias_architectures
+	Architectures map[string]string `json:"architectures,omitempty" yaml:"architectures,omitempty"`
 }
 
 // ImageAliasesEntry represents an image alias
@@ -311,6 +360,17 @@ type ImageMetadata struct {
 
 	// Template for files in the image
 	Templates map[string]*ImageMetadataTemplate `json:"templates" yaml:"templates"`
+
+	// Build provenance of the image
+	//
+	// API extension: image_sbom
+	Provenance *ImageProvenance `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+
+	// SBOM document of the image (in JSON)
+	// Example: {"spdxVersion": "SPDX-2.3", "packages": []}
+	//
+	// API extension: image_sbom
+	SBOM string `json:"sbom,omitempty" yaml:"sbom,omitempty"`
 }
 
 // ImageMetadataTemplate represents a template entry in image metadata (used in image tarball)
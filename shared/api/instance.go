@@ -127,6 +127,23 @@ type InstancePost struct {
 	//
 	// API extension: instance_move_config
 	Profiles []string
+
+	// Whether to stop, move and start the instance again if its live migration fails (migration only)
+	// Example: false
+	//
+	// API extension: instance_live_fallback
+	LiveFallback bool `json:"live_fallback" yaml:"live_fallback"`
+
+	// Whether to compare the checksums of the volumes once transferred (migration only)
+	// Example: false
+	//
+	// API extension: migration_verify
+	Verify bool `json:"verify,omitempty" yaml:"verify,omitempty"`
+
+	// Timeouts of the migration (migration only)
+	//
+	// API extension: migration_timeouts
+	Timeouts *MigrationTimeouts `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
 }
 
 // InstancePostTarget represents the migration target host and operation.
@@ -148,6 +165,134 @@ type InstancePostTarget struct {
 	Websockets map[string]string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
 }
 
+// Status values of the migration pre-flight checks.
+const (
+	InstanceMigrationCheckOK      = "ok"
+	InstanceMigrationCheckWarning = "warning"
+	InstanceMigrationCheckError   = "error"
+)
+
+// InstanceMigrationCheck represents the report of a migration pre-flight check.
+//
+// swagger:model
+//
+// API extension: instance_migration_check.
+type InstanceMigrationCheck struct {
+	// Whether the migration is expected to succeed
+	// Example: true
+	Ready bool `json:"ready" yaml:"ready"`
+
+	// Results of the individual checks
+	Checks []InstanceMigrationCheckResult `json:"checks" yaml:"checks"`
+}
+
+// Add records the result of a check, the report is no longer ready if the check failed.
+func (c *InstanceMigrationCheck) Add(name string, status string, message string) {
+	c.Checks = append(c.Checks, InstanceMigrationCheckResult{Name: name, Status: status, Message: message})
+
+	if status == InstanceMigrationCheckError {
+		c.Ready = false
+	}
+}
+
+// InstanceMigrationCheckResult represents the result of a single migration pre-flight check.
+//
+// swagger:model
+//
+// API extension: instance_migration_check.
+type InstanceMigrationCheckResult struct {
+	// Name of the check
+	// Example: cpu
+	Name string `json:"name" yaml:"name"`
+
+	// Status of the check (ok, warning or error)
+	// Example: ok
+	Status string `json:"status" yaml:"status"`
+
+	// Details about the result
+	// Example: The target supports all the CPU flags of the source
+	Message string `json:"message" yaml:"message"`
+}
+
+// Phases of an instance migration.
+const (
+	InstanceMigrationPhaseVolumes = "volumes"
+	InstanceMigrationPhaseMemory  = "memory"
+	InstanceMigrationPhaseFinal   = "final"
+)
+
+// InstanceMigrationProgress represents the progress of an instance migration.
+// It's exposed through the "migration" key of the metadata of the migration operation.
+//
+// swagger:model
+//
+// API extension: instance_migration_progress.
+type InstanceMigrationProgress struct {
+	// Current phase of the migration (volumes, memory or final)
+	// Example: memory
+	Phase string `json:"phase" yaml:"phase"`
+
+	// Progress of the volume transfers, indexed by volume name
+	Volumes map[string]InstanceMigrationVolumeProgress `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// Progress of the memory transfer of live migrations
+	Memory *InstanceMigrationMemoryProgress `json:"memory,omitempty" yaml:"memory,omitempty"`
+}
+
+// InstanceMigrationVolumeProgress represents the transfer progress of a single volume.
+//
+// swagger:model
+//
+// API extension: instance_migration_progress.
+type InstanceMigrationVolumeProgress struct {
+	// Number of bytes transferred so far
+	// Example: 1073741824
+	Bytes int64 `json:"bytes" yaml:"bytes"`
+
+	// Current transfer speed in bytes per second
+	// Example: 104857600
+	Speed int64 `json:"speed" yaml:"speed"`
+
+	// Number of files transferred so far (only for rsync transfers)
+	// Example: 1520
+	Files int64 `json:"files,omitempty" yaml:"files,omitempty"`
+}
+
+// InstanceMigrationMemoryProgress represents the memory transfer progress of a live migration.
+//
+// swagger:model
+//
+// API extension: instance_migration_progress.
+type InstanceMigrationMemoryProgress struct {
+	// Current iteration over the memory (CRIU pre-dump or QEMU dirty page sync)
+	// Example: 3
+	Iteration int64 `json:"iteration" yaml:"iteration"`
+
+	// Number of bytes of memory transferred so far
+	// Example: 536870912
+	Transferred int64 `json:"transferred" yaml:"transferred"`
+
+	// Number of bytes of memory still to be transferred (only for virtual machines)
+	// Example: 16777216
+	Remaining int64 `json:"remaining,omitempty" yaml:"remaining,omitempty"`
+
+	// Total amount of memory in bytes
+	// Example: 2147483648
+	Total int64 `json:"total" yaml:"total"`
+
+	// Number of memory pages written in the last iteration (only for containers)
+	// Example: 4096
+	PagesWritten int64 `json:"pages_written,omitempty" yaml:"pages_written,omitempty"`
+
+	// Number of memory pages unchanged since the previous iteration (only for containers)
+	// Example: 126976
+	PagesSkipped int64 `json:"pages_skipped,omitempty" yaml:"pages_skipped,omitempty"`
+
+	// Rate at which the guest dirties memory pages, in pages per second
+	// Example: 1200
+	DirtyRate int64 `json:"dirty_rate,omitempty" yaml:"dirty_rate,omitempty"`
+}
+
 // InstancePut represents the modifiable fields of an instance.
 //
 // swagger:model
@@ -195,6 +340,12 @@ type InstancePut struct {
 type InstanceRebuildPost struct {
 	// Rebuild source
 	Source InstanceSource `json:"source" yaml:"source"`
+
+	// Whether to rebuild from the latest image of the alias the instance was built from (instead of a source)
+	// Example: false
+	//
+	// API extension: instance_rebuild_latest_image
+	LatestImage bool `json:"latest_image,omitempty" yaml:"latest_image,omitempty"`
 }
 
 // Instance represents an instance.
@@ -377,4 +528,21 @@ type InstanceSource struct {
 	//
 	// API extension: instance_allow_inconsistent_copy
 	AllowInconsistent bool `json:"allow_inconsistent" yaml:"allow_inconsistent"`
+
+	// Whether to compare the checksums of the volumes once transferred (for migration)
+	// Example: false
+	//
+	// API extension: migration_verify
+	Verify bool `json:"verify,omitempty" yaml:"verify,omitempty"`
+
+	// Timeouts of the migration (for migration)
+	//
+	// API extension: migration_timeouts
+	Timeouts *MigrationTimeouts `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+
+	// Whether the copy must be an instant copy-on-write clone of the source (for copy)
+	// Example: false
+	//
+	// API extension: instance_copy_instant
+	Instant bool `json:"instant,omitempty" yaml:"instant,omitempty"`
 }
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"time"
+)
+
+const (
+	// InstanceAccessLinkAccessConsole allows attaching to the console of the instance.
+	//
+	// API extension: instance_access_links.
+	InstanceAccessLinkAccessConsole = "console"
+
+	// InstanceAccessLinkAccessExec allows executing commands in the instance.
+	//
+	// API extension: instance_access_links.
+	InstanceAccessLinkAccessExec = "exec"
+)
+
+// InstanceAccessLinksPost represents the fields available for a new instance access link.
+//
+// swagger:model
+//
+// API extension: instance_access_links.
+type InstanceAccessLinksPost struct {
+	// Description of the access link
+	// Example: Debugging of the boot issue
+	Description string `json:"description" yaml:"description"`
+
+	// What the access link allows (console and/or exec), defaults to console
+	// Example: ["console", "exec"]
+	Access []string `json:"access" yaml:"access"`
+
+	// When the access link expires, defaults to one hour
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// InstanceAccessLink represents an instance access link.
+//
+// swagger:model
+//
+// API extension: instance_access_links.
+type InstanceAccessLink struct {
+	// Access link identifier
+	// Example: 8c9bd4a8-2ab5-4bcd-8273-0b8c3f2a3c3e
+	ID string `json:"id" yaml:"id"`
+
+	// Description of the access link
+	// Example: Debugging of the boot issue
+	Description string `json:"description" yaml:"description"`
+
+	// What the access link allows (console and/or exec)
+	// Example: ["console", "exec"]
+	Access []string `json:"access" yaml:"access"`
+
+	// Who created the access link
+	// Example: tls/3f1a2b...
+	CreatedBy string `json:"created_by" yaml:"created_by"`
+
+	// When the access link was created
+	// Example: 2021-03-23T16:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// When the access link expires
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+
+	// When the access link was last used (zero if never used)
+	// Example: 2021-03-23T16:48:37.753398689-04:00
+	LastUsedAt time.Time `json:"last_used_at" yaml:"last_used_at"`
+}
+
+// InstanceAccessLinkToken represents the token of a new instance access link.
+// The secret is only returned when creating the access link.
+//
+// swagger:model
+//
+// API extension: instance_access_links.
+type InstanceAccessLinkToken struct {
+	// Access link identifier
+	// Example: 8c9bd4a8-2ab5-4bcd-8273-0b8c3f2a3c3e
+	ID string `json:"id" yaml:"id"`
+
+	// Address of the server to connect to
+	// Example: https://10.0.0.1:8443
+	URL string `json:"url" yaml:"url"`
+
+	// Fingerprint of the server certificate
+	// Example: 2c5829b6c6ba19ef5ded3e3a4ea1f4af47d9e1187e14c07ff772fdd1e9e4c1a8
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+
+	// Secret to authenticate with (sent as a bearer token)
+	// Example: 8c9bd4a8-2ab5-4bcd-8273-0b8c3f2a3c3e.4f1e...
+	Secret string `json:"secret" yaml:"secret"`
+
+	// When the access link expires
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
@@ -31,6 +31,12 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_compression_algorithm
 	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
+
+	// Whether to store the backup in the backup target of the server instead of as a tarball
+	// Example: false
+	//
+	// API extension: backup_target
+	Target bool `json:"target" yaml:"target"`
 }
 
 // InstanceBackup represents an instance backup.
@@ -33,4 +33,10 @@ type InstanceConsolePost struct {
 	//
 	// API extension: console_force
 	Force bool `json:"force" yaml:"force"`
+
+	// Name of the additional serial console to attach to (console type and virtual machines only)
+	// Example: admin
+	//
+	// API extension: instance_console_serials
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 }
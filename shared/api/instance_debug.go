@@ -0,0 +1,106 @@
+package api
+
+// InstanceDebugQMPPost represents a QMP command sent to a virtual machine.
+//
+// swagger:model
+//
+// API extension: instance_debug_qmp.
+type InstanceDebugQMPPost struct {
+	// Name of the QMP command
+	// Example: query-status
+	Command string `json:"command" yaml:"command"`
+
+	// Arguments of the QMP command
+	// Example: {"id": "dev-incus_eth0"}
+	Arguments map[string]any `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+}
+
+// InstanceDebugQMP represents the result of a QMP command.
+//
+// swagger:model
+//
+// API extension: instance_debug_qmp.
+type InstanceDebugQMP struct {
+	// Value returned by QEMU
+	// Example: {"running": true, "status": "running"}
+	Return any `json:"return" yaml:"return"`
+}
+
+// InstanceDebugLXCConfig represents the effective liblxc configuration of a container.
+//
+// swagger:model
+//
+// API extension: instance_debug_lxc_config.
+type InstanceDebugLXCConfig struct {
+	// Final liblxc configuration, including raw.lxc
+	// Example: lxc.uts.name = c1
+	Config string `json:"config" yaml:"config"`
+
+	// List of keys generated by Incus which raw.lxc overrides
+	Conflicts []InstanceDebugLXCConfigConflict `json:"conflicts" yaml:"conflicts"`
+}
+
+// InstanceDebugLXCConfigConflict represents a liblxc key set both by Incus and by raw.lxc.
+//
+// swagger:model
+//
+// API extension: instance_debug_lxc_config.
+type InstanceDebugLXCConfigConflict struct {
+	// Name of the liblxc key
+	// Example: lxc.apparmor.profile
+	Key string `json:"key" yaml:"key"`
+
+	// Value generated by Incus
+	// Example: incus-c1_</var/lib/incus>
+	Value string `json:"value" yaml:"value"`
+
+	// Value set in raw.lxc
+	// Example: unconfined
+	RawValue string `json:"raw_value" yaml:"raw_value"`
+}
+
+// InstanceDebugNetnsPost represents a command run on the host, joined only to the network namespace of a container.
+//
+// swagger:model
+//
+// API extension: instance_debug_netns.
+type InstanceDebugNetnsPost struct {
+	// Command and its arguments (defaults to a shell)
+	// Example: ["ss", "-tlnp"]
+	Command []string `json:"command" yaml:"command"`
+
+	// Additional environment to pass to the command
+	// Example: {"TERM": "xterm"}
+	Environment map[string]string `json:"environment" yaml:"environment"`
+
+	// Whether the command is to be spawned in interactive mode (singled PTY instead of 3 PIPEs)
+	// Example: true
+	Interactive bool `json:"interactive" yaml:"interactive"`
+
+	// Terminal width (characters)
+	// Example: 80
+	Width int `json:"width" yaml:"width"`
+
+	// Terminal height (rows)
+	// Example: 24
+	Height int `json:"height" yaml:"height"`
+
+	// Current working directory for the command on the host
+	// Example: /tmp
+	Cwd string `json:"cwd" yaml:"cwd"`
+}
+
+// InstanceDebugProfilePost represents a CPU profiling request of an instance.
+//
+// swagger:model
+//
+// API extension: instance_debug_profile.
+type InstanceDebugProfilePost struct {
+	// Duration of the profiling in seconds (defaults to 30)
+	// Example: 30
+	Duration int `json:"duration" yaml:"duration"`
+
+	// Sampling frequency in Hz (defaults to 99)
+	// Example: 99
+	Frequency int `json:"frequency" yaml:"frequency"`
+}
@@ -0,0 +1,23 @@
+package api
+
+// InstanceDeviceMediaPost represents a change of the media of a removable instance device.
+//
+// swagger:model
+//
+// API extension: instance_device_media.
+type InstanceDeviceMediaPost struct {
+	// Action to perform (eject, insert or toggle)
+	// Example: eject
+	Action string `json:"action" yaml:"action"`
+}
+
+// InstanceDeviceMedia represents the state of the media of a removable instance device.
+//
+// swagger:model
+//
+// API extension: instance_device_media.
+type InstanceDeviceMedia struct {
+	// Whether the media is inserted
+	// Example: true
+	Inserted bool `json:"inserted" yaml:"inserted"`
+}
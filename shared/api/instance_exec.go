@@ -54,3 +54,30 @@ type InstanceExecPost struct {
 	// Example: /home/foo/
 	Cwd string `json:"cwd" yaml:"cwd"`
 }
+
+// InstanceExecResult represents the result of a command run in synchronous mode.
+//
+// swagger:model
+//
+// API extension: instance_exec_sync.
+type InstanceExecResult struct {
+	// Exit status of the command
+	// Example: 0
+	Return int `json:"return" yaml:"return"`
+
+	// Standard output of the command
+	// Example: Hello world
+	Stdout string `json:"stdout" yaml:"stdout"`
+
+	// Standard error of the command
+	// Example: bash: foo: command not found
+	Stderr string `json:"stderr" yaml:"stderr"`
+
+	// Whether the standard output was truncated to the size limit
+	// Example: false
+	StdoutTruncated bool `json:"stdout_truncated" yaml:"stdout_truncated"`
+
+	// Whether the standard error was truncated to the size limit
+	// Example: false
+	StderrTruncated bool `json:"stderr_truncated" yaml:"stderr_truncated"`
+}
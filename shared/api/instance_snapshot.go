@@ -115,6 +115,18 @@ type InstanceSnapshot struct {
 	//
 	// API extension: snapshot_disk_usage
 	Size int64 `json:"size" yaml:"size"`
+
+	// Space used by the data of the snapshot in bytes (-1 if unknown)
+	// Example: 143360
+	//
+	// API extension: snapshot_usage_breakdown
+	Used int64 `json:"used" yaml:"used"`
+
+	// Space only used by the snapshot in bytes, freed when deleting it (-1 if unknown)
+	// Example: 40960
+	//
+	// API extension: snapshot_usage_breakdown
+	Unique int64 `json:"unique" yaml:"unique"`
 }
 
 // Writable converts a full InstanceSnapshot struct into a InstanceSnapshotPut struct
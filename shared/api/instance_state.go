@@ -89,6 +89,41 @@ type InstanceStateDisk struct {
 	Total int64 `json:"total" yaml:"total"`
 }
 
+// InstanceDiskUsage represents the breakdown of the disk space used by an instance.
+//
+// swagger:model
+//
+// API extension: instance_disk_usage.
+type InstanceDiskUsage struct {
+	// Disk usage of the root volume
+	Root InstanceDiskUsageVolume `json:"root" yaml:"root"`
+
+	// Disk space unique to each snapshot, indexed by snapshot name
+	Snapshots map[string]InstanceDiskUsageVolume `json:"snapshots" yaml:"snapshots"`
+
+	// Disk usage of the attached custom volumes, indexed by device name
+	Volumes map[string]InstanceDiskUsageVolume `json:"volumes" yaml:"volumes"`
+}
+
+// InstanceDiskUsageVolume represents the disk usage of a single volume in the breakdown of an instance.
+//
+// swagger:model
+//
+// API extension: instance_disk_usage.
+type InstanceDiskUsageVolume struct {
+	// Name of the storage pool
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Name of the storage volume
+	// Example: c1
+	Volume string `json:"volume" yaml:"volume"`
+
+	// Disk usage in bytes (-1 if the storage driver can't report it)
+	// Example: 502239232
+	Usage int64 `json:"usage" yaml:"usage"`
+}
+
 // InstanceStateCPU represents the cpu information section of an instance's state.
 //
 // swagger:model
@@ -1,5 +1,10 @@
 package api
 
+import (
+	"fmt"
+	"time"
+)
+
 // SecretNameControl is the secret name used for the migration control connection.
 const SecretNameControl = "control"
 
@@ -8,3 +13,147 @@ const SecretNameFilesystem = "fs"
 
 // SecretNameState is the secret name used for the migration state connection.
 const SecretNameState = "criu" // Legacy value used for backward compatibility for clients.
+
+// SecretNameFilesystemStream returns the secret name used for an additional parallel migration filesystem
+// connection (starting at 1).
+//
+// API extension: migration_parallel_streams.
+func SecretNameFilesystemStream(index int) string {
+	return fmt.Sprintf("%s%d", SecretNameFilesystem, index)
+}
+
+// Status values of migration statistics.
+const (
+	MigrationStatisticsStatusRunning = "running"
+	MigrationStatisticsStatusSuccess = "success"
+	MigrationStatisticsStatusFailure = "failure"
+
+	// API extension: migration_abort.
+	MigrationStatisticsStatusCancelled = "cancelled"
+)
+
+// MigrationStatistics represents the statistics of a migration sent by a server.
+//
+// swagger:model
+//
+// API extension: migration_statistics.
+type MigrationStatistics struct {
+	// ID of the migration operation
+	// Example: 6916c8a6-9b7d-4abd-90b3-aedfec7ec7da
+	Operation string `json:"operation" yaml:"operation"`
+
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Type of the instance (container or virtual-machine)
+	// Example: container
+	InstanceType string `json:"instance_type" yaml:"instance_type"`
+
+	// Cluster member which sent the migration
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// Whether the migration was live
+	// Example: false
+	Live bool `json:"live" yaml:"live"`
+
+	// Negotiated storage transfer type
+	// Example: zfs
+	TransferType string `json:"transfer_type" yaml:"transfer_type"`
+
+	// Status of the migration (running, success, failure or cancelled)
+	// Example: success
+	Status string `json:"status" yaml:"status"`
+
+	// Error of failed migrations
+	// Example: Failed migration on source: Connection reset by peer
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// When the migration started
+	// Example: 2021-03-23T20:00:00-04:00
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// When the migration ended
+	// Example: 2021-03-23T20:05:00-04:00
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+
+	// Duration of the migration in milliseconds
+	// Example: 300000
+	Duration int64 `json:"duration" yaml:"duration"`
+
+	// Time in milliseconds during which a live migrated instance was paused
+	// Example: 250
+	Downtime int64 `json:"downtime" yaml:"downtime"`
+
+	// Number of bytes transferred (volumes and memory)
+	// Example: 10737418240
+	Bytes int64 `json:"bytes" yaml:"bytes"`
+
+	// Average throughput in bytes per second
+	// Example: 35791394
+	Throughput int64 `json:"throughput" yaml:"throughput"`
+
+	// Number of failed attempts at migrating the instance from this server right before this one
+	// Example: 1
+	Retries int `json:"retries" yaml:"retries"`
+}
+
+// MigrationTimeouts represents the timeouts of a migration, overriding the server configuration.
+//
+// swagger:model
+//
+// API extension: migration_timeouts.
+type MigrationTimeouts struct {
+	// Seconds to wait for a message to be sent on the control connection (0 to use the server configuration)
+	// Example: 120
+	Control int `json:"control,omitempty" yaml:"control,omitempty"`
+
+	// Seconds between the pings sent on the control connection (-1 to disable)
+	// Example: 15
+	Keepalive int `json:"keepalive,omitempty" yaml:"keepalive,omitempty"`
+
+	// Maximum duration of the migration in seconds (-1 for no limit)
+	// Example: 3600
+	Total int `json:"total,omitempty" yaml:"total,omitempty"`
+}
+
+// MigrationRelayPost represents a request for a server to relay the connections of a migration.
+//
+// swagger:model
+//
+// API extension: migration_relay.
+type MigrationRelayPost struct {
+	// The source of the migration
+	Source MigrationRelayEndpoint `json:"source" yaml:"source"`
+
+	// The target of the migration
+	Target MigrationRelayEndpoint `json:"target" yaml:"target"`
+}
+
+// MigrationRelayEndpoint represents one of the servers taking part in a relayed migration.
+//
+// swagger:model
+//
+// API extension: migration_relay.
+type MigrationRelayEndpoint struct {
+	// Addresses at which the server can be reached
+	// Example: ["https://10.0.0.1:8443"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+
+	// Certificate of the server
+	// Example: X509 PEM certificate
+	Certificate string `json:"certificate" yaml:"certificate"`
+
+	// ID of the migration operation on the server
+	// Example: 6916c8a6-9b7d-4abd-90b3-aedfec7ec7da
+	Operation string `json:"operation" yaml:"operation"`
+
+	// Map of migration websockets (name to secret)
+	// Example: {"control": "random-string", "fs": "random-string"}
+	Websockets map[string]string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+}
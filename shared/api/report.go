@@ -0,0 +1,63 @@
+package api
+
+import (
+	"time"
+)
+
+// Report types.
+const (
+	ReportTypeCapacity  = "capacity"
+	ReportTypeInventory = "inventory"
+)
+
+// Report represents a capacity or inventory report of the server
+//
+// swagger:model
+//
+// API extension: reports.
+type Report struct {
+	// Report name
+	// Example: capacity-20261014-120000
+	Name string `json:"name" yaml:"name"`
+
+	// Report type (capacity or inventory)
+	// Example: capacity
+	Type string `json:"type" yaml:"type"`
+
+	// When the report was generated
+	// Example: 2021-03-23T16:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Tables of the report
+	Sections []ReportSection `json:"sections" yaml:"sections"`
+}
+
+// ReportSection represents a table of a report
+//
+// swagger:model
+//
+// API extension: reports.
+type ReportSection struct {
+	// Section name
+	// Example: storage_pools
+	Name string `json:"name" yaml:"name"`
+
+	// Names of the columns
+	// Example: ["pool", "member", "driver", "space_used", "space_total"]
+	Columns []string `json:"columns" yaml:"columns"`
+
+	// Rows of values, in the order of the columns
+	// Example: [["default", "server01", "zfs", "2147483648", "107374182400"]]
+	Rows [][]string `json:"rows" yaml:"rows"`
+}
+
+// ReportsPost represents the fields available to generate a report
+//
+// swagger:model
+//
+// API extension: reports.
+type ReportsPost struct {
+	// Report type (capacity or inventory)
+	// Example: inventory
+	Type string `json:"type" yaml:"type"`
+}
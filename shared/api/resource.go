@@ -786,6 +786,59 @@ type ResourcesStoragePool struct {
 
 	// DIsk inode usage
 	Inodes ResourcesStoragePoolInodes `json:"inodes,omitempty" yaml:"inodes,omitempty"`
+
+	// Deduplication and compression statistics
+	//
+	// API extension: storage_pool_statistics
+	Statistics *StoragePoolStatistics `json:"statistics,omitempty" yaml:"statistics,omitempty"`
+
+	// Cache device usage and statistics
+	//
+	// API extension: storage_pool_cache
+	Cache *ResourcesStoragePoolCache `json:"cache,omitempty" yaml:"cache,omitempty"`
+}
+
+// ResourcesStoragePoolCache represents the cache device of a given storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_cache.
+type ResourcesStoragePoolCache struct {
+	// Cache device
+	// Example: /dev/nvme0n1
+	Device string `json:"device" yaml:"device"`
+
+	// Cache mode
+	// Example: writeback
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Used cache space (bytes)
+	// Example: 85899345920
+	Used uint64 `json:"used" yaml:"used"`
+
+	// Total cache space (bytes)
+	// Example: 238370684928
+	Total uint64 `json:"total" yaml:"total"`
+
+	// Cached data not yet written back to the storage pool (bytes)
+	// Example: 1073741824
+	Dirty uint64 `json:"dirty" yaml:"dirty"`
+
+	// Number of reads served from the cache
+	// Example: 1048576
+	ReadHits uint64 `json:"read_hits" yaml:"read_hits"`
+
+	// Number of reads not served from the cache
+	// Example: 65536
+	ReadMisses uint64 `json:"read_misses" yaml:"read_misses"`
+
+	// Number of writes to already cached blocks
+	// Example: 524288
+	WriteHits uint64 `json:"write_hits" yaml:"write_hits"`
+
+	// Number of writes to blocks not yet cached
+	// Example: 32768
+	WriteMisses uint64 `json:"write_misses" yaml:"write_misses"`
 }
 
 // ResourcesStoragePoolSpace represents the space available to a given storage pool
@@ -74,6 +74,12 @@ type ServerEnvironment struct {
 	// API extension: projects
 	Project string `json:"project" yaml:"project"`
 
+	// Whether the server runs as an unprivileged user
+	// Example: false
+	//
+	// API extension: server_rootless
+	Rootless bool `json:"rootless" yaml:"rootless"`
+
 	// Server implementation name
 	// Example: incus
 	Server string `json:"server" yaml:"server"`
@@ -27,7 +27,7 @@ type StoragePoolsPost struct {
 	// Example: local
 	Name string `json:"name" yaml:"name"`
 
-	// Storage pool driver (btrfs, ceph, cephfs, cephobject, dir, lvm, lvmcluster or zfs)
+	// Storage pool driver (btrfs, ceph, cephfs, cephobject, dir, iscsi, linstor, lvm, lvmcluster, nfs, raw or zfs)
 	// Example: zfs
 	Driver string `json:"driver" yaml:"driver"`
 }
@@ -44,7 +44,7 @@ type StoragePool struct {
 	// Example: local
 	Name string `json:"name" yaml:"name"`
 
-	// Storage pool driver (btrfs, ceph, cephfs, cephobject, dir, lvm, lvmcluster or zfs)
+	// Storage pool driver (btrfs, ceph, cephfs, cephobject, dir, iscsi, linstor, lvm, lvmcluster, nfs, raw or zfs)
 	// Example: zfs
 	Driver string `json:"driver" yaml:"driver"`
 
@@ -65,6 +65,12 @@ type StoragePool struct {
 	//
 	// API extension: clustering
 	Locations []string `json:"locations" yaml:"locations"`
+
+	// Result of the last health check of the pool on the server
+	// Read only: true
+	//
+	// API extension: storage_pool_health
+	Health *StoragePoolHealth `json:"health,omitempty" yaml:"health,omitempty"`
 }
 
 // StoragePoolPut represents the modifiable fields of a storage pool.
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"time"
+)
+
+// StoragePoolHealthHealthy storage pool didn't report any issue.
+const StoragePoolHealthHealthy = "Healthy"
+
+// StoragePoolHealthDegraded storage pool reported issues.
+const StoragePoolHealthDegraded = "Degraded"
+
+// StoragePoolHealth represents the result of the last health check of a storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_health.
+type StoragePoolHealth struct {
+	// Health status (Healthy or Degraded)
+	// Example: Degraded
+	Status string `json:"status" yaml:"status"`
+
+	// Issues reported by the storage backend
+	// Example: ["Device /dev/sdb is FAULTED"]
+	Messages []string `json:"messages" yaml:"messages"`
+
+	// Status of the last scrub, when supported by the storage backend
+	// Example: scrub repaired 0B in 00:10:42 with 0 errors on Sun Mar 10 00:34:43 2024
+	Scrub string `json:"scrub" yaml:"scrub"`
+
+	// When the health was checked
+	// Example: 2024-03-10T01:00:00Z
+	CheckedAt time.Time `json:"checked_at" yaml:"checked_at"`
+}
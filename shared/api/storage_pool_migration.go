@@ -0,0 +1,55 @@
+package api
+
+// StoragePoolMigration represents the migration features a server supports for a storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_migration.
+type StoragePoolMigration struct {
+	// Storage pool driver
+	// Example: zfs
+	Driver string `json:"driver" yaml:"driver"`
+
+	// Whether the storage pool is shared between cluster members
+	// Example: false
+	Remote bool `json:"remote" yaml:"remote"`
+
+	// Supported transfer types for each content type (filesystem or block)
+	ContentTypes map[string]StoragePoolMigrationContentType `json:"content_types" yaml:"content_types"`
+
+	// Instance types which can be live migrated
+	// Example: ["virtual-machine"]
+	Live []string `json:"live" yaml:"live"`
+
+	// Supported migration protocol features
+	// Example: ["compression", "encryption", "verify"]
+	Features []string `json:"features" yaml:"features"`
+}
+
+// StoragePoolMigrationContentType represents the transfer types supported for a content type
+//
+// swagger:model
+//
+// API extension: storage_pool_migration.
+type StoragePoolMigrationContentType struct {
+	// Transfer types for copies and moves, by order of preference
+	Types []StoragePoolMigrationType `json:"types" yaml:"types"`
+
+	// Transfer types for refreshes, by order of preference
+	RefreshTypes []StoragePoolMigrationType `json:"refresh_types" yaml:"refresh_types"`
+}
+
+// StoragePoolMigrationType represents a transfer type and its features
+//
+// swagger:model
+//
+// API extension: storage_pool_migration.
+type StoragePoolMigrationType struct {
+	// Name of the transfer type
+	// Example: zfs
+	Type string `json:"type" yaml:"type"`
+
+	// Features of the transfer type
+	// Example: ["migration_header", "compress"]
+	Features []string `json:"features" yaml:"features"`
+}
@@ -0,0 +1,24 @@
+package api
+
+// StoragePoolStatistics represents the data reduction statistics of a storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_statistics.
+type StoragePoolStatistics struct {
+	// Ratio between the data stored and its size after deduplication (1.0 without deduplication)
+	// Example: 1.35
+	DedupRatio float64 `json:"dedup_ratio" yaml:"dedup_ratio"`
+
+	// Ratio between the data stored and its size after compression (1.0 without compression)
+	// Example: 1.82
+	CompressionRatio float64 `json:"compression_ratio" yaml:"compression_ratio"`
+
+	// Size of the data stored, before compression and deduplication (bytes)
+	// Example: 625265278976
+	LogicalUsed uint64 `json:"logical_used" yaml:"logical_used"`
+
+	// Space used on the storage backend (bytes)
+	// Example: 343537419776
+	Used uint64 `json:"used" yaml:"used"`
+}
@@ -75,6 +75,11 @@ type StorageVolumePost struct {
 	//
 	// API extension: cluster_internal_custom_volume_copy
 	Source StorageVolumeSource `json:"source" yaml:"source"`
+
+	// Timeouts of the migration (migration only)
+	//
+	// API extension: migration_timeouts
+	Timeouts *MigrationTimeouts `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
 }
 
 // StorageVolumePostTarget represents the migration target host and operation
@@ -183,11 +188,11 @@ type StorageVolumePut struct {
 //
 // API extension: storage_api_local_volume_handling.
 type StorageVolumeSource struct {
-	// Source volume name (for copy)
+	// Source volume name (for copy) or name of the dataset, subvolume or logical volume to adopt (for existing)
 	// Example: foo
 	Name string `json:"name" yaml:"name"`
 
-	// Source type (copy or migration)
+	// Source type (copy, migration, existing or url)
 	// Example: copy
 	Type string `json:"type" yaml:"type"`
 
@@ -248,6 +253,23 @@ type StorageVolumeSource struct {
 	//
 	// API extension: cluster_internal_custom_volume_copy
 	Location string `json:"location" yaml:"location"`
+
+	// Timeouts of the migration (for migration)
+	//
+	// API extension: migration_timeouts
+	Timeouts *MigrationTimeouts `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+
+	// URL to download the volume content from (for url)
+	// Example: https://example.com/install.iso
+	//
+	// API extension: storage_volume_import_url
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Expected checksum of the downloaded content, as ALGORITHM:HASH with sha256 or sha512 (for url)
+	// Example: sha256:2c8e0a8e3b7e6dbd7c1a0b6bd1bb9f2a6b3e2f1b1e4f1c4f1a0e2c3f5d8a9b0c
+	//
+	// API extension: storage_volume_import_url
+	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
 }
 
 // Writable converts a full StorageVolume struct into a StorageVolumePut struct (filters read-only fields).
@@ -0,0 +1,24 @@
+package api
+
+// StorageVolumeDiffEntry represents a change between two states of a storage volume.
+//
+// swagger:model
+//
+// API extension: storage_volume_snapshot_diff.
+type StorageVolumeDiffEntry struct {
+	// Type of change (added, modified or deleted)
+	// Example: modified
+	Type string `json:"type" yaml:"type"`
+
+	// Path of the changed file (for filesystem volumes)
+	// Example: /etc/hostname
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Offset in bytes of the changed range (for block volumes)
+	// Example: 1048576
+	Offset int64 `json:"offset,omitempty" yaml:"offset,omitempty"`
+
+	// Length in bytes of the changed range (for block volumes)
+	// Example: 1048576
+	Length int64 `json:"length,omitempty" yaml:"length,omitempty"`
+}
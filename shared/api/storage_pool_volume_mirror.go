@@ -0,0 +1,65 @@
+package api
+
+// StorageVolumeMirror represents the mirroring state of a storage volume.
+//
+// swagger:model
+//
+// API extension: storage_volume_mirror.
+type StorageVolumeMirror struct {
+	// Mirroring mode
+	// Example: snapshot
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Whether the local copy of the volume is the primary one
+	// Example: true
+	Primary bool `json:"primary" yaml:"primary"`
+
+	// State of the local copy of the volume
+	// Example: up+stopped
+	State string `json:"state" yaml:"state"`
+
+	// Description of the state
+	// Example: local image is primary
+	Description string `json:"description" yaml:"description"`
+
+	// State of the copies of the volume on the peer clusters
+	Peers []StorageVolumeMirrorPeer `json:"peers" yaml:"peers"`
+}
+
+// StorageVolumeMirrorPeer represents the mirroring state of a storage volume on a peer cluster.
+//
+// swagger:model
+//
+// API extension: storage_volume_mirror.
+type StorageVolumeMirrorPeer struct {
+	// Name of the peer cluster
+	// Example: site-b
+	Name string `json:"name" yaml:"name"`
+
+	// State of the copy of the volume
+	// Example: up+replaying
+	State string `json:"state" yaml:"state"`
+
+	// Description of the state
+	// Example: replaying
+	Description string `json:"description" yaml:"description"`
+
+	// Time of the last update of the state
+	// Example: 2024-03-04 10:11:12
+	LastUpdate string `json:"last_update" yaml:"last_update"`
+}
+
+// StorageVolumeMirrorPost represents a change of the mirroring role of a storage volume.
+//
+// swagger:model
+//
+// API extension: storage_volume_mirror.
+type StorageVolumeMirrorPost struct {
+	// Action to perform (promote or demote)
+	// Example: promote
+	Action string `json:"action" yaml:"action"`
+
+	// Whether to promote the volume even if the peer can't be reached
+	// Example: false
+	Force bool `json:"force" yaml:"force"`
+}
@@ -8,6 +8,11 @@ package api
 type StorageVolumeState struct {
 	// Volume usage
 	Usage *StorageVolumeStateUsage `json:"usage" yaml:"usage"`
+
+	// Volume I/O limits
+	//
+	// API extension: storage_volume_limits
+	Limits *StorageVolumeStateLimits `json:"limits,omitempty" yaml:"limits,omitempty"`
 }
 
 // StorageVolumeStateUsage represents the disk usage of a volume
@@ -26,3 +31,26 @@ type StorageVolumeStateUsage struct {
 	// API extension: storage_volume_state_total
 	Total int64 `json:"total" yaml:"total"`
 }
+
+// StorageVolumeStateLimits represents the I/O limits of a volume
+//
+// swagger:model
+//
+// API extension: storage_volume_limits.
+type StorageVolumeStateLimits struct {
+	// Read limit in bytes per second
+	// Example: 10000000
+	ReadBytes int64 `json:"read_bytes,omitempty" yaml:"read_bytes,omitempty"`
+
+	// Read limit in operations per second
+	// Example: 1000
+	ReadIOps int64 `json:"read_iops,omitempty" yaml:"read_iops,omitempty"`
+
+	// Write limit in bytes per second
+	// Example: 10000000
+	WriteBytes int64 `json:"write_bytes,omitempty" yaml:"write_bytes,omitempty"`
+
+	// Write limit in operations per second
+	// Example: 1000
+	WriteIOps int64 `json:"write_iops,omitempty" yaml:"write_iops,omitempty"`
+}
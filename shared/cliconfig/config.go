@@ -21,6 +21,12 @@ type Config struct {
 	// communication with the named daemon
 	Remotes map[string]Remote `yaml:"remotes"`
 
+	// Profiles defines a map of settings which can be shared by multiple remotes
+	Profiles map[string]RemoteProfile `yaml:"profiles,omitempty"`
+
+	// RemoteGroups defines named groups of remotes which commands can be run against at once
+	RemoteGroups map[string][]string `yaml:"remote_groups,omitempty"`
+
 	// Command line aliases for `incus`
 	Aliases map[string]string `yaml:"aliases"`
 
@@ -33,15 +39,26 @@ type Config struct {
 	// The UserAgent to pass for all queries
 	UserAgent string `yaml:"-"`
 
-	// PromptPassword is a helper function used when encountering an encrypted key
+	// PromptPassword is a helper function used when encountering an encrypted key or a remote requiring a password
 	PromptPassword func(filename string) (string, error) `yaml:"-"`
 
+	// LocalMachine is a helper function used on non-Linux systems to get the unix socket of the "local" remote,
+	// which is served by a virtual machine
+	LocalMachine func() (string, error) `yaml:"-"`
+
 	// ProjectOverride allows overriding the default project
 	ProjectOverride string `yaml:"-"`
 
 	// OIDC tokens
 	oidcTokens map[string]*oidc.Tokens[*oidc.IDTokenClaims]
 
+	// Passwords used for external authentication
+	externalAuthPasswords map[string]string
+
+	// Remotes as defined in the configuration file and after applying their profile
+	rawRemotes      map[string]Remote
+	resolvedRemotes map[string]Remote
+
 	// Defaults holds default settings for a client or daemon
 	Defaults DefaultSettings `yaml:"defaults"`
 }
@@ -6,3 +6,19 @@ import (
 
 // ErrNotLinux is returned when attempting to access the "local" remote on non-Linux systems.
 var ErrNotLinux = errors.New("Can't connect to a local server on a non-Linux system")
+
+// UnreachableError is returned when failing to connect to a remote which has the offline queue enabled.
+type UnreachableError struct {
+	Remote string
+	Err    error
+}
+
+// Error returns the underlying connection error.
+func (e *UnreachableError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying connection error.
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
@@ -69,6 +69,12 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("Unable to decode the configuration: %w", err)
 	}
 
+	// Apply the remote profiles and environment variables.
+	err = c.applyProfiles()
+	if err != nil {
+		return nil, err
+	}
+
 	for k, r := range c.Remotes {
 		if !r.Public && r.AuthType == "" {
 			r.AuthType = api.AuthenticationMethodTLS
@@ -85,9 +91,16 @@ func LoadConfig(path string) (*Config, error) {
 			return nil, fmt.Errorf("Unable to decode the configuration: %w", err)
 		}
 
+		err = globalConf.applyProfiles()
+		if err != nil {
+			return nil, err
+		}
+
 		for k, r := range globalConf.Remotes {
 			_, ok := c.Remotes[k]
 			if !ok {
+				// Global profiles aren't available in the user configuration.
+				r.Profile = ""
 				r.Global = true
 				c.Remotes[k] = r
 			}
@@ -141,7 +154,11 @@ func (c *Config) SaveConfig(path string) error {
 	for k, v := range c.Remotes {
 		if v.Global {
 			delete(conf.Remotes, k)
+			continue
 		}
+
+		// Keep inherited and substituted values as they were defined.
+		conf.Remotes[k] = c.unresolveRemote(k, v)
 	}
 
 	defaultRemote := DefaultConfig().DefaultRemote
@@ -0,0 +1,44 @@
+package cliconfig
+
+import (
+	"fmt"
+	"slices"
+)
+
+// GetRemoteGroup returns the remotes which are part of the named group.
+func (c *Config) GetRemoteGroup(name string) ([]string, error) {
+	members, ok := c.RemoteGroups[name]
+	if !ok {
+		return nil, fmt.Errorf("Remote group %q doesn't exist", name)
+	}
+
+	if len(members) == 0 {
+		return nil, fmt.Errorf("Remote group %q is empty", name)
+	}
+
+	for _, member := range members {
+		_, ok := c.Remotes[member]
+		if !ok {
+			return nil, fmt.Errorf("Remote %q of group %q doesn't exist", member, name)
+		}
+	}
+
+	return members, nil
+}
+
+// RenameGroupRemote updates the remote groups following the renaming of a remote.
+func (c *Config) RenameGroupRemote(oldName string, newName string) {
+	for _, members := range c.RemoteGroups {
+		index := slices.Index(members, oldName)
+		if index >= 0 {
+			members[index] = newName
+		}
+	}
+}
+
+// RemoveGroupRemote removes a remote from all the remote groups.
+func (c *Config) RemoveGroupRemote(name string) {
+	for group, members := range c.RemoteGroups {
+		c.RemoteGroups[group] = slices.DeleteFunc(members, func(member string) bool { return member == name })
+	}
+}
@@ -0,0 +1,188 @@
+package cliconfig
+
+import (
+	"fmt"
+	"os"
+	"slices"
+)
+
+// RemoteProfile holds default settings which can be shared by multiple remotes.
+type RemoteProfile struct {
+	Profile      string `yaml:"profile,omitempty"`
+	AuthType     string `yaml:"auth_type,omitempty"`
+	KeepAlive    int    `yaml:"keepalive,omitempty"`
+	OfflineQueue bool   `yaml:"offline_queue,omitempty"`
+	Project      string `yaml:"project,omitempty"`
+	Protocol     string `yaml:"protocol,omitempty"`
+	Public       bool   `yaml:"public,omitempty"`
+	Username     string `yaml:"username,omitempty"`
+}
+
+// resolveProfile returns the settings of the named profile, including those inherited from its parents.
+func (c *Config) resolveProfile(name string, seen []string) (*RemoteProfile, error) {
+	if slices.Contains(seen, name) {
+		return nil, fmt.Errorf("Remote profile %q inherits from itself", name)
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("Remote profile %q doesn't exist", name)
+	}
+
+	profile = expandProfile(profile)
+	if profile.Profile == "" {
+		return &profile, nil
+	}
+
+	parent, err := c.resolveProfile(profile.Profile, append(seen, name))
+	if err != nil {
+		return nil, err
+	}
+
+	// Settings of the profile override those of its parent.
+	if profile.AuthType == "" {
+		profile.AuthType = parent.AuthType
+	}
+
+	if profile.KeepAlive == 0 {
+		profile.KeepAlive = parent.KeepAlive
+	}
+
+	if !profile.OfflineQueue {
+		profile.OfflineQueue = parent.OfflineQueue
+	}
+
+	if profile.Project == "" {
+		profile.Project = parent.Project
+	}
+
+	if profile.Protocol == "" {
+		profile.Protocol = parent.Protocol
+	}
+
+	if !profile.Public {
+		profile.Public = parent.Public
+	}
+
+	if profile.Username == "" {
+		profile.Username = parent.Username
+	}
+
+	return &profile, nil
+}
+
+// resolveRemote returns the remote with environment variables substituted and its profile settings applied.
+func (c *Config) resolveRemote(remote Remote) (Remote, error) {
+	remote.Addr = os.ExpandEnv(remote.Addr)
+	remote.AuthType = os.ExpandEnv(remote.AuthType)
+	remote.Project = os.ExpandEnv(remote.Project)
+	remote.Protocol = os.ExpandEnv(remote.Protocol)
+	remote.Username = os.ExpandEnv(remote.Username)
+
+	if remote.Profile == "" {
+		return remote, nil
+	}
+
+	profile, err := c.resolveProfile(remote.Profile, nil)
+	if err != nil {
+		return Remote{}, err
+	}
+
+	// Settings of the remote override those of its profile.
+	if remote.AuthType == "" {
+		remote.AuthType = profile.AuthType
+	}
+
+	if remote.KeepAlive == 0 {
+		remote.KeepAlive = profile.KeepAlive
+	}
+
+	if !remote.OfflineQueue {
+		remote.OfflineQueue = profile.OfflineQueue
+	}
+
+	if remote.Project == "" {
+		remote.Project = profile.Project
+	}
+
+	if remote.Protocol == "" {
+		remote.Protocol = profile.Protocol
+	}
+
+	if !remote.Public {
+		remote.Public = profile.Public
+	}
+
+	if remote.Username == "" {
+		remote.Username = profile.Username
+	}
+
+	return remote, nil
+}
+
+// applyProfiles resolves all remotes, keeping track of their original definition so it can be saved back.
+func (c *Config) applyProfiles() error {
+	c.rawRemotes = make(map[string]Remote, len(c.Remotes))
+	c.resolvedRemotes = make(map[string]Remote, len(c.Remotes))
+
+	for name, raw := range c.Remotes {
+		resolved, err := c.resolveRemote(raw)
+		if err != nil {
+			return fmt.Errorf("Invalid remote %q: %w", name, err)
+		}
+
+		c.rawRemotes[name] = raw
+		c.resolvedRemotes[name] = resolved
+		c.Remotes[name] = resolved
+	}
+
+	return nil
+}
+
+// unresolveRemote returns the remote as it should be saved.
+// Settings which are still identical to the value that was inherited or substituted are reverted to their
+// original definition, so that the remote keeps following its profile and environment.
+func (c *Config) unresolveRemote(name string, remote Remote) Remote {
+	raw, ok := c.rawRemotes[name]
+	if !ok {
+		return remote
+	}
+
+	resolved := c.resolvedRemotes[name]
+
+	restoreString := func(current *string, raw string, resolved string) {
+		if *current == resolved && resolved != raw {
+			*current = raw
+		}
+	}
+
+	restoreString(&remote.Addr, raw.Addr, resolved.Addr)
+	restoreString(&remote.AuthType, raw.AuthType, resolved.AuthType)
+	restoreString(&remote.Project, raw.Project, resolved.Project)
+	restoreString(&remote.Protocol, raw.Protocol, resolved.Protocol)
+	restoreString(&remote.Username, raw.Username, resolved.Username)
+
+	if remote.KeepAlive == resolved.KeepAlive && resolved.KeepAlive != raw.KeepAlive {
+		remote.KeepAlive = raw.KeepAlive
+	}
+
+	if remote.OfflineQueue == resolved.OfflineQueue && resolved.OfflineQueue != raw.OfflineQueue {
+		remote.OfflineQueue = raw.OfflineQueue
+	}
+
+	if remote.Public == resolved.Public && resolved.Public != raw.Public {
+		remote.Public = raw.Public
+	}
+
+	return remote
+}
+
+// expandProfile substitutes environment variables in the profile settings.
+func expandProfile(profile RemoteProfile) RemoteProfile {
+	profile.AuthType = os.ExpandEnv(profile.AuthType)
+	profile.Project = os.ExpandEnv(profile.Project)
+	profile.Protocol = os.ExpandEnv(profile.Protocol)
+	profile.Username = os.ExpandEnv(profile.Username)
+
+	return profile
+}
@@ -0,0 +1,89 @@
+package cliconfig
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// QueuedCommand represents a command which was queued while its remote was unreachable.
+type QueuedCommand struct {
+	Remote    string    `yaml:"remote"`
+	Args      []string  `yaml:"args"`
+	Directory string    `yaml:"directory"`
+	Date      time.Time `yaml:"date"`
+}
+
+// isUnreachable checks whether a connection error indicates that the remote can't currently be reached.
+func isUnreachable(err error) bool {
+	if localtls.IsConnectionError(err) {
+		return true
+	}
+
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// LoadQueue returns the commands queued for unreachable remotes.
+func (c *Config) LoadQueue() ([]QueuedCommand, error) {
+	content, err := os.ReadFile(c.ConfigPath("queue.yml"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []QueuedCommand{}, nil
+		}
+
+		return nil, fmt.Errorf("Unable to read the command queue: %w", err)
+	}
+
+	queue := []QueuedCommand{}
+	err = yaml.Unmarshal(content, &queue)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to decode the command queue: %w", err)
+	}
+
+	return queue, nil
+}
+
+// SaveQueue writes the commands queued for unreachable remotes.
+func (c *Config) SaveQueue(queue []QueuedCommand) error {
+	path := c.ConfigPath("queue.yml")
+
+	if len(queue) == 0 {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("Unable to remove the command queue: %w", err)
+		}
+
+		return nil
+	}
+
+	data, err := yaml.Marshal(queue)
+	if err != nil {
+		return fmt.Errorf("Unable to marshal the command queue: %w", err)
+	}
+
+	err = os.MkdirAll(c.ConfigDir, 0o750)
+	if err != nil {
+		return fmt.Errorf("Unable to create the configuration directory: %w", err)
+	}
+
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("Unable to write the command queue: %w", err)
+	}
+
+	return nil
+}
@@ -25,14 +25,27 @@ import (
 
 // Remote holds details for communication with a remote daemon.
 type Remote struct {
-	Addr      string `yaml:"addr"`
-	AuthType  string `yaml:"auth_type,omitempty"`
-	KeepAlive int    `yaml:"keepalive,omitempty"`
-	Project   string `yaml:"project,omitempty"`
-	Protocol  string `yaml:"protocol,omitempty"`
-	Public    bool   `yaml:"public"`
-	Global    bool   `yaml:"-"`
-	Static    bool   `yaml:"-"`
+	Addr         string `yaml:"addr"`
+	AuthType     string `yaml:"auth_type,omitempty"`
+	KeepAlive    int    `yaml:"keepalive,omitempty"`
+	OfflineQueue bool   `yaml:"offline_queue,omitempty"`
+	Profile      string `yaml:"profile,omitempty"`
+	Project      string `yaml:"project,omitempty"`
+	Protocol     string `yaml:"protocol,omitempty"`
+	Public       bool   `yaml:"public"`
+	Global       bool   `yaml:"-"`
+	Static       bool   `yaml:"-"`
+	Username     string `yaml:"username,omitempty"`
+}
+
+// connectionError returns the error to report when failing to connect to a remote.
+// Remotes with the offline queue enabled report connectivity issues as an UnreachableError.
+func (c *Config) connectionError(name string, remote Remote, err error) error {
+	if remote.OfflineQueue && isUnreachable(err) {
+		return &UnreachableError{Remote: name, Err: err}
+	}
+
+	return err
 }
 
 // ParseRemote splits remote and object.
@@ -57,17 +70,22 @@ func (c *Config) ParseRemote(raw string) (string, string, error) {
 
 // GetInstanceServer returns a InstanceServer struct for the remote.
 func (c *Config) GetInstanceServer(name string) (incus.InstanceServer, error) {
-	// Handle "local" on non-Linux
-	if name == "local" && runtime.GOOS != "linux" {
-		return nil, ErrNotLinux
-	}
-
 	// Get the remote
 	remote, ok := c.Remotes[name]
 	if !ok {
 		return nil, fmt.Errorf("The remote \"%s\" doesn't exist", name)
 	}
 
+	// Handle "local" on non-Linux
+	if name == "local" && runtime.GOOS != "linux" {
+		addr, err := c.localMachineAddr()
+		if err != nil {
+			return nil, err
+		}
+
+		remote.Addr = addr
+	}
+
 	// Quick checks.
 	if remote.Public || remote.Protocol != "incus" {
 		return nil, errors.New("The remote isn't a private server")
@@ -114,7 +132,7 @@ func (c *Config) GetInstanceServer(name string) (incus.InstanceServer, error) {
 	}
 
 	// HTTPs
-	if !slices.Contains([]string{api.AuthenticationMethodOIDC}, remote.AuthType) && (args.TLSClientCert == "" || args.TLSClientKey == "") {
+	if !slices.Contains([]string{api.AuthenticationMethodOIDC, api.AuthenticationMethodExternal}, remote.AuthType) && (args.TLSClientCert == "" || args.TLSClientKey == "") {
 		return nil, errors.New("Missing TLS client certificate and key")
 	}
 
@@ -125,13 +143,13 @@ func (c *Config) GetInstanceServer(name string) (incus.InstanceServer, error) {
 			// On proxy failure, just fallback to regular client.
 			d, err = incus.ConnectIncus(remote.Addr, args)
 			if err != nil {
-				return nil, err
+				return nil, c.connectionError(name, remote, err)
 			}
 		}
 	} else {
 		d, err = incus.ConnectIncus(remote.Addr, args)
 		if err != nil {
-			return nil, err
+			return nil, c.connectionError(name, remote, err)
 		}
 	}
 
@@ -146,19 +164,39 @@ func (c *Config) GetInstanceServer(name string) (incus.InstanceServer, error) {
 	return d, nil
 }
 
-// GetImageServer returns a ImageServer struct for the remote.
-func (c *Config) GetImageServer(name string) (incus.ImageServer, error) {
-	// Handle "local" on non-Linux
-	if name == "local" && runtime.GOOS != "linux" {
-		return nil, ErrNotLinux
+// localMachineAddr returns the address of the "local" remote on non-Linux systems, where it's served by a
+// virtual machine.
+func (c *Config) localMachineAddr() (string, error) {
+	if c.LocalMachine == nil {
+		return "", ErrNotLinux
+	}
+
+	socketPath, err := c.LocalMachine()
+	if err != nil {
+		return "", err
 	}
 
+	return "unix://" + socketPath, nil
+}
+
+// GetImageServer returns a ImageServer struct for the remote.
+func (c *Config) GetImageServer(name string) (incus.ImageServer, error) {
 	// Get the remote
 	remote, ok := c.Remotes[name]
 	if !ok {
 		return nil, fmt.Errorf("The remote \"%s\" doesn't exist", name)
 	}
 
+	// Handle "local" on non-Linux
+	if name == "local" && runtime.GOOS != "linux" {
+		addr, err := c.localMachineAddr()
+		if err != nil {
+			return nil, err
+		}
+
+		remote.Addr = addr
+	}
+
 	// Get connection arguments
 	args, err := c.getConnectionArgs(name)
 	if err != nil {
@@ -278,6 +316,41 @@ func (c *Config) getConnectionArgs(name string) (*incus.ConnectionArgs, error) {
 		args.OIDCTokens = c.oidcTokens[name]
 	}
 
+	if args.AuthType == api.AuthenticationMethodExternal {
+		args.ExternalAuthUsername = remote.Username
+		if os.Getenv("INCUS_USERNAME") != "" {
+			args.ExternalAuthUsername = os.Getenv("INCUS_USERNAME")
+		}
+
+		if args.ExternalAuthUsername == "" {
+			return nil, errors.New("Missing username for external authentication")
+		}
+
+		if c.externalAuthPasswords == nil {
+			c.externalAuthPasswords = map[string]string{}
+		}
+
+		password, ok := c.externalAuthPasswords[name]
+		if !ok {
+			password = os.Getenv("INCUS_PASSWORD")
+			if password == "" {
+				if c.PromptPassword == nil {
+					return nil, errors.New("Remote requires a password and no helper was configured")
+				}
+
+				var err error
+				password, err = c.PromptPassword(fmt.Sprintf("%s@%s", args.ExternalAuthUsername, name))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			c.externalAuthPasswords[name] = password
+		}
+
+		args.ExternalAuthPassword = password
+	}
+
 	// Stop here if no TLS involved
 	if strings.HasPrefix(remote.Addr, "unix:") {
 		return &args, nil
@@ -294,7 +367,7 @@ func (c *Config) getConnectionArgs(name string) (*incus.ConnectionArgs, error) {
 	}
 
 	// Stop here if no client certificate involved
-	if remote.Protocol != "incus" || slices.Contains([]string{api.AuthenticationMethodOIDC}, remote.AuthType) {
+	if remote.Protocol != "incus" || slices.Contains([]string{api.AuthenticationMethodOIDC, api.AuthenticationMethodExternal}, remote.AuthType) {
 		return &args, nil
 	}
 
@@ -0,0 +1,145 @@
+package storageplugin
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client is a client for a storage plugin.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient returns a new client for the storage plugin listening on the provided unix socket.
+func NewClient(socketPath string) (*Client, error) {
+	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})))
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to storage plugin %q: %w", socketPath, err)
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the connection to the plugin.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// invoke calls a method of the plugin service.
+func invoke[Resp any](ctx context.Context, c *Client, method string, req any) (*Resp, error) {
+	resp := new(Resp)
+	err := c.conn.Invoke(ctx, "/"+ServiceName+"/"+method, req, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetInfo returns information about the plugin and its capabilities.
+func (c *Client) GetInfo(ctx context.Context, req *InfoRequest) (*InfoResponse, error) {
+	return invoke[InfoResponse](ctx, c, "GetInfo", req)
+}
+
+// ValidatePool validates the storage pool configuration.
+func (c *Client) ValidatePool(ctx context.Context, req *PoolRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "ValidatePool", req)
+}
+
+// CreatePool prepares the storage backend for a new storage pool.
+func (c *Client) CreatePool(ctx context.Context, req *PoolRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "CreatePool", req)
+}
+
+// DeletePool removes the storage pool from the storage backend.
+func (c *Client) DeletePool(ctx context.Context, req *PoolRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "DeletePool", req)
+}
+
+// MountPool makes the storage pool available on the host.
+func (c *Client) MountPool(ctx context.Context, req *PoolRequest) (*PoolMountResponse, error) {
+	return invoke[PoolMountResponse](ctx, c, "MountPool", req)
+}
+
+// UnmountPool releases the storage pool on the host.
+func (c *Client) UnmountPool(ctx context.Context, req *PoolRequest) (*PoolMountResponse, error) {
+	return invoke[PoolMountResponse](ctx, c, "UnmountPool", req)
+}
+
+// GetPoolResources returns the storage pool usage.
+func (c *Client) GetPoolResources(ctx context.Context, req *PoolRequest) (*PoolResourcesResponse, error) {
+	return invoke[PoolResourcesResponse](ctx, c, "GetPoolResources", req)
+}
+
+// CreateVolume creates a new empty block volume or clones it from a source volume.
+func (c *Client) CreateVolume(ctx context.Context, req *CreateVolumeRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "CreateVolume", req)
+}
+
+// DeleteVolume deletes a volume.
+func (c *Client) DeleteVolume(ctx context.Context, req *VolumeRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "DeleteVolume", req)
+}
+
+// RenameVolume renames a volume along with its snapshots.
+func (c *Client) RenameVolume(ctx context.Context, req *RenameVolumeRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "RenameVolume", req)
+}
+
+// HasVolume indicates whether the volume exists on the storage backend.
+func (c *Client) HasVolume(ctx context.Context, req *VolumeRequest) (*HasVolumeResponse, error) {
+	return invoke[HasVolumeResponse](ctx, c, "HasVolume", req)
+}
+
+// ResizeVolume changes the size of a volume.
+func (c *Client) ResizeVolume(ctx context.Context, req *ResizeVolumeRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "ResizeVolume", req)
+}
+
+// GetVolumeSize returns size information of a volume.
+func (c *Client) GetVolumeSize(ctx context.Context, req *VolumeRequest) (*VolumeSizeResponse, error) {
+	return invoke[VolumeSizeResponse](ctx, c, "GetVolumeSize", req)
+}
+
+// ListVolumes lists the volumes present on the storage backend.
+func (c *Client) ListVolumes(ctx context.Context, req *PoolRequest) (*ListVolumesResponse, error) {
+	return invoke[ListVolumesResponse](ctx, c, "ListVolumes", req)
+}
+
+// ActivateVolume maps the volume on the host and returns its device path.
+func (c *Client) ActivateVolume(ctx context.Context, req *VolumeRequest) (*ActivateVolumeResponse, error) {
+	return invoke[ActivateVolumeResponse](ctx, c, "ActivateVolume", req)
+}
+
+// DeactivateVolume unmaps the volume from the host.
+func (c *Client) DeactivateVolume(ctx context.Context, req *VolumeRequest) (*DeactivateVolumeResponse, error) {
+	return invoke[DeactivateVolumeResponse](ctx, c, "DeactivateVolume", req)
+}
+
+// CreateVolumeSnapshot creates a snapshot of a volume.
+func (c *Client) CreateVolumeSnapshot(ctx context.Context, req *VolumeRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "CreateVolumeSnapshot", req)
+}
+
+// DeleteVolumeSnapshot deletes a snapshot of a volume.
+func (c *Client) DeleteVolumeSnapshot(ctx context.Context, req *VolumeRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "DeleteVolumeSnapshot", req)
+}
+
+// RenameVolumeSnapshot renames a snapshot of a volume.
+func (c *Client) RenameVolumeSnapshot(ctx context.Context, req *RenameVolumeRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "RenameVolumeSnapshot", req)
+}
+
+// ListVolumeSnapshots lists the snapshots of a volume.
+func (c *Client) ListVolumeSnapshots(ctx context.Context, req *VolumeRequest) (*ListSnapshotsResponse, error) {
+	return invoke[ListSnapshotsResponse](ctx, c, "ListVolumeSnapshots", req)
+}
+
+// RestoreVolume restores a volume to one of its snapshots.
+func (c *Client) RestoreVolume(ctx context.Context, req *RestoreVolumeRequest) (*Empty, error) {
+	return invoke[Empty](ctx, c, "RestoreVolume", req)
+}
@@ -0,0 +1,53 @@
+package storageplugin
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer implements the methods used by the tests, calling any other method panics.
+type testServer struct {
+	DriverServer
+}
+
+func (s *testServer) GetInfo(ctx context.Context, req *InfoRequest) (*InfoResponse, error) {
+	return &InfoResponse{ProtocolVersion: req.ProtocolVersion, Name: "test", VolumeTypes: []string{"custom"}}, nil
+}
+
+func (s *testServer) ActivateVolume(ctx context.Context, req *VolumeRequest) (*ActivateVolumeResponse, error) {
+	return &ActivateVolumeResponse{DevicePath: "/dev/" + req.Pool + "/" + req.Volume.Name, Activated: true}, nil
+}
+
+func TestClient(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "plugin.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := NewServer()
+	RegisterDriverServer(server, &testServer{})
+
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	client, err := NewClient(socketPath)
+	require.NoError(t, err)
+
+	defer func() { _ = client.Close() }()
+
+	info, err := client.GetInfo(context.Background(), &InfoRequest{ProtocolVersion: ProtocolVersion})
+	require.NoError(t, err)
+	assert.Equal(t, ProtocolVersion, info.ProtocolVersion)
+	assert.Equal(t, "test", info.Name)
+	assert.Equal(t, []string{"custom"}, info.VolumeTypes)
+
+	resp, err := client.ActivateVolume(context.Background(), &VolumeRequest{Pool: "pool1", Volume: Volume{Name: "vol1", Type: "custom", ContentType: "block"}})
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/pool1/vol1", resp.DevicePath)
+	assert.True(t, resp.Activated)
+}
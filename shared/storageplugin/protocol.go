@@ -0,0 +1,214 @@
+package storageplugin
+
+import (
+	"encoding/json"
+)
+
+// ServiceName is the fully qualified gRPC service name implemented by storage plugins.
+const ServiceName = "incus.storage.v1.Driver"
+
+// ProtocolVersion is the version of the storage plugin protocol.
+// It is bumped whenever a change is made that isn't backward compatible.
+const ProtocolVersion = 1
+
+// CodecName is the gRPC content sub-type used by the storage plugin protocol.
+// Messages are encoded as JSON so that plugins can be implemented without code generation.
+const CodecName = "json"
+
+// jsonCodec is a gRPC codec encoding messages as JSON.
+// It's forced on the connections of the plugin clients and servers rather than registered globally,
+// so that it doesn't replace the codec used by any other gRPC user for the same content sub-type.
+type jsonCodec struct{}
+
+// Marshal encodes the message.
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the message.
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// Name returns the name of the codec.
+func (jsonCodec) Name() string {
+	return CodecName
+}
+
+// Volume represents a storage volume as seen by a plugin.
+type Volume struct {
+	// Name of the volume (including the project prefix and, for snapshots, the parent name).
+	Name string `json:"name"`
+
+	// Type of the volume (container, virtual-machine, image or custom).
+	Type string `json:"type"`
+
+	// Content type of the volume (filesystem, block or iso).
+	ContentType string `json:"content_type"`
+
+	// Volume configuration.
+	Config map[string]string `json:"config,omitempty"`
+
+	// Whether the volume is a snapshot.
+	Snapshot bool `json:"snapshot,omitempty"`
+}
+
+// Empty is used for requests and responses that carry no data.
+type Empty struct{}
+
+// InfoRequest is used to retrieve the plugin information.
+type InfoRequest struct {
+	// Protocol version spoken by the daemon.
+	ProtocolVersion int `json:"protocol_version"`
+}
+
+// InfoResponse contains the plugin information.
+type InfoResponse struct {
+	// Protocol version spoken by the plugin.
+	ProtocolVersion int `json:"protocol_version"`
+
+	// Name of the storage backend (for display purposes).
+	Name string `json:"name"`
+
+	// Version of the plugin or storage backend.
+	Version string `json:"version"`
+
+	// Volume types supported by the plugin.
+	VolumeTypes []string `json:"volume_types"`
+
+	// Whether the plugin supports volume snapshots.
+	Snapshots bool `json:"snapshots"`
+
+	// Whether the plugin can create volumes from other volumes or snapshots without copying data.
+	Clone bool `json:"clone"`
+
+	// Whether the plugin supports shrinking volumes.
+	Shrink bool `json:"shrink"`
+}
+
+// PoolRequest is used for pool level operations.
+type PoolRequest struct {
+	// Name of the storage pool.
+	Pool string `json:"pool"`
+
+	// Storage pool configuration.
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// PoolMountResponse is returned when mounting or unmounting a pool.
+type PoolMountResponse struct {
+	// Whether the call caused a change.
+	Changed bool `json:"changed"`
+}
+
+// PoolResourcesResponse contains the pool usage information.
+type PoolResourcesResponse struct {
+	// Total space in bytes.
+	SpaceTotal uint64 `json:"space_total"`
+
+	// Used space in bytes.
+	SpaceUsed uint64 `json:"space_used"`
+}
+
+// VolumeRequest is used for volume level operations.
+type VolumeRequest struct {
+	// Name of the storage pool.
+	Pool string `json:"pool"`
+
+	// Volume the operation applies to.
+	Volume Volume `json:"volume"`
+}
+
+// CreateVolumeRequest is used to create a new volume.
+type CreateVolumeRequest struct {
+	// Name of the storage pool.
+	Pool string `json:"pool"`
+
+	// Volume to create.
+	Volume Volume `json:"volume"`
+
+	// Size of the volume in bytes.
+	SizeBytes int64 `json:"size_bytes"`
+
+	// Optional source volume or snapshot to clone from (only if the plugin supports cloning).
+	Source *Volume `json:"source,omitempty"`
+}
+
+// RenameVolumeRequest is used to rename a volume or snapshot.
+type RenameVolumeRequest struct {
+	// Name of the storage pool.
+	Pool string `json:"pool"`
+
+	// Volume to rename.
+	Volume Volume `json:"volume"`
+
+	// New name of the volume.
+	NewName string `json:"new_name"`
+}
+
+// ResizeVolumeRequest is used to resize a volume.
+type ResizeVolumeRequest struct {
+	// Name of the storage pool.
+	Pool string `json:"pool"`
+
+	// Volume to resize.
+	Volume Volume `json:"volume"`
+
+	// New size of the volume in bytes.
+	SizeBytes int64 `json:"size_bytes"`
+}
+
+// RestoreVolumeRequest is used to restore a volume to one of its snapshots.
+type RestoreVolumeRequest struct {
+	// Name of the storage pool.
+	Pool string `json:"pool"`
+
+	// Volume to restore.
+	Volume Volume `json:"volume"`
+
+	// Name of the snapshot to restore.
+	Snapshot string `json:"snapshot"`
+}
+
+// HasVolumeResponse indicates whether a volume exists.
+type HasVolumeResponse struct {
+	// Whether the volume exists.
+	Exists bool `json:"exists"`
+}
+
+// VolumeSizeResponse contains size information of a volume.
+type VolumeSizeResponse struct {
+	// Size of the volume in bytes.
+	SizeBytes int64 `json:"size_bytes"`
+
+	// Used space in bytes or -1 if unknown.
+	UsedBytes int64 `json:"used_bytes"`
+}
+
+// ActivateVolumeResponse contains the host path of an activated volume.
+type ActivateVolumeResponse struct {
+	// Path to the block device on the host.
+	// For snapshots, the device must be writable so that the filesystem UUID can be regenerated if needed.
+	DevicePath string `json:"device_path"`
+
+	// Whether this call caused the volume to be activated.
+	Activated bool `json:"activated"`
+}
+
+// DeactivateVolumeResponse is returned when deactivating a volume.
+type DeactivateVolumeResponse struct {
+	// Whether this call caused the volume to be deactivated.
+	Deactivated bool `json:"deactivated"`
+}
+
+// ListVolumesResponse contains a list of volumes.
+type ListVolumesResponse struct {
+	// Volumes present on the pool.
+	Volumes []Volume `json:"volumes"`
+}
+
+// ListSnapshotsResponse contains a list of snapshot names.
+type ListSnapshotsResponse struct {
+	// Names of the snapshots (without the parent volume name).
+	Snapshots []string `json:"snapshots"`
+}
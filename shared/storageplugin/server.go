@@ -0,0 +1,145 @@
+package storageplugin
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// DriverServer is the interface implemented by storage plugins.
+//
+// Plugins are responsible for provisioning and mapping block devices on the host.
+// Incus takes care of creating filesystems on top of those devices, mounting them and of any data transfer
+// (copies, migrations and backups) using its generic implementations.
+type DriverServer interface {
+	// GetInfo returns information about the plugin and its capabilities.
+	GetInfo(ctx context.Context, req *InfoRequest) (*InfoResponse, error)
+
+	// ValidatePool validates the storage pool configuration.
+	ValidatePool(ctx context.Context, req *PoolRequest) (*Empty, error)
+
+	// CreatePool prepares the storage backend for a new storage pool.
+	CreatePool(ctx context.Context, req *PoolRequest) (*Empty, error)
+
+	// DeletePool removes the storage pool from the storage backend.
+	DeletePool(ctx context.Context, req *PoolRequest) (*Empty, error)
+
+	// MountPool makes the storage pool available on the host.
+	MountPool(ctx context.Context, req *PoolRequest) (*PoolMountResponse, error)
+
+	// UnmountPool releases the storage pool on the host.
+	UnmountPool(ctx context.Context, req *PoolRequest) (*PoolMountResponse, error)
+
+	// GetPoolResources returns the storage pool usage.
+	GetPoolResources(ctx context.Context, req *PoolRequest) (*PoolResourcesResponse, error)
+
+	// CreateVolume creates a new empty block volume or clones it from a source volume.
+	CreateVolume(ctx context.Context, req *CreateVolumeRequest) (*Empty, error)
+
+	// DeleteVolume deletes a volume.
+	DeleteVolume(ctx context.Context, req *VolumeRequest) (*Empty, error)
+
+	// RenameVolume renames a volume along with its snapshots.
+	RenameVolume(ctx context.Context, req *RenameVolumeRequest) (*Empty, error)
+
+	// HasVolume indicates whether the volume exists on the storage backend.
+	HasVolume(ctx context.Context, req *VolumeRequest) (*HasVolumeResponse, error)
+
+	// ResizeVolume changes the size of a volume.
+	ResizeVolume(ctx context.Context, req *ResizeVolumeRequest) (*Empty, error)
+
+	// GetVolumeSize returns size information of a volume.
+	GetVolumeSize(ctx context.Context, req *VolumeRequest) (*VolumeSizeResponse, error)
+
+	// ListVolumes lists the volumes present on the storage backend.
+	ListVolumes(ctx context.Context, req *PoolRequest) (*ListVolumesResponse, error)
+
+	// ActivateVolume maps the volume on the host and returns its device path.
+	ActivateVolume(ctx context.Context, req *VolumeRequest) (*ActivateVolumeResponse, error)
+
+	// DeactivateVolume unmaps the volume from the host.
+	DeactivateVolume(ctx context.Context, req *VolumeRequest) (*DeactivateVolumeResponse, error)
+
+	// CreateVolumeSnapshot creates a snapshot of a volume.
+	CreateVolumeSnapshot(ctx context.Context, req *VolumeRequest) (*Empty, error)
+
+	// DeleteVolumeSnapshot deletes a snapshot of a volume.
+	DeleteVolumeSnapshot(ctx context.Context, req *VolumeRequest) (*Empty, error)
+
+	// RenameVolumeSnapshot renames a snapshot of a volume.
+	RenameVolumeSnapshot(ctx context.Context, req *RenameVolumeRequest) (*Empty, error)
+
+	// ListVolumeSnapshots lists the snapshots of a volume.
+	ListVolumeSnapshots(ctx context.Context, req *VolumeRequest) (*ListSnapshotsResponse, error)
+
+	// RestoreVolume restores a volume to one of its snapshots.
+	RestoreVolume(ctx context.Context, req *RestoreVolumeRequest) (*Empty, error)
+}
+
+// unaryMethod returns a gRPC method description calling the provided DriverServer function.
+func unaryMethod[Req any, Resp any](name string, call func(srv DriverServer, ctx context.Context, req *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := new(Req)
+			err := dec(req)
+			if err != nil {
+				return nil, err
+			}
+
+			if interceptor == nil {
+				return call(srv.(DriverServer), ctx, req)
+			}
+
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: "/" + ServiceName + "/" + name,
+			}
+
+			handler := func(ctx context.Context, req any) (any, error) {
+				return call(srv.(DriverServer), ctx, req.(*Req))
+			}
+
+			return interceptor(ctx, req, info, handler)
+		},
+	}
+}
+
+// serviceDesc describes the storage plugin gRPC service.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*DriverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("GetInfo", DriverServer.GetInfo),
+		unaryMethod("ValidatePool", DriverServer.ValidatePool),
+		unaryMethod("CreatePool", DriverServer.CreatePool),
+		unaryMethod("DeletePool", DriverServer.DeletePool),
+		unaryMethod("MountPool", DriverServer.MountPool),
+		unaryMethod("UnmountPool", DriverServer.UnmountPool),
+		unaryMethod("GetPoolResources", DriverServer.GetPoolResources),
+		unaryMethod("CreateVolume", DriverServer.CreateVolume),
+		unaryMethod("DeleteVolume", DriverServer.DeleteVolume),
+		unaryMethod("RenameVolume", DriverServer.RenameVolume),
+		unaryMethod("HasVolume", DriverServer.HasVolume),
+		unaryMethod("ResizeVolume", DriverServer.ResizeVolume),
+		unaryMethod("GetVolumeSize", DriverServer.GetVolumeSize),
+		unaryMethod("ListVolumes", DriverServer.ListVolumes),
+		unaryMethod("ActivateVolume", DriverServer.ActivateVolume),
+		unaryMethod("DeactivateVolume", DriverServer.DeactivateVolume),
+		unaryMethod("CreateVolumeSnapshot", DriverServer.CreateVolumeSnapshot),
+		unaryMethod("DeleteVolumeSnapshot", DriverServer.DeleteVolumeSnapshot),
+		unaryMethod("RenameVolumeSnapshot", DriverServer.RenameVolumeSnapshot),
+		unaryMethod("ListVolumeSnapshots", DriverServer.ListVolumeSnapshots),
+		unaryMethod("RestoreVolume", DriverServer.RestoreVolume),
+	},
+}
+
+// NewServer returns a gRPC server using the codec of the storage plugin protocol.
+func NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	return grpc.NewServer(append(opts, grpc.ForceServerCodec(jsonCodec{}))...)
+}
+
+// RegisterDriverServer registers a storage plugin implementation with a gRPC server.
+func RegisterDriverServer(s grpc.ServiceRegistrar, srv DriverServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"net/url"
 	"os/exec"
@@ -120,6 +121,20 @@ func IsInRange(minValue int64, maxValue int64) func(value string) error {
 	}
 }
 
+// IsPositiveFloat validates whether the string is a finite floating point number greater than zero.
+func IsPositiveFloat(value string) error {
+	valueFloat, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid value for a float %q", value)
+	}
+
+	if !(valueFloat > 0) || math.IsInf(valueFloat, 1) {
+		return fmt.Errorf("Invalid value %q. Must be greater than 0", value)
+	}
+
+	return nil
+}
+
 // IsPriority validates priority number.
 func IsPriority(value string) error {
 	valueInt, err := strconv.ParseInt(value, 10, 64)
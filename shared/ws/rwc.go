@@ -2,6 +2,7 @@ package ws
 
 import (
 	"io"
+	"net"
 	"sync"
 
 	"github.com/gorilla/websocket"
@@ -9,14 +10,21 @@ import (
 	"github.com/lxc/incus/v6/shared/logger"
 )
 
+// MessageConn represents a message based connection, such as a websocket.
+type MessageConn interface {
+	NextReader() (int, io.Reader, error)
+	WriteMessage(messageType int, data []byte) error
+	RemoteAddr() net.Addr
+}
+
 // NewWrapper returns a new ReadWriteCloser wrapper for a websocket connection.
-func NewWrapper(conn *websocket.Conn) io.ReadWriteCloser {
+func NewWrapper(conn MessageConn) io.ReadWriteCloser {
 	return &wrapper{conn: conn}
 }
 
 // wrapper implements ReadWriteCloser on top of a websocket connection.
 type wrapper struct {
-	conn   *websocket.Conn
+	conn   MessageConn
 	reader io.Reader
 	mur    sync.Mutex
 	muw    sync.Mutex